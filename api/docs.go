@@ -22,20 +22,71 @@ const docTemplate = `{
     "paths": {
         "/": {
             "get": {
-                "description": "Returns all stored metrics rendered as HTML table.",
+                "description": "Returns series of the tenant as an HTML dashboard grouped by metric type.\nSeries can be filtered by a key substring and type, sorted by ID, type, value or last update,\nand the page can reload itself periodically. Each series links to its detail page.\nUnits are taken from metric metadata.",
                 "produces": [
                     "text/html"
                 ],
                 "tags": [
                     "Metrics"
                 ],
-                "summary": "Get all metrics (HTML)",
+                "summary": "Metrics dashboard (HTML)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive substring of series keys",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "gauge",
+                            "counter",
+                            "histogram",
+                            "summary",
+                            "set"
+                        ],
+                        "type": "string",
+                        "description": "Metric type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "type",
+                            "value",
+                            "updated"
+                        ],
+                        "type": "string",
+                        "description": "Sort column (default: id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order (default: asc)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 3600,
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Auto-refresh interval in seconds, 0 disables it",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "HTML page with metrics"
+                        "description": "HTML dashboard"
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/api.APIError"
                         }
@@ -49,9 +100,142 @@ const docTemplate = `{
                 }
             }
         },
-        "/update": {
-            "post": {
-                "description": "Saves a metric using JSON body. Counters are incremented, gauges are overwritten.",
+        "/api/v1/alerts": {
+            "get": {
+                "description": "Returns pending and firing alerts of the tenant ordered by rule and series.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "Get alerts",
+                "responses": {
+                    "200": {
+                        "description": "Active alerts",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Alert"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/alerts/rules": {
+            "get": {
+                "description": "Returns all alert rules of the tenant ordered by ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "Get alert rules",
+                "responses": {
+                    "200": {
+                        "description": "Alert rules",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.AlertRule"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/alerts/rules/{id}": {
+            "get": {
+                "description": "Returns an alert rule of the tenant by its ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "Get alert rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Alert rule",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.AlertRule"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Stores an alert rule of the tenant, replacing the rule with the same ID.\nThe rule ID is taken from the path and the tenant from the request, values in the body are ignored.",
                 "consumes": [
                     "application/json"
                 ],
@@ -59,230 +243,264 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Metrics"
+                    "Alerts"
                 ],
-                "summary": "Save metric (JSON)",
+                "summary": "Put alert rule",
                 "parameters": [
                     {
-                        "description": "Metric payload",
-                        "name": "metric",
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Alert rule",
+                        "name": "rule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Metrics"
+                            "$ref": "#/definitions/models.AlertRule"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Saved metric",
+                        "description": "Stored rule",
                         "schema": {
-                            "$ref": "#/definitions/models.Metrics"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.AlertRule"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/api.APIError"
+                            "$ref": "#/definitions/api.ErrorEnvelope"
                         }
                     },
                     "422": {
                         "description": "Invalid JSON",
                         "schema": {
-                            "$ref": "#/definitions/api.APIError"
+                            "$ref": "#/definitions/api.ErrorEnvelope"
                         }
                     },
                     "500": {
                         "description": "Internal Error",
                         "schema": {
-                            "$ref": "#/definitions/api.APIError"
+                            "$ref": "#/definitions/api.ErrorEnvelope"
                         }
                     }
                 }
-            }
-        },
-        "/update/{type}/{id}/{value}": {
-            "post": {
-                "description": "Saves a metric using URL parameters. Counters are incremented, gauges are overwritten.",
+            },
+            "delete": {
+                "description": "Removes an alert rule of the tenant, its firing alerts are resolved on the next evaluation.",
                 "tags": [
-                    "Metrics"
+                    "Alerts"
                 ],
-                "summary": "Save metric (plain-text)",
+                "summary": "Delete alert rule",
                 "parameters": [
-                    {
-                        "enum": [
-                            "gauge",
-                            "counter"
-                        ],
-                        "type": "string",
-                        "description": "Metric type",
-                        "name": "type",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
-                        "description": "Metric ID",
+                        "description": "Rule ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Metric value",
-                        "name": "value",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Metric saved"
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/api.APIError"
-                        }
+                    "204": {
+                        "description": "Rule deleted"
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/api.APIError"
+                            "$ref": "#/definitions/api.ErrorEnvelope"
                         }
                     },
                     "500": {
                         "description": "Internal Error",
                         "schema": {
-                            "$ref": "#/definitions/api.APIError"
+                            "$ref": "#/definitions/api.ErrorEnvelope"
                         }
                     }
                 }
             }
         },
-        "/updates": {
-            "post": {
-                "description": "Saves multiple metrics. Counters are aggregated by ID, gauges use the last value.",
-                "consumes": [
-                    "application/json"
+        "/api/v1/export": {
+            "get": {
+                "description": "Streams all series of the tenant ordered by ID and labels.\nCounters carry their total as ` + "`" + `delta` + "`" + `, so exports can be imported into an empty server as is.\nCSV exports have a header row with the columns\nid,type,labels,delta,value,increment,bounds,buckets,sum,count,sketch,members,unit,timestamp,\nlabels, bounds, buckets and members cells are JSON-encoded, sketches are base64-encoded.",
+                "produces": [
+                    "application/json",
+                    "text/plain"
                 ],
                 "tags": [
-                    "Metrics"
+                    "Transfer"
                 ],
-                "summary": "Save metrics batch",
+                "summary": "Export metrics",
                 "parameters": [
                     {
-                        "description": "Metrics list",
-                        "name": "metrics",
-                        "in": "body",
-                        "required": true,
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "json"
+                        ],
+                        "type": "string",
+                        "description": "Export format, json by default",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exported metrics",
                         "schema": {
                             "type": "array",
                             "items": {
                                 "$ref": "#/definitions/models.Metrics"
                             }
                         }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Metrics saved"
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/api.APIError"
-                        }
-                    },
-                    "422": {
-                        "description": "Invalid JSON",
-                        "schema": {
-                            "$ref": "#/definitions/api.APIError"
+                            "$ref": "#/definitions/api.ErrorEnvelope"
                         }
                     },
                     "500": {
                         "description": "Internal Error",
                         "schema": {
-                            "$ref": "#/definitions/api.APIError"
+                            "$ref": "#/definitions/api.ErrorEnvelope"
                         }
                     }
                 }
             }
         },
-        "/value": {
+        "/api/v1/import": {
             "post": {
-                "description": "Returns full metric structure.",
+                "description": "Imports metrics in the export formats, records are stored like batch updates.\nInvalid records are reported with their line (the array position for json)\nand don't prevent valid records from being imported.\nImported counter deltas are added to stored counters,\nwith counters=replace counters are set to the last imported value of their series instead.\nWith dry_run records are only validated.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/plain"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Metrics"
+                    "Transfer"
                 ],
-                "summary": "Get metric (JSON)",
+                "summary": "Import metrics",
                 "parameters": [
                     {
-                        "description": "Metric identifier",
-                        "name": "metric",
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "json"
+                        ],
+                        "type": "string",
+                        "description": "Import format, json by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validate records without storing them",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "add",
+                            "replace"
+                        ],
+                        "type": "string",
+                        "description": "Counter mode, add by default",
+                        "name": "counters",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Metrics document",
+                        "name": "metrics",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Metrics"
+                            "type": "string"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of the request, repeated requests with the key get the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Metric data",
+                        "description": "Import report",
                         "schema": {
-                            "$ref": "#/definitions/models.Metrics"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ImportReport"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/api.APIError"
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Request with the idempotency key is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
                         }
                     },
                     "422": {
-                        "description": "Invalid JSON",
+                        "description": "Idempotency key used for a different request",
                         "schema": {
-                            "$ref": "#/definitions/api.APIError"
+                            "$ref": "#/definitions/api.ErrorEnvelope"
                         }
                     },
                     "500": {
                         "description": "Internal Error",
                         "schema": {
-                            "$ref": "#/definitions/api.APIError"
+                            "$ref": "#/definitions/api.ErrorEnvelope"
                         }
                     }
                 }
             }
         },
-        "/value/{type}/{id}": {
+        "/api/v1/meta/{id}": {
             "get": {
-                "description": "Returns raw metric value. Counter → int64, Gauge → float64.",
+                "description": "Returns unit, description and owner of a metric.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Metrics"
+                    "Meta"
                 ],
-                "summary": "Get metric value",
+                "summary": "Get metric metadata",
                 "parameters": [
-                    {
-                        "enum": [
-                            "gauge",
-                            "counter"
-                        ],
-                        "type": "string",
-                        "description": "Metric type",
-                        "name": "type",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Metric ID",
@@ -293,40 +511,1851 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Metric value",
-                        "schema": {}
+                        "description": "Metric metadata",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Meta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/api.APIError"
+                            "$ref": "#/definitions/api.ErrorEnvelope"
                         }
                     },
                     "500": {
                         "description": "Internal Error",
                         "schema": {
-                            "$ref": "#/definitions/api.APIError"
+                            "$ref": "#/definitions/api.ErrorEnvelope"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "api.APIError": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "description": "Error message\nexample: invalid metric type",
-                    "type": "string"
-                }
-            }
-        },
-        "models.Metrics": {
-            "type": "object",
-            "properties": {
-                "delta": {
-                    "description": "Counter increment value.\nUsed only when type is \"counter\".\nexample: 42",
+            },
+            "put": {
+                "description": "Stores unit, description and owner of a metric, replacing existing metadata.\nThe metric ID is taken from the path, an ID in the body is ignored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Meta"
+                ],
+                "summary": "Put metric metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Metric ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Metric metadata",
+                        "name": "meta",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Meta"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored metadata",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Meta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/metrics": {
+            "get": {
+                "description": "Returns full structures of series matching all given filters.\nPages are continued with the next_cursor of the previous page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "List metrics (JSON)",
+                "parameters": [
+                    {
+                        "enum": [
+                            "gauge",
+                            "counter",
+                            "histogram",
+                            "summary",
+                            "set"
+                        ],
+                        "type": "string",
+                        "description": "Metric type, all types when omitted",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Metric ID prefix",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Glob pattern of metric IDs (path.Match syntax)",
+                        "name": "pattern",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Regular expression of metric IDs (RE2 syntax)",
+                        "name": "regex",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "type"
+                        ],
+                        "type": "string",
+                        "description": "Sort field (default: id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order (default: asc)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default: 100, max: 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Metrics page",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.MetricsPage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Saves multiple metrics with the semantics of batch updates.\nBy default an invalid metric rejects the whole batch.\nWith partial=true every metric is validated, valid metrics are saved\nand the response lists rejected metrics by their index in the batch.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Save metrics",
+                "parameters": [
+                    {
+                        "description": "Metrics list",
+                        "name": "metrics",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Metrics"
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Save valid metrics and report rejected ones",
+                        "name": "partial",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of the request, repeated requests with the key get the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Per-item results",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.BatchResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Request with the idempotency key is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON or idempotency key used for a different request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes all series of metrics whose IDs match a glob pattern (path.Match syntax, e.g. cpu_*),\ntogether with their history. Returns keys of deleted series.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Delete metrics by pattern",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Glob pattern of metric IDs",
+                        "name": "pattern",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "gauge",
+                            "counter",
+                            "histogram",
+                            "summary",
+                            "set"
+                        ],
+                        "type": "string",
+                        "description": "Metric type, all types when omitted",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deleted series",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.DeleteResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/metrics/query": {
+            "post": {
+                "description": "Returns full structures of the requested series in request order.\nSeries that don't exist are omitted. Summaries include estimated quantiles.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Query metrics",
+                "parameters": [
+                    {
+                        "description": "Series identifiers",
+                        "name": "metrics",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SeriesRef"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Metrics data",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Metrics"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/metrics/{type}/{id}": {
+            "get": {
+                "description": "Returns the full structure of a series. Summaries include estimated quantiles.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Get metric",
+                "parameters": [
+                    {
+                        "enum": [
+                            "gauge",
+                            "counter",
+                            "histogram",
+                            "summary",
+                            "set"
+                        ],
+                        "type": "string",
+                        "description": "Metric type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Metric ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Series label as name=value",
+                        "name": "label",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Metric data",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Metrics"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes all series of a metric, whatever their labels, together with their history.",
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Delete metric",
+                "parameters": [
+                    {
+                        "enum": [
+                            "gauge",
+                            "counter",
+                            "histogram",
+                            "summary",
+                            "set"
+                        ],
+                        "type": "string",
+                        "description": "Metric type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Metric ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Metric deleted"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/range": {
+            "get": {
+                "description": "Returns points recorded for a series within [from, to] in chronological order.\nWhen step is set, points are aggregated into step windows:\ncounter deltas are summed, gauges keep the last value.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Get metric history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Metric ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "gauge",
+                            "counter"
+                        ],
+                        "type": "string",
+                        "description": "Metric type",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Series label as name=value",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Range start, Unix milliseconds or RFC3339 (default: to - 1h)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Range end, Unix milliseconds or RFC3339 (default: now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Aggregation window, e.g. 30s or 5m",
+                        "name": "step",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Series points",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Point"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/rate/{id}": {
+            "get": {
+                "description": "Returns the increase and per-second rate of a counter, or the per-second derivative of a gauge,\nwithin the window ending now. Rates are computed from samples the server keeps in memory\nat one second resolution for the configured rate window, independently of history storage,\nso samples saved before a restart are not included.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Get metric rate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Metric ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "counter",
+                            "gauge"
+                        ],
+                        "type": "string",
+                        "description": "Metric type, counter by default",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Window length, e.g. 30s or 5m, 1m by default",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Series label as name=value",
+                        "name": "label",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Series rate",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Rate"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stream": {
+            "get": {
+                "description": "Pushes current states of series as they are saved, one \"metrics\" event per save.\nEvent data is a JSON array of metrics, series saved by a single batch update share one event.\nClients resume after reconnecting by sending the ID of the last received event in the Last-Event-ID header;\nrecent events are replayed, older ones are lost.\nWith throttle set, every series is sent at most once per interval and only its latest state is kept back.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Stream metric changes (SSE)",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Metric IDs to stream, all metrics when omitted",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum interval between updates of a series, e.g. 1s",
+                        "name": "throttle",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last received event",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event stream of metric arrays",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Metrics"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/values": {
+            "get": {
+                "description": "Returns full structures of series matching all given filters.\nPages are continued with the next_cursor of the previous page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "List metrics (JSON)",
+                "parameters": [
+                    {
+                        "enum": [
+                            "gauge",
+                            "counter",
+                            "histogram",
+                            "summary",
+                            "set"
+                        ],
+                        "type": "string",
+                        "description": "Metric type, all types when omitted",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Metric ID prefix",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Glob pattern of metric IDs (path.Match syntax)",
+                        "name": "pattern",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Regular expression of metric IDs (RE2 syntax)",
+                        "name": "regex",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "type"
+                        ],
+                        "type": "string",
+                        "description": "Sort field (default: id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order (default: asc)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default: 100, max: 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Metrics page",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.MetricsPage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/write": {
+            "post": {
+                "description": "Accepts writes of the InfluxDB v2 API, such as those of the Telegraf influxdb_v2 output.\nEvery field becomes a metric named measurement_field with the line tags as labels,\ntag keys are sanitized into label names (host-name becomes host_name):\nfloat fields are gauges, integer fields (i and u suffixes) are counter deltas,\nstring and boolean fields are skipped.\nA malformed line rejects the whole request. The org and bucket parameters are ignored,\nthe tenant is resolved from the request like on other endpoints.\nRequests are not signed, use tenant API keys (X-API-Key header) to authenticate writers.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Write line protocol",
+                "parameters": [
+                    {
+                        "enum": [
+                            "ns",
+                            "us",
+                            "ms",
+                            "s"
+                        ],
+                        "type": "string",
+                        "description": "Timestamp precision, ns by default",
+                        "name": "precision",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization, ignored",
+                        "name": "org",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket, ignored",
+                        "name": "bucket",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Line protocol document",
+                        "name": "lines",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Metrics saved"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "Returns all series of the tenant in the Prometheus text exposition format.\nCounters are exposed as counter, gauges and set cardinalities as gauge,\nhistograms and summaries as histogram and summary.\nMetric and label names are sanitized to the Prometheus charset,\nHELP lines are taken from metric metadata descriptions.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Get all metrics (Prometheus)",
+                "responses": {
+                    "200": {
+                        "description": "Metrics in text exposition format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{type}/{id}": {
+            "get": {
+                "description": "Returns an HTML page with the current value, unit, description and last update of a series,\nhistogram buckets, summary quantiles and, for counters and gauges, values recorded in the last hour.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Series detail page (HTML)",
+                "parameters": [
+                    {
+                        "enum": [
+                            "gauge",
+                            "counter",
+                            "histogram",
+                            "summary",
+                            "set"
+                        ],
+                        "type": "string",
+                        "description": "Metric type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Metric ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Series label as name=value",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 3600,
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Auto-refresh interval in seconds, 0 disables it",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "HTML series page"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/update": {
+            "post": {
+                "description": "Saves a metric using JSON body. Counters are incremented, gauges are overwritten\nor changed by ` + "`" + `increment` + "`" + `,\nhistograms are merged bucket by bucket, summary and set sketches are merged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Save metric (JSON)",
+                "parameters": [
+                    {
+                        "description": "Metric payload",
+                        "name": "metric",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Metrics"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of the request, repeated requests with the key get the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Saved metric",
+                        "schema": {
+                            "$ref": "#/definitions/models.Metrics"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Request with the idempotency key is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON or idempotency key used for a different request",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/update/{type}/{id}/{value}": {
+            "post": {
+                "description": "Saves a metric using URL parameters. Counters are incremented, gauges are overwritten,\ngauge values prefixed with \"+\" are added to the stored value (\"+5\" increments, \"+-5\" decrements),\nhistograms and summaries record the value as a single observation, sets add the value as a member.",
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Save metric (plain-text)",
+                "parameters": [
+                    {
+                        "enum": [
+                            "gauge",
+                            "counter",
+                            "histogram",
+                            "summary",
+                            "set"
+                        ],
+                        "type": "string",
+                        "description": "Metric type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Metric ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Metric value",
+                        "name": "value",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of the request, repeated requests with the key get the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Metric saved"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Request with the idempotency key is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency key used for a different request",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/updates": {
+            "post": {
+                "description": "Saves multiple metrics. Counters are aggregated by ID, gauges use the last value,\ngauge increments are applied in order,\nhistograms, summaries and sets with the same ID are merged.\nBy default an invalid metric rejects the whole batch.\nWith partial=true every metric is validated, valid metrics are saved\nand the response lists rejected metrics by their index in the batch.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Save metrics batch",
+                "parameters": [
+                    {
+                        "description": "Metrics list",
+                        "name": "metrics",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Metrics"
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Save valid metrics and report rejected ones",
+                        "name": "partial",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of the request, repeated requests with the key get the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Metrics saved, per-item results with partial=true",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Request with the idempotency key is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON or idempotency key used for a different request",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/metrics": {
+            "post": {
+                "description": "Accepts the OTLP/HTTP JSON encoding of metric exports, protobuf bodies are not supported.\nGauges are saved as gauges, monotonic sums as counters with cumulative totals\nconverted to deltas per series, non-monotonic sums as gauges of their current value.\nResource and data point attributes become series labels, with names sanitized\ninto label names (service.name becomes service_name).\nHistograms, exponential histograms, summaries, fractional monotonic sums and invalid data points are rejected\nand reported as partial success, accepted data points are saved.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Export OTLP metrics",
+                "parameters": [
+                    {
+                        "description": "Export request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/otlp.ExportMetricsServiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Accepted data points saved",
+                        "schema": {
+                            "$ref": "#/definitions/otlp.ExportMetricsServiceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/value": {
+            "post": {
+                "description": "Returns full metric structure. Summaries include estimated quantiles.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Get metric (JSON)",
+                "parameters": [
+                    {
+                        "description": "Metric identifier",
+                        "name": "metric",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Metrics"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Metric data",
+                        "schema": {
+                            "$ref": "#/definitions/models.Metrics"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/value/{type}/{id}": {
+            "get": {
+                "description": "Returns raw metric value. Counter → int64, Gauge → float64, Histogram → HistogramSnapshot,\nSummary → SummarySnapshot with estimated quantiles, Set → int64 estimated distinct count.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Get metric value",
+                "parameters": [
+                    {
+                        "enum": [
+                            "gauge",
+                            "counter",
+                            "histogram",
+                            "summary",
+                            "set"
+                        ],
+                        "type": "string",
+                        "description": "Metric type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Metric ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Metric value",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes all series of a metric, whatever their labels, together with their history.",
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Delete metric",
+                "parameters": [
+                    {
+                        "enum": [
+                            "gauge",
+                            "counter",
+                            "histogram",
+                            "summary",
+                            "set"
+                        ],
+                        "type": "string",
+                        "description": "Metric type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Metric ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Metric deleted"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/values": {
+            "post": {
+                "description": "Returns full structures of the requested series in request order.\nSeries that don't exist are omitted. Summaries include estimated quantiles.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Get metrics batch (JSON)",
+                "parameters": [
+                    {
+                        "description": "Series identifiers",
+                        "name": "metrics",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SeriesRef"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Metrics data",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Metrics"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.APIError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Error message\nexample: invalid metric type",
+                    "type": "string"
+                }
+            }
+        },
+        "api.Envelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Response payload"
+                }
+            }
+        },
+        "api.ErrorBody": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Machine-readable error code derived from the HTTP status\nexample: not_found",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Error message\nexample: metric not found",
+                    "type": "string"
+                }
+            }
+        },
+        "api.ErrorEnvelope": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/api.ErrorBody"
+                }
+            }
+        },
+        "models.Alert": {
+            "type": "object",
+            "properties": {
+                "active_at": {
+                    "description": "Time the condition started to hold.",
+                    "type": "string"
+                },
+                "fingerprint": {
+                    "description": "Stable identity of the alert, the same for all notifications\nof a rule and series, used to deduplicate them.\nexample: 9f3c2a1b7d4e5f60",
+                    "type": "string"
+                },
+                "fired_at": {
+                    "description": "Time the alert started firing.",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Metric identifier (name) of the series.\nexample: FreeMemory",
+                    "type": "string"
+                },
+                "labels": {
+                    "description": "Labels of the series.\nexample: {\"host\":\"web-1\"}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "op": {
+                    "description": "Rule comparison.\nexample: \u003c",
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "description": "Time the alert was resolved.",
+                    "type": "string"
+                },
+                "rule": {
+                    "description": "Identifier of the rule raising the alert.\nexample: low-memory",
+                    "type": "string"
+                },
+                "state": {
+                    "description": "Alert state.\nenum: pending,firing,resolved",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AlertState"
+                        }
+                    ]
+                },
+                "tenant": {
+                    "description": "Tenant owning the rule and the series.\nexample: team-a",
+                    "type": "string"
+                },
+                "threshold": {
+                    "description": "Rule threshold.\nexample: 104857600",
+                    "type": "number"
+                },
+                "type": {
+                    "description": "Metric type of the series.\nexample: gauge",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Series value at the last evaluation.\nexample: 52428800",
+                    "type": "number"
+                }
+            }
+        },
+        "models.AlertRule": {
+            "type": "object",
+            "properties": {
+                "for": {
+                    "description": "Seconds the condition must hold before the alert fires,\nzero fires on the first evaluation.\nexample: 300",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "Rule identifier, unique within the tenant.\nrequired: true\nexample: low-memory",
+                    "type": "string"
+                },
+                "labels": {
+                    "description": "Labels a series must carry to match, all series when omitted.\nexample: {\"host\":\"web-1\"}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "metric": {
+                    "description": "Metric ID or glob pattern of metric IDs (path.Match syntax).\nrequired: true\nexample: FreeMemory",
+                    "type": "string"
+                },
+                "op": {
+                    "description": "Comparison of the series value with the threshold.\nValues are gauge values, counter totals, observation counts\nof histograms and summaries and set cardinalities.\nrequired: true\nenum: \u003c,\u003c=,\u003e,\u003e=,==,!=,unchanged\nexample: \u003c",
+                    "type": "string"
+                },
+                "tenant": {
+                    "description": "Tenant owning the rule, taken from the request for API calls.\nRules loaded from a file default to the default tenant.\nexample: team-a",
+                    "type": "string"
+                },
+                "threshold": {
+                    "description": "Threshold the series value is compared with.\nexample: 104857600",
+                    "type": "number"
+                },
+                "type": {
+                    "description": "Metric type, all types when omitted.\nenum: gauge,counter,histogram,summary,set",
+                    "type": "string"
+                },
+                "webhooks": {
+                    "description": "Webhook URLs notified about the rule alerts,\nthe server-wide webhooks when omitted.\nexample: [\"https://hooks.example.com/alerts\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.AlertState": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "firing",
+                "resolved"
+            ],
+            "x-enum-varnames": [
+                "AlertPending",
+                "AlertFiring",
+                "AlertResolved"
+            ]
+        },
+        "models.BatchError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Reason the item was rejected.\nexample: counter delta is required",
+                    "type": "string"
+                },
+                "index": {
+                    "description": "Position of the item in the batch, starting at 0.\nexample: 2",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BatchResult": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "description": "Number of applied items.\nexample: 9",
+                    "type": "integer"
+                },
+                "errors": {
+                    "description": "Rejected items ordered by index.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchError"
+                    }
+                },
+                "rejected": {
+                    "description": "Number of rejected items.\nexample: 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DeleteResult": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "description": "Keys of deleted series.\nexample: [\"cpu_user\",\"cpu_system{host=\\\"web-1\\\"}\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ImportError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Reason the record was rejected.\nexample: counter delta is required",
+                    "type": "string"
+                },
+                "line": {
+                    "description": "Line of CSV and NDJSON records, position of JSON array elements, starting at 1.\nexample: 3",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportReport": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "description": "Whether records were only validated.",
+                    "type": "boolean"
+                },
+                "errors": {
+                    "description": "Rejected records ordered by line.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportError"
+                    }
+                },
+                "failed": {
+                    "description": "Number of rejected records.\nexample: 1",
+                    "type": "integer"
+                },
+                "imported": {
+                    "description": "Number of valid records, imported unless dry_run is set.\nexample: 9",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Number of records read.\nexample: 10",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Meta": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Human-readable description of the metric.\nexample: Bytes of allocated heap objects",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Metric identifier (name).\nexample: HeapAlloc",
+                    "type": "string"
+                },
+                "owner": {
+                    "description": "Team or person owning the metric.\nexample: platform-team",
+                    "type": "string"
+                },
+                "ttl": {
+                    "description": "Seconds a series of the metric is kept without updates\nbefore it is purged. Zero falls back to the server-wide TTL.\nexample: 3600",
+                    "type": "integer"
+                },
+                "unit": {
+                    "description": "Unit of the metric values.\nexample: bytes",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Metrics": {
+            "type": "object",
+            "properties": {
+                "bounds": {
+                    "description": "Histogram upper bucket bounds in ascending order.\nUsed only when type is \"histogram\".\nexample: [0.1,0.5,1]",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "buckets": {
+                    "description": "Histogram bucket counts.\nHas one element more than bounds: the last bucket counts\nobservations greater than the highest bound.\nUsed only when type is \"histogram\".\nexample: [3,5,1,0]",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "count": {
+                    "description": "Number of observed values.\nFor sets, the estimated number of distinct members.\nUsed only when type is \"histogram\", \"summary\" or \"set\".\nexample: 9",
+                    "type": "integer"
+                },
+                "delta": {
+                    "description": "Counter increment value.\nUsed only when type is \"counter\".\nexample: 42",
                     "type": "integer"
                 },
                 "hash": {
@@ -337,8 +2366,51 @@ const docTemplate = `{
                     "description": "Metric identifier (name).\nrequired: true",
                     "type": "string"
                 },
+                "increment": {
+                    "description": "Gauge relative change added to the stored value.\nNegative values decrement the gauge, a missing gauge starts from zero.\nUsed only when type is \"gauge\" instead of value.\nexample: -2",
+                    "type": "number"
+                },
+                "labels": {
+                    "description": "Optional series labels.\nA metric series is identified by its ID together with the label set,\nso the same ID with different labels produces independent series.\nexample: {\"host\":\"web-1\",\"route\":\"/api\"}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "members": {
+                    "description": "Set members to add, duplicates are counted once.\nMembers are folded into the sketch and never stored.\nUsed only when type is \"set\".\nexample: [\"user-1\",\"user-2\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "quantiles": {
+                    "description": "Estimated quantile values keyed by quantile, such as \"0.99\".\nFilled in value lookups of summaries, ignored on updates.\nexample: {\"0.5\":0.12,\"0.99\":0.87}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number",
+                        "format": "float64"
+                    }
+                },
+                "sketch": {
+                    "description": "Serialized sketch, base64-encoded in JSON:\nDDSketch of observed values for summaries,\nHyperLogLog registers of members for sets.\nUsed only when type is \"summary\" or \"set\".",
+                    "type": "string",
+                    "format": "base64"
+                },
+                "sum": {
+                    "description": "Sum of all observed values.\nUsed only when type is \"histogram\" or \"summary\".\nexample: 2.75",
+                    "type": "number"
+                },
+                "timestamp": {
+                    "description": "Optional Unix timestamp of the value in milliseconds.\nThe server time is used when omitted.\nexample: 1700000000000",
+                    "type": "integer"
+                },
                 "type": {
-                    "description": "Metric type.\nrequired: true\nenum: gauge,counter",
+                    "description": "Metric type.\nrequired: true\nenum: gauge,counter,histogram,summary,set",
+                    "type": "string"
+                },
+                "unit": {
+                    "description": "Optional unit of the metric values.\nRegistered as metric metadata on first report if none is stored yet,\nfilled from metadata in value lookups.\nexample: bytes",
                     "type": "string"
                 },
                 "value": {
@@ -346,6 +2418,325 @@ const docTemplate = `{
                     "type": "number"
                 }
             }
+        },
+        "models.MetricsPage": {
+            "type": "object",
+            "properties": {
+                "metrics": {
+                    "description": "Series of the page.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Metrics"
+                    }
+                },
+                "next_cursor": {
+                    "description": "Opaque cursor of the next page, omitted on the last page.\nexample: eyJpZCI6ImNwdSIsInR5cGUiOiJnYXVnZSJ9",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Point": {
+            "type": "object",
+            "properties": {
+                "delta": {
+                    "description": "Counter increment recorded at the timestamp.\nSet only for counter series.\nexample: 5",
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "description": "Unix timestamp in milliseconds.\nexample: 1700000000000",
+                    "type": "integer"
+                },
+                "value": {
+                    "description": "Gauge value recorded at the timestamp.\nSet only for gauge series.\nexample: 3.14",
+                    "type": "number"
+                }
+            }
+        },
+        "models.Rate": {
+            "type": "object",
+            "properties": {
+                "derivative": {
+                    "description": "Least-squares slope of gauge values per second.\nSet only for gauge series with samples at two distinct times at least.\nexample: -0.5",
+                    "type": "number"
+                },
+                "id": {
+                    "description": "Metric identifier (name).",
+                    "type": "string"
+                },
+                "increase": {
+                    "description": "Sum of counter deltas within the window.\nSet only for counter series.\nexample: 120",
+                    "type": "integer"
+                },
+                "labels": {
+                    "description": "Series labels.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "rate": {
+                    "description": "Counter increase per second of the window.\nSet only for counter series.\nexample: 2",
+                    "type": "number"
+                },
+                "samples": {
+                    "description": "Number of samples within the window, samples are kept at one second resolution.\nexample: 12",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "Metric type.\nenum: gauge,counter",
+                    "type": "string"
+                },
+                "window": {
+                    "description": "Window length in seconds.\nexample: 60",
+                    "type": "number"
+                }
+            }
+        },
+        "models.SeriesRef": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Metric identifier (name).\nrequired: true",
+                    "type": "string"
+                },
+                "labels": {
+                    "description": "Optional series labels, the unlabeled series when omitted.\nexample: {\"host\":\"web-1\"}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "description": "Metric type.\nrequired: true\nenum: gauge,counter,histogram,summary,set",
+                    "type": "string"
+                }
+            }
+        },
+        "otlp.AnyValue": {
+            "type": "object",
+            "properties": {
+                "arrayValue": {
+                    "type": "object"
+                },
+                "boolValue": {
+                    "type": "boolean"
+                },
+                "bytesValue": {
+                    "type": "string"
+                },
+                "doubleValue": {
+                    "type": "number"
+                },
+                "intValue": {
+                    "type": "string"
+                },
+                "kvlistValue": {
+                    "type": "object"
+                },
+                "stringValue": {
+                    "type": "string"
+                }
+            }
+        },
+        "otlp.ExportMetricsServiceRequest": {
+            "type": "object",
+            "properties": {
+                "resourceMetrics": {
+                    "description": "Metrics grouped by the resource reporting them.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/otlp.ResourceMetrics"
+                    }
+                }
+            }
+        },
+        "otlp.ExportMetricsServiceResponse": {
+            "type": "object",
+            "properties": {
+                "partialSuccess": {
+                    "description": "Set when some data points were rejected.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/otlp.PartialSuccess"
+                        }
+                    ]
+                }
+            }
+        },
+        "otlp.Gauge": {
+            "type": "object",
+            "properties": {
+                "dataPoints": {
+                    "description": "Data points of the gauge.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/otlp.NumberDataPoint"
+                    }
+                }
+            }
+        },
+        "otlp.KeyValue": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "description": "Attribute name.",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Attribute value.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/otlp.AnyValue"
+                        }
+                    ]
+                }
+            }
+        },
+        "otlp.Metric": {
+            "type": "object",
+            "properties": {
+                "exponentialHistogram": {
+                    "description": "Exponential histogram data points, not supported.",
+                    "type": "object"
+                },
+                "gauge": {
+                    "description": "Gauge data points.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/otlp.Gauge"
+                        }
+                    ]
+                },
+                "histogram": {
+                    "description": "Histogram data points, not supported.",
+                    "type": "object"
+                },
+                "name": {
+                    "description": "Metric name.\nexample: http.server.requests",
+                    "type": "string"
+                },
+                "sum": {
+                    "description": "Sum data points.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/otlp.Sum"
+                        }
+                    ]
+                },
+                "summary": {
+                    "description": "Summary data points, not supported.",
+                    "type": "object"
+                },
+                "unit": {
+                    "description": "Optional unit of the data point values.\nexample: ms",
+                    "type": "string"
+                }
+            }
+        },
+        "otlp.NumberDataPoint": {
+            "type": "object",
+            "properties": {
+                "asDouble": {
+                    "description": "Floating-point value.",
+                    "type": "number"
+                },
+                "asInt": {
+                    "description": "Integer value.",
+                    "type": "string"
+                },
+                "attributes": {
+                    "description": "Series attributes.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/otlp.KeyValue"
+                    }
+                },
+                "startTimeUnixNano": {
+                    "description": "Start of the aggregation interval in Unix nanoseconds.",
+                    "type": "string"
+                },
+                "timeUnixNano": {
+                    "description": "Time of the value in Unix nanoseconds.",
+                    "type": "string"
+                }
+            }
+        },
+        "otlp.PartialSuccess": {
+            "type": "object",
+            "properties": {
+                "errorMessage": {
+                    "description": "Reason of the first rejection.\nexample: histogram metrics are not supported",
+                    "type": "string"
+                },
+                "rejectedDataPoints": {
+                    "description": "Number of rejected data points.\nexample: 2",
+                    "type": "integer"
+                }
+            }
+        },
+        "otlp.Resource": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "Resource attributes, such as service.name.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/otlp.KeyValue"
+                    }
+                }
+            }
+        },
+        "otlp.ResourceMetrics": {
+            "type": "object",
+            "properties": {
+                "resource": {
+                    "description": "Resource reporting the metrics.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/otlp.Resource"
+                        }
+                    ]
+                },
+                "scopeMetrics": {
+                    "description": "Metrics grouped by instrumentation scope.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/otlp.ScopeMetrics"
+                    }
+                }
+            }
+        },
+        "otlp.ScopeMetrics": {
+            "type": "object",
+            "properties": {
+                "metrics": {
+                    "description": "Reported metrics.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/otlp.Metric"
+                    }
+                }
+            }
+        },
+        "otlp.Sum": {
+            "type": "object",
+            "properties": {
+                "aggregationTemporality": {
+                    "description": "Aggregation temporality: 1 for delta, 2 for cumulative.\nenum: 0,1,2",
+                    "type": "integer"
+                },
+                "dataPoints": {
+                    "description": "Data points of the sum.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/otlp.NumberDataPoint"
+                    }
+                },
+                "isMonotonic": {
+                    "description": "Whether the sum only increases.",
+                    "type": "boolean"
+                }
+            }
         }
     }
 }`
@@ -357,7 +2748,7 @@ var SwaggerInfo = &swag.Spec{
 	BasePath:         "/",
 	Schemes:          []string{},
 	Title:            "Metrics Service API",
-	Description:      "HTTP API for collecting and retrieving gauge and counter metrics.\nSupports plain-text, JSON and batch endpoints.",
+	Description:      "HTTP API for collecting and retrieving gauge and counter metrics.\nSupports plain-text, JSON and batch endpoints.\nVersioned /api/v1 endpoints wrap JSON responses in {\"data\": ...}\nand errors in {\"error\": {\"code\": ..., \"message\": ...}}, unversioned endpoints are kept for existing agents.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
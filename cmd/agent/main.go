@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
@@ -12,8 +13,14 @@ import (
 
 	"github.com/gabkaclassic/metrics/internal/agent"
 	"github.com/gabkaclassic/metrics/internal/config"
+	"github.com/gabkaclassic/metrics/internal/pb"
+	"github.com/gabkaclassic/metrics/pkg/encrypt"
 	"github.com/gabkaclassic/metrics/pkg/httpclient"
+	"github.com/gabkaclassic/metrics/pkg/interceptor"
 	"github.com/gabkaclassic/metrics/pkg/logger"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 var (
@@ -58,10 +65,25 @@ func run() error {
 		httpclient.BaseURL(cfg.Client.BaseURL),
 		httpclient.Timeout(cfg.Client.Timeout),
 		httpclient.MaxRetries(cfg.Client.Retries),
+		httpclient.HeadersOption(tenantHeaders(cfg)),
 	)
 
+	options, closeTransport, err := transportOptions(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to setup transport: %w", err)
+	}
+	defer closeTransport()
+
+	publicKey, err := config.LoadPublicKey(cfg.CryptoKey)
+	if err != nil {
+		return fmt.Errorf("failed to setup encryption: %w", err)
+	}
+	if publicKey != nil {
+		options = append(options, agent.WithEncryption(encrypt.NewRSAEncryptor(publicKey)))
+	}
+
 	agent, err := agent.NewAgent(
-		client, cfg.BatchesEnabled, cfg.SignKey, cfg.RateLimit, cfg.BatchSize,
+		client, cfg.BatchesEnabled, cfg.SignKey, cfg.RateLimit, cfg.BatchSize, options...,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to initialize agent: %w", err)
@@ -72,6 +94,52 @@ func run() error {
 	return nil
 }
 
+// tenantHeaders returns headers identifying the agent's tenant on the server.
+func tenantHeaders(cfg *config.Agent) httpclient.Headers {
+	headers := make(httpclient.Headers)
+	if cfg.Tenant != "" {
+		headers[middleware.TenantHeader] = cfg.Tenant
+	}
+	if cfg.APIKey != "" {
+		headers[middleware.APIKeyHeader] = cfg.APIKey
+	}
+	return headers
+}
+
+// transportOptions returns agent options of the configured report transport
+// and a function releasing its resources.
+func transportOptions(cfg *config.Agent) ([]agent.Option, func(), error) {
+	switch cfg.Transport {
+	case "", "http":
+		return nil, func() {}, nil
+	case "grpc":
+		if cfg.GRPCAddress == "" {
+			return nil, nil, errors.New("gRPC address is required for grpc transport")
+		}
+
+		headers := tenantHeaders(cfg)
+		conn, err := grpc.NewClient(
+			cfg.GRPCAddress,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithChainUnaryInterceptor(interceptor.UnaryClientMetadata(headers)),
+			grpc.WithChainStreamInterceptor(interceptor.StreamClientMetadata(headers)),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		closeConn := func() {
+			if err := conn.Close(); err != nil {
+				slog.Error("Close gRPC connection error", slog.String("error", err.Error()))
+			}
+		}
+
+		return []agent.Option{agent.WithGRPC(pb.NewMetricsClient(conn), cfg.Client.Timeout)}, closeConn, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown transport: %s", cfg.Transport)
+	}
+}
+
 func startAgent(pollInterval, reportInterval time.Duration, agent *agent.MetricsAgent) {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
@@ -0,0 +1,69 @@
+// Command keygen generates the RSA key pair of request body encryption.
+//
+// The private key is passed to the server and the public key
+// to agents with the -crypto-key flag:
+//
+//	keygen -bits 4096 -private private.pem -public public.pem
+//
+// Existing key files are never overwritten.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/gabkaclassic/metrics/pkg/encrypt"
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	bits := flag.Int("bits", 4096, "RSA key size in bits")
+	privatePath := flag.String("private", "private.pem", "Private key output file")
+	publicPath := flag.String("public", "public.pem", "Public key output file")
+
+	flag.Parse()
+
+	privatePEM, publicPEM, err := encrypt.GenerateKeyPair(*bits)
+	if err != nil {
+		return err
+	}
+
+	if err := writeKey(*privatePath, privatePEM, 0o600); err != nil {
+		return err
+	}
+
+	if err := writeKey(*publicPath, publicPEM, 0o644); err != nil {
+		return err
+	}
+
+	fmt.Printf("Private key: %s\n", *privatePath)
+	fmt.Printf("Public key: %s\n", *publicPath)
+
+	return nil
+}
+
+// writeKey writes a PEM key to a new file.
+func writeKey(path string, data []byte, perm os.FileMode) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return fmt.Errorf("failed to create key file: %w", err)
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close key file: %w", err)
+	}
+
+	return nil
+}
@@ -5,20 +5,33 @@ import (
 	"fmt"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
 
+	"github.com/gabkaclassic/metrics/internal/alert"
 	"github.com/gabkaclassic/metrics/internal/audit"
 	"github.com/gabkaclassic/metrics/internal/config"
 	"github.com/gabkaclassic/metrics/internal/dump"
+	"github.com/gabkaclassic/metrics/internal/graphite"
 	"github.com/gabkaclassic/metrics/internal/handler"
+	"github.com/gabkaclassic/metrics/internal/janitor"
+	"github.com/gabkaclassic/metrics/internal/otlp"
+	"github.com/gabkaclassic/metrics/internal/pb"
 	"github.com/gabkaclassic/metrics/internal/repository"
+	"github.com/gabkaclassic/metrics/internal/rpc"
 	"github.com/gabkaclassic/metrics/internal/service"
+	"github.com/gabkaclassic/metrics/internal/statsd"
 	"github.com/gabkaclassic/metrics/internal/storage"
+	"github.com/gabkaclassic/metrics/internal/stream"
+	"github.com/gabkaclassic/metrics/pkg/encrypt"
+	"github.com/gabkaclassic/metrics/pkg/grpcserver"
+	"github.com/gabkaclassic/metrics/pkg/httpclient"
 	"github.com/gabkaclassic/metrics/pkg/httpserver"
+	"github.com/gabkaclassic/metrics/pkg/interceptor"
 	"github.com/gabkaclassic/metrics/pkg/logger"
 )
 
@@ -32,6 +45,8 @@ var (
 // @version 1.0
 // @description HTTP API for collecting and retrieving gauge and counter metrics.
 // @description Supports plain-text, JSON and batch endpoints.
+// @description Versioned /api/v1 endpoints wrap JSON responses in {"data": ...}
+// @description and errors in {"error": {"code": ..., "message": ...}}, unversioned endpoints are kept for existing agents.
 // @contact.name gabkaclassic
 // @license.name MIT
 // @BasePath /
@@ -70,6 +85,9 @@ func run() error {
 	defer stop()
 
 	var metricsRepository repository.MetricsRepository
+	var metaRepository repository.MetaRepository
+	var alertRuleRepository repository.AlertRuleRepository
+	var idempotencyRepository repository.IdempotencyRepository
 	var dumper *dump.Dumper
 	var dumperEnabled bool
 
@@ -85,9 +103,25 @@ func run() error {
 			return fmt.Errorf("failed to create metrics repository (DB): %w", err)
 		}
 
+		metaRepository, err = repository.NewDBMetaRepository(storage)
+		if err != nil {
+			return fmt.Errorf("failed to create meta repository (DB): %w", err)
+		}
+
+		alertRuleRepository, err = repository.NewDBAlertRuleRepository(storage)
+		if err != nil {
+			return fmt.Errorf("failed to create alert rule repository (DB): %w", err)
+		}
+
+		idempotencyRepository, err = repository.NewDBIdempotencyRepository(storage)
+		if err != nil {
+			return fmt.Errorf("failed to create idempotency repository (DB): %w", err)
+		}
+
 		slog.Info("Using database storage")
 	} else {
 		storage := storage.NewMemStorage()
+		storage.HistorySize = cfg.History.Size
 		storageMutex := &sync.RWMutex{}
 
 		metricsRepository, err = repository.NewMemoryMetricsRepository(storage, storageMutex)
@@ -95,6 +129,21 @@ func run() error {
 			return fmt.Errorf("failed to create metrics repository (in-memory): %w", err)
 		}
 
+		metaRepository, err = repository.NewMemoryMetaRepository(storage, storageMutex)
+		if err != nil {
+			return fmt.Errorf("failed to create meta repository (in-memory): %w", err)
+		}
+
+		alertRuleRepository, err = repository.NewMemoryAlertRuleRepository(storage, storageMutex)
+		if err != nil {
+			return fmt.Errorf("failed to create alert rule repository (in-memory): %w", err)
+		}
+
+		idempotencyRepository, err = repository.NewMemoryIdempotencyRepository(storage, storageMutex)
+		if err != nil {
+			return fmt.Errorf("failed to create idempotency repository (in-memory): %w", err)
+		}
+
 		dumper, err = dump.NewDumper(cfg.Dump.FileStoragePath, metricsRepository)
 		if err != nil {
 			return fmt.Errorf("failed to initialize dumper: %w", err)
@@ -113,7 +162,42 @@ func run() error {
 		return fmt.Errorf("failed to create auditor: %w", err)
 	}
 
-	router, err := setupRouter(&metricsRepository, cfg.SignKey, auditor)
+	metricsJanitor, err := janitor.NewJanitor(metricsRepository, metaRepository, auditor)
+	if err != nil {
+		return fmt.Errorf("failed to create janitor: %w", err)
+	}
+
+	alertEngine, err := setupAlertEngine(ctx, metricsRepository, alertRuleRepository, cfg.Alert)
+	if err != nil {
+		return fmt.Errorf("failed to setup alert engine: %w", err)
+	}
+
+	broker, err := stream.NewBroker(stream.DefaultHistorySize)
+	if err != nil {
+		return fmt.Errorf("failed to create stream broker: %w", err)
+	}
+	context.AfterFunc(ctx, broker.Close)
+
+	metricsService, err := service.NewMetricsService(
+		metricsRepository,
+		metaRepository,
+		auditor,
+		service.WithPublisher(broker),
+		service.WithRateWindow(cfg.Rate.Window),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create metrics service: %w", err)
+	}
+
+	var idempotencyService service.IdempotencyService
+	if cfg.Idempotency.TTL > 0 {
+		idempotencyService, err = service.NewIdempotencyService(idempotencyRepository, cfg.Idempotency.TTL)
+		if err != nil {
+			return fmt.Errorf("failed to create idempotency service: %w", err)
+		}
+	}
+
+	router, err := setupRouter(metricsService, metaRepository, alertRuleRepository, idempotencyService, alertEngine, broker, cfg)
 	if err != nil {
 		return fmt.Errorf("failed to setup HTTP router: %w", err)
 	}
@@ -128,6 +212,52 @@ func run() error {
 		slog.Info("Dumper started")
 	}
 
+	if cfg.TTL.CheckInterval > 0 {
+		go metricsJanitor.StartJanitor(ctx, cfg.TTL, cfg.History.Retention)
+		slog.Info("Janitor started")
+	}
+
+	if idempotencyService != nil && cfg.Idempotency.ExpireInterval > 0 {
+		go idempotencyService.StartExpiry(ctx, cfg.Idempotency.ExpireInterval)
+		slog.Info("Idempotency key expiry started")
+	}
+
+	if cfg.Alert.EvalInterval > 0 {
+		go alertEngine.StartEngine(ctx, cfg.Alert)
+		slog.Info("Alert engine started")
+	}
+
+	if cfg.StatsD.Address != "" || cfg.StatsD.Socket != "" {
+		statsdListener, err := statsd.NewListener(metricsService, cfg.StatsD)
+		if err != nil {
+			return fmt.Errorf("failed to create statsd listener: %w", err)
+		}
+
+		if err := statsdListener.StartListener(ctx, cfg.StatsD); err != nil {
+			return fmt.Errorf("failed to start statsd listener: %w", err)
+		}
+	}
+
+	if cfg.Graphite.Address != "" {
+		graphiteListener, err := graphite.NewListener(metricsService, cfg.Graphite)
+		if err != nil {
+			return fmt.Errorf("failed to create graphite listener: %w", err)
+		}
+
+		if err := graphiteListener.StartListener(ctx, cfg.Graphite); err != nil {
+			return fmt.Errorf("failed to start graphite listener: %w", err)
+		}
+	}
+
+	if cfg.GRPC.Address != "" {
+		grpcServer, err := setupGRPCServer(metricsService, cfg.GRPC, cfg.SignKey, cfg.Tenant.Keys)
+		if err != nil {
+			return fmt.Errorf("failed to setup gRPC server: %w", err)
+		}
+
+		go grpcServer.Run(ctx, stop)
+	}
+
 	go server.Run(ctx, stop)
 
 	<-ctx.Done()
@@ -142,23 +272,157 @@ func readDump(cfg config.Dump, dumper *dump.Dumper) {
 	}
 }
 
-func setupRouter(metricsRepository *repository.MetricsRepository, signKey string, auditor audit.Auditor) (http.Handler, error) {
+func setupRouter(
+	metricsService service.MetricsService,
+	metaRepository repository.MetaRepository,
+	alertRuleRepository repository.AlertRuleRepository,
+	idempotencyService service.IdempotencyService,
+	alertEngine *alert.Engine,
+	broker *stream.Broker,
+	cfg *config.Server,
+) (http.Handler, error) {
 
 	// Metrics
-	metricsService, err := service.NewMetricsService(*metricsRepository, auditor)
+	metricsHandler, err := handler.NewMetricsHandler(metricsService)
 
 	if err != nil {
 		return nil, err
 	}
 
-	metricsHandler, err := handler.NewMetricsHandler(metricsService)
+	// Meta
+	metaService, err := service.NewMetaService(metaRepository)
 
 	if err != nil {
 		return nil, err
 	}
 
+	metaHandler, err := handler.NewMetaHandler(metaService)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Alerts
+	alertService, err := service.NewAlertService(alertRuleRepository, alertEngine)
+
+	if err != nil {
+		return nil, err
+	}
+
+	alertHandler, err := handler.NewAlertHandler(alertService)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Stream
+	streamHandler, err := handler.NewStreamHandler(broker)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// OTLP
+	otlpHandler, err := handler.NewOTLPHandler(metricsService, otlp.NewTranslator(cfg.OTLP.TotalsTTL))
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Idempotency
+	var idempotencyHandler *handler.IdempotencyHandler
+	if idempotencyService != nil {
+		idempotencyHandler, err = handler.NewIdempotencyHandler(idempotencyService)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Encryption
+	var decryptor encrypt.Decryptor
+	privateKey, err := config.LoadPrivateKey(cfg.CryptoKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if privateKey != nil {
+		decryptor = encrypt.NewRSADecryptor(privateKey)
+	}
+
 	return handler.SetupRouter(&handler.RouterConfiguration{
-		MetricsHandler: metricsHandler,
-		SignKey:        signKey,
+		MetricsHandler:     metricsHandler,
+		MetaHandler:        metaHandler,
+		AlertHandler:       alertHandler,
+		StreamHandler:      streamHandler,
+		OTLPHandler:        otlpHandler,
+		IdempotencyHandler: idempotencyHandler,
+		Decryptor:          decryptor,
+		SignKey:            cfg.SignKey,
+		TenantKeys:         cfg.Tenant.Keys,
 	}), nil
 }
+
+func setupAlertEngine(ctx context.Context, metricsRepository repository.MetricsRepository, alertRuleRepository repository.AlertRuleRepository, cfg config.Alert) (*alert.Engine, error) {
+	notifier, err := alert.NewWebhookNotifier(httpclient.NewClient(
+		httpclient.Timeout(alert.WebhookTimeout),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	engine, err := alert.NewEngine(metricsRepository, alertRuleRepository, notifier, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.RulesFile != "" {
+		loaded, err := engine.LoadRules(ctx, cfg.RulesFile)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("Alert rules loaded", slog.Int("count", loaded), slog.String("file", cfg.RulesFile))
+	}
+
+	return engine, nil
+}
+
+func setupGRPCServer(metricsService service.MetricsService, cfg config.GRPC, signKey string, tenantKeys map[string]string) (*grpcserver.Server, error) {
+	var subnet *net.IPNet
+	if cfg.TrustedSubnet != "" {
+		_, parsed, err := net.ParseCIDR(cfg.TrustedSubnet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted subnet: %w", err)
+		}
+		subnet = parsed
+	}
+
+	metricsServer, err := rpc.NewMetricsServer(metricsService)
+
+	if err != nil {
+		return nil, err
+	}
+
+	signedMethods := []string{
+		pb.Metrics_UpdateMetrics_FullMethodName,
+		pb.Metrics_StreamMetrics_FullMethodName,
+	}
+
+	return grpcserver.New(
+		grpcserver.Address(cfg.Address),
+		grpcserver.Service(&pb.Metrics_ServiceDesc, metricsServer),
+		grpcserver.UnaryInterceptors(
+			interceptor.UnaryAuditContext(),
+			interceptor.UnaryTrustedSubnet(subnet),
+			interceptor.UnaryTenant(tenantKeys),
+			interceptor.UnarySignVerify(signKey, signedMethods...),
+		),
+		grpcserver.StreamInterceptors(
+			interceptor.StreamAuditContext(),
+			interceptor.StreamTrustedSubnet(subnet),
+			interceptor.StreamTenant(tenantKeys),
+			interceptor.StreamSignVerify(signKey, signedMethods...),
+		),
+	), nil
+}
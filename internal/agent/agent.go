@@ -6,6 +6,12 @@
 //   - Batch reporting (multiple metrics per HTTP request)
 //   - Rate-limited concurrent reporting
 //
+// With the WithGRPC option metrics are reported over gRPC instead:
+// batches with UpdateMetrics, individual metrics over a single StreamMetrics stream.
+//
+// With the WithEncryption option HTTP request bodies are encrypted
+// with the server public key after compression.
+//
 // Metrics collected include:
 //   - Go runtime statistics (memory allocation, GC, etc.)
 //   - System metrics (CPU utilization, memory usage)
@@ -28,11 +34,18 @@ import (
 	"compress/gzip"
 
 	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/pb"
+	"github.com/gabkaclassic/metrics/pkg/encrypt"
 	"github.com/gabkaclassic/metrics/pkg/hash"
 	"github.com/gabkaclassic/metrics/pkg/httpclient"
+	"github.com/gabkaclassic/metrics/pkg/interceptor"
 	"github.com/gabkaclassic/metrics/pkg/metric"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"github.com/google/uuid"
 	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/shirou/gopsutil/v4/mem"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
 )
 
 // Agent defines the interface for metrics collection agents.
@@ -63,8 +76,46 @@ type MetricsAgent struct {
 	jobCh          chan []metric.Metric
 	wg             sync.WaitGroup
 	batchSize      int
+	pollDuration   *metric.HistogramMetric
+	pollSummary    *metric.SummaryMetric
+	grpcClient     pb.MetricsClient
+	grpcTimeout    time.Duration
+	encryptor      encrypt.Encryptor
+}
+
+// Option configures optional agent features.
+type Option func(*MetricsAgent)
+
+// WithGRPC makes the agent report metrics over gRPC instead of HTTP.
+//
+// client: gRPC client connected to the server
+// timeout: Deadline of a single report call, zero for no deadline
+//
+// Batches are sent with UpdateMetrics, individual metrics
+// are streamed over a single StreamMetrics call.
+func WithGRPC(client pb.MetricsClient, timeout time.Duration) Option {
+	return func(agent *MetricsAgent) {
+		agent.grpcClient = client
+		agent.grpcTimeout = timeout
+	}
+}
+
+// WithEncryption makes the agent encrypt HTTP request bodies.
+//
+// encryptor: Encryptor sealing bodies with the server public key
+//
+// Bodies are encrypted after compression and signed as sent.
+// gRPC reports are not encrypted.
+func WithEncryption(encryptor encrypt.Encryptor) Option {
+	return func(agent *MetricsAgent) {
+		agent.encryptor = encryptor
+	}
 }
 
+// pollDurationBounds are the PollDuration histogram bucket bounds in seconds.
+// Polling includes a one second CPU sampling window, so buckets start there.
+var pollDurationBounds = []float64{1, 1.05, 1.1, 1.25, 1.5, 2, 5}
+
 // NewAgent creates and initializes a new metrics collection agent.
 //
 // client: HTTP client configured with server endpoint.
@@ -72,17 +123,18 @@ type MetricsAgent struct {
 // signKey: Secret key for request signature generation.
 // rateLimit: Maximum concurrent HTTP requests (0 for no limit).
 // batchSize: Maximum metrics per batch (ignored if batchesEnabled false).
+// options: Optional features such as the gRPC transport.
 //
 // Returns:
 //   - *MetricsAgent: Fully initialized agent ready for polling
 //   - error: If system metric collection fails during initialization
 //
 // The agent initializes with:
-//   - Default metrics (PollCount, RandomValue)
+//   - Default metrics (PollCount, RandomValue, PollDuration, PollDurationSummary)
 //   - Go runtime metrics
 //   - System metrics (CPU, memory)
 //   - Request signer for secure communication
-func NewAgent(client httpclient.HTTPClient, batchesEnabled bool, signKey string, rateLimit int, batchSize int) (*MetricsAgent, error) {
+func NewAgent(client httpclient.HTTPClient, batchesEnabled bool, signKey string, rateLimit int, batchSize int, options ...Option) (*MetricsAgent, error) {
 	metrics := []metric.Metric{
 		// Counters
 		&metric.PollCount{},
@@ -90,6 +142,16 @@ func NewAgent(client httpclient.HTTPClient, batchesEnabled bool, signKey string,
 		// Gauges
 		&metric.RandomValue{},
 	}
+	// Histograms
+	pollDuration := metric.NewHistogramMetric("PollDuration", pollDurationBounds)
+	metrics = append(metrics, pollDuration)
+
+	// Summaries
+	pollSummary, err := metric.NewSummaryMetric("PollDurationSummary", models.DefaultSketchAccuracy)
+	if err != nil {
+		return nil, err
+	}
+	metrics = append(metrics, pollSummary)
 
 	// Gauges runtime
 	stats := &runtime.MemStats{}
@@ -103,6 +165,8 @@ func NewAgent(client httpclient.HTTPClient, batchesEnabled bool, signKey string,
 		rateLimit:      rateLimit,
 		jobCh:          make(chan []metric.Metric, 1),
 		batchSize:      batchSize,
+		pollDuration:   pollDuration,
+		pollSummary:    pollSummary,
 	}
 	cpuStats, err := cpu.Percent(1*time.Second, false)
 
@@ -129,6 +193,10 @@ func NewAgent(client httpclient.HTTPClient, batchesEnabled bool, signKey string,
 	signer := hash.NewSHA256Signer(signKey)
 	agent.signer = signer
 
+	for _, option := range options {
+		option(agent)
+	}
+
 	return agent, nil
 }
 
@@ -140,8 +208,12 @@ func NewAgent(client httpclient.HTTPClient, batchesEnabled bool, signKey string,
 //   - CPU utilization (via gopsutil)
 //   - System memory (via gopsutil)
 //   - All registered custom metrics
+//
+// The duration of each poll is observed into the PollDuration histogram
+// and the PollDurationSummary summary.
 func (agent *MetricsAgent) Poll() {
 	slog.Debug("Start metrics polling")
+	start := time.Now()
 	runtime.ReadMemStats(agent.stats)
 
 	cpuStats, err := cpu.Percent(1*time.Second, false)
@@ -157,6 +229,14 @@ func (agent *MetricsAgent) Poll() {
 		metric.Update()
 	}
 
+	elapsed := time.Since(start).Seconds()
+	if agent.pollDuration != nil {
+		agent.pollDuration.Observe(elapsed)
+	}
+	if agent.pollSummary != nil {
+		agent.pollSummary.Observe(elapsed)
+	}
+
 	agent.mu.RLock()
 	metricCopy := make([]metric.Metric, len(agent.metrics))
 	copy(metricCopy, agent.metrics)
@@ -182,6 +262,9 @@ func (agent *MetricsAgent) Report() error {
 		if agent.batchesEnabled {
 			return agent.reportBatchWithLimit(metrics)
 		}
+		if agent.grpcClient != nil {
+			return agent.reportStream(metrics)
+		}
 		return agent.reportIndividual(metrics)
 	default:
 		slog.Debug("No metrics to report")
@@ -239,7 +322,7 @@ func (agent *MetricsAgent) reportWorker(wg *sync.WaitGroup, jobs <-chan metric.M
 	defer slog.Debug("Worker stop")
 
 	for m := range jobs {
-		metricModel, err := agent.prepareMetric(m)
+		metricModel, restore, err := agent.prepareMetric(m)
 		if err != nil {
 			slog.Error("Prepare metric error", slog.Any("metric", m), slog.String("error", err.Error()))
 			select {
@@ -251,6 +334,7 @@ func (agent *MetricsAgent) reportWorker(wg *sync.WaitGroup, jobs <-chan metric.M
 
 		raw, err := json.Marshal(metricModel)
 		if err != nil {
+			restore()
 			slog.Error("Marshal metric error", slog.Any("metric", m), slog.String("error", err.Error()))
 			select {
 			case errCh <- err:
@@ -261,6 +345,7 @@ func (agent *MetricsAgent) reportWorker(wg *sync.WaitGroup, jobs <-chan metric.M
 
 		buffer, err := agent.compressData(raw)
 		if err != nil {
+			restore()
 			slog.Error("Compress data error", slog.Any("metric", m), slog.String("error", err.Error()))
 			select {
 			case errCh <- err:
@@ -269,7 +354,8 @@ func (agent *MetricsAgent) reportWorker(wg *sync.WaitGroup, jobs <-chan metric.M
 			return
 		}
 
-		if err := agent.sendRequest("/update/", buffer); err != nil {
+		if _, err := agent.sendRequest("/update/", buffer); err != nil {
+			restore()
 			slog.Error("Send metric error", slog.Any("metric", m), slog.String("error", err.Error()))
 			select {
 			case errCh <- err:
@@ -335,47 +421,120 @@ func chunkMetrics(metrics []metric.Metric, size int) [][]metric.Metric {
 
 // reportBatch sends a single batch of metrics in one HTTP request.
 // metrics: Metrics to include in this batch.
-// Returns error if any step fails (preparation, marshaling, sending).
+// Batches are sent in partial mode: the server saves valid metrics
+// and reports rejected ones, which are logged. Rejected metrics are not
+// retried, since resending them can't make them valid.
+// Returns error if any step fails (preparation, marshaling, sending)
+// or the server rejected any metric.
+// Drained observations are restored unless the batch reached the server.
 func (agent *MetricsAgent) reportBatch(metrics []metric.Metric) error {
 	var metricModels []models.Metrics
+	var restores []restoreFunc
 	for _, m := range metrics {
-		metricModel, err := agent.prepareMetric(m)
+		metricModel, restore, err := agent.prepareMetric(m)
 		if err != nil {
+			restoreAll(restores)
 			return fmt.Errorf("prepare metric %s error: %w", m.Name(), err)
 		}
 		metricModels = append(metricModels, *metricModel)
+		restores = append(restores, restore)
+	}
+
+	if agent.grpcClient != nil {
+		if err := agent.sendBatchGRPC(metricModels); err != nil {
+			restoreAll(restores)
+			return fmt.Errorf("send metrics batch error: %w", err)
+		}
+		slog.Info("Metrics batch sent successfully", slog.Int("count", len(metrics)))
+		return nil
 	}
 
 	raw, err := json.Marshal(metricModels)
 	if err != nil {
+		restoreAll(restores)
 		return fmt.Errorf("marshal metrics batch error: %w", err)
 	}
 
 	buffer, err := agent.compressData(raw)
 	if err != nil {
+		restoreAll(restores)
 		return fmt.Errorf("compress batch data error: %w", err)
 	}
 
-	if err := agent.sendRequest("/updates/", buffer); err != nil {
+	response, err := agent.sendRequest("/updates/?partial=true", buffer)
+	if err != nil {
+		restoreAll(restores)
 		return fmt.Errorf("send metrics batch error: %w", err)
 	}
 
+	var result models.BatchResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return fmt.Errorf("decode batch result error: %w", err)
+	}
+
+	if result.Rejected > 0 {
+		for _, rejected := range result.Errors {
+			if rejected.Index < 0 || rejected.Index >= len(metricModels) {
+				continue
+			}
+			slog.Error("Metric rejected by server",
+				slog.String("metric", metricModels[rejected.Index].ID),
+				slog.String("error", rejected.Error),
+			)
+		}
+		return fmt.Errorf("server rejected %d of %d metrics", result.Rejected, len(metricModels))
+	}
+
 	slog.Info("Metrics batch sent successfully", slog.Int("count", len(metrics)))
 	return nil
 }
 
 // prepareMetric converts a metric.Metric to models.Metrics for transmission.
 // m: Source metric with current value.
-// Returns JSON-serializable model with appropriate value fields set.
+// Returns JSON-serializable model with appropriate value fields set
+// and the unit declared by the metric, if any.
+// Drainable metrics are drained, so that only observations made since
+// the previous report are sent and merged by the server; the returned
+// restore function merges the drained observations back if the report fails.
 // Returns error for unknown metric types or value conversion failures.
-func (agent *MetricsAgent) prepareMetric(m metric.Metric) (*models.Metrics, error) {
+func (agent *MetricsAgent) prepareMetric(m metric.Metric) (*models.Metrics, restoreFunc, error) {
+	var metricRawValue any
+	restore := func() {}
+	if drainable, ok := m.(metric.Drainable); ok {
+		metricRawValue = drainable.Drain()
+		restore = func() { drainable.Restore(metricRawValue) }
+	} else {
+		metricRawValue = m.Value()
+	}
+
+	metricModel, err := toModel(m, metricRawValue)
+	if err != nil {
+		restore()
+		return nil, nil, err
+	}
+
+	return metricModel, restore, nil
+}
+
+// restoreFunc merges drained observations of a metric back into it.
+type restoreFunc func()
+
+// restoreAll merges drained observations of all metrics of a failed report back.
+func restoreAll(restores []restoreFunc) {
+	for _, restore := range restores {
+		restore()
+	}
+}
+
+// toModel converts a metric value to the transmitted model.
+func toModel(m metric.Metric, metricRawValue any) (*models.Metrics, error) {
 	metricName := m.Name()
 	metricType := m.Type()
-	metricRawValue := m.Value()
 
 	metricModel := &models.Metrics{
 		ID:    metricName,
 		MType: string(metricType),
+		Unit:  metric.UnitOf(m),
 	}
 
 	switch metricType {
@@ -391,6 +550,21 @@ func (agent *MetricsAgent) prepareMetric(m metric.Metric) (*models.Metrics, erro
 			return nil, fmt.Errorf("invalid value: %v", metricRawValue)
 		}
 		metricModel.Value = &value
+	case models.Histogram:
+		histogram, ok := metricRawValue.(metric.HistogramValue)
+		if !ok {
+			return nil, fmt.Errorf("invalid histogram value: %v", metricRawValue)
+		}
+		metricModel.Bounds = histogram.Bounds
+		metricModel.Buckets = histogram.Buckets
+		metricModel.Sum = &histogram.Sum
+		metricModel.Count = &histogram.Count
+	case models.Summary, models.Set:
+		encoded, ok := metricRawValue.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s value: %v", metricType, metricRawValue)
+		}
+		metricModel.Sketch = encoded
 	default:
 		return nil, fmt.Errorf("unknown metric type: %s", metricType)
 	}
@@ -421,26 +595,41 @@ func (agent *MetricsAgent) compressData(data []byte) (*bytes.Buffer, error) {
 // sendRequest sends an HTTP POST request with compressed, signed data.
 // endpoint: Server endpoint path (e.g., "/update/" or "/updates/").
 // body: Compressed request body.
-// Returns error if request fails or server returns non-200 status.
-// Automatically adds required headers: Content-Type, Content-Encoding, Hash.
-func (agent *MetricsAgent) sendRequest(endpoint string, body *bytes.Buffer) error {
+// Returns the response body, or error if request fails or server returns non-200 status.
+// Automatically adds required headers: Content-Type, Content-Encoding, Hash, Idempotency-Key.
+// The idempotency key is shared by all retries of the request,
+// so the server applies the data once even if a response gets lost.
+// With encryption enabled the body is encrypted before signing
+// and marked with the X-Encryption header.
+func (agent *MetricsAgent) sendRequest(endpoint string, body *bytes.Buffer) ([]byte, error) {
+
+	headers := httpclient.Headers{
+		"Content-Type":              "application/json",
+		"Content-Encoding":          "gzip",
+		models.IdempotencyKeyHeader: uuid.NewString(),
+	}
+
+	if agent.encryptor != nil {
+		encrypted, err := agent.encryptor.Encrypt(body.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("encrypt request error: %w", err)
+		}
+		body = bytes.NewBuffer(encrypted)
+		headers[middleware.EncryptionHeader] = encrypt.Scheme
+	}
 
-	sign := agent.signer.Sign(body.Bytes())
+	headers["Hash"] = agent.signer.Sign(body.Bytes())
 
 	resp, err := agent.client.Post(
 		endpoint,
 		&httpclient.RequestOptions{
-			Body: body,
-			Headers: &httpclient.Headers{
-				"Content-Type":     "application/json",
-				"Content-Encoding": "gzip",
-				"Hash":             sign,
-			},
+			Body:    body,
+			Headers: &headers,
 		},
 	)
 
 	if err != nil {
-		return fmt.Errorf("send request error: %w", err)
+		return nil, fmt.Errorf("send request error: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -448,16 +637,16 @@ func (agent *MetricsAgent) sendRequest(endpoint string, body *bytes.Buffer) erro
 		responseBody, err := io.ReadAll(resp.Body)
 
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseBody))
+		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseBody))
 	}
 
 	responseBody, err := io.ReadAll(resp.Body)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	slog.Info("Request completed successfully",
@@ -465,9 +654,100 @@ func (agent *MetricsAgent) sendRequest(endpoint string, body *bytes.Buffer) erro
 		slog.String("response", string(responseBody)),
 	)
 
+	return responseBody, nil
+}
+
+// reportStream sends metrics one by one over a single gRPC stream.
+// metrics: Metrics to stream.
+// Returns error if any step fails (preparation, signing, sending).
+// Drained observations are restored if the stream fails.
+func (agent *MetricsAgent) reportStream(metrics []metric.Metric) error {
+	messages := make([]*pb.Metric, 0, len(metrics))
+	signed := make([]proto.Message, 0, len(metrics))
+	restores := make([]restoreFunc, 0, len(metrics))
+	for _, m := range metrics {
+		metricModel, restore, err := agent.prepareMetric(m)
+		if err != nil {
+			restoreAll(restores)
+			return fmt.Errorf("prepare metric %s error: %w", m.Name(), err)
+		}
+		message := pb.FromModel(*metricModel)
+		messages = append(messages, message)
+		signed = append(signed, message)
+		restores = append(restores, restore)
+	}
+
+	ctx, cancel, err := agent.grpcContext(signed...)
+	if err != nil {
+		restoreAll(restores)
+		return fmt.Errorf("sign metrics stream error: %w", err)
+	}
+	defer cancel()
+
+	stream, err := agent.grpcClient.StreamMetrics(ctx)
+	if err != nil {
+		restoreAll(restores)
+		return fmt.Errorf("open metrics stream error: %w", err)
+	}
+
+	for _, message := range messages {
+		if err := stream.Send(message); err != nil {
+			restoreAll(restores)
+			return fmt.Errorf("send metric %s error: %w", message.GetId(), err)
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		restoreAll(restores)
+		return fmt.Errorf("close metrics stream error: %w", err)
+	}
+
+	slog.Info("Metrics stream sent successfully", slog.Int64("count", resp.GetReceived()))
 	return nil
 }
 
+// sendBatchGRPC sends a batch of metrics with a signed UpdateMetrics call.
+// metricModels: Prepared metrics of the batch.
+// Returns error if signing or the call fails.
+func (agent *MetricsAgent) sendBatchGRPC(metricModels []models.Metrics) error {
+	req := &pb.UpdateMetricsRequest{Metrics: make([]*pb.Metric, 0, len(metricModels))}
+	for _, metricModel := range metricModels {
+		req.Metrics = append(req.Metrics, pb.FromModel(metricModel))
+	}
+
+	ctx, cancel, err := agent.grpcContext(req)
+	if err != nil {
+		return fmt.Errorf("sign metrics batch error: %w", err)
+	}
+	defer cancel()
+
+	if _, err := agent.grpcClient.UpdateMetrics(ctx, req); err != nil {
+		return fmt.Errorf("update metrics call error: %w", err)
+	}
+
+	return nil
+}
+
+// grpcContext returns a call context carrying the signature of messages
+// and the configured deadline.
+// Returns error if messages can't be marshaled for signing.
+func (agent *MetricsAgent) grpcContext(messages ...proto.Message) (context.Context, context.CancelFunc, error) {
+	payload, err := interceptor.SignPayload(messages...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), interceptor.HashKey, agent.signer.Sign(payload))
+
+	if agent.grpcTimeout > 0 {
+		ctx, cancel := context.WithTimeout(ctx, agent.grpcTimeout)
+		return ctx, cancel, nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return ctx, cancel, nil
+}
+
 // dispatchReports routes metrics to appropriate reporting method.
 // Internal method used by StartReporting for consistent dispatch logic.
 // metrics: Collected metrics to report.
@@ -477,6 +757,10 @@ func (agent *MetricsAgent) dispatchReports(metrics []metric.Metric) error {
 		return agent.reportBatch(metrics)
 	}
 
+	if agent.grpcClient != nil {
+		return agent.reportStream(metrics)
+	}
+
 	jobs := make(chan metric.Metric)
 	errCh := make(chan error, len(metrics))
 
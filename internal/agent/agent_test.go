@@ -3,8 +3,14 @@ package agent
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"runtime"
 	"strings"
@@ -12,11 +18,21 @@ import (
 	"testing"
 
 	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/pb"
+	"github.com/gabkaclassic/metrics/internal/repository"
+	"github.com/gabkaclassic/metrics/internal/storage"
+	"github.com/gabkaclassic/metrics/pkg/encrypt"
 	"github.com/gabkaclassic/metrics/pkg/hash"
 	"github.com/gabkaclassic/metrics/pkg/httpclient"
+	"github.com/gabkaclassic/metrics/pkg/interceptor"
 	"github.com/gabkaclassic/metrics/pkg/metric"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 func TestNewAgent(t *testing.T) {
@@ -123,7 +139,9 @@ func TestMetricsAgent_sendRequest(t *testing.T) {
 			body := bytes.NewBufferString("test body")
 
 			mockClient.EXPECT().
-				Post(endpoint, mock.AnythingOfType("*httpclient.RequestOptions")).
+				Post(endpoint, mock.MatchedBy(func(opts *httpclient.RequestOptions) bool {
+					return (*opts.Headers)[models.IdempotencyKeyHeader] != ""
+				})).
 				Return(tt.mockResponse, tt.mockError)
 
 			m := &MetricsAgent{
@@ -132,18 +150,224 @@ func TestMetricsAgent_sendRequest(t *testing.T) {
 				signer: hash.NewSHA256Signer(""),
 			}
 
-			err := m.sendRequest(endpoint, body)
+			response, err := m.sendRequest(endpoint, body)
 
 			if tt.expectedErrMsg != "" {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), tt.expectedErrMsg)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, `{"status":"ok"}`, string(response))
 			}
 		})
 	}
 }
 
+func TestMetricsAgent_sendRequest_encrypted(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, encrypt.MinKeyBits)
+	require.NoError(t, err)
+
+	mockClient := httpclient.NewMockHTTPClient(t)
+	signer := hash.NewSHA256Signer("secret")
+	endpoint := "http://example.com/updates/"
+
+	var sent []byte
+	mockClient.EXPECT().
+		Post(endpoint, mock.MatchedBy(func(opts *httpclient.RequestOptions) bool {
+			body, ok := opts.Body.(*bytes.Buffer)
+			if !ok {
+				return false
+			}
+			sent = body.Bytes()
+			headers := *opts.Headers
+			return headers[middleware.EncryptionHeader] == encrypt.Scheme &&
+				headers["Content-Encoding"] == "gzip" &&
+				headers["Hash"] == signer.Sign(sent)
+		})).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok"))}, nil)
+
+	m := &MetricsAgent{
+		client: mockClient,
+		mu:     &sync.RWMutex{},
+		signer: signer,
+	}
+	WithEncryption(encrypt.NewRSAEncryptor(&key.PublicKey))(m)
+
+	_, err = m.sendRequest(endpoint, bytes.NewBufferString("test body"))
+	require.NoError(t, err)
+
+	decrypted, err := encrypt.NewRSADecryptor(key).Decrypt(sent)
+	require.NoError(t, err)
+	assert.Equal(t, "test body", string(decrypted))
+}
+
+func TestMetricsAgent_reportBatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		response       string
+		expectedErrMsg string
+	}{
+		{
+			name:     "all accepted",
+			response: `{"accepted":2,"rejected":0}`,
+		},
+		{
+			name:           "rejected metrics",
+			response:       `{"accepted":1,"rejected":1,"errors":[{"index":1,"error":"gauge value or increment is required"}]}`,
+			expectedErrMsg: "server rejected 1 of 2 metrics",
+		},
+		{
+			name:           "invalid result",
+			response:       `ok`,
+			expectedErrMsg: "decode batch result error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := metric.NewMockMetric(t)
+			counter.EXPECT().Name().Return("PollCount")
+			counter.EXPECT().Type().Return(models.Counter)
+			counter.EXPECT().Value().Return(int64(5))
+
+			gauge := metric.NewMockMetric(t)
+			gauge.EXPECT().Name().Return("RandomValue")
+			gauge.EXPECT().Type().Return(models.Gauge)
+			gauge.EXPECT().Value().Return(0.5)
+
+			mockClient := httpclient.NewMockHTTPClient(t)
+			mockClient.EXPECT().
+				Post("/updates/?partial=true", mock.AnythingOfType("*httpclient.RequestOptions")).
+				Return(&http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(tt.response)),
+				}, nil)
+
+			agent := &MetricsAgent{
+				client: mockClient,
+				mu:     &sync.RWMutex{},
+				signer: hash.NewSHA256Signer(""),
+			}
+
+			err := agent.reportBatch([]metric.Metric{counter, gauge})
+
+			if tt.expectedErrMsg != "" {
+				assert.ErrorContains(t, err, tt.expectedErrMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// newMergingClient returns an HTTP client mock merging reported batches
+// into a memory repository, as the server does for histograms, summaries and sets.
+// newMergingClient returns a client merging posted batches into a memory repository,
+// the first failures posts are answered with 503 Service Unavailable.
+func newMergingClient(t *testing.T, failures int) (*httpclient.MockHTTPClient, repository.MetricsRepository) {
+	t.Helper()
+
+	repo, err := repository.NewMemoryMetricsRepository(storage.NewMemStorage(), &sync.RWMutex{})
+	require.NoError(t, err)
+
+	client := httpclient.NewMockHTTPClient(t)
+	if failures > 0 {
+		client.EXPECT().
+			Post("/updates/?partial=true", mock.Anything).
+			RunAndReturn(func(string, *httpclient.RequestOptions) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(strings.NewReader("unavailable"))}, nil
+			}).
+			Times(failures)
+	}
+	client.EXPECT().
+		Post("/updates/?partial=true", mock.Anything).
+		RunAndReturn(func(_ string, opts *httpclient.RequestOptions) (*http.Response, error) {
+			reader, err := gzip.NewReader(opts.Body)
+			require.NoError(t, err)
+
+			var metrics []models.Metrics
+			require.NoError(t, json.NewDecoder(reader).Decode(&metrics))
+			require.NoError(t, repo.MergeAll(context.Background(), metrics))
+
+			body := fmt.Sprintf(`{"accepted":%d,"rejected":0}`, len(metrics))
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+		})
+
+	return client, repo
+}
+
+func TestMetricsAgent_reportBatch_drainsObservations(t *testing.T) {
+	newHistogram := func(t *testing.T) (metric.Metric, func(float64)) {
+		histogram := metric.NewHistogramMetric("PollDuration", pollDurationBounds)
+		return histogram, histogram.Observe
+	}
+	histogramCount := func(t *testing.T, stored *models.Metrics) int64 {
+		var buckets int64
+		for _, bucket := range stored.Buckets {
+			buckets += bucket
+		}
+		assert.Equal(t, *stored.Count, buckets)
+		assert.InDelta(t, 13.52, *stored.Sum, 1e-9)
+		return *stored.Count
+	}
+
+	newSummary := func(t *testing.T) (metric.Metric, func(float64)) {
+		summary, err := metric.NewSummaryMetric("PollDurationSummary", models.DefaultSketchAccuracy)
+		require.NoError(t, err)
+		return summary, summary.Observe
+	}
+	summaryCount := func(t *testing.T, stored *models.Metrics) int64 {
+		snapshot := stored.SummarySnapshot()
+		assert.InDelta(t, 13.52, snapshot.Sum, 1e-9)
+		return snapshot.Count
+	}
+
+	tests := []struct {
+		name      string
+		newMetric func(t *testing.T) (metric.Metric, func(float64))
+		count     func(t *testing.T, stored *models.Metrics) int64
+		failures  int
+	}{
+		{name: "histogram", newMetric: newHistogram, count: histogramCount},
+		{name: "summary", newMetric: newSummary, count: summaryCount},
+		{name: "histogram restored after failed report", newMetric: newHistogram, count: histogramCount, failures: 1},
+		{name: "summary restored after failed report", newMetric: newSummary, count: summaryCount, failures: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reported, observe := tt.newMetric(t)
+			client, repo := newMergingClient(t, tt.failures)
+
+			a := &MetricsAgent{
+				client: client,
+				mu:     &sync.RWMutex{},
+				signer: hash.NewSHA256Signer(""),
+			}
+
+			for _, value := range []float64{1.02, 1.2, 3} {
+				observe(value)
+			}
+			err := a.reportBatch([]metric.Metric{reported})
+			if tt.failures > 0 {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			for _, value := range []float64{1.3, 7} {
+				observe(value)
+			}
+			require.NoError(t, a.reportBatch([]metric.Metric{reported}))
+
+			stored, err := repo.Get(context.Background(), reported.Name(), nil)
+			require.NoError(t, err)
+			require.NotNil(t, stored)
+			assert.Equal(t, int64(5), tt.count(t, stored))
+		})
+	}
+}
+
 func TestMetricsAgent_compressData(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -227,6 +451,71 @@ func TestMetricsAgent_prepareMetric(t *testing.T) {
 				Value: func() *float64 { v := 0.99; return &v }(),
 			},
 		},
+		{
+			name: "valid histogram metric",
+			setupMock: func(m *metric.MockMetric) {
+				m.EXPECT().Name().Return("poll_duration")
+				m.EXPECT().Type().Return(models.Histogram)
+				m.EXPECT().Value().Return(metric.HistogramValue{
+					Bounds:  []float64{1, 2},
+					Buckets: []int64{0, 1, 0},
+					Sum:     1.5,
+					Count:   1,
+				})
+			},
+			expectedMetric: &models.Metrics{
+				ID:      "poll_duration",
+				MType:   string(models.Histogram),
+				Bounds:  []float64{1, 2},
+				Buckets: []int64{0, 1, 0},
+				Sum:     func() *float64 { v := 1.5; return &v }(),
+				Count:   func() *int64 { v := int64(1); return &v }(),
+			},
+		},
+		{
+			name: "invalid histogram value type",
+			setupMock: func(m *metric.MockMetric) {
+				m.EXPECT().Name().Return("bad_histogram")
+				m.EXPECT().Type().Return(models.Histogram)
+				m.EXPECT().Value().Return(1.5)
+			},
+			expectedErrMsg: "invalid histogram value",
+		},
+		{
+			name: "valid summary metric",
+			setupMock: func(m *metric.MockMetric) {
+				m.EXPECT().Name().Return("poll_duration_summary")
+				m.EXPECT().Type().Return(models.Summary)
+				m.EXPECT().Value().Return([]byte{1, 2, 3})
+			},
+			expectedMetric: &models.Metrics{
+				ID:     "poll_duration_summary",
+				MType:  string(models.Summary),
+				Sketch: []byte{1, 2, 3},
+			},
+		},
+		{
+			name: "valid set metric",
+			setupMock: func(m *metric.MockMetric) {
+				m.EXPECT().Name().Return("unique_users")
+				m.EXPECT().Type().Return(models.Set)
+				m.EXPECT().Value().Return([]byte{4, 5, 6})
+			},
+			expectedMetric: &models.Metrics{
+				ID:     "unique_users",
+				MType:  string(models.Set),
+				Sketch: []byte{4, 5, 6},
+			},
+		},
+		{
+			name: "invalid summary value type",
+			setupMock: func(m *metric.MockMetric) {
+				m.EXPECT().Name().Return("bad_summary")
+				m.EXPECT().Type().Return(models.Summary)
+				m.EXPECT().Value().Return(1.5)
+			},
+			expectedErrMsg: "invalid summary value",
+		},
 		{
 			name: "invalid counter value type",
 			setupMock: func(m *metric.MockMetric) {
@@ -262,7 +551,7 @@ func TestMetricsAgent_prepareMetric(t *testing.T) {
 			tt.setupMock(mockMetric)
 
 			a := &MetricsAgent{}
-			result, err := a.prepareMetric(mockMetric)
+			result, _, err := a.prepareMetric(mockMetric)
 
 			if tt.expectedErrMsg != "" {
 				assert.Error(t, err)
@@ -276,6 +565,18 @@ func TestMetricsAgent_prepareMetric(t *testing.T) {
 	}
 }
 
+func TestMetricsAgent_prepareMetric_unit(t *testing.T) {
+	a := &MetricsAgent{}
+	gauge := metric.NewRuntimeGaugeMetric("HeapAlloc", func() float64 { return 1024 }).WithUnit(metric.UnitBytes)
+	gauge.Update()
+
+	result, _, err := a.prepareMetric(gauge)
+
+	assert.NoError(t, err)
+	assert.Equal(t, metric.UnitBytes, result.Unit)
+	assert.Equal(t, 1024.0, *result.Value)
+}
+
 func Test_chunkMetrics(t *testing.T) {
 	t.Parallel()
 
@@ -337,3 +638,143 @@ func Test_chunkMetrics(t *testing.T) {
 		})
 	}
 }
+
+// recordingServer records metrics received by the gRPC API.
+type recordingServer struct {
+	pb.UnimplementedMetricsServer
+
+	mu      sync.Mutex
+	batches [][]string
+	streams [][]string
+}
+
+func (s *recordingServer) UpdateMetrics(_ context.Context, req *pb.UpdateMetricsRequest) (*pb.UpdateMetricsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, metricIDs(req.GetMetrics()))
+	return &pb.UpdateMetricsResponse{Received: int64(len(req.GetMetrics()))}, nil
+}
+
+func (s *recordingServer) StreamMetrics(stream pb.Metrics_StreamMetricsServer) error {
+	var metrics []*pb.Metric
+	for {
+		metric, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		metrics = append(metrics, metric)
+	}
+
+	s.mu.Lock()
+	s.streams = append(s.streams, metricIDs(metrics))
+	s.mu.Unlock()
+
+	return stream.SendAndClose(&pb.UpdateMetricsResponse{Received: int64(len(metrics))})
+}
+
+// metricIDs returns IDs of metrics.
+func metricIDs(metrics []*pb.Metric) []string {
+	ids := make([]string, 0, len(metrics))
+	for _, metric := range metrics {
+		ids = append(ids, metric.GetId())
+	}
+	return ids
+}
+
+// newRecordingClient serves a recording server verifying signatures with serverKey.
+func newRecordingClient(t *testing.T, serverKey string) (pb.MetricsClient, *recordingServer) {
+	t.Helper()
+
+	recorder := &recordingServer{}
+	signed := []string{
+		pb.Metrics_UpdateMetrics_FullMethodName,
+		pb.Metrics_StreamMetrics_FullMethodName,
+	}
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(interceptor.UnarySignVerify(serverKey, signed...)),
+		grpc.StreamInterceptor(interceptor.StreamSignVerify(serverKey, signed...)),
+	)
+	pb.RegisterMetricsServer(server, recorder)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewMetricsClient(conn), recorder
+}
+
+func TestMetricsAgent_dispatchReports_grpc(t *testing.T) {
+	tests := []struct {
+		name           string
+		batchesEnabled bool
+		serverKey      string
+		wantBatches    [][]string
+		wantStreams    [][]string
+		wantErr        bool
+	}{
+		{
+			name:           "batch via UpdateMetrics",
+			batchesEnabled: true,
+			serverKey:      "secret",
+			wantBatches:    [][]string{{"HeapAlloc", "HeapSys"}},
+		},
+		{
+			name:        "individual via StreamMetrics",
+			serverKey:   "secret",
+			wantStreams: [][]string{{"HeapAlloc", "HeapSys"}},
+		},
+		{
+			name:           "batch with wrong key",
+			batchesEnabled: true,
+			serverKey:      "other",
+			wantErr:        true,
+		},
+		{
+			name:      "stream with wrong key",
+			serverKey: "other",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, recorder := newRecordingClient(t, tt.serverKey)
+
+			heapAlloc := metric.NewRuntimeGaugeMetric("HeapAlloc", func() float64 { return 1024 })
+			heapSys := metric.NewRuntimeGaugeMetric("HeapSys", func() float64 { return 2048 })
+			heapAlloc.Update()
+			heapSys.Update()
+
+			a := &MetricsAgent{
+				mu:             &sync.RWMutex{},
+				signer:         hash.NewSHA256Signer("secret"),
+				batchesEnabled: tt.batchesEnabled,
+				rateLimit:      1,
+			}
+			WithGRPC(client, 0)(a)
+
+			err := a.dispatchReports([]metric.Metric{heapAlloc, heapSys})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBatches, recorder.batches)
+			assert.Equal(t, tt.wantStreams, recorder.streams)
+		})
+	}
+}
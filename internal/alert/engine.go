@@ -0,0 +1,331 @@
+// Package alert evaluates threshold alert rules against stored metrics.
+//
+// Every rule is evaluated for each matching series of its tenant.
+// An alert becomes pending once its condition holds, fires when the
+// condition has held for the rule duration and is resolved when the
+// condition no longer holds. Webhooks are notified when an alert starts
+// firing, every repeat interval while it keeps firing and once when it
+// is resolved; pending alerts are never notified.
+package alert
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gabkaclassic/metrics/internal/config"
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/repository"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+)
+
+// Engine periodically evaluates alert rules and notifies webhooks.
+type Engine struct {
+	// repository stores the evaluated series.
+	repository repository.MetricsRepository
+
+	// rules stores alert rules of all tenants.
+	rules repository.AlertRuleRepository
+
+	// notifier delivers notifications to webhooks.
+	notifier Notifier
+
+	// webhooks are notified about alerts of rules without their own webhooks.
+	webhooks []string
+
+	// repeatInterval is the period notifications of firing alerts are repeated at.
+	repeatInterval time.Duration
+
+	// mu guards alerts and previous.
+	mu sync.Mutex
+
+	// alerts holds pending and firing alerts keyed by fingerprint.
+	alerts map[string]*tracked
+
+	// previous holds series values of the last evaluation keyed by fingerprint,
+	// compared by OpUnchanged rules.
+	previous map[string]float64
+
+	// now returns the current time, replaced in tests.
+	now func() time.Time
+}
+
+// tracked is an active alert with its notification state.
+type tracked struct {
+	alert      models.Alert
+	webhooks   []string
+	notifiedAt time.Time
+}
+
+// NewEngine creates a new alerting engine with required dependencies.
+//
+// repository: Metrics repository providing series of all tenants
+// rules: Alert rule repository
+// notifier: Webhook notification delivery
+// cfg: Alerting configuration with default webhooks and the repeat interval
+//
+// Returns:
+//   - *Engine: Initialized engine ready for operations
+//   - error: If repository, rules or notifier is nil or a default webhook is invalid
+func NewEngine(repository repository.MetricsRepository, rules repository.AlertRuleRepository, notifier Notifier, cfg config.Alert) (*Engine, error) {
+	if repository == nil {
+		return nil, errors.New("create alert engine error: repository can't be nil")
+	}
+
+	if rules == nil {
+		return nil, errors.New("create alert engine error: rule repository can't be nil")
+	}
+
+	if notifier == nil {
+		return nil, errors.New("create alert engine error: notifier can't be nil")
+	}
+
+	for _, webhook := range cfg.Webhooks {
+		if err := models.ValidateWebhook(webhook); err != nil {
+			return nil, fmt.Errorf("create alert engine error: %w", err)
+		}
+	}
+
+	return &Engine{
+		repository:     repository,
+		rules:          rules,
+		notifier:       notifier,
+		webhooks:       cfg.Webhooks,
+		repeatInterval: cfg.RepeatInterval,
+		alerts:         make(map[string]*tracked),
+		previous:       make(map[string]float64),
+		now:            time.Now,
+	}, nil
+}
+
+// LoadRules stores rules from a JSON file, replacing stored rules with the same IDs.
+// Rules without a tenant belong to the default tenant.
+//
+// Returns:
+//   - int: Number of loaded rules
+//   - error: Read, decode, validation or repository failure details
+func (e *Engine) LoadRules(ctx context.Context, path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("read alert rules: %w", err)
+	}
+
+	var rules []models.AlertRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return 0, fmt.Errorf("decode alert rules: %w", err)
+	}
+
+	tenants := make([]string, len(rules))
+	for i, rule := range rules {
+		if err := models.ValidateAlertRule(rule); err != nil {
+			return 0, fmt.Errorf("invalid alert rule %q: %w", rule.ID, err)
+		}
+
+		tenant, tenantErr := middleware.ResolveTenant(nil, "", rule.Tenant)
+		if tenantErr != nil {
+			return 0, fmt.Errorf("invalid alert rule %q: %w", rule.ID, tenantErr)
+		}
+		tenants[i] = tenant
+	}
+
+	for i, rule := range rules {
+		if err := e.rules.Put(middleware.WithTenant(ctx, tenants[i]), rule); err != nil {
+			return 0, fmt.Errorf("store alert rule %q: %w", rule.ID, err)
+		}
+	}
+
+	return len(rules), nil
+}
+
+// Evaluate evaluates all rules against series of their tenants,
+// advances alert states and notifies webhooks.
+// Notification failures are logged and don't fail the evaluation.
+//
+// Returns:
+//   - []models.Alert: Alerts notified by the evaluation
+//   - error: Rule or metric repository failure details
+func (e *Engine) Evaluate(ctx context.Context) ([]models.Alert, error) {
+	rules, err := e.rules.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get alert rules: %w", err)
+	}
+
+	var metrics []models.Metrics
+	if len(rules) > 0 {
+		metrics, err = e.repository.GetAllMetrics(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get metrics: %w", err)
+		}
+	}
+
+	e.mu.Lock()
+	notifications := e.advance(rules, metrics, e.now())
+	e.mu.Unlock()
+
+	notified := make([]models.Alert, 0)
+	for _, webhook := range slices.Sorted(maps.Keys(notifications)) {
+		alerts := notifications[webhook]
+		if err := e.notifier.Notify(ctx, webhook, models.AlertNotification{Alerts: alerts}); err != nil {
+			slog.Error("Alert notification error", slog.String("webhook", webhook), slog.String("error", err.Error()))
+			continue
+		}
+		notified = append(notified, alerts...)
+	}
+
+	return notified, nil
+}
+
+// advance moves alerts to their next states at now.
+// Must be called with the lock held.
+//
+// Returns alerts to notify grouped by webhook.
+func (e *Engine) advance(rules []models.AlertRule, metrics []models.Metrics, now time.Time) map[string][]models.Alert {
+	notifications := make(map[string][]models.Alert)
+	notify := func(entry *tracked) {
+		entry.notifiedAt = now
+		for _, webhook := range entry.webhooks {
+			notifications[webhook] = append(notifications[webhook], entry.alert)
+		}
+	}
+
+	active := make(map[string]struct{})
+	previous := make(map[string]float64)
+
+	for _, rule := range rules {
+		for _, metric := range metrics {
+			if metric.Tenant != rule.Tenant || !rule.Matches(metric) {
+				continue
+			}
+
+			value, ok := models.AlertValue(metric)
+			if !ok {
+				continue
+			}
+
+			fingerprint := models.AlertFingerprint(rule, metric)
+			holds := rule.Compare(value)
+			if rule.Op == models.OpUnchanged {
+				last, seen := e.previous[fingerprint]
+				holds = seen && last == value
+				previous[fingerprint] = value
+			}
+			if !holds {
+				continue
+			}
+			active[fingerprint] = struct{}{}
+
+			entry, exists := e.alerts[fingerprint]
+			if !exists {
+				entry = &tracked{alert: models.Alert{
+					Rule:        rule.ID,
+					Tenant:      rule.Tenant,
+					ID:          metric.ID,
+					MType:       metric.MType,
+					Labels:      metric.Labels,
+					State:       models.AlertPending,
+					ActiveAt:    now,
+					Fingerprint: fingerprint,
+				}}
+				e.alerts[fingerprint] = entry
+			}
+
+			entry.alert.Op = rule.Op
+			entry.alert.Threshold = rule.Threshold
+			entry.alert.Value = value
+			entry.webhooks = rule.Webhooks
+			if len(entry.webhooks) == 0 {
+				entry.webhooks = e.webhooks
+			}
+
+			switch {
+			case entry.alert.State == models.AlertPending && now.Sub(entry.alert.ActiveAt) >= rule.Duration():
+				firedAt := now
+				entry.alert.State = models.AlertFiring
+				entry.alert.FiredAt = &firedAt
+				notify(entry)
+			case entry.alert.State == models.AlertFiring && e.repeatInterval > 0 && now.Sub(entry.notifiedAt) >= e.repeatInterval:
+				notify(entry)
+			}
+		}
+	}
+
+	for fingerprint, entry := range e.alerts {
+		if _, exists := active[fingerprint]; exists {
+			continue
+		}
+
+		delete(e.alerts, fingerprint)
+		if entry.alert.State == models.AlertFiring {
+			resolvedAt := now
+			entry.alert.State = models.AlertResolved
+			entry.alert.ResolvedAt = &resolvedAt
+			notify(entry)
+		}
+	}
+
+	e.previous = previous
+
+	return notifications
+}
+
+// Alerts returns pending and firing alerts of the tenant
+// ordered by rule, metric ID and series key.
+func (e *Engine) Alerts(tenant string) []models.Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	alerts := make([]models.Alert, 0)
+	for _, entry := range e.alerts {
+		if entry.alert.Tenant == tenant {
+			alerts = append(alerts, entry.alert)
+		}
+	}
+
+	slices.SortFunc(alerts, func(a, b models.Alert) int {
+		return cmp.Or(
+			cmp.Compare(a.Rule, b.Rule),
+			cmp.Compare(a.ID, b.ID),
+			cmp.Compare(models.SeriesKey(a.ID, a.Labels), models.SeriesKey(b.ID, b.Labels)),
+		)
+	})
+
+	return alerts
+}
+
+// StartEngine initiates periodic rule evaluation based on configuration.
+// Runs until context cancellation.
+//
+// ctx: Context for graceful shutdown (cancellation stops the engine)
+// cfg: Alerting configuration containing the evaluation interval
+//
+// The engine:
+//   - Runs on every evaluation interval tick
+//   - Logs errors but continues on evaluation failures
+//   - Stops gracefully on context cancellation
+func (e *Engine) StartEngine(ctx context.Context, cfg config.Alert) {
+	ticker := time.NewTicker(cfg.EvalInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			notified, err := e.Evaluate(ctx)
+			if err != nil {
+				slog.Error("Evaluate alert rules error", slog.String("error", err.Error()))
+			} else if len(notified) > 0 {
+				slog.Info("Alert notifications sent", slog.Int("count", len(notified)))
+			}
+		case <-ctx.Done():
+			slog.Info("Alert engine stopped")
+			return
+		}
+	}
+}
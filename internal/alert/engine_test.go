@@ -0,0 +1,358 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gabkaclassic/metrics/internal/config"
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/repository"
+	"github.com/gabkaclassic/metrics/internal/storage"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+)
+
+func gauge(tenant, id string, value float64) models.Metrics {
+	return models.Metrics{Tenant: tenant, ID: id, MType: models.Gauge, Value: &value}
+}
+
+// newTestEngine creates an engine over in-memory rules and a mocked metrics
+// repository returning *metrics on every evaluation.
+func newTestEngine(t *testing.T, metrics *[]models.Metrics, notifier Notifier, cfg config.Alert) (*Engine, repository.AlertRuleRepository) {
+	t.Helper()
+
+	metricsRepository := repository.NewMockMetricsRepository(t)
+	metricsRepository.EXPECT().GetAllMetrics(mock.Anything).
+		RunAndReturn(func(context.Context) ([]models.Metrics, error) {
+			return *metrics, nil
+		}).Maybe()
+
+	rules, err := repository.NewMemoryAlertRuleRepository(storage.NewMemStorage(), &sync.RWMutex{})
+	require.NoError(t, err)
+
+	engine, err := NewEngine(metricsRepository, rules, notifier, cfg)
+	require.NoError(t, err)
+
+	return engine, rules
+}
+
+func TestNewEngine(t *testing.T) {
+	metricsRepository := repository.NewMockMetricsRepository(t)
+	rules := repository.NewMockAlertRuleRepository(t)
+	notifier := NewMockNotifier(t)
+
+	tests := []struct {
+		name        string
+		repository  repository.MetricsRepository
+		rules       repository.AlertRuleRepository
+		notifier    Notifier
+		cfg         config.Alert
+		expectError bool
+	}{
+		{
+			name:       "valid dependencies",
+			repository: metricsRepository,
+			rules:      rules,
+			notifier:   notifier,
+			cfg:        config.Alert{Webhooks: []string{"https://hooks.example.com/alerts"}},
+		},
+		{
+			name:        "nil repository",
+			rules:       rules,
+			notifier:    notifier,
+			expectError: true,
+		},
+		{
+			name:        "nil rules",
+			repository:  metricsRepository,
+			notifier:    notifier,
+			expectError: true,
+		},
+		{
+			name:        "nil notifier",
+			repository:  metricsRepository,
+			rules:       rules,
+			expectError: true,
+		},
+		{
+			name:        "invalid webhook",
+			repository:  metricsRepository,
+			rules:       rules,
+			notifier:    notifier,
+			cfg:         config.Alert{Webhooks: []string{"hooks.example.com"}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := NewEngine(tt.repository, tt.rules, tt.notifier, tt.cfg)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, engine)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, engine)
+			}
+		})
+	}
+}
+
+func TestEngine_Evaluate_Lifecycle(t *testing.T) {
+	const webhook = "http://hooks.example.com/alerts"
+
+	metrics := []models.Metrics{gauge(middleware.DefaultTenant, "HeapAlloc", 150)}
+	notifier := NewMockNotifier(t)
+	engine, rules := newTestEngine(t, &metrics, notifier, config.Alert{
+		Webhooks:       []string{webhook},
+		RepeatInterval: 10 * time.Minute,
+	})
+
+	require.NoError(t, rules.Put(t.Context(), models.AlertRule{
+		ID: "high-heap", Metric: "Heap*", Op: models.OpGreater, Threshold: 100, For: 60,
+	}))
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	engine.now = func() time.Time { return now }
+
+	var sent []models.AlertNotification
+	notifier.EXPECT().Notify(mock.Anything, webhook, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ string, notification models.AlertNotification) error {
+			sent = append(sent, notification)
+			return nil
+		})
+
+	// Condition holds, but not for the rule duration yet.
+	notified, err := engine.Evaluate(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, notified)
+
+	alerts := engine.Alerts(middleware.DefaultTenant)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, models.AlertPending, alerts[0].State)
+	assert.Equal(t, start, alerts[0].ActiveAt)
+
+	// Held for the rule duration.
+	now = start.Add(time.Minute)
+	notified, err = engine.Evaluate(t.Context())
+	require.NoError(t, err)
+	require.Len(t, notified, 1)
+	assert.Equal(t, models.AlertFiring, notified[0].State)
+	assert.Equal(t, 150.0, notified[0].Value)
+	require.NotNil(t, notified[0].FiredAt)
+	assert.Equal(t, now, *notified[0].FiredAt)
+
+	// Still firing within the repeat interval.
+	now = start.Add(5 * time.Minute)
+	notified, err = engine.Evaluate(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, notified)
+
+	// Repeat interval elapsed.
+	now = start.Add(11 * time.Minute)
+	notified, err = engine.Evaluate(t.Context())
+	require.NoError(t, err)
+	require.Len(t, notified, 1)
+	assert.Equal(t, models.AlertFiring, notified[0].State)
+
+	// Condition no longer holds.
+	metrics = []models.Metrics{gauge(middleware.DefaultTenant, "HeapAlloc", 50)}
+	now = start.Add(12 * time.Minute)
+	notified, err = engine.Evaluate(t.Context())
+	require.NoError(t, err)
+	require.Len(t, notified, 1)
+	assert.Equal(t, models.AlertResolved, notified[0].State)
+	require.NotNil(t, notified[0].ResolvedAt)
+	assert.Equal(t, now, *notified[0].ResolvedAt)
+
+	assert.Empty(t, engine.Alerts(middleware.DefaultTenant))
+	assert.Len(t, sent, 3)
+}
+
+func TestEngine_Evaluate_PendingNotNotified(t *testing.T) {
+	metrics := []models.Metrics{gauge(middleware.DefaultTenant, "HeapAlloc", 150)}
+	notifier := NewMockNotifier(t)
+	engine, rules := newTestEngine(t, &metrics, notifier, config.Alert{
+		Webhooks: []string{"http://hooks.example.com/alerts"},
+	})
+
+	require.NoError(t, rules.Put(t.Context(), models.AlertRule{
+		ID: "high-heap", Metric: "HeapAlloc", Op: models.OpGreater, Threshold: 100, For: 300,
+	}))
+
+	notified, err := engine.Evaluate(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, notified)
+
+	metrics = []models.Metrics{gauge(middleware.DefaultTenant, "HeapAlloc", 50)}
+	notified, err = engine.Evaluate(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, notified)
+	assert.Empty(t, engine.Alerts(middleware.DefaultTenant))
+}
+
+func TestEngine_Evaluate_Unchanged(t *testing.T) {
+	metrics := []models.Metrics{gauge(middleware.DefaultTenant, "PollCount", 1)}
+	notifier := NewMockNotifier(t)
+	engine, rules := newTestEngine(t, &metrics, notifier, config.Alert{})
+
+	require.NoError(t, rules.Put(t.Context(), models.AlertRule{
+		ID: "stale", Metric: "PollCount", Op: models.OpUnchanged,
+	}))
+
+	// No previous value to compare with.
+	_, err := engine.Evaluate(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, engine.Alerts(middleware.DefaultTenant))
+
+	_, err = engine.Evaluate(t.Context())
+	require.NoError(t, err)
+	alerts := engine.Alerts(middleware.DefaultTenant)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, models.AlertFiring, alerts[0].State)
+
+	metrics = []models.Metrics{gauge(middleware.DefaultTenant, "PollCount", 2)}
+	_, err = engine.Evaluate(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, engine.Alerts(middleware.DefaultTenant))
+}
+
+func TestEngine_Evaluate_Tenants(t *testing.T) {
+	metrics := []models.Metrics{
+		gauge("team-a", "HeapAlloc", 150),
+		gauge("team-b", "HeapAlloc", 150),
+	}
+	notifier := NewMockNotifier(t)
+	engine, rules := newTestEngine(t, &metrics, notifier, config.Alert{})
+
+	require.NoError(t, rules.Put(middleware.WithTenant(t.Context(), "team-a"), models.AlertRule{
+		ID: "high-heap", Metric: "HeapAlloc", Op: models.OpGreater, Threshold: 100,
+		Webhooks: []string{"http://team-a.example.com"},
+	}))
+
+	notifier.EXPECT().Notify(mock.Anything, "http://team-a.example.com", mock.Anything).Return(nil).Once()
+
+	notified, err := engine.Evaluate(t.Context())
+	require.NoError(t, err)
+	require.Len(t, notified, 1)
+	assert.Equal(t, "team-a", notified[0].Tenant)
+
+	assert.Len(t, engine.Alerts("team-a"), 1)
+	assert.Empty(t, engine.Alerts("team-b"))
+}
+
+func TestEngine_Evaluate_NotifyError(t *testing.T) {
+	metrics := []models.Metrics{gauge(middleware.DefaultTenant, "HeapAlloc", 150)}
+	notifier := NewMockNotifier(t)
+	engine, rules := newTestEngine(t, &metrics, notifier, config.Alert{
+		Webhooks: []string{"http://a.example.com", "http://b.example.com"},
+	})
+
+	require.NoError(t, rules.Put(t.Context(), models.AlertRule{
+		ID: "high-heap", Metric: "HeapAlloc", Op: models.OpGreater, Threshold: 100,
+	}))
+
+	notifier.EXPECT().Notify(mock.Anything, "http://a.example.com", mock.Anything).Return(errors.New("unavailable")).Once()
+	notifier.EXPECT().Notify(mock.Anything, "http://b.example.com", mock.Anything).Return(nil).Once()
+
+	notified, err := engine.Evaluate(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, notified, 1)
+	assert.Len(t, engine.Alerts(middleware.DefaultTenant), 1)
+}
+
+func TestEngine_Evaluate_RepositoryError(t *testing.T) {
+	metricsRepository := repository.NewMockMetricsRepository(t)
+	rules := repository.NewMockAlertRuleRepository(t)
+	rules.EXPECT().GetAll(mock.Anything).Return(nil, errors.New("db error"))
+
+	engine, err := NewEngine(metricsRepository, rules, NewMockNotifier(t), config.Alert{})
+	require.NoError(t, err)
+
+	notified, err := engine.Evaluate(t.Context())
+	assert.Error(t, err)
+	assert.Nil(t, notified)
+}
+
+func TestEngine_LoadRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expectCount int
+		expectError bool
+	}{
+		{
+			name: "valid rules",
+			content: `[
+				{"id": "high-heap", "metric": "HeapAlloc", "op": ">", "threshold": 100},
+				{"id": "stale", "tenant": "team-a", "metric": "PollCount", "op": "unchanged", "for": 60}
+			]`,
+			expectCount: 2,
+		},
+		{
+			name:        "invalid json",
+			content:     `{`,
+			expectError: true,
+		},
+		{
+			name:        "invalid rule",
+			content:     `[{"id": "high-heap", "metric": "HeapAlloc", "op": "~"}]`,
+			expectError: true,
+		},
+		{
+			name:        "invalid tenant",
+			content:     `[{"id": "high-heap", "tenant": "team a", "metric": "HeapAlloc", "op": ">"}]`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rules.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			var metrics []models.Metrics
+			engine, rules := newTestEngine(t, &metrics, NewMockNotifier(t), config.Alert{})
+
+			count, err := engine.LoadRules(t.Context(), path)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				stored, _ := rules.GetAll(t.Context())
+				assert.Empty(t, stored)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectCount, count)
+
+			rule, err := rules.Get(middleware.WithTenant(t.Context(), "team-a"), "stale")
+			require.NoError(t, err)
+			require.NotNil(t, rule)
+			assert.Equal(t, models.OpUnchanged, rule.Op)
+
+			rule, err = rules.Get(t.Context(), "high-heap")
+			require.NoError(t, err)
+			require.NotNil(t, rule)
+			assert.Equal(t, middleware.DefaultTenant, rule.Tenant)
+		})
+	}
+}
+
+func TestEngine_LoadRules_MissingFile(t *testing.T) {
+	var metrics []models.Metrics
+	engine, _ := newTestEngine(t, &metrics, NewMockNotifier(t), config.Alert{})
+
+	_, err := engine.LoadRules(t.Context(), filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/pkg/httpclient"
+)
+
+// WebhookTimeout bounds a single webhook delivery attempt.
+const WebhookTimeout = 10 * time.Second
+
+// Notifier delivers alert notifications to webhooks.
+type Notifier interface {
+	// Notify sends the notification to the webhook URL.
+	Notify(ctx context.Context, webhook string, notification models.AlertNotification) error
+}
+
+// webhookNotifier posts notifications as JSON through an HTTP client.
+type webhookNotifier struct {
+	client httpclient.HTTPClient
+}
+
+// NewWebhookNotifier creates a notifier posting JSON notifications.
+//
+// client: HTTP client without a base URL, retrying failed deliveries
+//
+// Returns:
+//   - Notifier: Ready-to-use notifier
+//   - error: If client is nil
+func NewWebhookNotifier(client httpclient.HTTPClient) (Notifier, error) {
+	if client == nil {
+		return nil, errors.New("create webhook notifier error: client can't be nil")
+	}
+
+	return &webhookNotifier{
+		client: client,
+	}, nil
+}
+
+// Notify posts the notification to the webhook.
+// Responses other than 2xx are treated as errors.
+func (n *webhookNotifier) Notify(ctx context.Context, webhook string, notification models.AlertNotification) error {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("marshal alert notification: %w", err)
+	}
+
+	resp, err := n.client.Post(webhook, &httpclient.RequestOptions{
+		Headers: &httpclient.Headers{"Content-Type": "application/json"},
+		Body:    bytes.NewReader(data),
+	})
+	if err != nil {
+		return fmt.Errorf("send alert notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected webhook response status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
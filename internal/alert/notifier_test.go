@@ -0,0 +1,93 @@
+package alert
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/pkg/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebhookNotifier(t *testing.T) {
+	tests := []struct {
+		name        string
+		client      httpclient.HTTPClient
+		expectError bool
+	}{
+		{
+			name:   "valid client",
+			client: httpclient.NewClient(),
+		},
+		{
+			name:        "nil client",
+			client:      nil,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier, err := NewWebhookNotifier(tt.client)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, notifier)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, notifier)
+			}
+		})
+	}
+}
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	notification := models.AlertNotification{Alerts: []models.Alert{
+		{Rule: "high-heap", ID: "HeapAlloc", MType: models.Gauge, State: models.AlertFiring, Value: 150},
+	}}
+
+	tests := []struct {
+		name        string
+		status      int
+		expectError bool
+	}{
+		{
+			name:   "accepted",
+			status: http.StatusNoContent,
+		},
+		{
+			name:        "rejected",
+			status:      http.StatusBadRequest,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received models.AlertNotification
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			notifier, err := NewWebhookNotifier(httpclient.NewClient())
+			require.NoError(t, err)
+
+			err = notifier.Notify(t.Context(), server.URL+"/hook", notification)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, notification.Alerts[0].Rule, received.Alerts[0].Rule)
+			assert.Equal(t, notification.Alerts[0].Value, received.Alerts[0].Value)
+		})
+	}
+}
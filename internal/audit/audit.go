@@ -7,8 +7,10 @@
 //
 // Audit events include:
 //   - Timestamp of the operation
+//   - Action performed: update, delete or expire
 //   - List of metric IDs involved
 //   - Source IP address of the request
+//   - Tenant owning the metrics
 //
 // The system supports multiple concurrent handlers and ensures thread-safe
 // operations where required (e.g., file writing).
@@ -29,6 +31,13 @@ import (
 	"github.com/gabkaclassic/metrics/pkg/httpclient"
 )
 
+// Audited actions.
+const (
+	actionUpdate = "update"
+	actionDelete = "delete"
+	actionExpire = "expire"
+)
+
 type (
 	// handler is the internal interface for audit event handlers.
 	// Implementations define how audit events are processed and stored
@@ -56,13 +65,28 @@ type (
 		// metric: The metric that was operated on
 		// timestamp: Unix timestamp of the operation
 		// ip: Source IP address of the request
-		AuditOne(models.Metrics, int64, string)
+		// tenant: Tenant owning the metric
+		AuditOne(models.Metrics, int64, string, string)
 
 		// AuditMany logs multiple metrics operations in a single event.
 		// metrics: List of metrics that were operated on
 		// timestamp: Unix timestamp of the operation
 		// ip: Source IP address of the request
-		AuditMany([]models.Metrics, int64, string)
+		// tenant: Tenant owning the metrics
+		AuditMany([]models.Metrics, int64, string, string)
+
+		// AuditDelete logs deletion of metrics in a single event.
+		// metrics: List of deleted metrics
+		// timestamp: Unix timestamp of the operation
+		// ip: Source IP address of the request
+		// tenant: Tenant owning the metrics
+		AuditDelete([]models.Metrics, int64, string, string)
+
+		// AuditExpire logs metrics purged after their TTL in a single event.
+		// metrics: List of expired metrics
+		// timestamp: Unix timestamp of the operation
+		// tenant: Tenant owning the metrics
+		AuditExpire([]models.Metrics, int64, string)
 	}
 	// auditor implements the Auditor interface with multiple handler support.
 	// Distributes audit events to all configured handlers concurrently.
@@ -76,11 +100,18 @@ type (
 		// Ts is the Unix timestamp of the audited operation.
 		TS int64 `json:"ts"`
 
+		// Action is the audited operation: update, delete or expire.
+		Action string `json:"action"`
+
 		// Metrics contains the IDs of all metrics involved in the operation.
 		Metrics []string `json:"metrics"`
 
-		// IPAddress is the source IP address of the request.
+		// IPAddress is the source IP address of the request,
+		// empty for expirations.
 		IPAddress string `json:"ip_address"`
+
+		// Tenant is the tenant owning the metrics.
+		Tenant string `json:"tenant"`
 	}
 )
 
@@ -171,12 +202,31 @@ func newFileHandler(filePath string) (fileHandler, error) {
 // AuditOne logs a single metric operation to all configured handlers.
 // Wraps the single metric in a slice and calls AuditMany.
 // This is a convenience method for single metric operations.
-func (a *auditor) AuditOne(metric models.Metrics, timestamp int64, ip string) {
+func (a *auditor) AuditOne(metric models.Metrics, timestamp int64, ip string, tenant string) {
 	metrics := []models.Metrics{metric}
-	a.AuditMany(metrics, timestamp, ip)
+	a.AuditMany(metrics, timestamp, ip, tenant)
 }
 
 // AuditMany logs multiple metric operations using all configured audit handlers.
+// See dispatch for the delivery guarantees.
+func (a *auditor) AuditMany(metrics []models.Metrics, timestamp int64, ip string, tenant string) {
+	a.dispatch(actionUpdate, metrics, timestamp, ip, tenant)
+}
+
+// AuditDelete logs deletion of metrics using all configured audit handlers.
+// See dispatch for the delivery guarantees.
+func (a *auditor) AuditDelete(metrics []models.Metrics, timestamp int64, ip string, tenant string) {
+	a.dispatch(actionDelete, metrics, timestamp, ip, tenant)
+}
+
+// AuditExpire logs expiration of metrics using all configured audit handlers.
+// Expirations are not caused by a request, so the event has no IP address.
+// See dispatch for the delivery guarantees.
+func (a *auditor) AuditExpire(metrics []models.Metrics, timestamp int64, tenant string) {
+	a.dispatch(actionExpire, metrics, timestamp, "", tenant)
+}
+
+// dispatch delivers an audit event to all configured handlers.
 //
 // The method is asynchronous (fire-and-forget):
 //   - returns immediately
@@ -189,7 +239,7 @@ func (a *auditor) AuditOne(metric models.Metrics, timestamp int64, ip string) {
 //  4. Does not propagate errors to the caller
 //
 // If no handlers are configured, the method is a no-op.
-func (a *auditor) AuditMany(metrics []models.Metrics, timestamp int64, ip string) {
+func (a *auditor) dispatch(action string, metrics []models.Metrics, timestamp int64, ip string, tenant string) {
 
 	if len(a.handlers) == 0 {
 		return
@@ -197,8 +247,10 @@ func (a *auditor) AuditMany(metrics []models.Metrics, timestamp int64, ip string
 
 	e := event{
 		TS:        timestamp,
+		Action:    action,
 		Metrics:   getMetricsNames(metrics),
 		IPAddress: ip,
+		Tenant:    tenant,
 	}
 
 	go func() {
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/gabkaclassic/metrics/internal/config"
 	models "github.com/gabkaclassic/metrics/internal/model"
@@ -263,6 +264,7 @@ func TestFileHandler_Handle(t *testing.T) {
 				TS:        1,
 				Metrics:   []string{"m1", "m2"},
 				IPAddress: "127.0.0.1",
+				Tenant:    "team-a",
 			},
 			expectLines: 1,
 		},
@@ -321,6 +323,7 @@ func TestFileHandler_Handle(t *testing.T) {
 				assert.Equal(t, tt.event.TS, got.TS)
 				assert.Equal(t, tt.event.Metrics, got.Metrics)
 				assert.Equal(t, tt.event.IPAddress, got.IPAddress)
+				assert.Equal(t, tt.event.Tenant, got.Tenant)
 			}
 		})
 	}
@@ -375,6 +378,7 @@ func TestURLHandler_Handle(t *testing.T) {
 				TS:        123,
 				Metrics:   []string{"m1", "m2"},
 				IPAddress: "127.0.0.1",
+				Tenant:    "team-a",
 			}
 
 			err := h.handle(e)
@@ -388,6 +392,61 @@ func TestURLHandler_Handle(t *testing.T) {
 			assert.Equal(t, e.TS, received.TS)
 			assert.Equal(t, e.Metrics, received.Metrics)
 			assert.Equal(t, e.IPAddress, received.IPAddress)
+			assert.Equal(t, e.Tenant, received.Tenant)
+		})
+	}
+}
+
+func TestAuditor_actions(t *testing.T) {
+	tests := []struct {
+		name     string
+		audit    func(a Auditor)
+		expected event
+	}{
+		{
+			name: "update",
+			audit: func(a Auditor) {
+				a.AuditOne(models.Metrics{ID: "m1"}, 10, "127.0.0.1", "team-a")
+			},
+			expected: event{TS: 10, Action: actionUpdate, Metrics: []string{"m1"}, IPAddress: "127.0.0.1", Tenant: "team-a"},
+		},
+		{
+			name: "delete",
+			audit: func(a Auditor) {
+				a.AuditDelete([]models.Metrics{{ID: "m1"}, {ID: "m2"}}, 20, "127.0.0.1", "team-a")
+			},
+			expected: event{TS: 20, Action: actionDelete, Metrics: []string{"m1", "m2"}, IPAddress: "127.0.0.1", Tenant: "team-a"},
+		},
+		{
+			name: "expire",
+			audit: func(a Auditor) {
+				a.AuditExpire([]models.Metrics{{ID: "m3"}}, 30, "team-b")
+			},
+			expected: event{TS: 30, Action: actionExpire, Metrics: []string{"m3"}, Tenant: "team-b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmp := filepath.Join(t.TempDir(), "audit.log")
+
+			a, err := NewAudior(config.Audit{File: tmp})
+			assert.NoError(t, err)
+
+			tt.audit(a)
+
+			assert.Eventually(t, func() bool {
+				data, err := os.ReadFile(tmp)
+				if err != nil || len(data) == 0 {
+					return false
+				}
+
+				var got event
+				if err := json.Unmarshal(data, &got); err != nil {
+					return false
+				}
+				return assert.ObjectsAreEqual(tt.expected, got)
+			}, time.Second, 10*time.Millisecond)
 		})
 	}
 }
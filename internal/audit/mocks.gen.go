@@ -114,12 +114,122 @@ func (_m *MockAuditor) EXPECT() *MockAuditor_Expecter {
 	return &MockAuditor_Expecter{mock: &_m.Mock}
 }
 
-// AuditMany provides a mock function for the type MockAuditor
-func (_mock *MockAuditor) AuditMany(metricss []models.Metrics, n int64, s string) {
+// AuditDelete provides a mock function for the type MockAuditor
+func (_mock *MockAuditor) AuditDelete(metricss []models.Metrics, n int64, s string, s1 string) {
+	_mock.Called(metricss, n, s, s1)
+	return
+}
+
+// MockAuditor_AuditDelete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AuditDelete'
+type MockAuditor_AuditDelete_Call struct {
+	*mock.Call
+}
+
+// AuditDelete is a helper method to define mock.On call
+//   - metricss []models.Metrics
+//   - n int64
+//   - s string
+//   - s1 string
+func (_e *MockAuditor_Expecter) AuditDelete(metricss interface{}, n interface{}, s interface{}, s1 interface{}) *MockAuditor_AuditDelete_Call {
+	return &MockAuditor_AuditDelete_Call{Call: _e.mock.On("AuditDelete", metricss, n, s, s1)}
+}
+
+func (_c *MockAuditor_AuditDelete_Call) Run(run func(metricss []models.Metrics, n int64, s string, s1 string)) *MockAuditor_AuditDelete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 []models.Metrics
+		if args[0] != nil {
+			arg0 = args[0].([]models.Metrics)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAuditor_AuditDelete_Call) Return() *MockAuditor_AuditDelete_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockAuditor_AuditDelete_Call) RunAndReturn(run func(metricss []models.Metrics, n int64, s string, s1 string)) *MockAuditor_AuditDelete_Call {
+	_c.Run(run)
+	return _c
+}
+
+// AuditExpire provides a mock function for the type MockAuditor
+func (_mock *MockAuditor) AuditExpire(metricss []models.Metrics, n int64, s string) {
 	_mock.Called(metricss, n, s)
 	return
 }
 
+// MockAuditor_AuditExpire_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AuditExpire'
+type MockAuditor_AuditExpire_Call struct {
+	*mock.Call
+}
+
+// AuditExpire is a helper method to define mock.On call
+//   - metricss []models.Metrics
+//   - n int64
+//   - s string
+func (_e *MockAuditor_Expecter) AuditExpire(metricss interface{}, n interface{}, s interface{}) *MockAuditor_AuditExpire_Call {
+	return &MockAuditor_AuditExpire_Call{Call: _e.mock.On("AuditExpire", metricss, n, s)}
+}
+
+func (_c *MockAuditor_AuditExpire_Call) Run(run func(metricss []models.Metrics, n int64, s string)) *MockAuditor_AuditExpire_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 []models.Metrics
+		if args[0] != nil {
+			arg0 = args[0].([]models.Metrics)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAuditor_AuditExpire_Call) Return() *MockAuditor_AuditExpire_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockAuditor_AuditExpire_Call) RunAndReturn(run func(metricss []models.Metrics, n int64, s string)) *MockAuditor_AuditExpire_Call {
+	_c.Run(run)
+	return _c
+}
+
+// AuditMany provides a mock function for the type MockAuditor
+func (_mock *MockAuditor) AuditMany(metricss []models.Metrics, n int64, s string, s1 string) {
+	_mock.Called(metricss, n, s, s1)
+	return
+}
+
 // MockAuditor_AuditMany_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AuditMany'
 type MockAuditor_AuditMany_Call struct {
 	*mock.Call
@@ -129,11 +239,12 @@ type MockAuditor_AuditMany_Call struct {
 //   - metricss []models.Metrics
 //   - n int64
 //   - s string
-func (_e *MockAuditor_Expecter) AuditMany(metricss interface{}, n interface{}, s interface{}) *MockAuditor_AuditMany_Call {
-	return &MockAuditor_AuditMany_Call{Call: _e.mock.On("AuditMany", metricss, n, s)}
+//   - s1 string
+func (_e *MockAuditor_Expecter) AuditMany(metricss interface{}, n interface{}, s interface{}, s1 interface{}) *MockAuditor_AuditMany_Call {
+	return &MockAuditor_AuditMany_Call{Call: _e.mock.On("AuditMany", metricss, n, s, s1)}
 }
 
-func (_c *MockAuditor_AuditMany_Call) Run(run func(metricss []models.Metrics, n int64, s string)) *MockAuditor_AuditMany_Call {
+func (_c *MockAuditor_AuditMany_Call) Run(run func(metricss []models.Metrics, n int64, s string, s1 string)) *MockAuditor_AuditMany_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 []models.Metrics
 		if args[0] != nil {
@@ -147,10 +258,15 @@ func (_c *MockAuditor_AuditMany_Call) Run(run func(metricss []models.Metrics, n
 		if args[2] != nil {
 			arg2 = args[2].(string)
 		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
@@ -161,14 +277,14 @@ func (_c *MockAuditor_AuditMany_Call) Return() *MockAuditor_AuditMany_Call {
 	return _c
 }
 
-func (_c *MockAuditor_AuditMany_Call) RunAndReturn(run func(metricss []models.Metrics, n int64, s string)) *MockAuditor_AuditMany_Call {
+func (_c *MockAuditor_AuditMany_Call) RunAndReturn(run func(metricss []models.Metrics, n int64, s string, s1 string)) *MockAuditor_AuditMany_Call {
 	_c.Run(run)
 	return _c
 }
 
 // AuditOne provides a mock function for the type MockAuditor
-func (_mock *MockAuditor) AuditOne(metrics models.Metrics, n int64, s string) {
-	_mock.Called(metrics, n, s)
+func (_mock *MockAuditor) AuditOne(metrics models.Metrics, n int64, s string, s1 string) {
+	_mock.Called(metrics, n, s, s1)
 	return
 }
 
@@ -181,11 +297,12 @@ type MockAuditor_AuditOne_Call struct {
 //   - metrics models.Metrics
 //   - n int64
 //   - s string
-func (_e *MockAuditor_Expecter) AuditOne(metrics interface{}, n interface{}, s interface{}) *MockAuditor_AuditOne_Call {
-	return &MockAuditor_AuditOne_Call{Call: _e.mock.On("AuditOne", metrics, n, s)}
+//   - s1 string
+func (_e *MockAuditor_Expecter) AuditOne(metrics interface{}, n interface{}, s interface{}, s1 interface{}) *MockAuditor_AuditOne_Call {
+	return &MockAuditor_AuditOne_Call{Call: _e.mock.On("AuditOne", metrics, n, s, s1)}
 }
 
-func (_c *MockAuditor_AuditOne_Call) Run(run func(metrics models.Metrics, n int64, s string)) *MockAuditor_AuditOne_Call {
+func (_c *MockAuditor_AuditOne_Call) Run(run func(metrics models.Metrics, n int64, s string, s1 string)) *MockAuditor_AuditOne_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 models.Metrics
 		if args[0] != nil {
@@ -199,10 +316,15 @@ func (_c *MockAuditor_AuditOne_Call) Run(run func(metrics models.Metrics, n int6
 		if args[2] != nil {
 			arg2 = args[2].(string)
 		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
@@ -213,7 +335,7 @@ func (_c *MockAuditor_AuditOne_Call) Return() *MockAuditor_AuditOne_Call {
 	return _c
 }
 
-func (_c *MockAuditor_AuditOne_Call) RunAndReturn(run func(metrics models.Metrics, n int64, s string)) *MockAuditor_AuditOne_Call {
+func (_c *MockAuditor_AuditOne_Call) RunAndReturn(run func(metrics models.Metrics, n int64, s string, s1 string)) *MockAuditor_AuditOne_Call {
 	_c.Run(run)
 	return _c
 }
@@ -15,6 +15,7 @@ package config
 
 import (
 	"flag"
+	"fmt"
 	"net/url"
 	"reflect"
 	"strconv"
@@ -33,6 +34,18 @@ type (
 		Dump    Dump
 		DB      DB
 		Audit   Audit
+		History History
+		Tenant  Tenant
+		TTL     TTL
+		StatsD  StatsD
+		GRPC    GRPC
+		Alert   Alert
+
+		Graphite    Graphite
+		Idempotency Idempotency
+		Rate        Rate
+		OTLP        OTLP
+		CryptoKey   string `env:"CRYPTO_KEY"`
 	}
 	// Agent represents the configuration of the metrics agent.
 	Agent struct {
@@ -42,8 +55,13 @@ type (
 		Log            Log
 		BatchesEnabled bool   `env:"BATCHES" envDefault:"true"`
 		SignKey        string `env:"KEY"`
+		CryptoKey      string `env:"CRYPTO_KEY"`
 		RateLimit      int    `env:"RATE_LIMIT" envDefault:"5"`
 		BatchSize      int    `env:"BATCH_SIZE" envDefault:"100"`
+		Tenant         string `env:"TENANT"`
+		APIKey         string `env:"API_KEY"`
+		Transport      string `env:"TRANSPORT" envDefault:"http"`
+		GRPCAddress    string `env:"GRPC_ADDRESS"`
 	}
 	// DB contains database-related configuration.
 	DB struct {
@@ -78,6 +96,94 @@ type (
 		File string `env:"AUDIT_FILE"`
 		URL  string `env:"AUDIT_URL"`
 	}
+	// History defines configuration for the series history.
+	// Size bounds the points kept per series in memory. Points older than
+	// Retention are pruned by the janitor, zero keeps them forever.
+	History struct {
+		Size      int           `env:"HISTORY_SIZE" envDefault:"1000"`
+		Retention time.Duration `env:"HISTORY_RETENTION" envDefault:"604800"`
+	}
+	// Tenant defines how request tenants are resolved.
+	// Keys maps API keys to tenants as "key:tenant,key:tenant".
+	// Without keys the tenant is taken from the X-Tenant header.
+	Tenant struct {
+		Keys map[string]string `env:"TENANT_KEYS"`
+	}
+	// TTL defines expiration of series that are not updated.
+	// Default applies to metrics without their own TTL in metadata,
+	// zero keeps them forever. CheckInterval is the janitor period,
+	// zero disables the janitor.
+	TTL struct {
+		Default       time.Duration `env:"METRIC_TTL" envDefault:"0"`
+		CheckInterval time.Duration `env:"TTL_CHECK_INTERVAL" envDefault:"60"`
+	}
+	// StatsD defines the optional StatsD ingestion listeners.
+	// Address is a UDP host:port and Socket a unix datagram socket path,
+	// each listener is disabled when empty. Received metrics are coalesced
+	// and saved once per FlushInterval into Tenant (default tenant when empty).
+	// Metrics of a sender above MaxPending per flush interval are dropped.
+	StatsD struct {
+		Address       string        `env:"STATSD_ADDRESS"`
+		Socket        string        `env:"STATSD_SOCKET"`
+		FlushInterval time.Duration `env:"STATSD_FLUSH_INTERVAL" envDefault:"1"`
+		Tenant        string        `env:"STATSD_TENANT"`
+		MaxPending    int           `env:"STATSD_MAX_PENDING" envDefault:"10000"`
+	}
+	// Graphite defines the optional Graphite plaintext listener.
+	// The listener accepts TCP connections on Address and is disabled when empty.
+	// Paths matching one of CounterPatterns (path.Match syntax, e.g. *.requests)
+	// are saved as counters, other paths as gauges. Received metrics are saved
+	// into Tenant (default tenant when empty) in batches of BatchSize metrics
+	// or once per FlushInterval. Connections above MaxConnections are rejected,
+	// connections that send nothing for IdleTimeout are closed, zero keeps them open.
+	Graphite struct {
+		Address         string        `env:"GRAPHITE_ADDRESS"`
+		CounterPatterns []string      `env:"GRAPHITE_COUNTER_PATTERNS"`
+		BatchSize       int           `env:"GRAPHITE_BATCH_SIZE" envDefault:"500"`
+		FlushInterval   time.Duration `env:"GRAPHITE_FLUSH_INTERVAL" envDefault:"1"`
+		MaxConnections  int           `env:"GRAPHITE_MAX_CONNECTIONS" envDefault:"100"`
+		IdleTimeout     time.Duration `env:"GRAPHITE_IDLE_TIMEOUT" envDefault:"300"`
+		Tenant          string        `env:"GRAPHITE_TENANT"`
+	}
+	// GRPC defines the optional gRPC server.
+	// The server is disabled when Address is empty.
+	// TrustedSubnet is a CIDR calls must originate from, empty allows all callers.
+	GRPC struct {
+		Address       string `env:"GRPC_ADDRESS"`
+		TrustedSubnet string `env:"TRUSTED_SUBNET"`
+	}
+	// Alert defines threshold alerting.
+	// RulesFile is a JSON array of rules loaded on startup, replacing stored
+	// rules with the same IDs. EvalInterval is the rule evaluation period,
+	// zero disables evaluation. Notifications of firing alerts are repeated
+	// every RepeatInterval. Webhooks are notified about alerts of rules
+	// without their own webhooks, as comma-separated URLs.
+	Alert struct {
+		RulesFile      string        `env:"ALERT_RULES_FILE"`
+		EvalInterval   time.Duration `env:"ALERT_EVAL_INTERVAL" envDefault:"15"`
+		RepeatInterval time.Duration `env:"ALERT_REPEAT_INTERVAL" envDefault:"3600"`
+		Webhooks       []string      `env:"ALERT_WEBHOOKS"`
+	}
+	// Idempotency defines how long responses of write requests
+	// with an Idempotency-Key header are remembered,
+	// zero disables idempotency keys.
+	// Expired keys are deleted every ExpireInterval, zero never deletes them.
+	Idempotency struct {
+		TTL            time.Duration `env:"IDEMPOTENCY_TTL" envDefault:"86400"`
+		ExpireInterval time.Duration `env:"IDEMPOTENCY_EXPIRE_INTERVAL" envDefault:"60"`
+	}
+	// Rate defines the in-memory sample window of rate queries.
+	// Counter and gauge samples are kept for Window, which bounds
+	// the window of rate queries, zero disables rate queries.
+	Rate struct {
+		Window time.Duration `env:"RATE_WINDOW" envDefault:"900"`
+	}
+	// OTLP defines the OTLP/HTTP metrics receiver.
+	// Cumulative totals of series not reported for TotalsTTL are forgotten,
+	// zero keeps them forever.
+	OTLP struct {
+		TotalsTTL time.Duration `env:"OTLP_TOTALS_TTL" envDefault:"3600"`
+	}
 )
 
 // ensureURL normalizes an address string into a valid URL.
@@ -93,6 +199,34 @@ func ensureURL(addr string) string {
 	return u.String()
 }
 
+// parseKeyValues parses "key:value,key:value" pairs,
+// the format caarlos0/env uses for maps.
+func parseKeyValues(raw string) (map[string]string, error) {
+	result := make(map[string]string)
+	for pair := range strings.SplitSeq(raw, ",") {
+		if pair == "" {
+			continue
+		}
+		key, value, found := strings.Cut(pair, ":")
+		if !found || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid key-value pair: %q", pair)
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+// splitList parses comma-separated values, skipping empty ones.
+func splitList(raw string) []string {
+	values := make([]string, 0)
+	for value := range strings.SplitSeq(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func defineEnvParsers() map[reflect.Type]env.ParserFunc {
 	return map[reflect.Type]env.ParserFunc{
 		reflect.TypeOf(time.Duration(0)): func(v string) (any, error) {
@@ -145,10 +279,49 @@ func ParseServerConfig() (*Server, error) {
 	auditFile := flag.String("audit-file", cfg.Audit.File, "Audit dump filepath")
 	auditURL := flag.String("audit-url", cfg.Audit.URL, "Audit url")
 
+	historySize := flag.Int("history-size", cfg.History.Size, "Points kept per series in memory")
+	historyRetention := flag.Uint("history-retention", uint(cfg.History.Retention.Seconds()), "Seconds history points are kept, 0 keeps them forever")
+
+	tenantKeys := flag.String("tenant-keys", "", "API keys of tenants as key:tenant,key:tenant")
+
+	metricTTL := flag.Uint("metric-ttl", uint(cfg.TTL.Default.Seconds()), "Seconds a series is kept without updates, 0 keeps it forever")
+	ttlCheckInterval := flag.Uint("ttl-check-interval", uint(cfg.TTL.CheckInterval.Seconds()), "Expired series check interval")
+
+	statsdAddress := flag.String("statsd-address", cfg.StatsD.Address, "StatsD UDP listen address")
+	statsdSocket := flag.String("statsd-socket", cfg.StatsD.Socket, "StatsD unix datagram socket path")
+	statsdFlushInterval := flag.Uint("statsd-flush-interval", uint(cfg.StatsD.FlushInterval.Seconds()), "StatsD metrics flush interval")
+	statsdTenant := flag.String("statsd-tenant", cfg.StatsD.Tenant, "Tenant StatsD metrics are saved to")
+	statsdMaxPending := flag.Int("statsd-max-pending", cfg.StatsD.MaxPending, "StatsD metrics kept per sender between flushes")
+
+	graphiteAddress := flag.String("graphite-address", cfg.Graphite.Address, "Graphite plaintext TCP listen address")
+	graphiteCounterPatterns := flag.String("graphite-counter-patterns", strings.Join(cfg.Graphite.CounterPatterns, ","), "Glob patterns of Graphite counter paths as pattern,pattern")
+	graphiteBatchSize := flag.Int("graphite-batch-size", cfg.Graphite.BatchSize, "Graphite metrics saved per batch")
+	graphiteFlushInterval := flag.Uint("graphite-flush-interval", uint(cfg.Graphite.FlushInterval.Seconds()), "Graphite metrics flush interval")
+	graphiteMaxConnections := flag.Int("graphite-max-connections", cfg.Graphite.MaxConnections, "Maximum concurrent Graphite connections")
+	graphiteIdleTimeout := flag.Uint("graphite-idle-timeout", uint(cfg.Graphite.IdleTimeout.Seconds()), "Seconds idle Graphite connections are kept open, 0 keeps them open")
+	graphiteTenant := flag.String("graphite-tenant", cfg.Graphite.Tenant, "Tenant Graphite metrics are saved to")
+
+	grpcAddress := flag.String("grpc-address", cfg.GRPC.Address, "gRPC server address")
+	trustedSubnet := flag.String("t", cfg.GRPC.TrustedSubnet, "Trusted subnet of gRPC callers in CIDR notation")
+
+	alertRulesFile := flag.String("alert-rules-file", cfg.Alert.RulesFile, "JSON file of alert rules loaded on startup")
+	alertEvalInterval := flag.Uint("alert-eval-interval", uint(cfg.Alert.EvalInterval.Seconds()), "Alert rules evaluation interval, 0 disables alerting")
+	alertRepeatInterval := flag.Uint("alert-repeat-interval", uint(cfg.Alert.RepeatInterval.Seconds()), "Firing alert notification repeat interval")
+	alertWebhooks := flag.String("alert-webhooks", strings.Join(cfg.Alert.Webhooks, ","), "Default alert webhook URLs as url,url")
+
+	idempotencyTTL := flag.Uint("idempotency-ttl", uint(cfg.Idempotency.TTL.Seconds()), "Seconds responses of idempotency keys are remembered, 0 disables idempotency keys")
+	idempotencyExpireInterval := flag.Uint("idempotency-expire-interval", uint(cfg.Idempotency.ExpireInterval.Seconds()), "Seconds between deletions of expired idempotency keys, 0 disables deletion")
+
+	rateWindow := flag.Uint("rate-window", uint(cfg.Rate.Window.Seconds()), "Seconds samples of rate queries are kept, 0 disables rate queries")
+
+	otlpTotalsTTL := flag.Uint("otlp-totals-ttl", uint(cfg.OTLP.TotalsTTL.Seconds()), "Seconds OTLP cumulative totals of unreported series are kept, 0 keeps them forever")
+
 	signKey := flag.String("k", cfg.SignKey, "Key to verify requests bodies")
+	cryptoKey := flag.String("crypto-key", cfg.CryptoKey, "Private key PEM file to decrypt requests bodies")
 
 	flag.Parse()
 
+	var flagErr error
 	flag.Visit(func(f *flag.Flag) {
 		switch f.Name {
 		case "a":
@@ -186,11 +359,81 @@ func ParseServerConfig() (*Server, error) {
 		case "audit-url":
 			cfg.Audit.URL = *auditURL
 
+		case "history-size":
+			cfg.History.Size = *historySize
+		case "history-retention":
+			cfg.History.Retention = time.Duration(*historyRetention) * time.Second
+
+		case "tenant-keys":
+			cfg.Tenant.Keys, flagErr = parseKeyValues(*tenantKeys)
+
+		case "metric-ttl":
+			cfg.TTL.Default = time.Duration(*metricTTL) * time.Second
+		case "ttl-check-interval":
+			cfg.TTL.CheckInterval = time.Duration(*ttlCheckInterval) * time.Second
+
+		case "statsd-address":
+			cfg.StatsD.Address = *statsdAddress
+		case "statsd-socket":
+			cfg.StatsD.Socket = *statsdSocket
+		case "statsd-flush-interval":
+			cfg.StatsD.FlushInterval = time.Duration(*statsdFlushInterval) * time.Second
+		case "statsd-tenant":
+			cfg.StatsD.Tenant = *statsdTenant
+		case "statsd-max-pending":
+			cfg.StatsD.MaxPending = *statsdMaxPending
+
+		case "graphite-address":
+			cfg.Graphite.Address = *graphiteAddress
+		case "graphite-counter-patterns":
+			cfg.Graphite.CounterPatterns = splitList(*graphiteCounterPatterns)
+		case "graphite-batch-size":
+			cfg.Graphite.BatchSize = *graphiteBatchSize
+		case "graphite-flush-interval":
+			cfg.Graphite.FlushInterval = time.Duration(*graphiteFlushInterval) * time.Second
+		case "graphite-max-connections":
+			cfg.Graphite.MaxConnections = *graphiteMaxConnections
+		case "graphite-idle-timeout":
+			cfg.Graphite.IdleTimeout = time.Duration(*graphiteIdleTimeout) * time.Second
+		case "graphite-tenant":
+			cfg.Graphite.Tenant = *graphiteTenant
+
+		case "grpc-address":
+			cfg.GRPC.Address = *grpcAddress
+		case "t":
+			cfg.GRPC.TrustedSubnet = *trustedSubnet
+
+		case "alert-rules-file":
+			cfg.Alert.RulesFile = *alertRulesFile
+		case "alert-eval-interval":
+			cfg.Alert.EvalInterval = time.Duration(*alertEvalInterval) * time.Second
+		case "alert-repeat-interval":
+			cfg.Alert.RepeatInterval = time.Duration(*alertRepeatInterval) * time.Second
+		case "alert-webhooks":
+			cfg.Alert.Webhooks = splitList(*alertWebhooks)
+
+		case "idempotency-ttl":
+			cfg.Idempotency.TTL = time.Duration(*idempotencyTTL) * time.Second
+		case "idempotency-expire-interval":
+			cfg.Idempotency.ExpireInterval = time.Duration(*idempotencyExpireInterval) * time.Second
+
+		case "rate-window":
+			cfg.Rate.Window = time.Duration(*rateWindow) * time.Second
+
+		case "otlp-totals-ttl":
+			cfg.OTLP.TotalsTTL = time.Duration(*otlpTotalsTTL) * time.Second
+
 		case "k":
 			cfg.SignKey = *signKey
+		case "crypto-key":
+			cfg.CryptoKey = *cryptoKey
 		}
 	})
 
+	if flagErr != nil {
+		return nil, flagErr
+	}
+
 	return &cfg, nil
 }
 
@@ -226,8 +469,15 @@ func ParseAgentConfig() (*Agent, error) {
 	logJSON := flag.Bool("log-json", cfg.Log.JSON, "Enable JSON output for logs")
 
 	signKey := flag.String("k", cfg.SignKey, "Key to sign requests bodies")
+	cryptoKey := flag.String("crypto-key", cfg.CryptoKey, "Public key PEM file to encrypt requests bodies")
 	rateLimit := flag.Int("l", cfg.RateLimit, "Rate limits to send metric")
 
+	tenant := flag.String("tenant", cfg.Tenant, "Tenant to report metrics to")
+	apiKey := flag.String("api-key", cfg.APIKey, "API key identifying the tenant")
+
+	transport := flag.String("transport", cfg.Transport, "Report transport: http or grpc")
+	grpcAddress := flag.String("grpc-address", cfg.GRPCAddress, "Server gRPC address")
+
 	flag.Parse()
 
 	flag.Visit(func(f *flag.Flag) {
@@ -259,8 +509,20 @@ func ParseAgentConfig() (*Agent, error) {
 
 		case "k":
 			cfg.SignKey = *signKey
+		case "crypto-key":
+			cfg.CryptoKey = *cryptoKey
 		case "l":
 			cfg.RateLimit = *rateLimit
+
+		case "tenant":
+			cfg.Tenant = *tenant
+		case "api-key":
+			cfg.APIKey = *apiKey
+
+		case "transport":
+			cfg.Transport = *transport
+		case "grpc-address":
+			cfg.GRPCAddress = *grpcAddress
 		}
 	})
 
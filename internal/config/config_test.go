@@ -298,3 +298,659 @@ func resetEnv(vars ...string) {
 		_ = os.Unsetenv(v)
 	}
 }
+
+func TestParseServerConfig_TenantKeys(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		env         map[string]string
+		wantKeys    map[string]string
+		expectError bool
+	}{
+		{
+			name:     "keys from env",
+			args:     []string{"cmd"},
+			env:      map[string]string{"TENANT_KEYS": "k1:team-a,k2:team-b"},
+			wantKeys: map[string]string{"k1": "team-a", "k2": "team-b"},
+		},
+		{
+			name:     "keys from flag",
+			args:     []string{"cmd", "-tenant-keys=k1:team-a"},
+			env:      map[string]string{"TENANT_KEYS": "k2:team-b"},
+			wantKeys: map[string]string{"k1": "team-a"},
+		},
+		{
+			name:        "malformed flag",
+			args:        []string{"cmd", "-tenant-keys=k1"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+			resetEnv("TENANT_KEYS")
+			t.Cleanup(func() { resetEnv("TENANT_KEYS") })
+
+			for k, v := range tt.env {
+				_ = os.Setenv(k, v)
+			}
+
+			os.Args = tt.args
+			cfg, err := ParseServerConfig()
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantKeys, cfg.Tenant.Keys)
+		})
+	}
+}
+
+func TestParseServerConfig_TTL(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		env          map[string]string
+		wantTTL      time.Duration
+		wantInterval time.Duration
+	}{
+		{
+			name:         "default values",
+			args:         []string{"cmd"},
+			wantTTL:      0,
+			wantInterval: time.Minute,
+		},
+		{
+			name:         "values from env",
+			args:         []string{"cmd"},
+			env:          map[string]string{"METRIC_TTL": "3600", "TTL_CHECK_INTERVAL": "30"},
+			wantTTL:      time.Hour,
+			wantInterval: 30 * time.Second,
+		},
+		{
+			name:         "env overridden by flags",
+			args:         []string{"cmd", "-metric-ttl=60", "-ttl-check-interval=5"},
+			env:          map[string]string{"METRIC_TTL": "3600"},
+			wantTTL:      time.Minute,
+			wantInterval: 5 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+			resetEnv("METRIC_TTL", "TTL_CHECK_INTERVAL")
+			t.Cleanup(func() { resetEnv("METRIC_TTL", "TTL_CHECK_INTERVAL") })
+
+			for k, v := range tt.env {
+				_ = os.Setenv(k, v)
+			}
+
+			os.Args = tt.args
+			cfg, err := ParseServerConfig()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTTL, cfg.TTL.Default)
+			assert.Equal(t, tt.wantInterval, cfg.TTL.CheckInterval)
+		})
+	}
+}
+
+func TestParseServerConfig_History(t *testing.T) {
+	envKeys := []string{"HISTORY_SIZE", "HISTORY_RETENTION"}
+
+	tests := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		expected History
+	}{
+		{
+			name:     "default values",
+			args:     []string{"cmd"},
+			expected: History{Size: 1000, Retention: 7 * 24 * time.Hour},
+		},
+		{
+			name:     "values from env",
+			args:     []string{"cmd"},
+			env:      map[string]string{"HISTORY_SIZE": "100", "HISTORY_RETENTION": "3600"},
+			expected: History{Size: 100, Retention: time.Hour},
+		},
+		{
+			name:     "env overridden by flags",
+			args:     []string{"cmd", "-history-size=10", "-history-retention=0"},
+			env:      map[string]string{"HISTORY_SIZE": "100", "HISTORY_RETENTION": "3600"},
+			expected: History{Size: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+			resetEnv(envKeys...)
+			t.Cleanup(func() { resetEnv(envKeys...) })
+
+			for k, v := range tt.env {
+				_ = os.Setenv(k, v)
+			}
+
+			os.Args = tt.args
+			cfg, err := ParseServerConfig()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.History)
+		})
+	}
+}
+
+func TestParseServerConfig_Idempotency(t *testing.T) {
+	tests := []struct {
+		name               string
+		args               []string
+		env                map[string]string
+		wantTTL            time.Duration
+		wantExpireInterval time.Duration
+	}{
+		{
+			name:               "default value",
+			args:               []string{"cmd"},
+			wantTTL:            24 * time.Hour,
+			wantExpireInterval: time.Minute,
+		},
+		{
+			name:               "value from env",
+			args:               []string{"cmd"},
+			env:                map[string]string{"IDEMPOTENCY_TTL": "600", "IDEMPOTENCY_EXPIRE_INTERVAL": "30"},
+			wantTTL:            10 * time.Minute,
+			wantExpireInterval: 30 * time.Second,
+		},
+		{
+			name:               "env overridden by flag",
+			args:               []string{"cmd", "-idempotency-ttl=0", "-idempotency-expire-interval=0"},
+			env:                map[string]string{"IDEMPOTENCY_TTL": "600", "IDEMPOTENCY_EXPIRE_INTERVAL": "30"},
+			wantTTL:            0,
+			wantExpireInterval: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+			resetEnv("IDEMPOTENCY_TTL", "IDEMPOTENCY_EXPIRE_INTERVAL")
+			t.Cleanup(func() { resetEnv("IDEMPOTENCY_TTL", "IDEMPOTENCY_EXPIRE_INTERVAL") })
+
+			for k, v := range tt.env {
+				_ = os.Setenv(k, v)
+			}
+
+			os.Args = tt.args
+			cfg, err := ParseServerConfig()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTTL, cfg.Idempotency.TTL)
+			assert.Equal(t, tt.wantExpireInterval, cfg.Idempotency.ExpireInterval)
+		})
+	}
+}
+
+func TestParseServerConfig_Rate(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		env        map[string]string
+		wantWindow time.Duration
+	}{
+		{
+			name:       "default value",
+			args:       []string{"cmd"},
+			wantWindow: 15 * time.Minute,
+		},
+		{
+			name:       "value from env",
+			args:       []string{"cmd"},
+			env:        map[string]string{"RATE_WINDOW": "300"},
+			wantWindow: 5 * time.Minute,
+		},
+		{
+			name:       "env overridden by flag",
+			args:       []string{"cmd", "-rate-window=0"},
+			env:        map[string]string{"RATE_WINDOW": "300"},
+			wantWindow: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+			resetEnv("RATE_WINDOW")
+			t.Cleanup(func() { resetEnv("RATE_WINDOW") })
+
+			for k, v := range tt.env {
+				_ = os.Setenv(k, v)
+			}
+
+			os.Args = tt.args
+			cfg, err := ParseServerConfig()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWindow, cfg.Rate.Window)
+		})
+	}
+}
+
+func TestParseServerConfig_OTLP(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		env           map[string]string
+		wantTotalsTTL time.Duration
+	}{
+		{
+			name:          "default value",
+			args:          []string{"cmd"},
+			wantTotalsTTL: time.Hour,
+		},
+		{
+			name:          "value from env",
+			args:          []string{"cmd"},
+			env:           map[string]string{"OTLP_TOTALS_TTL": "600"},
+			wantTotalsTTL: 10 * time.Minute,
+		},
+		{
+			name:          "env overridden by flag",
+			args:          []string{"cmd", "-otlp-totals-ttl=0"},
+			env:           map[string]string{"OTLP_TOTALS_TTL": "600"},
+			wantTotalsTTL: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+			resetEnv("OTLP_TOTALS_TTL")
+			t.Cleanup(func() { resetEnv("OTLP_TOTALS_TTL") })
+
+			for k, v := range tt.env {
+				_ = os.Setenv(k, v)
+			}
+
+			os.Args = tt.args
+			cfg, err := ParseServerConfig()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTotalsTTL, cfg.OTLP.TotalsTTL)
+		})
+	}
+}
+
+func TestParseServerConfig_StatsD(t *testing.T) {
+	envKeys := []string{"STATSD_ADDRESS", "STATSD_SOCKET", "STATSD_FLUSH_INTERVAL", "STATSD_TENANT", "STATSD_MAX_PENDING"}
+
+	tests := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		expected StatsD
+	}{
+		{
+			name:     "default values",
+			args:     []string{"cmd"},
+			expected: StatsD{FlushInterval: time.Second, MaxPending: 10000},
+		},
+		{
+			name: "values from env",
+			args: []string{"cmd"},
+			env: map[string]string{
+				"STATSD_ADDRESS":        ":8125",
+				"STATSD_SOCKET":         "/tmp/statsd.sock",
+				"STATSD_FLUSH_INTERVAL": "5",
+				"STATSD_TENANT":         "legacy",
+				"STATSD_MAX_PENDING":    "500",
+			},
+			expected: StatsD{Address: ":8125", Socket: "/tmp/statsd.sock", FlushInterval: 5 * time.Second, Tenant: "legacy", MaxPending: 500},
+		},
+		{
+			name:     "env overridden by flags",
+			args:     []string{"cmd", "-statsd-address=:9125", "-statsd-flush-interval=2", "-statsd-tenant=team-a", "-statsd-max-pending=50"},
+			env:      map[string]string{"STATSD_ADDRESS": ":8125", "STATSD_SOCKET": "/tmp/statsd.sock", "STATSD_MAX_PENDING": "500"},
+			expected: StatsD{Address: ":9125", Socket: "/tmp/statsd.sock", FlushInterval: 2 * time.Second, Tenant: "team-a", MaxPending: 50},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+			resetEnv(envKeys...)
+			t.Cleanup(func() { resetEnv(envKeys...) })
+
+			for k, v := range tt.env {
+				_ = os.Setenv(k, v)
+			}
+
+			os.Args = tt.args
+			cfg, err := ParseServerConfig()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.StatsD)
+		})
+	}
+}
+
+func TestParseServerConfig_Graphite(t *testing.T) {
+	envKeys := []string{
+		"GRAPHITE_ADDRESS", "GRAPHITE_COUNTER_PATTERNS", "GRAPHITE_BATCH_SIZE",
+		"GRAPHITE_FLUSH_INTERVAL", "GRAPHITE_MAX_CONNECTIONS", "GRAPHITE_IDLE_TIMEOUT", "GRAPHITE_TENANT",
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		expected Graphite
+	}{
+		{
+			name:     "default values",
+			args:     []string{"cmd"},
+			expected: Graphite{BatchSize: 500, FlushInterval: time.Second, MaxConnections: 100, IdleTimeout: 5 * time.Minute},
+		},
+		{
+			name: "values from env",
+			args: []string{"cmd"},
+			env: map[string]string{
+				"GRAPHITE_ADDRESS":          ":2003",
+				"GRAPHITE_COUNTER_PATTERNS": "*.requests,*.errors",
+				"GRAPHITE_BATCH_SIZE":       "100",
+				"GRAPHITE_FLUSH_INTERVAL":   "5",
+				"GRAPHITE_MAX_CONNECTIONS":  "10",
+				"GRAPHITE_IDLE_TIMEOUT":     "60",
+				"GRAPHITE_TENANT":           "legacy",
+			},
+			expected: Graphite{
+				Address:         ":2003",
+				CounterPatterns: []string{"*.requests", "*.errors"},
+				BatchSize:       100,
+				FlushInterval:   5 * time.Second,
+				MaxConnections:  10,
+				IdleTimeout:     time.Minute,
+				Tenant:          "legacy",
+			},
+		},
+		{
+			name: "env overridden by flags",
+			args: []string{
+				"cmd", "-graphite-address=:2004", "-graphite-counter-patterns=hits.*",
+				"-graphite-batch-size=50", "-graphite-flush-interval=2",
+				"-graphite-max-connections=5", "-graphite-idle-timeout=0", "-graphite-tenant=team-a",
+			},
+			env: map[string]string{"GRAPHITE_ADDRESS": ":2003", "GRAPHITE_COUNTER_PATTERNS": "*.requests", "GRAPHITE_IDLE_TIMEOUT": "60"},
+			expected: Graphite{
+				Address:         ":2004",
+				CounterPatterns: []string{"hits.*"},
+				BatchSize:       50,
+				FlushInterval:   2 * time.Second,
+				MaxConnections:  5,
+				Tenant:          "team-a",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+			resetEnv(envKeys...)
+			t.Cleanup(func() { resetEnv(envKeys...) })
+
+			for k, v := range tt.env {
+				_ = os.Setenv(k, v)
+			}
+
+			os.Args = tt.args
+			cfg, err := ParseServerConfig()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.Graphite)
+		})
+	}
+}
+
+func TestParseServerConfig_GRPC(t *testing.T) {
+	envKeys := []string{"GRPC_ADDRESS", "TRUSTED_SUBNET"}
+
+	tests := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		expected GRPC
+	}{
+		{
+			name:     "default values",
+			args:     []string{"cmd"},
+			expected: GRPC{},
+		},
+		{
+			name:     "values from env",
+			args:     []string{"cmd"},
+			env:      map[string]string{"GRPC_ADDRESS": ":3200", "TRUSTED_SUBNET": "10.0.0.0/8"},
+			expected: GRPC{Address: ":3200", TrustedSubnet: "10.0.0.0/8"},
+		},
+		{
+			name:     "env overridden by flags",
+			args:     []string{"cmd", "-grpc-address=:3201", "-t=192.168.0.0/16"},
+			env:      map[string]string{"GRPC_ADDRESS": ":3200", "TRUSTED_SUBNET": "10.0.0.0/8"},
+			expected: GRPC{Address: ":3201", TrustedSubnet: "192.168.0.0/16"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+			resetEnv(envKeys...)
+			t.Cleanup(func() { resetEnv(envKeys...) })
+
+			for k, v := range tt.env {
+				_ = os.Setenv(k, v)
+			}
+
+			os.Args = tt.args
+			cfg, err := ParseServerConfig()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.GRPC)
+		})
+	}
+}
+
+func TestParseServerConfig_Alert(t *testing.T) {
+	envKeys := []string{"ALERT_RULES_FILE", "ALERT_EVAL_INTERVAL", "ALERT_REPEAT_INTERVAL", "ALERT_WEBHOOKS"}
+
+	tests := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		expected Alert
+	}{
+		{
+			name:     "default values",
+			args:     []string{"cmd"},
+			expected: Alert{EvalInterval: 15 * time.Second, RepeatInterval: time.Hour},
+		},
+		{
+			name: "values from env",
+			args: []string{"cmd"},
+			env: map[string]string{
+				"ALERT_RULES_FILE":      "/etc/metrics/rules.json",
+				"ALERT_EVAL_INTERVAL":   "30",
+				"ALERT_REPEAT_INTERVAL": "600",
+				"ALERT_WEBHOOKS":        "http://a.example/hook,http://b.example/hook",
+			},
+			expected: Alert{
+				RulesFile:      "/etc/metrics/rules.json",
+				EvalInterval:   30 * time.Second,
+				RepeatInterval: 10 * time.Minute,
+				Webhooks:       []string{"http://a.example/hook", "http://b.example/hook"},
+			},
+		},
+		{
+			name: "env overridden by flags",
+			args: []string{"cmd", "-alert-eval-interval=5", "-alert-webhooks=http://c.example/hook, "},
+			env:  map[string]string{"ALERT_RULES_FILE": "/etc/metrics/rules.json", "ALERT_WEBHOOKS": "http://a.example/hook"},
+			expected: Alert{
+				RulesFile:      "/etc/metrics/rules.json",
+				EvalInterval:   5 * time.Second,
+				RepeatInterval: time.Hour,
+				Webhooks:       []string{"http://c.example/hook"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+			resetEnv(envKeys...)
+			t.Cleanup(func() { resetEnv(envKeys...) })
+
+			for k, v := range tt.env {
+				_ = os.Setenv(k, v)
+			}
+
+			os.Args = tt.args
+			cfg, err := ParseServerConfig()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.Alert)
+		})
+	}
+}
+
+func TestParseAgentConfig_Transport(t *testing.T) {
+	envKeys := []string{"TRANSPORT", "GRPC_ADDRESS"}
+
+	tests := []struct {
+		name          string
+		args          []string
+		env           map[string]string
+		wantTransport string
+		wantAddress   string
+	}{
+		{
+			name:          "default values",
+			args:          []string{"cmd"},
+			wantTransport: "http",
+		},
+		{
+			name:          "values from env",
+			args:          []string{"cmd"},
+			env:           map[string]string{"TRANSPORT": "grpc", "GRPC_ADDRESS": "localhost:3200"},
+			wantTransport: "grpc",
+			wantAddress:   "localhost:3200",
+		},
+		{
+			name:          "env overridden by flags",
+			args:          []string{"cmd", "-transport=grpc", "-grpc-address=localhost:3201"},
+			env:           map[string]string{"TRANSPORT": "http", "GRPC_ADDRESS": "localhost:3200"},
+			wantTransport: "grpc",
+			wantAddress:   "localhost:3201",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+			resetEnv(envKeys...)
+			t.Cleanup(func() { resetEnv(envKeys...) })
+
+			for k, v := range tt.env {
+				_ = os.Setenv(k, v)
+			}
+
+			os.Args = tt.args
+			cfg, err := ParseAgentConfig()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTransport, cfg.Transport)
+			assert.Equal(t, tt.wantAddress, cfg.GRPCAddress)
+		})
+	}
+}
+
+func TestParseConfig_CryptoKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		env   map[string]string
+		parse func() (string, error)
+		want  string
+	}{
+		{
+			name: "server default value",
+			args: []string{"cmd"},
+			parse: func() (string, error) {
+				cfg, err := ParseServerConfig()
+				if err != nil {
+					return "", err
+				}
+				return cfg.CryptoKey, nil
+			},
+		},
+		{
+			name: "server env overridden by flag",
+			args: []string{"cmd", "-crypto-key=/keys/flag.pem"},
+			env:  map[string]string{"CRYPTO_KEY": "/keys/env.pem"},
+			parse: func() (string, error) {
+				cfg, err := ParseServerConfig()
+				if err != nil {
+					return "", err
+				}
+				return cfg.CryptoKey, nil
+			},
+			want: "/keys/flag.pem",
+		},
+		{
+			name: "agent value from env",
+			args: []string{"cmd"},
+			env:  map[string]string{"CRYPTO_KEY": "/keys/env.pem"},
+			parse: func() (string, error) {
+				cfg, err := ParseAgentConfig()
+				if err != nil {
+					return "", err
+				}
+				return cfg.CryptoKey, nil
+			},
+			want: "/keys/env.pem",
+		},
+		{
+			name: "agent env overridden by flag",
+			args: []string{"cmd", "-crypto-key=/keys/flag.pem"},
+			env:  map[string]string{"CRYPTO_KEY": "/keys/env.pem"},
+			parse: func() (string, error) {
+				cfg, err := ParseAgentConfig()
+				if err != nil {
+					return "", err
+				}
+				return cfg.CryptoKey, nil
+			},
+			want: "/keys/flag.pem",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+			resetEnv("CRYPTO_KEY")
+			t.Cleanup(func() { resetEnv("CRYPTO_KEY") })
+
+			for k, v := range tt.env {
+				_ = os.Setenv(k, v)
+			}
+
+			os.Args = tt.args
+			got, err := tt.parse()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"os"
+
+	"github.com/gabkaclassic/metrics/pkg/encrypt"
+)
+
+// LoadPublicKey reads the PEM RSA public key the agent encrypts request bodies with.
+//
+// path: Key file path, the agent CryptoKey option.
+//
+// Returns:
+//   - *rsa.PublicKey: Parsed key, nil if path is empty
+//   - error: If the file can't be read or doesn't contain an RSA public key
+func LoadPublicKey(path string) (*rsa.PublicKey, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read public key: %w", err)
+	}
+
+	key, err := encrypt.ParsePublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("load public key %s: %w", path, err)
+	}
+
+	return key, nil
+}
+
+// LoadPrivateKey reads the PEM RSA private key the server decrypts request bodies with.
+//
+// path: Key file path, the server CryptoKey option.
+//
+// Returns:
+//   - *rsa.PrivateKey: Parsed key, nil if path is empty
+//   - error: If the file can't be read or doesn't contain an RSA private key
+func LoadPrivateKey(path string) (*rsa.PrivateKey, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read private key: %w", err)
+	}
+
+	key, err := encrypt.ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("load private key %s: %w", path, err)
+	}
+
+	return key, nil
+}
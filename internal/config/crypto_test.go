@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gabkaclassic/metrics/pkg/encrypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeKeyPair writes a generated key pair to a temporary directory
+// and returns the private and public key paths.
+func writeKeyPair(t *testing.T) (string, string) {
+	t.Helper()
+
+	privatePEM, publicPEM, err := encrypt.GenerateKeyPair(encrypt.MinKeyBits)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, "private.pem")
+	publicPath := filepath.Join(dir, "public.pem")
+	require.NoError(t, os.WriteFile(privatePath, privatePEM, 0o600))
+	require.NoError(t, os.WriteFile(publicPath, publicPEM, 0o644))
+
+	return privatePath, publicPath
+}
+
+func TestLoadKeys(t *testing.T) {
+	privatePath, publicPath := writeKeyPair(t)
+
+	privateKey, err := LoadPrivateKey(privatePath)
+	require.NoError(t, err)
+	publicKey, err := LoadPublicKey(publicPath)
+	require.NoError(t, err)
+
+	assert.True(t, privateKey.PublicKey.Equal(publicKey))
+}
+
+func TestLoadPublicKey(t *testing.T) {
+	privatePath, _ := writeKeyPair(t)
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{name: "empty path"},
+		{name: "missing file", path: filepath.Join(t.TempDir(), "missing.pem"), wantErr: "read public key"},
+		{name: "private key file", path: privatePath, wantErr: `unexpected PEM block type "PRIVATE KEY"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := LoadPublicKey(tt.path)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Nil(t, key)
+		})
+	}
+}
+
+func TestLoadPrivateKey(t *testing.T) {
+	_, publicPath := writeKeyPair(t)
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{name: "empty path"},
+		{name: "missing file", path: filepath.Join(t.TempDir(), "missing.pem"), wantErr: "read private key"},
+		{name: "public key file", path: publicPath, wantErr: `unexpected PEM block type "PUBLIC KEY"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := LoadPrivateKey(tt.path)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Nil(t, key)
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/gabkaclassic/metrics/internal/config"
 	models "github.com/gabkaclassic/metrics/internal/model"
 	"github.com/gabkaclassic/metrics/internal/repository"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
 )
 
 // Dumper handles metrics persistence to and from filesystem.
@@ -132,7 +133,7 @@ func (d *Dumper) Dump(ctx context.Context) error {
 }
 
 // Read restores metrics from dump file to the repository.
-// Reads JSON data, separates counters and gauges, restores concurrently.
+// Reads JSON data, groups metrics by tenant and restores every tenant.
 //
 // Returns:
 //   - error: If file read, unmarshal, or repository operations fail
@@ -141,8 +142,8 @@ func (d *Dumper) Dump(ctx context.Context) error {
 //  1. Read entire file contents
 //  2. Skip if file is empty (no previous dump)
 //  3. Unmarshal JSON to metrics slice
-//  4. Separate counters and gauges
-//  5. Restore counters (AddAll) and gauges (ResetAll) concurrently
+//  4. Group metrics by tenant, metrics without tenant belong to the default one
+//  5. Restore every tenant with restoreTenant
 //  6. Log success or combined error
 //
 // Note: Uses background context since this is typically called at startup.
@@ -161,24 +162,51 @@ func (d *Dumper) Read() error {
 	}
 
 	var metrics []models.Metrics
-	var counters []models.Metrics
-	var gauges []models.Metrics
 	if err := json.Unmarshal(data, &metrics); err != nil {
 		slog.Error("Unmarshal data error", slog.String("error", err.Error()))
 		return err
 	}
 
+	tenants := make(map[string][]models.Metrics)
+	for _, metric := range metrics {
+		tenant := metric.Tenant
+		if tenant == "" {
+			tenant = middleware.DefaultTenant
+		}
+		tenants[tenant] = append(tenants[tenant], metric)
+	}
+
+	for tenant, tenantMetrics := range tenants {
+		ctx := middleware.WithTenant(context.Background(), tenant)
+		if err := d.restoreTenant(ctx, tenantMetrics); err != nil {
+			return fmt.Errorf("restore tenant %s: %w", tenant, err)
+		}
+	}
+
+	slog.Info("Dump restored successfully")
+	return nil
+}
+
+// restoreTenant restores metrics of the context tenant.
+// Separates counters, gauges and mergeable metrics (histograms, summaries and sets)
+// and restores them with AddAll, ResetAll and MergeAll concurrently.
+func (d *Dumper) restoreTenant(ctx context.Context, metrics []models.Metrics) error {
+	var counters []models.Metrics
+	var gauges []models.Metrics
+	var mergeables []models.Metrics
+
 	for _, metric := range metrics {
 		switch metric.MType {
 		case models.Counter:
 			counters = append(counters, metric)
 		case models.Gauge:
 			gauges = append(gauges, metric)
+		case models.Histogram, models.Summary, models.Set:
+			mergeables = append(mergeables, metric)
 		}
 	}
 
-	errChan := make(chan error, 2)
-	ctx := context.Background()
+	errChan := make(chan error, 3)
 	if len(counters) > 0 {
 		go func() { errChan <- d.repository.AddAll(ctx, counters) }()
 	} else {
@@ -191,14 +219,20 @@ func (d *Dumper) Read() error {
 		go func() { errChan <- nil }()
 	}
 
+	if len(mergeables) > 0 {
+		go func() { errChan <- d.repository.MergeAll(ctx, mergeables) }()
+	} else {
+		go func() { errChan <- nil }()
+	}
+
 	err1 := <-errChan
 	err2 := <-errChan
+	err3 := <-errChan
 
-	if err1 != nil || err2 != nil {
-		return fmt.Errorf("save metrics error: counters: %v, gauges: %v", err1, err2)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return fmt.Errorf("save metrics error: %v, %v, %v", err1, err2, err3)
 	}
 
-	slog.Info("Dump restored successfully")
 	return nil
 }
 
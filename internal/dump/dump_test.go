@@ -1,6 +1,7 @@
 package dump
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"os"
@@ -9,8 +10,10 @@ import (
 
 	models "github.com/gabkaclassic/metrics/internal/model"
 	"github.com/gabkaclassic/metrics/internal/repository"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewDumper(t *testing.T) {
@@ -315,3 +318,41 @@ func int64Ptr(v int64) *int64 {
 func float64Ptr(v float64) *float64 {
 	return &v
 }
+
+func TestDumper_Read_tenants(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tenants.json")
+	metrics := []models.Metrics{
+		{ID: "c1", MType: models.Counter, Delta: int64Ptr(1)},
+		{ID: "c1", MType: models.Counter, Delta: int64Ptr(2), Tenant: "team-a"},
+	}
+	data, _ := json.Marshal(metrics)
+	require.NoError(t, os.WriteFile(path, data, 0660))
+
+	repo := repository.NewMockMetricsRepository(t)
+	tenants := make(chan string, 2)
+	repo.EXPECT().AddAll(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, counters []models.Metrics) error {
+			require.Len(t, counters, 1)
+			tenant := middleware.TenantFromCtx(ctx)
+			if tenant == middleware.DefaultTenant {
+				assert.Equal(t, int64(1), *counters[0].Delta)
+			} else {
+				assert.Equal(t, int64(2), *counters[0].Delta)
+			}
+			tenants <- tenant
+			return nil
+		}).Times(2)
+
+	dumper, err := NewDumper(path, repo)
+	require.NoError(t, err)
+	defer dumper.Close()
+
+	require.NoError(t, dumper.Read())
+	close(tenants)
+
+	restored := make([]string, 0, 2)
+	for tenant := range tenants {
+		restored = append(restored, tenant)
+	}
+	assert.ElementsMatch(t, []string{middleware.DefaultTenant, "team-a"}, restored)
+}
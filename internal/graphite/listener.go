@@ -0,0 +1,344 @@
+// Package graphite ingests metrics sent over the Graphite plaintext protocol.
+//
+// The listener accepts TCP connections, parses their lines into metrics
+// and collects them per sender. Collected metrics of a sender are passed to
+// MetricsService.SaveBatch once they fill a batch and once per flush interval,
+// so storage and audit behave exactly like the partial /updates/ endpoint:
+// invalid metrics are rejected one by one without dropping the rest of the batch.
+package graphite
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gabkaclassic/metrics/internal/config"
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/service"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+)
+
+// Listener collects Graphite metrics and saves them in micro-batches.
+type Listener struct {
+	// service stores the collected metrics.
+	service service.MetricsService
+
+	// parser converts received lines to metrics.
+	parser *Parser
+
+	// tenant receives all collected metrics.
+	tenant string
+
+	// batchSize is the number of metrics of a sender saved at once.
+	batchSize int
+
+	// idleTimeout is how long a connection may send nothing before it is closed,
+	// zero keeps idle connections open.
+	idleTimeout time.Duration
+
+	// mu guards pending.
+	mu sync.Mutex
+
+	// pending holds metrics collected since the last flush keyed by sender.
+	pending map[string][]models.Metrics
+
+	// now returns the current time, replaced in tests.
+	now func() time.Time
+}
+
+// NewListener creates a new Graphite listener.
+//
+// service: Metrics service receiving collected metrics
+// cfg: Graphite configuration containing counter patterns, batch size, idle timeout and tenant
+//
+// Returns:
+//   - *Listener: Initialized listener ready for serving
+//   - error: If service is nil, the batch size is not positive or a counter pattern is malformed
+func NewListener(service service.MetricsService, cfg config.Graphite) (*Listener, error) {
+	if service == nil {
+		return nil, errors.New("create graphite listener error: service can't be nil")
+	}
+
+	if cfg.BatchSize <= 0 {
+		return nil, errors.New("create graphite listener error: batch size must be positive")
+	}
+
+	parser, err := NewParser(cfg.CounterPatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	tenant := cfg.Tenant
+	if tenant == "" {
+		tenant = middleware.DefaultTenant
+	}
+
+	return &Listener{
+		service:     service,
+		parser:      parser,
+		tenant:      tenant,
+		batchSize:   cfg.BatchSize,
+		idleTimeout: cfg.IdleTimeout,
+		pending:     make(map[string][]models.Metrics),
+		now:         time.Now,
+	}, nil
+}
+
+// Serve reads lines from conn until it is closed or sends nothing for the idle timeout.
+// Malformed lines are logged and skipped, full batches are saved right away.
+//
+// ctx: Context the batches are saved with
+// conn: Connection to read from
+func (l *Listener) Serve(ctx context.Context, conn net.Conn) {
+	sender := senderOf(conn.RemoteAddr())
+	scanner := bufio.NewScanner(&idleReader{conn: conn, timeout: l.idleTimeout, now: l.now})
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		metric, err := l.parser.ParseLine(line)
+		if err != nil {
+			slog.Warn("Graphite parse error", slog.String("sender", sender), slog.String("error", err.Error()))
+			continue
+		}
+
+		if batch := l.collect(sender, metric); batch != nil {
+			l.save(ctx, sender, batch)
+		}
+	}
+
+	err := scanner.Err()
+	switch {
+	case err == nil, errors.Is(err, net.ErrClosed):
+	case errors.Is(err, os.ErrDeadlineExceeded):
+		slog.Info("Graphite connection idle, closing", slog.String("sender", sender))
+	default:
+		slog.Error("Graphite read error", slog.String("sender", sender), slog.String("error", err.Error()))
+	}
+}
+
+// idleReader reads from a connection, moving its read deadline
+// by the timeout before each read, so that a connection sending nothing
+// for the timeout fails the read with os.ErrDeadlineExceeded.
+type idleReader struct {
+	conn    net.Conn
+	timeout time.Duration
+	now     func() time.Time
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	if r.timeout > 0 {
+		if err := r.conn.SetReadDeadline(r.now().Add(r.timeout)); err != nil {
+			return 0, err
+		}
+	}
+
+	return r.conn.Read(p)
+}
+
+// collect queues a metric of a sender until the next flush.
+// Returns the pending metrics of the sender once they fill a batch.
+func (l *Listener) collect(sender string, metric models.Metrics) []models.Metrics {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pending[sender] = append(l.pending[sender], metric)
+	if len(l.pending[sender]) < l.batchSize {
+		return nil
+	}
+
+	batch := l.pending[sender]
+	delete(l.pending, sender)
+	return batch
+}
+
+// Flush saves metrics collected since the last flush, one batch per sender.
+//
+// ctx: Context the batches are saved with
+func (l *Listener) Flush(ctx context.Context) {
+	l.mu.Lock()
+	pending := l.pending
+	l.pending = make(map[string][]models.Metrics)
+	l.mu.Unlock()
+
+	for sender, metrics := range pending {
+		l.save(ctx, sender, metrics)
+	}
+}
+
+// save saves a batch into the listener tenant,
+// audited with the sender as source IP.
+// Rejected metrics are logged, the rest of the batch is saved.
+func (l *Listener) save(ctx context.Context, sender string, metrics []models.Metrics) {
+	ctx = middleware.WithTenant(ctx, l.tenant)
+	ctx = middleware.WithAuditSource(ctx, sender, l.now().Unix())
+
+	result, err := l.service.SaveBatch(ctx, metrics)
+	if err != nil {
+		slog.Error(
+			"Graphite save error",
+			slog.String("sender", sender),
+			slog.Int("count", len(metrics)),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	for _, rejected := range result.Errors {
+		slog.Warn(
+			"Graphite metric rejected",
+			slog.String("sender", sender),
+			slog.String("id", metrics[rejected.Index].ID),
+			slog.String("error", rejected.Error),
+		)
+	}
+}
+
+// StartListener opens the configured TCP address and serves connections
+// until context cancellation, flushing collected metrics every flush interval.
+// On shutdown open connections are closed and the remainder is flushed.
+//
+// ctx: Context for graceful shutdown (cancellation stops the listener)
+// cfg: Graphite configuration containing address, connection limit and flush interval
+//
+// Returns:
+//   - error: Flush interval, connection limit or socket setup failure details
+func (l *Listener) StartListener(ctx context.Context, cfg config.Graphite) error {
+	if cfg.FlushInterval <= 0 {
+		return errors.New("graphite flush interval must be positive")
+	}
+
+	if cfg.MaxConnections <= 0 {
+		return errors.New("graphite max connections must be positive")
+	}
+
+	listener, err := net.Listen("tcp", cfg.Address)
+	if err != nil {
+		return err
+	}
+	slog.Info("Graphite listener started", slog.String("address", listener.Addr().String()))
+
+	connections := newConnections(cfg.MaxConnections)
+
+	go l.accept(ctx, listener, connections)
+	go l.run(ctx, cfg.FlushInterval, func() {
+		listener.Close()
+		connections.closeAll()
+	})
+
+	return nil
+}
+
+// accept serves incoming connections until the listener is closed,
+// connections above the limit are closed right away.
+func (l *Listener) accept(ctx context.Context, listener net.Listener, connections *connections) {
+	saveCtx := context.WithoutCancel(ctx)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				slog.Error("Graphite accept error", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		if !connections.add(conn) {
+			slog.Warn("Graphite connection rejected, too many connections", slog.String("sender", senderOf(conn.RemoteAddr())))
+			conn.Close()
+			continue
+		}
+
+		go func() {
+			defer connections.remove(conn)
+			l.Serve(saveCtx, conn)
+		}()
+	}
+}
+
+// run flushes collected metrics every interval until context cancellation,
+// then closes the listener and connections and flushes the remainder.
+func (l *Listener) run(ctx context.Context, interval time.Duration, closeAll func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			l.Flush(ctx)
+		case <-ctx.Done():
+			closeAll()
+			l.Flush(context.WithoutCancel(ctx))
+			slog.Info("Graphite listener stopped")
+			return
+		}
+	}
+}
+
+// connections tracks open connections up to a limit.
+type connections struct {
+	mu     sync.Mutex
+	wg     sync.WaitGroup
+	limit  int
+	open   map[net.Conn]struct{}
+	closed bool
+}
+
+func newConnections(limit int) *connections {
+	return &connections{limit: limit, open: make(map[net.Conn]struct{})}
+}
+
+// add registers a connection, returns false when the limit is reached
+// or the listener is shutting down.
+func (c *connections) add(conn net.Conn) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed || len(c.open) >= c.limit {
+		return false
+	}
+
+	c.open[conn] = struct{}{}
+	c.wg.Add(1)
+	return true
+}
+
+// remove closes and forgets a served connection.
+func (c *connections) remove(conn net.Conn) {
+	c.mu.Lock()
+	delete(c.open, conn)
+	c.mu.Unlock()
+
+	conn.Close()
+	c.wg.Done()
+}
+
+// closeAll closes open connections and waits until they are served,
+// so that their metrics are collected before the final flush.
+func (c *connections) closeAll() {
+	c.mu.Lock()
+	c.closed = true
+	for conn := range c.open {
+		conn.Close()
+	}
+	c.mu.Unlock()
+
+	c.wg.Wait()
+}
+
+// senderOf returns the host of the connection peer.
+func senderOf(addr net.Addr) string {
+	if host, _, err := net.SplitHostPort(addr.String()); err == nil {
+		return host
+	}
+
+	return addr.String()
+}
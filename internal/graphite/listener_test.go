@@ -0,0 +1,311 @@
+package graphite
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gabkaclassic/metrics/internal/config"
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/service"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewListener(t *testing.T) {
+	tests := []struct {
+		name           string
+		service        service.MetricsService
+		cfg            config.Graphite
+		expectedTenant string
+		wantErr        string
+	}{
+		{
+			name:           "default tenant",
+			service:        service.NewMockMetricsService(t),
+			cfg:            config.Graphite{BatchSize: 10},
+			expectedTenant: middleware.DefaultTenant,
+		},
+		{
+			name:           "configured tenant",
+			service:        service.NewMockMetricsService(t),
+			cfg:            config.Graphite{BatchSize: 10, Tenant: "legacy"},
+			expectedTenant: "legacy",
+		},
+		{
+			name:    "nil service",
+			cfg:     config.Graphite{BatchSize: 10},
+			wantErr: "create graphite listener error: service can't be nil",
+		},
+		{
+			name:    "invalid batch size",
+			service: service.NewMockMetricsService(t),
+			wantErr: "create graphite listener error: batch size must be positive",
+		},
+		{
+			name:    "invalid counter pattern",
+			service: service.NewMockMetricsService(t),
+			cfg:     config.Graphite{BatchSize: 10, CounterPatterns: []string{"[a-"}},
+			wantErr: `invalid graphite counter pattern "[a-": syntax error in pattern`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := NewListener(tt.service, tt.cfg)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, l)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedTenant, l.tenant)
+			}
+		})
+	}
+}
+
+func TestListener_Serve(t *testing.T) {
+	svc := service.NewMockMetricsService(t)
+	svc.EXPECT().
+		SaveBatch(mock.MatchedBy(func(ctx context.Context) bool {
+			return middleware.AuditIPFromCtx(ctx) == "pipe" && middleware.TenantFromCtx(ctx) == "legacy"
+		}), []models.Metrics{
+			{ID: "web.requests", MType: models.Counter, Delta: int64Ptr(1)},
+			{ID: "web.requests", MType: models.Counter, Delta: int64Ptr(2)},
+		}).
+		Return(models.BatchResult{Accepted: 2}, nil).
+		Once()
+
+	l, err := NewListener(svc, config.Graphite{BatchSize: 2, CounterPatterns: []string{"*.requests"}, Tenant: "legacy"})
+	require.NoError(t, err)
+
+	server, client := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		l.Serve(t.Context(), server)
+		close(done)
+	}()
+
+	_, err = client.Write([]byte("web.requests 1 N\nbroken line here too\n\nweb.requests 2 N\nservers.load 0.5 N\n"))
+	require.NoError(t, err)
+	client.Close()
+	<-done
+
+	assert.Equal(t, map[string][]models.Metrics{
+		"pipe": {{ID: "servers.load", MType: models.Gauge, Value: float64Ptr(0.5)}},
+	}, l.pending)
+}
+
+func TestListener_Serve_idleTimeout(t *testing.T) {
+	l, err := NewListener(service.NewMockMetricsService(t), config.Graphite{BatchSize: 10, IdleTimeout: 20 * time.Millisecond})
+	require.NoError(t, err)
+
+	server, client := net.Pipe()
+	defer client.Close()
+
+	done := make(chan struct{})
+	go func() {
+		l.Serve(t.Context(), server)
+		close(done)
+	}()
+
+	// Each line moves the deadline, the connection is served while it keeps sending
+	for range 3 {
+		_, err = client.Write([]byte("servers.load 0.5 N\n"))
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("idle connection was not closed")
+	}
+
+	assert.Len(t, l.pending["pipe"], 3)
+}
+
+func TestListener_Flush(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name      string
+		pending   map[string][]models.Metrics
+		setupMock func(*service.MockMetricsService)
+	}{
+		{
+			name: "batch per sender",
+			pending: map[string][]models.Metrics{
+				"10.0.0.1": {{ID: "web.requests", MType: models.Counter, Delta: int64Ptr(1)}},
+				"10.0.0.2": {{ID: "servers.load", MType: models.Gauge, Value: float64Ptr(0.5)}},
+			},
+			setupMock: func(svc *service.MockMetricsService) {
+				for sender, metric := range map[string]models.Metrics{
+					"10.0.0.1": {ID: "web.requests", MType: models.Counter, Delta: int64Ptr(1)},
+					"10.0.0.2": {ID: "servers.load", MType: models.Gauge, Value: float64Ptr(0.5)},
+				} {
+					svc.EXPECT().
+						SaveBatch(mock.MatchedBy(func(ctx context.Context) bool {
+							return middleware.AuditIPFromCtx(ctx) == sender &&
+								middleware.AuditTSFromCtx(ctx) == now.Unix() &&
+								middleware.TenantFromCtx(ctx) == "legacy"
+						}), []models.Metrics{metric}).
+						Return(models.BatchResult{Accepted: 1}, nil)
+				}
+			},
+		},
+		{
+			name: "rejected metric is logged",
+			pending: map[string][]models.Metrics{
+				"10.0.0.1": {
+					{ID: "web.requests", MType: models.Counter, Delta: int64Ptr(1)},
+					{ID: "servers.load", MType: models.Gauge},
+				},
+			},
+			setupMock: func(svc *service.MockMetricsService) {
+				svc.EXPECT().
+					SaveBatch(mock.Anything, mock.Anything).
+					Return(models.BatchResult{
+						Accepted: 1,
+						Rejected: 1,
+						Errors:   []models.BatchError{{Index: 1, Error: "gauge value or increment is required"}},
+					}, nil)
+			},
+		},
+		{
+			name: "save error is logged",
+			pending: map[string][]models.Metrics{
+				"10.0.0.1": {{ID: "web.requests", MType: models.Counter, Delta: int64Ptr(1)}},
+			},
+			setupMock: func(svc *service.MockMetricsService) {
+				svc.EXPECT().
+					SaveBatch(mock.Anything, mock.Anything).
+					Return(models.BatchResult{}, api.Internal("save error", errors.New("db error")))
+			},
+		},
+		{
+			name:      "nothing collected",
+			pending:   map[string][]models.Metrics{},
+			setupMock: func(svc *service.MockMetricsService) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewMockMetricsService(t)
+			tt.setupMock(svc)
+
+			l, err := NewListener(svc, config.Graphite{BatchSize: 10, Tenant: "legacy"})
+			require.NoError(t, err)
+			l.now = func() time.Time { return now }
+			l.pending = tt.pending
+
+			l.Flush(t.Context())
+
+			assert.Empty(t, l.pending)
+		})
+	}
+}
+
+func TestListener_StartListener(t *testing.T) {
+	saved := make(chan []models.Metrics, 1)
+	svc := service.NewMockMetricsService(t)
+	svc.EXPECT().
+		SaveBatch(mock.MatchedBy(func(ctx context.Context) bool {
+			return middleware.AuditIPFromCtx(ctx) == "127.0.0.1"
+		}), mock.Anything).
+		RunAndReturn(func(_ context.Context, metrics []models.Metrics) (models.BatchResult, *api.APIError) {
+			saved <- metrics
+			return models.BatchResult{Accepted: len(metrics)}, nil
+		})
+
+	cfg := config.Graphite{
+		Address:         "127.0.0.1:0",
+		CounterPatterns: []string{"*.requests"},
+		BatchSize:       100,
+		FlushInterval:   time.Hour,
+		MaxConnections:  1,
+	}
+
+	probe, err := net.Listen("tcp", cfg.Address)
+	require.NoError(t, err)
+	cfg.Address = probe.Addr().String()
+	probe.Close()
+
+	l, err := NewListener(svc, cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	require.NoError(t, l.StartListener(ctx, cfg))
+
+	conn, err := net.Dial("tcp", cfg.Address)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("web.requests 1 N\nweb.requests 2 N\n"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return len(l.pending["127.0.0.1"]) == 2
+	}, time.Second, time.Millisecond)
+
+	rejected, err := net.Dial("tcp", cfg.Address)
+	require.NoError(t, err)
+	defer rejected.Close()
+
+	rejected.SetReadDeadline(time.Now().Add(time.Second))
+	_, err = rejected.Read(make([]byte, 1))
+	assert.Error(t, err, "connections above the limit are closed")
+
+	cancel()
+
+	select {
+	case metrics := <-saved:
+		assert.Equal(t, []models.Metrics{
+			{ID: "web.requests", MType: models.Counter, Delta: int64Ptr(1)},
+			{ID: "web.requests", MType: models.Counter, Delta: int64Ptr(2)},
+		}, metrics)
+	case <-time.After(time.Second):
+		t.Fatal("metrics were not flushed on shutdown")
+	}
+}
+
+func TestListener_StartListener_invalidConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Graphite
+		wantErr string
+	}{
+		{
+			name:    "invalid flush interval",
+			cfg:     config.Graphite{Address: "127.0.0.1:0", MaxConnections: 1},
+			wantErr: "graphite flush interval must be positive",
+		},
+		{
+			name:    "invalid connection limit",
+			cfg:     config.Graphite{Address: "127.0.0.1:0", FlushInterval: time.Second},
+			wantErr: "graphite max connections must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := NewListener(service.NewMockMetricsService(t), config.Graphite{BatchSize: 10})
+			require.NoError(t, err)
+
+			err = l.StartListener(t.Context(), tt.cfg)
+
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
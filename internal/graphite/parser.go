@@ -0,0 +1,137 @@
+package graphite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+)
+
+// Parser converts Graphite plaintext lines to metrics.
+type Parser struct {
+	// counterPatterns select paths saved as counters.
+	counterPatterns []string
+}
+
+// NewParser creates a new Graphite line parser.
+//
+// counterPatterns: Glob patterns (path.Match syntax) of counter paths,
+// paths matching none of them are gauges
+//
+// Returns:
+//   - *Parser: Initialized parser
+//   - error: If a pattern is malformed
+func NewParser(counterPatterns []string) (*Parser, error) {
+	for _, pattern := range counterPatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid graphite counter pattern %q: %w", pattern, err)
+		}
+	}
+
+	return &Parser{counterPatterns: counterPatterns}, nil
+}
+
+// MetricType returns the type of metrics reported under the path.
+func (p *Parser) MetricType(metricPath string) string {
+	for _, pattern := range p.counterPatterns {
+		if matched, _ := path.Match(pattern, metricPath); matched {
+			return models.Counter
+		}
+	}
+
+	return models.Gauge
+}
+
+// ParseLine parses a single Graphite line of the form
+// path[;tag=value...] value [timestamp].
+//
+// line: Graphite line without the trailing newline
+//
+// Returns:
+//   - models.Metrics: Parsed metric
+//   - error: Line format, value, timestamp or tag errors
+//
+// The dotted path becomes the metric ID and tags become series labels.
+// Counter values are rounded to deltas, gauge values are set as is.
+// Timestamps are Unix seconds, missing timestamps, -1 and N use the server time.
+func (p *Parser) ParseLine(line string) (models.Metrics, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || len(fields) > 3 {
+		return models.Metrics{}, fmt.Errorf("invalid graphite line %q: expected path, value and timestamp", line)
+	}
+
+	metricPath, labels, err := parsePath(fields[0])
+	if err != nil {
+		return models.Metrics{}, fmt.Errorf("invalid graphite line %q: %w", line, err)
+	}
+
+	value, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return models.Metrics{}, fmt.Errorf("invalid graphite line %q: invalid value", line)
+	}
+
+	metric := models.Metrics{ID: metricPath, MType: p.MetricType(metricPath), Labels: labels}
+	if metric.MType == models.Counter {
+		delta := int64(math.Round(value))
+		metric.Delta = &delta
+	} else {
+		metric.Value = &value
+	}
+
+	if len(fields) == 3 {
+		timestamp, err := parseTimestamp(fields[2])
+		if err != nil {
+			return models.Metrics{}, fmt.Errorf("invalid graphite line %q: %w", line, err)
+		}
+		metric.Timestamp = timestamp
+	}
+
+	return metric, nil
+}
+
+// parsePath splits a path into the metric path and tags of the
+// Graphite tag format path;tag=value;tag=value.
+func parsePath(raw string) (string, map[string]string, error) {
+	metricPath, rawTags, tagged := strings.Cut(raw, ";")
+	if metricPath == "" {
+		return "", nil, errors.New("missing path")
+	}
+	if !tagged {
+		return metricPath, nil, nil
+	}
+
+	labels := make(map[string]string)
+	for tag := range strings.SplitSeq(rawTags, ";") {
+		name, value, found := strings.Cut(tag, "=")
+		if !found || name == "" || value == "" {
+			return "", nil, fmt.Errorf("invalid tag %q", tag)
+		}
+		labels[name] = value
+	}
+
+	if err := models.ValidateLabels(labels); err != nil {
+		return "", nil, err
+	}
+
+	return metricPath, labels, nil
+}
+
+// parseTimestamp converts Unix seconds to Unix milliseconds,
+// returning nil for timestamps that ask for the server time.
+func parseTimestamp(raw string) (*int64, error) {
+	if raw == "N" || raw == "-1" {
+		return nil, nil
+	}
+
+	seconds, err := strconv.ParseFloat(raw, 64)
+	if err != nil || seconds < 0 || math.IsInf(seconds, 0) {
+		return nil, errors.New("invalid timestamp")
+	}
+
+	timestamp := int64(math.Round(seconds * 1000))
+	return &timestamp, nil
+}
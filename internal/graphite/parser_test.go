@@ -0,0 +1,153 @@
+package graphite
+
+import (
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func float64Ptr(v float64) *float64 { return &v }
+
+func TestNewParser(t *testing.T) {
+	_, err := NewParser([]string{"*.requests", "errors.*"})
+	assert.NoError(t, err)
+
+	_, err = NewParser([]string{"[a-"})
+	assert.EqualError(t, err, `invalid graphite counter pattern "[a-": syntax error in pattern`)
+}
+
+func TestParser_MetricType(t *testing.T) {
+	parser, err := NewParser([]string{"*.requests", "errors.*"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "web.requests", expected: models.Counter},
+		{path: "web.api.requests", expected: models.Counter},
+		{path: "errors.http", expected: models.Counter},
+		{path: "web.requests.rate", expected: models.Gauge},
+		{path: "servers.web1.load", expected: models.Gauge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parser.MetricType(tt.path))
+		})
+	}
+}
+
+func TestParser_ParseLine(t *testing.T) {
+	parser, err := NewParser([]string{"*.requests"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		line     string
+		expected models.Metrics
+		wantErr  string
+	}{
+		{
+			name: "gauge",
+			line: "servers.web1.load 0.75 1700000000",
+			expected: models.Metrics{
+				ID: "servers.web1.load", MType: models.Gauge,
+				Value: float64Ptr(0.75), Timestamp: int64Ptr(1700000000000),
+			},
+		},
+		{
+			name: "counter",
+			line: "web.requests 12.6 1700000000",
+			expected: models.Metrics{
+				ID: "web.requests", MType: models.Counter,
+				Delta: int64Ptr(13), Timestamp: int64Ptr(1700000000000),
+			},
+		},
+		{
+			name: "fractional timestamp",
+			line: "servers.web1.load 1 1700000000.25",
+			expected: models.Metrics{
+				ID: "servers.web1.load", MType: models.Gauge,
+				Value: float64Ptr(1), Timestamp: int64Ptr(1700000000250),
+			},
+		},
+		{
+			name:     "server time for -1",
+			line:     "servers.web1.load 1 -1",
+			expected: models.Metrics{ID: "servers.web1.load", MType: models.Gauge, Value: float64Ptr(1)},
+		},
+		{
+			name:     "server time for N",
+			line:     "servers.web1.load 1 N",
+			expected: models.Metrics{ID: "servers.web1.load", MType: models.Gauge, Value: float64Ptr(1)},
+		},
+		{
+			name:     "missing timestamp",
+			line:     "servers.web1.load 1",
+			expected: models.Metrics{ID: "servers.web1.load", MType: models.Gauge, Value: float64Ptr(1)},
+		},
+		{
+			name: "tags",
+			line: "disk.used;host=web1;mount=data 42 N",
+			expected: models.Metrics{
+				ID: "disk.used", MType: models.Gauge,
+				Labels: map[string]string{"host": "web1", "mount": "data"},
+				Value:  float64Ptr(42),
+			},
+		},
+		{
+			name:    "missing value",
+			line:    "servers.web1.load",
+			wantErr: `invalid graphite line "servers.web1.load": expected path, value and timestamp`,
+		},
+		{
+			name:    "extra fields",
+			line:    "servers.web1.load 1 1700000000 extra",
+			wantErr: `invalid graphite line "servers.web1.load 1 1700000000 extra": expected path, value and timestamp`,
+		},
+		{
+			name:    "invalid value",
+			line:    "servers.web1.load high N",
+			wantErr: `invalid graphite line "servers.web1.load high N": invalid value`,
+		},
+		{
+			name:    "NaN value",
+			line:    "servers.web1.load NaN N",
+			wantErr: `invalid graphite line "servers.web1.load NaN N": invalid value`,
+		},
+		{
+			name:    "invalid timestamp",
+			line:    "servers.web1.load 1 yesterday",
+			wantErr: `invalid graphite line "servers.web1.load 1 yesterday": invalid timestamp`,
+		},
+		{
+			name:    "invalid tag",
+			line:    "disk.used;host 42 N",
+			wantErr: `invalid graphite line "disk.used;host 42 N": invalid tag "host"`,
+		},
+		{
+			name:    "missing path",
+			line:    ";host=web1 42 N",
+			wantErr: `invalid graphite line ";host=web1 42 N": missing path`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric, err := parser.ParseLine(tt.line)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, metric)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/service"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+)
+
+// AlertHandler serves alert rule and alert state endpoints.
+type AlertHandler struct {
+	service service.AlertService
+}
+
+// NewAlertHandler creates a new alert handler.
+//
+// Returns:
+//   - *AlertHandler: Ready-to-use handler
+//   - error: If service is nil
+func NewAlertHandler(service service.AlertService) (*AlertHandler, error) {
+	if service == nil {
+		return nil, errors.New("create new alert handler failed: service is nil")
+	}
+
+	return &AlertHandler{
+		service: service,
+	}, nil
+}
+
+// GetAlerts retrieves pending and firing alerts.
+//
+// @Summary Get alerts
+// @Description Returns pending and firing alerts of the tenant ordered by rule and series.
+// @Tags Alerts
+// @Produce json
+// @Success 200 {object} api.Envelope{data=[]models.Alert} "Active alerts"
+// @Failure 500 {object} api.ErrorEnvelope "Internal Error"
+// @Router /api/v1/alerts [get]
+func (handler *AlertHandler) GetAlerts(w http.ResponseWriter, r *http.Request) {
+	alerts, err := handler.service.GetAlerts(r.Context())
+
+	if err != nil {
+		api.RespondError(w, err)
+		return
+	}
+
+	api.Respond(w, http.StatusOK, alerts)
+}
+
+// GetRules retrieves all alert rules.
+//
+// @Summary Get alert rules
+// @Description Returns all alert rules of the tenant ordered by ID.
+// @Tags Alerts
+// @Produce json
+// @Success 200 {object} api.Envelope{data=[]models.AlertRule} "Alert rules"
+// @Failure 500 {object} api.ErrorEnvelope "Internal Error"
+// @Router /api/v1/alerts/rules [get]
+func (handler *AlertHandler) GetRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := handler.service.GetRules(r.Context())
+
+	if err != nil {
+		api.RespondError(w, err)
+		return
+	}
+
+	api.Respond(w, http.StatusOK, rules)
+}
+
+// GetRule retrieves an alert rule.
+//
+// @Summary Get alert rule
+// @Description Returns an alert rule of the tenant by its ID.
+// @Tags Alerts
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} api.Envelope{data=models.AlertRule} "Alert rule"
+// @Failure 404 {object} api.ErrorEnvelope "Not Found"
+// @Failure 500 {object} api.ErrorEnvelope "Internal Error"
+// @Router /api/v1/alerts/rules/{id} [get]
+func (handler *AlertHandler) GetRule(w http.ResponseWriter, r *http.Request) {
+	rule, err := handler.service.GetRule(r.Context(), r.PathValue("id"))
+
+	if err != nil {
+		api.RespondError(w, err)
+		return
+	}
+
+	api.Respond(w, http.StatusOK, rule)
+}
+
+// PutRule creates or replaces an alert rule.
+//
+// @Summary Put alert rule
+// @Description Stores an alert rule of the tenant, replacing the rule with the same ID.
+// @Description The rule ID is taken from the path and the tenant from the request, values in the body are ignored.
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param rule body models.AlertRule true "Alert rule"
+// @Success 200 {object} api.Envelope{data=models.AlertRule} "Stored rule"
+// @Failure 400 {object} api.ErrorEnvelope "Bad Request"
+// @Failure 422 {object} api.ErrorEnvelope "Invalid JSON"
+// @Failure 500 {object} api.ErrorEnvelope "Internal Error"
+// @Router /api/v1/alerts/rules/{id} [put]
+func (handler *AlertHandler) PutRule(w http.ResponseWriter, r *http.Request) {
+	rule := models.AlertRule{}
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		api.RespondError(w, api.UnprocessibleEntity("Invalid input JSON"))
+		return
+	}
+	rule.ID = r.PathValue("id")
+
+	stored, err := handler.service.PutRule(r.Context(), rule)
+
+	if err != nil {
+		api.RespondError(w, err)
+		return
+	}
+
+	api.Respond(w, http.StatusOK, stored)
+}
+
+// DeleteRule removes an alert rule.
+//
+// @Summary Delete alert rule
+// @Description Removes an alert rule of the tenant, its firing alerts are resolved on the next evaluation.
+// @Tags Alerts
+// @Param id path string true "Rule ID"
+// @Success 204 "Rule deleted"
+// @Failure 404 {object} api.ErrorEnvelope "Not Found"
+// @Failure 500 {object} api.ErrorEnvelope "Internal Error"
+// @Router /api/v1/alerts/rules/{id} [delete]
+func (handler *AlertHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	if err := handler.service.DeleteRule(r.Context(), r.PathValue("id")); err != nil {
+		api.RespondError(w, err)
+		return
+	}
+
+	api.Respond(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/service"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAlertHandler(t *testing.T) {
+	h, err := NewAlertHandler(service.NewMockAlertService(t))
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+
+	h, err = NewAlertHandler(nil)
+	assert.Error(t, err)
+	assert.Nil(t, h)
+}
+
+func TestAlertHandler_GetAlerts(t *testing.T) {
+	mockService := service.NewMockAlertService(t)
+	mockService.EXPECT().GetAlerts(mock.Anything).Return([]models.Alert{
+		{Rule: "high-heap", ID: "HeapAlloc", MType: models.Gauge, State: models.AlertFiring, Value: 150},
+	}, nil)
+
+	h, err := NewAlertHandler(mockService)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/alerts", nil)
+	rr := httptest.NewRecorder()
+
+	h.GetAlerts(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"rule":"high-heap"`)
+	assert.Contains(t, rr.Body.String(), `"state":"firing"`)
+}
+
+func TestAlertHandler_GetRules(t *testing.T) {
+	mockService := service.NewMockAlertService(t)
+	mockService.EXPECT().GetRules(mock.Anything).Return([]models.AlertRule{
+		{ID: "high-heap", Metric: "HeapAlloc", Op: models.OpGreater, Threshold: 100},
+	}, nil)
+
+	h, err := NewAlertHandler(mockService)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/alerts/rules", nil)
+	rr := httptest.NewRecorder()
+
+	h.GetRules(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"id":"high-heap"`)
+}
+
+func TestAlertHandler_GetRule(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockRule     models.AlertRule
+		mockErr      *api.APIError
+		expectStatus int
+		expectBody   string
+	}{
+		{
+			name:         "found",
+			mockRule:     models.AlertRule{ID: "high-heap", Metric: "HeapAlloc", Op: models.OpGreater},
+			expectStatus: http.StatusOK,
+			expectBody:   `"metric":"HeapAlloc"`,
+		},
+		{
+			name:         "not found",
+			mockErr:      api.NotFound("alert rule high-heap not found"),
+			expectStatus: http.StatusNotFound,
+			expectBody:   "alert rule high-heap not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockAlertService(t)
+			mockService.EXPECT().GetRule(mock.Anything, "high-heap").Return(tt.mockRule, tt.mockErr)
+
+			h, err := NewAlertHandler(mockService)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerts/rules/high-heap", nil)
+			req.SetPathValue("id", "high-heap")
+			rr := httptest.NewRecorder()
+
+			h.GetRule(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectBody)
+		})
+	}
+}
+
+func TestAlertHandler_PutRule(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		expectRule   *models.AlertRule
+		mockErr      *api.APIError
+		expectStatus int
+	}{
+		{
+			name:         "path id overrides body id",
+			body:         `{"id":"other","metric":"HeapAlloc","op":">","threshold":100}`,
+			expectRule:   &models.AlertRule{ID: "high-heap", Metric: "HeapAlloc", Op: models.OpGreater, Threshold: 100},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "service error",
+			body:         `{"metric":"HeapAlloc","op":"~"}`,
+			expectRule:   &models.AlertRule{ID: "high-heap", Metric: "HeapAlloc", Op: "~"},
+			mockErr:      api.BadRequest("unsupported operator"),
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid json",
+			body:         `{"metric":`,
+			expectStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockAlertService(t)
+			if tt.expectRule != nil {
+				mockService.EXPECT().PutRule(mock.Anything, *tt.expectRule).Return(*tt.expectRule, tt.mockErr)
+			}
+
+			h, err := NewAlertHandler(mockService)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/alerts/rules/high-heap", strings.NewReader(tt.body))
+			req.SetPathValue("id", "high-heap")
+			rr := httptest.NewRecorder()
+
+			h.PutRule(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+		})
+	}
+}
+
+func TestAlertHandler_DeleteRule(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockErr      *api.APIError
+		expectStatus int
+	}{
+		{
+			name:         "deleted",
+			expectStatus: http.StatusNoContent,
+		},
+		{
+			name:         "not found",
+			mockErr:      api.NotFound("alert rule high-heap not found"),
+			expectStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockAlertService(t)
+			mockService.EXPECT().DeleteRule(mock.Anything, "high-heap").Return(tt.mockErr)
+
+			h, err := NewAlertHandler(mockService)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/alerts/rules/high-heap", nil)
+			req.SetPathValue("id", "high-heap")
+			rr := httptest.NewRecorder()
+
+			h.DeleteRule(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+		})
+	}
+}
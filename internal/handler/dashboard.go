@@ -0,0 +1,600 @@
+package handler
+
+import (
+	"bytes"
+	"cmp"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+)
+
+// Dashboard sort fields.
+const (
+	dashboardSortID      = "id"
+	dashboardSortType    = "type"
+	dashboardSortValue   = "value"
+	dashboardSortUpdated = "updated"
+)
+
+// maxDashboardRefresh is the longest auto-refresh interval in seconds.
+const maxDashboardRefresh = 3600
+
+// dashboardAssets holds dashboard templates and static files.
+//
+//go:embed dashboard
+var dashboardAssets embed.FS
+
+// dashboardTypes lists metric types in the order their groups are shown.
+var dashboardTypes = []string{models.Counter, models.Gauge, models.Histogram, models.Summary, models.Set}
+
+// dashboardRefreshOptions lists auto-refresh intervals offered by the pages in seconds.
+var dashboardRefreshOptions = []int{0, 5, 15, 30, 60}
+
+var dashboardTemplates = template.Must(
+	template.New("dashboard").
+		Funcs(template.FuncMap{
+			"dict":       dashboardDict,
+			"formatTime": formatDashboardTime,
+		}).
+		ParseFS(dashboardAssets, "dashboard/*.html"),
+)
+
+// newDashboardStatic builds the handler serving embedded static files under /static/.
+//
+// Returns:
+//   - http.Handler: File server of the dashboard/static directory
+//   - error: If the directory can't be opened
+func newDashboardStatic() (http.Handler, error) {
+	static, err := fs.Sub(dashboardAssets, "dashboard/static")
+	if err != nil {
+		return nil, err
+	}
+
+	return http.StripPrefix("/static/", http.FileServerFS(static)), nil
+}
+
+// DashboardPage is the dashboard page model.
+// Series are grouped by type, groups follow dashboardTypes
+// unless series are sorted by type.
+type DashboardPage struct {
+	Filter         DashboardFilter
+	Types          []string
+	RefreshOptions []int
+	Columns        []dashboardColumn
+	Groups         []dashboardGroup
+	Total          int
+	Shown          int
+}
+
+// DashboardFilter holds the dashboard query parameters.
+type DashboardFilter struct {
+	// Query is a case-insensitive substring of series keys.
+	Query string
+
+	// MType restricts series to the metric type, empty matches all types.
+	MType string
+
+	// SortBy is one of dashboardSortID, dashboardSortType,
+	// dashboardSortValue and dashboardSortUpdated.
+	SortBy string
+
+	Descending bool
+
+	// Refresh is the auto-refresh interval in seconds, zero disables it.
+	Refresh int
+}
+
+// SeriesPage is the series detail page model.
+type SeriesPage struct {
+	Metric         models.Metrics
+	Labels         string
+	Value          string
+	Description    string
+	Updated        time.Time
+	Buckets        []seriesBucket
+	Quantiles      []seriesQuantile
+	History        bool
+	Points         []seriesPoint
+	Refresh        int
+	RefreshOptions []int
+}
+
+// dashboardColumn is a table header, sortable columns link to their sort order.
+type dashboardColumn struct {
+	Title      string
+	Link       string
+	Sorted     bool
+	Descending bool
+}
+
+// dashboardGroup holds the rows of a metric type.
+type dashboardGroup struct {
+	MType string
+	Rows  []dashboardRow
+}
+
+// dashboardRow is a single series of the dashboard.
+type dashboardRow struct {
+	ID      string
+	MType   string
+	Labels  string
+	Value   string
+	Unit    string
+	Updated time.Time
+	Link    string
+}
+
+// seriesBucket is a histogram bucket with its upper bound.
+type seriesBucket struct {
+	Bound string
+	Count int64
+}
+
+// seriesQuantile is an estimated summary quantile.
+type seriesQuantile struct {
+	Quantile string
+	Value    float64
+}
+
+// seriesPoint is a recorded counter delta or gauge value.
+type seriesPoint struct {
+	Time  time.Time
+	Value string
+}
+
+// GetAll renders the metrics dashboard.
+//
+// @Summary Metrics dashboard (HTML)
+// @Description Returns series of the tenant as an HTML dashboard grouped by metric type.
+// @Description Series can be filtered by a key substring and type, sorted by ID, type, value or last update,
+// @Description and the page can reload itself periodically. Each series links to its detail page.
+// @Description Units are taken from metric metadata.
+// @Tags Metrics
+// @Produce text/html
+// @Param q query string false "Case-insensitive substring of series keys"
+// @Param type query string false "Metric type" Enums(gauge,counter,histogram,summary,set)
+// @Param sort query string false "Sort column (default: id)" Enums(id,type,value,updated)
+// @Param order query string false "Sort order (default: asc)" Enums(asc,desc)
+// @Param refresh query int false "Auto-refresh interval in seconds, 0 disables it" minimum(0) maximum(3600)
+// @Success 200 "HTML dashboard"
+// @Failure 400 {object} api.APIError "Bad Request"
+// @Failure 500 {object} api.APIError "Internal Error"
+// @Router / [get]
+func (handler *MetricsHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseDashboardFilter(r.URL.Query())
+	if err != nil {
+		api.RespondError(w, api.BadRequest(err.Error()))
+		return
+	}
+
+	metrics, getErr := handler.service.GetAllMetrics(r.Context())
+	if getErr != nil {
+		api.RespondError(w, getErr)
+		return
+	}
+
+	units, getErr := handler.service.GetUnits(r.Context())
+	if getErr != nil {
+		api.RespondError(w, getErr)
+		return
+	}
+
+	updated, getErr := handler.service.GetUpdated(r.Context())
+	if getErr != nil {
+		api.RespondError(w, getErr)
+		return
+	}
+
+	groups, shown := dashboardGroups(metrics, units, updated, filter)
+
+	renderDashboard(w, "index.html", DashboardPage{
+		Filter:         filter,
+		Types:          dashboardTypes,
+		RefreshOptions: dashboardRefreshOptions,
+		Columns:        dashboardColumns(filter),
+		Groups:         groups,
+		Total:          len(metrics),
+		Shown:          shown,
+	})
+}
+
+// Series renders the detail page of a series.
+//
+// @Summary Series detail page (HTML)
+// @Description Returns an HTML page with the current value, unit, description and last update of a series,
+// @Description histogram buckets, summary quantiles and, for counters and gauges, values recorded in the last hour.
+// @Tags Metrics
+// @Produce text/html
+// @Param type path string true "Metric type" Enums(gauge,counter,histogram,summary,set)
+// @Param id path string true "Metric ID"
+// @Param label query []string false "Series label as name=value" collectionFormat(multi)
+// @Param refresh query int false "Auto-refresh interval in seconds, 0 disables it" minimum(0) maximum(3600)
+// @Success 200 "HTML series page"
+// @Failure 400 {object} api.APIError "Bad Request"
+// @Failure 404 {object} api.APIError "Not Found"
+// @Failure 500 {object} api.APIError "Internal Error"
+// @Router /series/{type}/{id} [get]
+func (handler *MetricsHandler) Series(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	labels, err := parseLabels(values["label"])
+	if err != nil {
+		api.RespondError(w, api.BadRequest(err.Error()))
+		return
+	}
+
+	refresh, err := parseDashboardRefresh(values.Get("refresh"))
+	if err != nil {
+		api.RespondError(w, api.BadRequest(err.Error()))
+		return
+	}
+
+	metric, getErr := handler.service.GetStruct(r.Context(), r.PathValue("id"), r.PathValue("type"), labels)
+	if getErr != nil {
+		api.RespondError(w, getErr)
+		return
+	}
+
+	descriptions, getErr := handler.service.GetDescriptions(r.Context())
+	if getErr != nil {
+		api.RespondError(w, getErr)
+		return
+	}
+
+	updated, getErr := handler.service.GetUpdated(r.Context())
+	if getErr != nil {
+		api.RespondError(w, getErr)
+		return
+	}
+
+	page := SeriesPage{
+		Metric:         metric,
+		Labels:         models.FormatLabels(metric.Labels),
+		Value:          displayValue(metric),
+		Description:    descriptions[metric.ID],
+		Updated:        updated[metric.Key()],
+		Buckets:        seriesBuckets(metric),
+		Quantiles:      seriesQuantiles(metric),
+		History:        models.HasHistory(metric.MType),
+		Refresh:        refresh,
+		RefreshOptions: dashboardRefreshOptions,
+	}
+
+	if page.History {
+		now := time.Now()
+		points, rangeErr := handler.service.GetRange(r.Context(), models.RangeQuery{
+			ID:     metric.ID,
+			MType:  metric.MType,
+			Labels: metric.Labels,
+			From:   now.Add(-time.Hour),
+			To:     now,
+		})
+		if rangeErr != nil {
+			api.RespondError(w, rangeErr)
+			return
+		}
+		page.Points = seriesPoints(metric.MType, points)
+	}
+
+	renderDashboard(w, "series.html", page)
+}
+
+// Static serves embedded dashboard assets.
+func (handler *MetricsHandler) Static(w http.ResponseWriter, r *http.Request) {
+	handler.static.ServeHTTP(w, r)
+}
+
+// renderDashboard executes a dashboard template into a buffer,
+// so template errors are reported instead of a partial page.
+func renderDashboard(w http.ResponseWriter, name string, data any) {
+	var buffer bytes.Buffer
+	if err := dashboardTemplates.ExecuteTemplate(&buffer, name, data); err != nil {
+		api.RespondError(w, api.Internal("failed to render template", err))
+		return
+	}
+
+	_, _ = buffer.WriteTo(w)
+}
+
+// parseDashboardFilter builds a dashboard filter from URL query parameters.
+func parseDashboardFilter(values url.Values) (DashboardFilter, error) {
+	filter := DashboardFilter{
+		Query:  strings.TrimSpace(values.Get("q")),
+		MType:  values.Get("type"),
+		SortBy: cmp.Or(values.Get("sort"), dashboardSortID),
+	}
+
+	if filter.MType != "" && !slices.Contains(dashboardTypes, filter.MType) {
+		return DashboardFilter{}, fmt.Errorf("invalid type %q", filter.MType)
+	}
+
+	switch filter.SortBy {
+	case dashboardSortID, dashboardSortType, dashboardSortValue, dashboardSortUpdated:
+	default:
+		return DashboardFilter{}, fmt.Errorf("invalid sort %q, expected id, type, value or updated", filter.SortBy)
+	}
+
+	switch order := values.Get("order"); order {
+	case "", "asc":
+	case "desc":
+		filter.Descending = true
+	default:
+		return DashboardFilter{}, fmt.Errorf("invalid order %q, expected asc or desc", order)
+	}
+
+	refresh, err := parseDashboardRefresh(values.Get("refresh"))
+	if err != nil {
+		return DashboardFilter{}, err
+	}
+	filter.Refresh = refresh
+
+	return filter, nil
+}
+
+// parseDashboardRefresh parses an auto-refresh interval in seconds.
+// An empty value disables auto-refresh.
+func parseDashboardRefresh(raw string) (int, error) {
+	if raw == "" {
+		return 0, nil
+	}
+
+	refresh, err := strconv.Atoi(raw)
+	if err != nil || refresh < 0 || refresh > maxDashboardRefresh {
+		return 0, fmt.Errorf("invalid refresh %q, expected 0 to %d seconds", raw, maxDashboardRefresh)
+	}
+
+	return refresh, nil
+}
+
+// Link returns the dashboard URL of the filter.
+// Default parameters are omitted.
+func (filter DashboardFilter) Link() string {
+	values := url.Values{}
+	if filter.Query != "" {
+		values.Set("q", filter.Query)
+	}
+	if filter.MType != "" {
+		values.Set("type", filter.MType)
+	}
+	if filter.SortBy != dashboardSortID {
+		values.Set("sort", filter.SortBy)
+	}
+	if filter.Descending {
+		values.Set("order", "desc")
+	}
+	if filter.Refresh > 0 {
+		values.Set("refresh", strconv.Itoa(filter.Refresh))
+	}
+
+	if len(values) == 0 {
+		return "/"
+	}
+	return "/?" + values.Encode()
+}
+
+// dashboardColumns returns table headers for the filter.
+// Sorting by the current sort column again reverses the order.
+func dashboardColumns(filter DashboardFilter) []dashboardColumn {
+	column := func(title, sortBy string) dashboardColumn {
+		sorted := filter
+		sorted.SortBy = sortBy
+		sorted.Descending = filter.SortBy == sortBy && !filter.Descending
+
+		return dashboardColumn{
+			Title:      title,
+			Link:       sorted.Link(),
+			Sorted:     filter.SortBy == sortBy,
+			Descending: filter.Descending,
+		}
+	}
+
+	return []dashboardColumn{
+		column("Metric", dashboardSortID),
+		column("Type", dashboardSortType),
+		column("Value", dashboardSortValue),
+		{Title: "Unit"},
+		column("Last update", dashboardSortUpdated),
+	}
+}
+
+// dashboardGroups filters and sorts the series and groups them by type.
+//
+// Returns the groups and the number of shown series.
+func dashboardGroups(metrics []models.Metrics, units map[string]string, updated map[string]time.Time, filter DashboardFilter) ([]dashboardGroup, int) {
+	query := strings.ToLower(filter.Query)
+	selected := make([]models.Metrics, 0, len(metrics))
+	for _, metric := range metrics {
+		if filter.MType != "" && metric.MType != filter.MType {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(metric.Key()), query) {
+			continue
+		}
+		selected = append(selected, metric)
+	}
+
+	slices.SortStableFunc(selected, func(a, b models.Metrics) int {
+		order := compareDashboardSeries(a, b, filter.SortBy, updated)
+		if filter.Descending {
+			return -order
+		}
+		return order
+	})
+
+	groups := make([]dashboardGroup, 0, len(dashboardTypes))
+	index := make(map[string]int, len(dashboardTypes))
+	for _, metric := range selected {
+		i, exists := index[metric.MType]
+		if !exists {
+			i = len(groups)
+			index[metric.MType] = i
+			groups = append(groups, dashboardGroup{MType: metric.MType})
+		}
+
+		groups[i].Rows = append(groups[i].Rows, dashboardRow{
+			ID:      metric.ID,
+			MType:   metric.MType,
+			Labels:  models.FormatLabels(metric.Labels),
+			Value:   displayValue(metric),
+			Unit:    units[metric.ID],
+			Updated: updated[metric.Key()],
+			Link:    seriesLink(metric),
+		})
+	}
+
+	if filter.SortBy != dashboardSortType {
+		slices.SortStableFunc(groups, func(a, b dashboardGroup) int {
+			return cmp.Compare(dashboardTypeOrder(a.MType), dashboardTypeOrder(b.MType))
+		})
+	}
+
+	return groups, len(selected)
+}
+
+// compareDashboardSeries orders series by the sort column, then by series key.
+func compareDashboardSeries(a, b models.Metrics, sortBy string, updated map[string]time.Time) int {
+	var order int
+	switch sortBy {
+	case dashboardSortType:
+		order = cmp.Compare(a.MType, b.MType)
+	case dashboardSortValue:
+		aValue, _ := models.AlertValue(a)
+		bValue, _ := models.AlertValue(b)
+		order = cmp.Compare(aValue, bValue)
+	case dashboardSortUpdated:
+		order = updated[a.Key()].Compare(updated[b.Key()])
+	}
+
+	return cmp.Or(order, cmp.Compare(a.Key(), b.Key()))
+}
+
+// dashboardTypeOrder returns the position of the type group,
+// unknown types are shown last.
+func dashboardTypeOrder(mType string) int {
+	if i := slices.Index(dashboardTypes, mType); i >= 0 {
+		return i
+	}
+	return len(dashboardTypes)
+}
+
+// seriesLink returns the detail page URL of the series.
+func seriesLink(metric models.Metrics) string {
+	link := "/series/" + url.PathEscape(metric.MType) + "/" + url.PathEscape(metric.ID)
+	if len(metric.Labels) == 0 {
+		return link
+	}
+
+	values := url.Values{}
+	for _, name := range slices.Sorted(maps.Keys(metric.Labels)) {
+		values.Add("label", name+"="+metric.Labels[name])
+	}
+	return link + "?" + values.Encode()
+}
+
+// displayValue renders the current value of a series:
+// counter totals, gauge values, histogram and summary counts with sums
+// and set cardinality estimates.
+func displayValue(metric models.Metrics) string {
+	switch metric.MType {
+	case models.Counter:
+		if metric.Delta != nil {
+			return strconv.FormatInt(*metric.Delta, 10)
+		}
+	case models.Gauge:
+		if metric.Value != nil {
+			return strconv.FormatFloat(*metric.Value, 'g', -1, 64)
+		}
+	case models.Histogram:
+		return metric.Snapshot().String()
+	case models.Summary:
+		return metric.SummarySnapshot().String()
+	case models.Set:
+		return strconv.FormatInt(metric.Cardinality(), 10)
+	}
+	return ""
+}
+
+// seriesBuckets returns histogram buckets with their upper bounds,
+// the last bucket is unbounded.
+func seriesBuckets(metric models.Metrics) []seriesBucket {
+	if metric.MType != models.Histogram {
+		return nil
+	}
+
+	buckets := make([]seriesBucket, 0, len(metric.Buckets))
+	for i, count := range metric.Buckets {
+		bound := "+Inf"
+		if i < len(metric.Bounds) {
+			bound = strconv.FormatFloat(metric.Bounds[i], 'g', -1, 64)
+		}
+		buckets = append(buckets, seriesBucket{Bound: bound, Count: count})
+	}
+	return buckets
+}
+
+// seriesQuantiles returns estimated summary quantiles in ascending order.
+func seriesQuantiles(metric models.Metrics) []seriesQuantile {
+	if metric.MType != models.Summary {
+		return nil
+	}
+
+	quantiles := make([]seriesQuantile, 0, len(metric.Quantiles))
+	for _, quantile := range slices.Sorted(maps.Keys(metric.Quantiles)) {
+		quantiles = append(quantiles, seriesQuantile{Quantile: quantile, Value: metric.Quantiles[quantile]})
+	}
+	return quantiles
+}
+
+// seriesPoints returns recorded counter deltas or gauge values, newest first.
+func seriesPoints(mType string, points []models.Point) []seriesPoint {
+	result := make([]seriesPoint, 0, len(points))
+	for _, point := range slices.Backward(points) {
+		value := ""
+		switch {
+		case mType == models.Counter && point.Delta != nil:
+			value = strconv.FormatInt(*point.Delta, 10)
+		case mType == models.Gauge && point.Value != nil:
+			value = strconv.FormatFloat(*point.Value, 'g', -1, 64)
+		}
+		result = append(result, seriesPoint{Time: time.UnixMilli(point.Timestamp), Value: value})
+	}
+	return result
+}
+
+// dashboardDict builds a map from name and value pairs,
+// passing several values to a nested template.
+func dashboardDict(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("dict expects name and value pairs")
+	}
+
+	dict := make(map[string]any, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		name, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict name %v is not a string", pairs[i])
+		}
+		dict[name] = pairs[i+1]
+	}
+	return dict, nil
+}
+
+// formatDashboardTime renders a time in UTC, the zero time as a dash.
+func formatDashboardTime(t time.Time) string {
+	if t.IsZero() {
+		return "—"
+	}
+	return t.UTC().Format(time.DateTime) + " UTC"
+}
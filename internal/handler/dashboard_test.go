@@ -0,0 +1,287 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/service"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func dashboardMetrics() []models.Metrics {
+	return []models.Metrics{
+		{ID: "PollCount", MType: models.Gauge, Value: floatPtr(7)},
+		{ID: "HeapAlloc", MType: models.Gauge, Value: floatPtr(2048), Labels: map[string]string{"host": "a"}},
+		{ID: "HeapAlloc", MType: models.Gauge, Value: floatPtr(1024)},
+		{ID: "Requests", MType: models.Counter, Delta: intPtr(42)},
+	}
+}
+
+func TestMetricsHandler_GetAll(t *testing.T) {
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		url            string
+		mockMetrics    []models.Metrics
+		mockErr        *api.APIError
+		expectCalls    bool
+		expectedStatus int
+		expectedOrder  []string
+		expectedBody   []string
+		absentBody     []string
+	}{
+		{
+			name:           "grouped and sorted by id",
+			url:            "/",
+			mockMetrics:    dashboardMetrics(),
+			expectCalls:    true,
+			expectedStatus: http.StatusOK,
+			expectedOrder:  []string{"<h2>counter", "Requests", "<h2>gauge", `HeapAlloc</a> <span class="labels">`, "PollCount"},
+			expectedBody: []string{
+				"Showing 4 of 4 series",
+				`{host=&#34;a&#34;}`,
+				"<td>bytes</td>",
+				"2026-01-02 03:04:05 UTC",
+				`href="/series/gauge/HeapAlloc?label=host%3Da"`,
+				`href="/series/counter/Requests"`,
+				`<a href="/?order=desc" class="sorted">Metric</a>`,
+			},
+			absentBody: []string{`http-equiv="refresh"`},
+		},
+		{
+			name:           "sorted by value descending",
+			url:            "/?sort=value&order=desc",
+			mockMetrics:    dashboardMetrics(),
+			expectCalls:    true,
+			expectedStatus: http.StatusOK,
+			expectedOrder:  []string{"<h2>counter", "<h2>gauge", "2048", "1024", ">7<"},
+			expectedBody:   []string{`<a href="/?sort=value" class="sorted desc">Value</a>`},
+		},
+		{
+			name:           "filtered by query and type",
+			url:            "/?q=heap&type=gauge&refresh=30",
+			mockMetrics:    dashboardMetrics(),
+			expectCalls:    true,
+			expectedStatus: http.StatusOK,
+			expectedBody: []string{
+				"Showing 2 of 4 series",
+				`<meta http-equiv="refresh" content="30">`,
+				`<option value="30" selected>30s</option>`,
+			},
+			absentBody: []string{"PollCount", "Requests", "<h2>counter"},
+		},
+		{
+			name:           "empty",
+			url:            "/",
+			mockMetrics:    []models.Metrics{},
+			expectCalls:    true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{"No metrics found"},
+		},
+		{
+			name:           "invalid sort",
+			url:            "/?sort=unit",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid refresh",
+			url:            "/?refresh=-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "service error",
+			url:            "/",
+			mockErr:        api.Internal("some error", errors.New("some error")),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetricsService(t)
+			if tt.expectCalls || tt.mockErr != nil {
+				mockService.EXPECT().GetAllMetrics(mock.Anything).Return(tt.mockMetrics, tt.mockErr)
+			}
+			if tt.expectCalls {
+				mockService.EXPECT().GetUnits(mock.Anything).Return(map[string]string{"HeapAlloc": "bytes"}, nil)
+				mockService.EXPECT().GetUpdated(mock.Anything).Return(map[string]time.Time{"Requests": updatedAt}, nil)
+			}
+
+			handler, err := NewMetricsHandler(mockService)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rec := httptest.NewRecorder()
+
+			handler.GetAll(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			body := rec.Body.String()
+			for _, expected := range tt.expectedBody {
+				assert.Contains(t, body, expected)
+			}
+			for _, absent := range tt.absentBody {
+				assert.NotContains(t, body, absent)
+			}
+
+			position := 0
+			for _, expected := range tt.expectedOrder {
+				index := strings.Index(body[position:], expected)
+				require.GreaterOrEqual(t, index, 0, "%q not found after position %d", expected, position)
+				position += index + len(expected)
+			}
+		})
+	}
+}
+
+func TestMetricsHandler_Series(t *testing.T) {
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		url            string
+		mType          string
+		labels         map[string]string
+		mockMetric     models.Metrics
+		mockErr        *api.APIError
+		expectRange    bool
+		expectedStatus int
+		expectedBody   []string
+	}{
+		{
+			name:   "gauge with history",
+			url:    "/series/gauge/HeapAlloc?label=host=a&refresh=5",
+			mType:  models.Gauge,
+			labels: map[string]string{"host": "a"},
+			mockMetric: models.Metrics{
+				ID: "HeapAlloc", MType: models.Gauge, Value: floatPtr(2048),
+				Labels: map[string]string{"host": "a"}, Unit: "bytes",
+			},
+			expectRange:    true,
+			expectedStatus: http.StatusOK,
+			expectedBody: []string{
+				"<td>bytes</td>",
+				"<td>Heap size</td>",
+				"2026-01-02 03:04:05 UTC",
+				"2023-11-14 22:13:20 UTC",
+				`<input type="hidden" name="label" value="host=a">`,
+				`<meta http-equiv="refresh" content="5">`,
+			},
+		},
+		{
+			name:  "histogram buckets",
+			url:   "/series/histogram/latency",
+			mType: models.Histogram,
+			mockMetric: models.Metrics{
+				ID: "latency", MType: models.Histogram,
+				Bounds: []float64{0.1, 0.5}, Buckets: []int64{3, 5, 1},
+				Sum: floatPtr(2.75), Count: intPtr(9),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{"count=9 sum=2.75", "<td>0.5</td>", "<td>&#43;Inf</td>"},
+		},
+		{
+			name:           "not found",
+			url:            "/series/gauge/missing",
+			mType:          models.Gauge,
+			mockErr:        api.NotFound("metric missing gauge not found"),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid label",
+			url:            "/series/gauge/HeapAlloc?label=host",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetricsService(t)
+			if tt.mType != "" {
+				mockService.EXPECT().GetStruct(mock.Anything, mock.Anything, tt.mType, tt.labels).Return(tt.mockMetric, tt.mockErr)
+			}
+			if tt.mockErr == nil && tt.mType != "" {
+				mockService.EXPECT().GetDescriptions(mock.Anything).Return(map[string]string{"HeapAlloc": "Heap size"}, nil)
+				mockService.EXPECT().GetUpdated(mock.Anything).Return(map[string]time.Time{`HeapAlloc{host="a"}`: updatedAt}, nil)
+			}
+			if tt.expectRange {
+				mockService.EXPECT().GetRange(mock.Anything, mock.MatchedBy(func(query models.RangeQuery) bool {
+					return query.ID == tt.mockMetric.ID && query.MType == tt.mType && query.To.Sub(query.From) == time.Hour
+				})).Return([]models.Point{{Timestamp: 1700000000000, Value: floatPtr(1024)}}, nil)
+			}
+
+			handler, err := NewMetricsHandler(mockService)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			parts := strings.Split(strings.Split(tt.url, "?")[0], "/")
+			req.SetPathValue("type", parts[2])
+			req.SetPathValue("id", parts[3])
+			rec := httptest.NewRecorder()
+
+			handler.Series(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			for _, expected := range tt.expectedBody {
+				assert.Contains(t, rec.Body.String(), expected)
+			}
+		})
+	}
+}
+
+func TestMetricsHandler_Static(t *testing.T) {
+	handler, err := NewMetricsHandler(service.NewMockMetricsService(t))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/static/dashboard.css", nil)
+	rec := httptest.NewRecorder()
+
+	handler.Static(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "border-collapse")
+
+	req = httptest.NewRequest(http.MethodGet, "/static/missing.css", nil)
+	rec = httptest.NewRecorder()
+
+	handler.Static(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDashboardFilter_Link(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   DashboardFilter
+		expected string
+	}{
+		{
+			name:     "defaults",
+			filter:   DashboardFilter{SortBy: dashboardSortID},
+			expected: "/",
+		},
+		{
+			name: "all parameters",
+			filter: DashboardFilter{
+				Query: "heap alloc", MType: models.Gauge, SortBy: dashboardSortUpdated, Descending: true, Refresh: 15,
+			},
+			expected: "/?order=desc&q=heap+alloc&refresh=15&sort=updated&type=gauge",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.filter.Link())
+		})
+	}
+}
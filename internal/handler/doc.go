@@ -4,7 +4,13 @@
 //   - Plain-text REST endpoints
 //   - JSON-based API
 //   - Batch operations
-//   - HTML metrics rendering
+//   - Idempotency keys for write requests
+//   - Versioned /api/v1 endpoints with JSON response envelopes
+//   - Bulk CSV, NDJSON and JSON import and export
+//   - InfluxDB line protocol writes at /api/v2/write
+//   - OTLP/HTTP JSON metric exports at /v1/metrics
+//   - Counter rates and gauge derivatives over recent samples at /api/v1/rate/{id}
+//   - HTML dashboard with series detail pages
 //
 // Base URL: /
 //
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	models "github.com/gabkaclassic/metrics/internal/model"
 	api "github.com/gabkaclassic/metrics/pkg/error"
@@ -20,14 +21,53 @@ func (s *stubService) Save(ctx context.Context, id, mtype, value string) *api.AP
 func (s *stubService) SaveAll(ctx context.Context, metrics []models.Metrics) *api.APIError {
 	return nil
 }
+func (s *stubService) Import(ctx context.Context, records []models.ImportRecord, options models.ImportOptions) (models.ImportReport, *api.APIError) {
+	return models.ImportReport{Total: len(records), Imported: len(records), DryRun: options.DryRun}, nil
+}
+func (s *stubService) SaveBatch(ctx context.Context, metrics []models.Metrics) (models.BatchResult, *api.APIError) {
+	return models.BatchResult{Accepted: len(metrics)}, nil
+}
 func (s *stubService) SaveStruct(ctx context.Context, m models.Metrics) *api.APIError { return nil }
 func (s *stubService) Get(ctx context.Context, id, mtype string) (any, *api.APIError) { return 42, nil }
-func (s *stubService) GetStruct(ctx context.Context, id, mtype string) (models.Metrics, *api.APIError) {
+func (s *stubService) GetStruct(ctx context.Context, id, mtype string, labels map[string]string) (models.Metrics, *api.APIError) {
 	return models.Metrics{ID: id, MType: mtype}, nil
 }
 func (s *stubService) GetAll(ctx context.Context) (map[string]any, *api.APIError) {
 	return map[string]any{"m1": floatPtr(1.23)}, nil
 }
+func (s *stubService) GetUnits(ctx context.Context) (map[string]string, *api.APIError) {
+	return map[string]string{"m1": "bytes"}, nil
+}
+func (s *stubService) GetAllMetrics(ctx context.Context) ([]models.Metrics, *api.APIError) {
+	return []models.Metrics{{ID: "m1", MType: models.Gauge, Value: floatPtr(1.23)}}, nil
+}
+func (s *stubService) ExportPage(ctx context.Context, after *models.SeriesRef) ([]models.Metrics, *api.APIError) {
+	return []models.Metrics{{ID: "m1", MType: models.Gauge, Value: floatPtr(1.23)}}, nil
+}
+func (s *stubService) GetDescriptions(ctx context.Context) (map[string]string, *api.APIError) {
+	return map[string]string{"m1": "Example gauge"}, nil
+}
+func (s *stubService) GetUpdated(ctx context.Context) (map[string]time.Time, *api.APIError) {
+	return map[string]time.Time{"m1": time.UnixMilli(1700000000000)}, nil
+}
+func (s *stubService) List(ctx context.Context, query models.ListQuery) (models.MetricsPage, *api.APIError) {
+	return models.MetricsPage{Metrics: []models.Metrics{{ID: "m1", MType: "gauge", Value: floatPtr(1.23)}}}, nil
+}
+func (s *stubService) GetMany(ctx context.Context, refs []models.SeriesRef) ([]models.Metrics, *api.APIError) {
+	return []models.Metrics{{ID: "m1", MType: "gauge", Value: floatPtr(1.23)}}, nil
+}
+func (s *stubService) GetRange(ctx context.Context, query models.RangeQuery) ([]models.Point, *api.APIError) {
+	return []models.Point{{Timestamp: query.From.UnixMilli(), Value: floatPtr(1.23)}}, nil
+}
+func (s *stubService) Rate(ctx context.Context, query models.RateQuery) (models.Rate, *api.APIError) {
+	return models.NewRate(query, nil), nil
+}
+func (s *stubService) Delete(ctx context.Context, id, mType string) *api.APIError {
+	return nil
+}
+func (s *stubService) DeleteAll(ctx context.Context, pattern, mType string) (models.DeleteResult, *api.APIError) {
+	return models.DeleteResult{Deleted: []string{"m1"}}, nil
+}
 
 // ExampleMetricsHandler_Save shows how to call the Save endpoint (plain-text).
 func ExampleMetricsHandler_Save() {
@@ -114,4 +154,58 @@ func ExampleMetricsHandler_GetAll() {
 	// Output: 200
 }
 
+// ExampleMetricsHandler_GetRange shows how to call the GetRange endpoint.
+func ExampleMetricsHandler_GetRange() {
+	svc := &stubService{}
+	h, _ := NewMetricsHandler(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/range?id=mygauge&type=gauge&from=1700000000000&to=1700000060000&step=10s", nil)
+	w := httptest.NewRecorder()
+
+	h.GetRange(w, req)
+
+	fmt.Println(w.Code)
+	fmt.Print(w.Body.String())
+	// Output:
+	// 200
+	// {"data":[{"timestamp":1700000000000,"value":1.23}]}
+}
+
+// ExampleMetricsHandler_GetMany shows how to call the GetMany endpoint.
+func ExampleMetricsHandler_GetMany() {
+	svc := &stubService{}
+	h, _ := NewMetricsHandler(svc)
+
+	body, _ := json.Marshal([]models.SeriesRef{{ID: "m1", MType: "gauge"}})
+
+	req := httptest.NewRequest(http.MethodPost, "/values", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	h.GetMany(w, req)
+
+	fmt.Println(w.Code)
+	fmt.Print(w.Body.String())
+	// Output:
+	// 200
+	// [{"id":"m1","type":"gauge","value":1.23}]
+}
+
+// ExampleMetricsHandler_List shows how to call the List endpoint.
+func ExampleMetricsHandler_List() {
+	svc := &stubService{}
+	h, _ := NewMetricsHandler(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/values?type=gauge&prefix=m&limit=10", nil)
+	w := httptest.NewRecorder()
+
+	h.List(w, req)
+
+	fmt.Println(w.Code)
+	fmt.Print(w.Body.String())
+	// Output:
+	// 200
+	// {"data":{"metrics":[{"id":"m1","type":"gauge","value":1.23}]}}
+}
+
 func floatPtr(f float64) *float64 { return &f }
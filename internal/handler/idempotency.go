@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/service"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+)
+
+// IdempotentReplayedHeader marks responses replayed for a repeated idempotency key.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// IdempotencyHandler replays responses of write requests repeated
+// with the same Idempotency-Key header instead of applying them again.
+type IdempotencyHandler struct {
+	service service.IdempotencyService
+}
+
+// NewIdempotencyHandler creates a new idempotency handler.
+//
+// Returns:
+//   - *IdempotencyHandler: Ready-to-use handler
+//   - error: If service is nil
+func NewIdempotencyHandler(service service.IdempotencyService) (*IdempotencyHandler, error) {
+
+	if service == nil {
+		return nil, errors.New("create new idempotency handler failed: service is nil")
+	}
+
+	return &IdempotencyHandler{
+		service: service,
+	}, nil
+}
+
+// Middleware applies idempotency keys to the wrapped handler.
+//
+// Requests without the Idempotency-Key header are passed through.
+// The first request with a key is handled and its response is recorded,
+// responses with 5xx statuses are not recorded, so failed requests can be retried.
+// Repeated requests get the recorded response with the Idempotent-Replayed header,
+// or 409 Conflict while the first request is still handled.
+// Requests are told apart by method, path and body, reusing a key
+// for a different request gets 422 Unprocessable Entity.
+func (handler *IdempotencyHandler) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(models.IdempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, readErr := io.ReadAll(r.Body)
+		if readErr != nil {
+			api.RespondError(w, api.BadRequest("Read request body error"))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		recorded, err := handler.service.Begin(r.Context(), key, models.RequestFingerprint(r.Method, r.URL.Path, body))
+		if err != nil {
+			api.RespondError(w, err)
+			return
+		}
+
+		if recorded != nil {
+			if recorded.ContentType != "" {
+				w.Header().Set("Content-Type", recorded.ContentType)
+			}
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			w.WriteHeader(recorded.Status)
+			w.Write(recorded.Body)
+			return
+		}
+
+		// The key is released unless the response is recorded,
+		// including when next panics; the panic itself is left to the recoverer
+		completed := false
+		defer func() {
+			if !completed {
+				handler.release(r.Context(), key)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		if recorder.status >= http.StatusInternalServerError {
+			return
+		}
+
+		completed = true
+		if err := handler.service.Complete(r.Context(), key, models.IdempotentResponse{
+			Status:      recorder.status,
+			ContentType: w.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		}); err != nil {
+			slog.Error("Complete idempotency key error", slog.String("key", key), slog.Any("error", err))
+		}
+	})
+}
+
+// release forgets a key of a failed request, errors are only logged
+// since the response is already written.
+func (handler *IdempotencyHandler) release(ctx context.Context, key string) {
+	if err := handler.service.Release(ctx, key); err != nil {
+		slog.Error("Release idempotency key error", slog.String("key", key), slog.Any("error", err))
+	}
+}
+
+// responseRecorder writes the response through while recording its status and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (recorder *responseRecorder) WriteHeader(status int) {
+	if !recorder.wroteHeader {
+		recorder.status = status
+		recorder.wroteHeader = true
+	}
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *responseRecorder) Write(data []byte) (int, error) {
+	recorder.wroteHeader = true
+	recorder.body.Write(data)
+	return recorder.ResponseWriter.Write(data)
+}
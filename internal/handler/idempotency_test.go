@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/service"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIdempotencyHandler(t *testing.T) {
+	h, err := NewIdempotencyHandler(service.NewMockIdempotencyService(t))
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+
+	h, err = NewIdempotencyHandler(nil)
+	assert.Error(t, err)
+	assert.Nil(t, h)
+}
+
+func TestIdempotencyHandler_Middleware(t *testing.T) {
+	body := `[{"id":"requests","type":"counter","delta":1}]`
+	fingerprint := models.RequestFingerprint(http.MethodPost, "/updates/", []byte(body))
+
+	tests := []struct {
+		name           string
+		key            string
+		setupMock      func(m *service.MockIdempotencyService)
+		nextStatus     int
+		expectNext     bool
+		expectedStatus int
+		expectedBody   string
+		expectReplayed bool
+	}{
+		{
+			name:           "without key",
+			setupMock:      func(m *service.MockIdempotencyService) {},
+			nextStatus:     http.StatusOK,
+			expectNext:     true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "applied",
+		},
+		{
+			name: "first request is recorded",
+			key:  "batch-1",
+			setupMock: func(m *service.MockIdempotencyService) {
+				m.EXPECT().Begin(mock.Anything, "batch-1", fingerprint).Return(nil, nil)
+				m.EXPECT().Complete(mock.Anything, "batch-1", models.IdempotentResponse{
+					Status:      http.StatusOK,
+					ContentType: "application/json",
+					Body:        []byte("applied"),
+				}).Return(nil)
+			},
+			nextStatus:     http.StatusOK,
+			expectNext:     true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "applied",
+		},
+		{
+			name: "client errors are recorded",
+			key:  "batch-1",
+			setupMock: func(m *service.MockIdempotencyService) {
+				m.EXPECT().Begin(mock.Anything, "batch-1", fingerprint).Return(nil, nil)
+				m.EXPECT().Complete(mock.Anything, "batch-1", mock.MatchedBy(func(response models.IdempotentResponse) bool {
+					return response.Status == http.StatusBadRequest
+				})).Return(nil)
+			},
+			nextStatus:     http.StatusBadRequest,
+			expectNext:     true,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "applied",
+		},
+		{
+			name: "server errors release key",
+			key:  "batch-1",
+			setupMock: func(m *service.MockIdempotencyService) {
+				m.EXPECT().Begin(mock.Anything, "batch-1", fingerprint).Return(nil, nil)
+				m.EXPECT().Release(mock.Anything, "batch-1").Return(nil)
+			},
+			nextStatus:     http.StatusInternalServerError,
+			expectNext:     true,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "applied",
+		},
+		{
+			name: "repeated request is replayed",
+			key:  "batch-1",
+			setupMock: func(m *service.MockIdempotencyService) {
+				m.EXPECT().Begin(mock.Anything, "batch-1", fingerprint).Return(&models.IdempotentResponse{
+					Status:      http.StatusOK,
+					ContentType: "application/json",
+					Body:        []byte(`{"accepted":2}`),
+				}, nil)
+			},
+			expectNext:     false,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"accepted":2}`,
+			expectReplayed: true,
+		},
+		{
+			name: "key used for a different request",
+			key:  "batch-1",
+			setupMock: func(m *service.MockIdempotencyService) {
+				m.EXPECT().Begin(mock.Anything, "batch-1", fingerprint).Return(nil, api.UnprocessibleEntity("idempotency key batch-1 was used for a different request"))
+			},
+			expectNext:     false,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "different request",
+		},
+		{
+			name: "request in progress",
+			key:  "batch-1",
+			setupMock: func(m *service.MockIdempotencyService) {
+				m.EXPECT().Begin(mock.Anything, "batch-1", fingerprint).Return(nil, api.Conflict("request with this idempotency key is in progress"))
+			},
+			expectNext:     false,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "in progress",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockIdempotencyService(t)
+			tt.setupMock(mockService)
+
+			h, err := NewIdempotencyHandler(mockService)
+			require.NoError(t, err)
+
+			called := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				read, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, body, string(read), "the body is passed on after fingerprinting")
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.nextStatus)
+				w.Write([]byte("applied"))
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/updates/", strings.NewReader(body))
+			if tt.key != "" {
+				req.Header.Set(models.IdempotencyKeyHeader, tt.key)
+			}
+			rr := httptest.NewRecorder()
+
+			h.Middleware(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectNext, called)
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			if tt.expectReplayed {
+				assert.Equal(t, "true", rr.Header().Get(IdempotentReplayedHeader))
+				assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			} else {
+				assert.Empty(t, rr.Header().Get(IdempotentReplayedHeader))
+			}
+		})
+	}
+}
+
+func TestIdempotencyHandler_Middleware_panic(t *testing.T) {
+	mockService := service.NewMockIdempotencyService(t)
+	mockService.EXPECT().Begin(mock.Anything, "batch-1", models.RequestFingerprint(http.MethodPost, "/updates/", nil)).Return(nil, nil)
+	mockService.EXPECT().Release(mock.Anything, "batch-1").Return(nil)
+
+	h, err := NewIdempotencyHandler(mockService)
+	require.NoError(t, err)
+
+	// Serving a nil handler panics with a nil pointer dereference
+	var next http.Handler
+
+	req := httptest.NewRequest(http.MethodPost, "/updates/", nil)
+	req.Header.Set(models.IdempotencyKeyHeader, "batch-1")
+
+	assert.Panics(t, func() {
+		h.Middleware(next).ServeHTTP(httptest.NewRecorder(), req)
+	})
+}
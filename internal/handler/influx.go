@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"cmp"
+	"net/http"
+
+	"github.com/gabkaclassic/metrics/internal/influx"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+)
+
+// Write saves metrics written in the InfluxDB line protocol.
+//
+// @Summary Write line protocol
+// @Description Accepts writes of the InfluxDB v2 API, such as those of the Telegraf influxdb_v2 output.
+// @Description Every field becomes a metric named measurement_field with the line tags as labels,
+// @Description tag keys are sanitized into label names (host-name becomes host_name):
+// @Description float fields are gauges, integer fields (i and u suffixes) are counter deltas,
+// @Description string and boolean fields are skipped.
+// @Description A malformed line rejects the whole request. The org and bucket parameters are ignored,
+// @Description the tenant is resolved from the request like on other endpoints.
+// @Description Requests are not signed, use tenant API keys (X-API-Key header) to authenticate writers.
+// @Tags Metrics
+// @Accept plain
+// @Produce json
+// @Param precision query string false "Timestamp precision, ns by default" Enums(ns,us,ms,s)
+// @Param org query string false "Organization, ignored"
+// @Param bucket query string false "Bucket, ignored"
+// @Param lines body string true "Line protocol document"
+// @Success 204 "Metrics saved"
+// @Failure 400 {object} api.APIError "Bad Request"
+// @Failure 500 {object} api.APIError "Internal Error"
+// @Router /api/v2/write [post]
+func (handler *MetricsHandler) Write(w http.ResponseWriter, r *http.Request) {
+	precision := cmp.Or(r.URL.Query().Get("precision"), influx.Nanoseconds)
+
+	metrics, err := influx.Parse(r.Body, precision)
+	if err != nil {
+		api.RespondError(w, api.BadRequest(err.Error()))
+		return
+	}
+
+	if len(metrics) > 0 {
+		if saveErr := handler.service.SaveAll(r.Context(), metrics); saveErr != nil {
+			api.RespondError(w, saveErr)
+			return
+		}
+	}
+
+	api.Respond(w, http.StatusNoContent, nil)
+}
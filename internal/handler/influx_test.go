@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/service"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsHandler_Write(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		body           string
+		setupMock      func(m *service.MockMetricsService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "saves fields",
+			url:  "/api/v2/write?org=ops&bucket=telegraf",
+			body: "cpu,host=web1 usage_idle=97.5 1700000000000000000\nnet,host=web1 packets=12i 1700000000000000000\n",
+			setupMock: func(m *service.MockMetricsService) {
+				m.EXPECT().
+					SaveAll(mock.Anything, []models.Metrics{
+						{
+							ID: "cpu_usage_idle", MType: models.Gauge, Labels: map[string]string{"host": "web1"},
+							Value: floatPtr(97.5), Timestamp: intPtr(1700000000000),
+						},
+						{
+							ID: "net_packets", MType: models.Counter, Labels: map[string]string{"host": "web1"},
+							Delta: intPtr(12), Timestamp: intPtr(1700000000000),
+						},
+					}).
+					Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "precision",
+			url:  "/api/v2/write?precision=s",
+			body: "load value=1 1700000000",
+			setupMock: func(m *service.MockMetricsService) {
+				m.EXPECT().
+					SaveAll(mock.Anything, []models.Metrics{
+						{ID: "load_value", MType: models.Gauge, Value: floatPtr(1), Timestamp: intPtr(1700000000000)},
+					}).
+					Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "nothing to save",
+			url:            "/api/v2/write",
+			body:           `system status="ok"`,
+			setupMock:      func(m *service.MockMetricsService) {},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid precision",
+			url:            "/api/v2/write?precision=h",
+			body:           "load value=1",
+			setupMock:      func(m *service.MockMetricsService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `invalid precision \"h\"`,
+		},
+		{
+			name:           "malformed line",
+			url:            "/api/v2/write",
+			body:           "load value=1\nload value=high",
+			setupMock:      func(m *service.MockMetricsService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `line 2: invalid influx line \"load value=high\": invalid float field \"value\"`,
+		},
+		{
+			name: "service error",
+			url:  "/api/v2/write",
+			body: "load value=1",
+			setupMock: func(m *service.MockMetricsService) {
+				m.EXPECT().SaveAll(mock.Anything, mock.Anything).Return(api.Internal("Save failed", nil))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Save failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetricsService(t)
+			tt.setupMock(mockService)
+
+			handler, err := NewMetricsHandler(mockService)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			handler.Write(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			} else {
+				assert.Empty(t, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestSetupRouter_Write(t *testing.T) {
+	var body bytes.Buffer
+	writer := gzip.NewWriter(&body)
+	_, err := writer.Write([]byte("mem,host=web1 used_percent=12.5\n"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	mockService := service.NewMockMetricsService(t)
+	mockService.EXPECT().
+		SaveAll(mock.MatchedBy(func(ctx context.Context) bool {
+			return middleware.TenantFromCtx(ctx) == "team-a"
+		}), []models.Metrics{
+			{ID: "mem_used_percent", MType: models.Gauge, Labels: map[string]string{"host": "web1"}, Value: floatPtr(12.5)},
+		}).
+		Return(nil)
+
+	metricsHandler, err := NewMetricsHandler(mockService)
+	require.NoError(t, err)
+
+	router := SetupRouter(&RouterConfiguration{MetricsHandler: metricsHandler})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/write?org=ops&bucket=telegraf", &body)
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set(middleware.TenantHeader, "team-a")
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Empty(t, rr.Body.String())
+}
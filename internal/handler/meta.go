@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/service"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+)
+
+// MetaHandler serves metric metadata endpoints.
+type MetaHandler struct {
+	service service.MetaService
+}
+
+// NewMetaHandler creates a new metadata handler.
+//
+// Returns:
+//   - *MetaHandler: Ready-to-use handler
+//   - error: If service is nil
+func NewMetaHandler(service service.MetaService) (*MetaHandler, error) {
+	if service == nil {
+		return nil, errors.New("create new meta handler failed: service is nil")
+	}
+
+	return &MetaHandler{
+		service: service,
+	}, nil
+}
+
+// Get retrieves the metadata of a metric.
+//
+// @Summary Get metric metadata
+// @Description Returns unit, description and owner of a metric.
+// @Tags Meta
+// @Produce json
+// @Param id path string true "Metric ID"
+// @Success 200 {object} api.Envelope{data=models.Meta} "Metric metadata"
+// @Failure 404 {object} api.ErrorEnvelope "Not Found"
+// @Failure 500 {object} api.ErrorEnvelope "Internal Error"
+// @Router /api/v1/meta/{id} [get]
+func (handler *MetaHandler) Get(w http.ResponseWriter, r *http.Request) {
+	meta, err := handler.service.Get(r.Context(), r.PathValue("id"))
+
+	if err != nil {
+		api.RespondError(w, err)
+		return
+	}
+
+	api.Respond(w, http.StatusOK, meta)
+}
+
+// Put creates or replaces the metadata of a metric.
+//
+// @Summary Put metric metadata
+// @Description Stores unit, description and owner of a metric, replacing existing metadata.
+// @Description The metric ID is taken from the path, an ID in the body is ignored.
+// @Tags Meta
+// @Accept json
+// @Produce json
+// @Param id path string true "Metric ID"
+// @Param meta body models.Meta true "Metric metadata"
+// @Success 200 {object} api.Envelope{data=models.Meta} "Stored metadata"
+// @Failure 400 {object} api.ErrorEnvelope "Bad Request"
+// @Failure 422 {object} api.ErrorEnvelope "Invalid JSON"
+// @Failure 500 {object} api.ErrorEnvelope "Internal Error"
+// @Router /api/v1/meta/{id} [put]
+func (handler *MetaHandler) Put(w http.ResponseWriter, r *http.Request) {
+	meta := models.Meta{}
+	if err := json.NewDecoder(r.Body).Decode(&meta); err != nil {
+		api.RespondError(w, api.UnprocessibleEntity("Invalid input JSON"))
+		return
+	}
+	meta.ID = r.PathValue("id")
+
+	if err := handler.service.Put(r.Context(), meta); err != nil {
+		api.RespondError(w, err)
+		return
+	}
+
+	api.Respond(w, http.StatusOK, meta)
+}
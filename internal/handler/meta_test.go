@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/service"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMetaHandler(t *testing.T) {
+	h, err := NewMetaHandler(service.NewMockMetaService(t))
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+
+	h, err = NewMetaHandler(nil)
+	assert.Error(t, err)
+	assert.Nil(t, h)
+}
+
+func TestMetaHandler_Get(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockMeta     models.Meta
+		mockErr      *api.APIError
+		expectStatus int
+		expectBody   string
+	}{
+		{
+			name:         "found",
+			mockMeta:     models.Meta{ID: "HeapAlloc", Unit: "bytes"},
+			expectStatus: http.StatusOK,
+			expectBody:   `{"id":"HeapAlloc","unit":"bytes"}`,
+		},
+		{
+			name:         "not found",
+			mockErr:      api.NotFound("metadata of metric HeapAlloc not found"),
+			expectStatus: http.StatusNotFound,
+			expectBody:   "metadata of metric HeapAlloc not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetaService(t)
+			mockService.EXPECT().Get(mock.Anything, "HeapAlloc").Return(tt.mockMeta, tt.mockErr)
+
+			h, err := NewMetaHandler(mockService)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/meta/HeapAlloc", nil)
+			req.SetPathValue("id", "HeapAlloc")
+			rr := httptest.NewRecorder()
+
+			h.Get(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectBody)
+		})
+	}
+}
+
+func TestMetaHandler_Put(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		expectMeta   *models.Meta
+		mockErr      *api.APIError
+		expectStatus int
+	}{
+		{
+			name:         "path id overrides body id",
+			body:         `{"id":"Other","unit":"bytes","description":"heap","owner":"runtime"}`,
+			expectMeta:   &models.Meta{ID: "HeapAlloc", Unit: "bytes", Description: "heap", Owner: "runtime"},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "service error",
+			body:         `{"unit":"bytes"}`,
+			expectMeta:   &models.Meta{ID: "HeapAlloc", Unit: "bytes"},
+			mockErr:      api.BadRequest("metric id is required"),
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid json",
+			body:         `{"unit":`,
+			expectStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetaService(t)
+			if tt.expectMeta != nil {
+				mockService.EXPECT().Put(mock.Anything, *tt.expectMeta).Return(tt.mockErr)
+			}
+
+			h, err := NewMetaHandler(mockService)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/meta/HeapAlloc", strings.NewReader(tt.body))
+			req.SetPathValue("id", "HeapAlloc")
+			rr := httptest.NewRecorder()
+
+			h.Put(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+		})
+	}
+}
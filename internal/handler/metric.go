@@ -3,40 +3,22 @@ package handler
 import (
 	"encoding/json"
 	"errors"
-	"html/template"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/prometheus"
 	"github.com/gabkaclassic/metrics/internal/service"
 	api "github.com/gabkaclassic/metrics/pkg/error"
 )
 
-type MetricsPageData struct {
-	Metrics map[string]any
-}
-
-var metricsTemplate = template.Must(template.New("metrics").Parse(`
-<html>
-<head>
-	<title>Metrics</title>
-</head>
-<body>
-	<h1>Metrics</h1>
-	<table border="1" cellpadding="5" cellspacing="0">
-		<tr><th>ID</th><th>Value</th></tr>
-		{{range $id, $val := .Metrics}}
-		<tr>
-			<td>{{$id}}</td>
-			<td>{{$val}}</td>
-		</tr>
-		{{end}}
-	</table>
-</body>
-</html>
-`))
-
 type MetricsHandler struct {
 	service service.MetricsService
+	static  http.Handler
 }
 
 func NewMetricsHandler(service service.MetricsService) (*MetricsHandler, error) {
@@ -45,22 +27,33 @@ func NewMetricsHandler(service service.MetricsService) (*MetricsHandler, error)
 		return nil, errors.New("create new metrics handler failed: service is nil")
 	}
 
+	static, err := newDashboardStatic()
+	if err != nil {
+		return nil, fmt.Errorf("create new metrics handler failed: %w", err)
+	}
+
 	return &MetricsHandler{
 		service: service,
+		static:  static,
 	}, nil
 }
 
 // Save saves a single metric using plain-text URL parameters.
 //
 // @Summary Save metric (plain-text)
-// @Description Saves a metric using URL parameters. Counters are incremented, gauges are overwritten.
+// @Description Saves a metric using URL parameters. Counters are incremented, gauges are overwritten,
+// @Description gauge values prefixed with "+" are added to the stored value ("+5" increments, "+-5" decrements),
+// @Description histograms and summaries record the value as a single observation, sets add the value as a member.
 // @Tags Metrics
-// @Param type path string true "Metric type" Enums(gauge,counter)
+// @Param type path string true "Metric type" Enums(gauge,counter,histogram,summary,set)
 // @Param id path string true "Metric ID"
 // @Param value path string true "Metric value"
+// @Param Idempotency-Key header string false "Key of the request, repeated requests with the key get the original response"
 // @Success 200 "Metric saved"
 // @Failure 400 {object} api.APIError "Bad Request"
 // @Failure 404 {object} api.APIError "Not Found"
+// @Failure 409 {object} api.APIError "Request with the idempotency key is in progress"
+// @Failure 422 {object} api.APIError "Idempotency key used for a different request"
 // @Failure 500 {object} api.APIError "Internal Error"
 // @Router /update/{type}/{id}/{value} [post]
 func (handler *MetricsHandler) Save(w http.ResponseWriter, r *http.Request) {
@@ -80,14 +73,18 @@ func (handler *MetricsHandler) Save(w http.ResponseWriter, r *http.Request) {
 // SaveJSON saves a single metric using JSON payload.
 //
 // @Summary Save metric (JSON)
-// @Description Saves a metric using JSON body. Counters are incremented, gauges are overwritten.
+// @Description Saves a metric using JSON body. Counters are incremented, gauges are overwritten
+// @Description or changed by `increment`,
+// @Description histograms are merged bucket by bucket, summary and set sketches are merged.
 // @Tags Metrics
 // @Accept json
 // @Produce json
 // @Param metric body models.Metrics true "Metric payload"
+// @Param Idempotency-Key header string false "Key of the request, repeated requests with the key get the original response"
 // @Success 200 {object} models.Metrics "Saved metric"
 // @Failure 400 {object} api.APIError "Bad Request"
-// @Failure 422 {object} api.APIError "Invalid JSON"
+// @Failure 422 {object} api.APIError "Invalid JSON or idempotency key used for a different request"
+// @Failure 409 {object} api.APIError "Request with the idempotency key is in progress"
 // @Failure 500 {object} api.APIError "Internal Error"
 // @Router /update [post]
 func (handler *MetricsHandler) SaveJSON(w http.ResponseWriter, r *http.Request) {
@@ -116,23 +113,51 @@ func (handler *MetricsHandler) SaveJSON(w http.ResponseWriter, r *http.Request)
 // SaveAll saves multiple metrics in a single request.
 //
 // @Summary Save metrics batch
-// @Description Saves multiple metrics. Counters are aggregated by ID, gauges use the last value.
+// @Description Saves multiple metrics. Counters are aggregated by ID, gauges use the last value,
+// @Description gauge increments are applied in order,
+// @Description histograms, summaries and sets with the same ID are merged.
+// @Description By default an invalid metric rejects the whole batch.
+// @Description With partial=true every metric is validated, valid metrics are saved
+// @Description and the response lists rejected metrics by their index in the batch.
 // @Tags Metrics
 // @Accept json
+// @Produce json
 // @Param metrics body []models.Metrics true "Metrics list"
-// @Success 200 "Metrics saved"
+// @Param partial query bool false "Save valid metrics and report rejected ones"
+// @Param Idempotency-Key header string false "Key of the request, repeated requests with the key get the original response"
+// @Success 200 {object} models.BatchResult "Metrics saved, per-item results with partial=true"
 // @Failure 400 {object} api.APIError "Bad Request"
-// @Failure 422 {object} api.APIError "Invalid JSON"
+// @Failure 422 {object} api.APIError "Invalid JSON or idempotency key used for a different request"
+// @Failure 409 {object} api.APIError "Request with the idempotency key is in progress"
 // @Failure 500 {object} api.APIError "Internal Error"
 // @Router /updates [post]
 func (handler *MetricsHandler) SaveAll(w http.ResponseWriter, r *http.Request) {
+	partial, err := parseBool(r.URL.Query(), "partial")
+	if err != nil {
+		api.RespondError(w, api.BadRequest(err.Error()))
+		return
+	}
+
 	metrics := make([]models.Metrics, 0)
-	err := json.NewDecoder(r.Body).Decode(&metrics)
+	err = json.NewDecoder(r.Body).Decode(&metrics)
 	if err != nil {
 		api.RespondError(w, api.UnprocessibleEntity("Invalid input JSON"))
 		return
 	}
 
+	if partial {
+		result, saveErr := handler.service.SaveBatch(r.Context(), metrics)
+		if saveErr != nil {
+			api.RespondError(w, saveErr)
+			return
+		}
+
+		if encodeErr := json.NewEncoder(w).Encode(result); encodeErr != nil {
+			api.RespondError(w, encodeErr)
+		}
+		return
+	}
+
 	saveErr := handler.service.SaveAll(r.Context(), metrics)
 
 	if saveErr != nil {
@@ -144,10 +169,11 @@ func (handler *MetricsHandler) SaveAll(w http.ResponseWriter, r *http.Request) {
 // Get retrieves a metric value by ID and type.
 //
 // @Summary Get metric value
-// @Description Returns raw metric value. Counter → int64, Gauge → float64.
+// @Description Returns raw metric value. Counter → int64, Gauge → float64, Histogram → HistogramSnapshot,
+// @Description Summary → SummarySnapshot with estimated quantiles, Set → int64 estimated distinct count.
 // @Tags Metrics
 // @Produce json
-// @Param type path string true "Metric type" Enums(gauge,counter)
+// @Param type path string true "Metric type" Enums(gauge,counter,histogram,summary,set)
 // @Param id path string true "Metric ID"
 // @Success 200 {object} any "Metric value"
 // @Failure 404 {object} api.APIError "Not Found"
@@ -176,7 +202,7 @@ func (handler *MetricsHandler) Get(w http.ResponseWriter, r *http.Request) {
 // GetJSON retrieves a metric using JSON request.
 //
 // @Summary Get metric (JSON)
-// @Description Returns full metric structure.
+// @Description Returns full metric structure. Summaries include estimated quantiles.
 // @Tags Metrics
 // @Accept json
 // @Produce json
@@ -196,7 +222,7 @@ func (handler *MetricsHandler) GetJSON(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	value, getErr := handler.service.GetStruct(r.Context(), metric.ID, metric.MType)
+	value, getErr := handler.service.GetStruct(r.Context(), metric.ID, metric.MType, metric.Labels)
 
 	if getErr != nil {
 		api.RespondError(w, getErr)
@@ -211,41 +237,318 @@ func (handler *MetricsHandler) GetJSON(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// GetAll renders all metrics as an HTML page.
+// GetMany retrieves multiple metrics in a single request.
 //
-// @Summary Get all metrics (HTML)
-// @Description Returns all stored metrics rendered as HTML table.
+// @Summary Get metrics batch (JSON)
+// @Description Returns full structures of the requested series in request order.
+// @Description Series that don't exist are omitted. Summaries include estimated quantiles.
 // @Tags Metrics
-// @Produce text/html
-// @Success 200 "HTML page with metrics"
-// @Failure 404 {object} api.APIError "Not Found"
+// @Accept json
+// @Produce json
+// @Param metrics body []models.SeriesRef true "Series identifiers"
+// @Success 200 {array} models.Metrics "Metrics data"
+// @Failure 400 {object} api.APIError "Bad Request"
+// @Failure 422 {object} api.APIError "Invalid JSON"
+// @Failure 500 {object} api.APIError "Internal Error"
+// @Router /values [post]
+func (handler *MetricsHandler) GetMany(w http.ResponseWriter, r *http.Request) {
+	refs := make([]models.SeriesRef, 0)
+	err := json.NewDecoder(r.Body).Decode(&refs)
+
+	if err != nil {
+		api.RespondError(w, api.UnprocessibleEntity("Invalid input JSON"))
+		return
+	}
+
+	metrics, getErr := handler.service.GetMany(r.Context(), refs)
+
+	if getErr != nil {
+		api.RespondError(w, getErr)
+		return
+	}
+
+	encodeErr := json.NewEncoder(w).Encode(metrics)
+
+	if encodeErr != nil {
+		api.RespondError(w, encodeErr)
+		return
+	}
+}
+
+// List returns a page of metrics selected by filters.
+//
+// @Summary List metrics (JSON)
+// @Description Returns full structures of series matching all given filters.
+// @Description Pages are continued with the next_cursor of the previous page.
+// @Tags Metrics
+// @Produce json
+// @Param type query string false "Metric type, all types when omitted" Enums(gauge,counter,histogram,summary,set)
+// @Param prefix query string false "Metric ID prefix"
+// @Param pattern query string false "Glob pattern of metric IDs (path.Match syntax)"
+// @Param regex query string false "Regular expression of metric IDs (RE2 syntax)"
+// @Param sort query string false "Sort field (default: id)" Enums(id,type)
+// @Param order query string false "Sort order (default: asc)" Enums(asc,desc)
+// @Param limit query int false "Page size (default: 100, max: 1000)"
+// @Param cursor query string false "Cursor of the page"
+// @Success 200 {object} api.Envelope{data=models.MetricsPage} "Metrics page"
+// @Failure 400 {object} api.ErrorEnvelope "Bad Request"
+// @Failure 500 {object} api.ErrorEnvelope "Internal Error"
+// @Router /api/v1/metrics [get]
+// @Router /api/v1/values [get]
+func (handler *MetricsHandler) List(w http.ResponseWriter, r *http.Request) {
+	query, err := parseListQuery(r.URL.Query())
+	if err != nil {
+		api.RespondError(w, api.BadRequest(err.Error()))
+		return
+	}
+
+	page, listErr := handler.service.List(r.Context(), query)
+
+	if listErr != nil {
+		api.RespondError(w, listErr)
+		return
+	}
+
+	api.Respond(w, http.StatusOK, page)
+}
+
+// Prometheus renders all metrics in the Prometheus text exposition format.
+//
+// @Summary Get all metrics (Prometheus)
+// @Description Returns all series of the tenant in the Prometheus text exposition format.
+// @Description Counters are exposed as counter, gauges and set cardinalities as gauge,
+// @Description histograms and summaries as histogram and summary.
+// @Description Metric and label names are sanitized to the Prometheus charset,
+// @Description HELP lines are taken from metric metadata descriptions.
+// @Tags Metrics
+// @Produce plain
+// @Success 200 {string} string "Metrics in text exposition format"
 // @Failure 500 {object} api.APIError "Internal Error"
-// @Router / [get]
-func (handler *MetricsHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	metrics, err := handler.service.GetAll(r.Context())
+// @Router /metrics [get]
+func (handler *MetricsHandler) Prometheus(w http.ResponseWriter, r *http.Request) {
+	metrics, err := handler.service.GetAllMetrics(r.Context())
+
+	if err != nil {
+		api.RespondError(w, err)
+		return
+	}
+
+	descriptions, err := handler.service.GetDescriptions(r.Context())
 
 	if err != nil {
 		api.RespondError(w, err)
 		return
 	}
 
-	if metrics == nil {
+	if encodeErr := prometheus.Encode(w, metrics, descriptions); encodeErr != nil {
 		api.RespondError(
 			w,
-			api.NotFound("Metrics not found"),
+			api.Internal("failed to render metrics", encodeErr),
 		)
 		return
 	}
+}
 
-	data := MetricsPageData{
-		Metrics: metrics,
+// GetRange returns recorded points of a counter or gauge series.
+//
+// @Summary Get metric history
+// @Description Returns points recorded for a series within [from, to] in chronological order.
+// @Description When step is set, points are aggregated into step windows:
+// @Description counter deltas are summed, gauges keep the last value.
+// @Tags Metrics
+// @Produce json
+// @Param id query string true "Metric ID"
+// @Param type query string true "Metric type" Enums(gauge,counter)
+// @Param label query []string false "Series label as name=value" collectionFormat(multi)
+// @Param from query string false "Range start, Unix milliseconds or RFC3339 (default: to - 1h)"
+// @Param to query string false "Range end, Unix milliseconds or RFC3339 (default: now)"
+// @Param step query string false "Aggregation window, e.g. 30s or 5m"
+// @Success 200 {object} api.Envelope{data=[]models.Point} "Series points"
+// @Failure 400 {object} api.ErrorEnvelope "Bad Request"
+// @Failure 500 {object} api.ErrorEnvelope "Internal Error"
+// @Router /api/v1/range [get]
+func (handler *MetricsHandler) GetRange(w http.ResponseWriter, r *http.Request) {
+	query, err := parseRangeQuery(r.URL.Query(), time.Now())
+	if err != nil {
+		api.RespondError(w, api.BadRequest(err.Error()))
+		return
 	}
 
-	if err := metricsTemplate.Execute(w, data); err != nil {
-		api.RespondError(
-			w,
-			api.Internal("failed to render template", err),
-		)
+	points, getErr := handler.service.GetRange(r.Context(), query)
+
+	if getErr != nil {
+		api.RespondError(w, getErr)
 		return
 	}
+
+	api.Respond(w, http.StatusOK, points)
+}
+
+// Delete removes all series of a metric.
+//
+// @Summary Delete metric
+// @Description Deletes all series of a metric, whatever their labels, together with their history.
+// @Tags Metrics
+// @Param type path string true "Metric type" Enums(gauge,counter,histogram,summary,set)
+// @Param id path string true "Metric ID"
+// @Success 200 "Metric deleted"
+// @Failure 404 {object} api.APIError "Not Found"
+// @Failure 500 {object} api.APIError "Internal Error"
+// @Router /value/{type}/{id} [delete]
+func (handler *MetricsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	err := handler.service.Delete(r.Context(), r.PathValue("id"), r.PathValue("type"))
+
+	if err != nil {
+		api.RespondError(w, err)
+		return
+	}
+}
+
+// DeleteAll removes all series of metrics matching a glob pattern.
+//
+// @Summary Delete metrics by pattern
+// @Description Deletes all series of metrics whose IDs match a glob pattern (path.Match syntax, e.g. cpu_*),
+// @Description together with their history. Returns keys of deleted series.
+// @Tags Metrics
+// @Produce json
+// @Param pattern query string true "Glob pattern of metric IDs"
+// @Param type query string false "Metric type, all types when omitted" Enums(gauge,counter,histogram,summary,set)
+// @Success 200 {object} api.Envelope{data=models.DeleteResult} "Deleted series"
+// @Failure 400 {object} api.ErrorEnvelope "Bad Request"
+// @Failure 500 {object} api.ErrorEnvelope "Internal Error"
+// @Router /api/v1/metrics [delete]
+func (handler *MetricsHandler) DeleteAll(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	result, deleteErr := handler.service.DeleteAll(r.Context(), values.Get("pattern"), values.Get("type"))
+
+	if deleteErr != nil {
+		api.RespondError(w, deleteErr)
+		return
+	}
+
+	api.Respond(w, http.StatusOK, result)
+}
+
+// parseRangeQuery builds a range query from URL query parameters.
+// Missing to defaults to now, missing from defaults to one hour before to.
+func parseRangeQuery(values url.Values, now time.Time) (models.RangeQuery, error) {
+	query := models.RangeQuery{
+		ID:    values.Get("id"),
+		MType: values.Get("type"),
+		To:    now,
+	}
+
+	labels, err := parseLabels(values["label"])
+	if err != nil {
+		return models.RangeQuery{}, err
+	}
+	query.Labels = labels
+
+	if raw := values.Get("to"); raw != "" {
+		to, err := parseTime(raw)
+		if err != nil {
+			return models.RangeQuery{}, fmt.Errorf("invalid to: %w", err)
+		}
+		query.To = to
+	}
+
+	query.From = query.To.Add(-time.Hour)
+	if raw := values.Get("from"); raw != "" {
+		from, err := parseTime(raw)
+		if err != nil {
+			return models.RangeQuery{}, fmt.Errorf("invalid from: %w", err)
+		}
+		query.From = from
+	}
+
+	if raw := values.Get("step"); raw != "" {
+		step, err := time.ParseDuration(raw)
+		if err != nil {
+			return models.RangeQuery{}, fmt.Errorf("invalid step: %w", err)
+		}
+		query.Step = step
+	}
+
+	return query, nil
+}
+
+// parseLabels builds a label set from name=value pairs.
+// Returns nil labels when no pairs are given.
+func parseLabels(pairs []string) (map[string]string, error) {
+	var labels map[string]string
+	for _, pair := range pairs {
+		name, value, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid label %q, expected name=value", pair)
+		}
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[name] = value
+	}
+
+	return labels, nil
+}
+
+// parseListQuery builds a list query from URL query parameters.
+// Filters and the sort field are validated by the service.
+func parseListQuery(values url.Values) (models.ListQuery, error) {
+	query := models.ListQuery{
+		MType:   values.Get("type"),
+		Prefix:  values.Get("prefix"),
+		Pattern: values.Get("pattern"),
+		Regex:   values.Get("regex"),
+		SortBy:  values.Get("sort"),
+	}
+
+	switch order := values.Get("order"); order {
+	case "", "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		return models.ListQuery{}, fmt.Errorf("invalid order %q, expected asc or desc", order)
+	}
+
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return models.ListQuery{}, fmt.Errorf("invalid limit: %s", raw)
+		}
+		query.Limit = limit
+	}
+
+	if raw := values.Get("cursor"); raw != "" {
+		after, err := models.DecodeCursor(raw)
+		if err != nil {
+			return models.ListQuery{}, err
+		}
+		query.After = &after
+	}
+
+	return query, nil
+}
+
+// parseBool parses an optional boolean query parameter, missing parameters are false.
+func parseBool(values url.Values, name string) (bool, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %s", name, raw)
+	}
+
+	return parsed, nil
+}
+
+// parseTime parses Unix milliseconds or an RFC3339 timestamp.
+func parseTime(raw string) (time.Time, error) {
+	if ms, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+
+	return time.Parse(time.RFC3339, raw)
 }
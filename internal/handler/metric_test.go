@@ -1,7 +1,12 @@
 package handler
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -10,13 +15,17 @@ import (
 	"github.com/gabkaclassic/metrics/internal/service"
 	"github.com/stretchr/testify/mock"
 
-	"fmt"
 	"io"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/gabkaclassic/metrics/pkg/encrypt"
 	api "github.com/gabkaclassic/metrics/pkg/error"
+	"github.com/gabkaclassic/metrics/pkg/hash"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewMetricsHandler(t *testing.T) {
@@ -234,6 +243,75 @@ func TestMetricsHandler_SaveAll(t *testing.T) {
 	}
 }
 
+func TestMetricsHandler_SaveAll_partial(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		mockResult     models.BatchResult
+		mockErr        *api.APIError
+		expectCall     bool
+		expectStatus   int
+		expectedResult models.BatchResult
+	}{
+		{
+			name: "rejected items",
+			url:  "/updates/?partial=true",
+			mockResult: models.BatchResult{
+				Accepted: 1, Rejected: 1,
+				Errors: []models.BatchError{{Index: 1, Error: "counter delta is required"}},
+			},
+			expectCall:   true,
+			expectStatus: http.StatusOK,
+			expectedResult: models.BatchResult{
+				Accepted: 1, Rejected: 1,
+				Errors: []models.BatchError{{Index: 1, Error: "counter delta is required"}},
+			},
+		},
+		{
+			name:         "service error",
+			url:          "/updates/?partial=1",
+			mockErr:      api.Internal("save error", errors.New("some error")),
+			expectCall:   true,
+			expectStatus: http.StatusInternalServerError,
+		},
+		{
+			name:         "invalid partial",
+			url:          "/updates/?partial=maybe",
+			expectStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `[{"id": "c1", "type": "counter", "delta": 10}, {"id": "c2", "type": "counter"}]`
+
+			mockService := service.NewMockMetricsService(t)
+			if tt.expectCall {
+				mockService.EXPECT().
+					SaveBatch(mock.Anything, mock.MatchedBy(func(metrics []models.Metrics) bool { return len(metrics) == 2 })).
+					Return(tt.mockResult, tt.mockErr)
+			}
+
+			handler, err := NewMetricsHandler(mockService)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(body))
+			rr := httptest.NewRecorder()
+
+			handler.SaveAll(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+			if tt.expectStatus != http.StatusOK {
+				return
+			}
+
+			var result models.BatchResult
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&result))
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
 func TestMetricsHandler_SaveJSON(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -304,7 +382,7 @@ func TestMetricsHandler_GetJSON(t *testing.T) {
 	tests := []struct {
 		name           string
 		body           string
-		mockGet        func(ctx context.Context, id, mType string) (models.Metrics, *api.APIError)
+		mockGet        func(ctx context.Context, id, mType string, labels map[string]string) (models.Metrics, *api.APIError)
 		expectStatus   int
 		expectErrorMsg string
 		expectGetCall  bool
@@ -312,7 +390,7 @@ func TestMetricsHandler_GetJSON(t *testing.T) {
 		{
 			name: "valid JSON and service success",
 			body: `{"id":"m1","type":"counter"}`,
-			mockGet: func(ctx context.Context, id, mType string) (models.Metrics, *api.APIError) {
+			mockGet: func(ctx context.Context, id, mType string, labels map[string]string) (models.Metrics, *api.APIError) {
 				assert.Equal(t, "m1", id)
 				assert.Equal(t, "counter", mType)
 				delta := int64(42)
@@ -335,7 +413,7 @@ func TestMetricsHandler_GetJSON(t *testing.T) {
 		{
 			name: "service returns error",
 			body: `{"id":"m2","type":"gauge"}`,
-			mockGet: func(ctx context.Context, id, mType string) (models.Metrics, *api.APIError) {
+			mockGet: func(ctx context.Context, id, mType string, labels map[string]string) (models.Metrics, *api.APIError) {
 				return models.Metrics{}, api.NotFound("metric not found")
 			},
 			expectStatus:   http.StatusNotFound,
@@ -350,7 +428,7 @@ func TestMetricsHandler_GetJSON(t *testing.T) {
 
 			if tt.expectGetCall {
 				mockService.EXPECT().
-					GetStruct(mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+					GetStruct(mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.Anything).
 					RunAndReturn(tt.mockGet)
 			}
 
@@ -456,72 +534,440 @@ func TestMetricsHandler_Get(t *testing.T) {
 	}
 }
 
-func TestMetricsHandler_GetAll(t *testing.T) {
+func TestMetricsHandler_Prometheus(t *testing.T) {
 	tests := []struct {
-		name           string
-		mockReturn     map[string]any
-		expectedStatus int
-		expectedError  *api.APIError
+		name             string
+		mockMetrics      []models.Metrics
+		mockMetricsErr   *api.APIError
+		mockDescriptions map[string]string
+		mockDescErr      *api.APIError
+		expectedStatus   int
+		expectedBody     string
 	}{
 		{
-			name:           "nil metrics",
-			mockReturn:     nil,
-			expectedStatus: http.StatusNotFound,
-			expectedError:  nil,
+			name: "counter and gauge",
+			mockMetrics: []models.Metrics{
+				{ID: "requests.total", MType: models.Counter, Delta: func() *int64 { v := int64(7); return &v }()},
+				{ID: "HeapAlloc", MType: models.Gauge, Value: func() *float64 { v := 1.5; return &v }()},
+			},
+			mockDescriptions: map[string]string{"HeapAlloc": "Heap bytes"},
+			expectedStatus:   http.StatusOK,
+			expectedBody: "# HELP HeapAlloc Heap bytes\n" +
+				"# TYPE HeapAlloc gauge\n" +
+				"HeapAlloc 1.5\n" +
+				"# HELP requests_total requests.total\n" +
+				"# TYPE requests_total counter\n" +
+				"requests_total 7\n",
 		},
 		{
-			name:           "empty metrics",
-			mockReturn:     map[string]any{},
-			expectedStatus: http.StatusOK,
-			expectedError:  nil,
+			name:             "no metrics",
+			mockMetrics:      []models.Metrics{},
+			mockDescriptions: map[string]string{},
+			expectedStatus:   http.StatusOK,
 		},
 		{
-			name: "metrics with values",
-			mockReturn: map[string]any{
-				"c1": int64(10),
-				"g1": float64(3.14),
-			},
-			expectedStatus: http.StatusOK,
-			expectedError:  nil,
+			name:           "metrics error",
+			mockMetricsErr: api.Internal("some error", errors.New("some error")),
+			expectedStatus: http.StatusInternalServerError,
 		},
 		{
-			name:           "return error",
-			mockReturn:     nil,
+			name:           "descriptions error",
+			mockMetrics:    []models.Metrics{},
+			mockDescErr:    api.Internal("some error", errors.New("some error")),
 			expectedStatus: http.StatusInternalServerError,
-			expectedError:  api.Internal("some error", errors.New("some error")),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := service.NewMockMetricsService(t)
-			mockService.EXPECT().GetAll(mock.Anything).Return(tt.mockReturn, tt.expectedError)
+			mockService.EXPECT().GetAllMetrics(mock.Anything).Return(tt.mockMetrics, tt.mockMetricsErr)
+			if tt.mockMetricsErr == nil {
+				mockService.EXPECT().GetDescriptions(mock.Anything).Return(tt.mockDescriptions, tt.mockDescErr)
+			}
 
 			handler, err := NewMetricsHandler(mockService)
 			assert.NoError(t, err)
 
-			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
 			rec := httptest.NewRecorder()
 
-			handler.GetAll(rec, req)
+			handler.Prometheus(rec, req)
 
 			res := rec.Result()
 			defer res.Body.Close()
 
 			body, _ := io.ReadAll(res.Body)
 			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, tt.expectedBody, string(body))
+			}
+		})
+	}
+}
 
-			bodyStr := string(body)
-			if tt.mockReturn == nil {
-				if tt.expectedError == nil {
-					assert.Contains(t, bodyStr, "Metrics not found")
-				}
-			} else {
-				for id, val := range tt.mockReturn {
-					assert.Contains(t, bodyStr, id)
-					assert.Contains(t, bodyStr, fmt.Sprintf("%v", val))
-				}
-				assert.Contains(t, bodyStr, "<h1>Metrics</h1>")
+func TestMetricsHandler_GetRange(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		expectQuery    *models.RangeQuery
+		mockReturn     []models.Point
+		mockError      *api.APIError
+		expectStatus   int
+		expectBody     *string
+		expectErrorMsg string
+	}{
+		{
+			name: "unix milliseconds with step and labels",
+			url:  "/api/v1/range?id=c1&type=counter&label=host%3Dweb-1&from=1000&to=61000&step=30s",
+			expectQuery: &models.RangeQuery{
+				ID:     "c1",
+				MType:  models.Counter,
+				Labels: map[string]string{"host": "web-1"},
+				From:   time.UnixMilli(1000),
+				To:     time.UnixMilli(61000),
+				Step:   30 * time.Second,
+			},
+			mockReturn:   []models.Point{{Timestamp: 1000, Delta: intPtr(3)}},
+			expectStatus: http.StatusOK,
+			expectBody:   strPtr("{\"data\":[{\"timestamp\":1000,\"delta\":3}]}\n"),
+		},
+		{
+			name: "RFC3339 timestamps",
+			url:  "/api/v1/range?id=g1&type=gauge&from=2024-01-01T00:00:00Z&to=2024-01-01T01:00:00Z",
+			expectQuery: &models.RangeQuery{
+				ID:    "g1",
+				MType: models.Gauge,
+				From:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				To:    time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC),
+			},
+			mockReturn:   []models.Point{},
+			expectStatus: http.StatusOK,
+			expectBody:   strPtr("{\"data\":[]}\n"),
+		},
+		{
+			name:           "invalid from",
+			url:            "/api/v1/range?id=g1&type=gauge&from=yesterday",
+			expectStatus:   http.StatusBadRequest,
+			expectErrorMsg: "invalid from",
+		},
+		{
+			name:           "invalid step",
+			url:            "/api/v1/range?id=g1&type=gauge&step=often",
+			expectStatus:   http.StatusBadRequest,
+			expectErrorMsg: "invalid step",
+		},
+		{
+			name:           "invalid label",
+			url:            "/api/v1/range?id=g1&type=gauge&label=host",
+			expectStatus:   http.StatusBadRequest,
+			expectErrorMsg: "invalid label",
+		},
+		{
+			name: "service error",
+			url:  "/api/v1/range?id=h1&type=histogram&from=0&to=1000",
+			expectQuery: &models.RangeQuery{
+				ID:    "h1",
+				MType: models.Histogram,
+				From:  time.UnixMilli(0),
+				To:    time.UnixMilli(1000),
+			},
+			mockError:      api.BadRequest("metric type histogram has no history"),
+			expectStatus:   http.StatusBadRequest,
+			expectErrorMsg: "has no history",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetricsService(t)
+			if tt.expectQuery != nil {
+				mockService.EXPECT().
+					GetRange(mock.Anything, *tt.expectQuery).
+					Return(tt.mockReturn, tt.mockError)
+			}
+
+			handler, err := NewMetricsHandler(mockService)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rr := httptest.NewRecorder()
+
+			handler.GetRange(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+			if tt.expectBody != nil {
+				assert.Equal(t, *tt.expectBody, rr.Body.String())
+			}
+			if tt.expectErrorMsg != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectErrorMsg)
+			}
+		})
+	}
+}
+
+func TestMetricsHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      *api.APIError
+		expectStatus   int
+		expectErrorMsg string
+	}{
+		{
+			name:         "deleted",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:           "not found",
+			mockError:      api.NotFound("Metric g1 with type gauge not found"),
+			expectStatus:   http.StatusNotFound,
+			expectErrorMsg: "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetricsService(t)
+			mockService.EXPECT().
+				Delete(mock.Anything, "g1", models.Gauge).
+				Return(tt.mockError)
+
+			handler, err := NewMetricsHandler(mockService)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			req.SetPathValue("type", models.Gauge)
+			req.SetPathValue("id", "g1")
+			rr := httptest.NewRecorder()
+
+			handler.Delete(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+			if tt.expectErrorMsg != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectErrorMsg)
+			}
+		})
+	}
+}
+
+func TestMetricsHandler_DeleteAll(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		pattern        string
+		metricType     string
+		mockReturn     models.DeleteResult
+		mockError      *api.APIError
+		expectStatus   int
+		expectBody     *string
+		expectErrorMsg string
+	}{
+		{
+			name:         "deleted by pattern and type",
+			url:          "/api/v1/metrics?pattern=cpu_%2A&type=counter",
+			pattern:      "cpu_*",
+			metricType:   models.Counter,
+			mockReturn:   models.DeleteResult{Deleted: []string{"cpu_user"}},
+			expectStatus: http.StatusOK,
+			expectBody:   strPtr("{\"data\":{\"deleted\":[\"cpu_user\"]}}\n"),
+		},
+		{
+			name:           "service error",
+			url:            "/api/v1/metrics?pattern=cpu_%5B",
+			pattern:        "cpu_[",
+			mockError:      api.BadRequest("invalid pattern cpu_["),
+			expectStatus:   http.StatusBadRequest,
+			expectErrorMsg: "invalid pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetricsService(t)
+			mockService.EXPECT().
+				DeleteAll(mock.Anything, tt.pattern, tt.metricType).
+				Return(tt.mockReturn, tt.mockError)
+
+			handler, err := NewMetricsHandler(mockService)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodDelete, tt.url, nil)
+			rr := httptest.NewRecorder()
+
+			handler.DeleteAll(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+			if tt.expectBody != nil {
+				assert.Equal(t, *tt.expectBody, rr.Body.String())
+			}
+			if tt.expectErrorMsg != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectErrorMsg)
+			}
+		})
+	}
+}
+
+func TestMetricsHandler_List(t *testing.T) {
+	cursor := models.EncodeCursor(models.SeriesRef{ID: "cpu_user", MType: models.Counter})
+
+	tests := []struct {
+		name           string
+		url            string
+		expectQuery    *models.ListQuery
+		mockReturn     models.MetricsPage
+		mockError      *api.APIError
+		expectStatus   int
+		expectBody     *string
+		expectErrorMsg string
+	}{
+		{
+			name: "all filters",
+			url:  "/api/v1/values?type=counter&prefix=cpu&pattern=cpu_%2A&regex=%5Ecpu&sort=type&order=desc&limit=2&cursor=" + cursor,
+			expectQuery: &models.ListQuery{
+				MType:      models.Counter,
+				Prefix:     "cpu",
+				Pattern:    "cpu_*",
+				Regex:      "^cpu",
+				SortBy:     models.SortByType,
+				Descending: true,
+				Limit:      2,
+				After:      &models.SeriesRef{ID: "cpu_user", MType: models.Counter},
+			},
+			mockReturn: models.MetricsPage{
+				Metrics:    []models.Metrics{{ID: "cpu_system", MType: models.Counter, Delta: intPtr(3)}},
+				NextCursor: "next",
+			},
+			expectStatus: http.StatusOK,
+			expectBody:   strPtr("{\"data\":{\"metrics\":[{\"id\":\"cpu_system\",\"type\":\"counter\",\"delta\":3}],\"next_cursor\":\"next\"}}\n"),
+		},
+		{
+			name:         "no filters",
+			url:          "/api/v1/values",
+			expectQuery:  &models.ListQuery{},
+			mockReturn:   models.MetricsPage{Metrics: []models.Metrics{}},
+			expectStatus: http.StatusOK,
+			expectBody:   strPtr("{\"data\":{\"metrics\":[]}}\n"),
+		},
+		{
+			name:           "service error",
+			url:            "/api/v1/values?sort=value",
+			expectQuery:    &models.ListQuery{SortBy: "value"},
+			mockError:      api.BadRequest("invalid sort field: value"),
+			expectStatus:   http.StatusBadRequest,
+			expectErrorMsg: "invalid sort field",
+		},
+		{
+			name:           "invalid order",
+			url:            "/api/v1/values?order=up",
+			expectStatus:   http.StatusBadRequest,
+			expectErrorMsg: "invalid order",
+		},
+		{
+			name:           "invalid limit",
+			url:            "/api/v1/values?limit=-1",
+			expectStatus:   http.StatusBadRequest,
+			expectErrorMsg: "invalid limit",
+		},
+		{
+			name:           "invalid cursor",
+			url:            "/api/v1/values?cursor=%21%21",
+			expectStatus:   http.StatusBadRequest,
+			expectErrorMsg: "invalid cursor",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetricsService(t)
+			if tt.expectQuery != nil {
+				mockService.EXPECT().
+					List(mock.Anything, *tt.expectQuery).
+					Return(tt.mockReturn, tt.mockError)
+			}
+
+			handler, err := NewMetricsHandler(mockService)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rr := httptest.NewRecorder()
+
+			handler.List(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+			if tt.expectBody != nil {
+				assert.Equal(t, *tt.expectBody, rr.Body.String())
+			}
+			if tt.expectErrorMsg != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectErrorMsg)
+			}
+		})
+	}
+}
+
+func TestMetricsHandler_GetMany(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectRefs     []models.SeriesRef
+		mockReturn     []models.Metrics
+		mockError      *api.APIError
+		expectStatus   int
+		expectBody     *string
+		expectErrorMsg string
+	}{
+		{
+			name: "found metrics",
+			body: `[{"id":"requests","type":"counter"},{"id":"temp","type":"gauge","labels":{"room":"lab"}}]`,
+			expectRefs: []models.SeriesRef{
+				{ID: "requests", MType: models.Counter},
+				{ID: "temp", MType: models.Gauge, Labels: map[string]string{"room": "lab"}},
+			},
+			mockReturn: []models.Metrics{
+				{ID: "requests", MType: models.Counter, Delta: intPtr(5)},
+			},
+			expectStatus: http.StatusOK,
+			expectBody:   strPtr("[{\"id\":\"requests\",\"type\":\"counter\",\"delta\":5}]\n"),
+		},
+		{
+			name:           "service error",
+			body:           `[{"id":"requests","type":"unknown"}]`,
+			expectRefs:     []models.SeriesRef{{ID: "requests", MType: "unknown"}},
+			mockError:      api.BadRequest("invalid metric type: unknown"),
+			expectStatus:   http.StatusBadRequest,
+			expectErrorMsg: "invalid metric type",
+		},
+		{
+			name:           "invalid json",
+			body:           `{"id":"requests"}`,
+			expectStatus:   http.StatusUnprocessableEntity,
+			expectErrorMsg: "Invalid input JSON",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetricsService(t)
+			if tt.expectRefs != nil {
+				mockService.EXPECT().
+					GetMany(mock.Anything, tt.expectRefs).
+					Return(tt.mockReturn, tt.mockError)
+			}
+
+			handler, err := NewMetricsHandler(mockService)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/values/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			handler.GetMany(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+			if tt.expectBody != nil {
+				assert.Equal(t, *tt.expectBody, rr.Body.String())
+			}
+			if tt.expectErrorMsg != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectErrorMsg)
 			}
 		})
 	}
@@ -530,3 +976,71 @@ func TestMetricsHandler_GetAll(t *testing.T) {
 func strPtr(value string) *string {
 	return &value
 }
+
+func intPtr(value int64) *int64 {
+	return &value
+}
+
+func TestSetupRouter_Decrypt(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, encrypt.MinKeyBits)
+	require.NoError(t, err)
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, err = writer.Write([]byte(`[{"id":"PollCount","type":"counter","delta":2}]`))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	payload, err := encrypt.NewRSAEncryptor(&key.PublicKey).Encrypt(compressed.Bytes())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		decryptor      encrypt.Decryptor
+		setupMock      func(m *service.MockMetricsService)
+		expectedStatus int
+	}{
+		{
+			name:      "decrypted, decompressed and saved",
+			decryptor: encrypt.NewRSADecryptor(key),
+			setupMock: func(m *service.MockMetricsService) {
+				m.EXPECT().
+					SaveAll(mock.Anything, []models.Metrics{{ID: "PollCount", MType: models.Counter, Delta: intPtr(2)}}).
+					Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "no private key",
+			setupMock:      func(m *service.MockMetricsService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetricsService(t)
+			tt.setupMock(mockService)
+
+			metricsHandler, err := NewMetricsHandler(mockService)
+			require.NoError(t, err)
+
+			router := SetupRouter(&RouterConfiguration{
+				MetricsHandler: metricsHandler,
+				Decryptor:      tt.decryptor,
+				SignKey:        "secret",
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/updates/", bytes.NewReader(payload))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Content-Encoding", "gzip")
+			req.Header.Set(middleware.EncryptionHeader, encrypt.Scheme)
+			req.Header.Set("Hash", hash.NewSHA256Signer("secret").Sign(payload))
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+)
+
+// GetMetric retrieves a series by type, ID and labels.
+//
+// @Summary Get metric
+// @Description Returns the full structure of a series. Summaries include estimated quantiles.
+// @Tags Metrics
+// @Produce json
+// @Param type path string true "Metric type" Enums(gauge,counter,histogram,summary,set)
+// @Param id path string true "Metric ID"
+// @Param label query []string false "Series label as name=value" collectionFormat(multi)
+// @Success 200 {object} api.Envelope{data=models.Metrics} "Metric data"
+// @Failure 400 {object} api.ErrorEnvelope "Bad Request"
+// @Failure 404 {object} api.ErrorEnvelope "Not Found"
+// @Failure 500 {object} api.ErrorEnvelope "Internal Error"
+// @Router /api/v1/metrics/{type}/{id} [get]
+func (handler *MetricsHandler) GetMetric(w http.ResponseWriter, r *http.Request) {
+	labels, err := parseLabels(r.URL.Query()["label"])
+	if err != nil {
+		api.RespondError(w, api.BadRequest(err.Error()))
+		return
+	}
+
+	metric, getErr := handler.service.GetStruct(r.Context(), r.PathValue("id"), r.PathValue("type"), labels)
+
+	if getErr != nil {
+		api.RespondError(w, getErr)
+		return
+	}
+
+	api.Respond(w, http.StatusOK, metric)
+}
+
+// SaveMetrics saves a batch of metrics.
+//
+// @Summary Save metrics
+// @Description Saves multiple metrics with the semantics of batch updates.
+// @Description By default an invalid metric rejects the whole batch.
+// @Description With partial=true every metric is validated, valid metrics are saved
+// @Description and the response lists rejected metrics by their index in the batch.
+// @Tags Metrics
+// @Accept json
+// @Produce json
+// @Param metrics body []models.Metrics true "Metrics list"
+// @Param partial query bool false "Save valid metrics and report rejected ones"
+// @Param Idempotency-Key header string false "Key of the request, repeated requests with the key get the original response"
+// @Success 200 {object} api.Envelope{data=models.BatchResult} "Per-item results"
+// @Failure 400 {object} api.ErrorEnvelope "Bad Request"
+// @Failure 409 {object} api.ErrorEnvelope "Request with the idempotency key is in progress"
+// @Failure 422 {object} api.ErrorEnvelope "Invalid JSON or idempotency key used for a different request"
+// @Failure 500 {object} api.ErrorEnvelope "Internal Error"
+// @Router /api/v1/metrics [post]
+func (handler *MetricsHandler) SaveMetrics(w http.ResponseWriter, r *http.Request) {
+	partial, err := parseBool(r.URL.Query(), "partial")
+	if err != nil {
+		api.RespondError(w, api.BadRequest(err.Error()))
+		return
+	}
+
+	metrics := make([]models.Metrics, 0)
+	if err := json.NewDecoder(r.Body).Decode(&metrics); err != nil {
+		api.RespondError(w, api.UnprocessibleEntity("Invalid input JSON"))
+		return
+	}
+
+	if partial {
+		result, saveErr := handler.service.SaveBatch(r.Context(), metrics)
+		if saveErr != nil {
+			api.RespondError(w, saveErr)
+			return
+		}
+
+		api.Respond(w, http.StatusOK, result)
+		return
+	}
+
+	if saveErr := handler.service.SaveAll(r.Context(), metrics); saveErr != nil {
+		api.RespondError(w, saveErr)
+		return
+	}
+
+	api.Respond(w, http.StatusOK, models.BatchResult{Accepted: len(metrics)})
+}
+
+// QueryMetrics retrieves multiple series in a single request.
+//
+// @Summary Query metrics
+// @Description Returns full structures of the requested series in request order.
+// @Description Series that don't exist are omitted. Summaries include estimated quantiles.
+// @Tags Metrics
+// @Accept json
+// @Produce json
+// @Param metrics body []models.SeriesRef true "Series identifiers"
+// @Success 200 {object} api.Envelope{data=[]models.Metrics} "Metrics data"
+// @Failure 400 {object} api.ErrorEnvelope "Bad Request"
+// @Failure 422 {object} api.ErrorEnvelope "Invalid JSON"
+// @Failure 500 {object} api.ErrorEnvelope "Internal Error"
+// @Router /api/v1/metrics/query [post]
+func (handler *MetricsHandler) QueryMetrics(w http.ResponseWriter, r *http.Request) {
+	refs := make([]models.SeriesRef, 0)
+	if err := json.NewDecoder(r.Body).Decode(&refs); err != nil {
+		api.RespondError(w, api.UnprocessibleEntity("Invalid input JSON"))
+		return
+	}
+
+	metrics, getErr := handler.service.GetMany(r.Context(), refs)
+
+	if getErr != nil {
+		api.RespondError(w, getErr)
+		return
+	}
+
+	api.Respond(w, http.StatusOK, metrics)
+}
+
+// DeleteMetric removes all series of a metric.
+//
+// @Summary Delete metric
+// @Description Deletes all series of a metric, whatever their labels, together with their history.
+// @Tags Metrics
+// @Param type path string true "Metric type" Enums(gauge,counter,histogram,summary,set)
+// @Param id path string true "Metric ID"
+// @Success 204 "Metric deleted"
+// @Failure 404 {object} api.ErrorEnvelope "Not Found"
+// @Failure 500 {object} api.ErrorEnvelope "Internal Error"
+// @Router /api/v1/metrics/{type}/{id} [delete]
+func (handler *MetricsHandler) DeleteMetric(w http.ResponseWriter, r *http.Request) {
+	if err := handler.service.Delete(r.Context(), r.PathValue("id"), r.PathValue("type")); err != nil {
+		api.RespondError(w, err)
+		return
+	}
+
+	api.Respond(w, http.StatusNoContent, nil)
+}
+
+// GetRate computes how a counter or gauge series changed within a recent window.
+//
+// @Summary Get metric rate
+// @Description Returns the increase and per-second rate of a counter, or the per-second derivative of a gauge,
+// @Description within the window ending now. Rates are computed from samples the server keeps in memory
+// @Description at one second resolution for the configured rate window, independently of history storage,
+// @Description so samples saved before a restart are not included.
+// @Tags Metrics
+// @Produce json
+// @Param id path string true "Metric ID"
+// @Param type query string false "Metric type, counter by default" Enums(counter,gauge)
+// @Param window query string false "Window length, e.g. 30s or 5m, 1m by default"
+// @Param label query []string false "Series label as name=value" collectionFormat(multi)
+// @Success 200 {object} api.Envelope{data=models.Rate} "Series rate"
+// @Failure 400 {object} api.ErrorEnvelope "Bad Request"
+// @Failure 404 {object} api.ErrorEnvelope "Not Found"
+// @Failure 500 {object} api.ErrorEnvelope "Internal Error"
+// @Router /api/v1/rate/{id} [get]
+func (handler *MetricsHandler) GetRate(w http.ResponseWriter, r *http.Request) {
+	query, err := parseRateQuery(r)
+	if err != nil {
+		api.RespondError(w, api.BadRequest(err.Error()))
+		return
+	}
+
+	rate, getErr := handler.service.Rate(r.Context(), query)
+
+	if getErr != nil {
+		api.RespondError(w, getErr)
+		return
+	}
+
+	api.Respond(w, http.StatusOK, rate)
+}
+
+// parseRateQuery builds a rate query from the path and URL query parameters.
+// The type defaults to counter and the window to one minute.
+func parseRateQuery(r *http.Request) (models.RateQuery, error) {
+	values := r.URL.Query()
+
+	query := models.RateQuery{
+		ID:     r.PathValue("id"),
+		MType:  models.Counter,
+		Window: time.Minute,
+	}
+
+	if metricType := values.Get("type"); metricType != "" {
+		query.MType = metricType
+	}
+
+	labels, err := parseLabels(values["label"])
+	if err != nil {
+		return models.RateQuery{}, err
+	}
+	query.Labels = labels
+
+	if raw := values.Get("window"); raw != "" {
+		window, err := time.ParseDuration(raw)
+		if err != nil {
+			return models.RateQuery{}, fmt.Errorf("invalid window: %w", err)
+		}
+		query.Window = window
+	}
+
+	return query, nil
+}
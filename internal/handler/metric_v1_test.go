@@ -0,0 +1,419 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/service"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsHandler_GetMetric(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		setupMock    func(m *service.MockMetricsService)
+		expectStatus int
+		expectBody   string
+	}{
+		{
+			name: "found with labels",
+			url:  "/api/v1/metrics/gauge/temp?label=room=lab",
+			setupMock: func(m *service.MockMetricsService) {
+				m.EXPECT().
+					GetStruct(mock.Anything, "temp", models.Gauge, map[string]string{"room": "lab"}).
+					Return(models.Metrics{ID: "temp", MType: models.Gauge, Labels: map[string]string{"room": "lab"}, Value: floatPtr(21.5)}, nil)
+			},
+			expectStatus: http.StatusOK,
+			expectBody:   `{"data":{"id":"temp","type":"gauge","labels":{"room":"lab"},"value":21.5}}` + "\n",
+		},
+		{
+			name:         "invalid label",
+			url:          "/api/v1/metrics/gauge/temp?label=room",
+			setupMock:    func(m *service.MockMetricsService) {},
+			expectStatus: http.StatusBadRequest,
+			expectBody:   `{"error":{"code":"bad_request","message":"invalid label \"room\", expected name=value"}}` + "\n",
+		},
+		{
+			name: "not found",
+			url:  "/api/v1/metrics/gauge/temp",
+			setupMock: func(m *service.MockMetricsService) {
+				m.EXPECT().
+					GetStruct(mock.Anything, "temp", models.Gauge, map[string]string(nil)).
+					Return(models.Metrics{}, api.NotFound("Metric temp with type gauge not found"))
+			},
+			expectStatus: http.StatusNotFound,
+			expectBody:   `{"error":{"code":"not_found","message":"Metric temp with type gauge not found"}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetricsService(t)
+			tt.setupMock(mockService)
+
+			handler, err := NewMetricsHandler(mockService)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			req.SetPathValue("type", models.Gauge)
+			req.SetPathValue("id", "temp")
+			rr := httptest.NewRecorder()
+			rr.Header().Set(api.VersionHeader, "v1")
+
+			handler.GetMetric(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+			assert.Equal(t, tt.expectBody, rr.Body.String())
+		})
+	}
+}
+
+func TestMetricsHandler_SaveMetrics(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		body         string
+		setupMock    func(m *service.MockMetricsService)
+		expectStatus int
+		expectBody   string
+	}{
+		{
+			name: "saved",
+			url:  "/api/v1/metrics",
+			body: `[{"id":"c1","type":"counter","delta":1},{"id":"g1","type":"gauge","value":2}]`,
+			setupMock: func(m *service.MockMetricsService) {
+				m.EXPECT().SaveAll(mock.Anything, mock.MatchedBy(func(metrics []models.Metrics) bool {
+					return len(metrics) == 2
+				})).Return(nil)
+			},
+			expectStatus: http.StatusOK,
+			expectBody:   `{"data":{"accepted":2,"rejected":0}}` + "\n",
+		},
+		{
+			name: "partial",
+			url:  "/api/v1/metrics?partial=true",
+			body: `[{"id":"c1","type":"counter","delta":1},{"id":"g1","type":"gauge"}]`,
+			setupMock: func(m *service.MockMetricsService) {
+				m.EXPECT().SaveBatch(mock.Anything, mock.Anything).Return(models.BatchResult{
+					Accepted: 1,
+					Rejected: 1,
+					Errors:   []models.BatchError{{Index: 1, Error: "gauge value or increment is required"}},
+				}, nil)
+			},
+			expectStatus: http.StatusOK,
+			expectBody:   `{"data":{"accepted":1,"rejected":1,"errors":[{"index":1,"error":"gauge value or increment is required"}]}}` + "\n",
+		},
+		{
+			name: "rejected batch",
+			url:  "/api/v1/metrics",
+			body: `[{"id":"g1","type":"gauge"}]`,
+			setupMock: func(m *service.MockMetricsService) {
+				m.EXPECT().SaveAll(mock.Anything, mock.Anything).Return(api.BadRequest("gauge value or increment is required"))
+			},
+			expectStatus: http.StatusBadRequest,
+			expectBody:   `{"error":{"code":"bad_request","message":"gauge value or increment is required"}}` + "\n",
+		},
+		{
+			name:         "invalid partial",
+			url:          "/api/v1/metrics?partial=maybe",
+			body:         `[]`,
+			setupMock:    func(m *service.MockMetricsService) {},
+			expectStatus: http.StatusBadRequest,
+			expectBody:   `{"error":{"code":"bad_request","message":"invalid partial: maybe"}}` + "\n",
+		},
+		{
+			name:         "invalid json",
+			url:          "/api/v1/metrics",
+			body:         `{"id":"c1"}`,
+			setupMock:    func(m *service.MockMetricsService) {},
+			expectStatus: http.StatusUnprocessableEntity,
+			expectBody:   `{"error":{"code":"unprocessable_entity","message":"Invalid input JSON"}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetricsService(t)
+			tt.setupMock(mockService)
+
+			handler, err := NewMetricsHandler(mockService)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			rr.Header().Set(api.VersionHeader, "v1")
+
+			handler.SaveMetrics(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+			assert.Equal(t, tt.expectBody, rr.Body.String())
+		})
+	}
+}
+
+func TestMetricsHandler_QueryMetrics(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		setupMock    func(m *service.MockMetricsService)
+		expectStatus int
+		expectBody   string
+	}{
+		{
+			name: "found metrics",
+			body: `[{"id":"requests","type":"counter"}]`,
+			setupMock: func(m *service.MockMetricsService) {
+				m.EXPECT().
+					GetMany(mock.Anything, []models.SeriesRef{{ID: "requests", MType: models.Counter}}).
+					Return([]models.Metrics{{ID: "requests", MType: models.Counter, Delta: intPtr(5)}}, nil)
+			},
+			expectStatus: http.StatusOK,
+			expectBody:   `{"data":[{"id":"requests","type":"counter","delta":5}]}` + "\n",
+		},
+		{
+			name:         "invalid json",
+			body:         `{"id":"requests"}`,
+			setupMock:    func(m *service.MockMetricsService) {},
+			expectStatus: http.StatusUnprocessableEntity,
+			expectBody:   `{"error":{"code":"unprocessable_entity","message":"Invalid input JSON"}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetricsService(t)
+			tt.setupMock(mockService)
+
+			handler, err := NewMetricsHandler(mockService)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/metrics/query", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			rr.Header().Set(api.VersionHeader, "v1")
+
+			handler.QueryMetrics(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+			assert.Equal(t, tt.expectBody, rr.Body.String())
+		})
+	}
+}
+
+func TestMetricsHandler_DeleteMetric(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockError    *api.APIError
+		expectStatus int
+		expectBody   string
+	}{
+		{
+			name:         "deleted",
+			expectStatus: http.StatusNoContent,
+			expectBody:   "",
+		},
+		{
+			name:         "not found",
+			mockError:    api.NotFound("Metric g1 with type gauge not found"),
+			expectStatus: http.StatusNotFound,
+			expectBody:   `{"error":{"code":"not_found","message":"Metric g1 with type gauge not found"}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetricsService(t)
+			mockService.EXPECT().Delete(mock.Anything, "g1", models.Gauge).Return(tt.mockError)
+
+			handler, err := NewMetricsHandler(mockService)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/metrics/gauge/g1", nil)
+			req.SetPathValue("type", models.Gauge)
+			req.SetPathValue("id", "g1")
+			rr := httptest.NewRecorder()
+			rr.Header().Set(api.VersionHeader, "v1")
+
+			handler.DeleteMetric(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+			assert.Equal(t, tt.expectBody, rr.Body.String())
+		})
+	}
+}
+
+func TestMetricsHandler_GetRate(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		setupMock    func(m *service.MockMetricsService)
+		expectStatus int
+		expectBody   string
+	}{
+		{
+			name: "counter by default",
+			url:  "/api/v1/rate/requests",
+			setupMock: func(m *service.MockMetricsService) {
+				m.EXPECT().
+					Rate(mock.Anything, models.RateQuery{ID: "requests", MType: models.Counter, Window: time.Minute}).
+					Return(models.Rate{ID: "requests", MType: models.Counter, Window: 60, Samples: 3, Increase: intPtr(120), Rate: floatPtr(2)}, nil)
+			},
+			expectStatus: http.StatusOK,
+			expectBody:   `{"data":{"id":"requests","type":"counter","window":60,"samples":3,"increase":120,"rate":2}}` + "\n",
+		},
+		{
+			name: "gauge with window and labels",
+			url:  "/api/v1/rate/requests?type=gauge&window=5m&label=host=a",
+			setupMock: func(m *service.MockMetricsService) {
+				m.EXPECT().
+					Rate(mock.Anything, models.RateQuery{
+						ID: "requests", MType: models.Gauge, Labels: map[string]string{"host": "a"}, Window: 5 * time.Minute,
+					}).
+					Return(models.Rate{ID: "requests", MType: models.Gauge, Labels: map[string]string{"host": "a"}, Window: 300, Samples: 2, Derivative: floatPtr(-0.5)}, nil)
+			},
+			expectStatus: http.StatusOK,
+			expectBody:   `{"data":{"id":"requests","type":"gauge","labels":{"host":"a"},"window":300,"samples":2,"derivative":-0.5}}` + "\n",
+		},
+		{
+			name:         "invalid window",
+			url:          "/api/v1/rate/requests?window=soon",
+			setupMock:    func(m *service.MockMetricsService) {},
+			expectStatus: http.StatusBadRequest,
+			expectBody:   `{"error":{"code":"bad_request","message":"invalid window: time: invalid duration \"soon\""}}` + "\n",
+		},
+		{
+			name:         "invalid label",
+			url:          "/api/v1/rate/requests?label=host",
+			setupMock:    func(m *service.MockMetricsService) {},
+			expectStatus: http.StatusBadRequest,
+			expectBody:   `{"error":{"code":"bad_request","message":"invalid label \"host\", expected name=value"}}` + "\n",
+		},
+		{
+			name: "service error",
+			url:  "/api/v1/rate/requests",
+			setupMock: func(m *service.MockMetricsService) {
+				m.EXPECT().
+					Rate(mock.Anything, mock.Anything).
+					Return(models.Rate{}, api.NotFound("metric requests counter not found"))
+			},
+			expectStatus: http.StatusNotFound,
+			expectBody:   `{"error":{"code":"not_found","message":"metric requests counter not found"}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetricsService(t)
+			tt.setupMock(mockService)
+
+			handler, err := NewMetricsHandler(mockService)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			req.SetPathValue("id", "requests")
+			rr := httptest.NewRecorder()
+			rr.Header().Set(api.VersionHeader, "v1")
+
+			handler.GetRate(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+			assert.Equal(t, tt.expectBody, rr.Body.String())
+		})
+	}
+}
+
+func TestSetupRouter_APIv1(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		url           string
+		tenantKeys    map[string]string
+		setupMock     func(m *service.MockMetricsService)
+		expectStatus  int
+		expectBody    string
+		expectVersion string
+	}{
+		{
+			name:   "enveloped response",
+			method: http.MethodGet,
+			url:    "/api/v1/metrics/counter/requests",
+			setupMock: func(m *service.MockMetricsService) {
+				m.EXPECT().
+					GetStruct(mock.Anything, "requests", models.Counter, map[string]string(nil)).
+					Return(models.Metrics{ID: "requests", MType: models.Counter, Delta: intPtr(5)}, nil)
+			},
+			expectStatus:  http.StatusOK,
+			expectBody:    `{"data":{"id":"requests","type":"counter","delta":5}}` + "\n",
+			expectVersion: "v1",
+		},
+		{
+			name:          "unknown route",
+			method:        http.MethodGet,
+			url:           "/api/v1/unknown",
+			setupMock:     func(m *service.MockMetricsService) {},
+			expectStatus:  http.StatusNotFound,
+			expectBody:    `{"error":{"code":"not_found","message":"Route not found"}}` + "\n",
+			expectVersion: "v1",
+		},
+		{
+			name:          "method not allowed",
+			method:        http.MethodPatch,
+			url:           "/api/v1/metrics",
+			setupMock:     func(m *service.MockMetricsService) {},
+			expectStatus:  http.StatusMethodNotAllowed,
+			expectBody:    `{"error":{"code":"method_not_allowed","message":"Method is not allowed"}}` + "\n",
+			expectVersion: "v1",
+		},
+		{
+			name:          "tenant error is enveloped",
+			method:        http.MethodGet,
+			url:           "/api/v1/metrics/counter/requests",
+			tenantKeys:    map[string]string{"secret": "team-a"},
+			setupMock:     func(m *service.MockMetricsService) {},
+			expectStatus:  http.StatusUnauthorized,
+			expectBody:    `{"error":{"code":"unauthorized","message":"API key is invalid"}}` + "\n",
+			expectVersion: "v1",
+		},
+		{
+			name:   "legacy route is not enveloped",
+			method: http.MethodGet,
+			url:    "/value/counter/requests",
+			setupMock: func(m *service.MockMetricsService) {
+				m.EXPECT().Get(mock.Anything, "requests", models.Counter).Return(int64(5), nil)
+			},
+			expectStatus: http.StatusOK,
+			expectBody:   "5\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetricsService(t)
+			tt.setupMock(mockService)
+
+			metricsHandler, err := NewMetricsHandler(mockService)
+			require.NoError(t, err)
+
+			router := SetupRouter(&RouterConfiguration{
+				MetricsHandler: metricsHandler,
+				TenantKeys:     tt.tenantKeys,
+			})
+
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+			assert.Equal(t, tt.expectBody, rr.Body.String())
+			assert.Equal(t, tt.expectVersion, rr.Header().Get(api.VersionHeader))
+		})
+	}
+}
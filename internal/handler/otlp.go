@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gabkaclassic/metrics/internal/otlp"
+	"github.com/gabkaclassic/metrics/internal/service"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+)
+
+// OTLPHandler serves the OTLP/HTTP metrics receiver.
+type OTLPHandler struct {
+	service    service.MetricsService
+	translator *otlp.Translator
+}
+
+// NewOTLPHandler creates a new OTLP handler.
+//
+// Returns:
+//   - *OTLPHandler: Ready-to-use handler
+//   - error: If service or translator is nil
+func NewOTLPHandler(service service.MetricsService, translator *otlp.Translator) (*OTLPHandler, error) {
+	if service == nil {
+		return nil, errors.New("create new otlp handler failed: service is nil")
+	}
+
+	if translator == nil {
+		return nil, errors.New("create new otlp handler failed: translator is nil")
+	}
+
+	return &OTLPHandler{
+		service:    service,
+		translator: translator,
+	}, nil
+}
+
+// Export saves metrics of an OTLP export request.
+//
+// @Summary Export OTLP metrics
+// @Description Accepts the OTLP/HTTP JSON encoding of metric exports, protobuf bodies are not supported.
+// @Description Gauges are saved as gauges, monotonic sums as counters with cumulative totals
+// @Description converted to deltas per series, non-monotonic sums as gauges of their current value.
+// @Description Resource and data point attributes become series labels, with names sanitized
+// @Description into label names (service.name becomes service_name).
+// @Description Histograms, exponential histograms, summaries, fractional monotonic sums and invalid data points are rejected
+// @Description and reported as partial success, accepted data points are saved.
+// @Tags Metrics
+// @Accept json
+// @Produce json
+// @Param request body otlp.ExportMetricsServiceRequest true "Export request"
+// @Success 200 {object} otlp.ExportMetricsServiceResponse "Accepted data points saved"
+// @Failure 400 {object} api.APIError "Bad Request"
+// @Failure 500 {object} api.APIError "Internal Error"
+// @Router /v1/metrics [post]
+func (handler *OTLPHandler) Export(w http.ResponseWriter, r *http.Request) {
+	var request otlp.ExportMetricsServiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		api.RespondError(w, api.BadRequest("Invalid OTLP JSON"))
+		return
+	}
+
+	metrics, increases, partialSuccess := handler.translator.Translate(middleware.TenantFromCtx(r.Context()), request)
+
+	if len(metrics) > 0 {
+		if saveErr := handler.service.SaveAll(r.Context(), metrics); saveErr != nil {
+			// Increases that were not saved are taken back,
+			// so a retried request counts them again
+			handler.translator.Rollback(increases)
+			api.RespondError(w, saveErr)
+			return
+		}
+	}
+
+	response := otlp.ExportMetricsServiceResponse{PartialSuccess: partialSuccess}
+	if encodeErr := json.NewEncoder(w).Encode(response); encodeErr != nil {
+		api.RespondError(w, encodeErr)
+	}
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/otlp"
+	"github.com/gabkaclassic/metrics/internal/service"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOTLPHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		service    service.MetricsService
+		translator *otlp.Translator
+		wantErr    string
+	}{
+		{
+			name:       "valid",
+			service:    service.NewMockMetricsService(t),
+			translator: otlp.NewTranslator(time.Hour),
+		},
+		{
+			name:       "nil service",
+			translator: otlp.NewTranslator(time.Hour),
+			wantErr:    "create new otlp handler failed: service is nil",
+		},
+		{
+			name:    "nil translator",
+			service: service.NewMockMetricsService(t),
+			wantErr: "create new otlp handler failed: translator is nil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, err := NewOTLPHandler(tt.service, tt.translator)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, handler)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, handler)
+			}
+		})
+	}
+}
+
+func TestOTLPHandler_Export(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(m *service.MockMetricsService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "all accepted",
+			body: `{"resourceMetrics":[{
+				"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"checkout"}}]},
+				"scopeMetrics":[{"metrics":[{"name":"http.requests","sum":{"aggregationTemporality":1,"isMonotonic":true,"dataPoints":[{"asInt":"3"}]}}]}]
+			}]}`,
+			setupMock: func(m *service.MockMetricsService) {
+				m.EXPECT().
+					SaveAll(mock.MatchedBy(func(ctx context.Context) bool {
+						return middleware.TenantFromCtx(ctx) == "team-a"
+					}), []models.Metrics{
+						{ID: "http.requests", MType: models.Counter, Labels: map[string]string{"service_name": "checkout"}, Delta: intPtr(3)},
+					}).
+					Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "{}\n",
+		},
+		{
+			name: "partial success",
+			body: `{"resourceMetrics":[{"scopeMetrics":[{"metrics":[
+				{"name":"load","gauge":{"dataPoints":[{"asDouble":0.5}]}},
+				{"name":"http.duration","histogram":{"dataPoints":[{}]}}
+			]}]}]}`,
+			setupMock: func(m *service.MockMetricsService) {
+				m.EXPECT().
+					SaveAll(mock.Anything, []models.Metrics{{ID: "load", MType: models.Gauge, Value: floatPtr(0.5)}}).
+					Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"partialSuccess":{"rejectedDataPoints":1,"errorMessage":"histogram \"http.duration\" is not supported"}}` + "\n",
+		},
+		{
+			name:           "nothing accepted",
+			body:           `{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"name":"rpc.duration","summary":{"dataPoints":[{}]}}]}]}]}`,
+			setupMock:      func(m *service.MockMetricsService) {},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"partialSuccess":{"rejectedDataPoints":1,"errorMessage":"summary \"rpc.duration\" is not supported"}}` + "\n",
+		},
+		{
+			name:           "invalid json",
+			body:           `{"resourceMetrics":`,
+			setupMock:      func(m *service.MockMetricsService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid OTLP JSON",
+		},
+		{
+			name: "service error",
+			body: `{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"name":"load","gauge":{"dataPoints":[{"asDouble":1}]}}]}]}]}`,
+			setupMock: func(m *service.MockMetricsService) {
+				m.EXPECT().SaveAll(mock.Anything, mock.Anything).Return(api.Internal("Save failed", nil))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Save failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetricsService(t)
+			tt.setupMock(mockService)
+
+			handler, err := NewOTLPHandler(mockService, otlp.NewTranslator(time.Hour))
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/metrics", strings.NewReader(tt.body))
+			req = req.WithContext(middleware.WithTenant(req.Context(), "team-a"))
+			rr := httptest.NewRecorder()
+
+			handler.Export(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestOTLPHandler_Export_retry(t *testing.T) {
+	body := `{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"name":"http.requests","sum":{
+		"aggregationTemporality":2,"isMonotonic":true,
+		"dataPoints":[{"startTimeUnixNano":"4102444800000000000","asInt":"7"}]
+	}}]}]}]}`
+	expected := []models.Metrics{{ID: "http.requests", MType: models.Counter, Delta: intPtr(7)}}
+
+	mockService := service.NewMockMetricsService(t)
+	mockService.EXPECT().SaveAll(mock.Anything, expected).Return(api.Internal("Save failed", nil)).Once()
+	mockService.EXPECT().SaveAll(mock.Anything, expected).Return(nil).Once()
+
+	handler, err := NewOTLPHandler(mockService, otlp.NewTranslator(time.Hour))
+	require.NoError(t, err)
+
+	for _, expectedStatus := range []int{http.StatusInternalServerError, http.StatusOK} {
+		req := httptest.NewRequest(http.MethodPost, "/v1/metrics", strings.NewReader(body))
+		rr := httptest.NewRecorder()
+
+		handler.Export(rr, req)
+
+		assert.Equal(t, expectedStatus, rr.Code)
+	}
+}
+
+func TestSetupRouter_OTLP(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		expectedStatus int
+	}{
+		{name: "json", contentType: "application/json", expectedStatus: http.StatusOK},
+		{name: "protobuf", contentType: "application/x-protobuf", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			otlpHandler, err := NewOTLPHandler(service.NewMockMetricsService(t), otlp.NewTranslator(time.Hour))
+			require.NoError(t, err)
+
+			router := SetupRouter(&RouterConfiguration{OTLPHandler: otlpHandler})
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/metrics", strings.NewReader(`{"resourceMetrics":[]}`))
+			req.Header.Set("Content-Type", tt.contentType)
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
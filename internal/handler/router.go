@@ -3,6 +3,8 @@ package handler
 import (
 	"net/http"
 
+	"github.com/gabkaclassic/metrics/pkg/encrypt"
+	api "github.com/gabkaclassic/metrics/pkg/error"
 	"github.com/gabkaclassic/metrics/pkg/middleware"
 	"github.com/go-chi/chi/v5"
 )
@@ -14,9 +16,37 @@ type RouterConfiguration struct {
 	// Must be initialized before router setup.
 	MetricsHandler *MetricsHandler
 
+	// MetaHandler handles metric metadata endpoints.
+	// Must be initialized before router setup.
+	MetaHandler *MetaHandler
+
+	// AlertHandler handles alert rule and alert state endpoints.
+	// Must be initialized before router setup.
+	AlertHandler *AlertHandler
+
+	// StreamHandler handles the metric change stream endpoint.
+	// Must be initialized before router setup.
+	StreamHandler *StreamHandler
+
+	// OTLPHandler handles the OTLP/HTTP metrics receiver endpoint.
+	// Must be initialized before router setup.
+	OTLPHandler *OTLPHandler
+
+	// IdempotencyHandler applies idempotency keys to metric updates.
+	// If nil, Idempotency-Key headers are ignored.
+	IdempotencyHandler *IdempotencyHandler
+
+	// Decryptor decrypts request bodies marked with the X-Encryption header.
+	// If nil, encrypted request bodies are rejected.
+	Decryptor encrypt.Decryptor
+
 	// SignKey is the secret key used for request signature verification.
 	// If empty, signature verification middleware is disabled.
 	SignKey string
+
+	// TenantKeys maps API keys to tenant names.
+	// If empty, the tenant is taken from the X-Tenant header.
+	TenantKeys map[string]string
 }
 
 // SetupRouter configures and returns a fully initialized HTTP router with all middleware.
@@ -29,18 +59,50 @@ type RouterConfiguration struct {
 // The router includes:
 //   - Request logging
 //   - Audit context propagation
+//   - Tenant resolution (all routes except ping)
 //   - Compression/decompression
+//   - Request body decryption (if Decryptor provided)
 //   - Content type validation
 //   - Request signature verification (if SignKey provided)
+//   - Idempotency keys of metric updates (if IdempotencyHandler provided)
 //
 // Routes configured:
 //   - GET  /ping     - Health check endpoint
+//   - GET  /static/* - Embedded dashboard assets
 //   - GET  /         - HTML metrics dashboard
+//   - GET  /series/{type}/{id} - HTML series detail page
+//   - GET  /metrics  - Prometheus text exposition
 //   - POST /update/  - JSON metric update (single)
 //   - POST /updates/ - JSON metric batch update
 //   - POST /value/   - JSON metric retrieval
+//   - POST /values/  - JSON metric batch retrieval
 //   - POST /update/{type}/{id}/{value} - Plain text metric update
 //   - GET  /value/{type}/{id} - Plain text metric retrieval
+//   - DELETE /value/{type}/{id} - Metric deletion
+//   - POST /api/v2/write - InfluxDB line protocol write
+//   - POST /v1/metrics - OTLP/HTTP JSON metrics export
+//
+// Versioned routes of the /api/v1 group wrap JSON responses in
+// { "data": ... } and errors in { "error": { "code": ..., "message": ... } }:
+//   - GET  /api/v1/metrics - Metric listing with filters and pagination
+//   - POST /api/v1/metrics - Metric batch update
+//   - DELETE /api/v1/metrics - Bulk metric deletion by glob pattern
+//   - POST /api/v1/metrics/query - Metric batch retrieval
+//   - GET  /api/v1/metrics/{type}/{id} - Metric retrieval
+//   - DELETE /api/v1/metrics/{type}/{id} - Metric deletion
+//   - GET  /api/v1/values - Metric listing, alias of GET /api/v1/metrics
+//   - GET  /api/v1/range - Series history retrieval
+//   - GET  /api/v1/rate/{id} - Counter rate or gauge derivative within a recent window
+//   - GET  /api/v1/export - CSV, NDJSON or JSON metric export
+//   - POST /api/v1/import - CSV, NDJSON or JSON metric import
+//   - GET  /api/v1/stream - SSE stream of metric changes
+//   - GET  /api/v1/alerts - Active alert retrieval
+//   - GET  /api/v1/alerts/rules - Alert rule listing
+//   - GET  /api/v1/alerts/rules/{id} - Alert rule retrieval
+//   - PUT  /api/v1/alerts/rules/{id} - Alert rule update
+//   - DELETE /api/v1/alerts/rules/{id} - Alert rule deletion
+//   - GET  /api/v1/meta/{id} - Metric metadata retrieval
+//   - PUT  /api/v1/meta/{id} - Metric metadata update
 func SetupRouter(config *RouterConfiguration) http.Handler {
 
 	router := chi.NewRouter()
@@ -53,29 +115,81 @@ func SetupRouter(config *RouterConfiguration) http.Handler {
 	// Ping endpoint
 	router.Get("/ping", func(w http.ResponseWriter, r *http.Request) {})
 
-	setupMetricsRouter(router, config.MetricsHandler, middleware.Decompress(), middleware.SignVerify(config.SignKey))
+	// Dashboard assets are shared by all tenants
+	router.Get(
+		"/static/*",
+		middleware.Wrap(
+			http.HandlerFunc(config.MetricsHandler.Static),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.CSS: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.CSS),
+		),
+	)
+
+	idempotencyMiddleware := func(handler http.Handler) http.Handler { return handler }
+	if config.IdempotencyHandler != nil {
+		idempotencyMiddleware = config.IdempotencyHandler.Middleware
+	}
+
+	// Bodies are decrypted before they are decompressed,
+	// signatures are verified against bodies as sent
+	decompress := func(handler http.Handler) http.Handler {
+		return middleware.Wrap(handler, middleware.Decompress(), middleware.Decrypt(config.Decryptor))
+	}
+
+	tenantRouter := router.With(middleware.Tenant(config.TenantKeys))
+
+	setupMetricsRouter(tenantRouter, config.MetricsHandler, decompress, middleware.SignVerify(config.SignKey), idempotencyMiddleware)
+	setupOTLPRouter(tenantRouter, config.OTLPHandler, decompress)
+
+	// Versioned API, the version is set first so that tenant errors are enveloped too
+	router.Route("/api/v1", func(v1 chi.Router) {
+		v1.Use(
+			middleware.APIVersion("v1"),
+			middleware.Tenant(config.TenantKeys),
+		)
+
+		v1.NotFound(func(w http.ResponseWriter, r *http.Request) {
+			api.RespondError(w, api.NotFound("Route not found"))
+		})
+		v1.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+			api.RespondError(w, api.NotAllowed())
+		})
+
+		setupMetricsV1Router(v1, config.MetricsHandler, decompress, middleware.SignVerify(config.SignKey), idempotencyMiddleware)
+		setupMetaRouter(v1, config.MetaHandler, decompress, middleware.SignVerify(config.SignKey))
+		setupStreamRouter(v1, config.StreamHandler)
+		setupAlertRouter(v1, config.AlertHandler, decompress, middleware.SignVerify(config.SignKey))
+	})
 
 	return router
 }
 
-// setupMetricsRouter configures all metrics-related routes with appropriate middleware.
+// setupMetricsRouter configures unversioned metrics routes with appropriate middleware.
 // This function separates metrics route configuration for better organization.
 //
 // router: Chi router instance to register routes on.
 // handler: Metrics handler implementing endpoint logic.
 // decompressMiddleware: Middleware for decompressing request bodies (gzip).
 // signVerifyMiddleware: Middleware for verifying request signatures (HMAC).
+// idempotencyMiddleware: Middleware replaying responses of repeated idempotency keys.
 //
 // Middleware composition per route:
 //   - All routes: decompression, content type headers
 //   - Write operations: signature verification (if key provided)
+//   - Metric updates: idempotency keys
+//   - Line protocol writes: no content type requirement or signature verification,
+//     InfluxDB clients send plain text and can't sign requests
 //   - JSON endpoints: content type validation, compression
 //   - HTML endpoint: HTML-specific compression
+//   - Prometheus endpoint: exposition-format-specific compression
 func setupMetricsRouter(
-	router *chi.Mux,
+	router chi.Router,
 	handler *MetricsHandler,
 	decompressMiddleware func(handler http.Handler) http.Handler,
 	signVerifyMiddleware func(handler http.Handler) http.Handler,
+	idempotencyMiddleware func(handler http.Handler) http.Handler,
 ) {
 	// Metrics
 	router.Get(
@@ -90,10 +204,34 @@ func setupMetricsRouter(
 			decompressMiddleware,
 		),
 	)
+	router.Get(
+		"/series/{type}/{id}",
+		middleware.Wrap(
+			http.HandlerFunc(handler.Series),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.HTML:     middleware.GZIP,
+				middleware.HTMLUTF8: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.HTML),
+			decompressMiddleware,
+		),
+	)
+	router.Get(
+		"/metrics",
+		middleware.Wrap(
+			http.HandlerFunc(handler.Prometheus),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.PROMETHEUS: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.PROMETHEUS),
+			decompressMiddleware,
+		),
+	)
 	router.Post(
 		"/update/",
 		middleware.Wrap(
 			http.HandlerFunc(handler.SaveJSON),
+			idempotencyMiddleware,
 			middleware.RequireContentType(middleware.JSON),
 			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
 				middleware.JSON: middleware.GZIP,
@@ -107,6 +245,7 @@ func setupMetricsRouter(
 		"/updates/",
 		middleware.Wrap(
 			http.HandlerFunc(handler.SaveAll),
+			idempotencyMiddleware,
 			middleware.RequireContentType(middleware.JSON),
 			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
 				middleware.JSON: middleware.GZIP,
@@ -128,10 +267,23 @@ func setupMetricsRouter(
 			decompressMiddleware,
 		),
 	)
+	router.Post(
+		"/values/",
+		middleware.Wrap(
+			http.HandlerFunc(handler.GetMany),
+			middleware.RequireContentType(middleware.JSON),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.JSON: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+		),
+	)
 	router.Post(
 		"/update/{type}/{id}/{value}",
 		middleware.Wrap(
 			http.HandlerFunc(handler.Save),
+			idempotencyMiddleware,
 			middleware.WithContentType(middleware.TEXT),
 			decompressMiddleware,
 		),
@@ -147,4 +299,325 @@ func setupMetricsRouter(
 			decompressMiddleware,
 		),
 	)
+	router.Delete(
+		"/value/{type}/{id}",
+		middleware.Wrap(
+			http.HandlerFunc(handler.Delete),
+			middleware.WithContentType(middleware.TEXT),
+			decompressMiddleware,
+			signVerifyMiddleware,
+		),
+	)
+	router.Post(
+		"/api/v2/write",
+		middleware.Wrap(
+			http.HandlerFunc(handler.Write),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+		),
+	)
+}
+
+// setupMetricsV1Router configures metrics routes of the /api/v1 group.
+//
+// router: Chi router of the /api/v1 group, paths are relative to it.
+// handler: Metrics handler implementing endpoint logic.
+// decompressMiddleware: Middleware for decompressing request bodies (gzip).
+// signVerifyMiddleware: Middleware for verifying request signatures (HMAC).
+// idempotencyMiddleware: Middleware replaying responses of repeated idempotency keys.
+//
+// Middleware composition per route matches setupMetricsRouter,
+// metric updates and imports also get idempotency keys
+// and the export endpoint gets format-specific content type and compression.
+func setupMetricsV1Router(
+	router chi.Router,
+	handler *MetricsHandler,
+	decompressMiddleware func(handler http.Handler) http.Handler,
+	signVerifyMiddleware func(handler http.Handler) http.Handler,
+	idempotencyMiddleware func(handler http.Handler) http.Handler,
+) {
+	router.Get(
+		"/metrics",
+		middleware.Wrap(
+			http.HandlerFunc(handler.List),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.JSON: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+		),
+	)
+	router.Post(
+		"/metrics",
+		middleware.Wrap(
+			http.HandlerFunc(handler.SaveMetrics),
+			idempotencyMiddleware,
+			middleware.RequireContentType(middleware.JSON),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.JSON: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+			signVerifyMiddleware,
+		),
+	)
+	router.Post(
+		"/metrics/query",
+		middleware.Wrap(
+			http.HandlerFunc(handler.QueryMetrics),
+			middleware.RequireContentType(middleware.JSON),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.JSON: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+		),
+	)
+	router.Get(
+		"/metrics/{type}/{id}",
+		middleware.Wrap(
+			http.HandlerFunc(handler.GetMetric),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.JSON: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+		),
+	)
+	router.Delete(
+		"/metrics/{type}/{id}",
+		middleware.Wrap(
+			http.HandlerFunc(handler.DeleteMetric),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+			signVerifyMiddleware,
+		),
+	)
+	router.Delete(
+		"/metrics",
+		middleware.Wrap(
+			http.HandlerFunc(handler.DeleteAll),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.JSON: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+			signVerifyMiddleware,
+		),
+	)
+	router.Get(
+		"/values",
+		middleware.Wrap(
+			http.HandlerFunc(handler.List),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.JSON: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+		),
+	)
+	router.Get(
+		"/range",
+		middleware.Wrap(
+			http.HandlerFunc(handler.GetRange),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.JSON: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+		),
+	)
+	router.Get(
+		"/rate/{id}",
+		middleware.Wrap(
+			http.HandlerFunc(handler.GetRate),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.JSON: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+		),
+	)
+	router.Get(
+		"/export",
+		middleware.Wrap(
+			http.HandlerFunc(handler.Export),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.CSV:    middleware.GZIP,
+				middleware.NDJSON: middleware.GZIP,
+				middleware.JSON:   middleware.GZIP,
+			}),
+			exportContentType,
+			decompressMiddleware,
+		),
+	)
+	router.Post(
+		"/import",
+		middleware.Wrap(
+			http.HandlerFunc(handler.Import),
+			idempotencyMiddleware,
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.JSON: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+			signVerifyMiddleware,
+		),
+	)
+}
+
+// setupOTLPRouter configures the OTLP/HTTP metrics receiver route.
+//
+// router: Chi router instance to register routes on.
+// handler: OTLP handler implementing endpoint logic.
+// decompressMiddleware: Middleware for decompressing request bodies (gzip).
+//
+// Exports require JSON content type and skip signature verification,
+// as OpenTelemetry exporters can't sign requests.
+func setupOTLPRouter(
+	router chi.Router,
+	handler *OTLPHandler,
+	decompressMiddleware func(handler http.Handler) http.Handler,
+) {
+	router.Post(
+		"/v1/metrics",
+		middleware.Wrap(
+			http.HandlerFunc(handler.Export),
+			middleware.RequireContentType(middleware.JSON),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.JSON: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+		),
+	)
+}
+
+// setupMetaRouter configures metric metadata routes.
+//
+// router: Chi router of the /api/v1 group, paths are relative to it.
+// handler: Metadata handler implementing endpoint logic.
+// decompressMiddleware: Middleware for decompressing request bodies (gzip).
+// signVerifyMiddleware: Middleware for verifying request signatures (HMAC).
+//
+// Updates require JSON content type and pass signature verification.
+func setupMetaRouter(
+	router chi.Router,
+	handler *MetaHandler,
+	decompressMiddleware func(handler http.Handler) http.Handler,
+	signVerifyMiddleware func(handler http.Handler) http.Handler,
+) {
+	router.Get(
+		"/meta/{id}",
+		middleware.Wrap(
+			http.HandlerFunc(handler.Get),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.JSON: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+		),
+	)
+	router.Put(
+		"/meta/{id}",
+		middleware.Wrap(
+			http.HandlerFunc(handler.Put),
+			middleware.RequireContentType(middleware.JSON),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.JSON: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+			signVerifyMiddleware,
+		),
+	)
+}
+
+// setupStreamRouter configures the metric change stream route.
+//
+// router: Chi router of the /api/v1 group, paths are relative to it.
+// handler: Stream handler implementing endpoint logic.
+//
+// Streamed responses are not compressed, so that every event is
+// delivered as soon as it is written.
+func setupStreamRouter(router chi.Router, handler *StreamHandler) {
+	router.Get(
+		"/stream",
+		middleware.Wrap(
+			http.HandlerFunc(handler.Stream),
+			middleware.WithContentType(middleware.EVENTSTREAM),
+		),
+	)
+}
+
+// setupAlertRouter configures alert rule and alert state routes.
+//
+// router: Chi router of the /api/v1 group, paths are relative to it.
+// handler: Alert handler implementing endpoint logic.
+// decompressMiddleware: Middleware for decompressing request bodies (gzip).
+// signVerifyMiddleware: Middleware for verifying request signatures (HMAC).
+//
+// Rule updates and deletions pass signature verification,
+// updates require JSON content type.
+func setupAlertRouter(
+	router chi.Router,
+	handler *AlertHandler,
+	decompressMiddleware func(handler http.Handler) http.Handler,
+	signVerifyMiddleware func(handler http.Handler) http.Handler,
+) {
+	router.Get(
+		"/alerts",
+		middleware.Wrap(
+			http.HandlerFunc(handler.GetAlerts),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.JSON: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+		),
+	)
+	router.Get(
+		"/alerts/rules",
+		middleware.Wrap(
+			http.HandlerFunc(handler.GetRules),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.JSON: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+		),
+	)
+	router.Get(
+		"/alerts/rules/{id}",
+		middleware.Wrap(
+			http.HandlerFunc(handler.GetRule),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.JSON: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+		),
+	)
+	router.Put(
+		"/alerts/rules/{id}",
+		middleware.Wrap(
+			http.HandlerFunc(handler.PutRule),
+			middleware.RequireContentType(middleware.JSON),
+			middleware.Compress(map[middleware.ContentType]middleware.CompressType{
+				middleware.JSON: middleware.GZIP,
+			}),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+			signVerifyMiddleware,
+		),
+	)
+	router.Delete(
+		"/alerts/rules/{id}",
+		middleware.Wrap(
+			http.HandlerFunc(handler.DeleteRule),
+			middleware.WithContentType(middleware.JSON),
+			decompressMiddleware,
+			signVerifyMiddleware,
+		),
+	)
 }
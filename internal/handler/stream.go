@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/stream"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+)
+
+const (
+	// streamEvent is the SSE event name of metric changes.
+	streamEvent = "metrics"
+
+	// streamRetry is the reconnection delay advised to SSE clients.
+	streamRetry = 3 * time.Second
+
+	// streamHeartbeat is the interval of comments keeping idle streams open.
+	streamHeartbeat = 15 * time.Second
+)
+
+// StreamHandler serves live metric changes as server-sent events.
+type StreamHandler struct {
+	broker *stream.Broker
+}
+
+// NewStreamHandler creates a new metric stream handler.
+//
+// Returns:
+//   - *StreamHandler: Ready-to-use handler
+//   - error: If broker is nil
+func NewStreamHandler(broker *stream.Broker) (*StreamHandler, error) {
+	if broker == nil {
+		return nil, errors.New("create new stream handler failed: broker is nil")
+	}
+
+	return &StreamHandler{
+		broker: broker,
+	}, nil
+}
+
+// streamFilter selects and throttles streamed series.
+type streamFilter struct {
+	ids      []string
+	throttle time.Duration
+}
+
+// Stream pushes metric changes of the request tenant as server-sent events.
+//
+// @Summary Stream metric changes (SSE)
+// @Description Pushes current states of series as they are saved, one "metrics" event per save.
+// @Description Event data is a JSON array of metrics, series saved by a single batch update share one event.
+// @Description Clients resume after reconnecting by sending the ID of the last received event in the Last-Event-ID header;
+// @Description recent events are replayed, older ones are lost.
+// @Description With throttle set, every series is sent at most once per interval and only its latest state is kept back.
+// @Tags Metrics
+// @Produce text/event-stream
+// @Param id query []string false "Metric IDs to stream, all metrics when omitted" collectionFormat(multi)
+// @Param throttle query string false "Minimum interval between updates of a series, e.g. 1s"
+// @Param Last-Event-ID header string false "ID of the last received event"
+// @Success 200 {array} models.Metrics "Event stream of metric arrays"
+// @Failure 400 {object} api.ErrorEnvelope "Bad Request"
+// @Failure 500 {object} api.ErrorEnvelope "Internal Error"
+// @Router /api/v1/stream [get]
+func (handler *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseStreamFilter(r.URL.Query())
+	if err != nil {
+		api.RespondError(w, api.BadRequest(err.Error()))
+		return
+	}
+
+	var lastEventID uint64
+	if raw := r.Header.Get("Last-Event-ID"); raw != "" {
+		lastEventID, err = strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			api.RespondError(w, api.BadRequest(fmt.Sprintf("invalid Last-Event-ID: %s", raw)))
+			return
+		}
+	}
+
+	subscription, replay := handler.broker.Subscribe(middleware.TenantFromCtx(r.Context()), lastEventID)
+	defer handler.broker.Unsubscribe(subscription)
+
+	controller := http.NewResponseController(w)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds()); err != nil {
+		return
+	}
+
+	// Held back series are released with the ID of the latest received
+	// event, as they are at least as recent as that event.
+	throttler := stream.NewThrottler(filter.throttle)
+	received := lastEventID
+	receive := func(event stream.Event) error {
+		received = event.ID
+		return writeStreamEvent(w, event.ID, throttler.Add(time.Now(), filter.apply(event.Metrics)))
+	}
+
+	for _, event := range replay {
+		if err := receive(event); err != nil {
+			return
+		}
+	}
+
+	if err := controller.Flush(); err != nil {
+		slog.Error("Flush event stream error", "error", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	var release <-chan time.Time
+	if filter.throttle > 0 {
+		ticker := time.NewTicker(filter.throttle)
+		defer ticker.Stop()
+		release = ticker.C
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return
+			}
+			err = receive(event)
+		case now := <-release:
+			err = writeStreamEvent(w, received, throttler.Flush(now))
+		case <-heartbeat.C:
+			_, err = io.WriteString(w, ": heartbeat\n\n")
+		}
+
+		if err == nil {
+			err = controller.Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// parseStreamFilter builds a stream filter from URL query parameters.
+func parseStreamFilter(values url.Values) (streamFilter, error) {
+	filter := streamFilter{ids: values["id"]}
+
+	if raw := values.Get("throttle"); raw != "" {
+		throttle, err := time.ParseDuration(raw)
+		if err != nil || throttle < 0 {
+			return streamFilter{}, fmt.Errorf("invalid throttle: %s", raw)
+		}
+		filter.throttle = throttle
+	}
+
+	return filter, nil
+}
+
+// apply returns metrics selected by the filter.
+func (filter streamFilter) apply(metrics []models.Metrics) []models.Metrics {
+	if len(filter.ids) == 0 {
+		return metrics
+	}
+
+	selected := make([]models.Metrics, 0, len(metrics))
+	for _, metric := range metrics {
+		if slices.Contains(filter.ids, metric.ID) {
+			selected = append(selected, metric)
+		}
+	}
+
+	return selected
+}
+
+// writeStreamEvent writes metrics as a single server-sent event.
+// Nothing is written for no metrics.
+func writeStreamEvent(w io.Writer, id uint64, metrics []models.Metrics) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(metrics)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", id, streamEvent, data)
+	return err
+}
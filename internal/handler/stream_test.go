@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/stream"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// publication is a single save published to the broker.
+type publication struct {
+	tenant  string
+	metrics []models.Metrics
+}
+
+// readStreamEvent reads the next metrics event as "id data",
+// skipping the retry advice and comments.
+func readStreamEvent(t *testing.T, reader *bufio.Reader) string {
+	t.Helper()
+
+	var id, data string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "" && id != "":
+			return id + " " + data
+		}
+	}
+}
+
+func TestNewStreamHandler(t *testing.T) {
+	broker, err := stream.NewBroker(stream.DefaultHistorySize)
+	require.NoError(t, err)
+
+	h, err := NewStreamHandler(broker)
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+
+	h, err = NewStreamHandler(nil)
+	assert.Error(t, err)
+	assert.Nil(t, h)
+}
+
+func TestStreamHandler_Stream(t *testing.T) {
+	cpu := func(value float64) models.Metrics {
+		return models.Metrics{ID: "cpu", MType: models.Gauge, Value: &value}
+	}
+	mem := func(value float64) models.Metrics {
+		return models.Metrics{ID: "mem", MType: models.Gauge, Value: &value}
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		lastEventID  string
+		before       []publication
+		after        []publication
+		expectEvents []string
+	}{
+		{
+			name:         "batch as single event",
+			after:        []publication{{tenant: "team-a", metrics: []models.Metrics{cpu(1), mem(2)}}},
+			expectEvents: []string{`1 [{"id":"cpu","type":"gauge","value":1},{"id":"mem","type":"gauge","value":2}]`},
+		},
+		{
+			name:  "id filter",
+			query: "?id=mem&id=disk",
+			after: []publication{
+				{tenant: "team-a", metrics: []models.Metrics{cpu(1)}},
+				{tenant: "team-a", metrics: []models.Metrics{cpu(2), mem(3)}},
+			},
+			expectEvents: []string{`2 [{"id":"mem","type":"gauge","value":3}]`},
+		},
+		{
+			name: "other tenant",
+			after: []publication{
+				{tenant: "team-b", metrics: []models.Metrics{cpu(1)}},
+				{tenant: "team-a", metrics: []models.Metrics{cpu(2)}},
+			},
+			expectEvents: []string{`2 [{"id":"cpu","type":"gauge","value":2}]`},
+		},
+		{
+			name:        "replay after last event",
+			lastEventID: "1",
+			before: []publication{
+				{tenant: "team-a", metrics: []models.Metrics{cpu(1)}},
+				{tenant: "team-a", metrics: []models.Metrics{cpu(2)}},
+			},
+			after: []publication{{tenant: "team-a", metrics: []models.Metrics{cpu(3)}}},
+			expectEvents: []string{
+				`2 [{"id":"cpu","type":"gauge","value":2}]`,
+				`3 [{"id":"cpu","type":"gauge","value":3}]`,
+			},
+		},
+		{
+			name:  "throttled series",
+			query: "?throttle=50ms",
+			after: []publication{
+				{tenant: "team-a", metrics: []models.Metrics{cpu(1)}},
+				{tenant: "team-a", metrics: []models.Metrics{cpu(2)}},
+				{tenant: "team-a", metrics: []models.Metrics{cpu(3), mem(4)}},
+			},
+			expectEvents: []string{
+				`1 [{"id":"cpu","type":"gauge","value":1}]`,
+				`3 [{"id":"mem","type":"gauge","value":4}]`,
+				`3 [{"id":"cpu","type":"gauge","value":3}]`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker, err := stream.NewBroker(stream.DefaultHistorySize)
+			require.NoError(t, err)
+
+			h, err := NewStreamHandler(broker)
+			require.NoError(t, err)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				h.Stream(w, r.WithContext(middleware.WithTenant(r.Context(), "team-a")))
+			}))
+			defer server.Close()
+
+			for _, p := range tt.before {
+				broker.Publish(p.tenant, p.metrics)
+			}
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+tt.query, nil)
+			require.NoError(t, err)
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+
+			resp, err := server.Client().Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+
+			for _, p := range tt.after {
+				broker.Publish(p.tenant, p.metrics)
+			}
+
+			reader := bufio.NewReader(resp.Body)
+			for _, expected := range tt.expectEvents {
+				assert.Equal(t, expected, readStreamEvent(t, reader))
+			}
+		})
+	}
+}
+
+func TestStreamHandler_Stream_invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		lastEventID string
+		expectBody  string
+	}{
+		{name: "invalid throttle", query: "?throttle=fast", expectBody: "invalid throttle: fast"},
+		{name: "negative throttle", query: "?throttle=-1s", expectBody: "invalid throttle: -1s"},
+		{name: "invalid last event id", lastEventID: "abc", expectBody: "invalid Last-Event-ID: abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker, err := stream.NewBroker(stream.DefaultHistorySize)
+			require.NoError(t, err)
+
+			h, err := NewStreamHandler(broker)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/stream"+tt.query, nil)
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+			rr := httptest.NewRecorder()
+
+			h.Stream(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectBody)
+			assert.False(t, broker.Active())
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/service"
+	"github.com/gabkaclassic/metrics/internal/transfer"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+)
+
+// exportContentTypes maps export formats to response content types.
+var exportContentTypes = map[string]middleware.ContentType{
+	transfer.CSV:    middleware.CSV,
+	transfer.NDJSON: middleware.NDJSON,
+	transfer.JSON:   middleware.JSON,
+}
+
+// Export streams all series of the tenant in a bulk format.
+// Series are read and encoded page by page, errors after the first page
+// are only logged since the response has already started.
+//
+// @Summary Export metrics
+// @Description Streams all series of the tenant ordered by ID and labels.
+// @Description Counters carry their total as `delta`, so exports can be imported into an empty server as is.
+// @Description CSV exports have a header row with the columns
+// @Description id,type,labels,delta,value,increment,bounds,buckets,sum,count,sketch,members,unit,timestamp,
+// @Description labels, bounds, buckets and members cells are JSON-encoded, sketches are base64-encoded.
+// @Tags Transfer
+// @Produce json
+// @Produce plain
+// @Param format query string false "Export format, json by default" Enums(csv,ndjson,json)
+// @Success 200 {array} models.Metrics "Exported metrics"
+// @Failure 400 {object} api.ErrorEnvelope "Bad Request"
+// @Failure 500 {object} api.ErrorEnvelope "Internal Error"
+// @Router /api/v1/export [get]
+func (handler *MetricsHandler) Export(w http.ResponseWriter, r *http.Request) {
+	format := cmp.Or(r.URL.Query().Get("format"), transfer.JSON)
+
+	encoder, err := transfer.NewEncoder(w, format)
+	if err != nil {
+		api.RespondError(w, api.BadRequest(err.Error()))
+		return
+	}
+
+	units, apiErr := handler.service.GetUnits(r.Context())
+	if apiErr != nil {
+		api.RespondError(w, apiErr)
+		return
+	}
+
+	metrics, apiErr := handler.service.ExportPage(r.Context(), nil)
+	if apiErr != nil {
+		api.RespondError(w, apiErr)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "metrics."+format))
+
+	for {
+		for _, metric := range metrics {
+			metric.Tenant = ""
+			metric.Unit = units[metric.ID]
+
+			if err := encoder.Encode(metric); err != nil {
+				slog.Error("Export metrics error", "error", err)
+				return
+			}
+		}
+
+		if len(metrics) < service.ExportPageSize {
+			break
+		}
+
+		after := metrics[len(metrics)-1].Ref()
+		if metrics, apiErr = handler.service.ExportPage(r.Context(), &after); apiErr != nil {
+			slog.Error("Export metrics error", "error", apiErr)
+			return
+		}
+	}
+
+	if err := encoder.Close(); err != nil {
+		slog.Error("Export metrics error", "error", err)
+	}
+}
+
+// Import stores metrics from a bulk document.
+//
+// @Summary Import metrics
+// @Description Imports metrics in the export formats, records are stored like batch updates.
+// @Description Invalid records are reported with their line (the array position for json)
+// @Description and don't prevent valid records from being imported.
+// @Description Imported counter deltas are added to stored counters,
+// @Description with counters=replace counters are set to the last imported value of their series instead.
+// @Description With dry_run records are only validated.
+// @Tags Transfer
+// @Accept json
+// @Accept plain
+// @Produce json
+// @Param format query string false "Import format, json by default" Enums(csv,ndjson,json)
+// @Param dry_run query bool false "Validate records without storing them"
+// @Param counters query string false "Counter mode, add by default" Enums(add,replace)
+// @Param metrics body string true "Metrics document"
+// @Param Idempotency-Key header string false "Key of the request, repeated requests with the key get the original response"
+// @Success 200 {object} api.Envelope{data=models.ImportReport} "Import report"
+// @Failure 400 {object} api.ErrorEnvelope "Bad Request"
+// @Failure 409 {object} api.ErrorEnvelope "Request with the idempotency key is in progress"
+// @Failure 422 {object} api.ErrorEnvelope "Idempotency key used for a different request"
+// @Failure 500 {object} api.ErrorEnvelope "Internal Error"
+// @Router /api/v1/import [post]
+func (handler *MetricsHandler) Import(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	dryRun, err := parseBool(query, "dry_run")
+	if err != nil {
+		api.RespondError(w, api.BadRequest(err.Error()))
+		return
+	}
+	options := models.ImportOptions{DryRun: dryRun, Counters: query.Get("counters")}
+
+	records, failures, err := transfer.Decode(r.Body, cmp.Or(query.Get("format"), transfer.JSON))
+	if err != nil {
+		api.RespondError(w, api.BadRequest(err.Error()))
+		return
+	}
+
+	report, importErr := handler.service.Import(r.Context(), records, options)
+	if importErr != nil {
+		api.RespondError(w, importErr)
+		return
+	}
+
+	report.Total += len(failures)
+	report.Failed += len(failures)
+	report.Errors = append(report.Errors, failures...)
+	slices.SortStableFunc(report.Errors, func(a, b models.ImportError) int {
+		return cmp.Compare(a.Line, b.Line)
+	})
+
+	api.Respond(w, http.StatusOK, report)
+}
+
+// exportContentType sets the response content type from the export format,
+// unknown formats get JSON errors.
+func exportContentType(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType, exists := exportContentTypes[cmp.Or(r.URL.Query().Get("format"), transfer.JSON)]
+		if !exists {
+			contentType = middleware.JSON
+		}
+
+		w.Header().Set("Content-Type", string(contentType))
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/service"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsHandler_Export(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		mockErr        *api.APIError
+		expectCalls    bool
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "default json",
+			url:            "/api/v1/export",
+			expectCalls:    true,
+			expectedStatus: http.StatusOK,
+			expectedBody: `[{"id":"HeapAlloc","type":"gauge","value":1024,"unit":"bytes"},` +
+				`{"id":"HeapAlloc","type":"gauge","labels":{"host":"a"},"value":2048,"unit":"bytes"},` +
+				`{"id":"PollCount","type":"gauge","value":7},` +
+				`{"id":"Requests","type":"counter","delta":42}]` + "\n",
+		},
+		{
+			name:           "ndjson",
+			url:            "/api/v1/export?format=ndjson",
+			expectCalls:    true,
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"id":"HeapAlloc","type":"gauge","value":1024,"unit":"bytes"}` + "\n" +
+				`{"id":"HeapAlloc","type":"gauge","labels":{"host":"a"},"value":2048,"unit":"bytes"}` + "\n" +
+				`{"id":"PollCount","type":"gauge","value":7}` + "\n" +
+				`{"id":"Requests","type":"counter","delta":42}` + "\n",
+		},
+		{
+			name:           "csv",
+			url:            "/api/v1/export?format=csv",
+			expectCalls:    true,
+			expectedStatus: http.StatusOK,
+			expectedBody: "id,type,labels,delta,value,increment,bounds,buckets,sum,count,sketch,members,unit,timestamp\n" +
+				"HeapAlloc,gauge,,,1024,,,,,,,,bytes,\n" +
+				"HeapAlloc,gauge,\"{\"\"host\"\":\"\"a\"\"}\",,2048,,,,,,,,bytes,\n" +
+				"PollCount,gauge,,,7,,,,,,,,,\n" +
+				"Requests,counter,,42,,,,,,,,,,\n",
+		},
+		{
+			name:           "unsupported format",
+			url:            "/api/v1/export?format=xml",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "service error",
+			url:            "/api/v1/export",
+			mockErr:        api.Internal("some error", errors.New("some error")),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	exported := []models.Metrics{
+		{ID: "HeapAlloc", MType: models.Gauge, Value: floatPtr(1024)},
+		{ID: "HeapAlloc", MType: models.Gauge, Value: floatPtr(2048), Labels: map[string]string{"host": "a"}},
+		{ID: "PollCount", MType: models.Gauge, Value: floatPtr(7)},
+		{ID: "Requests", MType: models.Counter, Delta: intPtr(42)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetricsService(t)
+			if tt.expectCalls || tt.mockErr != nil {
+				mockService.EXPECT().GetUnits(mock.Anything).Return(map[string]string{"HeapAlloc": "bytes"}, nil)
+				mockService.EXPECT().ExportPage(mock.Anything, (*models.SeriesRef)(nil)).Return(exported, tt.mockErr)
+			}
+
+			handler, err := NewMetricsHandler(mockService)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rec := httptest.NewRecorder()
+
+			handler.Export(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, rec.Body.String())
+				assert.Contains(t, rec.Header().Get("Content-Disposition"), "attachment")
+			}
+		})
+	}
+}
+
+func TestMetricsHandler_Export_pages(t *testing.T) {
+	first := make([]models.Metrics, service.ExportPageSize)
+	for i := range first {
+		first[i] = models.Metrics{ID: fmt.Sprintf("m%04d", i), MType: models.Counter, Delta: intPtr(int64(i))}
+	}
+	last := first[len(first)-1].Ref()
+
+	mockService := service.NewMockMetricsService(t)
+	mockService.EXPECT().GetUnits(mock.Anything).Return(map[string]string{}, nil)
+	mockService.EXPECT().ExportPage(mock.Anything, (*models.SeriesRef)(nil)).Return(first, nil)
+	mockService.EXPECT().ExportPage(mock.Anything, &last).
+		Return([]models.Metrics{{ID: "z", MType: models.Gauge, Value: floatPtr(1)}}, nil)
+
+	handler, err := NewMetricsHandler(mockService)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/export?format=ndjson", nil)
+	rec := httptest.NewRecorder()
+
+	handler.Export(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	assert.Len(t, lines, service.ExportPageSize+1)
+	assert.Equal(t, `{"id":"m0000","type":"counter","delta":0}`, lines[0])
+	assert.Equal(t, `{"id":"z","type":"gauge","value":1}`, lines[len(lines)-1])
+}
+
+func TestMetricsHandler_Import(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		body           string
+		mockFn         func(svc *service.MockMetricsService)
+		expectedStatus int
+		expectedReport models.ImportReport
+	}{
+		{
+			name: "csv with parse errors",
+			url:  "/api/v1/import?format=csv&counters=replace",
+			body: "id,type,delta\nrequests,counter,5\nrequests,counter,x\nmissing,counter,\n",
+			mockFn: func(svc *service.MockMetricsService) {
+				svc.EXPECT().Import(mock.Anything, []models.ImportRecord{
+					{Line: 2, Metric: models.Metrics{ID: "requests", MType: models.Counter, Delta: intPtr(5)}},
+					{Line: 4, Metric: models.Metrics{ID: "missing", MType: models.Counter}},
+				}, models.ImportOptions{Counters: models.CounterReplace}).Return(models.ImportReport{
+					Total: 2, Imported: 1, Failed: 1,
+					Errors: []models.ImportError{{Line: 4, Error: "counter delta is required"}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedReport: models.ImportReport{
+				Total: 3, Imported: 1, Failed: 2,
+				Errors: []models.ImportError{
+					{Line: 3, Error: `invalid delta: strconv.ParseInt: parsing "x": invalid syntax`},
+					{Line: 4, Error: "counter delta is required"},
+				},
+			},
+		},
+		{
+			name: "json dry run",
+			url:  "/api/v1/import?dry_run=true",
+			body: `[{"id":"temp","type":"gauge","value":1.5}]`,
+			mockFn: func(svc *service.MockMetricsService) {
+				svc.EXPECT().Import(mock.Anything, []models.ImportRecord{
+					{Line: 1, Metric: models.Metrics{ID: "temp", MType: models.Gauge, Value: floatPtr(1.5)}},
+				}, models.ImportOptions{DryRun: true}).Return(models.ImportReport{Total: 1, Imported: 1, DryRun: true}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedReport: models.ImportReport{Total: 1, Imported: 1, DryRun: true},
+		},
+		{
+			name:           "invalid dry_run",
+			url:            "/api/v1/import?dry_run=maybe",
+			mockFn:         func(svc *service.MockMetricsService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed document",
+			url:            "/api/v1/import?format=json",
+			body:           `{"id":"temp"}`,
+			mockFn:         func(svc *service.MockMetricsService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unsupported format",
+			url:            "/api/v1/import?format=xml",
+			mockFn:         func(svc *service.MockMetricsService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "service error",
+			url:  "/api/v1/import?counters=set",
+			body: `[]`,
+			mockFn: func(svc *service.MockMetricsService) {
+				svc.EXPECT().Import(mock.Anything, mock.Anything, mock.Anything).
+					Return(models.ImportReport{}, api.BadRequest(`invalid counters mode "set", expected add or replace`))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := service.NewMockMetricsService(t)
+			tt.mockFn(mockService)
+
+			handler, err := NewMetricsHandler(mockService)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			handler.Import(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var envelope struct {
+				Data models.ImportReport `json:"data"`
+			}
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&envelope))
+			assert.Equal(t, tt.expectedReport, envelope.Data)
+		})
+	}
+}
+
+func TestExportContentType(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{url: "/api/v1/export", expected: "application/json"},
+		{url: "/api/v1/export?format=csv", expected: "text/csv; charset=utf-8"},
+		{url: "/api/v1/export?format=ndjson", expected: "application/x-ndjson"},
+		{url: "/api/v1/export?format=xml", expected: "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			exportContentType(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).
+				ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			assert.Equal(t, tt.expected, rec.Header().Get("Content-Type"))
+		})
+	}
+}
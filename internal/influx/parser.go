@@ -0,0 +1,262 @@
+// Package influx parses metrics written in the InfluxDB line protocol.
+//
+// Every field of a line becomes a metric named measurement_field,
+// tags become series labels with keys sanitized into label names
+// (host-name becomes host_name). Float fields are gauges, integer fields
+// (i and u suffixes) are counter deltas. String and boolean fields
+// have no metric representation and are skipped.
+package influx
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+)
+
+// Timestamp precisions of the InfluxDB write API.
+const (
+	Nanoseconds  = "ns"
+	Microseconds = "us"
+	Milliseconds = "ms"
+	Seconds      = "s"
+)
+
+// maxLineSize limits the length of a single line.
+const maxLineSize = 1 << 20
+
+// millisecondDivisors convert timestamps of a precision to milliseconds,
+// seconds are multiplied instead.
+var millisecondDivisors = map[string]int64{
+	Nanoseconds:  1_000_000,
+	Microseconds: 1_000,
+	Milliseconds: 1,
+}
+
+// unescaper removes line protocol escapes of measurements, tags and field keys.
+var unescaper = strings.NewReplacer(`\,`, ",", `\=`, "=", `\ `, " ", `\\`, `\`)
+
+// Parse parses a line protocol document.
+//
+// r: Document of newline-separated lines
+// precision: Timestamp precision, one of ns, us, ms and s
+//
+// Returns:
+//   - []models.Metrics: Metrics of all lines
+//   - error: Precision error, or the first malformed line with its number
+//
+// Empty lines and comments starting with # are skipped.
+func Parse(r io.Reader, precision string) ([]models.Metrics, error) {
+	if !validPrecision(precision) {
+		return nil, fmt.Errorf("invalid precision %q", precision)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+
+	metrics := make([]models.Metrics, 0)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parsed, err := ParseLine(line, precision)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+		metrics = append(metrics, parsed...)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read line protocol: %w", err)
+	}
+
+	return metrics, nil
+}
+
+// ParseLine parses a single line of the form
+// measurement[,tag=value...] field=value[,field=value...] [timestamp].
+//
+// line: Line without the trailing newline
+// precision: Timestamp precision, one of ns, us, ms and s
+//
+// Returns:
+//   - []models.Metrics: One metric per numeric field
+//   - error: Line format, tag, field or timestamp errors
+func ParseLine(line string, precision string) ([]models.Metrics, error) {
+	seriesKey, rest, found := cut(line, ' ', false)
+	if !found {
+		return nil, fmt.Errorf("invalid influx line %q: missing fields", line)
+	}
+
+	measurement, labels, err := parseSeriesKey(seriesKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid influx line %q: %w", line, err)
+	}
+
+	rawFields, rawTimestamp, _ := cut(rest, ' ', true)
+	if rawFields == "" {
+		return nil, fmt.Errorf("invalid influx line %q: missing fields", line)
+	}
+
+	var timestamp *int64
+	if rawTimestamp = strings.TrimSpace(rawTimestamp); rawTimestamp != "" {
+		timestamp, err = parseTimestamp(rawTimestamp, precision)
+		if err != nil {
+			return nil, fmt.Errorf("invalid influx line %q: %w", line, err)
+		}
+	}
+
+	metrics := make([]models.Metrics, 0)
+	for _, field := range split(rawFields, ',', true) {
+		metric, ok, err := parseField(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid influx line %q: %w", line, err)
+		}
+		if !ok {
+			continue
+		}
+
+		metric.ID = measurement + "_" + metric.ID
+		metric.Labels = labels
+		metric.Timestamp = timestamp
+		metrics = append(metrics, metric)
+	}
+
+	return metrics, nil
+}
+
+// parseSeriesKey splits a series key into the measurement and its tags,
+// tag keys are sanitized into label names.
+func parseSeriesKey(seriesKey string) (string, map[string]string, error) {
+	parts := split(seriesKey, ',', false)
+
+	measurement := unescaper.Replace(parts[0])
+	if measurement == "" {
+		return "", nil, errors.New("missing measurement")
+	}
+
+	if len(parts) == 1 {
+		return measurement, nil, nil
+	}
+
+	labels := make(map[string]string, len(parts)-1)
+	for _, tag := range parts[1:] {
+		name, value, found := cut(tag, '=', false)
+		if !found || name == "" || value == "" {
+			return "", nil, fmt.Errorf("invalid tag %q", tag)
+		}
+		labels[models.SanitizeLabelName(unescaper.Replace(name))] = unescaper.Replace(value)
+	}
+
+	return measurement, labels, nil
+}
+
+// parseField converts a field to a metric named after the field key.
+// Reports false for string and boolean fields.
+func parseField(field string) (models.Metrics, bool, error) {
+	rawKey, value, found := cut(field, '=', false)
+	key := unescaper.Replace(rawKey)
+	if !found || key == "" || value == "" {
+		return models.Metrics{}, false, fmt.Errorf("invalid field %q", field)
+	}
+
+	switch {
+	case strings.HasPrefix(value, `"`):
+		if len(value) < 2 || !strings.HasSuffix(value, `"`) {
+			return models.Metrics{}, false, fmt.Errorf("invalid string field %q", key)
+		}
+		return models.Metrics{}, false, nil
+	case isBool(value):
+		return models.Metrics{}, false, nil
+	case strings.HasSuffix(value, "i"):
+		delta, err := strconv.ParseInt(value[:len(value)-1], 10, 64)
+		if err != nil {
+			return models.Metrics{}, false, fmt.Errorf("invalid integer field %q", key)
+		}
+		return models.Metrics{ID: key, MType: models.Counter, Delta: &delta}, true, nil
+	case strings.HasSuffix(value, "u"):
+		unsigned, err := strconv.ParseUint(value[:len(value)-1], 10, 63)
+		if err != nil {
+			return models.Metrics{}, false, fmt.Errorf("invalid unsigned field %q", key)
+		}
+		delta := int64(unsigned)
+		return models.Metrics{ID: key, MType: models.Counter, Delta: &delta}, true, nil
+	default:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+			return models.Metrics{}, false, fmt.Errorf("invalid float field %q", key)
+		}
+		return models.Metrics{ID: key, MType: models.Gauge, Value: &parsed}, true, nil
+	}
+}
+
+// parseTimestamp converts a timestamp of the precision to Unix milliseconds.
+func parseTimestamp(raw string, precision string) (*int64, error) {
+	timestamp, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid timestamp")
+	}
+
+	if precision == Seconds {
+		timestamp *= 1000
+	} else {
+		timestamp /= millisecondDivisors[precision]
+	}
+
+	return &timestamp, nil
+}
+
+// validPrecision reports whether precision is a supported timestamp precision.
+func validPrecision(precision string) bool {
+	_, exists := millisecondDivisors[precision]
+	return exists || precision == Seconds
+}
+
+// isBool reports whether value is a line protocol boolean literal.
+func isBool(value string) bool {
+	switch value {
+	case "t", "T", "true", "True", "TRUE", "f", "F", "false", "False", "FALSE":
+		return true
+	}
+	return false
+}
+
+// cut slices s around the first unescaped separator,
+// separators inside double quotes are ignored if quotes is set.
+func cut(s string, sep byte, quotes bool) (string, string, bool) {
+	quoted := false
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case quotes && s[i] == '"':
+			quoted = !quoted
+		case s[i] == sep && !quoted:
+			return s[:i], s[i+1:], true
+		}
+	}
+
+	return s, "", false
+}
+
+// split slices s into all parts separated by unescaped separators.
+func split(s string, sep byte, quotes bool) []string {
+	parts := make([]string, 0, 1)
+
+	for {
+		part, rest, found := cut(s, sep, quotes)
+		parts = append(parts, part)
+		if !found {
+			return parts
+		}
+		s = rest
+	}
+}
@@ -0,0 +1,223 @@
+package influx
+
+import (
+	"strings"
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func float64Ptr(v float64) *float64 { return &v }
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		precision string
+		expected  []models.Metrics
+		wantErr   string
+	}{
+		{
+			name: "telegraf batch",
+			body: "# system metrics\n" +
+				"cpu,cpu=cpu-total,host=web1 usage_idle=97.5,usage_user=1.25 1700000000000000000\n" +
+				"\n" +
+				"mem,host=web1 used=1024i,used_percent=12.5 1700000000000000000\n",
+			precision: Nanoseconds,
+			expected: []models.Metrics{
+				{
+					ID: "cpu_usage_idle", MType: models.Gauge,
+					Labels: map[string]string{"cpu": "cpu-total", "host": "web1"},
+					Value:  float64Ptr(97.5), Timestamp: int64Ptr(1700000000000),
+				},
+				{
+					ID: "cpu_usage_user", MType: models.Gauge,
+					Labels: map[string]string{"cpu": "cpu-total", "host": "web1"},
+					Value:  float64Ptr(1.25), Timestamp: int64Ptr(1700000000000),
+				},
+				{
+					ID: "mem_used", MType: models.Counter,
+					Labels: map[string]string{"host": "web1"},
+					Delta:  int64Ptr(1024), Timestamp: int64Ptr(1700000000000),
+				},
+				{
+					ID: "mem_used_percent", MType: models.Gauge,
+					Labels: map[string]string{"host": "web1"},
+					Value:  float64Ptr(12.5), Timestamp: int64Ptr(1700000000000),
+				},
+			},
+		},
+		{
+			name:      "empty body",
+			body:      "",
+			precision: Nanoseconds,
+			expected:  []models.Metrics{},
+		},
+		{
+			name:      "invalid precision",
+			body:      "cpu usage=1",
+			precision: "h",
+			wantErr:   `invalid precision "h"`,
+		},
+		{
+			name:      "malformed line",
+			body:      "cpu usage=1\ncpu usage=high\n",
+			precision: Nanoseconds,
+			wantErr:   `line 2: invalid influx line "cpu usage=high": invalid float field "usage"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, err := Parse(strings.NewReader(tt.body), tt.precision)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, metrics)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, metrics)
+		})
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		precision string
+		expected  []models.Metrics
+		wantErr   string
+	}{
+		{
+			name:      "float field",
+			line:      "load value=0.75",
+			precision: Nanoseconds,
+			expected:  []models.Metrics{{ID: "load_value", MType: models.Gauge, Value: float64Ptr(0.75)}},
+		},
+		{
+			name:      "integer field",
+			line:      "http requests=-3i",
+			precision: Nanoseconds,
+			expected:  []models.Metrics{{ID: "http_requests", MType: models.Counter, Delta: int64Ptr(-3)}},
+		},
+		{
+			name:      "unsigned field",
+			line:      "http requests=3u",
+			precision: Nanoseconds,
+			expected:  []models.Metrics{{ID: "http_requests", MType: models.Counter, Delta: int64Ptr(3)}},
+		},
+		{
+			name:      "string and boolean fields are skipped",
+			line:      `system uptime=42i,uptime_format="0 days, 0:42",healthy=true`,
+			precision: Nanoseconds,
+			expected:  []models.Metrics{{ID: "system_uptime", MType: models.Counter, Delta: int64Ptr(42)}},
+		},
+		{
+			name:      "only string fields",
+			line:      `system status="ok"`,
+			precision: Nanoseconds,
+			expected:  []models.Metrics{},
+		},
+		{
+			name:      "escaped names",
+			line:      `disk\ io,mount=/data\,old read\=ops=1`,
+			precision: Nanoseconds,
+			expected: []models.Metrics{{
+				ID: "disk io_read=ops", MType: models.Gauge,
+				Labels: map[string]string{"mount": "/data,old"},
+				Value:  float64Ptr(1),
+			}},
+		},
+		{
+			name:      "seconds",
+			line:      "load value=1 1700000000",
+			precision: Seconds,
+			expected:  []models.Metrics{{ID: "load_value", MType: models.Gauge, Value: float64Ptr(1), Timestamp: int64Ptr(1700000000000)}},
+		},
+		{
+			name:      "milliseconds",
+			line:      "load value=1 1700000000123",
+			precision: Milliseconds,
+			expected:  []models.Metrics{{ID: "load_value", MType: models.Gauge, Value: float64Ptr(1), Timestamp: int64Ptr(1700000000123)}},
+		},
+		{
+			name:      "microseconds",
+			line:      "load value=1 1700000000123456",
+			precision: Microseconds,
+			expected:  []models.Metrics{{ID: "load_value", MType: models.Gauge, Value: float64Ptr(1), Timestamp: int64Ptr(1700000000123)}},
+		},
+		{
+			name:      "missing fields",
+			line:      "load",
+			precision: Nanoseconds,
+			wantErr:   `invalid influx line "load": missing fields`,
+		},
+		{
+			name:      "missing measurement",
+			line:      ",host=web1 value=1",
+			precision: Nanoseconds,
+			wantErr:   `invalid influx line ",host=web1 value=1": missing measurement`,
+		},
+		{
+			name:      "invalid tag",
+			line:      "load,host value=1",
+			precision: Nanoseconds,
+			wantErr:   `invalid influx line "load,host value=1": invalid tag "host"`,
+		},
+		{
+			name:      "sanitized tag keys",
+			line:      "load,host-name=web1,dc.zone=eu-1 value=1",
+			precision: Nanoseconds,
+			expected: []models.Metrics{{
+				ID: "load_value", MType: models.Gauge,
+				Labels: map[string]string{"host_name": "web1", "dc_zone": "eu-1"},
+				Value:  float64Ptr(1),
+			}},
+		},
+		{
+			name:      "invalid field",
+			line:      "load value",
+			precision: Nanoseconds,
+			wantErr:   `invalid influx line "load value": invalid field "value"`,
+		},
+		{
+			name:      "invalid integer",
+			line:      "load value=1.5i",
+			precision: Nanoseconds,
+			wantErr:   `invalid influx line "load value=1.5i": invalid integer field "value"`,
+		},
+		{
+			name:      "unterminated string",
+			line:      `load status="ok`,
+			precision: Nanoseconds,
+			wantErr:   `invalid influx line "load status=\"ok": invalid string field "status"`,
+		},
+		{
+			name:      "invalid timestamp",
+			line:      "load value=1 yesterday",
+			precision: Nanoseconds,
+			wantErr:   `invalid influx line "load value=1 yesterday": invalid timestamp`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, err := ParseLine(tt.line, tt.precision)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, metrics)
+		})
+	}
+}
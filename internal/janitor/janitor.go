@@ -0,0 +1,187 @@
+// Package janitor purges metric series that are no longer updated
+// and history points older than the history retention.
+//
+// A series expires when it was not updated within the TTL of its metric:
+// the TTL from metadata of the metric in the series tenant if set,
+// the server-wide default otherwise.
+// Expired series are removed together with their history in every tenant,
+// and each purge is recorded by the auditor per tenant.
+package janitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gabkaclassic/metrics/internal/audit"
+	"github.com/gabkaclassic/metrics/internal/config"
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/repository"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+)
+
+// Janitor periodically removes expired metric series and history points.
+type Janitor struct {
+	// repository stores the series to expire.
+	repository repository.MetricsRepository
+
+	// metaRepository provides per-metric TTLs.
+	metaRepository repository.MetaRepository
+
+	// auditor records expired series.
+	auditor audit.Auditor
+
+	// now returns the current time, replaced in tests.
+	now func() time.Time
+}
+
+// NewJanitor creates a new janitor with required dependencies.
+//
+// repository: Metrics repository to expire series in
+// metaRepository: Metadata repository providing per-metric TTLs
+// auditor: Audit logging system recording expirations
+//
+// Returns:
+//   - *Janitor: Initialized janitor ready for operations
+//   - error: If repository, metaRepository or auditor is nil
+func NewJanitor(repository repository.MetricsRepository, metaRepository repository.MetaRepository, auditor audit.Auditor) (*Janitor, error) {
+	if repository == nil {
+		return nil, errors.New("create janitor error: repository can't be nil")
+	}
+
+	if metaRepository == nil {
+		return nil, errors.New("create janitor error: meta repository can't be nil")
+	}
+
+	if auditor == nil {
+		return nil, errors.New("create janitor error: auditor can't be nil")
+	}
+
+	return &Janitor{
+		repository:     repository,
+		metaRepository: metaRepository,
+		auditor:        auditor,
+		now:            time.Now,
+	}, nil
+}
+
+// Purge removes series of all tenants not updated within their TTL.
+//
+// defaultTTL: TTL of metrics without their own TTL, zero keeps them forever.
+//
+// Returns:
+//   - []models.Metrics: Expired series with Tenant set
+//   - error: Metadata or repository failure details
+func (j *Janitor) Purge(ctx context.Context, defaultTTL time.Duration) ([]models.Metrics, error) {
+	tenants, err := j.metaRepository.GetAllTenants(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get metric ttls: %w", err)
+	}
+
+	policy := models.ExpirePolicy{
+		Now:       j.now(),
+		Default:   defaultTTL,
+		PerMetric: make(map[string]map[string]time.Duration),
+	}
+	for tenant, metas := range tenants {
+		for id, meta := range metas {
+			if meta.TTL <= 0 {
+				continue
+			}
+			if policy.PerMetric[tenant] == nil {
+				policy.PerMetric[tenant] = make(map[string]time.Duration)
+			}
+			policy.PerMetric[tenant][id] = time.Duration(meta.TTL) * time.Second
+		}
+	}
+
+	if policy.Default == 0 && len(policy.PerMetric) == 0 {
+		return nil, nil
+	}
+
+	expired, err := j.repository.Expire(ctx, policy)
+	if err != nil {
+		return nil, fmt.Errorf("expire metrics: %w", err)
+	}
+
+	j.notify(expired, policy.Now.Unix())
+
+	return expired, nil
+}
+
+// PruneHistory removes history points of all tenants older than the retention.
+//
+// retention: How long history points are kept, zero keeps them forever.
+//
+// Returns:
+//   - int: Number of removed points
+//   - error: Repository failure details
+func (j *Janitor) PruneHistory(ctx context.Context, retention time.Duration) (int, error) {
+	if retention <= 0 {
+		return 0, nil
+	}
+
+	pruned, err := j.repository.PruneHistory(ctx, j.now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("prune history: %w", err)
+	}
+
+	return pruned, nil
+}
+
+// notify records expired series in the audit system, one event per tenant.
+func (j *Janitor) notify(expired []models.Metrics, timestamp int64) {
+	byTenant := make(map[string][]models.Metrics)
+	for _, metric := range expired {
+		tenant := metric.Tenant
+		if tenant == "" {
+			tenant = middleware.DefaultTenant
+		}
+		byTenant[tenant] = append(byTenant[tenant], metric)
+	}
+
+	for tenant, metrics := range byTenant {
+		j.auditor.AuditExpire(metrics, timestamp, tenant)
+	}
+}
+
+// StartJanitor initiates periodic purging of expired series
+// and history points based on configuration.
+// Runs until context cancellation.
+//
+// ctx: Context for graceful shutdown (cancellation stops the janitor)
+// cfg: TTL configuration containing the default TTL and check interval
+// historyRetention: How long history points are kept, zero keeps them forever
+//
+// The janitor:
+//   - Runs on every check interval tick
+//   - Logs errors but continues on purge failures
+//   - Stops gracefully on context cancellation
+func (j *Janitor) StartJanitor(ctx context.Context, cfg config.TTL, historyRetention time.Duration) {
+	ticker := time.NewTicker(cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			expired, err := j.Purge(ctx, cfg.Default)
+			if err != nil {
+				slog.Error("Purge expired metrics error", slog.String("error", err.Error()))
+			} else if len(expired) > 0 {
+				slog.Info("Expired metrics purged", slog.Int("count", len(expired)))
+			}
+
+			pruned, err := j.PruneHistory(ctx, historyRetention)
+			if err != nil {
+				slog.Error("Prune history error", slog.String("error", err.Error()))
+			} else if pruned > 0 {
+				slog.Info("History pruned", slog.Int("count", pruned))
+			}
+		case <-ctx.Done():
+			slog.Info("Janitor stopped")
+			return
+		}
+	}
+}
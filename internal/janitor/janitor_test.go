@@ -0,0 +1,261 @@
+package janitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gabkaclassic/metrics/internal/audit"
+	"github.com/gabkaclassic/metrics/internal/config"
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/repository"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJanitor(t *testing.T) {
+	tests := []struct {
+		name           string
+		repository     repository.MetricsRepository
+		metaRepository repository.MetaRepository
+		auditor        audit.Auditor
+		wantErr        string
+	}{
+		{
+			name:           "valid dependencies",
+			repository:     repository.NewMockMetricsRepository(t),
+			metaRepository: repository.NewMockMetaRepository(t),
+			auditor:        audit.NewMockAuditor(t),
+		},
+		{
+			name:           "nil repository",
+			metaRepository: repository.NewMockMetaRepository(t),
+			auditor:        audit.NewMockAuditor(t),
+			wantErr:        "create janitor error: repository can't be nil",
+		},
+		{
+			name:       "nil meta repository",
+			repository: repository.NewMockMetricsRepository(t),
+			auditor:    audit.NewMockAuditor(t),
+			wantErr:    "create janitor error: meta repository can't be nil",
+		},
+		{
+			name:           "nil auditor",
+			repository:     repository.NewMockMetricsRepository(t),
+			metaRepository: repository.NewMockMetaRepository(t),
+			wantErr:        "create janitor error: auditor can't be nil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j, err := NewJanitor(tt.repository, tt.metaRepository, tt.auditor)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, j)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, j)
+			}
+		})
+	}
+}
+
+func TestJanitor_Purge(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name       string
+		defaultTTL time.Duration
+		setupMock  func(*repository.MockMetricsRepository, *repository.MockMetaRepository, *audit.MockAuditor)
+		expected   []models.Metrics
+		expectErr  bool
+	}{
+		{
+			name:       "expired series audited per tenant",
+			defaultTTL: time.Minute,
+			setupMock: func(repo *repository.MockMetricsRepository, meta *repository.MockMetaRepository, auditor *audit.MockAuditor) {
+				meta.EXPECT().GetAllTenants(mock.Anything).Return(map[string]map[string]models.Meta{
+					middleware.DefaultTenant: {
+						"HeapAlloc": {ID: "HeapAlloc", TTL: 3600},
+						"PollCount": {ID: "PollCount", Unit: "count"},
+					},
+					"team-a": {
+						"HeapAlloc": {ID: "HeapAlloc", TTL: 60},
+					},
+					"team-b": {
+						"PollCount": {ID: "PollCount", Unit: "count"},
+					},
+				}, nil)
+				repo.EXPECT().
+					Expire(mock.Anything, models.ExpirePolicy{
+						Now:     now,
+						Default: time.Minute,
+						PerMetric: map[string]map[string]time.Duration{
+							middleware.DefaultTenant: {"HeapAlloc": time.Hour},
+							"team-a":                 {"HeapAlloc": time.Minute},
+						},
+					}).
+					Return([]models.Metrics{
+						{ID: "g1", MType: models.Gauge, Tenant: middleware.DefaultTenant},
+						{ID: "g2", MType: models.Gauge, Tenant: "team-a"},
+						{ID: "g3", MType: models.Gauge, Tenant: middleware.DefaultTenant},
+					}, nil)
+				auditor.EXPECT().AuditExpire([]models.Metrics{
+					{ID: "g1", MType: models.Gauge, Tenant: middleware.DefaultTenant},
+					{ID: "g3", MType: models.Gauge, Tenant: middleware.DefaultTenant},
+				}, now.Unix(), middleware.DefaultTenant)
+				auditor.EXPECT().AuditExpire([]models.Metrics{
+					{ID: "g2", MType: models.Gauge, Tenant: "team-a"},
+				}, now.Unix(), "team-a")
+			},
+			expected: []models.Metrics{
+				{ID: "g1", MType: models.Gauge, Tenant: middleware.DefaultTenant},
+				{ID: "g2", MType: models.Gauge, Tenant: "team-a"},
+				{ID: "g3", MType: models.Gauge, Tenant: middleware.DefaultTenant},
+			},
+		},
+		{
+			name: "no ttl configured",
+			setupMock: func(repo *repository.MockMetricsRepository, meta *repository.MockMetaRepository, auditor *audit.MockAuditor) {
+				meta.EXPECT().GetAllTenants(mock.Anything).Return(map[string]map[string]models.Meta{}, nil)
+			},
+		},
+		{
+			name:       "meta error",
+			defaultTTL: time.Minute,
+			setupMock: func(repo *repository.MockMetricsRepository, meta *repository.MockMetaRepository, auditor *audit.MockAuditor) {
+				meta.EXPECT().GetAllTenants(mock.Anything).Return(nil, errors.New("db error"))
+			},
+			expectErr: true,
+		},
+		{
+			name:       "expire error",
+			defaultTTL: time.Minute,
+			setupMock: func(repo *repository.MockMetricsRepository, meta *repository.MockMetaRepository, auditor *audit.MockAuditor) {
+				meta.EXPECT().GetAllTenants(mock.Anything).Return(map[string]map[string]models.Meta{}, nil)
+				repo.EXPECT().Expire(mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMockMetricsRepository(t)
+			meta := repository.NewMockMetaRepository(t)
+			auditor := audit.NewMockAuditor(t)
+			tt.setupMock(repo, meta, auditor)
+
+			j, err := NewJanitor(repo, meta, auditor)
+			require.NoError(t, err)
+			j.now = func() time.Time { return now }
+
+			expired, err := j.Purge(t.Context(), tt.defaultTTL)
+
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, expired)
+			}
+		})
+	}
+}
+
+func TestJanitor_PruneHistory(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		retention      time.Duration
+		setupMock      func(repo *repository.MockMetricsRepository)
+		expectedPruned int
+		expectErr      bool
+	}{
+		{
+			name:      "points older than retention",
+			retention: time.Hour,
+			setupMock: func(repo *repository.MockMetricsRepository) {
+				repo.EXPECT().PruneHistory(mock.Anything, now.Add(-time.Hour)).Return(3, nil)
+			},
+			expectedPruned: 3,
+		},
+		{
+			name:      "zero retention keeps history",
+			retention: 0,
+			setupMock: func(repo *repository.MockMetricsRepository) {},
+		},
+		{
+			name:      "repository error",
+			retention: time.Hour,
+			setupMock: func(repo *repository.MockMetricsRepository) {
+				repo.EXPECT().PruneHistory(mock.Anything, now.Add(-time.Hour)).Return(0, errors.New("db error"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMockMetricsRepository(t)
+			tt.setupMock(repo)
+
+			j, err := NewJanitor(repo, repository.NewMockMetaRepository(t), audit.NewMockAuditor(t))
+			require.NoError(t, err)
+			j.now = func() time.Time { return now }
+
+			pruned, err := j.PruneHistory(t.Context(), tt.retention)
+
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedPruned, pruned)
+			}
+		})
+	}
+}
+
+func TestJanitor_StartJanitor(t *testing.T) {
+	repo := repository.NewMockMetricsRepository(t)
+	meta := repository.NewMockMetaRepository(t)
+	auditor := audit.NewMockAuditor(t)
+
+	purged := make(chan struct{})
+	meta.EXPECT().GetAllTenants(mock.Anything).Return(map[string]map[string]models.Meta{}, nil)
+	repo.EXPECT().Expire(mock.Anything, mock.Anything).Return([]models.Metrics{}, nil)
+	repo.EXPECT().
+		PruneHistory(mock.Anything, mock.Anything).
+		RunAndReturn(func(context.Context, time.Time) (int, error) {
+			select {
+			case <-purged:
+			default:
+				close(purged)
+			}
+			return 0, nil
+		})
+
+	j, err := NewJanitor(repo, meta, auditor)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		j.StartJanitor(ctx, config.TTL{Default: time.Minute, CheckInterval: time.Millisecond}, time.Hour)
+		close(done)
+	}()
+
+	select {
+	case <-purged:
+	case <-time.After(time.Second):
+		t.Fatal("janitor did not purge")
+	}
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,282 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"path"
+	"time"
+)
+
+const (
+	// Comparison operators of alert rules.
+	OpLess         = "<"
+	OpLessEqual    = "<="
+	OpGreater      = ">"
+	OpGreaterEqual = ">="
+	OpEqual        = "=="
+	OpNotEqual     = "!="
+
+	// OpUnchanged holds when a series keeps its value between evaluations,
+	// e.g. a counter that stopped increasing. The threshold is ignored.
+	OpUnchanged = "unchanged"
+)
+
+// AlertState is a stage of the alert lifecycle.
+type AlertState string
+
+const (
+	// AlertPending alerts hold their condition for less than the rule duration.
+	AlertPending AlertState = "pending"
+
+	// AlertFiring alerts hold their condition for at least the rule duration.
+	AlertFiring AlertState = "firing"
+
+	// AlertResolved alerts were firing and no longer hold their condition.
+	AlertResolved AlertState = "resolved"
+)
+
+// AlertRule describes a condition on metric series that raises alerts.
+// Every matching series of the rule tenant is evaluated on its own.
+//
+// swagger:model AlertRule
+type AlertRule struct {
+	// Rule identifier, unique within the tenant.
+	// required: true
+	// example: low-memory
+	ID string `json:"id"`
+
+	// Tenant owning the rule, taken from the request for API calls.
+	// Rules loaded from a file default to the default tenant.
+	// example: team-a
+	Tenant string `json:"tenant,omitempty"`
+
+	// Metric ID or glob pattern of metric IDs (path.Match syntax).
+	// required: true
+	// example: FreeMemory
+	Metric string `json:"metric"`
+
+	// Metric type, all types when omitted.
+	// enum: gauge,counter,histogram,summary,set
+	MType string `json:"type,omitempty"`
+
+	// Labels a series must carry to match, all series when omitted.
+	// example: {"host":"web-1"}
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Comparison of the series value with the threshold.
+	// Values are gauge values, counter totals, observation counts
+	// of histograms and summaries and set cardinalities.
+	// required: true
+	// enum: <,<=,>,>=,==,!=,unchanged
+	// example: <
+	Op string `json:"op"`
+
+	// Threshold the series value is compared with.
+	// example: 104857600
+	Threshold float64 `json:"threshold"`
+
+	// Seconds the condition must hold before the alert fires,
+	// zero fires on the first evaluation.
+	// example: 300
+	For int64 `json:"for,omitempty"`
+
+	// Webhook URLs notified about the rule alerts,
+	// the server-wide webhooks when omitted.
+	// example: ["https://hooks.example.com/alerts"]
+	Webhooks []string `json:"webhooks,omitempty"`
+}
+
+// ValidateAlertRule checks that the rule is complete and well-formed.
+func ValidateAlertRule(rule AlertRule) error {
+	if rule.ID == "" {
+		return errors.New("rule id is required")
+	}
+
+	if rule.Metric == "" {
+		return errors.New("rule metric is required")
+	}
+
+	if err := ValidatePattern(rule.Metric); err != nil {
+		return fmt.Errorf("invalid rule metric pattern: %w", err)
+	}
+
+	if rule.MType != "" && !IsKnownType(rule.MType) {
+		return fmt.Errorf("invalid metric type: %s", rule.MType)
+	}
+
+	if err := ValidateLabels(rule.Labels); err != nil {
+		return err
+	}
+
+	switch rule.Op {
+	case OpLess, OpLessEqual, OpGreater, OpGreaterEqual, OpEqual, OpNotEqual, OpUnchanged:
+	default:
+		return fmt.Errorf("invalid rule operator: %s", rule.Op)
+	}
+
+	if rule.For < 0 {
+		return errors.New("rule duration can't be negative")
+	}
+
+	for _, webhook := range rule.Webhooks {
+		if err := ValidateWebhook(webhook); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateWebhook checks that the webhook is an absolute HTTP(S) URL.
+func ValidateWebhook(webhook string) error {
+	u, err := url.Parse(webhook)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook url: %s", webhook)
+	}
+	return nil
+}
+
+// Duration returns the time the condition must hold before the alert fires.
+func (r AlertRule) Duration() time.Duration {
+	return time.Duration(r.For) * time.Second
+}
+
+// Matches reports whether the series is evaluated by the rule.
+// Malformed patterns match nothing, see ValidateAlertRule.
+func (r AlertRule) Matches(metric Metrics) bool {
+	if r.MType != "" && r.MType != metric.MType {
+		return false
+	}
+
+	if matched, err := path.Match(r.Metric, metric.ID); err != nil || !matched {
+		return false
+	}
+
+	for name, value := range r.Labels {
+		if metric.Labels[name] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Compare reports whether the value holds the rule comparison.
+// Always false for OpUnchanged, which depends on the previous value.
+func (r AlertRule) Compare(value float64) bool {
+	switch r.Op {
+	case OpLess:
+		return value < r.Threshold
+	case OpLessEqual:
+		return value <= r.Threshold
+	case OpGreater:
+		return value > r.Threshold
+	case OpGreaterEqual:
+		return value >= r.Threshold
+	case OpEqual:
+		return value == r.Threshold
+	case OpNotEqual:
+		return value != r.Threshold
+	default:
+		return false
+	}
+}
+
+// AlertValue returns the value of the series compared by alert rules:
+// the gauge value, the counter total, the observation count of histograms
+// and summaries or the set cardinality.
+// Returns false if the series carries no value.
+func AlertValue(metric Metrics) (float64, bool) {
+	switch metric.MType {
+	case Gauge:
+		if metric.Value != nil {
+			return *metric.Value, true
+		}
+	case Counter:
+		if metric.Delta != nil {
+			return float64(*metric.Delta), true
+		}
+	case Histogram:
+		if metric.Count != nil {
+			return float64(*metric.Count), true
+		}
+	case Summary:
+		return float64(metric.SummarySnapshot().Count), true
+	case Set:
+		return float64(metric.Cardinality()), true
+	}
+
+	return 0, false
+}
+
+// Alert is the state of a rule evaluated for a single series.
+//
+// swagger:model Alert
+type Alert struct {
+	// Identifier of the rule raising the alert.
+	// example: low-memory
+	Rule string `json:"rule"`
+
+	// Tenant owning the rule and the series.
+	// example: team-a
+	Tenant string `json:"tenant"`
+
+	// Metric identifier (name) of the series.
+	// example: FreeMemory
+	ID string `json:"id"`
+
+	// Metric type of the series.
+	// example: gauge
+	MType string `json:"type"`
+
+	// Labels of the series.
+	// example: {"host":"web-1"}
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Rule comparison.
+	// example: <
+	Op string `json:"op"`
+
+	// Rule threshold.
+	// example: 104857600
+	Threshold float64 `json:"threshold"`
+
+	// Alert state.
+	// enum: pending,firing,resolved
+	State AlertState `json:"state"`
+
+	// Series value at the last evaluation.
+	// example: 52428800
+	Value float64 `json:"value"`
+
+	// Time the condition started to hold.
+	ActiveAt time.Time `json:"active_at"`
+
+	// Time the alert started firing.
+	FiredAt *time.Time `json:"fired_at,omitempty"`
+
+	// Time the alert was resolved.
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+
+	// Stable identity of the alert, the same for all notifications
+	// of a rule and series, used to deduplicate them.
+	// example: 9f3c2a1b7d4e5f60
+	Fingerprint string `json:"fingerprint"`
+}
+
+// AlertFingerprint returns the stable identity of the rule alert for the series.
+func AlertFingerprint(rule AlertRule, metric Metrics) string {
+	hash := fnv.New64a()
+	hash.Write([]byte(rule.Tenant + "\x00" + rule.ID + "\x00" + metric.MType + "\x00" + metric.Key()))
+	return fmt.Sprintf("%016x", hash.Sum64())
+}
+
+// AlertNotification is the webhook payload of alert state changes.
+//
+// swagger:model AlertNotification
+type AlertNotification struct {
+	// Alerts that started firing, are still firing or were resolved.
+	Alerts []Alert `json:"alerts"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// BatchError describes a rejected item of a batch update.
+//
+// swagger:model BatchError
+type BatchError struct {
+	// Position of the item in the batch, starting at 0.
+	// example: 2
+	Index int `json:"index"`
+
+	// Reason the item was rejected.
+	// example: counter delta is required
+	Error string `json:"error"`
+}
+
+// BatchResult reports per-item results of a partial batch update.
+// Valid items are applied even when other items are rejected.
+//
+// swagger:model BatchResult
+type BatchResult struct {
+	// Number of applied items.
+	// example: 9
+	Accepted int `json:"accepted"`
+
+	// Number of rejected items.
+	// example: 1
+	Rejected int `json:"rejected"`
+
+	// Rejected items ordered by index.
+	Errors []BatchError `json:"errors,omitempty"`
+}
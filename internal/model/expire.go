@@ -0,0 +1,80 @@
+package models
+
+import (
+	"path"
+	"time"
+)
+
+// DeleteQuery selects metric series to delete.
+// All series of matching metrics are deleted regardless of their labels.
+type DeleteQuery struct {
+	// ID selects a metric by its exact name.
+	// Ignored when Pattern is set.
+	ID string
+
+	// Pattern selects metrics whose names match the glob pattern,
+	// using path.Match syntax, e.g. "cpu_*".
+	Pattern string
+
+	// MType restricts deletion to the metric type, empty matches all types.
+	MType string
+}
+
+// Matches reports whether a series of the metric is selected by the query.
+// Malformed patterns match nothing, see ValidatePattern.
+func (q DeleteQuery) Matches(id string, metricType string) bool {
+	if q.MType != "" && q.MType != metricType {
+		return false
+	}
+
+	if q.Pattern == "" {
+		return q.ID == id
+	}
+
+	matched, err := path.Match(q.Pattern, id)
+	return err == nil && matched
+}
+
+// ValidatePattern checks that the glob pattern is well-formed.
+func ValidatePattern(pattern string) error {
+	_, err := path.Match(pattern, "")
+	return err
+}
+
+// DeleteResult reports series removed by a delete request.
+//
+// swagger:model DeleteResult
+type DeleteResult struct {
+	// Keys of deleted series.
+	// example: ["cpu_user","cpu_system{host=\"web-1\"}"]
+	Deleted []string `json:"deleted"`
+}
+
+// ExpirePolicy decides when a series that is not updated becomes stale.
+type ExpirePolicy struct {
+	// Now is the moment staleness is measured from.
+	Now time.Time
+
+	// Default is the TTL of metrics without their own one,
+	// zero keeps them forever.
+	Default time.Duration
+
+	// PerMetric holds TTLs of metrics partitioned by tenant,
+	// then keyed by metric ID, overriding Default.
+	PerMetric map[string]map[string]time.Duration
+}
+
+// TTL returns the TTL of the metric of the tenant, zero if it never expires.
+func (p ExpirePolicy) TTL(tenant string, id string) time.Duration {
+	if ttl, exists := p.PerMetric[tenant][id]; exists && ttl > 0 {
+		return ttl
+	}
+	return p.Default
+}
+
+// Expired reports whether a series of the metric of the tenant
+// last updated at updated is stale.
+func (p ExpirePolicy) Expired(tenant string, id string, updated time.Time) bool {
+	ttl := p.TTL(tenant, id)
+	return ttl > 0 && updated.Before(p.Now.Add(-ttl))
+}
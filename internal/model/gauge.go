@@ -0,0 +1,56 @@
+package models
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// IncrementPrefix marks a relative gauge update in plain-text values:
+// "+5" increments the gauge by 5, "+-5" decrements it by 5.
+// Values without the prefix, including negative ones, set the gauge.
+// StatsD gauges follow their own convention where "-5" decrements,
+// see the statsd package.
+const IncrementPrefix = "+"
+
+// ValidateGauge checks that the metric holds either
+// an absolute value or a relative increment, but not both.
+func ValidateGauge(m Metrics) error {
+	if m.Value != nil && m.Increment != nil {
+		return errors.New("gauge value and increment are mutually exclusive")
+	}
+
+	if m.Value == nil && m.Increment == nil {
+		return errors.New("gauge value or increment is required")
+	}
+
+	return nil
+}
+
+// ParseGauge builds a gauge update from a plain-text value.
+// Values starting with IncrementPrefix produce a relative increment,
+// other values produce an absolute value.
+//
+// Returns:
+//   - Metrics: gauge with Value or Increment set
+//   - error: if the value is not a valid float
+func ParseGauge(id string, raw string) (Metrics, error) {
+	metric := Metrics{
+		ID:    id,
+		MType: Gauge,
+	}
+
+	relative := strings.HasPrefix(raw, IncrementPrefix)
+	number, err := strconv.ParseFloat(strings.TrimPrefix(raw, IncrementPrefix), 64)
+	if err != nil {
+		return Metrics{}, err
+	}
+
+	if relative {
+		metric.Increment = &number
+	} else {
+		metric.Value = &number
+	}
+
+	return metric, nil
+}
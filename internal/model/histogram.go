@@ -0,0 +1,143 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// DefaultBounds are the bucket bounds used for histograms created
+// from single observations when no bounds are known yet.
+var DefaultBounds = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// HistogramSnapshot is a read-only view of a histogram state.
+// Returned as the histogram value in listings and value lookups.
+//
+// swagger:model HistogramSnapshot
+type HistogramSnapshot struct {
+	// Upper bucket bounds in ascending order.
+	Bounds []float64 `json:"bounds"`
+
+	// Bucket counts, one element more than bounds.
+	Buckets []int64 `json:"buckets"`
+
+	// Sum of all observed values.
+	Sum float64 `json:"sum"`
+
+	// Number of observed values.
+	Count int64 `json:"count"`
+}
+
+// String renders a compact histogram representation for HTML output.
+func (h HistogramSnapshot) String() string {
+	return fmt.Sprintf("count=%d sum=%g", h.Count, h.Sum)
+}
+
+// Snapshot returns histogram fields of the metric as a HistogramSnapshot.
+// Nil sum and count are reported as zero.
+func (m Metrics) Snapshot() HistogramSnapshot {
+	snapshot := HistogramSnapshot{
+		Bounds:  m.Bounds,
+		Buckets: m.Buckets,
+	}
+	if m.Sum != nil {
+		snapshot.Sum = *m.Sum
+	}
+	if m.Count != nil {
+		snapshot.Count = *m.Count
+	}
+	return snapshot
+}
+
+// ValidateHistogram checks that the metric holds a consistent histogram.
+//
+// Requirements:
+//   - bounds are strictly ascending
+//   - buckets has exactly len(bounds)+1 elements, none negative
+//   - sum and count are set, count is not negative
+func ValidateHistogram(m Metrics) error {
+	if m.Sum == nil || m.Count == nil {
+		return errors.New("histogram sum and count are required")
+	}
+
+	if *m.Count < 0 {
+		return errors.New("histogram count can't be negative")
+	}
+
+	for i := 1; i < len(m.Bounds); i++ {
+		if m.Bounds[i] <= m.Bounds[i-1] {
+			return errors.New("histogram bounds must be strictly ascending")
+		}
+	}
+
+	if len(m.Buckets) != len(m.Bounds)+1 {
+		return fmt.Errorf("histogram must have %d buckets, got %d", len(m.Bounds)+1, len(m.Buckets))
+	}
+
+	for _, bucket := range m.Buckets {
+		if bucket < 0 {
+			return errors.New("histogram bucket count can't be negative")
+		}
+	}
+
+	return nil
+}
+
+// ObserveHistogram creates a histogram holding a single observation.
+//
+// id: Metric identifier
+// bounds: Bucket bounds of the new histogram
+// value: Observed value
+func ObserveHistogram(id string, bounds []float64, value float64) Metrics {
+	buckets := make([]int64, len(bounds)+1)
+	index, _ := slices.BinarySearch(bounds, value)
+	buckets[index] = 1
+
+	sum := value
+	count := int64(1)
+
+	return Metrics{
+		ID:      id,
+		MType:   Histogram,
+		Bounds:  slices.Clone(bounds),
+		Buckets: buckets,
+		Sum:     &sum,
+		Count:   &count,
+	}
+}
+
+// MergeHistogram adds src histogram into dst and returns the result.
+//
+// Buckets, sum and count are added together, the same way counters add deltas.
+// Both histograms must have identical bounds.
+// Neither dst nor src are modified.
+func MergeHistogram(dst, src Metrics) (Metrics, error) {
+	if !slices.Equal(dst.Bounds, src.Bounds) {
+		return Metrics{}, fmt.Errorf("histogram %s bounds mismatch: %v != %v", dst.ID, dst.Bounds, src.Bounds)
+	}
+
+	if len(dst.Buckets) != len(src.Buckets) {
+		return Metrics{}, fmt.Errorf("histogram %s buckets mismatch", dst.ID)
+	}
+
+	dstSnapshot := dst.Snapshot()
+	srcSnapshot := src.Snapshot()
+
+	buckets := make([]int64, len(dst.Buckets))
+	for i := range buckets {
+		buckets[i] = dst.Buckets[i] + src.Buckets[i]
+	}
+
+	sum := dstSnapshot.Sum + srcSnapshot.Sum
+	count := dstSnapshot.Count + srcSnapshot.Count
+
+	return Metrics{
+		ID:      dst.ID,
+		MType:   Histogram,
+		Labels:  dst.Labels,
+		Bounds:  slices.Clone(dst.Bounds),
+		Buckets: buckets,
+		Sum:     &sum,
+		Count:   &count,
+	}, nil
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"time"
+)
+
+// Point is a single recorded value of a metric series.
+//
+// swagger:model Point
+type Point struct {
+	// Unix timestamp in milliseconds.
+	// example: 1700000000000
+	Timestamp int64 `json:"timestamp"`
+
+	// Counter increment recorded at the timestamp.
+	// Set only for counter series.
+	// example: 5
+	Delta *int64 `json:"delta,omitempty"`
+
+	// Gauge value recorded at the timestamp.
+	// Set only for gauge series.
+	// example: 3.14
+	Value *float64 `json:"value,omitempty"`
+}
+
+// RangeQuery selects points of a single series within a time interval.
+type RangeQuery struct {
+	// ID is the metric name.
+	ID string
+
+	// MType is the metric type, only counters and gauges have history.
+	MType string
+
+	// Labels select the series, nil selects the unlabeled one.
+	Labels map[string]string
+
+	// From is the inclusive start of the interval.
+	From time.Time
+
+	// To is the inclusive end of the interval.
+	To time.Time
+
+	// Step is the aggregation window, zero returns raw points.
+	Step time.Duration
+}
+
+// HasHistory reports whether values of the metric type are recorded in history.
+func HasHistory(metricType string) bool {
+	return metricType == Counter || metricType == Gauge
+}
+
+// Point returns a copy of the metric value as a history point.
+// Metrics without a timestamp are recorded at the current time.
+func (m Metrics) Point() Point {
+	var point Point
+
+	if m.Delta != nil {
+		delta := *m.Delta
+		point.Delta = &delta
+	}
+	if m.Value != nil {
+		value := *m.Value
+		point.Value = &value
+	}
+
+	if m.Timestamp != nil {
+		point.Timestamp = *m.Timestamp
+	} else {
+		point.Timestamp = time.Now().UnixMilli()
+	}
+
+	return point
+}
+
+// AggregatePoints groups chronologically ordered points into step-sized windows
+// starting at from.
+//
+// Counter deltas are summed within a window, gauges keep the last value.
+// Each resulting point is stamped with the start of its window.
+// A non-positive step returns the points unchanged.
+func AggregatePoints(points []Point, metricType string, from time.Time, step time.Duration) []Point {
+	if step <= 0 || len(points) == 0 {
+		return points
+	}
+
+	start := from.UnixMilli()
+	width := step.Milliseconds()
+	if width == 0 {
+		width = 1
+	}
+
+	result := make([]Point, 0)
+	for _, point := range points {
+		window := start + (point.Timestamp-start)/width*width
+
+		if len(result) == 0 || result[len(result)-1].Timestamp != window {
+			result = append(result, Point{Timestamp: window})
+		}
+		last := &result[len(result)-1]
+
+		switch metricType {
+		case Counter:
+			if point.Delta == nil {
+				continue
+			}
+			sum := *point.Delta
+			if last.Delta != nil {
+				sum += *last.Delta
+			}
+			last.Delta = &sum
+		case Gauge:
+			last.Value = point.Value
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// IdempotencyKeyHeader carries the idempotency key of a write request.
+// Requests repeated with the same key get the response of the first one
+// without being applied again.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// MaxIdempotencyKeyLength limits the length of idempotency keys.
+const MaxIdempotencyKeyLength = 255
+
+// IdempotentResponse is the response recorded for an idempotency key.
+type IdempotentResponse struct {
+	// Status is the HTTP status code, zero while the request is processed.
+	Status int
+
+	// ContentType of the body.
+	ContentType string
+
+	// Body of the response.
+	Body []byte
+
+	// Fingerprint identifies the request the key was claimed by,
+	// see RequestFingerprint.
+	Fingerprint string
+
+	// ExpiresAt is the time the key is forgotten.
+	ExpiresAt time.Time
+}
+
+// Pending reports whether the request of the key is still processed.
+func (r IdempotentResponse) Pending() bool {
+	return r.Status == 0
+}
+
+// RequestFingerprint identifies a request by its method, path and a SHA-256 hash of its body,
+// so that a key reused for a different request can be told apart from a repeated one.
+func RequestFingerprint(method string, path string, body []byte) string {
+	hash := sha256.Sum256(body)
+	return method + " " + path + " " + hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SeriesKey returns the identity of a metric series.
+//
+// The key is the metric name followed by the label set sorted by label name,
+// e.g. `http_latency{method="GET",route="/api"}`.
+// Unlabeled metrics are identified by their name alone, so existing
+// unlabeled series keep their keys.
+func SeriesKey(id string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return id
+	}
+
+	return id + "{" + FormatLabels(labels) + "}"
+}
+
+// Key returns the series identity of the metric.
+// See SeriesKey for the key format.
+func (m Metrics) Key() string {
+	return SeriesKey(m.ID, m.Labels)
+}
+
+// FormatLabels renders a label set sorted by label name
+// as comma separated name="value" pairs with quoted values.
+func FormatLabels(labels map[string]string) string {
+	var builder strings.Builder
+
+	for i, name := range slices.Sorted(maps.Keys(labels)) {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteString(name)
+		builder.WriteByte('=')
+		builder.WriteString(strconv.Quote(labels[name]))
+	}
+
+	return builder.String()
+}
+
+// ValidateLabels checks that all label names are valid identifiers.
+//
+// Label names must start with a letter or underscore and
+// contain only letters, digits and underscores.
+func ValidateLabels(labels map[string]string) error {
+	for name := range labels {
+		if !isLabelName(name) {
+			return fmt.Errorf("invalid label name: %q", name)
+		}
+	}
+
+	return nil
+}
+
+// isLabelName reports whether name matches [a-zA-Z_][a-zA-Z0-9_]*.
+func isLabelName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// SanitizeLabelName converts a name of another system into a label name,
+// replacing invalid characters with underscores and prefixing names
+// that start with a digit (service.name becomes service_name).
+// Returns an empty string for an empty name.
+func SanitizeLabelName(name string) string {
+	if name == "" {
+		return ""
+	}
+
+	sanitized := strings.Map(func(r rune) rune {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+
+	if sanitized[0] >= '0' && sanitized[0] <= '9' {
+		sanitized = "_" + sanitized
+	}
+
+	return sanitized
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeLabelName(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{key: "host", expected: "host"},
+		{key: "service.name", expected: "service_name"},
+		{key: "k8s.pod-name", expected: "k8s_pod_name"},
+		{key: "dc.zone", expected: "dc_zone"},
+		{key: "1st", expected: "_1st"},
+		{key: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SanitizeLabelName(tt.key))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package models
+
+import "fmt"
+
+// IsKnownType reports whether the metric type is supported.
+func IsKnownType(metricType string) bool {
+	switch metricType {
+	case Counter, Gauge, Histogram, Summary, Set:
+		return true
+	}
+	return false
+}
+
+// IsMergeable reports whether metrics of the type are combined
+// with the stored value by merging rather than adding or replacing.
+func IsMergeable(metricType string) bool {
+	return metricType == Histogram || metricType == Summary || metricType == Set
+}
+
+// Merge combines src into dst according to their type.
+// Both metrics must have the same mergeable type.
+// Neither dst nor src are modified.
+func Merge(dst, src Metrics) (Metrics, error) {
+	if dst.MType != src.MType {
+		return Metrics{}, fmt.Errorf("metric %s has type %s", dst.ID, dst.MType)
+	}
+
+	switch src.MType {
+	case Histogram:
+		return MergeHistogram(dst, src)
+	case Summary:
+		return MergeSummary(dst, src)
+	case Set:
+		return MergeSet(dst, src)
+	default:
+		return Metrics{}, fmt.Errorf("metric type %s can't be merged", src.MType)
+	}
+}
@@ -0,0 +1,43 @@
+package models
+
+import "errors"
+
+// Meta describes a metric: the unit of its values, what it measures,
+// who is responsible for it and how long its stale series are kept. Metadata is keyed by metric ID and
+// shared by all series of the metric.
+//
+// swagger:model Meta
+type Meta struct {
+	// Metric identifier (name).
+	// example: HeapAlloc
+	ID string `json:"id"`
+
+	// Unit of the metric values.
+	// example: bytes
+	Unit string `json:"unit,omitempty"`
+
+	// Human-readable description of the metric.
+	// example: Bytes of allocated heap objects
+	Description string `json:"description,omitempty"`
+
+	// Team or person owning the metric.
+	// example: platform-team
+	Owner string `json:"owner,omitempty"`
+
+	// Seconds a series of the metric is kept without updates
+	// before it is purged. Zero falls back to the server-wide TTL.
+	// example: 3600
+	TTL int64 `json:"ttl,omitempty"`
+}
+
+// ValidateMeta checks that the metadata identifies a metric
+// and its TTL is not negative.
+func ValidateMeta(meta Meta) error {
+	if meta.ID == "" {
+		return errors.New("metric id is required")
+	}
+	if meta.TTL < 0 {
+		return errors.New("metric ttl can't be negative")
+	}
+	return nil
+}
@@ -1,18 +1,44 @@
 // Package models provides data structures for metrics transmission and storage.
 package models
 
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
 const (
-	Counter = "counter"
-	Gauge   = "gauge"
+	Counter   = "counter"
+	Gauge     = "gauge"
+	Histogram = "histogram"
+	Summary   = "summary"
+	Set       = "set"
 )
 
+// MaxIDLength is the maximum length of a metric ID in characters,
+// limited by the id column of the metric table.
+const MaxIDLength = 64
+
+// ValidateID checks that the metric ID fits into MaxIDLength characters.
+func ValidateID(id string) error {
+	if utf8.RuneCountInString(id) > MaxIDLength {
+		return fmt.Errorf("metric id is longer than %d characters", MaxIDLength)
+	}
+	return nil
+}
+
 // Metrics represents a metric entity exchanged between agent and server.
 //
-// Supports two metric types:
-//   - gauge   — absolute floating-point value
-//   - counter — incremental integer value
+// Supports five metric types:
+//   - gauge     — absolute floating-point value
+//   - counter   — incremental integer value
+//   - histogram — distribution of observed values over fixed buckets
+//   - summary   — mergeable quantile sketch of observed values
+//   - set       — distinct count of members estimated with HyperLogLog
 //
-// Exactly one of `value` or `delta` must be set depending on metric type.
+// Exactly one of `value` or `delta` must be set for gauges and counters,
+// gauges may carry a relative `increment` instead of `value`.
+// Histograms carry `bounds`, `buckets`, `sum` and `count` instead,
+// summaries carry a serialized `sketch`, sets carry `members`, a `sketch` or both.
 //
 // swagger:model Metrics
 type Metrics struct {
@@ -22,9 +48,15 @@ type Metrics struct {
 
 	// Metric type.
 	// required: true
-	// enum: gauge,counter
+	// enum: gauge,counter,histogram,summary,set
 	MType string `json:"type"`
 
+	// Optional series labels.
+	// A metric series is identified by its ID together with the label set,
+	// so the same ID with different labels produces independent series.
+	// example: {"host":"web-1","route":"/api"}
+	Labels map[string]string `json:"labels,omitempty"`
+
 	// Counter increment value.
 	// Used only when type is "counter".
 	// example: 42
@@ -35,7 +67,68 @@ type Metrics struct {
 	// example: 3.14
 	Value *float64 `json:"value,omitempty"`
 
+	// Gauge relative change added to the stored value.
+	// Negative values decrement the gauge, a missing gauge starts from zero.
+	// Used only when type is "gauge" instead of value.
+	// example: -2
+	Increment *float64 `json:"increment,omitempty"`
+
+	// Histogram upper bucket bounds in ascending order.
+	// Used only when type is "histogram".
+	// example: [0.1,0.5,1]
+	Bounds []float64 `json:"bounds,omitempty"`
+
+	// Histogram bucket counts.
+	// Has one element more than bounds: the last bucket counts
+	// observations greater than the highest bound.
+	// Used only when type is "histogram".
+	// example: [3,5,1,0]
+	Buckets []int64 `json:"buckets,omitempty"`
+
+	// Sum of all observed values.
+	// Used only when type is "histogram" or "summary".
+	// example: 2.75
+	Sum *float64 `json:"sum,omitempty"`
+
+	// Number of observed values.
+	// For sets, the estimated number of distinct members.
+	// Used only when type is "histogram", "summary" or "set".
+	// example: 9
+	Count *int64 `json:"count,omitempty"`
+
+	// Serialized sketch, base64-encoded in JSON:
+	// DDSketch of observed values for summaries,
+	// HyperLogLog registers of members for sets.
+	// Used only when type is "summary" or "set".
+	Sketch []byte `json:"sketch,omitempty" swaggertype:"string" format:"base64"`
+
+	// Set members to add, duplicates are counted once.
+	// Members are folded into the sketch and never stored.
+	// Used only when type is "set".
+	// example: ["user-1","user-2"]
+	Members []string `json:"members,omitempty"`
+
+	// Estimated quantile values keyed by quantile, such as "0.99".
+	// Filled in value lookups of summaries, ignored on updates.
+	// example: {"0.5":0.12,"0.99":0.87}
+	Quantiles map[string]float64 `json:"quantiles,omitempty"`
+
+	// Optional unit of the metric values.
+	// Registered as metric metadata on first report if none is stored yet,
+	// filled from metadata in value lookups.
+	// example: bytes
+	Unit string `json:"unit,omitempty"`
+
+	// Optional Unix timestamp of the value in milliseconds.
+	// The server time is used when omitted.
+	// example: 1700000000000
+	Timestamp *int64 `json:"timestamp,omitempty"`
+
 	// Optional integrity hash.
 	// example: 1a2b3c4d
 	Hash string `json:"hash,omitempty"`
+
+	// Tenant owning the series, resolved by the server from the request.
+	// Ignored on updates, kept in dumps to restore series of every tenant.
+	Tenant string `json:"tenant,omitempty" swaggerignore:"true"`
 }
@@ -0,0 +1,224 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const (
+	// SortByID orders series by metric ID, then by labels.
+	SortByID = "id"
+
+	// SortByType orders series by metric type, then by metric ID and labels.
+	SortByType = "type"
+)
+
+// SeriesRef identifies a single metric series of a known type.
+//
+// swagger:model SeriesRef
+type SeriesRef struct {
+	// Metric identifier (name).
+	// required: true
+	ID string `json:"id"`
+
+	// Metric type.
+	// required: true
+	// enum: gauge,counter,histogram,summary,set
+	MType string `json:"type"`
+
+	// Optional series labels, the unlabeled series when omitted.
+	// example: {"host":"web-1"}
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Key returns the series identity of the reference.
+// See SeriesKey for the key format.
+func (r SeriesRef) Key() string {
+	return SeriesKey(r.ID, r.Labels)
+}
+
+// Ref returns the reference of the metric series.
+func (m Metrics) Ref() SeriesRef {
+	return SeriesRef{ID: m.ID, MType: m.MType, Labels: m.Labels}
+}
+
+// ListQuery selects, orders and paginates metric series.
+// All set filters must match.
+type ListQuery struct {
+	// MType restricts series to the metric type, empty matches all types.
+	MType string
+
+	// Prefix selects metrics whose IDs start with the prefix.
+	Prefix string
+
+	// Pattern selects metrics whose IDs match the glob pattern,
+	// using path.Match syntax, e.g. "cpu_*".
+	Pattern string
+
+	// Regex selects metrics whose IDs match the regular expression
+	// in Go RE2 syntax.
+	Regex string
+
+	// SortBy is the sort field, SortByID or SortByType.
+	SortBy string
+
+	// Descending reverses the sort order.
+	Descending bool
+
+	// Limit is the maximum number of series, zero means no limit.
+	Limit int
+
+	// After is the last series of the previous page,
+	// nil starts from the first series.
+	After *SeriesRef
+}
+
+// Matches reports whether a series of the metric passes the query filters.
+// Malformed patterns and expressions match nothing, see ListQuery.Validate.
+// The regular expression is compiled on every call,
+// use Matcher to filter many series.
+func (q ListQuery) Matches(id string, metricType string) bool {
+	return q.Matcher()(id, metricType)
+}
+
+// Matcher returns a function reporting whether a series of the metric
+// passes the query filters, with the regular expression compiled once.
+// Malformed patterns and expressions match nothing, see ListQuery.Validate.
+func (q ListQuery) Matcher() func(id string, metricType string) bool {
+	var re *regexp.Regexp
+	if q.Regex != "" {
+		var err error
+		if re, err = regexp.Compile(q.Regex); err != nil {
+			return func(string, string) bool { return false }
+		}
+	}
+
+	return func(id string, metricType string) bool {
+		if q.MType != "" && q.MType != metricType {
+			return false
+		}
+
+		if !strings.HasPrefix(id, q.Prefix) {
+			return false
+		}
+
+		if q.Pattern != "" {
+			if matched, err := path.Match(q.Pattern, id); err != nil || !matched {
+				return false
+			}
+		}
+
+		return re == nil || re.MatchString(id)
+	}
+}
+
+// Validate checks the sort field, the pattern and the regular expression.
+func (q ListQuery) Validate() error {
+	if q.SortBy != "" && q.SortBy != SortByID && q.SortBy != SortByType {
+		return errors.New("invalid sort field: " + q.SortBy)
+	}
+
+	if err := ValidatePattern(q.Pattern); err != nil {
+		return errors.New("invalid pattern: " + err.Error())
+	}
+
+	if _, err := regexp.Compile(q.Regex); err != nil {
+		return errors.New("invalid regex: " + err.Error())
+	}
+
+	return nil
+}
+
+// MetricsPage is a single page of a metric listing.
+//
+// swagger:model MetricsPage
+type MetricsPage struct {
+	// Series of the page.
+	Metrics []Metrics `json:"metrics"`
+
+	// Opaque cursor of the next page, omitted on the last page.
+	// example: eyJpZCI6ImNwdSIsInR5cGUiOiJnYXVnZSJ9
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// EncodeCursor returns the opaque page cursor positioned after the series.
+func EncodeCursor(ref SeriesRef) string {
+	raw, _ := json.Marshal(ref)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor parses a page cursor built by EncodeCursor.
+func DecodeCursor(cursor string) (SeriesRef, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return SeriesRef{}, errors.New("invalid cursor")
+	}
+
+	var ref SeriesRef
+	if err := json.Unmarshal(raw, &ref); err != nil || ref.ID == "" {
+		return SeriesRef{}, errors.New("invalid cursor")
+	}
+
+	return ref, nil
+}
+
+// GlobRegex translates a path.Match glob pattern into an anchored
+// regular expression understood by both Go and PostgreSQL.
+func GlobRegex(pattern string) (string, error) {
+	if err := ValidatePattern(pattern); err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	builder.WriteByte('^')
+
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '*':
+			builder.WriteString("[^/]*")
+		case '?':
+			builder.WriteString("[^/]")
+		case '\\':
+			i++
+			builder.WriteString(regexp.QuoteMeta(string(runes[i])))
+		case '[':
+			builder.WriteByte('[')
+			i++
+			if runes[i] == '^' {
+				builder.WriteByte('^')
+				i++
+			}
+			for ; runes[i] != ']'; i++ {
+				escaped := runes[i] == '\\'
+				if escaped {
+					i++
+				}
+				if runes[i] == '-' && !escaped {
+					builder.WriteByte('-')
+					continue
+				}
+				writeClassRune(&builder, runes[i])
+			}
+			builder.WriteByte(']')
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	builder.WriteByte('$')
+	return builder.String(), nil
+}
+
+// writeClassRune writes a literal rune of a bracket expression,
+// escaping everything but letters and digits.
+func writeClassRune(builder *strings.Builder, r rune) {
+	if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+		builder.WriteByte('\\')
+	}
+	builder.WriteRune(r)
+}
@@ -0,0 +1,116 @@
+package models
+
+import "time"
+
+// RateQuery selects the recent samples of a single series.
+type RateQuery struct {
+	// ID is the metric name.
+	ID string
+
+	// MType is the metric type, only counters and gauges have rates.
+	MType string
+
+	// Labels select the series, nil selects the unlabeled one.
+	Labels map[string]string
+
+	// Window is the length of the interval ending now.
+	Window time.Duration
+}
+
+// Rate describes how a series changed within a window.
+//
+// Counters report their increase and per-second rate,
+// gauges report their per-second derivative.
+//
+// swagger:model Rate
+type Rate struct {
+	// Metric identifier (name).
+	ID string `json:"id"`
+
+	// Metric type.
+	// enum: gauge,counter
+	MType string `json:"type"`
+
+	// Series labels.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Window length in seconds.
+	// example: 60
+	Window float64 `json:"window"`
+
+	// Number of samples within the window, samples are kept at one second resolution.
+	// example: 12
+	Samples int `json:"samples"`
+
+	// Sum of counter deltas within the window.
+	// Set only for counter series.
+	// example: 120
+	Increase *int64 `json:"increase,omitempty"`
+
+	// Counter increase per second of the window.
+	// Set only for counter series.
+	// example: 2
+	Rate *float64 `json:"rate,omitempty"`
+
+	// Least-squares slope of gauge values per second.
+	// Set only for gauge series with samples at two distinct times at least.
+	// example: -0.5
+	Derivative *float64 `json:"derivative,omitempty"`
+}
+
+// NewRate computes the rate of a series from its chronologically
+// ordered samples within the query window.
+func NewRate(query RateQuery, points []Point) Rate {
+	rate := Rate{
+		ID:      query.ID,
+		MType:   query.MType,
+		Labels:  query.Labels,
+		Window:  query.Window.Seconds(),
+		Samples: len(points),
+	}
+
+	switch query.MType {
+	case Counter:
+		var increase int64
+		for _, point := range points {
+			if point.Delta != nil {
+				increase += *point.Delta
+			}
+		}
+		perSecond := float64(increase) / rate.Window
+		rate.Increase = &increase
+		rate.Rate = &perSecond
+	case Gauge:
+		rate.Derivative = derivative(points)
+	}
+
+	return rate
+}
+
+// derivative returns the least-squares slope of gauge values per second,
+// nil if the values were not sampled at two distinct times.
+func derivative(points []Point) *float64 {
+	var n, sumX, sumY, sumXY, sumXX float64
+
+	for _, point := range points {
+		if point.Value == nil {
+			continue
+		}
+		x := float64(point.Timestamp-points[0].Timestamp) / 1000
+		y := *point.Value
+
+		n++
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if n < 2 || denominator == 0 {
+		return nil
+	}
+
+	slope := (n*sumXY - sumX*sumY) / denominator
+	return &slope
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gabkaclassic/metrics/pkg/sketch"
+)
+
+// DefaultSetPrecision is the HyperLogLog precision of sets built
+// from members, about 1.6% standard error with 4 KiB of registers.
+const DefaultSetPrecision = 12
+
+// DecodeSet deserializes the HyperLogLog of a set metric.
+func (m Metrics) DecodeSet() (*sketch.HyperLogLog, error) {
+	var decoded sketch.HyperLogLog
+	if err := decoded.UnmarshalBinary(m.Sketch); err != nil {
+		return nil, err
+	}
+	return &decoded, nil
+}
+
+// Cardinality returns the estimated number of distinct members of a set metric.
+// Metrics with an invalid sketch are reported as empty.
+func (m Metrics) Cardinality() int64 {
+	decoded, err := m.DecodeSet()
+	if err != nil {
+		return 0
+	}
+	return int64(decoded.Estimate())
+}
+
+// ValidateSet checks that the metric carries members or a decodable HyperLogLog.
+func ValidateSet(m Metrics) error {
+	if len(m.Members) == 0 && len(m.Sketch) == 0 {
+		return errors.New("set members or sketch are required")
+	}
+
+	if len(m.Sketch) == 0 {
+		return nil
+	}
+
+	if _, err := m.DecodeSet(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ObserveSet creates a set holding the given members.
+//
+// id: Metric identifier
+// members: Observed members, duplicates are counted once
+func ObserveSet(id string, members ...string) Metrics {
+	observed, _ := sketch.NewHyperLogLog(DefaultSetPrecision)
+	for _, member := range members {
+		observed.Add(member)
+	}
+
+	return setMetric(id, nil, observed)
+}
+
+// NormalizeSet folds the members of a set metric into its HyperLogLog.
+//
+// Members are added to the sent sketch, or to a new sketch with
+// DefaultSetPrecision when only members are sent. The result carries
+// the sketch and its cardinality, without members.
+func NormalizeSet(m Metrics) (Metrics, error) {
+	if err := ValidateSet(m); err != nil {
+		return Metrics{}, err
+	}
+
+	var hll *sketch.HyperLogLog
+	if len(m.Sketch) == 0 {
+		hll, _ = sketch.NewHyperLogLog(DefaultSetPrecision)
+	} else {
+		hll, _ = m.DecodeSet()
+	}
+
+	for _, member := range m.Members {
+		hll.Add(member)
+	}
+
+	normalized := setMetric(m.ID, m.Labels, hll)
+	normalized.Timestamp = m.Timestamp
+	return normalized, nil
+}
+
+// MergeSet adds src set into dst and returns the result.
+//
+// Both metrics must carry a sketch, use NormalizeSet for member lists.
+// Sketches must have the same precision.
+// Neither dst nor src are modified.
+func MergeSet(dst, src Metrics) (Metrics, error) {
+	srcSet, err := src.DecodeSet()
+	if err != nil {
+		return Metrics{}, fmt.Errorf("set %s: %w", src.ID, err)
+	}
+
+	if len(dst.Sketch) == 0 {
+		return setMetric(dst.ID, dst.Labels, srcSet), nil
+	}
+
+	dstSet, err := dst.DecodeSet()
+	if err != nil {
+		return Metrics{}, fmt.Errorf("set %s: %w", dst.ID, err)
+	}
+
+	if err := dstSet.Merge(srcSet); err != nil {
+		return Metrics{}, fmt.Errorf("set %s: %w", dst.ID, err)
+	}
+
+	return setMetric(dst.ID, dst.Labels, dstSet), nil
+}
+
+// setMetric builds a set metric from a HyperLogLog.
+func setMetric(id string, labels map[string]string, hll *sketch.HyperLogLog) Metrics {
+	encoded, _ := hll.MarshalBinary()
+	count := int64(hll.Estimate())
+
+	return Metrics{
+		ID:     id,
+		MType:  Set,
+		Labels: labels,
+		Sketch: encoded,
+		Count:  &count,
+	}
+}
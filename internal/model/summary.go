@@ -0,0 +1,153 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gabkaclassic/metrics/pkg/sketch"
+)
+
+// DefaultSketchAccuracy is the relative accuracy of summaries
+// created from single observations.
+const DefaultSketchAccuracy = 0.01
+
+// DefaultQuantiles are the quantiles reported for summaries.
+var DefaultQuantiles = []float64{0.5, 0.9, 0.95, 0.99}
+
+// SummarySnapshot is a read-only view of a summary state.
+// Returned as the summary value in listings and value lookups.
+//
+// swagger:model SummarySnapshot
+type SummarySnapshot struct {
+	// Number of observed values.
+	Count int64 `json:"count"`
+
+	// Sum of all observed values.
+	Sum float64 `json:"sum"`
+
+	// Estimated values of DefaultQuantiles keyed by quantile.
+	// Empty for summaries without observations.
+	Quantiles map[string]float64 `json:"quantiles"`
+}
+
+// String renders a compact summary representation for HTML output.
+func (s SummarySnapshot) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "count=%d sum=%g", s.Count, s.Sum)
+
+	keys := make([]string, 0, len(s.Quantiles))
+	for key := range s.Quantiles {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(&builder, " q%s=%g", key, s.Quantiles[key])
+	}
+	return builder.String()
+}
+
+// QuantileKey formats a quantile as a key of SummarySnapshot.Quantiles.
+func QuantileKey(q float64) string {
+	return strconv.FormatFloat(q, 'f', -1, 64)
+}
+
+// DecodeSketch deserializes the sketch of a summary metric.
+func (m Metrics) DecodeSketch() (*sketch.DDSketch, error) {
+	var decoded sketch.DDSketch
+	if err := decoded.UnmarshalBinary(m.Sketch); err != nil {
+		return nil, err
+	}
+	return &decoded, nil
+}
+
+// SummarySnapshot returns count, sum and DefaultQuantiles of a summary metric.
+// Metrics with an invalid sketch are reported as empty.
+func (m Metrics) SummarySnapshot() SummarySnapshot {
+	snapshot := SummarySnapshot{Quantiles: make(map[string]float64)}
+
+	decoded, err := m.DecodeSketch()
+	if err != nil {
+		return snapshot
+	}
+
+	snapshot.Count = int64(decoded.Count())
+	snapshot.Sum = decoded.Sum()
+	for _, q := range DefaultQuantiles {
+		if value, err := decoded.Quantile(q); err == nil {
+			snapshot.Quantiles[QuantileKey(q)] = value
+		}
+	}
+
+	return snapshot
+}
+
+// ValidateSummary checks that the metric holds a decodable sketch.
+func ValidateSummary(m Metrics) error {
+	if len(m.Sketch) == 0 {
+		return errors.New("summary sketch is required")
+	}
+
+	if _, err := m.DecodeSketch(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ObserveSummary creates a summary holding a single observation.
+//
+// id: Metric identifier
+// value: Observed value
+func ObserveSummary(id string, value float64) Metrics {
+	observed, _ := sketch.NewDDSketch(DefaultSketchAccuracy)
+	observed.Add(value)
+
+	return summaryMetric(id, nil, observed)
+}
+
+// MergeSummary adds src summary into dst and returns the result.
+//
+// Sketches are merged and sum and count are refreshed from the result.
+// Both sketches must have the same relative accuracy.
+// Neither dst nor src are modified.
+func MergeSummary(dst, src Metrics) (Metrics, error) {
+	srcSketch, err := src.DecodeSketch()
+	if err != nil {
+		return Metrics{}, fmt.Errorf("summary %s: %w", src.ID, err)
+	}
+
+	if len(dst.Sketch) == 0 {
+		return summaryMetric(dst.ID, dst.Labels, srcSketch), nil
+	}
+
+	dstSketch, err := dst.DecodeSketch()
+	if err != nil {
+		return Metrics{}, fmt.Errorf("summary %s: %w", dst.ID, err)
+	}
+
+	if err := dstSketch.Merge(srcSketch); err != nil {
+		return Metrics{}, fmt.Errorf("summary %s: %w", dst.ID, err)
+	}
+
+	return summaryMetric(dst.ID, dst.Labels, dstSketch), nil
+}
+
+// summaryMetric builds a summary metric from a sketch.
+func summaryMetric(id string, labels map[string]string, s *sketch.DDSketch) Metrics {
+	encoded, _ := s.MarshalBinary()
+	sum := s.Sum()
+	count := int64(s.Count())
+
+	return Metrics{
+		ID:     id,
+		MType:  Summary,
+		Labels: labels,
+		Sketch: encoded,
+		Sum:    &sum,
+		Count:  &count,
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+// Counter import modes.
+const (
+	// CounterAdd adds imported counter values to the stored ones.
+	CounterAdd = "add"
+
+	// CounterReplace sets counters to the last imported value of their series.
+	CounterReplace = "replace"
+)
+
+// ImportRecord is a decoded metric with its position in the imported document.
+type ImportRecord struct {
+	// Line is the position of the record, see ImportError.Line.
+	Line int
+
+	// Metric is the decoded metric.
+	Metric Metrics
+}
+
+// ImportOptions controls how imported metrics are stored.
+type ImportOptions struct {
+	// DryRun validates records without storing them.
+	DryRun bool
+
+	// Counters is CounterAdd or CounterReplace, empty means CounterAdd.
+	Counters string
+}
+
+// ImportError describes a record that was not imported.
+//
+// swagger:model ImportError
+type ImportError struct {
+	// Line of CSV and NDJSON records, position of JSON array elements, starting at 1.
+	// example: 3
+	Line int `json:"line"`
+
+	// Reason the record was rejected.
+	// example: counter delta is required
+	Error string `json:"error"`
+}
+
+// ImportReport summarizes an import.
+// Valid records are imported even when other records are rejected.
+//
+// swagger:model ImportReport
+type ImportReport struct {
+	// Number of records read.
+	// example: 10
+	Total int `json:"total"`
+
+	// Number of valid records, imported unless dry_run is set.
+	// example: 9
+	Imported int `json:"imported"`
+
+	// Number of rejected records.
+	// example: 1
+	Failed int `json:"failed"`
+
+	// Whether records were only validated.
+	DryRun bool `json:"dry_run"`
+
+	// Rejected records ordered by line.
+	Errors []ImportError `json:"errors,omitempty"`
+}
@@ -0,0 +1,190 @@
+// Package otlp receives metrics in the OTLP/HTTP JSON encoding.
+//
+// The package declares the subset of the OTLP metrics data model
+// needed to read gauges and sums, and a Translator converting
+// export requests to metrics of the server.
+package otlp
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// Aggregation temporalities of sums.
+const (
+	TemporalityUnspecified = 0
+	TemporalityDelta       = 1
+	TemporalityCumulative  = 2
+)
+
+// ExportMetricsServiceRequest is the body of an OTLP metrics export.
+type ExportMetricsServiceRequest struct {
+	// Metrics grouped by the resource reporting them.
+	ResourceMetrics []ResourceMetrics `json:"resourceMetrics"`
+}
+
+// ResourceMetrics holds metrics of a single resource.
+type ResourceMetrics struct {
+	// Resource reporting the metrics.
+	Resource Resource `json:"resource"`
+
+	// Metrics grouped by instrumentation scope.
+	ScopeMetrics []ScopeMetrics `json:"scopeMetrics"`
+}
+
+// Resource describes the entity reporting metrics, such as a service instance.
+type Resource struct {
+	// Resource attributes, such as service.name.
+	Attributes []KeyValue `json:"attributes,omitempty"`
+}
+
+// ScopeMetrics holds metrics of a single instrumentation scope.
+type ScopeMetrics struct {
+	// Reported metrics.
+	Metrics []Metric `json:"metrics"`
+}
+
+// Metric is a named metric with data points of exactly one type.
+type Metric struct {
+	// Metric name.
+	// example: http.server.requests
+	Name string `json:"name"`
+
+	// Optional unit of the data point values.
+	// example: ms
+	Unit string `json:"unit,omitempty"`
+
+	// Gauge data points.
+	Gauge *Gauge `json:"gauge,omitempty"`
+
+	// Sum data points.
+	Sum *Sum `json:"sum,omitempty"`
+
+	// Histogram data points, not supported.
+	Histogram json.RawMessage `json:"histogram,omitempty" swaggertype:"object"`
+
+	// Exponential histogram data points, not supported.
+	ExponentialHistogram json.RawMessage `json:"exponentialHistogram,omitempty" swaggertype:"object"`
+
+	// Summary data points, not supported.
+	Summary json.RawMessage `json:"summary,omitempty" swaggertype:"object"`
+}
+
+// Gauge holds sampled values.
+type Gauge struct {
+	// Data points of the gauge.
+	DataPoints []NumberDataPoint `json:"dataPoints"`
+}
+
+// Sum holds values aggregated over time.
+type Sum struct {
+	// Data points of the sum.
+	DataPoints []NumberDataPoint `json:"dataPoints"`
+
+	// Aggregation temporality: 1 for delta, 2 for cumulative.
+	// enum: 0,1,2
+	AggregationTemporality int `json:"aggregationTemporality"`
+
+	// Whether the sum only increases.
+	IsMonotonic bool `json:"isMonotonic"`
+}
+
+// NumberDataPoint is a single value of a series.
+type NumberDataPoint struct {
+	// Series attributes.
+	Attributes []KeyValue `json:"attributes,omitempty"`
+
+	// Start of the aggregation interval in Unix nanoseconds.
+	StartTimeUnixNano Int64 `json:"startTimeUnixNano,omitempty" swaggertype:"string"`
+
+	// Time of the value in Unix nanoseconds.
+	TimeUnixNano Int64 `json:"timeUnixNano,omitempty" swaggertype:"string"`
+
+	// Floating-point value.
+	AsDouble *float64 `json:"asDouble,omitempty"`
+
+	// Integer value.
+	AsInt *Int64 `json:"asInt,omitempty" swaggertype:"string"`
+}
+
+// KeyValue is a named attribute.
+type KeyValue struct {
+	// Attribute name.
+	Key string `json:"key"`
+
+	// Attribute value.
+	Value AnyValue `json:"value"`
+}
+
+// AnyValue is an attribute value of one of the supported types.
+type AnyValue struct {
+	StringValue *string          `json:"stringValue,omitempty"`
+	BoolValue   *bool            `json:"boolValue,omitempty"`
+	IntValue    *Int64           `json:"intValue,omitempty" swaggertype:"string"`
+	DoubleValue *float64         `json:"doubleValue,omitempty"`
+	ArrayValue  *json.RawMessage `json:"arrayValue,omitempty" swaggertype:"object"`
+	KvlistValue *json.RawMessage `json:"kvlistValue,omitempty" swaggertype:"object"`
+	BytesValue  *string          `json:"bytesValue,omitempty"`
+}
+
+// String renders the value as a label value,
+// arrays and key-value lists are rendered as their JSON.
+func (v AnyValue) String() string {
+	switch {
+	case v.StringValue != nil:
+		return *v.StringValue
+	case v.BoolValue != nil:
+		return strconv.FormatBool(*v.BoolValue)
+	case v.IntValue != nil:
+		return strconv.FormatInt(int64(*v.IntValue), 10)
+	case v.DoubleValue != nil:
+		return strconv.FormatFloat(*v.DoubleValue, 'g', -1, 64)
+	case v.ArrayValue != nil:
+		return string(*v.ArrayValue)
+	case v.KvlistValue != nil:
+		return string(*v.KvlistValue)
+	case v.BytesValue != nil:
+		return *v.BytesValue
+	}
+	return ""
+}
+
+// Int64 is a 64-bit integer encoded as a JSON string or number,
+// OTLP encodes 64-bit integers as strings.
+type Int64 int64
+
+// UnmarshalJSON decodes quoted and unquoted integers.
+func (i *Int64) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var raw string
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		data = []byte(raw)
+	}
+
+	parsed, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return err
+	}
+
+	*i = Int64(parsed)
+	return nil
+}
+
+// ExportMetricsServiceResponse is the response of an OTLP metrics export.
+type ExportMetricsServiceResponse struct {
+	// Set when some data points were rejected.
+	PartialSuccess *PartialSuccess `json:"partialSuccess,omitempty"`
+}
+
+// PartialSuccess reports data points rejected by the server.
+type PartialSuccess struct {
+	// Number of rejected data points.
+	// example: 2
+	RejectedDataPoints int64 `json:"rejectedDataPoints"`
+
+	// Reason of the first rejection.
+	// example: histogram metrics are not supported
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
@@ -0,0 +1,65 @@
+package otlp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInt64_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected Int64
+		wantErr  bool
+	}{
+		{name: "string", data: `"1700000000000000000"`, expected: 1700000000000000000},
+		{name: "number", data: `42`, expected: 42},
+		{name: "negative", data: `"-7"`, expected: -7},
+		{name: "float", data: `1.5`, wantErr: true},
+		{name: "invalid string", data: `"many"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value Int64
+			err := json.Unmarshal([]byte(tt.data), &value)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestAnyValue_String(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{name: "string", data: `{"stringValue":"checkout"}`, expected: "checkout"},
+		{name: "bool", data: `{"boolValue":true}`, expected: "true"},
+		{name: "int", data: `{"intValue":"3"}`, expected: "3"},
+		{name: "double", data: `{"doubleValue":0.5}`, expected: "0.5"},
+		{name: "array", data: `{"arrayValue":{"values":[{"stringValue":"a"}]}}`, expected: `{"values":[{"stringValue":"a"}]}`},
+		{name: "kvlist", data: `{"kvlistValue":{"values":[]}}`, expected: `{"values":[]}`},
+		{name: "bytes", data: `{"bytesValue":"AQI="}`, expected: "AQI="},
+		{name: "empty", data: `{}`, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value AnyValue
+			require.NoError(t, json.Unmarshal([]byte(tt.data), &value))
+
+			assert.Equal(t, tt.expected, value.String())
+		})
+	}
+}
@@ -0,0 +1,356 @@
+package otlp
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"sync"
+	"time"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+)
+
+// Translator converts OTLP export requests to metrics.
+//
+// Gauges become gauges and monotonic sums become counters.
+// Non-monotonic sums can decrease, so they are stored as gauges of their current value.
+// Resource attributes are merged with data point attributes into series labels,
+// attribute names are sanitized into label names (service.name becomes service_name).
+//
+// Cumulative sums are converted to deltas against the last total of their series.
+// The first total of a series is taken as a whole if the series started after
+// the translator was created and is otherwise only remembered as the baseline,
+// so that totals accumulated before a server restart are not counted twice.
+// A total below the previous one or a new start time marks a counter reset.
+//
+// Totals are remembered as soon as a request is translated, so that concurrent
+// requests reporting the same total don't count its increase twice.
+// If the metrics of a request fail to save, Rollback takes the increases
+// of the request back out of the remembered totals, so they are counted on retry.
+// Monotonic sums must have integer values, fractional doubles are rejected.
+// Totals of series not reported for the totals TTL are evicted. Series started
+// before the latest evicted one are taken as baselines when they reappear,
+// like series started before the translator, so they are not counted twice.
+type Translator struct {
+	// started is the creation time of the translator in Unix nanoseconds,
+	// raised to the start time of evicted series.
+	started int64
+
+	// ttl is how long totals of unreported series are kept, zero keeps them forever.
+	ttl time.Duration
+
+	// mu guards started, totals and evicted.
+	mu sync.Mutex
+
+	// totals holds the last cumulative total of series keyed by tenant and series key.
+	totals map[string]cumulative
+
+	// evicted is the time of the last eviction.
+	evicted time.Time
+
+	// now returns the current time, replaced in tests.
+	now func() time.Time
+}
+
+// cumulative is the last reported total of a cumulative sum.
+type cumulative struct {
+	start int64
+	total int64
+
+	// seen is the time the total was reported.
+	seen time.Time
+}
+
+// increase is the part of a cumulative sum counted by a request.
+type increase struct {
+	start int64
+	delta int64
+}
+
+// Increases holds increases of cumulative sums counted by a translated request
+// keyed by tenant and series key, see Translator.Rollback.
+type Increases map[string]increase
+
+// NewTranslator creates a new OTLP translator.
+//
+// ttl: How long totals of unreported series are kept, zero keeps them forever
+//
+// Returns:
+//   - *Translator: Translator without known cumulative totals
+func NewTranslator(ttl time.Duration) *Translator {
+	now := time.Now()
+
+	return &Translator{
+		started: now.UnixNano(),
+		ttl:     ttl,
+		totals:  make(map[string]cumulative),
+		evicted: now,
+		now:     time.Now,
+	}
+}
+
+// Translate converts an export request of a tenant to metrics.
+//
+// tenant: Tenant owning the series, separates cumulative totals of tenants
+// request: Decoded export request
+//
+// Returns:
+//   - []models.Metrics: Metrics of all accepted data points
+//   - Increases: Increases of cumulative sums, to be rolled back if the metrics fail to save
+//   - *PartialSuccess: Rejected data points and the first rejection reason, nil if none were rejected
+//
+// Histograms, exponential histograms and summaries are not supported and rejected.
+func (t *Translator) Translate(tenant string, request ExportMetricsServiceRequest) ([]models.Metrics, Increases, *PartialSuccess) {
+	t.expire()
+
+	metrics := make([]models.Metrics, 0)
+	increases := make(Increases)
+	rejected := &PartialSuccess{}
+
+	for _, resourceMetrics := range request.ResourceMetrics {
+		resourceLabels := labelsOf(nil, resourceMetrics.Resource.Attributes)
+
+		for _, scopeMetrics := range resourceMetrics.ScopeMetrics {
+			for _, metric := range scopeMetrics.Metrics {
+				metrics = append(metrics, t.translateMetric(tenant, metric, resourceLabels, increases, rejected)...)
+			}
+		}
+	}
+
+	if rejected.RejectedDataPoints == 0 {
+		return metrics, increases, nil
+	}
+
+	return metrics, increases, rejected
+}
+
+// Rollback takes increases of a request whose metrics failed to save
+// back out of the remembered totals, so that a retry counts them again.
+// Increases of series that were reset or evicted since are dropped,
+// as well as increases counted before a reset within the request.
+//
+// increases: Increases returned by Translate
+func (t *Translator) Rollback(increases Increases) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key, increase := range increases {
+		total, exists := t.totals[key]
+		if !exists || total.start != increase.start {
+			continue
+		}
+		total.total -= increase.delta
+		t.totals[key] = total
+	}
+}
+
+// expire evicts totals of series not reported for the totals TTL,
+// at most once per TTL.
+func (t *Translator) expire() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if t.ttl > 0 && now.Sub(t.evicted) >= t.ttl {
+		t.evict(now.Add(-t.ttl))
+		t.evicted = now
+	}
+}
+
+// evict forgets totals reported before cutoff. Must be called with mu held.
+func (t *Translator) evict(cutoff time.Time) {
+	for key, total := range t.totals {
+		if total.seen.Before(cutoff) {
+			t.started = max(t.started, total.start)
+			delete(t.totals, key)
+		}
+	}
+}
+
+// translateMetric converts data points of a metric, counting rejected ones.
+func (t *Translator) translateMetric(
+	tenant string,
+	metric Metric,
+	resourceLabels map[string]string,
+	increases Increases,
+	rejected *PartialSuccess,
+) []models.Metrics {
+	switch {
+	case metric.Gauge != nil:
+		if metric.Name == "" {
+			reject(rejected, len(metric.Gauge.DataPoints), "metric name is missing")
+			return nil
+		}
+	case metric.Sum != nil:
+		if metric.Name == "" {
+			reject(rejected, len(metric.Sum.DataPoints), "metric name is missing")
+			return nil
+		}
+		if metric.Sum.AggregationTemporality != TemporalityDelta && metric.Sum.AggregationTemporality != TemporalityCumulative {
+			reject(rejected, len(metric.Sum.DataPoints), "sum %q has unspecified aggregation temporality", metric.Name)
+			return nil
+		}
+	case metric.Histogram != nil:
+		reject(rejected, countDataPoints(metric.Histogram), "histogram %q is not supported", metric.Name)
+		return nil
+	case metric.ExponentialHistogram != nil:
+		reject(rejected, countDataPoints(metric.ExponentialHistogram), "exponential histogram %q is not supported", metric.Name)
+		return nil
+	case metric.Summary != nil:
+		reject(rejected, countDataPoints(metric.Summary), "summary %q is not supported", metric.Name)
+		return nil
+	default:
+		return nil
+	}
+
+	var dataPoints []NumberDataPoint
+	if metric.Gauge != nil {
+		dataPoints = metric.Gauge.DataPoints
+	} else {
+		dataPoints = metric.Sum.DataPoints
+	}
+
+	metrics := make([]models.Metrics, 0, len(dataPoints))
+	for _, point := range dataPoints {
+		value, ok := point.value()
+		if !ok {
+			reject(rejected, 1, "data point of %q has no valid value", metric.Name)
+			continue
+		}
+
+		converted := models.Metrics{
+			ID:     metric.Name,
+			Labels: labelsOf(resourceLabels, point.Attributes),
+			Unit:   metric.Unit,
+		}
+		if point.TimeUnixNano > 0 {
+			timestamp := int64(point.TimeUnixNano) / int64(time.Millisecond)
+			converted.Timestamp = &timestamp
+		}
+
+		if metric.Gauge != nil || !metric.Sum.IsMonotonic {
+			converted.MType = models.Gauge
+			converted.Value = &value
+			metrics = append(metrics, converted)
+			continue
+		}
+
+		if value < 0 {
+			reject(rejected, 1, "monotonic sum %q has a negative value", metric.Name)
+			continue
+		}
+		if value != math.Trunc(value) || value >= math.MaxInt64 {
+			reject(rejected, 1, "monotonic sum %q has a non-integer value", metric.Name)
+			continue
+		}
+
+		delta := int64(value)
+		if metric.Sum.AggregationTemporality == TemporalityCumulative {
+			delta, ok = t.delta(increases, tenant+"\x00"+converted.Key(), int64(point.StartTimeUnixNano), delta)
+			if !ok {
+				continue
+			}
+		}
+
+		converted.MType = models.Counter
+		converted.Delta = &delta
+		metrics = append(metrics, converted)
+	}
+
+	return metrics
+}
+
+// delta converts a cumulative total of a series to the increase since its last total,
+// remembers the total and adds the increase to the increases of the request.
+// Reports false for baseline totals that add nothing.
+func (t *Translator) delta(increases Increases, key string, start int64, total int64) (int64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous, exists := t.totals[key]
+	t.totals[key] = cumulative{start: start, total: total, seen: t.now()}
+
+	continued := exists && previous.start == start && previous.total <= total
+
+	var delta int64
+	switch {
+	case continued:
+		delta = total - previous.total
+	case exists, start > t.started:
+		delta = total
+	default:
+		return 0, false
+	}
+
+	counted := increase{start: start}
+	if saved, counting := increases[key]; continued && counting && saved.start == start {
+		counted = saved
+	}
+	counted.delta += delta
+	increases[key] = counted
+
+	return delta, true
+}
+
+// value returns the value of the data point as a float.
+func (p NumberDataPoint) value() (float64, bool) {
+	switch {
+	case p.AsDouble != nil:
+		if math.IsNaN(*p.AsDouble) || math.IsInf(*p.AsDouble, 0) {
+			return 0, false
+		}
+		return *p.AsDouble, true
+	case p.AsInt != nil:
+		return float64(*p.AsInt), true
+	}
+	return 0, false
+}
+
+// labelsOf returns base extended with attributes as labels,
+// nil if there are no labels at all.
+func labelsOf(base map[string]string, attributes []KeyValue) map[string]string {
+	if len(base) == 0 && len(attributes) == 0 {
+		return nil
+	}
+
+	labels := make(map[string]string, len(base)+len(attributes))
+	maps.Copy(labels, base)
+
+	for _, attribute := range attributes {
+		if name := models.SanitizeLabelName(attribute.Key); name != "" {
+			labels[name] = attribute.Value.String()
+		}
+	}
+
+	if len(labels) == 0 {
+		return nil
+	}
+
+	return labels
+}
+
+// countDataPoints returns the number of data points of an unsupported metric.
+func countDataPoints(raw json.RawMessage) int {
+	var data struct {
+		DataPoints []json.RawMessage `json:"dataPoints"`
+	}
+
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return 0
+	}
+
+	return len(data.DataPoints)
+}
+
+// reject counts rejected data points, keeping the first reason.
+func reject(rejected *PartialSuccess, count int, format string, args ...any) {
+	if count == 0 {
+		return
+	}
+
+	rejected.RejectedDataPoints += int64(count)
+	if rejected.ErrorMessage == "" {
+		rejected.ErrorMessage = fmt.Sprintf(format, args...)
+	}
+}
@@ -0,0 +1,244 @@
+package otlp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func float64Ptr(v float64) *float64 { return &v }
+
+func decodeRequest(t *testing.T, body string) ExportMetricsServiceRequest {
+	t.Helper()
+
+	var request ExportMetricsServiceRequest
+	require.NoError(t, json.Unmarshal([]byte(body), &request))
+	return request
+}
+
+// cumulativeRequest builds an export request of a monotonic cumulative sum
+// of the http.requests series with the route label.
+func cumulativeRequest(t *testing.T, start int64, value int64, route string) ExportMetricsServiceRequest {
+	t.Helper()
+
+	body, err := json.Marshal(ExportMetricsServiceRequest{ResourceMetrics: []ResourceMetrics{{
+		ScopeMetrics: []ScopeMetrics{{Metrics: []Metric{{
+			Name: "http.requests",
+			Sum: &Sum{
+				AggregationTemporality: TemporalityCumulative,
+				IsMonotonic:            true,
+				DataPoints: []NumberDataPoint{{
+					Attributes:        []KeyValue{{Key: "route", Value: AnyValue{StringValue: &route}}},
+					StartTimeUnixNano: Int64(start),
+					AsInt:             (*Int64)(&value),
+				}},
+			},
+		}}}},
+	}}})
+	require.NoError(t, err)
+
+	return decodeRequest(t, string(body))
+}
+
+func TestTranslator_Translate(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            string
+		expected        []models.Metrics
+		expectedPartial *PartialSuccess
+	}{
+		{
+			name: "gauge with resource attributes",
+			body: `{"resourceMetrics":[{
+				"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"checkout"}}]},
+				"scopeMetrics":[{"metrics":[{
+					"name":"process.memory.usage","unit":"By",
+					"gauge":{"dataPoints":[
+						{"attributes":[{"key":"host","value":{"stringValue":"web1"}}],"timeUnixNano":"1700000000000000000","asInt":"1024"},
+						{"attributes":[{"key":"service.name","value":{"stringValue":"override"}}],"asDouble":0.5}
+					]}
+				}]}]
+			}]}`,
+			expected: []models.Metrics{
+				{
+					ID: "process.memory.usage", MType: models.Gauge, Unit: "By",
+					Labels: map[string]string{"service_name": "checkout", "host": "web1"},
+					Value:  float64Ptr(1024), Timestamp: int64Ptr(1700000000000),
+				},
+				{
+					ID: "process.memory.usage", MType: models.Gauge, Unit: "By",
+					Labels: map[string]string{"service_name": "override"},
+					Value:  float64Ptr(0.5),
+				},
+			},
+		},
+		{
+			name: "delta sum",
+			body: `{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{
+				"name":"http.requests",
+				"sum":{"aggregationTemporality":1,"isMonotonic":true,"dataPoints":[{"asDouble":3.0}]}
+			}]}]}]}`,
+			expected: []models.Metrics{
+				{ID: "http.requests", MType: models.Counter, Delta: int64Ptr(3)},
+			},
+		},
+		{
+			name: "fractional monotonic sum",
+			body: `{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{
+				"name":"http.requests",
+				"sum":{"aggregationTemporality":1,"isMonotonic":true,"dataPoints":[{"asDouble":1.5},{"asDouble":2}]}
+			}]}]}]}`,
+			expected: []models.Metrics{
+				{ID: "http.requests", MType: models.Counter, Delta: int64Ptr(2)},
+			},
+			expectedPartial: &PartialSuccess{
+				RejectedDataPoints: 1,
+				ErrorMessage:       `monotonic sum "http.requests" has a non-integer value`,
+			},
+		},
+		{
+			name: "non-monotonic sum",
+			body: `{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{
+				"name":"queue.size",
+				"sum":{"aggregationTemporality":2,"isMonotonic":false,"dataPoints":[{"asInt":"-4"}]}
+			}]}]}]}`,
+			expected: []models.Metrics{
+				{ID: "queue.size", MType: models.Gauge, Value: float64Ptr(-4)},
+			},
+		},
+		{
+			name: "unsupported and invalid data points",
+			body: `{"resourceMetrics":[{"scopeMetrics":[{"metrics":[
+				{"name":"http.duration","histogram":{"dataPoints":[{},{}]}},
+				{"name":"load","gauge":{"dataPoints":[{"asDouble":1},{}]}},
+				{"name":"http.requests","sum":{"aggregationTemporality":0,"isMonotonic":true,"dataPoints":[{"asInt":"1"}]}},
+				{"name":"http.errors","sum":{"aggregationTemporality":1,"isMonotonic":true,"dataPoints":[{"asInt":"-1"}]}},
+				{"name":"","gauge":{"dataPoints":[{"asDouble":1}]}},
+				{"name":"rpc.duration","summary":{"dataPoints":[{}]}},
+				{"name":"rpc.size","exponentialHistogram":{"dataPoints":[{}]}},
+				{"name":"empty"}
+			]}]}]}`,
+			expected: []models.Metrics{
+				{ID: "load", MType: models.Gauge, Value: float64Ptr(1)},
+			},
+			expectedPartial: &PartialSuccess{
+				RejectedDataPoints: 8,
+				ErrorMessage:       `histogram "http.duration" is not supported`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			translator := NewTranslator(time.Hour)
+
+			metrics, _, partial := translator.Translate("default", decodeRequest(t, tt.body))
+
+			assert.Equal(t, tt.expected, metrics)
+			assert.Equal(t, tt.expectedPartial, partial)
+		})
+	}
+}
+
+func TestTranslator_Translate_cumulative(t *testing.T) {
+	translator := NewTranslator(time.Hour)
+	before := translator.started - 1
+	after := translator.started + 1
+
+	export := func(tenant string, start int64, value int64, route string) []models.Metrics {
+		metrics, _, partial := translator.Translate(tenant, cumulativeRequest(t, start, value, route))
+		require.Nil(t, partial)
+		return metrics
+	}
+
+	deltaOf := func(metrics []models.Metrics) []int64 {
+		deltas := make([]int64, 0, len(metrics))
+		for _, metric := range metrics {
+			assert.Equal(t, models.Counter, metric.MType)
+			deltas = append(deltas, *metric.Delta)
+		}
+		return deltas
+	}
+
+	// Series started before the translator only set the baseline
+	assert.Empty(t, export("default", before, 100, "/a"))
+	assert.Equal(t, []int64{5}, deltaOf(export("default", before, 105, "/a")))
+	assert.Equal(t, []int64{0}, deltaOf(export("default", before, 105, "/a")))
+
+	// Counter resets restart from the reported total
+	assert.Equal(t, []int64{3}, deltaOf(export("default", before, 3, "/a")))
+	assert.Equal(t, []int64{7}, deltaOf(export("default", after, 7, "/a")))
+
+	// Series started after the translator count from zero
+	assert.Equal(t, []int64{10}, deltaOf(export("default", after, 10, "/b")))
+	assert.Equal(t, []int64{2}, deltaOf(export("default", after, 12, "/b")))
+
+	// Tenants have separate totals
+	assert.Empty(t, export("team-a", before, 100, "/a"))
+	assert.Equal(t, []int64{1}, deltaOf(export("team-a", before, 101, "/a")))
+}
+
+func TestTranslator_Rollback(t *testing.T) {
+	translator := NewTranslator(time.Hour)
+	after := translator.started + 1
+
+	// Concurrent requests reporting the same total count its increase once
+	metrics, first, _ := translator.Translate("default", cumulativeRequest(t, after, 10, "/a"))
+	assert.Equal(t, int64(10), *metrics[0].Delta)
+	metrics, second, _ := translator.Translate("default", cumulativeRequest(t, after, 10, "/a"))
+	assert.Equal(t, int64(0), *metrics[0].Delta)
+
+	// A failed request gives its increase back to the retry
+	translator.Rollback(second)
+	translator.Rollback(first)
+	metrics, _, _ = translator.Translate("default", cumulativeRequest(t, after, 10, "/a"))
+	assert.Equal(t, int64(10), *metrics[0].Delta)
+
+	// Increases of other requests stay counted
+	_, failed, _ := translator.Translate("default", cumulativeRequest(t, after, 15, "/a"))
+	metrics, _, _ = translator.Translate("default", cumulativeRequest(t, after, 16, "/a"))
+	assert.Equal(t, int64(1), *metrics[0].Delta)
+	translator.Rollback(failed)
+	metrics, _, _ = translator.Translate("default", cumulativeRequest(t, after, 16, "/a"))
+	assert.Equal(t, int64(5), *metrics[0].Delta)
+
+	// Increases before a reset are not taken back from the new series
+	_, failed, _ = translator.Translate("default", cumulativeRequest(t, after, 20, "/a"))
+	translator.Translate("default", cumulativeRequest(t, after+1, 2, "/a"))
+	translator.Rollback(failed)
+	metrics, _, _ = translator.Translate("default", cumulativeRequest(t, after+1, 3, "/a"))
+	assert.Equal(t, int64(1), *metrics[0].Delta)
+}
+
+func TestTranslator_Translate_evict(t *testing.T) {
+	translator := NewTranslator(time.Minute)
+	now := time.Unix(0, translator.started)
+	translator.now = func() time.Time { return now }
+
+	start := translator.started + 1
+	export := func(route string, value int64) []models.Metrics {
+		metrics, _, _ := translator.Translate("default", cumulativeRequest(t, start, value, route))
+		return metrics
+	}
+
+	export("/a", 10)
+	now = now.Add(30 * time.Second)
+	export("/b", 5)
+
+	now = now.Add(45 * time.Second)
+	export("/b", 6)
+
+	// /a was not reported for the TTL, /b was
+	assert.Len(t, translator.totals, 1)
+	assert.Equal(t, start, translator.started)
+
+	// An evicted series reappears as a baseline instead of being counted twice
+	assert.Empty(t, export("/a", 11))
+	assert.Equal(t, []int64{2}, []int64{*export("/a", 13)[0].Delta})
+}
@@ -0,0 +1,55 @@
+package pb
+
+import (
+	models "github.com/gabkaclassic/metrics/internal/model"
+)
+
+// ToModel converts a protobuf metric to the metric model.
+func ToModel(metric *Metric) models.Metrics {
+	return models.Metrics{
+		ID:        metric.GetId(),
+		MType:     metric.GetType(),
+		Labels:    metric.GetLabels(),
+		Delta:     metric.Delta,
+		Value:     metric.Value,
+		Increment: metric.Increment,
+		Bounds:    metric.GetBounds(),
+		Buckets:   metric.GetBuckets(),
+		Sum:       metric.Sum,
+		Count:     metric.Count,
+		Sketch:    metric.GetSketch(),
+		Members:   metric.GetMembers(),
+		Unit:      metric.GetUnit(),
+		Timestamp: metric.Timestamp,
+	}
+}
+
+// ToModels converts protobuf metrics to metric models.
+func ToModels(metrics []*Metric) []models.Metrics {
+	result := make([]models.Metrics, 0, len(metrics))
+	for _, metric := range metrics {
+		result = append(result, ToModel(metric))
+	}
+	return result
+}
+
+// FromModel converts a metric model to a protobuf metric.
+func FromModel(metric models.Metrics) *Metric {
+	return &Metric{
+		Id:        metric.ID,
+		Type:      metric.MType,
+		Labels:    metric.Labels,
+		Delta:     metric.Delta,
+		Value:     metric.Value,
+		Increment: metric.Increment,
+		Bounds:    metric.Bounds,
+		Buckets:   metric.Buckets,
+		Sum:       metric.Sum,
+		Count:     metric.Count,
+		Sketch:    metric.Sketch,
+		Members:   metric.Members,
+		Quantiles: metric.Quantiles,
+		Unit:      metric.Unit,
+		Timestamp: metric.Timestamp,
+	}
+}
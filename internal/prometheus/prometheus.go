@@ -0,0 +1,241 @@
+// Package prometheus renders metrics in the Prometheus text exposition format.
+//
+// Every metric becomes a family with # HELP and # TYPE lines followed by
+// its series:
+//   - counters are exposed as counter
+//   - gauges and set cardinality estimates are exposed as gauge
+//   - histograms are exposed as histogram with cumulative le buckets
+//   - summaries are exposed as summary with models.DefaultQuantiles
+//
+// Metric and label names are sanitized to the Prometheus charset.
+package prometheus
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+)
+
+var (
+	// invalidNameChars matches characters not allowed in metric names.
+	invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_:]`)
+
+	// invalidLabelChars matches characters not allowed in label names.
+	invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+	// helpEscaper escapes HELP text.
+	helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+	// labelValueEscaper escapes label values.
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+// family groups series of a single metric name and type.
+type family struct {
+	name   string
+	id     string
+	mtype  string
+	series []models.Metrics
+}
+
+// label is a single sanitized label pair.
+type label struct {
+	name  string
+	value string
+}
+
+// Encode writes metrics in the text exposition format.
+//
+// w: Destination of the rendered text
+// metrics: Series to render, in any order
+// help: Metric descriptions keyed by metric ID, the ID is used when missing
+//
+// Returns:
+//   - error: Write failure details
+//
+// Families are sorted by name and series by their key,
+// so the output is stable between scrapes.
+func Encode(w io.Writer, metrics []models.Metrics, help map[string]string) error {
+	buffered := bufio.NewWriter(w)
+
+	for _, f := range groupFamilies(metrics) {
+		description := help[f.id]
+		if description == "" {
+			description = f.id
+		}
+
+		buffered.WriteString("# HELP " + f.name + " " + helpEscaper.Replace(description) + "\n")
+		buffered.WriteString("# TYPE " + f.name + " " + familyType(f.mtype) + "\n")
+
+		for _, m := range f.series {
+			writeSeries(buffered, f.name, m)
+		}
+	}
+
+	return buffered.Flush()
+}
+
+// groupFamilies groups metrics by sanitized name and type.
+// Families are sorted by name, then type; series by key.
+func groupFamilies(metrics []models.Metrics) []*family {
+	index := make(map[string]*family)
+	families := make([]*family, 0)
+
+	for _, m := range metrics {
+		name := SanitizeName(m.ID)
+		key := name + " " + m.MType
+
+		f, exists := index[key]
+		if !exists {
+			f = &family{name: name, id: m.ID, mtype: m.MType}
+			index[key] = f
+			families = append(families, f)
+		}
+		f.series = append(f.series, m)
+	}
+
+	slices.SortFunc(families, func(a, b *family) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.mtype, b.mtype)
+	})
+
+	for _, f := range families {
+		slices.SortFunc(f.series, func(a, b models.Metrics) int {
+			return strings.Compare(a.Key(), b.Key())
+		})
+	}
+
+	return families
+}
+
+// familyType maps a metric type to its Prometheus type.
+func familyType(metricType string) string {
+	switch metricType {
+	case models.Counter:
+		return "counter"
+	case models.Histogram:
+		return "histogram"
+	case models.Summary:
+		return "summary"
+	default:
+		return "gauge"
+	}
+}
+
+// writeSeries writes the sample lines of a single series.
+func writeSeries(w *bufio.Writer, name string, m models.Metrics) {
+	labels := sanitizeLabels(m.Labels)
+
+	switch m.MType {
+	case models.Counter:
+		if m.Delta != nil {
+			writeSample(w, name, labels, strconv.FormatInt(*m.Delta, 10))
+		}
+	case models.Gauge:
+		if m.Value != nil {
+			writeSample(w, name, labels, formatFloat(*m.Value))
+		}
+	case models.Set:
+		writeSample(w, name, labels, strconv.FormatInt(m.Cardinality(), 10))
+	case models.Histogram:
+		snapshot := m.Snapshot()
+		var cumulative int64
+		for i, bound := range snapshot.Bounds {
+			cumulative += snapshot.Buckets[i]
+			writeSample(w, name+"_bucket", withLabel(labels, "le", formatFloat(bound)), strconv.FormatInt(cumulative, 10))
+		}
+		writeSample(w, name+"_bucket", withLabel(labels, "le", "+Inf"), strconv.FormatInt(snapshot.Count, 10))
+		writeSample(w, name+"_sum", labels, formatFloat(snapshot.Sum))
+		writeSample(w, name+"_count", labels, strconv.FormatInt(snapshot.Count, 10))
+	case models.Summary:
+		snapshot := m.SummarySnapshot()
+		for _, q := range models.DefaultQuantiles {
+			if value, exists := snapshot.Quantiles[models.QuantileKey(q)]; exists {
+				writeSample(w, name, withLabel(labels, "quantile", formatFloat(q)), formatFloat(value))
+			}
+		}
+		writeSample(w, name+"_sum", labels, formatFloat(snapshot.Sum))
+		writeSample(w, name+"_count", labels, strconv.FormatInt(snapshot.Count, 10))
+	}
+}
+
+// writeSample writes a single sample line.
+func writeSample(w *bufio.Writer, name string, labels []label, value string) {
+	w.WriteString(name)
+
+	if len(labels) > 0 {
+		w.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(l.name + `="` + labelValueEscaper.Replace(l.value) + `"`)
+		}
+		w.WriteByte('}')
+	}
+
+	w.WriteString(" " + value + "\n")
+}
+
+// sanitizeLabels returns labels with sanitized names sorted by name.
+func sanitizeLabels(labels map[string]string) []label {
+	result := make([]label, 0, len(labels))
+	for name, value := range labels {
+		result = append(result, label{name: SanitizeLabelName(name), value: value})
+	}
+
+	slices.SortFunc(result, func(a, b label) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	return result
+}
+
+// withLabel returns a copy of labels with an extra trailing label.
+func withLabel(labels []label, name string, value string) []label {
+	return append(slices.Clone(labels), label{name: name, value: value})
+}
+
+// SanitizeName converts a metric ID to a valid Prometheus metric name.
+// Invalid characters are replaced with underscores and a leading digit
+// is prefixed with an underscore.
+func SanitizeName(id string) string {
+	return sanitize(invalidNameChars.ReplaceAllString(id, "_"))
+}
+
+// SanitizeLabelName converts a label name to a valid Prometheus label name.
+// Invalid characters are replaced with underscores and a leading digit
+// is prefixed with an underscore.
+func SanitizeLabelName(name string) string {
+	return sanitize(invalidLabelChars.ReplaceAllString(name, "_"))
+}
+
+// sanitize prefixes names that are empty or start with a digit.
+func sanitize(name string) string {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return "_" + name
+	}
+	return name
+}
+
+// formatFloat renders a float sample value, including infinities and NaN.
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
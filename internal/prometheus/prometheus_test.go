@@ -0,0 +1,183 @@
+package prometheus
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func float64Ptr(v float64) *float64 { return &v }
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name     string
+		metrics  []models.Metrics
+		help     map[string]string
+		expected string
+	}{
+		{
+			name:     "no metrics",
+			metrics:  []models.Metrics{},
+			expected: "",
+		},
+		{
+			name: "counter and gauge",
+			metrics: []models.Metrics{
+				{ID: "PollCount", MType: models.Counter, Delta: int64Ptr(5)},
+				{ID: "HeapAlloc", MType: models.Gauge, Value: float64Ptr(1024.5)},
+			},
+			help: map[string]string{"HeapAlloc": "Bytes of allocated heap objects"},
+			expected: "# HELP HeapAlloc Bytes of allocated heap objects\n" +
+				"# TYPE HeapAlloc gauge\n" +
+				"HeapAlloc 1024.5\n" +
+				"# HELP PollCount PollCount\n" +
+				"# TYPE PollCount counter\n" +
+				"PollCount 5\n",
+		},
+		{
+			name: "labeled series sorted",
+			metrics: []models.Metrics{
+				{ID: "cpu", MType: models.Gauge, Value: float64Ptr(2), Labels: map[string]string{"host": "web-2"}},
+				{ID: "cpu", MType: models.Gauge, Value: float64Ptr(1), Labels: map[string]string{"host": "web-1", "core": "0"}},
+				{ID: "cpu", MType: models.Gauge, Value: float64Ptr(3)},
+			},
+			expected: "# HELP cpu cpu\n" +
+				"# TYPE cpu gauge\n" +
+				"cpu 3\n" +
+				"cpu{core=\"0\",host=\"web-1\"} 1\n" +
+				"cpu{host=\"web-2\"} 2\n",
+		},
+		{
+			name: "names sanitized and values escaped",
+			metrics: []models.Metrics{
+				{ID: "1http.requests-total", MType: models.Counter, Delta: int64Ptr(1), Labels: map[string]string{"path": "/a\"b\\c\nd"}},
+			},
+			help: map[string]string{"1http.requests-total": "Requests\\served\nso far"},
+			expected: "# HELP _1http_requests_total Requests\\\\served\\nso far\n" +
+				"# TYPE _1http_requests_total counter\n" +
+				"_1http_requests_total{path=\"/a\\\"b\\\\c\\nd\"} 1\n",
+		},
+		{
+			name: "special float values",
+			metrics: []models.Metrics{
+				{ID: "a", MType: models.Gauge, Value: float64Ptr(math.Inf(1))},
+				{ID: "b", MType: models.Gauge, Value: float64Ptr(math.Inf(-1))},
+				{ID: "c", MType: models.Gauge, Value: float64Ptr(math.NaN())},
+			},
+			expected: "# HELP a a\n# TYPE a gauge\na +Inf\n" +
+				"# HELP b b\n# TYPE b gauge\nb -Inf\n" +
+				"# HELP c c\n# TYPE c gauge\nc NaN\n",
+		},
+		{
+			name: "histogram buckets are cumulative",
+			metrics: []models.Metrics{
+				{
+					ID:      "latency",
+					MType:   models.Histogram,
+					Bounds:  []float64{0.1, 1},
+					Buckets: []int64{2, 3, 1},
+					Sum:     float64Ptr(4.5),
+					Count:   int64Ptr(6),
+				},
+			},
+			expected: "# HELP latency latency\n" +
+				"# TYPE latency histogram\n" +
+				"latency_bucket{le=\"0.1\"} 2\n" +
+				"latency_bucket{le=\"1\"} 5\n" +
+				"latency_bucket{le=\"+Inf\"} 6\n" +
+				"latency_sum 4.5\n" +
+				"latency_count 6\n",
+		},
+		{
+			name: "set exposed as gauge",
+			metrics: []models.Metrics{
+				models.ObserveSet("users", "alice", "bob"),
+			},
+			expected: "# HELP users users\n" +
+				"# TYPE users gauge\n" +
+				"users 2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := Encode(&buf, tt.metrics, tt.help)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestEncode_summary(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := Encode(&buf, []models.Metrics{models.ObserveSummary("rtt", 2)}, nil)
+
+	require.NoError(t, err)
+	body := buf.String()
+	assert.Contains(t, body, "# TYPE rtt summary\n")
+	for _, q := range []string{"0.5", "0.9", "0.95", "0.99"} {
+		assert.Contains(t, body, "rtt{quantile=\""+q+"\"} ")
+	}
+	assert.Contains(t, body, "rtt_sum 2\n")
+	assert.Contains(t, body, "rtt_count 1\n")
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("write error") }
+
+func TestEncode_writeError(t *testing.T) {
+	err := Encode(failingWriter{}, []models.Metrics{
+		{ID: "PollCount", MType: models.Counter, Delta: int64Ptr(5)},
+	}, nil)
+
+	assert.EqualError(t, err, "write error")
+}
+
+func TestSanitizeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "valid name", input: "http_requests:total", expected: "http_requests:total"},
+		{name: "invalid characters", input: "cpu.user-time", expected: "cpu_user_time"},
+		{name: "leading digit", input: "9lives", expected: "_9lives"},
+		{name: "empty name", input: "", expected: "_"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SanitizeName(tt.input))
+		})
+	}
+}
+
+func TestSanitizeLabelName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "valid name", input: "host_name", expected: "host_name"},
+		{name: "colon replaced", input: "k8s:pod", expected: "k8s_pod"},
+		{name: "leading digit", input: "0zone", expected: "_0zone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SanitizeLabelName(tt.input))
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/storage"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+)
+
+// AlertRuleRepository defines the interface for alert rule operations.
+// Rules are partitioned by the tenant of the request context.
+type AlertRuleRepository interface {
+	// Put creates or replaces a rule of the request tenant.
+	Put(context.Context, models.AlertRule) error
+
+	// Get retrieves a rule of the request tenant by its ID.
+	// Returns nil without error if the rule doesn't exist.
+	Get(context.Context, string) (*models.AlertRule, error)
+
+	// GetAll returns rules of all tenants with Tenant set.
+	GetAll(context.Context) ([]models.AlertRule, error)
+
+	// Delete removes a rule of the request tenant by its ID.
+	// Reports whether the rule existed.
+	Delete(context.Context, string) (bool, error)
+}
+
+// memoryAlertRuleRepository implements AlertRuleRepository using in-memory storage.
+// Provides thread-safe operations through read-write mutex.
+type memoryAlertRuleRepository struct {
+	storage *storage.MemStorage
+	mutex   *sync.RWMutex
+}
+
+// NewMemoryAlertRuleRepository creates a new in-memory alert rule repository.
+//
+// storage: MemStorage instance for data persistence
+// mutex: Read-write mutex for thread safety (can be shared)
+//
+// Returns:
+//   - AlertRuleRepository: Ready-to-use repository instance
+//   - error: If storage is nil
+func NewMemoryAlertRuleRepository(storage *storage.MemStorage, mutex *sync.RWMutex) (AlertRuleRepository, error) {
+	if storage == nil {
+		return nil, errors.New("create new alert rule repository failed: storage is nil")
+	}
+
+	return &memoryAlertRuleRepository{
+		storage: storage,
+		mutex:   mutex,
+	}, nil
+}
+
+// Put creates or replaces a rule of the request tenant.
+func (repository *memoryAlertRuleRepository) Put(ctx context.Context, rule models.AlertRule) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	if repository.storage.AlertRules == nil {
+		repository.storage.AlertRules = make(map[string]map[string]models.AlertRule)
+	}
+
+	tenant := middleware.TenantFromCtx(ctx)
+	rules, exists := repository.storage.AlertRules[tenant]
+	if !exists {
+		rules = make(map[string]models.AlertRule)
+		repository.storage.AlertRules[tenant] = rules
+	}
+
+	rule.Tenant = tenant
+	rules[rule.ID] = rule
+	return nil
+}
+
+// Get retrieves a rule of the request tenant by its ID.
+// Returns nil without error if the rule doesn't exist.
+func (repository *memoryAlertRuleRepository) Get(ctx context.Context, id string) (*models.AlertRule, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	rule, exists := repository.storage.AlertRules[middleware.TenantFromCtx(ctx)][id]
+	if !exists {
+		return nil, nil
+	}
+
+	return &rule, nil
+}
+
+// GetAll returns rules of all tenants with Tenant set.
+func (repository *memoryAlertRuleRepository) GetAll(ctx context.Context) ([]models.AlertRule, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	rules := make([]models.AlertRule, 0)
+	for tenant, tenantRules := range repository.storage.AlertRules {
+		for _, rule := range tenantRules {
+			rule.Tenant = tenant
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, nil
+}
+
+// Delete removes a rule of the request tenant by its ID.
+// Reports whether the rule existed.
+func (repository *memoryAlertRuleRepository) Delete(ctx context.Context, id string) (bool, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	rules := repository.storage.AlertRules[middleware.TenantFromCtx(ctx)]
+	if _, exists := rules[id]; !exists {
+		return false, nil
+	}
+
+	delete(rules, id)
+	return true, nil
+}
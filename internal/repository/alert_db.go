@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/storage"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+)
+
+// alertRuleColumns lists alert_rule columns in scanAlertRule order.
+const alertRuleColumns = `tenant, id, metric, type, labels, op, threshold, "for", webhooks`
+
+// dbAlertRuleRepository implements AlertRuleRepository using the alert_rule table.
+type dbAlertRuleRepository struct {
+	storage storage.DB
+}
+
+// NewDBAlertRuleRepository creates a new PostgreSQL-based alert rule repository.
+//
+// storage: Established SQL database connection (typically PostgreSQL)
+//
+// Returns:
+//   - AlertRuleRepository: Ready-to-use repository instance
+//   - error: If storage connection is nil
+//
+// The repository automatically retries operations on transient database errors.
+func NewDBAlertRuleRepository(s storage.DB) (AlertRuleRepository, error) {
+	if s == nil {
+		return nil, errors.New("create new alert rule repository failed: storage is nil")
+	}
+
+	return &dbAlertRuleRepository{
+		storage: s,
+	}, nil
+}
+
+// Put creates or replaces a rule of the request tenant.
+func (repository *dbAlertRuleRepository) Put(ctx context.Context, rule models.AlertRule) error {
+	webhooks := rule.Webhooks
+	if webhooks == nil {
+		webhooks = []string{}
+	}
+
+	return executeWithRetry(func() error {
+		_, err := repository.storage.Exec(
+			ctx,
+			`INSERT INTO alert_rule (`+alertRuleColumns+`)
+			VALUES ($1, $2, $3, $4, $5::jsonb, $6, $7, $8, $9)
+			ON CONFLICT (tenant, id)
+			DO UPDATE SET metric = EXCLUDED.metric, type = EXCLUDED.type, labels = EXCLUDED.labels, op = EXCLUDED.op,
+				threshold = EXCLUDED.threshold, "for" = EXCLUDED."for", webhooks = EXCLUDED.webhooks;`,
+			middleware.TenantFromCtx(ctx), rule.ID, rule.Metric, rule.MType, encodeLabels(rule.Labels),
+			rule.Op, rule.Threshold, rule.For, webhooks,
+		)
+		return err
+	})
+}
+
+// Get retrieves a rule of the request tenant by its ID.
+// Returns nil without error if the rule doesn't exist.
+func (repository *dbAlertRuleRepository) Get(ctx context.Context, id string) (*models.AlertRule, error) {
+	var rule *models.AlertRule
+	err := executeWithRetry(func() error {
+		current, err := scanAlertRule(repository.storage.QueryRow(
+			ctx,
+			"SELECT "+alertRuleColumns+" FROM alert_rule WHERE tenant = $1 AND id = $2",
+			middleware.TenantFromCtx(ctx), id,
+		))
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		rule = &current
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// GetAll returns rules of all tenants with Tenant set.
+func (repository *dbAlertRuleRepository) GetAll(ctx context.Context) ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+	err := executeWithRetry(func() error {
+		rows, err := repository.storage.Query(ctx, "SELECT "+alertRuleColumns+" FROM alert_rule;")
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		currentRules := make([]models.AlertRule, 0)
+		for rows.Next() {
+			rule, err := scanAlertRule(rows)
+			if err != nil {
+				return err
+			}
+			currentRules = append(currentRules, rule)
+		}
+
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		rules = currentRules
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Delete removes a rule of the request tenant by its ID.
+// Reports whether the rule existed.
+func (repository *dbAlertRuleRepository) Delete(ctx context.Context, id string) (bool, error) {
+	var deleted bool
+	err := executeWithRetry(func() error {
+		tag, err := repository.storage.Exec(
+			ctx,
+			"DELETE FROM alert_rule WHERE tenant = $1 AND id = $2;",
+			middleware.TenantFromCtx(ctx), id,
+		)
+		if err != nil {
+			return err
+		}
+
+		deleted = tag.RowsAffected() > 0
+		return nil
+	})
+
+	return deleted, err
+}
+
+// scanAlertRule reads an alert rule selected with alertRuleColumns.
+func scanAlertRule(row pgx.Row) (models.AlertRule, error) {
+	var rule models.AlertRule
+
+	if err := row.Scan(
+		&rule.Tenant, &rule.ID, &rule.Metric, &rule.MType, &rule.Labels,
+		&rule.Op, &rule.Threshold, &rule.For, &rule.Webhooks,
+	); err != nil {
+		return models.AlertRule{}, err
+	}
+
+	if len(rule.Labels) == 0 {
+		rule.Labels = nil
+	}
+	if len(rule.Webhooks) == 0 {
+		rule.Webhooks = nil
+	}
+
+	return rule, nil
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v4"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/storage"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var alertRuleColumnNames = []string{"tenant", "id", "metric", "type", "labels", "op", "threshold", "for", "webhooks"}
+
+func TestNewDBAlertRuleRepository(t *testing.T) {
+	tests := []struct {
+		name        string
+		storage     storage.DB
+		expectError bool
+	}{
+		{
+			name:        "valid db connection",
+			storage:     storage.NewMockDB(t),
+			expectError: false,
+		},
+		{
+			name:        "nil storage",
+			storage:     nil,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := NewDBAlertRuleRepository(tt.storage)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, repo)
+				assert.Contains(t, err.Error(), "storage is nil")
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, repo)
+			}
+		})
+	}
+}
+
+func TestDBAlertRuleRepository_Put(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBAlertRuleRepository(mock)
+	require.NoError(t, err)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO alert_rule ("+alertRuleColumns+")")).
+		WithArgs("team-a", "high-heap", "HeapAlloc", models.Gauge, `{"host":"a"}`, models.OpGreater, 100.0, int64(60), []string{}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	err = repo.Put(middleware.WithTenant(t.Context(), "team-a"), models.AlertRule{
+		ID:        "high-heap",
+		Metric:    "HeapAlloc",
+		MType:     models.Gauge,
+		Labels:    map[string]string{"host": "a"},
+		Op:        models.OpGreater,
+		Threshold: 100,
+		For:       60,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDBAlertRuleRepository_Get(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBAlertRuleRepository(mock)
+	require.NoError(t, err)
+
+	query := regexp.QuoteMeta("SELECT " + alertRuleColumns + " FROM alert_rule WHERE tenant = $1 AND id = $2")
+
+	tests := []struct {
+		name        string
+		mockQuery   func()
+		expectRule  *models.AlertRule
+		expectError bool
+	}{
+		{
+			name: "found",
+			mockQuery: func() {
+				mock.ExpectQuery(query).
+					WithArgs(middleware.DefaultTenant, "high-heap").
+					WillReturnRows(pgxmock.NewRows(alertRuleColumnNames).AddRow(
+						middleware.DefaultTenant, "high-heap", "HeapAlloc", "", map[string]string{},
+						models.OpGreater, 100.0, int64(0), []string{"http://hook"},
+					))
+			},
+			expectRule: &models.AlertRule{
+				ID:        "high-heap",
+				Tenant:    middleware.DefaultTenant,
+				Metric:    "HeapAlloc",
+				Op:        models.OpGreater,
+				Threshold: 100,
+				Webhooks:  []string{"http://hook"},
+			},
+		},
+		{
+			name: "not found",
+			mockQuery: func() {
+				mock.ExpectQuery(query).
+					WithArgs(middleware.DefaultTenant, "high-heap").
+					WillReturnRows(pgxmock.NewRows(alertRuleColumnNames))
+			},
+			expectRule: nil,
+		},
+		{
+			name: "query error",
+			mockQuery: func() {
+				mock.ExpectQuery(query).
+					WithArgs(middleware.DefaultTenant, "high-heap").
+					WillReturnError(errors.New("db error"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockQuery()
+
+			rule, err := repo.Get(t.Context(), "high-heap")
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, rule)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectRule, rule)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDBAlertRuleRepository_GetAll(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBAlertRuleRepository(mock)
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT " + alertRuleColumns + " FROM alert_rule;")).
+		WillReturnRows(pgxmock.NewRows(alertRuleColumnNames).
+			AddRow("team-a", "a", "HeapAlloc", "", map[string]string{}, models.OpGreater, 1.0, int64(0), []string{}).
+			AddRow("team-b", "b", "Poll*", models.Counter, map[string]string{"host": "a"}, models.OpUnchanged, 0.0, int64(30), []string{}))
+
+	rules, err := repo.GetAll(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []models.AlertRule{
+		{ID: "a", Tenant: "team-a", Metric: "HeapAlloc", Op: models.OpGreater, Threshold: 1},
+		{ID: "b", Tenant: "team-b", Metric: "Poll*", MType: models.Counter, Labels: map[string]string{"host": "a"}, Op: models.OpUnchanged, For: 30},
+	}, rules)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDBAlertRuleRepository_Delete(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBAlertRuleRepository(mock)
+	require.NoError(t, err)
+
+	query := regexp.QuoteMeta("DELETE FROM alert_rule WHERE tenant = $1 AND id = $2;")
+
+	mock.ExpectExec(query).
+		WithArgs(middleware.DefaultTenant, "a").
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectExec(query).
+		WithArgs(middleware.DefaultTenant, "b").
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	deleted, err := repo.Delete(t.Context(), "a")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = repo.Delete(t.Context(), "b")
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
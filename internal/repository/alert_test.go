@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"sync"
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/storage"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMemoryAlertRuleRepository(t *testing.T) {
+	tests := []struct {
+		name        string
+		storage     *storage.MemStorage
+		expectError bool
+	}{
+		{
+			name:        "valid storage",
+			storage:     storage.NewMemStorage(),
+			expectError: false,
+		},
+		{
+			name:        "nil storage",
+			storage:     nil,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := NewMemoryAlertRuleRepository(tt.storage, &sync.RWMutex{})
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, repo)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, repo)
+			}
+		})
+	}
+}
+
+func TestMemoryAlertRuleRepository_PutGetDelete(t *testing.T) {
+	repo, err := NewMemoryAlertRuleRepository(storage.NewMemStorage(), &sync.RWMutex{})
+	require.NoError(t, err)
+
+	rule, err := repo.Get(t.Context(), "high-heap")
+	assert.NoError(t, err)
+	assert.Nil(t, rule)
+
+	stored := models.AlertRule{ID: "high-heap", Metric: "HeapAlloc", Op: models.OpGreater, Threshold: 100}
+	require.NoError(t, repo.Put(t.Context(), stored))
+
+	rule, err = repo.Get(t.Context(), "high-heap")
+	assert.NoError(t, err)
+	stored.Tenant = middleware.DefaultTenant
+	assert.Equal(t, &stored, rule)
+
+	replaced := models.AlertRule{ID: "high-heap", Metric: "HeapAlloc", Op: models.OpGreater, Threshold: 200}
+	require.NoError(t, repo.Put(t.Context(), replaced))
+
+	rule, err = repo.Get(t.Context(), "high-heap")
+	assert.NoError(t, err)
+	assert.Equal(t, 200.0, rule.Threshold)
+
+	deleted, err := repo.Delete(t.Context(), "high-heap")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = repo.Delete(t.Context(), "high-heap")
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+}
+
+func TestMemoryAlertRuleRepository_Tenants(t *testing.T) {
+	repo, err := NewMemoryAlertRuleRepository(storage.NewMemStorage(), &sync.RWMutex{})
+	require.NoError(t, err)
+
+	teamA := middleware.WithTenant(t.Context(), "team-a")
+	teamB := middleware.WithTenant(t.Context(), "team-b")
+
+	require.NoError(t, repo.Put(teamA, models.AlertRule{ID: "rule", Metric: "a", Op: models.OpGreater}))
+	require.NoError(t, repo.Put(teamB, models.AlertRule{ID: "rule", Metric: "b", Op: models.OpGreater}))
+
+	rule, err := repo.Get(teamA, "rule")
+	require.NoError(t, err)
+	assert.Equal(t, "a", rule.Metric)
+
+	deleted, err := repo.Delete(teamB, "rule")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	rules, err := repo.GetAll(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []models.AlertRule{
+		{ID: "rule", Tenant: "team-a", Metric: "a", Op: models.OpGreater},
+	}, rules)
+}
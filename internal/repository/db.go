@@ -3,16 +3,23 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	models "github.com/gabkaclassic/metrics/internal/model"
 	"github.com/gabkaclassic/metrics/internal/storage"
 	"github.com/gabkaclassic/metrics/pkg/metric"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
 )
 
 const (
@@ -22,6 +29,9 @@ const (
 	// retryDelay defines the initial delay between retry attempts.
 	// Uses exponential backoff: delay doubles after each retry.
 	retryDelay time.Duration = 1 * time.Second
+
+	// metricColumns lists the metric table columns in the order expected by scanMetric.
+	metricColumns = "tenant, id, type, labels, delta, value, bounds, buckets, sum, count, sketch"
 )
 
 // dbMetricsRepository implements MetricsRepository using PostgreSQL database.
@@ -50,31 +60,21 @@ func NewDBMetricsRepository(s storage.DB) (MetricsRepository, error) {
 	}, nil
 }
 
-// GetAllMetrics retrieves all metrics from the database.
-// Returns metrics in their complete structure including type and values.
+// GetAllMetrics retrieves metrics of all tenants from the database.
+// Returns metrics in their complete structure including type, values and tenant.
 func (repository *dbMetricsRepository) GetAllMetrics(ctx context.Context) ([]models.Metrics, error) {
 	metrics := make([]models.Metrics, 0)
 	err := repository.executeWithRetry(func() error {
-		rows, err := repository.storage.Query(ctx, "SELECT id, type, delta, value FROM metric;")
+		rows, err := repository.storage.Query(ctx, "SELECT "+metricColumns+" FROM metric;")
 		if err != nil {
 			return err
 		}
 		defer rows.Close()
 		for rows.Next() {
-			var m models.Metrics
-			var delta pgtype.Int8
-			var value pgtype.Float8
-			if err = rows.Scan(&m.ID, &m.MType, &delta, &value); err != nil {
+			m, err := scanMetric(rows)
+			if err != nil {
 				return err
 			}
-			switch m.MType {
-			case string(metric.CounterType):
-				m.Delta = &delta.Int64
-			case string(metric.GaugeType):
-				m.Value = &value.Float64
-			default:
-				return fmt.Errorf("invalid metric type: %s", m.MType)
-			}
 			metrics = append(metrics, m)
 		}
 
@@ -91,13 +91,16 @@ func (repository *dbMetricsRepository) GetAllMetrics(ctx context.Context) ([]mod
 	return metrics, nil
 }
 
-// GetAll returns all metrics as a map of metric ID to value.
-// Counter metrics are returned as int64, gauge metrics as float64.
+// GetAll returns all metrics as a map of series key to value.
+// Counter metrics are returned as int64, gauge metrics as float64,
+// histogram metrics as models.HistogramSnapshot,
+// summary metrics as models.SummarySnapshot,
+// set metrics as int64 cardinality estimates.
 // Performs a single database query with automatic retry on failure.
 func (repository *dbMetricsRepository) GetAll(ctx context.Context) (map[string]any, error) {
 	var metrics map[string]any
 	err := repository.executeWithRetry(func() error {
-		rows, err := repository.storage.Query(ctx, "SELECT id, type, delta, value FROM metric;")
+		rows, err := repository.storage.Query(ctx, "SELECT "+metricColumns+" FROM metric WHERE tenant = $1;", middleware.TenantFromCtx(ctx))
 		if err != nil {
 			return err
 		}
@@ -105,20 +108,21 @@ func (repository *dbMetricsRepository) GetAll(ctx context.Context) (map[string]a
 
 		currentMetrics := make(map[string]any)
 		for rows.Next() {
-			var m models.Metrics
-			var delta pgtype.Int8
-			var value pgtype.Float8
-
-			if err = rows.Scan(&m.ID, &m.MType, &delta, &value); err != nil {
+			m, err := scanMetric(rows)
+			if err != nil {
 				return err
 			}
 			switch m.MType {
 			case string(metric.CounterType):
-				currentMetrics[m.ID] = delta.Int64
+				currentMetrics[m.Key()] = *m.Delta
 			case string(metric.GaugeType):
-				currentMetrics[m.ID] = value.Float64
-			default:
-				return fmt.Errorf("invalid metric type: %s", m.MType)
+				currentMetrics[m.Key()] = *m.Value
+			case string(metric.HistogramType):
+				currentMetrics[m.Key()] = m.Snapshot()
+			case string(metric.SummaryType):
+				currentMetrics[m.Key()] = m.SummarySnapshot()
+			case string(metric.SetType):
+				currentMetrics[m.Key()] = m.Cardinality()
 			}
 		}
 
@@ -136,35 +140,23 @@ func (repository *dbMetricsRepository) GetAll(ctx context.Context) (map[string]a
 	return metrics, nil
 }
 
-// Get retrieves a single metric by its ID from the database.
+// Get retrieves a single metric series by its ID and labels from the database.
 // Returns sql.ErrNoRows wrapped in a descriptive error if metric not found.
-func (repository *dbMetricsRepository) Get(ctx context.Context, metricID string) (*models.Metrics, error) {
+func (repository *dbMetricsRepository) Get(ctx context.Context, metricID string, labels map[string]string) (*models.Metrics, error) {
 	var result models.Metrics
 	err := repository.executeWithRetry(func() error {
-		m := models.Metrics{}
-		var delta pgtype.Int8
-		var value pgtype.Float8
-		err := repository.storage.QueryRow(
+		m, err := scanMetric(repository.storage.QueryRow(
 			ctx,
-			"SELECT id, type, delta, value FROM metric WHERE id = $1",
-			metricID,
-		).
-			Scan(&m.ID, &m.MType, &delta, &value)
+			"SELECT "+metricColumns+" FROM metric WHERE tenant = $1 AND id = $2 AND labels = $3::jsonb",
+			middleware.TenantFromCtx(ctx), metricID, encodeLabels(labels),
+		))
 
 		if err != nil {
 			if err == sql.ErrNoRows {
-				return fmt.Errorf("metric %s not found", metricID)
+				return fmt.Errorf("metric %s not found", models.SeriesKey(metricID, labels))
 			}
 			return err
 		}
-		switch m.MType {
-		case string(metric.CounterType):
-			m.Delta = &delta.Int64
-		case string(metric.GaugeType):
-			m.Value = &value.Float64
-		default:
-			return fmt.Errorf("invalid metric type: %s", m.MType)
-		}
 		result = m
 		return nil
 	})
@@ -175,8 +167,212 @@ func (repository *dbMetricsRepository) Get(ctx context.Context, metricID string)
 	return &result, nil
 }
 
+// List retrieves series of the context tenant selected by the query.
+// Filters, ordering, the page position and the limit are applied in SQL,
+// labels are ordered by the jsonb ordering of the database.
+// The regular expression is evaluated in Go with RE2 semantics instead:
+// rows are streamed in order and read until the limit is reached.
+func (repository *dbMetricsRepository) List(ctx context.Context, query models.ListQuery) ([]models.Metrics, error) {
+	statement, args, err := listStatement(middleware.TenantFromCtx(ctx), query)
+	if err != nil {
+		return nil, err
+	}
+
+	var re *regexp.Regexp
+	if query.Regex != "" {
+		if re, err = regexp.Compile(query.Regex); err != nil {
+			return nil, err
+		}
+	}
+
+	var metrics []models.Metrics
+	err = repository.executeWithRetry(func() error {
+		rows, err := repository.storage.Query(ctx, statement, args...)
+		if err != nil {
+			return err
+		}
+
+		if re == nil {
+			metrics, err = collectMetrics(rows)
+			return err
+		}
+
+		currentMetrics, err := collectMatching(rows, re, query.Limit)
+		if err != nil {
+			return err
+		}
+
+		metrics = currentMetrics
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// listStatement builds the SELECT statement and its arguments for a list query.
+// Glob patterns are translated into regular expressions matched with the ~ operator.
+// The query regular expression is left to the caller, so the limit
+// is only applied in SQL if the query has no regular expression.
+func listStatement(tenant string, query models.ListQuery) (string, []any, error) {
+	conditions := []string{"tenant = $1"}
+	args := []any{tenant}
+
+	where := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if query.MType != "" {
+		where("type = $%d", query.MType)
+	}
+	if query.Prefix != "" {
+		where("starts_with(id, $%d)", query.Prefix)
+	}
+	if query.Pattern != "" {
+		pattern, err := models.GlobRegex(query.Pattern)
+		if err != nil {
+			return "", nil, err
+		}
+		where("id ~ $%d", pattern)
+	}
+	columns := []string{"id", "labels"}
+	if query.SortBy == models.SortByType {
+		columns = []string{"type", "id", "labels"}
+	}
+
+	direction, comparison := "", ">"
+	if query.Descending {
+		direction, comparison = " DESC", "<"
+	}
+
+	if query.After != nil {
+		placeholders := make([]string, 0, len(columns))
+		if query.SortBy == models.SortByType {
+			args = append(args, query.After.MType)
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		}
+		args = append(args, query.After.ID, encodeLabels(query.After.Labels))
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)-1), fmt.Sprintf("$%d::jsonb", len(args)))
+
+		conditions = append(conditions, fmt.Sprintf(
+			"(%s) %s (%s)",
+			strings.Join(columns, ", "), comparison, strings.Join(placeholders, ", "),
+		))
+	}
+
+	order := make([]string, len(columns))
+	for i, column := range columns {
+		order[i] = column + direction
+	}
+
+	statement := "SELECT " + metricColumns + " FROM metric WHERE " + strings.Join(conditions, " AND ") +
+		" ORDER BY " + strings.Join(order, ", ")
+
+	if query.Limit > 0 && query.Regex == "" {
+		args = append(args, query.Limit)
+		statement += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	return statement + ";", args, nil
+}
+
+// GetMany retrieves the referenced series of the context tenant with a single query.
+// References are passed as arrays and joined against the metric table.
+func (repository *dbMetricsRepository) GetMany(ctx context.Context, refs []models.SeriesRef) ([]models.Metrics, error) {
+	ids := make([]string, len(refs))
+	labels := make([]string, len(refs))
+	types := make([]string, len(refs))
+	for i, ref := range refs {
+		ids[i] = ref.ID
+		labels[i] = encodeLabels(ref.Labels)
+		types[i] = ref.MType
+	}
+
+	var metrics []models.Metrics
+	err := repository.executeWithRetry(func() error {
+		rows, err := repository.storage.Query(
+			ctx,
+			`SELECT `+metricColumns+` FROM metric
+			WHERE tenant = $1 AND (id, labels, type) IN (
+				SELECT unnest($2::text[]), unnest($3::text[])::jsonb, unnest($4::text[])
+			);`,
+			middleware.TenantFromCtx(ctx), ids, labels, types,
+		)
+		if err != nil {
+			return err
+		}
+
+		currentMetrics, err := collectMetrics(rows)
+		if err != nil {
+			return err
+		}
+
+		metrics = currentMetrics
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// GetTypes returns the stored types of the referenced series
+// of the context tenant with a single query.
+func (repository *dbMetricsRepository) GetTypes(ctx context.Context, refs []models.SeriesRef) (map[string]string, error) {
+	ids := make([]string, len(refs))
+	labels := make([]string, len(refs))
+	for i, ref := range refs {
+		ids[i] = ref.ID
+		labels[i] = encodeLabels(ref.Labels)
+	}
+
+	var types map[string]string
+	err := repository.executeWithRetry(func() error {
+		rows, err := repository.storage.Query(
+			ctx,
+			`SELECT id, labels, type FROM metric
+			WHERE tenant = $1 AND (id, labels) IN (
+				SELECT unnest($2::text[]), unnest($3::text[])::jsonb
+			);`,
+			middleware.TenantFromCtx(ctx), ids, labels,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		currentTypes := make(map[string]string, len(refs))
+		for rows.Next() {
+			var id string
+			var labels map[string]string
+			var metricType string
+			if err := rows.Scan(&id, &labels, &metricType); err != nil {
+				return err
+			}
+			currentTypes[models.SeriesKey(id, labels)] = metricType
+		}
+
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		types = currentTypes
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return types, nil
+}
+
 // Add increments a counter metric in the database.
 // Uses UPSERT pattern: inserts new counter or adds delta to existing one.
+// Rows of another type are left unchanged and reported as ErrTypeMismatch.
 // Executes within a transaction with automatic rollback on error.
 func (repository *dbMetricsRepository) Add(ctx context.Context, metric models.Metrics) error {
 	return repository.executeWithRetry(func() error {
@@ -186,18 +382,26 @@ func (repository *dbMetricsRepository) Add(ctx context.Context, metric models.Me
 		}
 		defer tx.Rollback(ctx)
 
-		_, err = tx.Exec(
+		result, err := tx.Exec(
 			ctx,
-			`INSERT INTO metric (id, type, delta)
-            VALUES ($1, 'counter', $2)
-            ON CONFLICT (id)
-            DO UPDATE SET delta = metric.delta + EXCLUDED.delta;`,
-			metric.ID, metric.Delta,
+			`INSERT INTO metric (tenant, id, labels, type, delta)
+            VALUES ($1, $2, $3::jsonb, 'counter', $4)
+            ON CONFLICT (tenant, id, labels)
+            DO UPDATE SET delta = metric.delta + EXCLUDED.delta, updated_at = now()
+            WHERE metric.type = EXCLUDED.type;`,
+			middleware.TenantFromCtx(ctx), metric.ID, encodeLabels(metric.Labels), metric.Delta,
 		)
 
 		if err != nil {
 			return err
 		}
+		if err = checkUpserted(result, 1); err != nil {
+			return err
+		}
+
+		if err = recordHistory(ctx, tx, []models.Metrics{metric}); err != nil {
+			return err
+		}
 
 		return tx.Commit(ctx)
 	})
@@ -215,27 +419,39 @@ func (repository *dbMetricsRepository) AddAll(ctx context.Context, metrics []mod
 		defer tx.Rollback(ctx)
 
 		ids := make([]string, len(metrics))
+		labels := make([]string, len(metrics))
 		deltas := make([]int64, len(metrics))
 
 		for i, metric := range metrics {
 			ids[i] = metric.ID
+			labels[i] = encodeLabels(metric.Labels)
 			deltas[i] = *metric.Delta
 		}
 
-		_, err = tx.Exec(
+		result, err := tx.Exec(
 			ctx,
 			`
-			INSERT INTO metric (id, type, delta)
-			SELECT unnest($1::text[]), 'counter', unnest($2::bigint[])
-			ON CONFLICT (id) DO UPDATE
-			SET delta = metric.delta + EXCLUDED.delta
+			INSERT INTO metric (tenant, id, labels, type, delta)
+			SELECT $1, unnest($2::text[]), unnest($3::text[])::jsonb, 'counter', unnest($4::bigint[])
+			ON CONFLICT (tenant, id, labels) DO UPDATE
+			SET delta = metric.delta + EXCLUDED.delta, updated_at = now()
+			WHERE metric.type = EXCLUDED.type
 			`,
+			middleware.TenantFromCtx(ctx),
 			ids,
+			labels,
 			deltas,
 		)
 		if err != nil {
 			return err
 		}
+		if err = checkUpserted(result, len(metrics)); err != nil {
+			return err
+		}
+
+		if err = recordHistory(ctx, tx, metrics); err != nil {
+			return err
+		}
 
 		return tx.Commit(ctx)
 	})
@@ -253,18 +469,26 @@ func (repository *dbMetricsRepository) ResetOne(ctx context.Context, metric mode
 
 		defer tx.Rollback(ctx)
 
-		_, err = tx.Exec(
+		result, err := tx.Exec(
 			ctx,
-			`INSERT INTO metric (id, type, value)
-			VALUES ($1, 'gauge', $2)
-			ON CONFLICT (id)
-			DO UPDATE SET value = EXCLUDED.value;`,
-			metric.ID, metric.Value,
+			`INSERT INTO metric (tenant, id, labels, type, value)
+			VALUES ($1, $2, $3::jsonb, 'gauge', $4)
+			ON CONFLICT (tenant, id, labels)
+			DO UPDATE SET value = EXCLUDED.value, updated_at = now()
+			WHERE metric.type = EXCLUDED.type;`,
+			middleware.TenantFromCtx(ctx), metric.ID, encodeLabels(metric.Labels), metric.Value,
 		)
 
 		if err != nil {
 			return err
 		}
+		if err = checkUpserted(result, 1); err != nil {
+			return err
+		}
+
+		if err = recordHistory(ctx, tx, []models.Metrics{metric}); err != nil {
+			return err
+		}
 
 		return tx.Commit(ctx)
 	})
@@ -283,30 +507,538 @@ func (repository *dbMetricsRepository) ResetAll(ctx context.Context, metrics []m
 		defer tx.Rollback(ctx)
 
 		ids := make([]string, len(metrics))
+		labels := make([]string, len(metrics))
 		values := make([]float64, len(metrics))
 
 		for i, metric := range metrics {
 			ids[i] = metric.ID
+			labels[i] = encodeLabels(metric.Labels)
 			values[i] = *metric.Value
 		}
 
-		_, err = tx.Exec(
+		result, err := tx.Exec(
 			ctx,
 			`
-			INSERT INTO metric (id, type, value)
-			SELECT unnest($1::text[]), 'gauge', unnest($2::float8[])
-			ON CONFLICT (id) DO UPDATE 
-			SET value = EXCLUDED.value;
-		;`, ids, values)
+			INSERT INTO metric (tenant, id, labels, type, value)
+			SELECT $1, unnest($2::text[]), unnest($3::text[])::jsonb, 'gauge', unnest($4::float8[])
+			ON CONFLICT (tenant, id, labels) DO UPDATE 
+			SET value = EXCLUDED.value, updated_at = now()
+			WHERE metric.type = EXCLUDED.type;
+		;`, middleware.TenantFromCtx(ctx), ids, labels, values)
 
 		if err != nil {
 			return err
 		}
+		if err = checkUpserted(result, len(metrics)); err != nil {
+			return err
+		}
+
+		if err = recordHistory(ctx, tx, metrics); err != nil {
+			return err
+		}
 
 		return tx.Commit(ctx)
 	})
 }
 
+// Increment atomically adds the gauge increment to the stored value in the database.
+// Uses UPSERT pattern: inserts a new gauge starting from zero
+// or adds the increment to the existing value.
+// Executes within a transaction with automatic rollback on error.
+func (repository *dbMetricsRepository) Increment(ctx context.Context, metric models.Metrics) error {
+	return repository.IncrementAll(ctx, []models.Metrics{metric})
+}
+
+// IncrementAll performs batch increment of gauge metrics.
+// Increments are applied in slice order within a single transaction,
+// so repeated series accumulate.
+func (repository *dbMetricsRepository) IncrementAll(ctx context.Context, metrics []models.Metrics) error {
+	return repository.executeWithRetry(func() error {
+		tx, err := repository.storage.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		updated := make([]models.Metrics, len(metrics))
+		for i, metric := range metrics {
+			if updated[i], err = incrementGauge(ctx, tx, metric); err != nil {
+				return err
+			}
+		}
+
+		if err = recordHistory(ctx, tx, updated); err != nil {
+			return err
+		}
+
+		return tx.Commit(ctx)
+	})
+}
+
+// incrementGauge adds the gauge increment of the context tenant
+// within the given transaction.
+// Returns the gauge with its resulting value.
+func incrementGauge(ctx context.Context, tx pgx.Tx, metric models.Metrics) (models.Metrics, error) {
+	var value float64
+	err := tx.QueryRow(
+		ctx,
+		`INSERT INTO metric (tenant, id, labels, type, value)
+		VALUES ($1, $2, $3::jsonb, 'gauge', $4)
+		ON CONFLICT (tenant, id, labels)
+		DO UPDATE SET value = COALESCE(metric.value, 0) + EXCLUDED.value, updated_at = now()
+		WHERE metric.type = EXCLUDED.type
+		RETURNING value;`,
+		middleware.TenantFromCtx(ctx), metric.ID, encodeLabels(metric.Labels), metric.Increment,
+	).Scan(&value)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Metrics{}, fmt.Errorf("%w: gauge %s is stored with another type", ErrTypeMismatch, metric.Key())
+	}
+	if err != nil {
+		return models.Metrics{}, err
+	}
+
+	return models.Metrics{
+		ID:        metric.ID,
+		MType:     models.Gauge,
+		Labels:    metric.Labels,
+		Value:     &value,
+		Timestamp: metric.Timestamp,
+	}, nil
+}
+
+// Merge combines a histogram, summary or set metric with the stored one in the database.
+// Locks the stored row, merges buckets or sketches in Go and writes the result back.
+// Executes within a transaction with automatic rollback on error.
+func (repository *dbMetricsRepository) Merge(ctx context.Context, metric models.Metrics) error {
+	return repository.MergeAll(ctx, []models.Metrics{metric})
+}
+
+// MergeAll performs batch merge of histogram, summary and set metrics.
+// All metrics are merged within a single transaction.
+func (repository *dbMetricsRepository) MergeAll(ctx context.Context, metrics []models.Metrics) error {
+	return repository.executeWithRetry(func() error {
+		tx, err := repository.storage.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		for _, metric := range metrics {
+			if err := mergeMetric(ctx, tx, metric); err != nil {
+				return err
+			}
+		}
+
+		return tx.Commit(ctx)
+	})
+}
+
+// mergeMetric merges a single histogram, summary or set of the context tenant
+// within the given transaction.
+// The stored row is locked with SELECT ... FOR UPDATE until commit.
+func mergeMetric(ctx context.Context, tx pgx.Tx, metric models.Metrics) error {
+	tenant := middleware.TenantFromCtx(ctx)
+	saved, err := scanMetric(tx.QueryRow(
+		ctx,
+		"SELECT "+metricColumns+" FROM metric WHERE tenant = $1 AND id = $2 AND labels = $3::jsonb FOR UPDATE",
+		tenant, metric.ID, encodeLabels(metric.Labels),
+	))
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		saved = emptyMergeable(metric)
+	case err != nil:
+		return err
+	case saved.MType != metric.MType:
+		return typeMismatch(metric.Key(), saved.MType)
+	}
+
+	merged, err := models.Merge(saved, metric)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`INSERT INTO metric (tenant, id, labels, type, bounds, buckets, sum, count, sketch)
+		VALUES ($1, $2, $3::jsonb, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (tenant, id, labels)
+		DO UPDATE SET bounds = EXCLUDED.bounds, buckets = EXCLUDED.buckets,
+			sum = EXCLUDED.sum, count = EXCLUDED.count, sketch = EXCLUDED.sketch, updated_at = now();`,
+		tenant, merged.ID, encodeLabels(merged.Labels), merged.MType, merged.Bounds, merged.Buckets, merged.Sum, merged.Count, merged.Sketch,
+	)
+
+	return err
+}
+
+// checkUpserted returns ErrTypeMismatch if the upsert affected fewer rows
+// than expected, which happens when conflicting rows have another type
+// and are left unchanged by the conditional DO UPDATE.
+func checkUpserted(result pgconn.CommandTag, expected int) error {
+	if result.RowsAffected() < int64(expected) {
+		return fmt.Errorf("%w: %d of %d series are stored with another type", ErrTypeMismatch, int64(expected)-result.RowsAffected(), expected)
+	}
+	return nil
+}
+
+// recordHistory appends counter deltas and gauge values of the context tenant
+// to metric_history within the given transaction.
+// Uses a single bulk insert for all metrics.
+func recordHistory(ctx context.Context, tx pgx.Tx, metrics []models.Metrics) error {
+	ids := make([]string, len(metrics))
+	labels := make([]string, len(metrics))
+	types := make([]string, len(metrics))
+	timestamps := make([]time.Time, len(metrics))
+	deltas := make([]*int64, len(metrics))
+	values := make([]*float64, len(metrics))
+
+	for i, metric := range metrics {
+		point := metric.Point()
+		ids[i] = metric.ID
+		labels[i] = encodeLabels(metric.Labels)
+		types[i] = metric.MType
+		timestamps[i] = time.UnixMilli(point.Timestamp)
+		deltas[i] = point.Delta
+		values[i] = point.Value
+	}
+
+	_, err := tx.Exec(
+		ctx,
+		`
+		INSERT INTO metric_history (tenant, id, labels, type, ts, delta, value)
+		SELECT $1, unnest($2::text[]), unnest($3::text[])::jsonb, unnest($4::text[]),
+			unnest($5::timestamptz[]), unnest($6::bigint[]), unnest($7::float8[])
+		`,
+		middleware.TenantFromCtx(ctx), ids, labels, types, timestamps, deltas, values,
+	)
+
+	return err
+}
+
+// Delete removes all series of the context tenant selected by the query
+// together with their history in a single transaction.
+// Glob patterns are matched in Go against the distinct metric names of the tenant.
+func (repository *dbMetricsRepository) Delete(ctx context.Context, query models.DeleteQuery) ([]models.Metrics, error) {
+	var deleted []models.Metrics
+	err := repository.executeWithRetry(func() error {
+		tx, err := repository.storage.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		tenant := middleware.TenantFromCtx(ctx)
+		ids := []string{query.ID}
+		if query.Pattern != "" {
+			if ids, err = matchingIDs(ctx, tx, tenant, query); err != nil {
+				return err
+			}
+		}
+
+		rows, err := tx.Query(
+			ctx,
+			`
+			WITH deleted AS (
+				DELETE FROM metric
+				WHERE tenant = $1 AND id = ANY($2::text[]) AND ($3 = '' OR type = $3)
+				RETURNING `+metricColumns+`
+			), history AS (
+				DELETE FROM metric_history AS h USING deleted AS d
+				WHERE h.tenant = d.tenant AND h.id = d.id AND h.labels = d.labels
+			)
+			SELECT `+metricColumns+` FROM deleted;
+			`,
+			tenant, ids, query.MType,
+		)
+		if err != nil {
+			return err
+		}
+
+		currentDeleted, err := collectMetrics(rows)
+		if err != nil {
+			return err
+		}
+
+		if err = tx.Commit(ctx); err != nil {
+			return err
+		}
+
+		deleted = currentDeleted
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// matchingIDs returns distinct metric names of the tenant selected by the query pattern.
+func matchingIDs(ctx context.Context, tx pgx.Tx, tenant string, query models.DeleteQuery) ([]string, error) {
+	rows, err := tx.Query(ctx, "SELECT DISTINCT id, type FROM metric WHERE tenant = $1;", tenant)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id, metricType string
+		if err := rows.Scan(&id, &metricType); err != nil {
+			return nil, err
+		}
+		if query.Matches(id, metricType) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, rows.Err()
+}
+
+// Expire removes stale series of all tenants together with their history.
+// Staleness is evaluated in SQL against updated_at, per-metric TTLs
+// of every tenant are passed as arrays and override the default TTL.
+func (repository *dbMetricsRepository) Expire(ctx context.Context, policy models.ExpirePolicy) ([]models.Metrics, error) {
+	tenants := make([]string, 0)
+	ids := make([]string, 0)
+	ttls := make([]int64, 0)
+	for _, tenant := range slices.Sorted(maps.Keys(policy.PerMetric)) {
+		for _, id := range slices.Sorted(maps.Keys(policy.PerMetric[tenant])) {
+			if ttl := policy.PerMetric[tenant][id]; ttl > 0 {
+				tenants = append(tenants, tenant)
+				ids = append(ids, id)
+				ttls = append(ttls, int64(ttl.Seconds()))
+			}
+		}
+	}
+
+	var expired []models.Metrics
+	err := repository.executeWithRetry(func() error {
+		rows, err := repository.storage.Query(
+			ctx,
+			`
+			WITH ttl AS (
+				SELECT unnest($2::text[]) AS tenant, unnest($3::text[]) AS id, unnest($4::bigint[]) AS seconds
+			), deleted AS (
+				DELETE FROM metric AS m
+				WHERE COALESCE((SELECT ttl.seconds FROM ttl WHERE ttl.tenant = m.tenant AND ttl.id = m.id), $5) > 0
+					AND m.updated_at < $1::timestamptz
+						- COALESCE((SELECT ttl.seconds FROM ttl WHERE ttl.tenant = m.tenant AND ttl.id = m.id), $5) * interval '1 second'
+				RETURNING `+metricColumns+`
+			), history AS (
+				DELETE FROM metric_history AS h USING deleted AS d
+				WHERE h.tenant = d.tenant AND h.id = d.id AND h.labels = d.labels
+			)
+			SELECT `+metricColumns+` FROM deleted;
+			`,
+			policy.Now, tenants, ids, ttls, int64(policy.Default.Seconds()),
+		)
+		if err != nil {
+			return err
+		}
+
+		currentExpired, err := collectMetrics(rows)
+		if err != nil {
+			return err
+		}
+
+		expired = currentExpired
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+// PruneHistory deletes history points of all tenants before the given time.
+func (repository *dbMetricsRepository) PruneHistory(ctx context.Context, before time.Time) (int, error) {
+	var pruned int
+	err := repository.executeWithRetry(func() error {
+		tag, err := repository.storage.Exec(
+			ctx,
+			"DELETE FROM metric_history WHERE ts < $1;",
+			before,
+		)
+		if err != nil {
+			return err
+		}
+
+		pruned = int(tag.RowsAffected())
+		return nil
+	})
+
+	return pruned, err
+}
+
+// collectMetrics scans all rows selected with metricColumns and closes them.
+func collectMetrics(rows pgx.Rows) ([]models.Metrics, error) {
+	defer rows.Close()
+
+	metrics := make([]models.Metrics, 0)
+	for rows.Next() {
+		m, err := scanMetric(rows)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m)
+	}
+
+	return metrics, rows.Err()
+}
+
+// collectMatching reads metric rows whose IDs match the regular expression
+// until the limit is reached, zero means no limit.
+// Remaining rows are discarded when the rows are closed.
+func collectMatching(rows pgx.Rows, re *regexp.Regexp, limit int) ([]models.Metrics, error) {
+	defer rows.Close()
+
+	metrics := make([]models.Metrics, 0)
+	for rows.Next() {
+		m, err := scanMetric(rows)
+		if err != nil {
+			return nil, err
+		}
+		if !re.MatchString(m.ID) {
+			continue
+		}
+		metrics = append(metrics, m)
+		if limit > 0 && len(metrics) == limit {
+			break
+		}
+	}
+
+	return metrics, rows.Err()
+}
+
+// GetRange returns recorded points of a series within the query interval.
+// Points are ordered by timestamp using the (tenant, id, ts) index.
+func (repository *dbMetricsRepository) GetRange(ctx context.Context, query models.RangeQuery) ([]models.Point, error) {
+	points := make([]models.Point, 0)
+	err := repository.executeWithRetry(func() error {
+		rows, err := repository.storage.Query(
+			ctx,
+			`SELECT ts, delta, value FROM metric_history
+			WHERE tenant = $1 AND id = $2 AND labels = $3::jsonb AND type = $4 AND ts BETWEEN $5 AND $6
+			ORDER BY ts;`,
+			middleware.TenantFromCtx(ctx), query.ID, encodeLabels(query.Labels), query.MType, query.From, query.To,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		currentPoints := make([]models.Point, 0)
+		for rows.Next() {
+			var ts time.Time
+			var point models.Point
+			if err := rows.Scan(&ts, &point.Delta, &point.Value); err != nil {
+				return err
+			}
+			point.Timestamp = ts.UnixMilli()
+			currentPoints = append(currentPoints, point)
+		}
+
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		points = currentPoints
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// GetUpdated returns the last update time of every series
+// of the request tenant keyed by series key.
+func (repository *dbMetricsRepository) GetUpdated(ctx context.Context) (map[string]time.Time, error) {
+	var updated map[string]time.Time
+	err := repository.executeWithRetry(func() error {
+		rows, err := repository.storage.Query(
+			ctx,
+			"SELECT id, labels, updated_at FROM metric WHERE tenant = $1;",
+			middleware.TenantFromCtx(ctx),
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		currentUpdated := make(map[string]time.Time)
+		for rows.Next() {
+			var id string
+			var labels map[string]string
+			var at time.Time
+			if err := rows.Scan(&id, &labels, &at); err != nil {
+				return err
+			}
+			currentUpdated[models.SeriesKey(id, labels)] = at
+		}
+
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		updated = currentUpdated
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// scanMetric reads a single metric row selected with metricColumns.
+// Only the value fields matching the metric type are populated.
+func scanMetric(row pgx.Row) (models.Metrics, error) {
+	var m models.Metrics
+	var delta pgtype.Int8
+	var value pgtype.Float8
+	var sum pgtype.Float8
+	var count pgtype.Int8
+
+	if err := row.Scan(&m.Tenant, &m.ID, &m.MType, &m.Labels, &delta, &value, &m.Bounds, &m.Buckets, &sum, &count, &m.Sketch); err != nil {
+		return models.Metrics{}, err
+	}
+
+	if len(m.Labels) == 0 {
+		m.Labels = nil
+	}
+
+	switch m.MType {
+	case string(metric.CounterType):
+		m.Delta = &delta.Int64
+	case string(metric.GaugeType):
+		m.Value = &value.Float64
+	case string(metric.HistogramType), string(metric.SummaryType):
+		m.Sum = &sum.Float64
+		m.Count = &count.Int64
+	case string(metric.SetType):
+		m.Count = &count.Int64
+	default:
+		return models.Metrics{}, fmt.Errorf("invalid metric type: %s", m.MType)
+	}
+
+	return m, nil
+}
+
+// encodeLabels renders labels as a JSON object for the jsonb labels column.
+// Nil labels are stored as an empty object, the unlabeled series.
+func encodeLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "{}"
+	}
+
+	encoded, _ := json.Marshal(labels)
+	return string(encoded)
+}
+
 // isRetryableError determines if a database error is transient and safe to retry.
 // Checks PostgreSQL error codes for connection issues, deadlocks, and serialization failures.
 func isRetryableError(err error) bool {
@@ -333,6 +1065,12 @@ func isRetryableError(err error) bool {
 // Implements exponential backoff for retryable errors.
 // Returns the last error if all retries fail.
 func (repository *dbMetricsRepository) executeWithRetry(operation func() error) error {
+	return executeWithRetry(operation)
+}
+
+// executeWithRetry runs a database operation, retrying transient errors
+// with exponential backoff. Shared by all database repositories.
+func executeWithRetry(operation func() error) error {
 	var lastErr error
 	currentRetryDelay := retryDelay
 	for i := 0; i < retriesAmount; i++ {
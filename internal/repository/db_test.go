@@ -4,14 +4,18 @@ import (
 	"database/sql"
 	"errors"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/pashagolub/pgxmock/v4"
 
 	models "github.com/gabkaclassic/metrics/internal/model"
 	"github.com/gabkaclassic/metrics/internal/storage"
 	"github.com/gabkaclassic/metrics/pkg/metric"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -71,10 +75,12 @@ func TestDBMetricsRepository_GetAll(t *testing.T) {
 		{
 			name: "success mixed metrics",
 			mockQuery: func() {
-				rows := pgxmock.NewRows([]string{"id", "type", "delta", "value"}).
-					AddRow("counter1", string(metric.CounterType), int64(5), nil).
-					AddRow("gauge1", string(metric.GaugeType), nil, float64(3.14))
-				mock.ExpectQuery("SELECT id, type, delta, value FROM metric;").WillReturnRows(rows)
+				rows := pgxmock.NewRows(metricColumnNames).
+					AddRow(metricRow("counter1", string(metric.CounterType), int64(5), nil)...).
+					AddRow(metricRow("gauge1", string(metric.GaugeType), nil, float64(3.14))...)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT " + metricColumns + " FROM metric WHERE tenant = $1;")).
+					WithArgs(middleware.DefaultTenant).
+					WillReturnRows(rows)
 			},
 			expectData: map[string]any{
 				"counter1": int64(5),
@@ -85,7 +91,8 @@ func TestDBMetricsRepository_GetAll(t *testing.T) {
 		{
 			name: "query error",
 			mockQuery: func() {
-				mock.ExpectQuery("SELECT id, type, delta, value FROM metric;").
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT " + metricColumns + " FROM metric WHERE tenant = $1;")).
+					WithArgs(middleware.DefaultTenant).
 					WillReturnError(errors.New("db failure"))
 			},
 			expectData:  nil,
@@ -112,6 +119,27 @@ func TestDBMetricsRepository_GetAll(t *testing.T) {
 	}
 }
 
+func TestDBMetricsRepository_GetAll_tenant(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBMetricsRepository(mock)
+	assert.NoError(t, err)
+
+	rows := pgxmock.NewRows(metricColumnNames).
+		AddRow("team-a", "requests", models.Counter, nil, int64(5), nil, nil, nil, nil, nil, nil)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT " + metricColumns + " FROM metric WHERE tenant = $1;")).
+		WithArgs("team-a").
+		WillReturnRows(rows)
+
+	result, err := repo.GetAll(middleware.WithTenant(t.Context(), "team-a"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"requests": int64(5)}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestDBMetricsRepository_Get(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	assert.NoError(t, err)
@@ -132,10 +160,10 @@ func TestDBMetricsRepository_Get(t *testing.T) {
 			name:     "success gauge metric",
 			metricID: "g1",
 			mockQuery: func() {
-				rows := pgxmock.NewRows([]string{"id", "type", "delta", "value"}).
-					AddRow("g1", string(metric.GaugeType), nil, float64(12.34))
-				mock.ExpectQuery("SELECT id, type, delta, value FROM metric WHERE id = \\$1").
-					WithArgs("g1").WillReturnRows(rows)
+				rows := pgxmock.NewRows(metricColumnNames).
+					AddRow(metricRow("g1", string(metric.GaugeType), nil, float64(12.34))...)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT "+metricColumns+" FROM metric WHERE tenant = $1 AND id = $2 AND labels = $3::jsonb")).
+					WithArgs(middleware.DefaultTenant, "g1", "{}").WillReturnRows(rows)
 			},
 			expectValue: &models.Metrics{
 				ID:    "g1",
@@ -148,10 +176,10 @@ func TestDBMetricsRepository_Get(t *testing.T) {
 			name:     "success counter metric",
 			metricID: "c1",
 			mockQuery: func() {
-				rows := pgxmock.NewRows([]string{"id", "type", "delta", "value"}).
-					AddRow("c1", string(metric.CounterType), int64(7), nil)
-				mock.ExpectQuery("SELECT id, type, delta, value FROM metric WHERE id = \\$1").
-					WithArgs("c1").WillReturnRows(rows)
+				rows := pgxmock.NewRows(metricColumnNames).
+					AddRow(metricRow("c1", string(metric.CounterType), int64(7), nil)...)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT "+metricColumns+" FROM metric WHERE tenant = $1 AND id = $2 AND labels = $3::jsonb")).
+					WithArgs(middleware.DefaultTenant, "c1", "{}").WillReturnRows(rows)
 			},
 			expectValue: &models.Metrics{
 				ID:    "c1",
@@ -164,8 +192,8 @@ func TestDBMetricsRepository_Get(t *testing.T) {
 			name:     "metric not found",
 			metricID: "missing",
 			mockQuery: func() {
-				mock.ExpectQuery("SELECT id, type, delta, value FROM metric WHERE id = \\$1").
-					WithArgs("missing").WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT "+metricColumns+" FROM metric WHERE tenant = $1 AND id = $2 AND labels = $3::jsonb")).
+					WithArgs(middleware.DefaultTenant, "missing", "{}").WillReturnError(sql.ErrNoRows)
 			},
 			expectValue: nil,
 			expectError: true,
@@ -175,8 +203,8 @@ func TestDBMetricsRepository_Get(t *testing.T) {
 			name:     "query error",
 			metricID: "broken",
 			mockQuery: func() {
-				mock.ExpectQuery("SELECT id, type, delta, value FROM metric WHERE id = \\$1").
-					WithArgs("broken").WillReturnError(errors.New("db error"))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT "+metricColumns+" FROM metric WHERE tenant = $1 AND id = $2 AND labels = $3::jsonb")).
+					WithArgs(middleware.DefaultTenant, "broken", "{}").WillReturnError(errors.New("db error"))
 			},
 			expectValue: nil,
 			expectError: true,
@@ -188,7 +216,7 @@ func TestDBMetricsRepository_Get(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockQuery()
 
-			result, err := repo.Get(t.Context(), tt.metricID)
+			result, err := repo.Get(t.Context(), tt.metricID, nil)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -231,8 +259,10 @@ func TestDBMetricsRepository_Add(t *testing.T) {
 			},
 			mockQuery: func() {
 				mock.ExpectBegin()
-				mock.ExpectExec(`INSERT INTO metric \(id, type, delta\)`).
-					WithArgs("c1", intPtr(10)).
+				mock.ExpectExec(`INSERT INTO metric \(tenant, id, labels, type, delta\)`).
+					WithArgs(middleware.DefaultTenant, "c1", "{}", intPtr(10)).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectExec(historyQuery).WithArgs(historyArgs()...).
 					WillReturnResult(pgxmock.NewResult("INSERT", 1))
 				mock.ExpectCommit()
 			},
@@ -260,8 +290,8 @@ func TestDBMetricsRepository_Add(t *testing.T) {
 			},
 			mockQuery: func() {
 				mock.ExpectBegin()
-				mock.ExpectExec(`INSERT INTO metric \(id, type, delta\)`).
-					WithArgs("c3", intPtr(15)).
+				mock.ExpectExec(`INSERT INTO metric \(tenant, id, labels, type, delta\)`).
+					WithArgs(middleware.DefaultTenant, "c3", "{}", intPtr(15)).
 					WillReturnError(errors.New("insert failed"))
 				mock.ExpectRollback()
 			},
@@ -277,8 +307,10 @@ func TestDBMetricsRepository_Add(t *testing.T) {
 			},
 			mockQuery: func() {
 				mock.ExpectBegin()
-				mock.ExpectExec(`INSERT INTO metric \(id, type, delta\)`).
-					WithArgs("c4", intPtr(20)).
+				mock.ExpectExec(`INSERT INTO metric \(tenant, id, labels, type, delta\)`).
+					WithArgs(middleware.DefaultTenant, "c4", "{}", intPtr(20)).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectExec(historyQuery).WithArgs(historyArgs()...).
 					WillReturnResult(pgxmock.NewResult("INSERT", 1))
 				mock.ExpectCommit().WillReturnError(errors.New("commit failed"))
 			},
@@ -329,8 +361,10 @@ func TestDBMetricsRepository_Reset(t *testing.T) {
 			},
 			mockQuery: func() {
 				mock.ExpectBegin()
-				mock.ExpectExec(`INSERT INTO metric \(id, type, value\)`).
-					WithArgs("g1", floatPtr(42.42)).
+				mock.ExpectExec(`INSERT INTO metric \(tenant, id, labels, type, value\)`).
+					WithArgs(middleware.DefaultTenant, "g1", "{}", floatPtr(42.42)).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectExec(historyQuery).WithArgs(historyArgs()...).
 					WillReturnResult(pgxmock.NewResult("INSERT", 1))
 				mock.ExpectCommit()
 			},
@@ -358,8 +392,8 @@ func TestDBMetricsRepository_Reset(t *testing.T) {
 			},
 			mockQuery: func() {
 				mock.ExpectBegin()
-				mock.ExpectExec(`INSERT INTO metric \(id, type, value\)`).
-					WithArgs("g3", floatPtr(1.23)).
+				mock.ExpectExec(`INSERT INTO metric \(tenant, id, labels, type, value\)`).
+					WithArgs(middleware.DefaultTenant, "g3", "{}", floatPtr(1.23)).
 					WillReturnError(errors.New("insert failed"))
 				mock.ExpectRollback()
 			},
@@ -375,8 +409,10 @@ func TestDBMetricsRepository_Reset(t *testing.T) {
 			},
 			mockQuery: func() {
 				mock.ExpectBegin()
-				mock.ExpectExec(`INSERT INTO metric \(id, type, value\)`).
-					WithArgs("g4", floatPtr(99.9)).
+				mock.ExpectExec(`INSERT INTO metric \(tenant, id, labels, type, value\)`).
+					WithArgs(middleware.DefaultTenant, "g4", "{}", floatPtr(99.9)).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectExec(historyQuery).WithArgs(historyArgs()...).
 					WillReturnResult(pgxmock.NewResult("INSERT", 1))
 				mock.ExpectCommit().WillReturnError(errors.New("commit failed"))
 			},
@@ -403,6 +439,118 @@ func TestDBMetricsRepository_Reset(t *testing.T) {
 	}
 }
 
+func TestDBMetricsRepository_IncrementAll(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBMetricsRepository(mock)
+	assert.NoError(t, err)
+
+	incrementQuery := regexp.QuoteMeta(`INSERT INTO metric (tenant, id, labels, type, value)
+		VALUES ($1, $2, $3::jsonb, 'gauge', $4)
+		ON CONFLICT (tenant, id, labels)
+		DO UPDATE SET value = COALESCE(metric.value, 0) + EXCLUDED.value, updated_at = now()
+		WHERE metric.type = EXCLUDED.type
+		RETURNING value;`)
+
+	tests := []struct {
+		name        string
+		metrics     []models.Metrics
+		mockQuery   func()
+		expectError bool
+		errorText   string
+	}{
+		{
+			name: "success increments applied in order",
+			metrics: []models.Metrics{
+				{ID: "g1", MType: models.Gauge, Increment: floatPtr(5)},
+				{ID: "g1", MType: models.Gauge, Increment: floatPtr(-2)},
+			},
+			mockQuery: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(incrementQuery).
+					WithArgs(middleware.DefaultTenant, "g1", "{}", floatPtr(5)).
+					WillReturnRows(pgxmock.NewRows([]string{"value"}).AddRow(15.0))
+				mock.ExpectQuery(incrementQuery).
+					WithArgs(middleware.DefaultTenant, "g1", "{}", floatPtr(-2)).
+					WillReturnRows(pgxmock.NewRows([]string{"value"}).AddRow(13.0))
+				mock.ExpectExec(historyQuery).
+					WithArgs(
+						middleware.DefaultTenant,
+						[]string{"g1", "g1"},
+						[]string{"{}", "{}"},
+						[]string{models.Gauge, models.Gauge},
+						pgxmock.AnyArg(),
+						[]*int64{nil, nil},
+						[]*float64{floatPtr(15), floatPtr(13)},
+					).
+					WillReturnResult(pgxmock.NewResult("INSERT", 2))
+				mock.ExpectCommit()
+			},
+			expectError: false,
+		},
+		{
+			name: "begin transaction error",
+			metrics: []models.Metrics{
+				{ID: "g2", MType: models.Gauge, Increment: floatPtr(1)},
+			},
+			mockQuery: func() {
+				mock.ExpectBegin().WillReturnError(errors.New("tx begin failed"))
+			},
+			expectError: true,
+			errorText:   "tx begin failed",
+		},
+		{
+			name: "upsert error",
+			metrics: []models.Metrics{
+				{ID: "g3", MType: models.Gauge, Increment: floatPtr(1)},
+			},
+			mockQuery: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(incrementQuery).
+					WithArgs(middleware.DefaultTenant, "g3", "{}", floatPtr(1)).
+					WillReturnError(errors.New("upsert failed"))
+				mock.ExpectRollback()
+			},
+			expectError: true,
+			errorText:   "upsert failed",
+		},
+		{
+			name: "stored with another type",
+			metrics: []models.Metrics{
+				{ID: "g4", MType: models.Gauge, Increment: floatPtr(1)},
+			},
+			mockQuery: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(incrementQuery).
+					WithArgs(middleware.DefaultTenant, "g4", "{}", floatPtr(1)).
+					WillReturnRows(pgxmock.NewRows([]string{"value"}))
+				mock.ExpectRollback()
+			},
+			expectError: true,
+			errorText:   ErrTypeMismatch.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockQuery()
+
+			err := repo.IncrementAll(t.Context(), tt.metrics)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorText)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestDBMetricsRepository_AddAll(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	assert.NoError(t, err)
@@ -425,11 +573,34 @@ func TestDBMetricsRepository_AddAll(t *testing.T) {
 			mockQuery: func() {
 				mock.ExpectBegin()
 				mock.ExpectExec(regexp.QuoteMeta(
-					"INSERT INTO metric (id, type, delta) "+
-						"SELECT unnest($1::text[]), 'counter', unnest($2::bigint[]) "+
-						"ON CONFLICT (id) DO UPDATE SET delta = metric.delta + EXCLUDED.delta",
+					"INSERT INTO metric (tenant, id, labels, type, delta) "+
+						"SELECT $1, unnest($2::text[]), unnest($3::text[])::jsonb, 'counter', unnest($4::bigint[]) "+
+						"ON CONFLICT (tenant, id, labels) DO UPDATE SET delta = metric.delta + EXCLUDED.delta",
 				)).
-					WithArgs([]string{"c1"}, []int64{10}).
+					WithArgs(middleware.DefaultTenant, []string{"c1"}, []string{"{}"}, []int64{10}).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectExec(historyQuery).WithArgs(historyArgs()...).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectCommit()
+			},
+			expectError: false,
+		},
+		{
+			name: "success labeled counters",
+			metrics: []models.Metrics{
+				{ID: "c1", MType: models.Counter, Labels: map[string]string{"route": "/api", "host": "a"}, Delta: intPtr(10)},
+				{ID: "c1", MType: models.Counter, Delta: intPtr(2)},
+			},
+			mockQuery: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					"INSERT INTO metric (tenant, id, labels, type, delta) "+
+						"SELECT $1, unnest($2::text[]), unnest($3::text[])::jsonb, 'counter', unnest($4::bigint[]) "+
+						"ON CONFLICT (tenant, id, labels) DO UPDATE SET delta = metric.delta + EXCLUDED.delta",
+				)).
+					WithArgs(middleware.DefaultTenant, []string{"c1", "c1"}, []string{`{"host":"a","route":"/api"}`, "{}"}, []int64{10, 2}).
+					WillReturnResult(pgxmock.NewResult("INSERT", 2))
+				mock.ExpectExec(historyQuery).WithArgs(historyArgs()...).
 					WillReturnResult(pgxmock.NewResult("INSERT", 1))
 				mock.ExpectCommit()
 			},
@@ -445,13 +616,15 @@ func TestDBMetricsRepository_AddAll(t *testing.T) {
 			mockQuery: func() {
 				mock.ExpectBegin()
 				mock.ExpectExec(regexp.QuoteMeta(
-					"INSERT INTO metric (id, type, delta) "+
-						"SELECT unnest($1::text[]), 'counter', unnest($2::bigint[]) "+
-						"ON CONFLICT (id) DO UPDATE "+
+					"INSERT INTO metric (tenant, id, labels, type, delta) "+
+						"SELECT $1, unnest($2::text[]), unnest($3::text[])::jsonb, 'counter', unnest($4::bigint[]) "+
+						"ON CONFLICT (tenant, id, labels) DO UPDATE "+
 						"SET delta = metric.delta + EXCLUDED.delta",
 				)).
-					WithArgs([]string{"c1", "c2", "c1"}, []int64{10, 5, 3}).
+					WithArgs(middleware.DefaultTenant, []string{"c1", "c2", "c1"}, []string{"{}", "{}", "{}"}, []int64{10, 5, 3}).
 					WillReturnResult(pgxmock.NewResult("INSERT", 3))
+				mock.ExpectExec(historyQuery).WithArgs(historyArgs()...).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
 				mock.ExpectCommit()
 			},
 			expectError: false,
@@ -461,12 +634,14 @@ func TestDBMetricsRepository_AddAll(t *testing.T) {
 			metrics: []models.Metrics{},
 			mockQuery: func() {
 				mock.ExpectBegin()
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO metric (id, type, delta) "+
-					"SELECT unnest($1::text[]), 'counter', unnest($2::bigint[]) "+
-					"ON CONFLICT (id) DO UPDATE "+
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO metric (tenant, id, labels, type, delta) "+
+					"SELECT $1, unnest($2::text[]), unnest($3::text[])::jsonb, 'counter', unnest($4::bigint[]) "+
+					"ON CONFLICT (tenant, id, labels) DO UPDATE "+
 					"SET delta = metric.delta + EXCLUDED.delta")).
-					WithArgs([]string{}, []int64{}).
+					WithArgs(middleware.DefaultTenant, []string{}, []string{}, []int64{}).
 					WillReturnResult(pgxmock.NewResult("INSERT", 0))
+				mock.ExpectExec(historyQuery).WithArgs(historyArgs()...).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
 				mock.ExpectCommit()
 			},
 			expectError: false,
@@ -488,16 +663,35 @@ func TestDBMetricsRepository_AddAll(t *testing.T) {
 			},
 			mockQuery: func() {
 				mock.ExpectBegin()
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO metric (id, type, delta) "+
-					"SELECT unnest($1::text[]), 'counter', unnest($2::bigint[]) "+
-					"ON CONFLICT (id) DO UPDATE "+
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO metric (tenant, id, labels, type, delta) "+
+					"SELECT $1, unnest($2::text[]), unnest($3::text[])::jsonb, 'counter', unnest($4::bigint[]) "+
+					"ON CONFLICT (tenant, id, labels) DO UPDATE "+
 					"SET delta = metric.delta + EXCLUDED.delta")).
-					WithArgs([]string{"c1"}, []int64{10}).
+					WithArgs(middleware.DefaultTenant, []string{"c1"}, []string{"{}"}, []int64{10}).
 					WillReturnError(errors.New("exec error"))
 				mock.ExpectRollback()
 			},
 			expectError: true,
 		},
+		{
+			name: "stored with another type",
+			metrics: []models.Metrics{
+				{ID: "c1", MType: models.Counter, Delta: intPtr(10)},
+				{ID: "h1", MType: models.Counter, Delta: intPtr(1)},
+			},
+			mockQuery: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO metric (tenant, id, labels, type, delta) "+
+					"SELECT $1, unnest($2::text[]), unnest($3::text[])::jsonb, 'counter', unnest($4::bigint[]) "+
+					"ON CONFLICT (tenant, id, labels) DO UPDATE "+
+					"SET delta = metric.delta + EXCLUDED.delta, updated_at = now() "+
+					"WHERE metric.type = EXCLUDED.type")).
+					WithArgs(middleware.DefaultTenant, []string{"c1", "h1"}, []string{"{}", "{}"}, []int64{10, 1}).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectRollback()
+			},
+			expectError: true,
+		},
 		{
 			name: "commit error",
 			metrics: []models.Metrics{
@@ -505,11 +699,13 @@ func TestDBMetricsRepository_AddAll(t *testing.T) {
 			},
 			mockQuery: func() {
 				mock.ExpectBegin()
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO metric (id, type, delta) "+
-					"SELECT unnest($1::text[]), 'counter', unnest($2::bigint[]) "+
-					"ON CONFLICT (id) DO UPDATE "+
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO metric (tenant, id, labels, type, delta) "+
+					"SELECT $1, unnest($2::text[]), unnest($3::text[])::jsonb, 'counter', unnest($4::bigint[]) "+
+					"ON CONFLICT (tenant, id, labels) DO UPDATE "+
 					"SET delta = metric.delta + EXCLUDED.delta")).
-					WithArgs([]string{"c1"}, []int64{10}).
+					WithArgs(middleware.DefaultTenant, []string{"c1"}, []string{"{}"}, []int64{10}).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectExec(historyQuery).WithArgs(historyArgs()...).
 					WillReturnResult(pgxmock.NewResult("INSERT", 1))
 				mock.ExpectCommit().WillReturnError(errors.New("commit error"))
 			},
@@ -555,11 +751,13 @@ func TestDBMetricsRepository_ResetAll(t *testing.T) {
 			},
 			mockQuery: func() {
 				mock.ExpectBegin()
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO metric (id, type, value) "+
-					"SELECT unnest($1::text[]), 'gauge', unnest($2::float8[]) "+
-					"ON CONFLICT (id) DO UPDATE "+
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO metric (tenant, id, labels, type, value) "+
+					"SELECT $1, unnest($2::text[]), unnest($3::text[])::jsonb, 'gauge', unnest($4::float8[]) "+
+					"ON CONFLICT (tenant, id, labels) DO UPDATE "+
 					"SET value = EXCLUDED.value")).
-					WithArgs([]string{"g1"}, []float64{3.14}).
+					WithArgs(middleware.DefaultTenant, []string{"g1"}, []string{"{}"}, []float64{3.14}).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectExec(historyQuery).WithArgs(historyArgs()...).
 					WillReturnResult(pgxmock.NewResult("INSERT", 1))
 				mock.ExpectCommit()
 			},
@@ -574,12 +772,14 @@ func TestDBMetricsRepository_ResetAll(t *testing.T) {
 			},
 			mockQuery: func() {
 				mock.ExpectBegin()
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO metric (id, type, value) "+
-					"SELECT unnest($1::text[]), 'gauge', unnest($2::float8[]) "+
-					"ON CONFLICT (id) DO UPDATE "+
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO metric (tenant, id, labels, type, value) "+
+					"SELECT $1, unnest($2::text[]), unnest($3::text[])::jsonb, 'gauge', unnest($4::float8[]) "+
+					"ON CONFLICT (tenant, id, labels) DO UPDATE "+
 					"SET value = EXCLUDED.value")).
-					WithArgs([]string{"g1", "g2", "g3"}, []float64{3.14, 2.71, 1.41}).
+					WithArgs(middleware.DefaultTenant, []string{"g1", "g2", "g3"}, []string{"{}", "{}", "{}"}, []float64{3.14, 2.71, 1.41}).
 					WillReturnResult(pgxmock.NewResult("INSERT", 3))
+				mock.ExpectExec(historyQuery).WithArgs(historyArgs()...).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
 				mock.ExpectCommit()
 			},
 			expectError: false,
@@ -589,13 +789,16 @@ func TestDBMetricsRepository_ResetAll(t *testing.T) {
 			metrics: []models.Metrics{},
 			mockQuery: func() {
 				mock.ExpectBegin()
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO metric (id, type, value) "+
-					"SELECT unnest($1::text[]), 'gauge', unnest($2::float8[]) "+
-					"ON CONFLICT (id) DO UPDATE "+
-					"SET value = EXCLUDED.value; "+
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO metric (tenant, id, labels, type, value) "+
+					"SELECT $1, unnest($2::text[]), unnest($3::text[])::jsonb, 'gauge', unnest($4::float8[]) "+
+					"ON CONFLICT (tenant, id, labels) DO UPDATE "+
+					"SET value = EXCLUDED.value, updated_at = now() "+
+					"WHERE metric.type = EXCLUDED.type; "+
 					";")).
-					WithArgs([]string{}, []float64{}).
+					WithArgs(middleware.DefaultTenant, []string{}, []string{}, []float64{}).
 					WillReturnResult(pgxmock.NewResult("INSERT", 0))
+				mock.ExpectExec(historyQuery).WithArgs(historyArgs()...).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
 				mock.ExpectCommit()
 			},
 			expectError: false,
@@ -617,12 +820,13 @@ func TestDBMetricsRepository_ResetAll(t *testing.T) {
 			},
 			mockQuery: func() {
 				mock.ExpectBegin()
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO metric (id, type, value) "+
-					"SELECT unnest($1::text[]), 'gauge', unnest($2::float8[]) "+
-					"ON CONFLICT (id) DO UPDATE "+
-					"SET value = EXCLUDED.value; "+
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO metric (tenant, id, labels, type, value) "+
+					"SELECT $1, unnest($2::text[]), unnest($3::text[])::jsonb, 'gauge', unnest($4::float8[]) "+
+					"ON CONFLICT (tenant, id, labels) DO UPDATE "+
+					"SET value = EXCLUDED.value, updated_at = now() "+
+					"WHERE metric.type = EXCLUDED.type; "+
 					";")).
-					WithArgs([]string{"g1"}, []float64{3.14}).
+					WithArgs(middleware.DefaultTenant, []string{"g1"}, []string{"{}"}, []float64{3.14}).
 					WillReturnError(errors.New("exec error"))
 				mock.ExpectRollback()
 			},
@@ -635,12 +839,15 @@ func TestDBMetricsRepository_ResetAll(t *testing.T) {
 			},
 			mockQuery: func() {
 				mock.ExpectBegin()
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO metric (id, type, value) "+
-					"SELECT unnest($1::text[]), 'gauge', unnest($2::float8[]) "+
-					"ON CONFLICT (id) DO UPDATE "+
-					"SET value = EXCLUDED.value; "+
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO metric (tenant, id, labels, type, value) "+
+					"SELECT $1, unnest($2::text[]), unnest($3::text[])::jsonb, 'gauge', unnest($4::float8[]) "+
+					"ON CONFLICT (tenant, id, labels) DO UPDATE "+
+					"SET value = EXCLUDED.value, updated_at = now() "+
+					"WHERE metric.type = EXCLUDED.type; "+
 					";")).
-					WithArgs([]string{"g1"}, []float64{3.14}).
+					WithArgs(middleware.DefaultTenant, []string{"g1"}, []string{"{}"}, []float64{3.14}).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectExec(historyQuery).WithArgs(historyArgs()...).
 					WillReturnResult(pgxmock.NewResult("INSERT", 1))
 				mock.ExpectCommit().WillReturnError(errors.New("commit error"))
 			},
@@ -682,10 +889,10 @@ func TestDBMetricsRepository_GetAllMetrics(t *testing.T) {
 		{
 			name: "success mixed metrics",
 			mockQuery: func() {
-				rows := pgxmock.NewRows([]string{"id", "type", "delta", "value"}).
-					AddRow("counter1", string(models.Counter), int64(5), nil).
-					AddRow("gauge1", string(models.Gauge), nil, float64(3.14))
-				mock.ExpectQuery("SELECT id, type, delta, value FROM metric;").WillReturnRows(rows)
+				rows := pgxmock.NewRows(metricColumnNames).
+					AddRow(metricRow("counter1", string(models.Counter), int64(5), nil)...).
+					AddRow(metricRow("gauge1", string(models.Gauge), nil, float64(3.14))...)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT " + metricColumns + " FROM metric;")).WillReturnRows(rows)
 			},
 			expectData: []models.Metrics{
 				{ID: "counter1", MType: models.Counter, Delta: intPtr(5), Value: nil},
@@ -696,10 +903,10 @@ func TestDBMetricsRepository_GetAllMetrics(t *testing.T) {
 		{
 			name: "success only counters",
 			mockQuery: func() {
-				rows := pgxmock.NewRows([]string{"id", "type", "delta", "value"}).
-					AddRow("counter1", string(models.Counter), int64(10), nil).
-					AddRow("counter2", string(models.Counter), int64(20), nil)
-				mock.ExpectQuery("SELECT id, type, delta, value FROM metric;").WillReturnRows(rows)
+				rows := pgxmock.NewRows(metricColumnNames).
+					AddRow(metricRow("counter1", string(models.Counter), int64(10), nil)...).
+					AddRow(metricRow("counter2", string(models.Counter), int64(20), nil)...)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT " + metricColumns + " FROM metric;")).WillReturnRows(rows)
 			},
 			expectData: []models.Metrics{
 				{ID: "counter1", MType: models.Counter, Delta: intPtr(10), Value: nil},
@@ -710,10 +917,10 @@ func TestDBMetricsRepository_GetAllMetrics(t *testing.T) {
 		{
 			name: "success only gauges",
 			mockQuery: func() {
-				rows := pgxmock.NewRows([]string{"id", "type", "delta", "value"}).
-					AddRow("gauge1", string(models.Gauge), nil, float64(1.1)).
-					AddRow("gauge2", string(models.Gauge), nil, float64(2.2))
-				mock.ExpectQuery("SELECT id, type, delta, value FROM metric;").WillReturnRows(rows)
+				rows := pgxmock.NewRows(metricColumnNames).
+					AddRow(metricRow("gauge1", string(models.Gauge), nil, float64(1.1))...).
+					AddRow(metricRow("gauge2", string(models.Gauge), nil, float64(2.2))...)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT " + metricColumns + " FROM metric;")).WillReturnRows(rows)
 			},
 			expectData: []models.Metrics{
 				{ID: "gauge1", MType: models.Gauge, Delta: nil, Value: floatPtr(1.1)},
@@ -724,8 +931,8 @@ func TestDBMetricsRepository_GetAllMetrics(t *testing.T) {
 		{
 			name: "empty result",
 			mockQuery: func() {
-				rows := pgxmock.NewRows([]string{"id", "type", "delta", "value"})
-				mock.ExpectQuery("SELECT id, type, delta, value FROM metric;").WillReturnRows(rows)
+				rows := pgxmock.NewRows(metricColumnNames)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT " + metricColumns + " FROM metric;")).WillReturnRows(rows)
 			},
 			expectData:  []models.Metrics{},
 			expectError: false,
@@ -733,7 +940,7 @@ func TestDBMetricsRepository_GetAllMetrics(t *testing.T) {
 		{
 			name: "query error",
 			mockQuery: func() {
-				mock.ExpectQuery("SELECT id, type, delta, value FROM metric;").
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT " + metricColumns + " FROM metric;")).
 					WillReturnError(errors.New("db failure"))
 			},
 			expectData:  nil,
@@ -742,9 +949,9 @@ func TestDBMetricsRepository_GetAllMetrics(t *testing.T) {
 		{
 			name: "scan error",
 			mockQuery: func() {
-				rows := pgxmock.NewRows([]string{"id", "type", "delta", "value"}).
-					AddRow(nil, nil, nil, nil)
-				mock.ExpectQuery("SELECT id, type, delta, value FROM metric;").WillReturnRows(rows)
+				rows := pgxmock.NewRows(metricColumnNames).
+					AddRow(metricRow(nil, nil, nil, nil)...)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT " + metricColumns + " FROM metric;")).WillReturnRows(rows)
 			},
 			expectData:  nil,
 			expectError: true,
@@ -752,10 +959,10 @@ func TestDBMetricsRepository_GetAllMetrics(t *testing.T) {
 		{
 			name: "rows error",
 			mockQuery: func() {
-				rows := pgxmock.NewRows([]string{"id", "type", "delta", "value"}).
-					AddRow("counter1", string(models.Counter), int64(5), nil).
+				rows := pgxmock.NewRows(metricColumnNames).
+					AddRow(metricRow("counter1", string(models.Counter), int64(5), nil)...).
 					RowError(0, errors.New("row error"))
-				mock.ExpectQuery("SELECT id, type, delta, value FROM metric;").WillReturnRows(rows)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT " + metricColumns + " FROM metric;")).WillReturnRows(rows)
 			},
 			expectData:  nil,
 			expectError: true,
@@ -795,3 +1002,685 @@ func TestDBMetricsRepository_GetAllMetrics(t *testing.T) {
 		})
 	}
 }
+
+func TestDBMetricsRepository_MergeAll(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBMetricsRepository(mock)
+	assert.NoError(t, err)
+
+	selectQuery := regexp.QuoteMeta("SELECT " + metricColumns + " FROM metric WHERE tenant = $1 AND id = $2 AND labels = $3::jsonb FOR UPDATE")
+	insertQuery := regexp.QuoteMeta("INSERT INTO metric (tenant, id, labels, type, bounds, buckets, sum, count, sketch)")
+
+	storedSummary := models.ObserveSummary("s1", 1)
+	observedSummary := models.ObserveSummary("s1", 3)
+	mergedSummary, err := models.MergeSummary(storedSummary, observedSummary)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		metrics     []models.Metrics
+		mockQuery   func()
+		expectError bool
+	}{
+		{
+			name: "new histogram",
+			metrics: []models.Metrics{
+				models.ObserveHistogram("h1", []float64{1, 2}, 1.5),
+			},
+			mockQuery: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).
+					WithArgs(middleware.DefaultTenant, "h1", "{}").
+					WillReturnError(pgx.ErrNoRows)
+				mock.ExpectExec(insertQuery).
+					WithArgs(middleware.DefaultTenant, "h1", "{}", models.Histogram, []float64{1, 2}, []int64{0, 1, 0}, floatPtr(1.5), intPtr(1), []byte(nil)).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectCommit()
+			},
+			expectError: false,
+		},
+		{
+			name: "existing histogram",
+			metrics: []models.Metrics{
+				models.ObserveHistogram("h1", []float64{1, 2}, 3),
+			},
+			mockQuery: func() {
+				mock.ExpectBegin()
+				rows := pgxmock.NewRows(metricColumnNames).
+					AddRow(middleware.DefaultTenant, "h1", models.Histogram, nil, nil, nil, []float64{1, 2}, []int64{1, 0, 0}, float64(0.5), int64(1), nil)
+				mock.ExpectQuery(selectQuery).
+					WithArgs(middleware.DefaultTenant, "h1", "{}").
+					WillReturnRows(rows)
+				mock.ExpectExec(insertQuery).
+					WithArgs(middleware.DefaultTenant, "h1", "{}", models.Histogram, []float64{1, 2}, []int64{1, 0, 1}, floatPtr(3.5), intPtr(2), []byte(nil)).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectCommit()
+			},
+			expectError: false,
+		},
+		{
+			name: "new summary",
+			metrics: []models.Metrics{
+				observedSummary,
+			},
+			mockQuery: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).
+					WithArgs(middleware.DefaultTenant, "s1", "{}").
+					WillReturnError(pgx.ErrNoRows)
+				mock.ExpectExec(insertQuery).
+					WithArgs(middleware.DefaultTenant, "s1", "{}", models.Summary, []float64(nil), []int64(nil), floatPtr(3), intPtr(1), observedSummary.Sketch).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectCommit()
+			},
+			expectError: false,
+		},
+		{
+			name: "existing summary",
+			metrics: []models.Metrics{
+				observedSummary,
+			},
+			mockQuery: func() {
+				mock.ExpectBegin()
+				rows := pgxmock.NewRows(metricColumnNames).
+					AddRow(middleware.DefaultTenant, "s1", models.Summary, nil, nil, nil, nil, nil, float64(1), int64(1), storedSummary.Sketch)
+				mock.ExpectQuery(selectQuery).
+					WithArgs(middleware.DefaultTenant, "s1", "{}").
+					WillReturnRows(rows)
+				mock.ExpectExec(insertQuery).
+					WithArgs(middleware.DefaultTenant, "s1", "{}", models.Summary, []float64(nil), []int64(nil), floatPtr(4), intPtr(2), mergedSummary.Sketch).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectCommit()
+			},
+			expectError: false,
+		},
+		{
+			name: "new set",
+			metrics: []models.Metrics{
+				models.ObserveSet("u1", "alice", "bob"),
+			},
+			mockQuery: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).
+					WithArgs(middleware.DefaultTenant, "u1", "{}").
+					WillReturnError(pgx.ErrNoRows)
+				mock.ExpectExec(insertQuery).
+					WithArgs(middleware.DefaultTenant, "u1", "{}", models.Set, []float64(nil), []int64(nil), (*float64)(nil), intPtr(2), models.ObserveSet("u1", "alice", "bob").Sketch).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectCommit()
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid stored sketch",
+			metrics: []models.Metrics{
+				observedSummary,
+			},
+			mockQuery: func() {
+				mock.ExpectBegin()
+				rows := pgxmock.NewRows(metricColumnNames).
+					AddRow(middleware.DefaultTenant, "s1", models.Summary, nil, nil, nil, nil, nil, float64(1), int64(1), []byte("broken"))
+				mock.ExpectQuery(selectQuery).
+					WithArgs(middleware.DefaultTenant, "s1", "{}").
+					WillReturnRows(rows)
+				mock.ExpectRollback()
+			},
+			expectError: true,
+		},
+		{
+			name: "bounds mismatch",
+			metrics: []models.Metrics{
+				models.ObserveHistogram("h1", []float64{5}, 3),
+			},
+			mockQuery: func() {
+				mock.ExpectBegin()
+				rows := pgxmock.NewRows(metricColumnNames).
+					AddRow(middleware.DefaultTenant, "h1", models.Histogram, nil, nil, nil, []float64{1, 2}, []int64{1, 0, 0}, float64(0.5), int64(1), nil)
+				mock.ExpectQuery(selectQuery).
+					WithArgs(middleware.DefaultTenant, "h1", "{}").
+					WillReturnRows(rows)
+				mock.ExpectRollback()
+			},
+			expectError: true,
+		},
+		{
+			name: "type mismatch",
+			metrics: []models.Metrics{
+				models.ObserveHistogram("c1", []float64{1}, 3),
+			},
+			mockQuery: func() {
+				mock.ExpectBegin()
+				rows := pgxmock.NewRows(metricColumnNames).
+					AddRow(metricRow("c1", models.Counter, int64(5), nil)...)
+				mock.ExpectQuery(selectQuery).
+					WithArgs(middleware.DefaultTenant, "c1", "{}").
+					WillReturnRows(rows)
+				mock.ExpectRollback()
+			},
+			expectError: true,
+		},
+		{
+			name: "begin error",
+			metrics: []models.Metrics{
+				models.ObserveHistogram("h1", []float64{1}, 3),
+			},
+			mockQuery: func() {
+				mock.ExpectBegin().WillReturnError(errors.New("begin error"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockQuery()
+
+			err := repo.MergeAll(t.Context(), tt.metrics)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDBMetricsRepository_Delete(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBMetricsRepository(mock)
+	assert.NoError(t, err)
+
+	deleteQuery := regexp.QuoteMeta("DELETE FROM metric WHERE tenant = $1 AND id = ANY($2::text[]) AND ($3 = '' OR type = $3)")
+	idsQuery := regexp.QuoteMeta("SELECT DISTINCT id, type FROM metric WHERE tenant = $1;")
+
+	tests := []struct {
+		name          string
+		query         models.DeleteQuery
+		mockQuery     func()
+		expectDeleted []models.Metrics
+		expectError   bool
+	}{
+		{
+			name:  "exact id",
+			query: models.DeleteQuery{ID: "g1", MType: models.Gauge},
+			mockQuery: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(deleteQuery).
+					WithArgs(middleware.DefaultTenant, []string{"g1"}, models.Gauge).
+					WillReturnRows(pgxmock.NewRows(metricColumnNames).AddRow(metricRow("g1", models.Gauge, nil, 1.5)...))
+				mock.ExpectCommit()
+			},
+			expectDeleted: []models.Metrics{
+				{Tenant: middleware.DefaultTenant, ID: "g1", MType: models.Gauge, Value: floatPtr(1.5)},
+			},
+		},
+		{
+			name:  "pattern matched in go",
+			query: models.DeleteQuery{Pattern: "cpu_*"},
+			mockQuery: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(idsQuery).
+					WithArgs(middleware.DefaultTenant).
+					WillReturnRows(pgxmock.NewRows([]string{"id", "type"}).
+						AddRow("cpu_user", models.Counter).
+						AddRow("mem", models.Gauge))
+				mock.ExpectQuery(deleteQuery).
+					WithArgs(middleware.DefaultTenant, []string{"cpu_user"}, "").
+					WillReturnRows(pgxmock.NewRows(metricColumnNames).AddRow(metricRow("cpu_user", models.Counter, int64(3), nil)...))
+				mock.ExpectCommit()
+			},
+			expectDeleted: []models.Metrics{
+				{Tenant: middleware.DefaultTenant, ID: "cpu_user", MType: models.Counter, Delta: intPtr(3)},
+			},
+		},
+		{
+			name:  "delete error",
+			query: models.DeleteQuery{ID: "g1"},
+			mockQuery: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(deleteQuery).
+					WithArgs(middleware.DefaultTenant, []string{"g1"}, "").
+					WillReturnError(errors.New("delete failed"))
+				mock.ExpectRollback()
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockQuery()
+
+			deleted, err := repo.Delete(t.Context(), tt.query)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectDeleted, deleted)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDBMetricsRepository_Expire(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBMetricsRepository(mock)
+	assert.NoError(t, err)
+
+	now := time.Now()
+	row := metricRow("g1", models.Gauge, nil, 1.5)
+	row[0] = "team-a"
+
+	mock.ExpectQuery(regexp.QuoteMeta("DELETE FROM metric AS m")).
+		WithArgs(now, []string{"default", "team-a"}, []string{"g1", "g1"}, []int64{3600, 60}, int64(60)).
+		WillReturnRows(pgxmock.NewRows(metricColumnNames).AddRow(row...))
+
+	expired, err := repo.Expire(t.Context(), models.ExpirePolicy{
+		Now:     now,
+		Default: time.Minute,
+		PerMetric: map[string]map[string]time.Duration{
+			"default": {"g1": time.Hour, "g2": 0},
+			"team-a":  {"g1": time.Minute},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Metrics{
+		{Tenant: "team-a", ID: "g1", MType: models.Gauge, Value: floatPtr(1.5)},
+	}, expired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDBMetricsRepository_PruneHistory(t *testing.T) {
+	before := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		setupMock      func(mock pgxmock.PgxPoolIface)
+		expectedPruned int
+		expectError    bool
+	}{
+		{
+			name: "points of all tenants",
+			setupMock: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM metric_history WHERE ts < $1;")).
+					WithArgs(before).
+					WillReturnResult(pgxmock.NewResult("DELETE", 42))
+			},
+			expectedPruned: 42,
+		},
+		{
+			name: "database error",
+			setupMock: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM metric_history")).
+					WithArgs(before).
+					WillReturnError(errors.New("db error"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			assert.NoError(t, err)
+			defer mock.Close()
+
+			tt.setupMock(mock)
+
+			repo, err := NewDBMetricsRepository(mock)
+			assert.NoError(t, err)
+
+			pruned, err := repo.PruneHistory(t.Context(), before)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedPruned, pruned)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDBMetricsRepository_GetRange(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBMetricsRepository(mock)
+	assert.NoError(t, err)
+
+	rangeQuery := regexp.QuoteMeta("SELECT ts, delta, value FROM metric_history " +
+		"WHERE tenant = $1 AND id = $2 AND labels = $3::jsonb AND type = $4 AND ts BETWEEN $5 AND $6 ORDER BY ts;")
+	from := time.UnixMilli(1000)
+	to := time.UnixMilli(5000)
+
+	tests := []struct {
+		name        string
+		query       models.RangeQuery
+		mockQuery   func()
+		expectData  []models.Point
+		expectError bool
+	}{
+		{
+			name:  "counter points",
+			query: models.RangeQuery{ID: "c1", MType: models.Counter, Labels: map[string]string{"host": "a"}, From: from, To: to},
+			mockQuery: func() {
+				rows := pgxmock.NewRows([]string{"ts", "delta", "value"}).
+					AddRow(time.UnixMilli(1000), intPtr(3), nil).
+					AddRow(time.UnixMilli(2000), intPtr(4), nil)
+				mock.ExpectQuery(rangeQuery).
+					WithArgs(middleware.DefaultTenant, "c1", `{"host":"a"}`, models.Counter, from, to).
+					WillReturnRows(rows)
+			},
+			expectData: []models.Point{
+				{Timestamp: 1000, Delta: intPtr(3)},
+				{Timestamp: 2000, Delta: intPtr(4)},
+			},
+			expectError: false,
+		},
+		{
+			name:  "no points",
+			query: models.RangeQuery{ID: "g1", MType: models.Gauge, From: from, To: to},
+			mockQuery: func() {
+				rows := pgxmock.NewRows([]string{"ts", "delta", "value"})
+				mock.ExpectQuery(rangeQuery).
+					WithArgs(middleware.DefaultTenant, "g1", "{}", models.Gauge, from, to).
+					WillReturnRows(rows)
+			},
+			expectData:  []models.Point{},
+			expectError: false,
+		},
+		{
+			name:  "query error",
+			query: models.RangeQuery{ID: "g1", MType: models.Gauge, From: from, To: to},
+			mockQuery: func() {
+				mock.ExpectQuery(rangeQuery).
+					WithArgs(middleware.DefaultTenant, "g1", "{}", models.Gauge, from, to).
+					WillReturnError(errors.New("db error"))
+			},
+			expectData:  nil,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockQuery()
+
+			result, err := repo.GetRange(t.Context(), tt.query)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectData, result)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDBMetricsRepository_List(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBMetricsRepository(mock)
+	assert.NoError(t, err)
+
+	selectPrefix := "SELECT " + metricColumns + " FROM metric WHERE "
+
+	tests := []struct {
+		name        string
+		query       models.ListQuery
+		statement   string
+		args        []any
+		rows        [][]any
+		mockErr     error
+		expectData  []models.Metrics
+		expectError bool
+	}{
+		{
+			name:      "no filters",
+			query:     models.ListQuery{},
+			statement: selectPrefix + "tenant = $1 ORDER BY id, labels;",
+			args:      []any{middleware.DefaultTenant},
+			expectData: []models.Metrics{
+				{Tenant: middleware.DefaultTenant, ID: "g1", MType: models.Gauge, Value: floatPtr(1.5)},
+			},
+		},
+		{
+			name: "all filters",
+			query: models.ListQuery{
+				MType:   models.Gauge,
+				Prefix:  "cpu",
+				Pattern: "cpu_*",
+				Regex:   "^cpu",
+				Limit:   10,
+			},
+			statement: selectPrefix + "tenant = $1 AND type = $2 AND starts_with(id, $3) AND id ~ $4 " +
+				"ORDER BY id, labels;",
+			args: []any{middleware.DefaultTenant, models.Gauge, "cpu", "^cpu_[^/]*$"},
+			rows: [][]any{metricRow("cpu_a", models.Gauge, nil, 1.5)},
+			expectData: []models.Metrics{
+				{Tenant: middleware.DefaultTenant, ID: "cpu_a", MType: models.Gauge, Value: floatPtr(1.5)},
+			},
+		},
+		{
+			name:      "regex evaluated in go until limit",
+			query:     models.ListQuery{Regex: `\Acpu_\d`, Limit: 2},
+			statement: selectPrefix + "tenant = $1 ORDER BY id, labels;",
+			args:      []any{middleware.DefaultTenant},
+			rows: [][]any{
+				metricRow("cpu_1", models.Gauge, nil, 1.0),
+				metricRow("cpu_x", models.Gauge, nil, 2.0),
+				metricRow("cpu_2", models.Gauge, nil, 3.0),
+				metricRow("cpu_3", models.Gauge, nil, 4.0),
+			},
+			expectData: []models.Metrics{
+				{Tenant: middleware.DefaultTenant, ID: "cpu_1", MType: models.Gauge, Value: floatPtr(1)},
+				{Tenant: middleware.DefaultTenant, ID: "cpu_2", MType: models.Gauge, Value: floatPtr(3)},
+			},
+		},
+		{
+			name: "type order descending after cursor",
+			query: models.ListQuery{
+				SortBy:     models.SortByType,
+				Descending: true,
+				Limit:      2,
+				After:      &models.SeriesRef{ID: "cpu", MType: models.Gauge, Labels: map[string]string{"host": "a"}},
+			},
+			statement: selectPrefix + "tenant = $1 AND (type, id, labels) < ($2, $3, $4::jsonb) " +
+				"ORDER BY type DESC, id DESC, labels DESC LIMIT $5;",
+			args: []any{middleware.DefaultTenant, models.Gauge, "cpu", `{"host":"a"}`, 2},
+			expectData: []models.Metrics{
+				{Tenant: middleware.DefaultTenant, ID: "g1", MType: models.Gauge, Value: floatPtr(1.5)},
+			},
+		},
+		{
+			name:      "id order after cursor",
+			query:     models.ListQuery{After: &models.SeriesRef{ID: "cpu", MType: models.Gauge}},
+			statement: selectPrefix + "tenant = $1 AND (id, labels) > ($2, $3::jsonb) ORDER BY id, labels;",
+			args:      []any{middleware.DefaultTenant, "cpu", "{}"},
+			expectData: []models.Metrics{
+				{Tenant: middleware.DefaultTenant, ID: "g1", MType: models.Gauge, Value: floatPtr(1.5)},
+			},
+		},
+		{
+			name:        "query error",
+			query:       models.ListQuery{},
+			statement:   selectPrefix + "tenant = $1 ORDER BY id, labels;",
+			args:        []any{middleware.DefaultTenant},
+			mockErr:     errors.New("db error"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectation := mock.ExpectQuery(regexp.QuoteMeta(tt.statement)).WithArgs(tt.args...)
+			if tt.mockErr != nil {
+				expectation.WillReturnError(tt.mockErr)
+			} else {
+				rows := tt.rows
+				if rows == nil {
+					rows = [][]any{metricRow("g1", models.Gauge, nil, 1.5)}
+				}
+				expectation.WillReturnRows(pgxmock.NewRows(metricColumnNames).AddRows(rows...))
+			}
+
+			result, err := repo.List(t.Context(), tt.query)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectData, result)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDBMetricsRepository_GetMany(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBMetricsRepository(mock)
+	assert.NoError(t, err)
+
+	getManyQuery := regexp.QuoteMeta("WHERE tenant = $1 AND (id, labels, type) IN (")
+	refs := []models.SeriesRef{
+		{ID: "c1", MType: models.Counter},
+		{ID: "g1", MType: models.Gauge, Labels: map[string]string{"host": "a"}},
+	}
+	args := []any{
+		middleware.DefaultTenant,
+		[]string{"c1", "g1"},
+		[]string{"{}", `{"host":"a"}`},
+		[]string{models.Counter, models.Gauge},
+	}
+
+	tests := []struct {
+		name        string
+		mockQuery   func()
+		expectData  []models.Metrics
+		expectError bool
+	}{
+		{
+			name: "found series",
+			mockQuery: func() {
+				mock.ExpectQuery(getManyQuery).
+					WithArgs(args...).
+					WillReturnRows(pgxmock.NewRows(metricColumnNames).AddRow(metricRow("c1", models.Counter, int64(3), nil)...))
+			},
+			expectData: []models.Metrics{
+				{Tenant: middleware.DefaultTenant, ID: "c1", MType: models.Counter, Delta: intPtr(3)},
+			},
+		},
+		{
+			name: "query error",
+			mockQuery: func() {
+				mock.ExpectQuery(getManyQuery).
+					WithArgs(args...).
+					WillReturnError(errors.New("db error"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockQuery()
+
+			result, err := repo.GetMany(t.Context(), refs)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectData, result)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// historyQuery matches the bulk insert of recorded points.
+var historyQuery = regexp.QuoteMeta("INSERT INTO metric_history (tenant, id, labels, type, ts, delta, value)")
+
+// historyArgs matches any arguments of the history insert.
+func historyArgs() []any {
+	args := make([]any, 7)
+	for i := range args {
+		args[i] = pgxmock.AnyArg()
+	}
+	return args
+}
+
+// metricColumnNames lists the columns selected by metricColumns.
+var metricColumnNames = strings.Split(metricColumns, ", ")
+
+// metricRow builds a mocked unlabeled default tenant metric row with empty histogram and summary columns.
+func metricRow(id, mtype, delta, value any) []any {
+	return []any{middleware.DefaultTenant, id, mtype, nil, delta, value, nil, nil, nil, nil, nil}
+}
+
+func TestDBMetricsRepository_GetUpdated(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBMetricsRepository(mock)
+	assert.NoError(t, err)
+
+	query := regexp.QuoteMeta("SELECT id, labels, updated_at FROM metric WHERE tenant = $1;")
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	mock.ExpectQuery(query).
+		WithArgs(middleware.DefaultTenant).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "labels", "updated_at"}).
+			AddRow("c1", map[string]string{}, updatedAt).
+			AddRow("g1", map[string]string{"host": "a"}, updatedAt.Add(time.Second)))
+
+	updated, err := repo.GetUpdated(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Time{
+		"c1":           updatedAt,
+		`g1{host="a"}`: updatedAt.Add(time.Second),
+	}, updated)
+
+	mock.ExpectQuery(query).
+		WithArgs(middleware.DefaultTenant).
+		WillReturnError(errors.New("db error"))
+
+	updated, err = repo.GetUpdated(t.Context())
+	assert.Error(t, err)
+	assert.Nil(t, updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/storage"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+)
+
+// IdempotencyRepository defines the interface for idempotency key operations.
+// Keys are partitioned by the tenant of the request context.
+type IdempotencyRepository interface {
+	// Reserve claims a key of the request tenant for the request with the fingerprint
+	// until the expiration time, expired keys are claimed again.
+	// Reports false with the recorded response if the key is already claimed,
+	// the response is pending while the request of the key is processed.
+	Reserve(context.Context, string, string, time.Time) (models.IdempotentResponse, bool, error)
+
+	// Complete records the response of a claimed key of the request tenant.
+	Complete(context.Context, string, models.IdempotentResponse) error
+
+	// Release forgets a claimed key of the request tenant,
+	// so that its request can be repeated.
+	Release(context.Context, string) error
+
+	// Expire forgets keys of all tenants expired at the given time.
+	// Returns the number of forgotten keys.
+	Expire(context.Context, time.Time) (int, error)
+}
+
+// memoryIdempotencyRepository implements IdempotencyRepository using in-memory storage.
+// Provides thread-safe operations through read-write mutex.
+type memoryIdempotencyRepository struct {
+	storage *storage.MemStorage
+	mutex   *sync.RWMutex
+}
+
+// NewMemoryIdempotencyRepository creates a new in-memory idempotency key repository.
+//
+// storage: MemStorage instance for data persistence
+// mutex: Read-write mutex for thread safety (can be shared)
+//
+// Returns:
+//   - IdempotencyRepository: Ready-to-use repository instance
+//   - error: If storage is nil
+func NewMemoryIdempotencyRepository(storage *storage.MemStorage, mutex *sync.RWMutex) (IdempotencyRepository, error) {
+	if storage == nil {
+		return nil, errors.New("create new idempotency repository failed: storage is nil")
+	}
+
+	return &memoryIdempotencyRepository{
+		storage: storage,
+		mutex:   mutex,
+	}, nil
+}
+
+// Reserve claims a key of the request tenant for the request with the fingerprint
+// until the expiration time. Only the claimed key is checked for expiry,
+// other expired keys are left to Expire.
+func (repository *memoryIdempotencyRepository) Reserve(ctx context.Context, key string, fingerprint string, expiresAt time.Time) (models.IdempotentResponse, bool, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	if repository.storage.Idempotency == nil {
+		repository.storage.Idempotency = make(map[string]map[string]models.IdempotentResponse)
+	}
+
+	tenant := middleware.TenantFromCtx(ctx)
+	responses, exists := repository.storage.Idempotency[tenant]
+	if !exists {
+		responses = make(map[string]models.IdempotentResponse)
+		repository.storage.Idempotency[tenant] = responses
+	}
+
+	if response, exists := responses[key]; exists && response.ExpiresAt.After(time.Now()) {
+		return response, false, nil
+	}
+
+	responses[key] = models.IdempotentResponse{Fingerprint: fingerprint, ExpiresAt: expiresAt}
+	return models.IdempotentResponse{}, true, nil
+}
+
+// Complete records the response of a claimed key of the request tenant.
+// Keys that are no longer claimed are ignored.
+func (repository *memoryIdempotencyRepository) Complete(ctx context.Context, key string, response models.IdempotentResponse) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	responses := repository.storage.Idempotency[middleware.TenantFromCtx(ctx)]
+	claimed, exists := responses[key]
+	if !exists {
+		return nil
+	}
+
+	response.Fingerprint = claimed.Fingerprint
+	response.ExpiresAt = claimed.ExpiresAt
+	responses[key] = response
+	return nil
+}
+
+// Release forgets a claimed key of the request tenant.
+func (repository *memoryIdempotencyRepository) Release(ctx context.Context, key string) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	delete(repository.storage.Idempotency[middleware.TenantFromCtx(ctx)], key)
+	return nil
+}
+
+// Expire forgets keys of all tenants expired at the given time.
+func (repository *memoryIdempotencyRepository) Expire(ctx context.Context, now time.Time) (int, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	expired := 0
+	for tenant, responses := range repository.storage.Idempotency {
+		for key, response := range responses {
+			if !response.ExpiresAt.After(now) {
+				delete(responses, key)
+				expired++
+			}
+		}
+		if len(responses) == 0 {
+			delete(repository.storage.Idempotency, tenant)
+		}
+	}
+
+	return expired, nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/storage"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+)
+
+// dbIdempotencyRepository implements IdempotencyRepository using the idempotency_key table.
+type dbIdempotencyRepository struct {
+	storage storage.DB
+}
+
+// NewDBIdempotencyRepository creates a new PostgreSQL-based idempotency key repository.
+//
+// storage: Established SQL database connection (typically PostgreSQL)
+//
+// Returns:
+//   - IdempotencyRepository: Ready-to-use repository instance
+//   - error: If storage connection is nil
+//
+// The repository automatically retries operations on transient database errors.
+func NewDBIdempotencyRepository(s storage.DB) (IdempotencyRepository, error) {
+	if s == nil {
+		return nil, errors.New("create new idempotency repository failed: storage is nil")
+	}
+
+	return &dbIdempotencyRepository{
+		storage: s,
+	}, nil
+}
+
+// Reserve claims a key of the request tenant for the request with the fingerprint
+// until the expiration time. The key is claimed by inserting a pending row,
+// or by replacing the row of an expired key, so that concurrent requests
+// with the same key claim it only once. Other expired keys are left to Expire.
+func (repository *dbIdempotencyRepository) Reserve(ctx context.Context, key string, fingerprint string, expiresAt time.Time) (models.IdempotentResponse, bool, error) {
+	tenant := middleware.TenantFromCtx(ctx)
+
+	var response models.IdempotentResponse
+	var reserved bool
+	err := executeWithRetry(func() error {
+		tag, err := repository.storage.Exec(
+			ctx,
+			`INSERT INTO idempotency_key (tenant, key, fingerprint, expires_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (tenant, key) DO UPDATE SET
+				status = 0, content_type = '', body = NULL,
+				fingerprint = EXCLUDED.fingerprint, expires_at = EXCLUDED.expires_at
+			WHERE idempotency_key.expires_at <= $5;`,
+			tenant, key, fingerprint, expiresAt, time.Now(),
+		)
+		if err != nil {
+			return err
+		}
+
+		if tag.RowsAffected() > 0 {
+			response, reserved = models.IdempotentResponse{}, true
+			return nil
+		}
+
+		reserved = false
+		err = repository.storage.QueryRow(
+			ctx,
+			"SELECT status, content_type, body, fingerprint, expires_at FROM idempotency_key WHERE tenant = $1 AND key = $2;",
+			tenant, key,
+		).Scan(&response.Status, &response.ContentType, &response.Body, &response.Fingerprint, &response.ExpiresAt)
+
+		// The key was deleted between the insert and the select, its request is treated as pending.
+		if errors.Is(err, pgx.ErrNoRows) {
+			response = models.IdempotentResponse{}
+			return nil
+		}
+		return err
+	})
+
+	if err != nil {
+		return models.IdempotentResponse{}, false, err
+	}
+	return response, reserved, nil
+}
+
+// Complete records the response of a claimed key of the request tenant.
+// Keys that are no longer claimed are ignored.
+func (repository *dbIdempotencyRepository) Complete(ctx context.Context, key string, response models.IdempotentResponse) error {
+	return executeWithRetry(func() error {
+		_, err := repository.storage.Exec(
+			ctx,
+			"UPDATE idempotency_key SET status = $3, content_type = $4, body = $5 WHERE tenant = $1 AND key = $2;",
+			middleware.TenantFromCtx(ctx), key, response.Status, response.ContentType, response.Body,
+		)
+		return err
+	})
+}
+
+// Release forgets a claimed key of the request tenant.
+func (repository *dbIdempotencyRepository) Release(ctx context.Context, key string) error {
+	return executeWithRetry(func() error {
+		_, err := repository.storage.Exec(
+			ctx,
+			"DELETE FROM idempotency_key WHERE tenant = $1 AND key = $2;",
+			middleware.TenantFromCtx(ctx), key,
+		)
+		return err
+	})
+}
+
+// Expire deletes keys of all tenants expired at the given time.
+func (repository *dbIdempotencyRepository) Expire(ctx context.Context, now time.Time) (int, error) {
+	var expired int
+	err := executeWithRetry(func() error {
+		tag, err := repository.storage.Exec(
+			ctx,
+			"DELETE FROM idempotency_key WHERE expires_at <= $1;",
+			now,
+		)
+		if err != nil {
+			return err
+		}
+
+		expired = int(tag.RowsAffected())
+		return nil
+	})
+
+	return expired, err
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/storage"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDBIdempotencyRepository(t *testing.T) {
+	tests := []struct {
+		name        string
+		storage     storage.DB
+		expectError bool
+	}{
+		{
+			name:        "valid db connection",
+			storage:     storage.NewMockDB(t),
+			expectError: false,
+		},
+		{
+			name:        "nil storage",
+			storage:     nil,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := NewDBIdempotencyRepository(tt.storage)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, repo)
+				assert.Contains(t, err.Error(), "storage is nil")
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, repo)
+			}
+		})
+	}
+}
+
+func TestDBIdempotencyRepository_Reserve(t *testing.T) {
+	expiresAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fingerprint := "POST /updates/ a"
+
+	tests := []struct {
+		name             string
+		setupMock        func(mock pgxmock.PgxPoolIface)
+		expectedResponse models.IdempotentResponse
+		expectedReserved bool
+		expectError      bool
+	}{
+		{
+			name: "new or expired key",
+			setupMock: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO idempotency_key (tenant, key, fingerprint, expires_at)")).
+					WithArgs("team-a", "batch-1", fingerprint, expiresAt, pgxmock.AnyArg()).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+			},
+			expectedReserved: true,
+		},
+		{
+			name: "completed key",
+			setupMock: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(regexp.QuoteMeta("WHERE idempotency_key.expires_at <= $5;")).
+					WithArgs("team-a", "batch-1", fingerprint, expiresAt, pgxmock.AnyArg()).
+					WillReturnResult(pgxmock.NewResult("INSERT", 0))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT status, content_type, body, fingerprint, expires_at FROM idempotency_key")).
+					WithArgs("team-a", "batch-1").
+					WillReturnRows(pgxmock.NewRows([]string{"status", "content_type", "body", "fingerprint", "expires_at"}).
+						AddRow(200, "application/json", []byte(`{}`), fingerprint, expiresAt))
+			},
+			expectedResponse: models.IdempotentResponse{
+				Status: 200, ContentType: "application/json", Body: []byte(`{}`), Fingerprint: fingerprint, ExpiresAt: expiresAt,
+			},
+		},
+		{
+			name: "key deleted concurrently",
+			setupMock: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO idempotency_key")).
+					WithArgs("team-a", "batch-1", fingerprint, expiresAt, pgxmock.AnyArg()).
+					WillReturnResult(pgxmock.NewResult("INSERT", 0))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT status, content_type, body, fingerprint, expires_at FROM idempotency_key")).
+					WithArgs("team-a", "batch-1").
+					WillReturnError(pgx.ErrNoRows)
+			},
+		},
+		{
+			name: "database error",
+			setupMock: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO idempotency_key")).
+					WithArgs("team-a", "batch-1", fingerprint, expiresAt, pgxmock.AnyArg()).
+					WillReturnError(errors.New("db error"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			tt.setupMock(mock)
+
+			repo, err := NewDBIdempotencyRepository(mock)
+			require.NoError(t, err)
+
+			response, reserved, err := repo.Reserve(middleware.WithTenant(t.Context(), "team-a"), "batch-1", fingerprint, expiresAt)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedReserved, reserved)
+				assert.Equal(t, tt.expectedResponse, response)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDBIdempotencyRepository_Complete(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBIdempotencyRepository(mock)
+	require.NoError(t, err)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE idempotency_key SET status = $3, content_type = $4, body = $5 WHERE tenant = $1 AND key = $2;")).
+		WithArgs(middleware.DefaultTenant, "batch-1", 200, "application/json", []byte(`{}`)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	err = repo.Complete(t.Context(), "batch-1", models.IdempotentResponse{Status: 200, ContentType: "application/json", Body: []byte(`{}`)})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDBIdempotencyRepository_Release(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBIdempotencyRepository(mock)
+	require.NoError(t, err)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM idempotency_key WHERE tenant = $1 AND key = $2;")).
+		WithArgs(middleware.DefaultTenant, "batch-1").
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+	assert.NoError(t, repo.Release(t.Context(), "batch-1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDBIdempotencyRepository_Expire(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		setupMock       func(mock pgxmock.PgxPoolIface)
+		expectedExpired int
+		expectError     bool
+	}{
+		{
+			name: "expired keys of all tenants",
+			setupMock: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM idempotency_key WHERE expires_at <= $1;")).
+					WithArgs(now).
+					WillReturnResult(pgxmock.NewResult("DELETE", 3))
+			},
+			expectedExpired: 3,
+		},
+		{
+			name: "database error",
+			setupMock: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM idempotency_key")).
+					WithArgs(now).
+					WillReturnError(errors.New("db error"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			tt.setupMock(mock)
+
+			repo, err := NewDBIdempotencyRepository(mock)
+			require.NoError(t, err)
+
+			expired, err := repo.Expire(t.Context(), now)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedExpired, expired)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/storage"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMemoryIdempotencyRepository(t *testing.T) {
+	tests := []struct {
+		name        string
+		storage     *storage.MemStorage
+		expectError bool
+	}{
+		{
+			name:        "valid storage",
+			storage:     storage.NewMemStorage(),
+			expectError: false,
+		},
+		{
+			name:        "nil storage",
+			storage:     nil,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := NewMemoryIdempotencyRepository(tt.storage, &sync.RWMutex{})
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, repo)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, repo)
+			}
+		})
+	}
+}
+
+func TestMemoryIdempotencyRepository_ReserveComplete(t *testing.T) {
+	repo, err := NewMemoryIdempotencyRepository(storage.NewMemStorage(), &sync.RWMutex{})
+	require.NoError(t, err)
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	_, reserved, err := repo.Reserve(t.Context(), "batch-1", "POST /updates/ a", expiresAt)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+
+	response, reserved, err := repo.Reserve(t.Context(), "batch-1", "POST /updates/ b", expiresAt)
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	assert.True(t, response.Pending())
+	assert.Equal(t, "POST /updates/ a", response.Fingerprint, "the fingerprint of the claiming request is kept")
+
+	_, reserved, err = repo.Reserve(middleware.WithTenant(t.Context(), "team-a"), "batch-1", "POST /updates/ a", expiresAt)
+	require.NoError(t, err)
+	assert.True(t, reserved, "keys of other tenants are independent")
+
+	completed := models.IdempotentResponse{Status: 200, ContentType: "application/json", Body: []byte(`{"accepted":1}`)}
+	require.NoError(t, repo.Complete(t.Context(), "batch-1", completed))
+
+	response, reserved, err = repo.Reserve(t.Context(), "batch-1", "POST /updates/ a", expiresAt)
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	completed.Fingerprint = "POST /updates/ a"
+	completed.ExpiresAt = expiresAt
+	assert.Equal(t, completed, response)
+}
+
+func TestMemoryIdempotencyRepository_Release(t *testing.T) {
+	repo, err := NewMemoryIdempotencyRepository(storage.NewMemStorage(), &sync.RWMutex{})
+	require.NoError(t, err)
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	_, reserved, err := repo.Reserve(t.Context(), "batch-1", "POST /updates/ a", expiresAt)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	require.NoError(t, repo.Release(t.Context(), "batch-1"))
+	require.NoError(t, repo.Complete(t.Context(), "batch-1", models.IdempotentResponse{Status: 200}))
+
+	_, reserved, err = repo.Reserve(t.Context(), "batch-1", "POST /updates/ a", expiresAt)
+	require.NoError(t, err)
+	assert.True(t, reserved, "released keys are not completed and can be claimed again")
+}
+
+func TestMemoryIdempotencyRepository_expired(t *testing.T) {
+	repo, err := NewMemoryIdempotencyRepository(storage.NewMemStorage(), &sync.RWMutex{})
+	require.NoError(t, err)
+
+	_, reserved, err := repo.Reserve(t.Context(), "old", "POST /updates/ a", time.Now().Add(-time.Second))
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	_, reserved, err = repo.Reserve(t.Context(), "old", "POST /updates/ b", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, reserved, "expired keys can be claimed again")
+
+	response, reserved, err := repo.Reserve(t.Context(), "old", "POST /updates/ a", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, "POST /updates/ b", response.Fingerprint)
+}
+
+func TestMemoryIdempotencyRepository_Expire(t *testing.T) {
+	memStorage := storage.NewMemStorage()
+	repo, err := NewMemoryIdempotencyRepository(memStorage, &sync.RWMutex{})
+	require.NoError(t, err)
+
+	now := time.Now()
+	teamA := middleware.WithTenant(t.Context(), "team-a")
+
+	for _, claim := range []struct {
+		ctx       context.Context
+		key       string
+		expiresAt time.Time
+	}{
+		{ctx: t.Context(), key: "old", expiresAt: now.Add(-time.Second)},
+		{ctx: t.Context(), key: "new", expiresAt: now.Add(time.Hour)},
+		{ctx: teamA, key: "old", expiresAt: now},
+	} {
+		_, reserved, err := repo.Reserve(claim.ctx, claim.key, "POST /updates/ a", claim.expiresAt)
+		require.NoError(t, err)
+		require.True(t, reserved)
+	}
+
+	expired, err := repo.Expire(t.Context(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, expired)
+
+	assert.Equal(t, []string{"new"}, slices.Collect(maps.Keys(memStorage.Idempotency[middleware.DefaultTenant])))
+	assert.NotContains(t, memStorage.Idempotency, "team-a", "tenants without keys are forgotten")
+}
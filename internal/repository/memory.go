@@ -1,18 +1,31 @@
 package repository
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
+	"time"
 
 	models "github.com/gabkaclassic/metrics/internal/model"
 	"github.com/gabkaclassic/metrics/internal/storage"
 	"github.com/gabkaclassic/metrics/pkg/metric"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
 )
 
+// ErrTypeMismatch is returned by write operations when a series
+// is already stored with another metric type.
+var ErrTypeMismatch = errors.New("metric type mismatch")
+
 // MetricsRepository defines the interface for metric data operations.
 // Implementations provide persistence-agnostic access to metrics.
+// Metrics are partitioned by tenant: every operation is scoped to the tenant
+// carried by the context (see middleware.TenantFromCtx), except
+// GetAllMetrics, which spans all tenants.
+// Write operations return ErrTypeMismatch if a written series
+// is stored with another type.
 type MetricsRepository interface {
 	// Add increments a counter metric or adds a new metric.
 	// For counter metrics, adds delta to existing value.
@@ -31,17 +44,83 @@ type MetricsRepository interface {
 	// Creates the metric if it doesn't exist.
 	ResetOne(context.Context, models.Metrics) error
 
-	// Get retrieves a single metric by its ID.
-	// Returns error if metric not found.
-	Get(context.Context, string) (*models.Metrics, error)
+	// Increment atomically adds the gauge increment to the stored gauge value.
+	// Creates the metric starting from zero if it doesn't exist.
+	Increment(context.Context, models.Metrics) error
+
+	// IncrementAll performs batch increment of gauge metrics,
+	// applying increments in slice order.
+	IncrementAll(context.Context, []models.Metrics) error
+
+	// Merge combines a histogram, summary or set metric with the stored one.
+	// Histogram buckets, sum and count are added; bounds must match.
+	// Summary sketches are merged; relative accuracy must match.
+	// Set HyperLogLogs are merged; precision must match, members
+	// must already be folded into the sketch with models.NormalizeSet.
+	// Creates the metric if it doesn't exist.
+	Merge(context.Context, models.Metrics) error
+
+	// MergeAll performs batch merge of histogram, summary and set metrics.
+	MergeAll(context.Context, []models.Metrics) error
 
-	// GetAll returns all metrics as a map of ID to value.
-	// Counter metrics return int64, gauge metrics return float64.
+	// Get retrieves a single metric series by its ID and labels.
+	// Nil labels select the unlabeled series.
+	// Returns error if metric not found.
+	Get(context.Context, string, map[string]string) (*models.Metrics, error)
+
+	// GetAll returns all metrics as a map of series key to value.
+	// Series keys are built with models.SeriesKey.
+	// Counter metrics return int64, gauge metrics return float64,
+	// histogram metrics return models.HistogramSnapshot,
+	// summary metrics return models.SummarySnapshot,
+	// set metrics return the int64 cardinality estimate.
 	GetAll(context.Context) (map[string]any, error)
 
-	// GetAllMetrics returns all metrics as a slice of models.Metrics.
-	// Preserves complete metric structure including type and hash.
+	// List returns series selected by the query filters in the query order,
+	// starting after query.After and limited to query.Limit series.
+	// Label sets are ordered consistently within an implementation.
+	List(context.Context, models.ListQuery) ([]models.Metrics, error)
+
+	// GetMany retrieves the referenced series in a single lookup.
+	// Series that don't exist or have another type are omitted,
+	// the order of the result is not guaranteed.
+	GetMany(context.Context, []models.SeriesRef) ([]models.Metrics, error)
+
+	// GetTypes returns the stored types of the referenced series
+	// keyed by series key, regardless of the referenced type.
+	// Series that don't exist are omitted.
+	GetTypes(context.Context, []models.SeriesRef) (map[string]string, error)
+
+	// GetAllMetrics returns metrics of all tenants as a slice of models.Metrics.
+	// Preserves complete metric structure including type and hash,
+	// with Tenant set to the owning tenant.
 	GetAllMetrics(context.Context) ([]models.Metrics, error)
+
+	// Delete removes all series of the metrics selected by the query
+	// and their history.
+	// Returns the deleted series.
+	Delete(context.Context, models.DeleteQuery) ([]models.Metrics, error)
+
+	// Expire removes series of all tenants that were not updated
+	// within their TTL, together with their history.
+	// Returns the expired series with Tenant set to the owning tenant.
+	Expire(context.Context, models.ExpirePolicy) ([]models.Metrics, error)
+
+	// PruneHistory removes recorded points of all tenants
+	// with timestamps before the given time.
+	// Returns the number of removed points.
+	PruneHistory(context.Context, time.Time) (int, error)
+
+	// GetRange returns recorded points of a counter or gauge series
+	// within the query interval in chronological order.
+	// Every accepted counter delta and gauge value is recorded
+	// by Add, AddAll, ResetOne and ResetAll, gauges changed by Increment
+	// and IncrementAll record the resulting value.
+	GetRange(context.Context, models.RangeQuery) ([]models.Point, error)
+
+	// GetUpdated returns the last update time of every series
+	// of the request tenant keyed by series key.
+	GetUpdated(context.Context) (map[string]time.Time, error)
 }
 
 // memoryMetricsRepository implements MetricsRepository using in-memory storage.
@@ -74,76 +153,215 @@ func NewMemoryMetricsRepository(storage *storage.MemStorage, mutex *sync.RWMutex
 	}, nil
 }
 
-// GetAllMetrics returns all stored metrics as a slice.
+// GetAllMetrics returns stored metrics of all tenants as a slice under the read lock.
 // Order of metrics in the slice is not guaranteed.
 func (repository *memoryMetricsRepository) GetAllMetrics(ctx context.Context) ([]models.Metrics, error) {
-	metrics := make([]models.Metrics, len(repository.storage.Metrics))
-	index := 0
-	for _, m := range repository.storage.Metrics {
-		metrics[index] = m
-		index += 1
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	metrics := make([]models.Metrics, 0)
+	for tenant, series := range repository.storage.Metrics {
+		for _, m := range series {
+			m.Tenant = tenant
+			metrics = append(metrics, m)
+		}
 	}
 
 	return metrics, nil
 }
 
-// GetAll returns all metrics as a map of metric ID to value.
-// Counter metrics are returned as int64, gauge metrics as float64.
+// GetAll returns all metrics as a map of series key to value.
+// Counter metrics are returned as int64, gauge metrics as float64,
+// histogram metrics as models.HistogramSnapshot,
+// summary metrics as models.SummarySnapshot,
+// set metrics as int64 cardinality estimates.
+// Values are read under the read lock.
 func (repository *memoryMetricsRepository) GetAll(ctx context.Context) (map[string]any, error) {
-	metrics := make(map[string]any, len(repository.storage.Metrics))
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
 
-	for id, m := range repository.storage.Metrics {
+	series := repository.storage.Metrics[middleware.TenantFromCtx(ctx)]
+	metrics := make(map[string]any, len(series))
+
+	for key, m := range series {
 		switch m.MType {
 		case string(metric.CounterType):
-			metrics[id] = *m.Delta
+			metrics[key] = *m.Delta
 		case string(metric.GaugeType):
-			metrics[id] = *m.Value
+			metrics[key] = *m.Value
+		case string(metric.HistogramType):
+			metrics[key] = m.Snapshot()
+		case string(metric.SummaryType):
+			metrics[key] = m.SummarySnapshot()
+		case string(metric.SetType):
+			metrics[key] = m.Cardinality()
 		}
 	}
 
 	return metrics, nil
 }
 
-// Get retrieves a metric by its ID.
-// Returns error if metric with given ID doesn't exist.
-func (repository *memoryMetricsRepository) Get(ctx context.Context, metricID string) (*models.Metrics, error) {
-	metric, exists := repository.storage.Metrics[metricID]
+// Get retrieves a metric series by its ID and labels under the read lock.
+// Returns error if the series doesn't exist.
+func (repository *memoryMetricsRepository) Get(ctx context.Context, metricID string, labels map[string]string) (*models.Metrics, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	key := models.SeriesKey(metricID, labels)
+	metric, exists := repository.storage.Metrics[middleware.TenantFromCtx(ctx)][key]
 
 	if !exists {
-		return nil, fmt.Errorf("metric %s not found", metricID)
+		return nil, fmt.Errorf("metric %s not found", key)
 	}
 
 	return &metric, nil
 }
 
+// List returns series of the context tenant selected by the query under the read lock.
+// Series are filtered and sorted in memory, labels are ordered by series key.
+func (repository *memoryMetricsRepository) List(ctx context.Context, query models.ListQuery) ([]models.Metrics, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	matches := query.Matcher()
+	metrics := make([]models.Metrics, 0)
+	for _, metric := range repository.storage.Metrics[middleware.TenantFromCtx(ctx)] {
+		if !matches(metric.ID, metric.MType) {
+			continue
+		}
+		if query.After != nil && compareSeries(query, metric.Ref(), *query.After) <= 0 {
+			continue
+		}
+		metrics = append(metrics, metric)
+	}
+
+	slices.SortFunc(metrics, func(a, b models.Metrics) int {
+		return compareSeries(query, a.Ref(), b.Ref())
+	})
+
+	if query.Limit > 0 && len(metrics) > query.Limit {
+		metrics = metrics[:query.Limit]
+	}
+
+	return metrics, nil
+}
+
+// compareSeries compares two series in the order of the query,
+// so that a is listed before b if the result is negative.
+func compareSeries(query models.ListQuery, a, b models.SeriesRef) int {
+	result := 0
+	if query.SortBy == models.SortByType {
+		result = cmp.Compare(a.MType, b.MType)
+	}
+	if result == 0 {
+		result = cmp.Compare(a.ID, b.ID)
+	}
+	if result == 0 {
+		result = cmp.Compare(a.Key(), b.Key())
+	}
+
+	if query.Descending {
+		return -result
+	}
+	return result
+}
+
+// GetMany retrieves the referenced series of the context tenant under the read lock.
+func (repository *memoryMetricsRepository) GetMany(ctx context.Context, refs []models.SeriesRef) ([]models.Metrics, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	series := repository.storage.Metrics[middleware.TenantFromCtx(ctx)]
+	metrics := make([]models.Metrics, 0, len(refs))
+	for _, ref := range refs {
+		if metric, exists := series[ref.Key()]; exists && metric.MType == ref.MType {
+			metrics = append(metrics, metric)
+		}
+	}
+
+	return metrics, nil
+}
+
+// GetTypes returns the stored types of the referenced series
+// of the context tenant under the read lock.
+func (repository *memoryMetricsRepository) GetTypes(ctx context.Context, refs []models.SeriesRef) (map[string]string, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	series := repository.storage.Metrics[middleware.TenantFromCtx(ctx)]
+	types := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		if metric, exists := series[ref.Key()]; exists {
+			types[ref.Key()] = metric.MType
+		}
+	}
+
+	return types, nil
+}
+
 // updateMetric executes a metric update operation with thread safety.
-// Acquires write lock and ensures storage map is initialized.
-func (repository *memoryMetricsRepository) updateMetric(_ context.Context, metric models.Metrics, updateMetricFunction func(metric models.Metrics) error) error {
+// Acquires write lock and passes the series of the context tenant,
+// initializing storage maps as needed.
+func (repository *memoryMetricsRepository) updateMetric(ctx context.Context, metric models.Metrics, updateMetricFunction func(tenant tenantPartition, metric models.Metrics) error) error {
 	repository.mutex.Lock()
 	defer repository.mutex.Unlock()
 
-	if repository.storage.Metrics == nil {
-		repository.storage.Metrics = make(map[string]models.Metrics)
-	}
-
-	err := updateMetricFunction(metric)
+	err := updateMetricFunction(repository.partition(ctx), metric)
 
 	return err
 }
 
 // updateMetrics executes a batch metrics update with thread safety.
-// Acquires write lock and ensures storage map is initialized.
-func (repository *memoryMetricsRepository) updateMetrics(_ context.Context, metrics []models.Metrics, updateMetricsFunction func(metric []models.Metrics) error) error {
+// Acquires write lock and passes the series of the context tenant,
+// initializing storage maps as needed.
+func (repository *memoryMetricsRepository) updateMetrics(ctx context.Context, metrics []models.Metrics, updateMetricsFunction func(tenant tenantPartition, metric []models.Metrics) error) error {
 	repository.mutex.Lock()
 	defer repository.mutex.Unlock()
 
+	err := updateMetricsFunction(repository.partition(ctx), metrics)
+
+	return err
+}
+
+// tenantPartition holds the series, history and update times of a single tenant.
+type tenantPartition struct {
+	metrics     map[string]models.Metrics
+	history     map[string]*storage.History
+	updated     map[string]time.Time
+	historySize int
+}
+
+// partition returns the storage partition of the context tenant.
+// Must be called with the write lock held.
+func (repository *memoryMetricsRepository) partition(ctx context.Context) tenantPartition {
+	tenant := middleware.TenantFromCtx(ctx)
+
 	if repository.storage.Metrics == nil {
-		repository.storage.Metrics = make(map[string]models.Metrics)
+		repository.storage.Metrics = make(map[string]map[string]models.Metrics)
+	}
+	if repository.storage.History == nil {
+		repository.storage.History = make(map[string]map[string]*storage.History)
+	}
+	if repository.storage.Updated == nil {
+		repository.storage.Updated = make(map[string]map[string]time.Time)
 	}
 
-	err := updateMetricsFunction(metrics)
+	if repository.storage.Metrics[tenant] == nil {
+		repository.storage.Metrics[tenant] = make(map[string]models.Metrics)
+	}
+	if repository.storage.History[tenant] == nil {
+		repository.storage.History[tenant] = make(map[string]*storage.History)
+	}
+	if repository.storage.Updated[tenant] == nil {
+		repository.storage.Updated[tenant] = make(map[string]time.Time)
+	}
 
-	return err
+	return tenantPartition{
+		metrics:     repository.storage.Metrics[tenant],
+		history:     repository.storage.History[tenant],
+		updated:     repository.storage.Updated[tenant],
+		historySize: repository.storage.HistorySize,
+	}
 }
 
 // Add increments a counter metric or adds a new metric.
@@ -153,12 +371,12 @@ func (repository *memoryMetricsRepository) Add(ctx context.Context, metric model
 	err := repository.updateMetric(
 		ctx,
 		metric,
-		func(metric models.Metrics) error {
-			if savedMetric, exists := repository.storage.Metrics[metric.ID]; exists {
-				*savedMetric.Delta = *(savedMetric.Delta) + *(metric.Delta)
-			} else {
-				repository.storage.Metrics[metric.ID] = metric
+		func(tenant tenantPartition, metric models.Metrics) error {
+			if err := tenant.checkTypes(metric); err != nil {
+				return err
 			}
+			tenant.addCounter(metric)
+			tenant.record(metric)
 			return nil
 		},
 	)
@@ -172,13 +390,13 @@ func (repository *memoryMetricsRepository) AddAll(ctx context.Context, metrics [
 	err := repository.updateMetrics(
 		ctx,
 		metrics,
-		func(metrics []models.Metrics) error {
+		func(tenant tenantPartition, metrics []models.Metrics) error {
+			if err := tenant.checkTypes(metrics...); err != nil {
+				return err
+			}
 			for _, metric := range metrics {
-				if savedMetric, exists := repository.storage.Metrics[metric.ID]; exists {
-					*savedMetric.Delta = *(savedMetric.Delta) + *(metric.Delta)
-				} else {
-					repository.storage.Metrics[metric.ID] = metric
-				}
+				tenant.addCounter(metric)
+				tenant.record(metric)
 			}
 			return nil
 		},
@@ -193,12 +411,12 @@ func (repository *memoryMetricsRepository) ResetOne(ctx context.Context, metric
 	err := repository.updateMetric(
 		ctx,
 		metric,
-		func(metric models.Metrics) error {
-			if savedMetric, exists := repository.storage.Metrics[metric.ID]; exists {
-				*savedMetric.Value = *(metric.Value)
-			} else {
-				repository.storage.Metrics[metric.ID] = metric
+		func(tenant tenantPartition, metric models.Metrics) error {
+			if err := tenant.checkTypes(metric); err != nil {
+				return err
 			}
+			tenant.resetGauge(metric)
+			tenant.record(metric)
 			return nil
 		},
 	)
@@ -212,12 +430,140 @@ func (repository *memoryMetricsRepository) ResetAll(ctx context.Context, metrics
 	err := repository.updateMetrics(
 		ctx,
 		metrics,
-		func(metrics []models.Metrics) error {
+		func(tenant tenantPartition, metrics []models.Metrics) error {
+			if err := tenant.checkTypes(metrics...); err != nil {
+				return err
+			}
+			for _, metric := range metrics {
+				tenant.resetGauge(metric)
+				tenant.record(metric)
+			}
+			return nil
+		},
+	)
+
+	return err
+}
+
+// addCounter adds the counter delta to the tenant series,
+// creating the series if it doesn't exist.
+// The stored delta is replaced rather than written through,
+// so metrics returned to readers are never modified.
+// Must be called with the write lock held.
+func (tenant tenantPartition) addCounter(metric models.Metrics) {
+	if savedMetric, exists := tenant.metrics[metric.Key()]; exists {
+		delta := *savedMetric.Delta + *metric.Delta
+		savedMetric.Delta = &delta
+		tenant.metrics[metric.Key()] = savedMetric
+	} else {
+		tenant.metrics[metric.Key()] = metric
+	}
+}
+
+// resetGauge sets the gauge value of the tenant series,
+// creating the series if it doesn't exist.
+// The stored value is replaced rather than written through,
+// so metrics returned to readers are never modified.
+// Must be called with the write lock held.
+func (tenant tenantPartition) resetGauge(metric models.Metrics) {
+	if savedMetric, exists := tenant.metrics[metric.Key()]; exists {
+		value := *metric.Value
+		savedMetric.Value = &value
+		tenant.metrics[metric.Key()] = savedMetric
+	} else {
+		tenant.metrics[metric.Key()] = metric
+	}
+}
+
+// Increment adds the gauge increment to the stored value under the write lock.
+// Creates the metric starting from zero if it doesn't exist.
+func (repository *memoryMetricsRepository) Increment(ctx context.Context, metric models.Metrics) error {
+	err := repository.updateMetric(
+		ctx,
+		metric,
+		func(tenant tenantPartition, metric models.Metrics) error {
+			if err := tenant.checkTypes(metric); err != nil {
+				return err
+			}
+			tenant.incrementGauge(metric)
+			return nil
+		},
+	)
+
+	return err
+}
+
+// IncrementAll performs batch increment of gauge metrics in slice order.
+func (repository *memoryMetricsRepository) IncrementAll(ctx context.Context, metrics []models.Metrics) error {
+	err := repository.updateMetrics(
+		ctx,
+		metrics,
+		func(tenant tenantPartition, metrics []models.Metrics) error {
+			if err := tenant.checkTypes(metrics...); err != nil {
+				return err
+			}
+			for _, metric := range metrics {
+				tenant.incrementGauge(metric)
+			}
+			return nil
+		},
+	)
+
+	return err
+}
+
+// incrementGauge adds the gauge increment to the tenant series
+// and records the resulting value.
+// Must be called with the write lock held.
+func (tenant tenantPartition) incrementGauge(metric models.Metrics) {
+	value := *metric.Increment
+	if savedMetric, exists := tenant.metrics[metric.Key()]; exists && savedMetric.Value != nil {
+		value += *savedMetric.Value
+	}
+
+	updated := models.Metrics{
+		ID:        metric.ID,
+		MType:     models.Gauge,
+		Labels:    metric.Labels,
+		Value:     &value,
+		Timestamp: metric.Timestamp,
+	}
+
+	tenant.metrics[metric.Key()] = updated
+	tenant.record(updated)
+}
+
+// Merge combines a histogram, summary or set metric with the stored one.
+// Creates the metric if it doesn't exist.
+func (repository *memoryMetricsRepository) Merge(ctx context.Context, metric models.Metrics) error {
+	err := repository.updateMetric(
+		ctx,
+		metric,
+		func(tenant tenantPartition, metric models.Metrics) error {
+			if err := tenant.checkTypes(metric); err != nil {
+				return err
+			}
+			return tenant.mergeMetric(metric)
+		},
+	)
+
+	return err
+}
+
+// MergeAll performs batch merge of histogram, summary and set metrics.
+// Nothing is merged if a series is stored with another type,
+// otherwise stops on the first merge error; earlier metrics stay merged.
+func (repository *memoryMetricsRepository) MergeAll(ctx context.Context, metrics []models.Metrics) error {
+	err := repository.updateMetrics(
+		ctx,
+		metrics,
+		func(tenant tenantPartition, metrics []models.Metrics) error {
+			if err := tenant.checkTypes(metrics...); err != nil {
+				return err
+			}
 			for _, metric := range metrics {
-				if savedMetric, exists := repository.storage.Metrics[metric.ID]; exists {
-					*savedMetric.Value = *(metric.Value)
-				} else {
-					repository.storage.Metrics[metric.ID] = metric
+				if err := tenant.mergeMetric(metric); err != nil {
+					return err
 				}
 			}
 			return nil
@@ -226,3 +572,177 @@ func (repository *memoryMetricsRepository) ResetAll(ctx context.Context, metrics
 
 	return err
 }
+
+// mergeMetric merges a single histogram, summary or set into the tenant series.
+// Must be called with the write lock held.
+func (tenant tenantPartition) mergeMetric(metric models.Metrics) error {
+	savedMetric, exists := tenant.metrics[metric.Key()]
+	if !exists {
+		savedMetric = emptyMergeable(metric)
+	}
+
+	merged, err := models.Merge(savedMetric, metric)
+	if err != nil {
+		return err
+	}
+
+	tenant.metrics[metric.Key()] = merged
+	tenant.updated[metric.Key()] = time.Now()
+	return nil
+}
+
+// checkTypes returns ErrTypeMismatch if a series of the metrics
+// is stored with another type, so that batches are rejected as a whole.
+// Must be called with the write lock held.
+func (tenant tenantPartition) checkTypes(metrics ...models.Metrics) error {
+	for _, metric := range metrics {
+		if savedMetric, exists := tenant.metrics[metric.Key()]; exists && savedMetric.MType != metric.MType {
+			return typeMismatch(metric.Key(), savedMetric.MType)
+		}
+	}
+	return nil
+}
+
+// typeMismatch wraps ErrTypeMismatch with the series key and its stored type.
+func typeMismatch(key string, storedType string) error {
+	return fmt.Errorf("%w: metric %s has type %s", ErrTypeMismatch, key, storedType)
+}
+
+// record appends the metric value to the history of its series
+// and marks the series as updated.
+// Must be called with the write lock held.
+func (tenant tenantPartition) record(metric models.Metrics) {
+	key := metric.Key()
+	tenant.updated[key] = time.Now()
+	history, exists := tenant.history[key]
+	if !exists {
+		history = storage.NewHistory(tenant.historySize)
+		tenant.history[key] = history
+	}
+
+	history.Append(metric.Point())
+}
+
+// Delete removes all series of the context tenant selected by the query
+// under the write lock.
+func (repository *memoryMetricsRepository) Delete(ctx context.Context, query models.DeleteQuery) ([]models.Metrics, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	tenant := repository.partition(ctx)
+	deleted := make([]models.Metrics, 0)
+
+	for key, metric := range tenant.metrics {
+		if query.Matches(metric.ID, metric.MType) {
+			tenant.remove(key)
+			deleted = append(deleted, metric)
+		}
+	}
+
+	return deleted, nil
+}
+
+// Expire removes stale series of all tenants under the write lock.
+// Series without a known update time are kept.
+func (repository *memoryMetricsRepository) Expire(ctx context.Context, policy models.ExpirePolicy) ([]models.Metrics, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	expired := make([]models.Metrics, 0)
+
+	for tenantName := range repository.storage.Metrics {
+		tenant := repository.partition(middleware.WithTenant(ctx, tenantName))
+
+		for key, metric := range tenant.metrics {
+			updated, exists := tenant.updated[key]
+			if !exists || !policy.Expired(tenantName, metric.ID, updated) {
+				continue
+			}
+
+			tenant.remove(key)
+			metric.Tenant = tenantName
+			expired = append(expired, metric)
+		}
+	}
+
+	return expired, nil
+}
+
+// PruneHistory removes recorded points of all tenants before the given time.
+func (repository *memoryMetricsRepository) PruneHistory(ctx context.Context, before time.Time) (int, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	pruned := 0
+	for _, histories := range repository.storage.History {
+		for _, history := range histories {
+			pruned += history.Prune(before.UnixMilli())
+		}
+	}
+
+	return pruned, nil
+}
+
+// remove deletes the series with its history and update time.
+// Must be called with the write lock held.
+func (tenant tenantPartition) remove(key string) {
+	delete(tenant.metrics, key)
+	delete(tenant.history, key)
+	delete(tenant.updated, key)
+}
+
+// GetRange returns recorded points of a series within the query interval.
+// Returns an empty slice if the series has no history or has another type.
+func (repository *memoryMetricsRepository) GetRange(ctx context.Context, query models.RangeQuery) ([]models.Point, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	key := models.SeriesKey(query.ID, query.Labels)
+	tenant := middleware.TenantFromCtx(ctx)
+
+	metric, exists := repository.storage.Metrics[tenant][key]
+	if !exists || metric.MType != query.MType {
+		return []models.Point{}, nil
+	}
+
+	history, exists := repository.storage.History[tenant][key]
+	if !exists {
+		return []models.Point{}, nil
+	}
+
+	return history.Range(query.From.UnixMilli(), query.To.UnixMilli()), nil
+}
+
+// GetUpdated returns the last update time of every series
+// of the request tenant keyed by series key.
+func (repository *memoryMetricsRepository) GetUpdated(ctx context.Context) (map[string]time.Time, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	tenant := middleware.TenantFromCtx(ctx)
+	updated := make(map[string]time.Time, len(repository.storage.Metrics[tenant]))
+	for key := range repository.storage.Metrics[tenant] {
+		if at, exists := repository.storage.Updated[tenant][key]; exists {
+			updated[key] = at
+		}
+	}
+
+	return updated, nil
+}
+
+// emptyMergeable returns an empty metric of the same series and shape
+// to merge the first value of a histogram, summary or set into.
+func emptyMergeable(metric models.Metrics) models.Metrics {
+	empty := models.Metrics{
+		ID:     metric.ID,
+		MType:  metric.MType,
+		Labels: metric.Labels,
+	}
+
+	if metric.MType == models.Histogram {
+		empty.Bounds = metric.Bounds
+		empty.Buckets = make([]int64, len(metric.Bounds)+1)
+	}
+
+	return empty
+}
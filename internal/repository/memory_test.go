@@ -2,12 +2,15 @@ package repository
 
 import (
 	"errors"
+	"math"
 	"sync"
 	"testing"
+	"time"
 
 	models "github.com/gabkaclassic/metrics/internal/model"
 	"github.com/gabkaclassic/metrics/internal/storage"
 	"github.com/gabkaclassic/metrics/pkg/metric"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -46,7 +49,7 @@ func TestNewMemoryMetricsRepository(t *testing.T) {
 
 func TestMetricsRepository_Get(t *testing.T) {
 	st := storage.NewMemStorage()
-	st.Metrics["existing"] = models.Metrics{ID: "existing", Value: floatPtr(42)}
+	st.Metrics = tenantMetrics(map[string]models.Metrics{"existing": {ID: "existing", Value: floatPtr(42)}})
 
 	repo, err := NewMemoryMetricsRepository(st, &sync.RWMutex{})
 
@@ -74,7 +77,7 @@ func TestMetricsRepository_Get(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := repo.Get(t.Context(), tt.metricID)
+			result, err := repo.Get(t.Context(), tt.metricID, nil)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -94,14 +97,14 @@ func TestMetricsRepository_updateMetric(t *testing.T) {
 	tests := []struct {
 		name        string
 		metric      *models.Metrics
-		updateFunc  func(metric models.Metrics) error
+		updateFunc  func(tenant tenantPartition, metric models.Metrics) error
 		expectError bool
 	}{
 		{
 			name:   "successful update",
 			metric: &models.Metrics{ID: "m1", Value: floatPtr(10)},
-			updateFunc: func(metric models.Metrics) error {
-				repo.storage.Metrics[metric.ID] = metric
+			updateFunc: func(tenant tenantPartition, metric models.Metrics) error {
+				tenant.metrics[metric.ID] = metric
 				return nil
 			},
 			expectError: false,
@@ -109,7 +112,7 @@ func TestMetricsRepository_updateMetric(t *testing.T) {
 		{
 			name:   "update returns error",
 			metric: &models.Metrics{ID: "m2", Value: floatPtr(20)},
-			updateFunc: func(metric models.Metrics) error {
+			updateFunc: func(tenant tenantPartition, metric models.Metrics) error {
 				return errors.New("update failed")
 			},
 			expectError: true,
@@ -123,7 +126,7 @@ func TestMetricsRepository_updateMetric(t *testing.T) {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
-				stored, _ := repo.Get(t.Context(), tt.metric.ID)
+				stored, _ := repo.Get(t.Context(), tt.metric.ID, tt.metric.Labels)
 				assert.Equal(t, tt.metric, stored)
 			}
 		})
@@ -133,7 +136,7 @@ func TestMetricsRepository_updateMetric(t *testing.T) {
 func TestMemoryMetricsRepository_updateMetrics(t *testing.T) {
 	repo := &memoryMetricsRepository{
 		storage: &storage.MemStorage{
-			Metrics: make(map[string]models.Metrics),
+			Metrics: make(map[string]map[string]models.Metrics),
 		},
 		mutex: &sync.RWMutex{},
 	}
@@ -142,7 +145,7 @@ func TestMemoryMetricsRepository_updateMetrics(t *testing.T) {
 		name            string
 		initialStorage  map[string]models.Metrics
 		metrics         []models.Metrics
-		updateFn        func(metric []models.Metrics) error
+		updateFn        func(tenant tenantPartition, metric []models.Metrics) error
 		expectedError   bool
 		expectedStorage map[string]models.Metrics
 	}{
@@ -152,7 +155,7 @@ func TestMemoryMetricsRepository_updateMetrics(t *testing.T) {
 			metrics: []models.Metrics{
 				{ID: "test1", MType: models.Gauge, Value: floatPtr(1.0)},
 			},
-			updateFn: func(metrics []models.Metrics) error {
+			updateFn: func(tenant tenantPartition, metrics []models.Metrics) error {
 				return nil
 			},
 			expectedError:   false,
@@ -164,7 +167,7 @@ func TestMemoryMetricsRepository_updateMetrics(t *testing.T) {
 			metrics: []models.Metrics{
 				{ID: "test1", MType: models.Gauge, Value: floatPtr(1.0)},
 			},
-			updateFn: func(metrics []models.Metrics) error {
+			updateFn: func(tenant tenantPartition, metrics []models.Metrics) error {
 				return errors.New("update error")
 			},
 			expectedError:   true,
@@ -176,7 +179,7 @@ func TestMemoryMetricsRepository_updateMetrics(t *testing.T) {
 			metrics: []models.Metrics{
 				{ID: "test1", MType: models.Gauge, Value: floatPtr(1.0)},
 			},
-			updateFn: func(metrics []models.Metrics) error {
+			updateFn: func(tenant tenantPartition, metrics []models.Metrics) error {
 				return nil
 			},
 			expectedError:   false,
@@ -186,7 +189,7 @@ func TestMemoryMetricsRepository_updateMetrics(t *testing.T) {
 			name:           "empty metrics",
 			initialStorage: map[string]models.Metrics{},
 			metrics:        []models.Metrics{},
-			updateFn: func(metrics []models.Metrics) error {
+			updateFn: func(tenant tenantPartition, metrics []models.Metrics) error {
 				return nil
 			},
 			expectedError:   false,
@@ -196,7 +199,7 @@ func TestMemoryMetricsRepository_updateMetrics(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			repo.storage.Metrics = tt.initialStorage
+			repo.storage.Metrics = tenantMetrics(tt.initialStorage)
 
 			err := repo.updateMetrics(t.Context(), tt.metrics, tt.updateFn)
 
@@ -207,7 +210,7 @@ func TestMemoryMetricsRepository_updateMetrics(t *testing.T) {
 			}
 
 			assert.NotNil(t, repo.storage.Metrics)
-			assert.Equal(t, tt.expectedStorage, repo.storage.Metrics)
+			assert.Equal(t, tt.expectedStorage, repo.storage.Metrics[middleware.DefaultTenant])
 		})
 	}
 }
@@ -249,7 +252,7 @@ func TestMetricsRepository_Add(t *testing.T) {
 			err = repo.Add(t.Context(), tt.addMetric)
 			assert.NoError(t, err)
 
-			result, err := repo.Get(t.Context(), tt.addMetric.ID)
+			result, err := repo.Get(t.Context(), tt.addMetric.ID, tt.addMetric.Labels)
 			assert.NoError(t, err)
 			assert.NotNil(t, result)
 			assert.Equal(t, *tt.expectedMetric.Delta, *result.Delta)
@@ -296,6 +299,23 @@ func TestMemoryMetricsRepository_AddAll(t *testing.T) {
 			},
 			expectedError: false,
 		},
+		{
+			name: "labeled counters are separate series",
+			initialStorage: map[string]models.Metrics{
+				"c1": {ID: "c1", MType: models.Counter, Delta: intPtr(10)},
+			},
+			metrics: []models.Metrics{
+				{ID: "c1", MType: models.Counter, Labels: map[string]string{"host": "a"}, Delta: intPtr(3)},
+				{ID: "c1", MType: models.Counter, Labels: map[string]string{"host": "b"}, Delta: intPtr(7)},
+				{ID: "c1", MType: models.Counter, Labels: map[string]string{"host": "a"}, Delta: intPtr(1)},
+			},
+			expectedStorage: map[string]models.Metrics{
+				"c1":           {ID: "c1", MType: models.Counter, Delta: intPtr(10)},
+				`c1{host="a"}`: {ID: "c1", MType: models.Counter, Labels: map[string]string{"host": "a"}, Delta: intPtr(4)},
+				`c1{host="b"}`: {ID: "c1", MType: models.Counter, Labels: map[string]string{"host": "b"}, Delta: intPtr(7)},
+			},
+			expectedError: false,
+		},
 		{
 			name: "empty metrics",
 			initialStorage: map[string]models.Metrics{
@@ -339,7 +359,7 @@ func TestMemoryMetricsRepository_AddAll(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := &memoryMetricsRepository{
 				storage: &storage.MemStorage{
-					Metrics: tt.initialStorage,
+					Metrics: tenantMetrics(tt.initialStorage),
 				},
 				mutex: &sync.RWMutex{},
 			}
@@ -352,9 +372,9 @@ func TestMemoryMetricsRepository_AddAll(t *testing.T) {
 				assert.NoError(t, err)
 			}
 
-			assert.Equal(t, len(tt.expectedStorage), len(repo.storage.Metrics))
+			assert.Equal(t, len(tt.expectedStorage), len(repo.storage.Metrics[middleware.DefaultTenant]))
 			for id, expectedMetric := range tt.expectedStorage {
-				actualMetric, exists := repo.storage.Metrics[id]
+				actualMetric, exists := repo.storage.Metrics[middleware.DefaultTenant][id]
 				assert.True(t, exists)
 				assert.Equal(t, expectedMetric.ID, actualMetric.ID)
 				assert.Equal(t, expectedMetric.MType, actualMetric.MType)
@@ -392,15 +412,16 @@ func TestMetricsRepository_Reset(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			storage.Metrics = make(map[string]models.Metrics)
+			storage.Metrics = make(map[string]map[string]models.Metrics)
+			storage.Metrics[middleware.DefaultTenant] = make(map[string]models.Metrics)
 			for k, v := range tt.initialMetrics {
-				storage.Metrics[k] = v
+				storage.Metrics[middleware.DefaultTenant][k] = v
 			}
 
 			err := repo.ResetOne(t.Context(), tt.resetMetric)
 			assert.NoError(t, err)
 
-			result, _ := repo.Get(t.Context(), tt.resetMetric.ID)
+			result, _ := repo.Get(t.Context(), tt.resetMetric.ID, tt.resetMetric.Labels)
 			assert.NotNil(t, result)
 			assert.Equal(t, *tt.expectedMetric.Value, *result.Value)
 		})
@@ -489,7 +510,7 @@ func TestMemoryMetricsRepository_ResetAll(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := &memoryMetricsRepository{
 				storage: &storage.MemStorage{
-					Metrics: tt.initialStorage,
+					Metrics: tenantMetrics(tt.initialStorage),
 				},
 				mutex: &sync.RWMutex{},
 			}
@@ -502,9 +523,9 @@ func TestMemoryMetricsRepository_ResetAll(t *testing.T) {
 				assert.NoError(t, err)
 			}
 
-			assert.Equal(t, len(tt.expectedStorage), len(repo.storage.Metrics))
+			assert.Equal(t, len(tt.expectedStorage), len(repo.storage.Metrics[middleware.DefaultTenant]))
 			for id, expectedMetric := range tt.expectedStorage {
-				actualMetric, exists := repo.storage.Metrics[id]
+				actualMetric, exists := repo.storage.Metrics[middleware.DefaultTenant][id]
 				assert.True(t, exists)
 				assert.Equal(t, expectedMetric.ID, actualMetric.ID)
 				assert.Equal(t, expectedMetric.MType, actualMetric.MType)
@@ -514,6 +535,216 @@ func TestMemoryMetricsRepository_ResetAll(t *testing.T) {
 	}
 }
 
+func TestMemoryMetricsRepository_Increment(t *testing.T) {
+	tests := []struct {
+		name           string
+		initialStorage map[string]models.Metrics
+		metric         models.Metrics
+		expectedValue  float64
+	}{
+		{
+			name: "increment existing gauge",
+			initialStorage: map[string]models.Metrics{
+				"g1": {ID: "g1", MType: models.Gauge, Value: floatPtr(10)},
+			},
+			metric:        models.Metrics{ID: "g1", MType: models.Gauge, Increment: floatPtr(5)},
+			expectedValue: 15,
+		},
+		{
+			name: "decrement existing gauge",
+			initialStorage: map[string]models.Metrics{
+				"g1": {ID: "g1", MType: models.Gauge, Value: floatPtr(10)},
+			},
+			metric:        models.Metrics{ID: "g1", MType: models.Gauge, Increment: floatPtr(-2.5)},
+			expectedValue: 7.5,
+		},
+		{
+			name:           "new gauge starts from zero",
+			initialStorage: nil,
+			metric:         models.Metrics{ID: "g1", MType: models.Gauge, Increment: floatPtr(-3)},
+			expectedValue:  -3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryMetricsRepository{
+				storage: &storage.MemStorage{
+					Metrics:     tenantMetrics(tt.initialStorage),
+					HistorySize: storage.DefaultHistorySize,
+				},
+				mutex: &sync.RWMutex{},
+			}
+
+			err := repo.Increment(t.Context(), tt.metric)
+			assert.NoError(t, err)
+
+			actual := repo.storage.Metrics[middleware.DefaultTenant][tt.metric.Key()]
+			assert.Equal(t, models.Gauge, actual.MType)
+			assert.Nil(t, actual.Increment)
+			assert.Equal(t, tt.expectedValue, *actual.Value)
+
+			points := repo.storage.History[middleware.DefaultTenant][tt.metric.Key()].Range(0, math.MaxInt64)
+			assert.Len(t, points, 1)
+			assert.Equal(t, tt.expectedValue, *points[0].Value)
+		})
+	}
+}
+
+func TestMemoryMetricsRepository_IncrementAll(t *testing.T) {
+	repo := &memoryMetricsRepository{
+		storage: &storage.MemStorage{
+			Metrics: tenantMetrics(map[string]models.Metrics{
+				"g1": {ID: "g1", MType: models.Gauge, Value: floatPtr(1)},
+			}),
+			HistorySize: storage.DefaultHistorySize,
+		},
+		mutex: &sync.RWMutex{},
+	}
+
+	err := repo.IncrementAll(t.Context(), []models.Metrics{
+		{ID: "g1", MType: models.Gauge, Increment: floatPtr(2)},
+		{ID: "g2", MType: models.Gauge, Increment: floatPtr(4)},
+		{ID: "g1", MType: models.Gauge, Increment: floatPtr(-1)},
+	})
+	assert.NoError(t, err)
+
+	series := repo.storage.Metrics[middleware.DefaultTenant]
+	assert.Equal(t, 2.0, *series["g1"].Value)
+	assert.Equal(t, 4.0, *series["g2"].Value)
+
+	points := repo.storage.History[middleware.DefaultTenant]["g1"].Range(0, math.MaxInt64)
+	assert.Len(t, points, 2)
+	assert.Equal(t, 3.0, *points[0].Value)
+	assert.Equal(t, 2.0, *points[1].Value)
+}
+
+func TestMemoryMetricsRepository_Delete(t *testing.T) {
+	newRepo := func() *memoryMetricsRepository {
+		repo := &memoryMetricsRepository{
+			storage: storage.NewMemStorage(),
+			mutex:   &sync.RWMutex{},
+		}
+		assert.NoError(t, repo.AddAll(t.Context(), []models.Metrics{
+			{ID: "cpu_user", MType: models.Counter, Delta: intPtr(1)},
+			{ID: "cpu_user", MType: models.Counter, Labels: map[string]string{"host": "a"}, Delta: intPtr(2)},
+			{ID: "cpu_system", MType: models.Counter, Delta: intPtr(3)},
+		}))
+		assert.NoError(t, repo.ResetAll(t.Context(), []models.Metrics{
+			{ID: "cpu_temp", MType: models.Gauge, Value: floatPtr(40)},
+			{ID: "mem", MType: models.Gauge, Value: floatPtr(1)},
+		}))
+		return repo
+	}
+
+	tests := []struct {
+		name          string
+		query         models.DeleteQuery
+		expectDeleted []string
+		expectKept    []string
+	}{
+		{
+			name:          "exact id deletes all series",
+			query:         models.DeleteQuery{ID: "cpu_user", MType: models.Counter},
+			expectDeleted: []string{"cpu_user", `cpu_user{host="a"}`},
+			expectKept:    []string{"cpu_system", "cpu_temp", "mem"},
+		},
+		{
+			name:          "exact id with other type",
+			query:         models.DeleteQuery{ID: "cpu_user", MType: models.Gauge},
+			expectDeleted: []string{},
+			expectKept:    []string{"cpu_user", `cpu_user{host="a"}`, "cpu_system", "cpu_temp", "mem"},
+		},
+		{
+			name:          "pattern of all types",
+			query:         models.DeleteQuery{Pattern: "cpu_*"},
+			expectDeleted: []string{"cpu_user", `cpu_user{host="a"}`, "cpu_system", "cpu_temp"},
+			expectKept:    []string{"mem"},
+		},
+		{
+			name:          "pattern restricted to type",
+			query:         models.DeleteQuery{Pattern: "cpu_*", MType: models.Gauge},
+			expectDeleted: []string{"cpu_temp"},
+			expectKept:    []string{"cpu_user", `cpu_user{host="a"}`, "cpu_system", "mem"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newRepo()
+
+			deleted, err := repo.Delete(t.Context(), tt.query)
+			assert.NoError(t, err)
+
+			keys := make([]string, len(deleted))
+			for i, m := range deleted {
+				keys[i] = m.Key()
+			}
+			assert.ElementsMatch(t, tt.expectDeleted, keys)
+
+			tenant := middleware.DefaultTenant
+			assert.Len(t, repo.storage.Metrics[tenant], len(tt.expectKept))
+			for _, key := range tt.expectKept {
+				assert.Contains(t, repo.storage.Metrics[tenant], key)
+			}
+			for _, key := range tt.expectDeleted {
+				assert.NotContains(t, repo.storage.History[tenant], key)
+				assert.NotContains(t, repo.storage.Updated[tenant], key)
+			}
+		})
+	}
+}
+
+func TestMemoryMetricsRepository_Expire(t *testing.T) {
+	now := time.Now()
+	repo := &memoryMetricsRepository{
+		storage: &storage.MemStorage{
+			Metrics: map[string]map[string]models.Metrics{
+				middleware.DefaultTenant: {
+					"stale":   {ID: "stale", MType: models.Gauge, Value: floatPtr(1)},
+					"fresh":   {ID: "fresh", MType: models.Gauge, Value: floatPtr(2)},
+					"long":    {ID: "long", MType: models.Gauge, Value: floatPtr(3)},
+					"unknown": {ID: "unknown", MType: models.Gauge, Value: floatPtr(4)},
+				},
+				"team-a": {
+					"stale": {ID: "stale", MType: models.Counter, Delta: intPtr(1)},
+				},
+			},
+			Updated: map[string]map[string]time.Time{
+				middleware.DefaultTenant: {
+					"stale": now.Add(-2 * time.Minute),
+					"fresh": now,
+					"long":  now.Add(-2 * time.Minute),
+				},
+				"team-a": {
+					"stale": now.Add(-2 * time.Minute),
+				},
+			},
+		},
+		mutex: &sync.RWMutex{},
+	}
+
+	expired, err := repo.Expire(t.Context(), models.ExpirePolicy{
+		Now:     now,
+		Default: time.Minute,
+		PerMetric: map[string]map[string]time.Duration{
+			middleware.DefaultTenant: {"long": time.Hour},
+			"team-a":                 {"stale": time.Hour},
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.ElementsMatch(t, []models.Metrics{
+		{ID: "stale", MType: models.Gauge, Value: floatPtr(1), Tenant: middleware.DefaultTenant},
+	}, expired)
+
+	assert.NotContains(t, repo.storage.Metrics[middleware.DefaultTenant], "stale")
+	assert.Contains(t, repo.storage.Metrics["team-a"], "stale")
+	assert.Contains(t, repo.storage.Metrics[middleware.DefaultTenant], "fresh")
+	assert.Contains(t, repo.storage.Metrics[middleware.DefaultTenant], "long")
+	assert.Contains(t, repo.storage.Metrics[middleware.DefaultTenant], "unknown")
+}
+
 func TestMetricsRepository_GetAll(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -570,13 +801,35 @@ func TestMetricsRepository_GetAll(t *testing.T) {
 				"g1": float64(99.9),
 			},
 		},
+		{
+			name: "single set metric",
+			initialMetrics: map[string]models.Metrics{
+				"u1": models.ObserveSet("u1", "alice", "bob", "alice"),
+			},
+			expected: map[string]any{
+				"u1": int64(2),
+			},
+		},
+		{
+			name: "single summary metric",
+			initialMetrics: map[string]models.Metrics{
+				"s1": models.ObserveSummary("s1", 2),
+			},
+			expected: map[string]any{
+				"s1": models.SummarySnapshot{
+					Count:     1,
+					Sum:       2,
+					Quantiles: map[string]float64{"0.5": 2, "0.9": 2, "0.95": 2, "0.99": 2},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			st := storage.NewMemStorage()
-			st.Metrics = tt.initialMetrics
-			repo := &memoryMetricsRepository{storage: st}
+			st.Metrics = tenantMetrics(tt.initialMetrics)
+			repo := &memoryMetricsRepository{storage: st, mutex: &sync.RWMutex{}}
 
 			result, _ := repo.GetAll(t.Context())
 
@@ -640,7 +893,7 @@ func TestMemoryMetricsRepository_GetAllMetrics(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := &memoryMetricsRepository{
 				storage: &storage.MemStorage{
-					Metrics: tt.initialStorage,
+					Metrics: tenantMetrics(tt.initialStorage),
 				},
 				mutex: &sync.RWMutex{},
 			}
@@ -680,6 +933,14 @@ func TestMemoryMetricsRepository_GetAllMetrics(t *testing.T) {
 	}
 }
 
+// tenantMetrics places series into the default tenant partition.
+func tenantMetrics(series map[string]models.Metrics) map[string]map[string]models.Metrics {
+	if series == nil {
+		return nil
+	}
+	return map[string]map[string]models.Metrics{middleware.DefaultTenant: series}
+}
+
 func floatPtr(v float64) *float64 {
 	return &v
 }
@@ -687,3 +948,576 @@ func floatPtr(v float64) *float64 {
 func intPtr(value int64) *int64 {
 	return &value
 }
+
+func TestMemoryMetricsRepository_MergeAll(t *testing.T) {
+	bounds := []float64{1, 2}
+
+	tests := []struct {
+		name            string
+		initialStorage  map[string]models.Metrics
+		metrics         []models.Metrics
+		expectedStorage map[string]models.Metrics
+		expectedError   bool
+	}{
+		{
+			name:           "new histogram",
+			initialStorage: map[string]models.Metrics{},
+			metrics: []models.Metrics{
+				models.ObserveHistogram("h1", bounds, 1.5),
+			},
+			expectedStorage: map[string]models.Metrics{
+				"h1": {ID: "h1", MType: models.Histogram, Bounds: bounds, Buckets: []int64{0, 1, 0}, Sum: floatPtr(1.5), Count: intPtr(1)},
+			},
+			expectedError: false,
+		},
+		{
+			name: "merge into existing histogram",
+			initialStorage: map[string]models.Metrics{
+				"h1": {ID: "h1", MType: models.Histogram, Bounds: bounds, Buckets: []int64{1, 0, 0}, Sum: floatPtr(0.5), Count: intPtr(1)},
+			},
+			metrics: []models.Metrics{
+				models.ObserveHistogram("h1", bounds, 3),
+				models.ObserveHistogram("h1", bounds, 0.5),
+			},
+			expectedStorage: map[string]models.Metrics{
+				"h1": {ID: "h1", MType: models.Histogram, Bounds: bounds, Buckets: []int64{2, 0, 1}, Sum: floatPtr(4), Count: intPtr(3)},
+			},
+			expectedError: false,
+		},
+		{
+			name: "bounds mismatch",
+			initialStorage: map[string]models.Metrics{
+				"h1": {ID: "h1", MType: models.Histogram, Bounds: bounds, Buckets: []int64{1, 0, 0}, Sum: floatPtr(0.5), Count: intPtr(1)},
+			},
+			metrics: []models.Metrics{
+				models.ObserveHistogram("h1", []float64{5}, 3),
+			},
+			expectedStorage: map[string]models.Metrics{
+				"h1": {ID: "h1", MType: models.Histogram, Bounds: bounds, Buckets: []int64{1, 0, 0}, Sum: floatPtr(0.5), Count: intPtr(1)},
+			},
+			expectedError: true,
+		},
+		{
+			name:           "new summary",
+			initialStorage: map[string]models.Metrics{},
+			metrics: []models.Metrics{
+				models.ObserveSummary("s1", 1.5),
+			},
+			expectedStorage: map[string]models.Metrics{
+				"s1": models.ObserveSummary("s1", 1.5),
+			},
+			expectedError: false,
+		},
+		{
+			name: "merge into existing summary",
+			initialStorage: map[string]models.Metrics{
+				"s1": models.ObserveSummary("s1", 1),
+			},
+			metrics: []models.Metrics{
+				models.ObserveSummary("s1", 2),
+				models.ObserveSummary("s1", 3),
+			},
+			expectedStorage: map[string]models.Metrics{
+				"s1": observeSummary(t, "s1", 1, 2, 3),
+			},
+			expectedError: false,
+		},
+		{
+			name: "merge into existing set",
+			initialStorage: map[string]models.Metrics{
+				"u1": models.ObserveSet("u1", "alice", "bob"),
+			},
+			metrics: []models.Metrics{
+				models.ObserveSet("u1", "bob", "carol"),
+			},
+			expectedStorage: map[string]models.Metrics{
+				"u1": models.ObserveSet("u1", "alice", "bob", "carol"),
+			},
+			expectedError: false,
+		},
+		{
+			name: "summary merged into histogram",
+			initialStorage: map[string]models.Metrics{
+				"h1": {ID: "h1", MType: models.Histogram, Bounds: bounds, Buckets: []int64{1, 0, 0}, Sum: floatPtr(0.5), Count: intPtr(1)},
+			},
+			metrics: []models.Metrics{
+				models.ObserveSummary("h1", 3),
+			},
+			expectedStorage: map[string]models.Metrics{
+				"h1": {ID: "h1", MType: models.Histogram, Bounds: bounds, Buckets: []int64{1, 0, 0}, Sum: floatPtr(0.5), Count: intPtr(1)},
+			},
+			expectedError: true,
+		},
+		{
+			name: "type mismatch",
+			initialStorage: map[string]models.Metrics{
+				"c1": {ID: "c1", MType: models.Counter, Delta: intPtr(10)},
+			},
+			metrics: []models.Metrics{
+				models.ObserveHistogram("c1", bounds, 3),
+			},
+			expectedStorage: map[string]models.Metrics{
+				"c1": {ID: "c1", MType: models.Counter, Delta: intPtr(10)},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryMetricsRepository{
+				storage: &storage.MemStorage{
+					Metrics: tenantMetrics(tt.initialStorage),
+				},
+				mutex: &sync.RWMutex{},
+			}
+
+			err := repo.MergeAll(t.Context(), tt.metrics)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedStorage, repo.storage.Metrics[middleware.DefaultTenant])
+		})
+	}
+}
+
+func TestMemoryMetricsRepository_PruneHistory(t *testing.T) {
+	ts := func(ms int64) *int64 { return &ms }
+
+	repo, err := NewMemoryMetricsRepository(storage.NewMemStorage(), &sync.RWMutex{})
+	assert.NoError(t, err)
+
+	teamA := middleware.WithTenant(t.Context(), "team-a")
+	assert.NoError(t, repo.AddAll(t.Context(), []models.Metrics{
+		{ID: "c1", MType: models.Counter, Delta: intPtr(1), Timestamp: ts(1000)},
+		{ID: "c1", MType: models.Counter, Delta: intPtr(2), Timestamp: ts(3000)},
+	}))
+	assert.NoError(t, repo.ResetOne(teamA, models.Metrics{ID: "g1", MType: models.Gauge, Value: floatPtr(1.5), Timestamp: ts(2000)}))
+
+	pruned, err := repo.PruneHistory(t.Context(), time.UnixMilli(2500))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pruned)
+
+	points, err := repo.GetRange(t.Context(), models.RangeQuery{
+		ID: "c1", MType: models.Counter, From: time.UnixMilli(0), To: time.UnixMilli(5000),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Point{{Timestamp: 3000, Delta: intPtr(2)}}, points)
+
+	points, err = repo.GetRange(teamA, models.RangeQuery{
+		ID: "g1", MType: models.Gauge, From: time.UnixMilli(0), To: time.UnixMilli(5000),
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, points)
+
+	metric, err := repo.Get(teamA, "g1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, *metric.Value, "series outlive their pruned history")
+}
+
+func TestMemoryMetricsRepository_GetRange(t *testing.T) {
+	ts := func(ms int64) *int64 { return &ms }
+
+	memStorage := storage.NewMemStorage()
+	memStorage.HistorySize = 3
+	repo, err := NewMemoryMetricsRepository(memStorage, &sync.RWMutex{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.AddAll(t.Context(), []models.Metrics{
+		{ID: "c1", MType: models.Counter, Delta: intPtr(1), Timestamp: ts(1000)},
+		{ID: "c1", MType: models.Counter, Labels: map[string]string{"host": "a"}, Delta: intPtr(5), Timestamp: ts(1000)},
+	}))
+	assert.NoError(t, repo.Add(t.Context(), models.Metrics{ID: "c1", MType: models.Counter, Delta: intPtr(2), Timestamp: ts(2000)}))
+	assert.NoError(t, repo.ResetOne(t.Context(), models.Metrics{ID: "g1", MType: models.Gauge, Value: floatPtr(1.5), Timestamp: ts(1000)}))
+	assert.NoError(t, repo.ResetAll(t.Context(), []models.Metrics{
+		{ID: "g1", MType: models.Gauge, Value: floatPtr(2.5), Timestamp: ts(2000)},
+		{ID: "g1", MType: models.Gauge, Value: floatPtr(3.5), Timestamp: ts(3000)},
+		{ID: "g1", MType: models.Gauge, Value: floatPtr(4.5), Timestamp: ts(4000)},
+	}))
+
+	tests := []struct {
+		name     string
+		query    models.RangeQuery
+		expected []models.Point
+	}{
+		{
+			name: "counter deltas",
+			query: models.RangeQuery{
+				ID: "c1", MType: models.Counter,
+				From: time.UnixMilli(0), To: time.UnixMilli(5000),
+			},
+			expected: []models.Point{
+				{Timestamp: 1000, Delta: intPtr(1)},
+				{Timestamp: 2000, Delta: intPtr(2)},
+			},
+		},
+		{
+			name: "labeled series",
+			query: models.RangeQuery{
+				ID: "c1", MType: models.Counter, Labels: map[string]string{"host": "a"},
+				From: time.UnixMilli(0), To: time.UnixMilli(5000),
+			},
+			expected: []models.Point{
+				{Timestamp: 1000, Delta: intPtr(5)},
+			},
+		},
+		{
+			name: "gauge history bounded",
+			query: models.RangeQuery{
+				ID: "g1", MType: models.Gauge,
+				From: time.UnixMilli(0), To: time.UnixMilli(5000),
+			},
+			expected: []models.Point{
+				{Timestamp: 2000, Value: floatPtr(2.5)},
+				{Timestamp: 3000, Value: floatPtr(3.5)},
+				{Timestamp: 4000, Value: floatPtr(4.5)},
+			},
+		},
+		{
+			name: "interval filter",
+			query: models.RangeQuery{
+				ID: "g1", MType: models.Gauge,
+				From: time.UnixMilli(2500), To: time.UnixMilli(3500),
+			},
+			expected: []models.Point{
+				{Timestamp: 3000, Value: floatPtr(3.5)},
+			},
+		},
+		{
+			name: "type mismatch",
+			query: models.RangeQuery{
+				ID: "g1", MType: models.Counter,
+				From: time.UnixMilli(0), To: time.UnixMilli(5000),
+			},
+			expected: []models.Point{},
+		},
+		{
+			name: "unknown series",
+			query: models.RangeQuery{
+				ID: "missing", MType: models.Gauge,
+				From: time.UnixMilli(0), To: time.UnixMilli(5000),
+			},
+			expected: []models.Point{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := repo.GetRange(t.Context(), tt.query)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+// observeSummary builds a summary holding all values.
+func observeSummary(t *testing.T, id string, values ...float64) models.Metrics {
+	t.Helper()
+
+	summary := models.ObserveSummary(id, values[0])
+	for _, value := range values[1:] {
+		merged, err := models.MergeSummary(summary, models.ObserveSummary(id, value))
+		if err != nil {
+			t.Fatal(err)
+		}
+		summary = merged
+	}
+	return summary
+}
+
+func TestMemoryMetricsRepository_Tenants(t *testing.T) {
+	repo, err := NewMemoryMetricsRepository(storage.NewMemStorage(), &sync.RWMutex{})
+	assert.NoError(t, err)
+
+	teamA := middleware.WithTenant(t.Context(), "team-a")
+	teamB := middleware.WithTenant(t.Context(), "team-b")
+
+	assert.NoError(t, repo.Add(teamA, models.Metrics{ID: "requests", MType: models.Counter, Delta: intPtr(5)}))
+	assert.NoError(t, repo.Add(teamB, models.Metrics{ID: "requests", MType: models.Counter, Delta: intPtr(7)}))
+	assert.NoError(t, repo.ResetOne(teamB, models.Metrics{ID: "load", MType: models.Gauge, Value: floatPtr(0.5)}))
+
+	metricA, err := repo.Get(teamA, "requests", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), *metricA.Delta)
+
+	_, err = repo.Get(teamA, "load", nil)
+	assert.Error(t, err)
+
+	allB, err := repo.GetAll(teamB)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"requests": int64(7), "load": 0.5}, allB)
+
+	allDefault, err := repo.GetAll(t.Context())
+	assert.NoError(t, err)
+	assert.Empty(t, allDefault)
+
+	points, err := repo.GetRange(teamA, models.RangeQuery{ID: "load", MType: models.Gauge, From: time.UnixMilli(0), To: time.Now()})
+	assert.NoError(t, err)
+	assert.Empty(t, points)
+
+	all, err := repo.GetAllMetrics(t.Context())
+	assert.NoError(t, err)
+	tenants := make(map[string]int)
+	for _, m := range all {
+		tenants[m.Tenant]++
+	}
+	assert.Equal(t, map[string]int{"team-a": 1, "team-b": 2}, tenants)
+}
+
+func TestMemoryMetricsRepository_List(t *testing.T) {
+	repo := &memoryMetricsRepository{
+		storage: storage.NewMemStorage(),
+		mutex:   &sync.RWMutex{},
+	}
+	assert.NoError(t, repo.AddAll(t.Context(), []models.Metrics{
+		{ID: "cpu_user", MType: models.Counter, Delta: intPtr(1)},
+		{ID: "cpu_user", MType: models.Counter, Labels: map[string]string{"host": "a"}, Delta: intPtr(2)},
+		{ID: "cpu_system", MType: models.Counter, Delta: intPtr(3)},
+	}))
+	assert.NoError(t, repo.ResetAll(t.Context(), []models.Metrics{
+		{ID: "cpu_temp", MType: models.Gauge, Value: floatPtr(40)},
+		{ID: "mem", MType: models.Gauge, Value: floatPtr(1)},
+	}))
+	assert.NoError(t, repo.ResetOne(middleware.WithTenant(t.Context(), "team-a"), models.Metrics{
+		ID: "cpu_other", MType: models.Gauge, Value: floatPtr(1),
+	}))
+
+	tests := []struct {
+		name       string
+		query      models.ListQuery
+		expectKeys []string
+	}{
+		{
+			name:       "all series ordered by id",
+			query:      models.ListQuery{},
+			expectKeys: []string{"cpu_system", "cpu_temp", "cpu_user", `cpu_user{host="a"}`, "mem"},
+		},
+		{
+			name:       "ordered by type descending",
+			query:      models.ListQuery{SortBy: models.SortByType, Descending: true},
+			expectKeys: []string{"mem", "cpu_temp", `cpu_user{host="a"}`, "cpu_user", "cpu_system"},
+		},
+		{
+			name:       "type and prefix",
+			query:      models.ListQuery{MType: models.Counter, Prefix: "cpu_u"},
+			expectKeys: []string{"cpu_user", `cpu_user{host="a"}`},
+		},
+		{
+			name:       "glob pattern",
+			query:      models.ListQuery{Pattern: "cpu_[st]*"},
+			expectKeys: []string{"cpu_system", "cpu_temp"},
+		},
+		{
+			name:       "regex",
+			query:      models.ListQuery{Regex: "^(mem|cpu_t.*)$"},
+			expectKeys: []string{"cpu_temp", "mem"},
+		},
+		{
+			name:       "page after cursor",
+			query:      models.ListQuery{Limit: 2, After: &models.SeriesRef{ID: "cpu_temp", MType: models.Gauge}},
+			expectKeys: []string{"cpu_user", `cpu_user{host="a"}`},
+		},
+		{
+			name:       "descending page after cursor",
+			query:      models.ListQuery{Descending: true, After: &models.SeriesRef{ID: "cpu_temp", MType: models.Gauge}},
+			expectKeys: []string{"cpu_system"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, err := repo.List(t.Context(), tt.query)
+			assert.NoError(t, err)
+
+			keys := make([]string, len(metrics))
+			for i, m := range metrics {
+				keys[i] = m.Key()
+			}
+			assert.Equal(t, tt.expectKeys, keys)
+		})
+	}
+}
+
+func TestMemoryMetricsRepository_GetMany(t *testing.T) {
+	repo := &memoryMetricsRepository{
+		storage: storage.NewMemStorage(),
+		mutex:   &sync.RWMutex{},
+	}
+	assert.NoError(t, repo.AddAll(t.Context(), []models.Metrics{
+		{ID: "requests", MType: models.Counter, Labels: map[string]string{"host": "a"}, Delta: intPtr(1)},
+	}))
+	assert.NoError(t, repo.ResetOne(t.Context(), models.Metrics{ID: "temp", MType: models.Gauge, Value: floatPtr(20)}))
+
+	metrics, err := repo.GetMany(t.Context(), []models.SeriesRef{
+		{ID: "requests", MType: models.Counter, Labels: map[string]string{"host": "a"}},
+		{ID: "requests", MType: models.Counter},
+		{ID: "temp", MType: models.Counter},
+		{ID: "temp", MType: models.Gauge},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Metrics{
+		{ID: "requests", MType: models.Counter, Labels: map[string]string{"host": "a"}, Delta: intPtr(1)},
+		{ID: "temp", MType: models.Gauge, Value: floatPtr(20)},
+	}, metrics)
+}
+
+func TestMemoryMetricsRepository_GetTypes(t *testing.T) {
+	repo := &memoryMetricsRepository{
+		storage: storage.NewMemStorage(),
+		mutex:   &sync.RWMutex{},
+	}
+	assert.NoError(t, repo.Merge(t.Context(), models.ObserveHistogram("latency", []float64{1}, 0.5)))
+	assert.NoError(t, repo.ResetOne(t.Context(), models.Metrics{ID: "temp", MType: models.Gauge, Value: floatPtr(20)}))
+
+	types, err := repo.GetTypes(t.Context(), []models.SeriesRef{
+		{ID: "latency", MType: models.Counter},
+		{ID: "temp", MType: models.Gauge},
+		{ID: "missing", MType: models.Gauge},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"latency": models.Histogram, "temp": models.Gauge}, types)
+}
+
+func TestMemoryMetricsRepository_typeMismatch(t *testing.T) {
+	counter := models.Metrics{ID: "x", MType: models.Counter, Delta: intPtr(1)}
+	gauge := models.Metrics{ID: "x", MType: models.Gauge, Value: floatPtr(1)}
+	increment := models.Metrics{ID: "x", MType: models.Gauge, Increment: floatPtr(1)}
+	histogram := models.ObserveHistogram("x", []float64{1}, 0.5)
+
+	tests := []struct {
+		name   string
+		stored models.Metrics
+		write  func(repo MetricsRepository) error
+	}{
+		{
+			name:   "add counter over histogram",
+			stored: histogram,
+			write:  func(repo MetricsRepository) error { return repo.Add(t.Context(), counter) },
+		},
+		{
+			name:   "add all counters over histogram",
+			stored: histogram,
+			write: func(repo MetricsRepository) error {
+				return repo.AddAll(t.Context(), []models.Metrics{{ID: "y", MType: models.Counter, Delta: intPtr(1)}, counter})
+			},
+		},
+		{
+			name:   "add counter over gauge",
+			stored: gauge,
+			write:  func(repo MetricsRepository) error { return repo.AddAll(t.Context(), []models.Metrics{counter}) },
+		},
+		{
+			name:   "reset gauge over histogram",
+			stored: histogram,
+			write:  func(repo MetricsRepository) error { return repo.ResetOne(t.Context(), gauge) },
+		},
+		{
+			name:   "reset all gauges over counter",
+			stored: counter,
+			write:  func(repo MetricsRepository) error { return repo.ResetAll(t.Context(), []models.Metrics{gauge}) },
+		},
+		{
+			name:   "increment gauge over counter",
+			stored: counter,
+			write:  func(repo MetricsRepository) error { return repo.Increment(t.Context(), increment) },
+		},
+		{
+			name:   "increment all gauges over histogram",
+			stored: histogram,
+			write:  func(repo MetricsRepository) error { return repo.IncrementAll(t.Context(), []models.Metrics{increment}) },
+		},
+		{
+			name:   "merge histogram over counter",
+			stored: counter,
+			write:  func(repo MetricsRepository) error { return repo.MergeAll(t.Context(), []models.Metrics{histogram}) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryMetricsRepository{
+				storage: &storage.MemStorage{
+					Metrics: tenantMetrics(map[string]models.Metrics{"x": tt.stored}),
+				},
+				mutex: &sync.RWMutex{},
+			}
+
+			err := tt.write(repo)
+
+			assert.ErrorIs(t, err, ErrTypeMismatch)
+			series := repo.storage.Metrics[middleware.DefaultTenant]
+			assert.Len(t, series, 1)
+			assert.Equal(t, tt.stored.MType, series["x"].MType)
+		})
+	}
+}
+
+func TestMemoryMetricsRepository_GetUpdated(t *testing.T) {
+	repo, err := NewMemoryMetricsRepository(storage.NewMemStorage(), &sync.RWMutex{})
+	assert.NoError(t, err)
+
+	before := time.Now()
+	assert.NoError(t, repo.Add(t.Context(), models.Metrics{ID: "c1", MType: models.Counter, Delta: intPtr(1)}))
+	assert.NoError(t, repo.ResetOne(t.Context(), models.Metrics{
+		ID: "g1", MType: models.Gauge, Labels: map[string]string{"host": "a"}, Value: floatPtr(1.5),
+	}))
+	assert.NoError(t, repo.ResetOne(
+		middleware.WithTenant(t.Context(), "team-a"),
+		models.Metrics{ID: "g2", MType: models.Gauge, Value: floatPtr(2.5)},
+	))
+
+	updated, err := repo.GetUpdated(t.Context())
+	assert.NoError(t, err)
+	assert.Len(t, updated, 2)
+	assert.False(t, updated["c1"].Before(before))
+	assert.False(t, updated[`g1{host="a"}`].Before(before))
+
+	updated, err = repo.GetUpdated(middleware.WithTenant(t.Context(), "team-b"))
+	assert.NoError(t, err)
+	assert.Empty(t, updated)
+}
+
+func TestMemoryMetricsRepository_concurrentReads(t *testing.T) {
+	repo, err := NewMemoryMetricsRepository(storage.NewMemStorage(), &sync.RWMutex{})
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Add(t.Context(), models.Metrics{ID: "c1", MType: models.Counter, Delta: intPtr(1)}))
+	assert.NoError(t, repo.ResetOne(t.Context(), models.Metrics{ID: "g1", MType: models.Gauge, Value: floatPtr(1)}))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			assert.NoError(t, repo.Add(t.Context(), models.Metrics{ID: "c1", MType: models.Counter, Delta: intPtr(1)}))
+			assert.NoError(t, repo.ResetOne(t.Context(), models.Metrics{ID: "g1", MType: models.Gauge, Value: floatPtr(float64(i))}))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			metrics, err := repo.GetAllMetrics(t.Context())
+			assert.NoError(t, err)
+			for _, m := range metrics {
+				if m.Delta != nil {
+					assert.Positive(t, *m.Delta)
+				}
+			}
+			_, err = repo.GetAll(t.Context())
+			assert.NoError(t, err)
+			counter, err := repo.Get(t.Context(), "c1", nil)
+			assert.NoError(t, err)
+			assert.Positive(t, *counter.Delta)
+		}
+	}()
+	wg.Wait()
+
+	counter, err := repo.Get(t.Context(), "c1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(101), *counter.Delta)
+}
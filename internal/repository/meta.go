@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/storage"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+)
+
+// MetaRepository defines the interface for metric metadata operations.
+// Metadata is keyed by metric ID and shared by all series of the metric.
+// Metadata is partitioned by tenant: every operation is scoped to the tenant
+// carried by the context (see middleware.TenantFromCtx), except
+// GetAllTenants, which spans all tenants.
+type MetaRepository interface {
+	// Put creates or replaces the metadata of a metric.
+	Put(context.Context, models.Meta) error
+
+	// Register stores metadata of metrics that have none yet.
+	// Existing metadata is never overwritten, so units reported
+	// by agents don't replace metadata edited through the API.
+	Register(context.Context, []models.Meta) error
+
+	// Get retrieves the metadata of a metric by its ID.
+	// Returns nil without error if the metric has no metadata.
+	Get(context.Context, string) (*models.Meta, error)
+
+	// GetAll returns metadata of all metrics keyed by metric ID.
+	GetAll(context.Context) (map[string]models.Meta, error)
+
+	// GetAllTenants returns metadata of all tenants,
+	// partitioned by tenant, then keyed by metric ID.
+	GetAllTenants(context.Context) (map[string]map[string]models.Meta, error)
+}
+
+// memoryMetaRepository implements MetaRepository using in-memory storage.
+// Provides thread-safe operations through read-write mutex.
+type memoryMetaRepository struct {
+	storage *storage.MemStorage
+	mutex   *sync.RWMutex
+}
+
+// NewMemoryMetaRepository creates a new in-memory metadata repository.
+//
+// storage: MemStorage instance for data persistence
+// mutex: Read-write mutex for thread safety (can be shared)
+//
+// Returns:
+//   - MetaRepository: Ready-to-use repository instance
+//   - error: If storage is nil
+func NewMemoryMetaRepository(storage *storage.MemStorage, mutex *sync.RWMutex) (MetaRepository, error) {
+	if storage == nil {
+		return nil, errors.New("create new meta repository failed: storage is nil")
+	}
+
+	return &memoryMetaRepository{
+		storage: storage,
+		mutex:   mutex,
+	}, nil
+}
+
+// Put creates or replaces the metadata of a metric.
+func (repository *memoryMetaRepository) Put(ctx context.Context, meta models.Meta) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	repository.partition(ctx)[meta.ID] = meta
+	return nil
+}
+
+// Register stores metadata of metrics that have none yet.
+func (repository *memoryMetaRepository) Register(ctx context.Context, metas []models.Meta) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	tenant := repository.partition(ctx)
+	for _, meta := range metas {
+		if _, exists := tenant[meta.ID]; !exists {
+			tenant[meta.ID] = meta
+		}
+	}
+	return nil
+}
+
+// partition returns the metadata of the request tenant, creating it if needed.
+// Must be called with the write lock held.
+func (repository *memoryMetaRepository) partition(ctx context.Context) map[string]models.Meta {
+	if repository.storage.Meta == nil {
+		repository.storage.Meta = make(map[string]map[string]models.Meta)
+	}
+
+	tenant := middleware.TenantFromCtx(ctx)
+	metas, exists := repository.storage.Meta[tenant]
+	if !exists {
+		metas = make(map[string]models.Meta)
+		repository.storage.Meta[tenant] = metas
+	}
+	return metas
+}
+
+// Get retrieves the metadata of a metric by its ID.
+// Returns nil without error if the metric has no metadata.
+func (repository *memoryMetaRepository) Get(ctx context.Context, id string) (*models.Meta, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	meta, exists := repository.storage.Meta[middleware.TenantFromCtx(ctx)][id]
+	if !exists {
+		return nil, nil
+	}
+
+	return &meta, nil
+}
+
+// GetAll returns a copy of all stored metadata of the request tenant keyed by metric ID.
+func (repository *memoryMetaRepository) GetAll(ctx context.Context) (map[string]models.Meta, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	stored := repository.storage.Meta[middleware.TenantFromCtx(ctx)]
+	metas := make(map[string]models.Meta, len(stored))
+	maps.Copy(metas, stored)
+
+	return metas, nil
+}
+
+// GetAllTenants returns a copy of all stored metadata of all tenants.
+func (repository *memoryMetaRepository) GetAllTenants(ctx context.Context) (map[string]map[string]models.Meta, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	tenants := make(map[string]map[string]models.Meta, len(repository.storage.Meta))
+	for tenant, stored := range repository.storage.Meta {
+		tenants[tenant] = maps.Clone(stored)
+	}
+
+	return tenants, nil
+}
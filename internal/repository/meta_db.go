@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/storage"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+)
+
+// dbMetaRepository implements MetaRepository using the metric_meta table.
+type dbMetaRepository struct {
+	storage storage.DB
+}
+
+// NewDBMetaRepository creates a new PostgreSQL-based metadata repository.
+//
+// storage: Established SQL database connection (typically PostgreSQL)
+//
+// Returns:
+//   - MetaRepository: Ready-to-use repository instance
+//   - error: If storage connection is nil
+//
+// The repository automatically retries operations on transient database errors.
+func NewDBMetaRepository(s storage.DB) (MetaRepository, error) {
+	if s == nil {
+		return nil, errors.New("create new meta repository failed: storage is nil")
+	}
+
+	return &dbMetaRepository{
+		storage: s,
+	}, nil
+}
+
+// Put creates or replaces the metadata of a metric.
+func (repository *dbMetaRepository) Put(ctx context.Context, meta models.Meta) error {
+	return executeWithRetry(func() error {
+		_, err := repository.storage.Exec(
+			ctx,
+			`INSERT INTO metric_meta (tenant, id, unit, description, owner, ttl)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (tenant, id)
+			DO UPDATE SET unit = EXCLUDED.unit, description = EXCLUDED.description, owner = EXCLUDED.owner, ttl = EXCLUDED.ttl;`,
+			middleware.TenantFromCtx(ctx), meta.ID, meta.Unit, meta.Description, meta.Owner, meta.TTL,
+		)
+		return err
+	})
+}
+
+// Register stores metadata of metrics that have none yet.
+// Uses a single bulk insert skipping existing rows.
+func (repository *dbMetaRepository) Register(ctx context.Context, metas []models.Meta) error {
+	ids := make([]string, len(metas))
+	units := make([]string, len(metas))
+	descriptions := make([]string, len(metas))
+	owners := make([]string, len(metas))
+
+	for i, meta := range metas {
+		ids[i] = meta.ID
+		units[i] = meta.Unit
+		descriptions[i] = meta.Description
+		owners[i] = meta.Owner
+	}
+
+	return executeWithRetry(func() error {
+		_, err := repository.storage.Exec(
+			ctx,
+			`
+			INSERT INTO metric_meta (tenant, id, unit, description, owner)
+			SELECT $1, unnest($2::text[]), unnest($3::text[]), unnest($4::text[]), unnest($5::text[])
+			ON CONFLICT (tenant, id) DO NOTHING
+			`,
+			middleware.TenantFromCtx(ctx), ids, units, descriptions, owners,
+		)
+		return err
+	})
+}
+
+// Get retrieves the metadata of a metric by its ID.
+// Returns nil without error if the metric has no metadata.
+func (repository *dbMetaRepository) Get(ctx context.Context, id string) (*models.Meta, error) {
+	var meta *models.Meta
+	err := executeWithRetry(func() error {
+		var current models.Meta
+		err := repository.storage.QueryRow(
+			ctx,
+			"SELECT id, unit, description, owner, ttl FROM metric_meta WHERE tenant = $1 AND id = $2",
+			middleware.TenantFromCtx(ctx), id,
+		).Scan(&current.ID, &current.Unit, &current.Description, &current.Owner, &current.TTL)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		meta = &current
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// GetAll returns metadata of all metrics of the request tenant keyed by metric ID.
+func (repository *dbMetaRepository) GetAll(ctx context.Context) (map[string]models.Meta, error) {
+	var metas map[string]models.Meta
+	err := executeWithRetry(func() error {
+		rows, err := repository.storage.Query(
+			ctx,
+			"SELECT id, unit, description, owner, ttl FROM metric_meta WHERE tenant = $1;",
+			middleware.TenantFromCtx(ctx),
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		currentMetas := make(map[string]models.Meta)
+		for rows.Next() {
+			var meta models.Meta
+			if err := rows.Scan(&meta.ID, &meta.Unit, &meta.Description, &meta.Owner, &meta.TTL); err != nil {
+				return err
+			}
+			currentMetas[meta.ID] = meta
+		}
+
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		metas = currentMetas
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return metas, nil
+}
+
+// GetAllTenants returns metadata of all tenants,
+// partitioned by tenant, then keyed by metric ID.
+func (repository *dbMetaRepository) GetAllTenants(ctx context.Context) (map[string]map[string]models.Meta, error) {
+	var tenants map[string]map[string]models.Meta
+	err := executeWithRetry(func() error {
+		rows, err := repository.storage.Query(ctx, "SELECT tenant, id, unit, description, owner, ttl FROM metric_meta;")
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		currentTenants := make(map[string]map[string]models.Meta)
+		for rows.Next() {
+			var tenant string
+			var meta models.Meta
+			if err := rows.Scan(&tenant, &meta.ID, &meta.Unit, &meta.Description, &meta.Owner, &meta.TTL); err != nil {
+				return err
+			}
+			if currentTenants[tenant] == nil {
+				currentTenants[tenant] = make(map[string]models.Meta)
+			}
+			currentTenants[tenant][meta.ID] = meta
+		}
+
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		tenants = currentTenants
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
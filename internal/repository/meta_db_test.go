@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v4"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/storage"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var metaColumnNames = []string{"id", "unit", "description", "owner", "ttl"}
+
+func TestNewDBMetaRepository(t *testing.T) {
+	tests := []struct {
+		name        string
+		storage     storage.DB
+		expectError bool
+	}{
+		{
+			name:        "valid db connection",
+			storage:     storage.NewMockDB(t),
+			expectError: false,
+		},
+		{
+			name:        "nil storage",
+			storage:     nil,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := NewDBMetaRepository(tt.storage)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, repo)
+				assert.Contains(t, err.Error(), "storage is nil")
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, repo)
+			}
+		})
+	}
+}
+
+func TestDBMetaRepository_Put(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBMetaRepository(mock)
+	require.NoError(t, err)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO metric_meta (tenant, id, unit, description, owner, ttl)")).
+		WithArgs("team-a", "HeapAlloc", "bytes", "heap", "runtime", int64(3600)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	err = repo.Put(middleware.WithTenant(t.Context(), "team-a"), models.Meta{ID: "HeapAlloc", Unit: "bytes", Description: "heap", Owner: "runtime", TTL: 3600})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDBMetaRepository_Register(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBMetaRepository(mock)
+	require.NoError(t, err)
+
+	mock.ExpectExec(regexp.QuoteMeta("ON CONFLICT (tenant, id) DO NOTHING")).
+		WithArgs(
+			middleware.DefaultTenant,
+			[]string{"HeapAlloc", "PollCount"},
+			[]string{"bytes", "count"},
+			[]string{"", ""},
+			[]string{"", ""},
+		).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+	err = repo.Register(t.Context(), []models.Meta{
+		{ID: "HeapAlloc", Unit: "bytes"},
+		{ID: "PollCount", Unit: "count"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDBMetaRepository_Get(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBMetaRepository(mock)
+	require.NoError(t, err)
+
+	query := regexp.QuoteMeta("SELECT id, unit, description, owner, ttl FROM metric_meta WHERE tenant = $1 AND id = $2")
+
+	tests := []struct {
+		name        string
+		mockQuery   func()
+		expectMeta  *models.Meta
+		expectError bool
+	}{
+		{
+			name: "found",
+			mockQuery: func() {
+				mock.ExpectQuery(query).
+					WithArgs(middleware.DefaultTenant, "HeapAlloc").
+					WillReturnRows(pgxmock.NewRows(metaColumnNames).AddRow("HeapAlloc", "bytes", "", "", int64(0)))
+			},
+			expectMeta: &models.Meta{ID: "HeapAlloc", Unit: "bytes"},
+		},
+		{
+			name: "not found",
+			mockQuery: func() {
+				mock.ExpectQuery(query).
+					WithArgs(middleware.DefaultTenant, "HeapAlloc").
+					WillReturnRows(pgxmock.NewRows(metaColumnNames))
+			},
+			expectMeta: nil,
+		},
+		{
+			name: "query error",
+			mockQuery: func() {
+				mock.ExpectQuery(query).
+					WithArgs(middleware.DefaultTenant, "HeapAlloc").
+					WillReturnError(errors.New("db failure"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockQuery()
+
+			meta, err := repo.Get(t.Context(), "HeapAlloc")
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, meta)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectMeta, meta)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDBMetaRepository_GetAll(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBMetaRepository(mock)
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, unit, description, owner, ttl FROM metric_meta WHERE tenant = $1;")).
+		WithArgs("team-a").
+		WillReturnRows(pgxmock.NewRows(metaColumnNames).
+			AddRow("HeapAlloc", "bytes", "heap", "runtime", int64(60)).
+			AddRow("PollCount", "count", "", "", int64(0)))
+
+	metas, err := repo.GetAll(middleware.WithTenant(t.Context(), "team-a"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]models.Meta{
+		"HeapAlloc": {ID: "HeapAlloc", Unit: "bytes", Description: "heap", Owner: "runtime", TTL: 60},
+		"PollCount": {ID: "PollCount", Unit: "count"},
+	}, metas)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDBMetaRepository_GetAllTenants(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo, err := NewDBMetaRepository(mock)
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT tenant, id, unit, description, owner, ttl FROM metric_meta;")).
+		WillReturnRows(pgxmock.NewRows(append([]string{"tenant"}, metaColumnNames...)).
+			AddRow("default", "HeapAlloc", "bytes", "", "", int64(60)).
+			AddRow("team-a", "HeapAlloc", "kilobytes", "", "", int64(0)))
+
+	tenants, err := repo.GetAllTenants(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]models.Meta{
+		"default": {"HeapAlloc": {ID: "HeapAlloc", Unit: "bytes", TTL: 60}},
+		"team-a":  {"HeapAlloc": {ID: "HeapAlloc", Unit: "kilobytes"}},
+	}, tenants)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"sync"
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/storage"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMemoryMetaRepository(t *testing.T) {
+	tests := []struct {
+		name        string
+		storage     *storage.MemStorage
+		expectError bool
+	}{
+		{
+			name:        "valid storage",
+			storage:     storage.NewMemStorage(),
+			expectError: false,
+		},
+		{
+			name:        "nil storage",
+			storage:     nil,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := NewMemoryMetaRepository(tt.storage, &sync.RWMutex{})
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, repo)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, repo)
+			}
+		})
+	}
+}
+
+func TestMemoryMetaRepository_PutGet(t *testing.T) {
+	repo, err := NewMemoryMetaRepository(storage.NewMemStorage(), &sync.RWMutex{})
+	require.NoError(t, err)
+
+	meta, err := repo.Get(t.Context(), "HeapAlloc")
+	assert.NoError(t, err)
+	assert.Nil(t, meta)
+
+	stored := models.Meta{ID: "HeapAlloc", Unit: "bytes", Description: "heap", Owner: "runtime"}
+	require.NoError(t, repo.Put(t.Context(), stored))
+
+	meta, err = repo.Get(t.Context(), "HeapAlloc")
+	assert.NoError(t, err)
+	assert.Equal(t, &stored, meta)
+
+	replaced := models.Meta{ID: "HeapAlloc", Unit: "kilobytes"}
+	require.NoError(t, repo.Put(t.Context(), replaced))
+
+	meta, err = repo.Get(t.Context(), "HeapAlloc")
+	assert.NoError(t, err)
+	assert.Equal(t, &replaced, meta)
+}
+
+func TestMemoryMetaRepository_Register(t *testing.T) {
+	st := storage.NewMemStorage()
+	st.Meta[middleware.DefaultTenant] = map[string]models.Meta{
+		"HeapAlloc": {ID: "HeapAlloc", Unit: "kilobytes", Owner: "ops"},
+	}
+
+	repo, err := NewMemoryMetaRepository(st, &sync.RWMutex{})
+	require.NoError(t, err)
+
+	err = repo.Register(t.Context(), []models.Meta{
+		{ID: "HeapAlloc", Unit: "bytes"},
+		{ID: "PollCount", Unit: "count"},
+	})
+	require.NoError(t, err)
+
+	metas, err := repo.GetAll(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]models.Meta{
+		"HeapAlloc": {ID: "HeapAlloc", Unit: "kilobytes", Owner: "ops"},
+		"PollCount": {ID: "PollCount", Unit: "count"},
+	}, metas)
+
+	delete(metas, "PollCount")
+	assert.Contains(t, st.Meta[middleware.DefaultTenant], "PollCount")
+}
+
+func TestMemoryMetaRepository_tenants(t *testing.T) {
+	repo, err := NewMemoryMetaRepository(storage.NewMemStorage(), &sync.RWMutex{})
+	require.NoError(t, err)
+
+	teamA := middleware.WithTenant(t.Context(), "team-a")
+	teamB := middleware.WithTenant(t.Context(), "team-b")
+
+	require.NoError(t, repo.Put(teamA, models.Meta{ID: "requests", Unit: "count", TTL: 60}))
+	require.NoError(t, repo.Put(teamB, models.Meta{ID: "requests", Unit: "bytes"}))
+	require.NoError(t, repo.Register(teamB, []models.Meta{{ID: "latency", Unit: "seconds"}}))
+
+	meta, err := repo.Get(teamA, "requests")
+	assert.NoError(t, err)
+	assert.Equal(t, &models.Meta{ID: "requests", Unit: "count", TTL: 60}, meta)
+
+	meta, err = repo.Get(t.Context(), "requests")
+	assert.NoError(t, err)
+	assert.Nil(t, meta)
+
+	metas, err := repo.GetAll(teamA)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]models.Meta{"requests": {ID: "requests", Unit: "count", TTL: 60}}, metas)
+
+	tenants, err := repo.GetAllTenants(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]models.Meta{
+		"team-a": {"requests": {ID: "requests", Unit: "count", TTL: 60}},
+		"team-b": {
+			"requests": {ID: "requests", Unit: "bytes"},
+			"latency":  {ID: "latency", Unit: "seconds"},
+		},
+	}, tenants)
+}
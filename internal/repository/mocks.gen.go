@@ -6,18 +6,19 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	models "github.com/gabkaclassic/metrics/internal/model"
 	mock "github.com/stretchr/testify/mock"
 )
 
-// NewMockMetricsRepository creates a new instance of MockMetricsRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// NewMockAlertRuleRepository creates a new instance of MockAlertRuleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewMockMetricsRepository(t interface {
+func NewMockAlertRuleRepository(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *MockMetricsRepository {
-	mock := &MockMetricsRepository{}
+}) *MockAlertRuleRepository {
+	mock := &MockAlertRuleRepository{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -25,57 +26,1587 @@ func NewMockMetricsRepository(t interface {
 	return mock
 }
 
-// MockMetricsRepository is an autogenerated mock type for the MetricsRepository type
-type MockMetricsRepository struct {
+// MockAlertRuleRepository is an autogenerated mock type for the AlertRuleRepository type
+type MockAlertRuleRepository struct {
+	mock.Mock
+}
+
+type MockAlertRuleRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAlertRuleRepository) EXPECT() *MockAlertRuleRepository_Expecter {
+	return &MockAlertRuleRepository_Expecter{mock: &_m.Mock}
+}
+
+// Delete provides a mock function for the type MockAlertRuleRepository
+func (_mock *MockAlertRuleRepository) Delete(context1 context.Context, s string) (bool, error) {
+	ret := _mock.Called(context1, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return returnFunc(context1, s)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = returnFunc(context1, s)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(context1, s)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAlertRuleRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockAlertRuleRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+func (_e *MockAlertRuleRepository_Expecter) Delete(context1 interface{}, s interface{}) *MockAlertRuleRepository_Delete_Call {
+	return &MockAlertRuleRepository_Delete_Call{Call: _e.mock.On("Delete", context1, s)}
+}
+
+func (_c *MockAlertRuleRepository_Delete_Call) Run(run func(context1 context.Context, s string)) *MockAlertRuleRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAlertRuleRepository_Delete_Call) Return(b bool, err error) *MockAlertRuleRepository_Delete_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockAlertRuleRepository_Delete_Call) RunAndReturn(run func(context1 context.Context, s string) (bool, error)) *MockAlertRuleRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function for the type MockAlertRuleRepository
+func (_mock *MockAlertRuleRepository) Get(context1 context.Context, s string) (*models.AlertRule, error) {
+	ret := _mock.Called(context1, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *models.AlertRule
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.AlertRule, error)); ok {
+		return returnFunc(context1, s)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.AlertRule); ok {
+		r0 = returnFunc(context1, s)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AlertRule)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(context1, s)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAlertRuleRepository_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockAlertRuleRepository_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+func (_e *MockAlertRuleRepository_Expecter) Get(context1 interface{}, s interface{}) *MockAlertRuleRepository_Get_Call {
+	return &MockAlertRuleRepository_Get_Call{Call: _e.mock.On("Get", context1, s)}
+}
+
+func (_c *MockAlertRuleRepository_Get_Call) Run(run func(context1 context.Context, s string)) *MockAlertRuleRepository_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAlertRuleRepository_Get_Call) Return(alertRule *models.AlertRule, err error) *MockAlertRuleRepository_Get_Call {
+	_c.Call.Return(alertRule, err)
+	return _c
+}
+
+func (_c *MockAlertRuleRepository_Get_Call) RunAndReturn(run func(context1 context.Context, s string) (*models.AlertRule, error)) *MockAlertRuleRepository_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAll provides a mock function for the type MockAlertRuleRepository
+func (_mock *MockAlertRuleRepository) GetAll(context1 context.Context) ([]models.AlertRule, error) {
+	ret := _mock.Called(context1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAll")
+	}
+
+	var r0 []models.AlertRule
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.AlertRule, error)); ok {
+		return returnFunc(context1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.AlertRule); ok {
+		r0 = returnFunc(context1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AlertRule)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(context1)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAlertRuleRepository_GetAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAll'
+type MockAlertRuleRepository_GetAll_Call struct {
+	*mock.Call
+}
+
+// GetAll is a helper method to define mock.On call
+//   - context1 context.Context
+func (_e *MockAlertRuleRepository_Expecter) GetAll(context1 interface{}) *MockAlertRuleRepository_GetAll_Call {
+	return &MockAlertRuleRepository_GetAll_Call{Call: _e.mock.On("GetAll", context1)}
+}
+
+func (_c *MockAlertRuleRepository_GetAll_Call) Run(run func(context1 context.Context)) *MockAlertRuleRepository_GetAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAlertRuleRepository_GetAll_Call) Return(alertRules []models.AlertRule, err error) *MockAlertRuleRepository_GetAll_Call {
+	_c.Call.Return(alertRules, err)
+	return _c
+}
+
+func (_c *MockAlertRuleRepository_GetAll_Call) RunAndReturn(run func(context1 context.Context) ([]models.AlertRule, error)) *MockAlertRuleRepository_GetAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Put provides a mock function for the type MockAlertRuleRepository
+func (_mock *MockAlertRuleRepository) Put(context1 context.Context, alertRule models.AlertRule) error {
+	ret := _mock.Called(context1, alertRule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Put")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.AlertRule) error); ok {
+		r0 = returnFunc(context1, alertRule)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockAlertRuleRepository_Put_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Put'
+type MockAlertRuleRepository_Put_Call struct {
+	*mock.Call
+}
+
+// Put is a helper method to define mock.On call
+//   - context1 context.Context
+//   - alertRule models.AlertRule
+func (_e *MockAlertRuleRepository_Expecter) Put(context1 interface{}, alertRule interface{}) *MockAlertRuleRepository_Put_Call {
+	return &MockAlertRuleRepository_Put_Call{Call: _e.mock.On("Put", context1, alertRule)}
+}
+
+func (_c *MockAlertRuleRepository_Put_Call) Run(run func(context1 context.Context, alertRule models.AlertRule)) *MockAlertRuleRepository_Put_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.AlertRule
+		if args[1] != nil {
+			arg1 = args[1].(models.AlertRule)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAlertRuleRepository_Put_Call) Return(err error) *MockAlertRuleRepository_Put_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockAlertRuleRepository_Put_Call) RunAndReturn(run func(context1 context.Context, alertRule models.AlertRule) error) *MockAlertRuleRepository_Put_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIdempotencyRepository creates a new instance of MockIdempotencyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIdempotencyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIdempotencyRepository {
+	mock := &MockIdempotencyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIdempotencyRepository is an autogenerated mock type for the IdempotencyRepository type
+type MockIdempotencyRepository struct {
 	mock.Mock
 }
 
-type MockMetricsRepository_Expecter struct {
-	mock *mock.Mock
+type MockIdempotencyRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIdempotencyRepository) EXPECT() *MockIdempotencyRepository_Expecter {
+	return &MockIdempotencyRepository_Expecter{mock: &_m.Mock}
+}
+
+// Complete provides a mock function for the type MockIdempotencyRepository
+func (_mock *MockIdempotencyRepository) Complete(context1 context.Context, s string, idempotentResponse models.IdempotentResponse) error {
+	ret := _mock.Called(context1, s, idempotentResponse)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.IdempotentResponse) error); ok {
+		r0 = returnFunc(context1, s, idempotentResponse)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIdempotencyRepository_Complete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Complete'
+type MockIdempotencyRepository_Complete_Call struct {
+	*mock.Call
+}
+
+// Complete is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+//   - idempotentResponse models.IdempotentResponse
+func (_e *MockIdempotencyRepository_Expecter) Complete(context1 interface{}, s interface{}, idempotentResponse interface{}) *MockIdempotencyRepository_Complete_Call {
+	return &MockIdempotencyRepository_Complete_Call{Call: _e.mock.On("Complete", context1, s, idempotentResponse)}
+}
+
+func (_c *MockIdempotencyRepository_Complete_Call) Run(run func(context1 context.Context, s string, idempotentResponse models.IdempotentResponse)) *MockIdempotencyRepository_Complete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 models.IdempotentResponse
+		if args[2] != nil {
+			arg2 = args[2].(models.IdempotentResponse)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIdempotencyRepository_Complete_Call) Return(err error) *MockIdempotencyRepository_Complete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIdempotencyRepository_Complete_Call) RunAndReturn(run func(context1 context.Context, s string, idempotentResponse models.IdempotentResponse) error) *MockIdempotencyRepository_Complete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Expire provides a mock function for the type MockIdempotencyRepository
+func (_mock *MockIdempotencyRepository) Expire(context1 context.Context, time1 time.Time) (int, error) {
+	ret := _mock.Called(context1, time1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Expire")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return returnFunc(context1, time1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = returnFunc(context1, time1)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = returnFunc(context1, time1)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIdempotencyRepository_Expire_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Expire'
+type MockIdempotencyRepository_Expire_Call struct {
+	*mock.Call
+}
+
+// Expire is a helper method to define mock.On call
+//   - context1 context.Context
+//   - time1 time.Time
+func (_e *MockIdempotencyRepository_Expecter) Expire(context1 interface{}, time1 interface{}) *MockIdempotencyRepository_Expire_Call {
+	return &MockIdempotencyRepository_Expire_Call{Call: _e.mock.On("Expire", context1, time1)}
+}
+
+func (_c *MockIdempotencyRepository_Expire_Call) Run(run func(context1 context.Context, time1 time.Time)) *MockIdempotencyRepository_Expire_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIdempotencyRepository_Expire_Call) Return(n int, err error) *MockIdempotencyRepository_Expire_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockIdempotencyRepository_Expire_Call) RunAndReturn(run func(context1 context.Context, time1 time.Time) (int, error)) *MockIdempotencyRepository_Expire_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Release provides a mock function for the type MockIdempotencyRepository
+func (_mock *MockIdempotencyRepository) Release(context1 context.Context, s string) error {
+	ret := _mock.Called(context1, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(context1, s)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIdempotencyRepository_Release_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Release'
+type MockIdempotencyRepository_Release_Call struct {
+	*mock.Call
+}
+
+// Release is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+func (_e *MockIdempotencyRepository_Expecter) Release(context1 interface{}, s interface{}) *MockIdempotencyRepository_Release_Call {
+	return &MockIdempotencyRepository_Release_Call{Call: _e.mock.On("Release", context1, s)}
+}
+
+func (_c *MockIdempotencyRepository_Release_Call) Run(run func(context1 context.Context, s string)) *MockIdempotencyRepository_Release_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIdempotencyRepository_Release_Call) Return(err error) *MockIdempotencyRepository_Release_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIdempotencyRepository_Release_Call) RunAndReturn(run func(context1 context.Context, s string) error) *MockIdempotencyRepository_Release_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Reserve provides a mock function for the type MockIdempotencyRepository
+func (_mock *MockIdempotencyRepository) Reserve(context1 context.Context, s string, s1 string, time1 time.Time) (models.IdempotentResponse, bool, error) {
+	ret := _mock.Called(context1, s, s1, time1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reserve")
+	}
+
+	var r0 models.IdempotentResponse
+	var r1 bool
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (models.IdempotentResponse, bool, error)); ok {
+		return returnFunc(context1, s, s1, time1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Time) models.IdempotentResponse); ok {
+		r0 = returnFunc(context1, s, s1, time1)
+	} else {
+		r0 = ret.Get(0).(models.IdempotentResponse)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, time.Time) bool); ok {
+		r1 = returnFunc(context1, s, s1, time1)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, string, time.Time) error); ok {
+		r2 = returnFunc(context1, s, s1, time1)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockIdempotencyRepository_Reserve_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reserve'
+type MockIdempotencyRepository_Reserve_Call struct {
+	*mock.Call
+}
+
+// Reserve is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+//   - s1 string
+//   - time1 time.Time
+func (_e *MockIdempotencyRepository_Expecter) Reserve(context1 interface{}, s interface{}, s1 interface{}, time1 interface{}) *MockIdempotencyRepository_Reserve_Call {
+	return &MockIdempotencyRepository_Reserve_Call{Call: _e.mock.On("Reserve", context1, s, s1, time1)}
+}
+
+func (_c *MockIdempotencyRepository_Reserve_Call) Run(run func(context1 context.Context, s string, s1 string, time1 time.Time)) *MockIdempotencyRepository_Reserve_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIdempotencyRepository_Reserve_Call) Return(idempotentResponse models.IdempotentResponse, b bool, err error) *MockIdempotencyRepository_Reserve_Call {
+	_c.Call.Return(idempotentResponse, b, err)
+	return _c
+}
+
+func (_c *MockIdempotencyRepository_Reserve_Call) RunAndReturn(run func(context1 context.Context, s string, s1 string, time1 time.Time) (models.IdempotentResponse, bool, error)) *MockIdempotencyRepository_Reserve_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockMetricsRepository creates a new instance of MockMetricsRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMetricsRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMetricsRepository {
+	mock := &MockMetricsRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockMetricsRepository is an autogenerated mock type for the MetricsRepository type
+type MockMetricsRepository struct {
+	mock.Mock
+}
+
+type MockMetricsRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMetricsRepository) EXPECT() *MockMetricsRepository_Expecter {
+	return &MockMetricsRepository_Expecter{mock: &_m.Mock}
+}
+
+// Add provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) Add(context1 context.Context, metrics models.Metrics) error {
+	ret := _mock.Called(context1, metrics)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Add")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Metrics) error); ok {
+		r0 = returnFunc(context1, metrics)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMetricsRepository_Add_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Add'
+type MockMetricsRepository_Add_Call struct {
+	*mock.Call
+}
+
+// Add is a helper method to define mock.On call
+//   - context1 context.Context
+//   - metrics models.Metrics
+func (_e *MockMetricsRepository_Expecter) Add(context1 interface{}, metrics interface{}) *MockMetricsRepository_Add_Call {
+	return &MockMetricsRepository_Add_Call{Call: _e.mock.On("Add", context1, metrics)}
+}
+
+func (_c *MockMetricsRepository_Add_Call) Run(run func(context1 context.Context, metrics models.Metrics)) *MockMetricsRepository_Add_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.Metrics
+		if args[1] != nil {
+			arg1 = args[1].(models.Metrics)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsRepository_Add_Call) Return(err error) *MockMetricsRepository_Add_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMetricsRepository_Add_Call) RunAndReturn(run func(context1 context.Context, metrics models.Metrics) error) *MockMetricsRepository_Add_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AddAll provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) AddAll(context1 context.Context, metricss []models.Metrics) error {
+	ret := _mock.Called(context1, metricss)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddAll")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.Metrics) error); ok {
+		r0 = returnFunc(context1, metricss)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMetricsRepository_AddAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddAll'
+type MockMetricsRepository_AddAll_Call struct {
+	*mock.Call
+}
+
+// AddAll is a helper method to define mock.On call
+//   - context1 context.Context
+//   - metricss []models.Metrics
+func (_e *MockMetricsRepository_Expecter) AddAll(context1 interface{}, metricss interface{}) *MockMetricsRepository_AddAll_Call {
+	return &MockMetricsRepository_AddAll_Call{Call: _e.mock.On("AddAll", context1, metricss)}
+}
+
+func (_c *MockMetricsRepository_AddAll_Call) Run(run func(context1 context.Context, metricss []models.Metrics)) *MockMetricsRepository_AddAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []models.Metrics
+		if args[1] != nil {
+			arg1 = args[1].([]models.Metrics)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsRepository_AddAll_Call) Return(err error) *MockMetricsRepository_AddAll_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMetricsRepository_AddAll_Call) RunAndReturn(run func(context1 context.Context, metricss []models.Metrics) error) *MockMetricsRepository_AddAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) Delete(context1 context.Context, deleteQuery models.DeleteQuery) ([]models.Metrics, error) {
+	ret := _mock.Called(context1, deleteQuery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 []models.Metrics
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.DeleteQuery) ([]models.Metrics, error)); ok {
+		return returnFunc(context1, deleteQuery)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.DeleteQuery) []models.Metrics); ok {
+		r0 = returnFunc(context1, deleteQuery)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Metrics)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.DeleteQuery) error); ok {
+		r1 = returnFunc(context1, deleteQuery)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMetricsRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockMetricsRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - context1 context.Context
+//   - deleteQuery models.DeleteQuery
+func (_e *MockMetricsRepository_Expecter) Delete(context1 interface{}, deleteQuery interface{}) *MockMetricsRepository_Delete_Call {
+	return &MockMetricsRepository_Delete_Call{Call: _e.mock.On("Delete", context1, deleteQuery)}
+}
+
+func (_c *MockMetricsRepository_Delete_Call) Run(run func(context1 context.Context, deleteQuery models.DeleteQuery)) *MockMetricsRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.DeleteQuery
+		if args[1] != nil {
+			arg1 = args[1].(models.DeleteQuery)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsRepository_Delete_Call) Return(metricss []models.Metrics, err error) *MockMetricsRepository_Delete_Call {
+	_c.Call.Return(metricss, err)
+	return _c
+}
+
+func (_c *MockMetricsRepository_Delete_Call) RunAndReturn(run func(context1 context.Context, deleteQuery models.DeleteQuery) ([]models.Metrics, error)) *MockMetricsRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Expire provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) Expire(context1 context.Context, expirePolicy models.ExpirePolicy) ([]models.Metrics, error) {
+	ret := _mock.Called(context1, expirePolicy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Expire")
+	}
+
+	var r0 []models.Metrics
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ExpirePolicy) ([]models.Metrics, error)); ok {
+		return returnFunc(context1, expirePolicy)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ExpirePolicy) []models.Metrics); ok {
+		r0 = returnFunc(context1, expirePolicy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Metrics)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.ExpirePolicy) error); ok {
+		r1 = returnFunc(context1, expirePolicy)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMetricsRepository_Expire_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Expire'
+type MockMetricsRepository_Expire_Call struct {
+	*mock.Call
+}
+
+// Expire is a helper method to define mock.On call
+//   - context1 context.Context
+//   - expirePolicy models.ExpirePolicy
+func (_e *MockMetricsRepository_Expecter) Expire(context1 interface{}, expirePolicy interface{}) *MockMetricsRepository_Expire_Call {
+	return &MockMetricsRepository_Expire_Call{Call: _e.mock.On("Expire", context1, expirePolicy)}
+}
+
+func (_c *MockMetricsRepository_Expire_Call) Run(run func(context1 context.Context, expirePolicy models.ExpirePolicy)) *MockMetricsRepository_Expire_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.ExpirePolicy
+		if args[1] != nil {
+			arg1 = args[1].(models.ExpirePolicy)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsRepository_Expire_Call) Return(metricss []models.Metrics, err error) *MockMetricsRepository_Expire_Call {
+	_c.Call.Return(metricss, err)
+	return _c
+}
+
+func (_c *MockMetricsRepository_Expire_Call) RunAndReturn(run func(context1 context.Context, expirePolicy models.ExpirePolicy) ([]models.Metrics, error)) *MockMetricsRepository_Expire_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) Get(context1 context.Context, s string, stringToS map[string]string) (*models.Metrics, error) {
+	ret := _mock.Called(context1, s, stringToS)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *models.Metrics
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, map[string]string) (*models.Metrics, error)); ok {
+		return returnFunc(context1, s, stringToS)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, map[string]string) *models.Metrics); ok {
+		r0 = returnFunc(context1, s, stringToS)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Metrics)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, map[string]string) error); ok {
+		r1 = returnFunc(context1, s, stringToS)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMetricsRepository_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockMetricsRepository_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+//   - stringToS map[string]string
+func (_e *MockMetricsRepository_Expecter) Get(context1 interface{}, s interface{}, stringToS interface{}) *MockMetricsRepository_Get_Call {
+	return &MockMetricsRepository_Get_Call{Call: _e.mock.On("Get", context1, s, stringToS)}
+}
+
+func (_c *MockMetricsRepository_Get_Call) Run(run func(context1 context.Context, s string, stringToS map[string]string)) *MockMetricsRepository_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 map[string]string
+		if args[2] != nil {
+			arg2 = args[2].(map[string]string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsRepository_Get_Call) Return(metrics *models.Metrics, err error) *MockMetricsRepository_Get_Call {
+	_c.Call.Return(metrics, err)
+	return _c
+}
+
+func (_c *MockMetricsRepository_Get_Call) RunAndReturn(run func(context1 context.Context, s string, stringToS map[string]string) (*models.Metrics, error)) *MockMetricsRepository_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAll provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) GetAll(context1 context.Context) (map[string]any, error) {
+	ret := _mock.Called(context1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAll")
+	}
+
+	var r0 map[string]any
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (map[string]any, error)); ok {
+		return returnFunc(context1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) map[string]any); ok {
+		r0 = returnFunc(context1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]any)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(context1)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMetricsRepository_GetAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAll'
+type MockMetricsRepository_GetAll_Call struct {
+	*mock.Call
+}
+
+// GetAll is a helper method to define mock.On call
+//   - context1 context.Context
+func (_e *MockMetricsRepository_Expecter) GetAll(context1 interface{}) *MockMetricsRepository_GetAll_Call {
+	return &MockMetricsRepository_GetAll_Call{Call: _e.mock.On("GetAll", context1)}
+}
+
+func (_c *MockMetricsRepository_GetAll_Call) Run(run func(context1 context.Context)) *MockMetricsRepository_GetAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsRepository_GetAll_Call) Return(stringToV map[string]any, err error) *MockMetricsRepository_GetAll_Call {
+	_c.Call.Return(stringToV, err)
+	return _c
+}
+
+func (_c *MockMetricsRepository_GetAll_Call) RunAndReturn(run func(context1 context.Context) (map[string]any, error)) *MockMetricsRepository_GetAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllMetrics provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) GetAllMetrics(context1 context.Context) ([]models.Metrics, error) {
+	ret := _mock.Called(context1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllMetrics")
+	}
+
+	var r0 []models.Metrics
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.Metrics, error)); ok {
+		return returnFunc(context1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.Metrics); ok {
+		r0 = returnFunc(context1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Metrics)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(context1)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMetricsRepository_GetAllMetrics_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllMetrics'
+type MockMetricsRepository_GetAllMetrics_Call struct {
+	*mock.Call
+}
+
+// GetAllMetrics is a helper method to define mock.On call
+//   - context1 context.Context
+func (_e *MockMetricsRepository_Expecter) GetAllMetrics(context1 interface{}) *MockMetricsRepository_GetAllMetrics_Call {
+	return &MockMetricsRepository_GetAllMetrics_Call{Call: _e.mock.On("GetAllMetrics", context1)}
+}
+
+func (_c *MockMetricsRepository_GetAllMetrics_Call) Run(run func(context1 context.Context)) *MockMetricsRepository_GetAllMetrics_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsRepository_GetAllMetrics_Call) Return(metricss []models.Metrics, err error) *MockMetricsRepository_GetAllMetrics_Call {
+	_c.Call.Return(metricss, err)
+	return _c
+}
+
+func (_c *MockMetricsRepository_GetAllMetrics_Call) RunAndReturn(run func(context1 context.Context) ([]models.Metrics, error)) *MockMetricsRepository_GetAllMetrics_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMany provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) GetMany(context1 context.Context, seriesRefs []models.SeriesRef) ([]models.Metrics, error) {
+	ret := _mock.Called(context1, seriesRefs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMany")
+	}
+
+	var r0 []models.Metrics
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.SeriesRef) ([]models.Metrics, error)); ok {
+		return returnFunc(context1, seriesRefs)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.SeriesRef) []models.Metrics); ok {
+		r0 = returnFunc(context1, seriesRefs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Metrics)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []models.SeriesRef) error); ok {
+		r1 = returnFunc(context1, seriesRefs)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMetricsRepository_GetMany_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMany'
+type MockMetricsRepository_GetMany_Call struct {
+	*mock.Call
+}
+
+// GetMany is a helper method to define mock.On call
+//   - context1 context.Context
+//   - seriesRefs []models.SeriesRef
+func (_e *MockMetricsRepository_Expecter) GetMany(context1 interface{}, seriesRefs interface{}) *MockMetricsRepository_GetMany_Call {
+	return &MockMetricsRepository_GetMany_Call{Call: _e.mock.On("GetMany", context1, seriesRefs)}
+}
+
+func (_c *MockMetricsRepository_GetMany_Call) Run(run func(context1 context.Context, seriesRefs []models.SeriesRef)) *MockMetricsRepository_GetMany_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []models.SeriesRef
+		if args[1] != nil {
+			arg1 = args[1].([]models.SeriesRef)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsRepository_GetMany_Call) Return(metricss []models.Metrics, err error) *MockMetricsRepository_GetMany_Call {
+	_c.Call.Return(metricss, err)
+	return _c
+}
+
+func (_c *MockMetricsRepository_GetMany_Call) RunAndReturn(run func(context1 context.Context, seriesRefs []models.SeriesRef) ([]models.Metrics, error)) *MockMetricsRepository_GetMany_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRange provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) GetRange(context1 context.Context, rangeQuery models.RangeQuery) ([]models.Point, error) {
+	ret := _mock.Called(context1, rangeQuery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRange")
+	}
+
+	var r0 []models.Point
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RangeQuery) ([]models.Point, error)); ok {
+		return returnFunc(context1, rangeQuery)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RangeQuery) []models.Point); ok {
+		r0 = returnFunc(context1, rangeQuery)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Point)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.RangeQuery) error); ok {
+		r1 = returnFunc(context1, rangeQuery)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMetricsRepository_GetRange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRange'
+type MockMetricsRepository_GetRange_Call struct {
+	*mock.Call
+}
+
+// GetRange is a helper method to define mock.On call
+//   - context1 context.Context
+//   - rangeQuery models.RangeQuery
+func (_e *MockMetricsRepository_Expecter) GetRange(context1 interface{}, rangeQuery interface{}) *MockMetricsRepository_GetRange_Call {
+	return &MockMetricsRepository_GetRange_Call{Call: _e.mock.On("GetRange", context1, rangeQuery)}
+}
+
+func (_c *MockMetricsRepository_GetRange_Call) Run(run func(context1 context.Context, rangeQuery models.RangeQuery)) *MockMetricsRepository_GetRange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.RangeQuery
+		if args[1] != nil {
+			arg1 = args[1].(models.RangeQuery)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsRepository_GetRange_Call) Return(points []models.Point, err error) *MockMetricsRepository_GetRange_Call {
+	_c.Call.Return(points, err)
+	return _c
+}
+
+func (_c *MockMetricsRepository_GetRange_Call) RunAndReturn(run func(context1 context.Context, rangeQuery models.RangeQuery) ([]models.Point, error)) *MockMetricsRepository_GetRange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTypes provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) GetTypes(context1 context.Context, seriesRefs []models.SeriesRef) (map[string]string, error) {
+	ret := _mock.Called(context1, seriesRefs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTypes")
+	}
+
+	var r0 map[string]string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.SeriesRef) (map[string]string, error)); ok {
+		return returnFunc(context1, seriesRefs)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.SeriesRef) map[string]string); ok {
+		r0 = returnFunc(context1, seriesRefs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []models.SeriesRef) error); ok {
+		r1 = returnFunc(context1, seriesRefs)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMetricsRepository_GetTypes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTypes'
+type MockMetricsRepository_GetTypes_Call struct {
+	*mock.Call
+}
+
+// GetTypes is a helper method to define mock.On call
+//   - context1 context.Context
+//   - seriesRefs []models.SeriesRef
+func (_e *MockMetricsRepository_Expecter) GetTypes(context1 interface{}, seriesRefs interface{}) *MockMetricsRepository_GetTypes_Call {
+	return &MockMetricsRepository_GetTypes_Call{Call: _e.mock.On("GetTypes", context1, seriesRefs)}
+}
+
+func (_c *MockMetricsRepository_GetTypes_Call) Run(run func(context1 context.Context, seriesRefs []models.SeriesRef)) *MockMetricsRepository_GetTypes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []models.SeriesRef
+		if args[1] != nil {
+			arg1 = args[1].([]models.SeriesRef)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsRepository_GetTypes_Call) Return(stringToS map[string]string, err error) *MockMetricsRepository_GetTypes_Call {
+	_c.Call.Return(stringToS, err)
+	return _c
+}
+
+func (_c *MockMetricsRepository_GetTypes_Call) RunAndReturn(run func(context1 context.Context, seriesRefs []models.SeriesRef) (map[string]string, error)) *MockMetricsRepository_GetTypes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUpdated provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) GetUpdated(context1 context.Context) (map[string]time.Time, error) {
+	ret := _mock.Called(context1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUpdated")
+	}
+
+	var r0 map[string]time.Time
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (map[string]time.Time, error)); ok {
+		return returnFunc(context1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) map[string]time.Time); ok {
+		r0 = returnFunc(context1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]time.Time)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(context1)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMetricsRepository_GetUpdated_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUpdated'
+type MockMetricsRepository_GetUpdated_Call struct {
+	*mock.Call
+}
+
+// GetUpdated is a helper method to define mock.On call
+//   - context1 context.Context
+func (_e *MockMetricsRepository_Expecter) GetUpdated(context1 interface{}) *MockMetricsRepository_GetUpdated_Call {
+	return &MockMetricsRepository_GetUpdated_Call{Call: _e.mock.On("GetUpdated", context1)}
+}
+
+func (_c *MockMetricsRepository_GetUpdated_Call) Run(run func(context1 context.Context)) *MockMetricsRepository_GetUpdated_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsRepository_GetUpdated_Call) Return(stringToTime map[string]time.Time, err error) *MockMetricsRepository_GetUpdated_Call {
+	_c.Call.Return(stringToTime, err)
+	return _c
+}
+
+func (_c *MockMetricsRepository_GetUpdated_Call) RunAndReturn(run func(context1 context.Context) (map[string]time.Time, error)) *MockMetricsRepository_GetUpdated_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Increment provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) Increment(context1 context.Context, metrics models.Metrics) error {
+	ret := _mock.Called(context1, metrics)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Increment")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Metrics) error); ok {
+		r0 = returnFunc(context1, metrics)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMetricsRepository_Increment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Increment'
+type MockMetricsRepository_Increment_Call struct {
+	*mock.Call
+}
+
+// Increment is a helper method to define mock.On call
+//   - context1 context.Context
+//   - metrics models.Metrics
+func (_e *MockMetricsRepository_Expecter) Increment(context1 interface{}, metrics interface{}) *MockMetricsRepository_Increment_Call {
+	return &MockMetricsRepository_Increment_Call{Call: _e.mock.On("Increment", context1, metrics)}
+}
+
+func (_c *MockMetricsRepository_Increment_Call) Run(run func(context1 context.Context, metrics models.Metrics)) *MockMetricsRepository_Increment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.Metrics
+		if args[1] != nil {
+			arg1 = args[1].(models.Metrics)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsRepository_Increment_Call) Return(err error) *MockMetricsRepository_Increment_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMetricsRepository_Increment_Call) RunAndReturn(run func(context1 context.Context, metrics models.Metrics) error) *MockMetricsRepository_Increment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IncrementAll provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) IncrementAll(context1 context.Context, metricss []models.Metrics) error {
+	ret := _mock.Called(context1, metricss)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementAll")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.Metrics) error); ok {
+		r0 = returnFunc(context1, metricss)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMetricsRepository_IncrementAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncrementAll'
+type MockMetricsRepository_IncrementAll_Call struct {
+	*mock.Call
+}
+
+// IncrementAll is a helper method to define mock.On call
+//   - context1 context.Context
+//   - metricss []models.Metrics
+func (_e *MockMetricsRepository_Expecter) IncrementAll(context1 interface{}, metricss interface{}) *MockMetricsRepository_IncrementAll_Call {
+	return &MockMetricsRepository_IncrementAll_Call{Call: _e.mock.On("IncrementAll", context1, metricss)}
+}
+
+func (_c *MockMetricsRepository_IncrementAll_Call) Run(run func(context1 context.Context, metricss []models.Metrics)) *MockMetricsRepository_IncrementAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []models.Metrics
+		if args[1] != nil {
+			arg1 = args[1].([]models.Metrics)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsRepository_IncrementAll_Call) Return(err error) *MockMetricsRepository_IncrementAll_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMetricsRepository_IncrementAll_Call) RunAndReturn(run func(context1 context.Context, metricss []models.Metrics) error) *MockMetricsRepository_IncrementAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) List(context1 context.Context, listQuery models.ListQuery) ([]models.Metrics, error) {
+	ret := _mock.Called(context1, listQuery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []models.Metrics
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ListQuery) ([]models.Metrics, error)); ok {
+		return returnFunc(context1, listQuery)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ListQuery) []models.Metrics); ok {
+		r0 = returnFunc(context1, listQuery)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Metrics)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.ListQuery) error); ok {
+		r1 = returnFunc(context1, listQuery)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMetricsRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockMetricsRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - context1 context.Context
+//   - listQuery models.ListQuery
+func (_e *MockMetricsRepository_Expecter) List(context1 interface{}, listQuery interface{}) *MockMetricsRepository_List_Call {
+	return &MockMetricsRepository_List_Call{Call: _e.mock.On("List", context1, listQuery)}
+}
+
+func (_c *MockMetricsRepository_List_Call) Run(run func(context1 context.Context, listQuery models.ListQuery)) *MockMetricsRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.ListQuery
+		if args[1] != nil {
+			arg1 = args[1].(models.ListQuery)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsRepository_List_Call) Return(metricss []models.Metrics, err error) *MockMetricsRepository_List_Call {
+	_c.Call.Return(metricss, err)
+	return _c
+}
+
+func (_c *MockMetricsRepository_List_Call) RunAndReturn(run func(context1 context.Context, listQuery models.ListQuery) ([]models.Metrics, error)) *MockMetricsRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Merge provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) Merge(context1 context.Context, metrics models.Metrics) error {
+	ret := _mock.Called(context1, metrics)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Merge")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Metrics) error); ok {
+		r0 = returnFunc(context1, metrics)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMetricsRepository_Merge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Merge'
+type MockMetricsRepository_Merge_Call struct {
+	*mock.Call
+}
+
+// Merge is a helper method to define mock.On call
+//   - context1 context.Context
+//   - metrics models.Metrics
+func (_e *MockMetricsRepository_Expecter) Merge(context1 interface{}, metrics interface{}) *MockMetricsRepository_Merge_Call {
+	return &MockMetricsRepository_Merge_Call{Call: _e.mock.On("Merge", context1, metrics)}
+}
+
+func (_c *MockMetricsRepository_Merge_Call) Run(run func(context1 context.Context, metrics models.Metrics)) *MockMetricsRepository_Merge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.Metrics
+		if args[1] != nil {
+			arg1 = args[1].(models.Metrics)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsRepository_Merge_Call) Return(err error) *MockMetricsRepository_Merge_Call {
+	_c.Call.Return(err)
+	return _c
 }
 
-func (_m *MockMetricsRepository) EXPECT() *MockMetricsRepository_Expecter {
-	return &MockMetricsRepository_Expecter{mock: &_m.Mock}
+func (_c *MockMetricsRepository_Merge_Call) RunAndReturn(run func(context1 context.Context, metrics models.Metrics) error) *MockMetricsRepository_Merge_Call {
+	_c.Call.Return(run)
+	return _c
 }
 
-// Add provides a mock function for the type MockMetricsRepository
-func (_mock *MockMetricsRepository) Add(context1 context.Context, metrics models.Metrics) error {
-	ret := _mock.Called(context1, metrics)
+// MergeAll provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) MergeAll(context1 context.Context, metricss []models.Metrics) error {
+	ret := _mock.Called(context1, metricss)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Add")
+		panic("no return value specified for MergeAll")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Metrics) error); ok {
-		r0 = returnFunc(context1, metrics)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.Metrics) error); ok {
+		r0 = returnFunc(context1, metricss)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockMetricsRepository_Add_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Add'
-type MockMetricsRepository_Add_Call struct {
+// MockMetricsRepository_MergeAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MergeAll'
+type MockMetricsRepository_MergeAll_Call struct {
 	*mock.Call
 }
 
-// Add is a helper method to define mock.On call
+// MergeAll is a helper method to define mock.On call
 //   - context1 context.Context
-//   - metrics models.Metrics
-func (_e *MockMetricsRepository_Expecter) Add(context1 interface{}, metrics interface{}) *MockMetricsRepository_Add_Call {
-	return &MockMetricsRepository_Add_Call{Call: _e.mock.On("Add", context1, metrics)}
+//   - metricss []models.Metrics
+func (_e *MockMetricsRepository_Expecter) MergeAll(context1 interface{}, metricss interface{}) *MockMetricsRepository_MergeAll_Call {
+	return &MockMetricsRepository_MergeAll_Call{Call: _e.mock.On("MergeAll", context1, metricss)}
 }
 
-func (_c *MockMetricsRepository_Add_Call) Run(run func(context1 context.Context, metrics models.Metrics)) *MockMetricsRepository_Add_Call {
+func (_c *MockMetricsRepository_MergeAll_Call) Run(run func(context1 context.Context, metricss []models.Metrics)) *MockMetricsRepository_MergeAll_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 models.Metrics
+		var arg1 []models.Metrics
 		if args[1] != nil {
-			arg1 = args[1].(models.Metrics)
+			arg1 = args[1].([]models.Metrics)
 		}
 		run(
 			arg0,
@@ -85,22 +1616,88 @@ func (_c *MockMetricsRepository_Add_Call) Run(run func(context1 context.Context,
 	return _c
 }
 
-func (_c *MockMetricsRepository_Add_Call) Return(err error) *MockMetricsRepository_Add_Call {
+func (_c *MockMetricsRepository_MergeAll_Call) Return(err error) *MockMetricsRepository_MergeAll_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockMetricsRepository_Add_Call) RunAndReturn(run func(context1 context.Context, metrics models.Metrics) error) *MockMetricsRepository_Add_Call {
+func (_c *MockMetricsRepository_MergeAll_Call) RunAndReturn(run func(context1 context.Context, metricss []models.Metrics) error) *MockMetricsRepository_MergeAll_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// AddAll provides a mock function for the type MockMetricsRepository
-func (_mock *MockMetricsRepository) AddAll(context1 context.Context, metricss []models.Metrics) error {
+// PruneHistory provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) PruneHistory(context1 context.Context, time1 time.Time) (int, error) {
+	ret := _mock.Called(context1, time1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PruneHistory")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return returnFunc(context1, time1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = returnFunc(context1, time1)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = returnFunc(context1, time1)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMetricsRepository_PruneHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PruneHistory'
+type MockMetricsRepository_PruneHistory_Call struct {
+	*mock.Call
+}
+
+// PruneHistory is a helper method to define mock.On call
+//   - context1 context.Context
+//   - time1 time.Time
+func (_e *MockMetricsRepository_Expecter) PruneHistory(context1 interface{}, time1 interface{}) *MockMetricsRepository_PruneHistory_Call {
+	return &MockMetricsRepository_PruneHistory_Call{Call: _e.mock.On("PruneHistory", context1, time1)}
+}
+
+func (_c *MockMetricsRepository_PruneHistory_Call) Run(run func(context1 context.Context, time1 time.Time)) *MockMetricsRepository_PruneHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsRepository_PruneHistory_Call) Return(n int, err error) *MockMetricsRepository_PruneHistory_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockMetricsRepository_PruneHistory_Call) RunAndReturn(run func(context1 context.Context, time1 time.Time) (int, error)) *MockMetricsRepository_PruneHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetAll provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) ResetAll(context1 context.Context, metricss []models.Metrics) error {
 	ret := _mock.Called(context1, metricss)
 
 	if len(ret) == 0 {
-		panic("no return value specified for AddAll")
+		panic("no return value specified for ResetAll")
 	}
 
 	var r0 error
@@ -112,19 +1709,19 @@ func (_mock *MockMetricsRepository) AddAll(context1 context.Context, metricss []
 	return r0
 }
 
-// MockMetricsRepository_AddAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddAll'
-type MockMetricsRepository_AddAll_Call struct {
+// MockMetricsRepository_ResetAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetAll'
+type MockMetricsRepository_ResetAll_Call struct {
 	*mock.Call
 }
 
-// AddAll is a helper method to define mock.On call
+// ResetAll is a helper method to define mock.On call
 //   - context1 context.Context
 //   - metricss []models.Metrics
-func (_e *MockMetricsRepository_Expecter) AddAll(context1 interface{}, metricss interface{}) *MockMetricsRepository_AddAll_Call {
-	return &MockMetricsRepository_AddAll_Call{Call: _e.mock.On("AddAll", context1, metricss)}
+func (_e *MockMetricsRepository_Expecter) ResetAll(context1 interface{}, metricss interface{}) *MockMetricsRepository_ResetAll_Call {
+	return &MockMetricsRepository_ResetAll_Call{Call: _e.mock.On("ResetAll", context1, metricss)}
 }
 
-func (_c *MockMetricsRepository_AddAll_Call) Run(run func(context1 context.Context, metricss []models.Metrics)) *MockMetricsRepository_AddAll_Call {
+func (_c *MockMetricsRepository_ResetAll_Call) Run(run func(context1 context.Context, metricss []models.Metrics)) *MockMetricsRepository_ResetAll_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -142,34 +1739,118 @@ func (_c *MockMetricsRepository_AddAll_Call) Run(run func(context1 context.Conte
 	return _c
 }
 
-func (_c *MockMetricsRepository_AddAll_Call) Return(err error) *MockMetricsRepository_AddAll_Call {
+func (_c *MockMetricsRepository_ResetAll_Call) Return(err error) *MockMetricsRepository_ResetAll_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockMetricsRepository_AddAll_Call) RunAndReturn(run func(context1 context.Context, metricss []models.Metrics) error) *MockMetricsRepository_AddAll_Call {
+func (_c *MockMetricsRepository_ResetAll_Call) RunAndReturn(run func(context1 context.Context, metricss []models.Metrics) error) *MockMetricsRepository_ResetAll_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Get provides a mock function for the type MockMetricsRepository
-func (_mock *MockMetricsRepository) Get(context1 context.Context, s string) (*models.Metrics, error) {
+// ResetOne provides a mock function for the type MockMetricsRepository
+func (_mock *MockMetricsRepository) ResetOne(context1 context.Context, metrics models.Metrics) error {
+	ret := _mock.Called(context1, metrics)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetOne")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Metrics) error); ok {
+		r0 = returnFunc(context1, metrics)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMetricsRepository_ResetOne_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetOne'
+type MockMetricsRepository_ResetOne_Call struct {
+	*mock.Call
+}
+
+// ResetOne is a helper method to define mock.On call
+//   - context1 context.Context
+//   - metrics models.Metrics
+func (_e *MockMetricsRepository_Expecter) ResetOne(context1 interface{}, metrics interface{}) *MockMetricsRepository_ResetOne_Call {
+	return &MockMetricsRepository_ResetOne_Call{Call: _e.mock.On("ResetOne", context1, metrics)}
+}
+
+func (_c *MockMetricsRepository_ResetOne_Call) Run(run func(context1 context.Context, metrics models.Metrics)) *MockMetricsRepository_ResetOne_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.Metrics
+		if args[1] != nil {
+			arg1 = args[1].(models.Metrics)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsRepository_ResetOne_Call) Return(err error) *MockMetricsRepository_ResetOne_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMetricsRepository_ResetOne_Call) RunAndReturn(run func(context1 context.Context, metrics models.Metrics) error) *MockMetricsRepository_ResetOne_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockMetaRepository creates a new instance of MockMetaRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMetaRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMetaRepository {
+	mock := &MockMetaRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockMetaRepository is an autogenerated mock type for the MetaRepository type
+type MockMetaRepository struct {
+	mock.Mock
+}
+
+type MockMetaRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMetaRepository) EXPECT() *MockMetaRepository_Expecter {
+	return &MockMetaRepository_Expecter{mock: &_m.Mock}
+}
+
+// Get provides a mock function for the type MockMetaRepository
+func (_mock *MockMetaRepository) Get(context1 context.Context, s string) (*models.Meta, error) {
 	ret := _mock.Called(context1, s)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Get")
 	}
 
-	var r0 *models.Metrics
+	var r0 *models.Meta
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.Metrics, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.Meta, error)); ok {
 		return returnFunc(context1, s)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.Metrics); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.Meta); ok {
 		r0 = returnFunc(context1, s)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.Metrics)
+			r0 = ret.Get(0).(*models.Meta)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
@@ -180,19 +1861,19 @@ func (_mock *MockMetricsRepository) Get(context1 context.Context, s string) (*mo
 	return r0, r1
 }
 
-// MockMetricsRepository_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
-type MockMetricsRepository_Get_Call struct {
+// MockMetaRepository_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockMetaRepository_Get_Call struct {
 	*mock.Call
 }
 
 // Get is a helper method to define mock.On call
 //   - context1 context.Context
 //   - s string
-func (_e *MockMetricsRepository_Expecter) Get(context1 interface{}, s interface{}) *MockMetricsRepository_Get_Call {
-	return &MockMetricsRepository_Get_Call{Call: _e.mock.On("Get", context1, s)}
+func (_e *MockMetaRepository_Expecter) Get(context1 interface{}, s interface{}) *MockMetaRepository_Get_Call {
+	return &MockMetaRepository_Get_Call{Call: _e.mock.On("Get", context1, s)}
 }
 
-func (_c *MockMetricsRepository_Get_Call) Run(run func(context1 context.Context, s string)) *MockMetricsRepository_Get_Call {
+func (_c *MockMetaRepository_Get_Call) Run(run func(context1 context.Context, s string)) *MockMetaRepository_Get_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -210,34 +1891,34 @@ func (_c *MockMetricsRepository_Get_Call) Run(run func(context1 context.Context,
 	return _c
 }
 
-func (_c *MockMetricsRepository_Get_Call) Return(metrics *models.Metrics, err error) *MockMetricsRepository_Get_Call {
-	_c.Call.Return(metrics, err)
+func (_c *MockMetaRepository_Get_Call) Return(meta *models.Meta, err error) *MockMetaRepository_Get_Call {
+	_c.Call.Return(meta, err)
 	return _c
 }
 
-func (_c *MockMetricsRepository_Get_Call) RunAndReturn(run func(context1 context.Context, s string) (*models.Metrics, error)) *MockMetricsRepository_Get_Call {
+func (_c *MockMetaRepository_Get_Call) RunAndReturn(run func(context1 context.Context, s string) (*models.Meta, error)) *MockMetaRepository_Get_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetAll provides a mock function for the type MockMetricsRepository
-func (_mock *MockMetricsRepository) GetAll(context1 context.Context) (map[string]any, error) {
+// GetAll provides a mock function for the type MockMetaRepository
+func (_mock *MockMetaRepository) GetAll(context1 context.Context) (map[string]models.Meta, error) {
 	ret := _mock.Called(context1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAll")
 	}
 
-	var r0 map[string]any
+	var r0 map[string]models.Meta
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) (map[string]any, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (map[string]models.Meta, error)); ok {
 		return returnFunc(context1)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) map[string]any); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context) map[string]models.Meta); ok {
 		r0 = returnFunc(context1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[string]any)
+			r0 = ret.Get(0).(map[string]models.Meta)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
@@ -248,18 +1929,18 @@ func (_mock *MockMetricsRepository) GetAll(context1 context.Context) (map[string
 	return r0, r1
 }
 
-// MockMetricsRepository_GetAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAll'
-type MockMetricsRepository_GetAll_Call struct {
+// MockMetaRepository_GetAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAll'
+type MockMetaRepository_GetAll_Call struct {
 	*mock.Call
 }
 
 // GetAll is a helper method to define mock.On call
 //   - context1 context.Context
-func (_e *MockMetricsRepository_Expecter) GetAll(context1 interface{}) *MockMetricsRepository_GetAll_Call {
-	return &MockMetricsRepository_GetAll_Call{Call: _e.mock.On("GetAll", context1)}
+func (_e *MockMetaRepository_Expecter) GetAll(context1 interface{}) *MockMetaRepository_GetAll_Call {
+	return &MockMetaRepository_GetAll_Call{Call: _e.mock.On("GetAll", context1)}
 }
 
-func (_c *MockMetricsRepository_GetAll_Call) Run(run func(context1 context.Context)) *MockMetricsRepository_GetAll_Call {
+func (_c *MockMetaRepository_GetAll_Call) Run(run func(context1 context.Context)) *MockMetaRepository_GetAll_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -272,34 +1953,34 @@ func (_c *MockMetricsRepository_GetAll_Call) Run(run func(context1 context.Conte
 	return _c
 }
 
-func (_c *MockMetricsRepository_GetAll_Call) Return(stringToV map[string]any, err error) *MockMetricsRepository_GetAll_Call {
-	_c.Call.Return(stringToV, err)
+func (_c *MockMetaRepository_GetAll_Call) Return(stringToMeta map[string]models.Meta, err error) *MockMetaRepository_GetAll_Call {
+	_c.Call.Return(stringToMeta, err)
 	return _c
 }
 
-func (_c *MockMetricsRepository_GetAll_Call) RunAndReturn(run func(context1 context.Context) (map[string]any, error)) *MockMetricsRepository_GetAll_Call {
+func (_c *MockMetaRepository_GetAll_Call) RunAndReturn(run func(context1 context.Context) (map[string]models.Meta, error)) *MockMetaRepository_GetAll_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetAllMetrics provides a mock function for the type MockMetricsRepository
-func (_mock *MockMetricsRepository) GetAllMetrics(context1 context.Context) ([]models.Metrics, error) {
+// GetAllTenants provides a mock function for the type MockMetaRepository
+func (_mock *MockMetaRepository) GetAllTenants(context1 context.Context) (map[string]map[string]models.Meta, error) {
 	ret := _mock.Called(context1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAllMetrics")
+		panic("no return value specified for GetAllTenants")
 	}
 
-	var r0 []models.Metrics
+	var r0 map[string]map[string]models.Meta
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.Metrics, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (map[string]map[string]models.Meta, error)); ok {
 		return returnFunc(context1)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.Metrics); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context) map[string]map[string]models.Meta); ok {
 		r0 = returnFunc(context1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Metrics)
+			r0 = ret.Get(0).(map[string]map[string]models.Meta)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
@@ -310,18 +1991,18 @@ func (_mock *MockMetricsRepository) GetAllMetrics(context1 context.Context) ([]m
 	return r0, r1
 }
 
-// MockMetricsRepository_GetAllMetrics_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllMetrics'
-type MockMetricsRepository_GetAllMetrics_Call struct {
+// MockMetaRepository_GetAllTenants_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllTenants'
+type MockMetaRepository_GetAllTenants_Call struct {
 	*mock.Call
 }
 
-// GetAllMetrics is a helper method to define mock.On call
+// GetAllTenants is a helper method to define mock.On call
 //   - context1 context.Context
-func (_e *MockMetricsRepository_Expecter) GetAllMetrics(context1 interface{}) *MockMetricsRepository_GetAllMetrics_Call {
-	return &MockMetricsRepository_GetAllMetrics_Call{Call: _e.mock.On("GetAllMetrics", context1)}
+func (_e *MockMetaRepository_Expecter) GetAllTenants(context1 interface{}) *MockMetaRepository_GetAllTenants_Call {
+	return &MockMetaRepository_GetAllTenants_Call{Call: _e.mock.On("GetAllTenants", context1)}
 }
 
-func (_c *MockMetricsRepository_GetAllMetrics_Call) Run(run func(context1 context.Context)) *MockMetricsRepository_GetAllMetrics_Call {
+func (_c *MockMetaRepository_GetAllTenants_Call) Run(run func(context1 context.Context)) *MockMetaRepository_GetAllTenants_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -334,54 +2015,54 @@ func (_c *MockMetricsRepository_GetAllMetrics_Call) Run(run func(context1 contex
 	return _c
 }
 
-func (_c *MockMetricsRepository_GetAllMetrics_Call) Return(metricss []models.Metrics, err error) *MockMetricsRepository_GetAllMetrics_Call {
-	_c.Call.Return(metricss, err)
+func (_c *MockMetaRepository_GetAllTenants_Call) Return(stringToStringToMeta map[string]map[string]models.Meta, err error) *MockMetaRepository_GetAllTenants_Call {
+	_c.Call.Return(stringToStringToMeta, err)
 	return _c
 }
 
-func (_c *MockMetricsRepository_GetAllMetrics_Call) RunAndReturn(run func(context1 context.Context) ([]models.Metrics, error)) *MockMetricsRepository_GetAllMetrics_Call {
+func (_c *MockMetaRepository_GetAllTenants_Call) RunAndReturn(run func(context1 context.Context) (map[string]map[string]models.Meta, error)) *MockMetaRepository_GetAllTenants_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ResetAll provides a mock function for the type MockMetricsRepository
-func (_mock *MockMetricsRepository) ResetAll(context1 context.Context, metricss []models.Metrics) error {
-	ret := _mock.Called(context1, metricss)
+// Put provides a mock function for the type MockMetaRepository
+func (_mock *MockMetaRepository) Put(context1 context.Context, meta models.Meta) error {
+	ret := _mock.Called(context1, meta)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ResetAll")
+		panic("no return value specified for Put")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.Metrics) error); ok {
-		r0 = returnFunc(context1, metricss)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Meta) error); ok {
+		r0 = returnFunc(context1, meta)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockMetricsRepository_ResetAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetAll'
-type MockMetricsRepository_ResetAll_Call struct {
+// MockMetaRepository_Put_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Put'
+type MockMetaRepository_Put_Call struct {
 	*mock.Call
 }
 
-// ResetAll is a helper method to define mock.On call
+// Put is a helper method to define mock.On call
 //   - context1 context.Context
-//   - metricss []models.Metrics
-func (_e *MockMetricsRepository_Expecter) ResetAll(context1 interface{}, metricss interface{}) *MockMetricsRepository_ResetAll_Call {
-	return &MockMetricsRepository_ResetAll_Call{Call: _e.mock.On("ResetAll", context1, metricss)}
+//   - meta models.Meta
+func (_e *MockMetaRepository_Expecter) Put(context1 interface{}, meta interface{}) *MockMetaRepository_Put_Call {
+	return &MockMetaRepository_Put_Call{Call: _e.mock.On("Put", context1, meta)}
 }
 
-func (_c *MockMetricsRepository_ResetAll_Call) Run(run func(context1 context.Context, metricss []models.Metrics)) *MockMetricsRepository_ResetAll_Call {
+func (_c *MockMetaRepository_Put_Call) Run(run func(context1 context.Context, meta models.Meta)) *MockMetaRepository_Put_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 []models.Metrics
+		var arg1 models.Meta
 		if args[1] != nil {
-			arg1 = args[1].([]models.Metrics)
+			arg1 = args[1].(models.Meta)
 		}
 		run(
 			arg0,
@@ -391,54 +2072,54 @@ func (_c *MockMetricsRepository_ResetAll_Call) Run(run func(context1 context.Con
 	return _c
 }
 
-func (_c *MockMetricsRepository_ResetAll_Call) Return(err error) *MockMetricsRepository_ResetAll_Call {
+func (_c *MockMetaRepository_Put_Call) Return(err error) *MockMetaRepository_Put_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockMetricsRepository_ResetAll_Call) RunAndReturn(run func(context1 context.Context, metricss []models.Metrics) error) *MockMetricsRepository_ResetAll_Call {
+func (_c *MockMetaRepository_Put_Call) RunAndReturn(run func(context1 context.Context, meta models.Meta) error) *MockMetaRepository_Put_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ResetOne provides a mock function for the type MockMetricsRepository
-func (_mock *MockMetricsRepository) ResetOne(context1 context.Context, metrics models.Metrics) error {
-	ret := _mock.Called(context1, metrics)
+// Register provides a mock function for the type MockMetaRepository
+func (_mock *MockMetaRepository) Register(context1 context.Context, metas []models.Meta) error {
+	ret := _mock.Called(context1, metas)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ResetOne")
+		panic("no return value specified for Register")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Metrics) error); ok {
-		r0 = returnFunc(context1, metrics)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.Meta) error); ok {
+		r0 = returnFunc(context1, metas)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockMetricsRepository_ResetOne_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetOne'
-type MockMetricsRepository_ResetOne_Call struct {
+// MockMetaRepository_Register_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Register'
+type MockMetaRepository_Register_Call struct {
 	*mock.Call
 }
 
-// ResetOne is a helper method to define mock.On call
+// Register is a helper method to define mock.On call
 //   - context1 context.Context
-//   - metrics models.Metrics
-func (_e *MockMetricsRepository_Expecter) ResetOne(context1 interface{}, metrics interface{}) *MockMetricsRepository_ResetOne_Call {
-	return &MockMetricsRepository_ResetOne_Call{Call: _e.mock.On("ResetOne", context1, metrics)}
+//   - metas []models.Meta
+func (_e *MockMetaRepository_Expecter) Register(context1 interface{}, metas interface{}) *MockMetaRepository_Register_Call {
+	return &MockMetaRepository_Register_Call{Call: _e.mock.On("Register", context1, metas)}
 }
 
-func (_c *MockMetricsRepository_ResetOne_Call) Run(run func(context1 context.Context, metrics models.Metrics)) *MockMetricsRepository_ResetOne_Call {
+func (_c *MockMetaRepository_Register_Call) Run(run func(context1 context.Context, metas []models.Meta)) *MockMetaRepository_Register_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 models.Metrics
+		var arg1 []models.Meta
 		if args[1] != nil {
-			arg1 = args[1].(models.Metrics)
+			arg1 = args[1].([]models.Meta)
 		}
 		run(
 			arg0,
@@ -448,12 +2129,12 @@ func (_c *MockMetricsRepository_ResetOne_Call) Run(run func(context1 context.Con
 	return _c
 }
 
-func (_c *MockMetricsRepository_ResetOne_Call) Return(err error) *MockMetricsRepository_ResetOne_Call {
+func (_c *MockMetaRepository_Register_Call) Return(err error) *MockMetaRepository_Register_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockMetricsRepository_ResetOne_Call) RunAndReturn(run func(context1 context.Context, metrics models.Metrics) error) *MockMetricsRepository_ResetOne_Call {
+func (_c *MockMetaRepository_Register_Call) RunAndReturn(run func(context1 context.Context, metas []models.Meta) error) *MockMetaRepository_Register_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,101 @@
+// Package rpc implements the gRPC metrics API.
+//
+// MetricsServer exposes the same operations as the HTTP handlers
+// on top of service.MetricsService, so storage, validation and audit
+// behave identically for both transports. Tenant resolution, signature
+// verification and audit context are provided by pkg/interceptor.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/pb"
+	"github.com/gabkaclassic/metrics/internal/service"
+)
+
+// MetricsServer implements pb.MetricsServer.
+type MetricsServer struct {
+	pb.UnimplementedMetricsServer
+
+	service service.MetricsService
+}
+
+// NewMetricsServer creates a new gRPC metrics server.
+//
+// service: Metrics service handling all calls
+//
+// Returns:
+//   - *MetricsServer: Initialized server ready for registration
+//   - error: If service is nil
+func NewMetricsServer(service service.MetricsService) (*MetricsServer, error) {
+	if service == nil {
+		return nil, errors.New("create new metrics server failed: service is nil")
+	}
+
+	return &MetricsServer{
+		service: service,
+	}, nil
+}
+
+// UpdateMetrics saves a batch of metrics.
+// Errors are returned as service API errors, converted to gRPC statuses.
+func (server *MetricsServer) UpdateMetrics(ctx context.Context, req *pb.UpdateMetricsRequest) (*pb.UpdateMetricsResponse, error) {
+	if err := server.service.SaveAll(ctx, pb.ToModels(req.GetMetrics())); err != nil {
+		return nil, err
+	}
+
+	return &pb.UpdateMetricsResponse{Received: int64(len(req.GetMetrics()))}, nil
+}
+
+// StreamMetrics collects metrics until the client closes the stream
+// and saves them as a single batch. Empty streams save nothing.
+func (server *MetricsServer) StreamMetrics(stream pb.Metrics_StreamMetricsServer) error {
+	metrics := make([]models.Metrics, 0)
+
+	for {
+		metric, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		metrics = append(metrics, pb.ToModel(metric))
+	}
+
+	if len(metrics) > 0 {
+		if err := server.service.SaveAll(stream.Context(), metrics); err != nil {
+			return err
+		}
+	}
+
+	return stream.SendAndClose(&pb.UpdateMetricsResponse{Received: int64(len(metrics))})
+}
+
+// GetMetric returns a single series by ID, type and labels.
+func (server *MetricsServer) GetMetric(ctx context.Context, req *pb.GetMetricRequest) (*pb.Metric, error) {
+	metric, err := server.service.GetStruct(ctx, req.GetId(), req.GetType(), req.GetLabels())
+	if err != nil {
+		return nil, err
+	}
+
+	return pb.FromModel(metric), nil
+}
+
+// ListMetrics returns all series of the call tenant.
+func (server *MetricsServer) ListMetrics(ctx context.Context, req *pb.ListMetricsRequest) (*pb.ListMetricsResponse, error) {
+	metrics, err := server.service.GetAllMetrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*pb.Metric, 0, len(metrics))
+	for _, metric := range metrics {
+		result = append(result, pb.FromModel(metric))
+	}
+
+	return &pb.ListMetricsResponse{Metrics: result}, nil
+}
@@ -0,0 +1,287 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/pb"
+	"github.com/gabkaclassic/metrics/internal/service"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+func intPtr(i int64) *int64       { return &i }
+func floatPtr(f float64) *float64 { return &f }
+
+// newTestClient serves svc over an in-memory connection and returns a client.
+func newTestClient(t *testing.T, svc service.MetricsService) pb.MetricsClient {
+	t.Helper()
+
+	server, err := NewMetricsServer(svc)
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	pb.RegisterMetricsServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewMetricsClient(conn)
+}
+
+func TestNewMetricsServer(t *testing.T) {
+	tests := []struct {
+		name    string
+		service service.MetricsService
+		wantErr bool
+	}{
+		{
+			name:    "valid service",
+			service: service.NewMockMetricsService(t),
+		},
+		{
+			name:    "nil service",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, err := NewMetricsServer(tt.service)
+
+			if tt.wantErr {
+				assert.EqualError(t, err, "create new metrics server failed: service is nil")
+				assert.Nil(t, server)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, server)
+			}
+		})
+	}
+}
+
+func TestMetricsServer_UpdateMetrics(t *testing.T) {
+	metrics := []models.Metrics{
+		{ID: "requests", MType: models.Counter, Delta: intPtr(5)},
+		{ID: "temperature", MType: models.Gauge, Value: floatPtr(21.5), Labels: map[string]string{"room": "lab"}},
+	}
+
+	tests := []struct {
+		name         string
+		saveErr      *api.APIError
+		wantCode     codes.Code
+		wantReceived int64
+	}{
+		{
+			name:         "saved",
+			wantCode:     codes.OK,
+			wantReceived: 2,
+		},
+		{
+			name:     "validation error",
+			saveErr:  api.BadRequest("invalid metric"),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "internal error",
+			saveErr:  api.Internal("save error", errors.New("db error")),
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewMockMetricsService(t)
+			svc.EXPECT().SaveAll(mock.Anything, metrics).Return(tt.saveErr)
+
+			client := newTestClient(t, svc)
+
+			req := &pb.UpdateMetricsRequest{}
+			for _, metric := range metrics {
+				req.Metrics = append(req.Metrics, pb.FromModel(metric))
+			}
+			resp, err := client.UpdateMetrics(t.Context(), req)
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, tt.wantReceived, resp.GetReceived())
+			}
+		})
+	}
+}
+
+func TestMetricsServer_StreamMetrics(t *testing.T) {
+	tests := []struct {
+		name         string
+		metrics      []models.Metrics
+		setupMock    func(*service.MockMetricsService)
+		wantCode     codes.Code
+		wantReceived int64
+	}{
+		{
+			name: "saved as single batch",
+			metrics: []models.Metrics{
+				{ID: "requests", MType: models.Counter, Delta: intPtr(1)},
+				{ID: "requests", MType: models.Counter, Delta: intPtr(2)},
+			},
+			setupMock: func(svc *service.MockMetricsService) {
+				svc.EXPECT().
+					SaveAll(mock.Anything, []models.Metrics{
+						{ID: "requests", MType: models.Counter, Delta: intPtr(1)},
+						{ID: "requests", MType: models.Counter, Delta: intPtr(2)},
+					}).
+					Return(nil)
+			},
+			wantCode:     codes.OK,
+			wantReceived: 2,
+		},
+		{
+			name:      "empty stream",
+			setupMock: func(svc *service.MockMetricsService) {},
+			wantCode:  codes.OK,
+		},
+		{
+			name: "save error",
+			metrics: []models.Metrics{
+				{ID: "requests", MType: models.Counter},
+			},
+			setupMock: func(svc *service.MockMetricsService) {
+				svc.EXPECT().SaveAll(mock.Anything, mock.Anything).Return(api.BadRequest("invalid metric"))
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewMockMetricsService(t)
+			tt.setupMock(svc)
+
+			client := newTestClient(t, svc)
+
+			stream, err := client.StreamMetrics(t.Context())
+			require.NoError(t, err)
+			for _, metric := range tt.metrics {
+				require.NoError(t, stream.Send(pb.FromModel(metric)))
+			}
+			resp, err := stream.CloseAndRecv()
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, tt.wantReceived, resp.GetReceived())
+			}
+		})
+	}
+}
+
+func TestMetricsServer_GetMetric(t *testing.T) {
+	labels := map[string]string{"room": "lab"}
+
+	tests := []struct {
+		name     string
+		metric   models.Metrics
+		getErr   *api.APIError
+		wantCode codes.Code
+	}{
+		{
+			name:     "found",
+			metric:   models.Metrics{ID: "temperature", MType: models.Gauge, Value: floatPtr(21.5), Labels: labels},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "not found",
+			getErr:   api.NotFound("metric not found"),
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewMockMetricsService(t)
+			svc.EXPECT().
+				GetStruct(mock.Anything, "temperature", models.Gauge, labels).
+				Return(tt.metric, tt.getErr)
+
+			client := newTestClient(t, svc)
+
+			resp, err := client.GetMetric(t.Context(), &pb.GetMetricRequest{
+				Id:     "temperature",
+				Type:   models.Gauge,
+				Labels: labels,
+			})
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				assert.True(t, proto.Equal(pb.FromModel(tt.metric), resp))
+			}
+		})
+	}
+}
+
+func TestMetricsServer_ListMetrics(t *testing.T) {
+	tests := []struct {
+		name     string
+		metrics  []models.Metrics
+		listErr  *api.APIError
+		wantCode codes.Code
+	}{
+		{
+			name: "listed",
+			metrics: []models.Metrics{
+				{ID: "requests", MType: models.Counter, Delta: intPtr(5)},
+				{ID: "temperature", MType: models.Gauge, Value: floatPtr(21.5)},
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "empty",
+			metrics:  []models.Metrics{},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "internal error",
+			listErr:  api.Internal("list error", errors.New("db error")),
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewMockMetricsService(t)
+			svc.EXPECT().GetAllMetrics(mock.Anything).Return(tt.metrics, tt.listErr)
+
+			client := newTestClient(t, svc)
+
+			resp, err := client.ListMetrics(t.Context(), &pb.ListMetricsRequest{})
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				require.Len(t, resp.GetMetrics(), len(tt.metrics))
+				for i, metric := range tt.metrics {
+					assert.Equal(t, metric, pb.ToModel(resp.GetMetrics()[i]))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/repository"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+)
+
+// AlertService defines the interface for alert rule and alert state operations.
+// All operations are scoped to the request tenant.
+type AlertService interface {
+	// GetRules retrieves all rules ordered by ID.
+	GetRules(context.Context) ([]models.AlertRule, *api.APIError)
+
+	// GetRule retrieves a rule by its ID.
+	// Returns NotFound if the rule doesn't exist.
+	GetRule(context.Context, string) (models.AlertRule, *api.APIError)
+
+	// PutRule validates and stores a rule, replacing the rule with the same ID.
+	// Returns the stored rule.
+	PutRule(context.Context, models.AlertRule) (models.AlertRule, *api.APIError)
+
+	// DeleteRule removes a rule by its ID.
+	// Returns NotFound if the rule doesn't exist.
+	DeleteRule(context.Context, string) *api.APIError
+
+	// GetAlerts retrieves pending and firing alerts.
+	GetAlerts(context.Context) ([]models.Alert, *api.APIError)
+}
+
+// AlertSource provides current alert states, such as the alerting engine.
+type AlertSource interface {
+	// Alerts returns pending and firing alerts of the tenant.
+	Alerts(tenant string) []models.Alert
+}
+
+// alertService implements AlertService on top of an AlertRuleRepository.
+type alertService struct {
+	repository repository.AlertRuleRepository
+	alerts     AlertSource
+}
+
+// NewAlertService creates a new alert service.
+//
+// repository: Data access layer for alert rule storage operations
+// alerts: Source of current alert states
+//
+// Returns:
+//   - AlertService: Ready-to-use service instance
+//   - error: If repository or alerts is nil
+func NewAlertService(repository repository.AlertRuleRepository, alerts AlertSource) (AlertService, error) {
+	if repository == nil {
+		return nil, errors.New("create new alert service failed: repository is nil")
+	}
+
+	if alerts == nil {
+		return nil, errors.New("create new alert service failed: alert source is nil")
+	}
+
+	return &alertService{
+		repository: repository,
+		alerts:     alerts,
+	}, nil
+}
+
+// GetRules retrieves all rules of the request tenant ordered by ID.
+func (service *alertService) GetRules(ctx context.Context) ([]models.AlertRule, *api.APIError) {
+	all, err := service.repository.GetAll(ctx)
+	if err != nil {
+		return nil, api.Internal("Get alert rules error", err)
+	}
+
+	tenant := middleware.TenantFromCtx(ctx)
+	rules := make([]models.AlertRule, 0, len(all))
+	for _, rule := range all {
+		if rule.Tenant == tenant {
+			rules = append(rules, rule)
+		}
+	}
+
+	slices.SortFunc(rules, func(a, b models.AlertRule) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return rules, nil
+}
+
+// GetRule retrieves a rule of the request tenant by its ID.
+func (service *alertService) GetRule(ctx context.Context, id string) (models.AlertRule, *api.APIError) {
+	rule, err := service.repository.Get(ctx, id)
+	if err != nil {
+		return models.AlertRule{}, api.Internal("Get alert rule error", err)
+	}
+
+	if rule == nil {
+		return models.AlertRule{}, api.NotFound(fmt.Sprintf("alert rule %s not found", id))
+	}
+
+	return *rule, nil
+}
+
+// PutRule validates and stores a rule of the request tenant.
+// The rule tenant is always the request tenant.
+func (service *alertService) PutRule(ctx context.Context, rule models.AlertRule) (models.AlertRule, *api.APIError) {
+	if err := models.ValidateAlertRule(rule); err != nil {
+		return models.AlertRule{}, api.BadRequest(err.Error())
+	}
+
+	rule.Tenant = middleware.TenantFromCtx(ctx)
+	if err := service.repository.Put(ctx, rule); err != nil {
+		return models.AlertRule{}, api.Internal("Put alert rule error", err)
+	}
+
+	return rule, nil
+}
+
+// DeleteRule removes a rule of the request tenant by its ID.
+func (service *alertService) DeleteRule(ctx context.Context, id string) *api.APIError {
+	deleted, err := service.repository.Delete(ctx, id)
+	if err != nil {
+		return api.Internal("Delete alert rule error", err)
+	}
+
+	if !deleted {
+		return api.NotFound(fmt.Sprintf("alert rule %s not found", id))
+	}
+
+	return nil
+}
+
+// GetAlerts retrieves pending and firing alerts of the request tenant.
+func (service *alertService) GetAlerts(ctx context.Context) ([]models.Alert, *api.APIError) {
+	return service.alerts.Alerts(middleware.TenantFromCtx(ctx)), nil
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/repository"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAlertService(t *testing.T) {
+	svc, err := NewAlertService(repository.NewMockAlertRuleRepository(t), NewMockAlertSource(t))
+	assert.NoError(t, err)
+	assert.NotNil(t, svc)
+
+	svc, err = NewAlertService(nil, NewMockAlertSource(t))
+	assert.Error(t, err)
+	assert.Nil(t, svc)
+
+	svc, err = NewAlertService(repository.NewMockAlertRuleRepository(t), nil)
+	assert.Error(t, err)
+	assert.Nil(t, svc)
+}
+
+func TestAlertService_GetRules(t *testing.T) {
+	mockRepo := repository.NewMockAlertRuleRepository(t)
+	mockRepo.EXPECT().GetAll(mock.Anything).Return([]models.AlertRule{
+		{ID: "b", Tenant: "team-a"},
+		{ID: "a", Tenant: "team-b"},
+		{ID: "a", Tenant: "team-a"},
+	}, nil)
+
+	svc, err := NewAlertService(mockRepo, NewMockAlertSource(t))
+	require.NoError(t, err)
+
+	rules, apiErr := svc.GetRules(middleware.WithTenant(t.Context(), "team-a"))
+	assert.Nil(t, apiErr)
+	assert.Equal(t, []models.AlertRule{
+		{ID: "a", Tenant: "team-a"},
+		{ID: "b", Tenant: "team-a"},
+	}, rules)
+}
+
+func TestAlertService_GetRules_Error(t *testing.T) {
+	mockRepo := repository.NewMockAlertRuleRepository(t)
+	mockRepo.EXPECT().GetAll(mock.Anything).Return(nil, errors.New("db error"))
+
+	svc, err := NewAlertService(mockRepo, NewMockAlertSource(t))
+	require.NoError(t, err)
+
+	rules, apiErr := svc.GetRules(t.Context())
+	require.NotNil(t, apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.Code)
+	assert.Nil(t, rules)
+}
+
+func TestAlertService_GetRule(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockRule     *models.AlertRule
+		mockErr      error
+		expectRule   models.AlertRule
+		expectStatus int
+	}{
+		{
+			name:         "found",
+			mockRule:     &models.AlertRule{ID: "high-heap", Metric: "HeapAlloc"},
+			expectRule:   models.AlertRule{ID: "high-heap", Metric: "HeapAlloc"},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "not found",
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			name:         "repository error",
+			mockErr:      errors.New("db error"),
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockAlertRuleRepository(t)
+			mockRepo.EXPECT().Get(mock.Anything, "high-heap").Return(tt.mockRule, tt.mockErr)
+
+			svc, err := NewAlertService(mockRepo, NewMockAlertSource(t))
+			require.NoError(t, err)
+
+			rule, apiErr := svc.GetRule(t.Context(), "high-heap")
+
+			if tt.expectStatus == http.StatusOK {
+				assert.Nil(t, apiErr)
+				assert.Equal(t, tt.expectRule, rule)
+			} else {
+				require.NotNil(t, apiErr)
+				assert.Equal(t, tt.expectStatus, apiErr.Code)
+			}
+		})
+	}
+}
+
+func TestAlertService_PutRule(t *testing.T) {
+	tests := []struct {
+		name         string
+		rule         models.AlertRule
+		mockErr      error
+		expectCall   bool
+		expectStatus int
+	}{
+		{
+			name:         "valid rule",
+			rule:         models.AlertRule{ID: "high-heap", Tenant: "team-b", Metric: "HeapAlloc", Op: models.OpGreater, Threshold: 100},
+			expectCall:   true,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "invalid rule",
+			rule:         models.AlertRule{ID: "high-heap", Metric: "HeapAlloc", Op: "~"},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "repository error",
+			rule:         models.AlertRule{ID: "high-heap", Metric: "HeapAlloc", Op: models.OpGreater},
+			mockErr:      errors.New("db error"),
+			expectCall:   true,
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := tt.rule
+			expected.Tenant = "team-a"
+
+			mockRepo := repository.NewMockAlertRuleRepository(t)
+			if tt.expectCall {
+				mockRepo.EXPECT().Put(mock.Anything, expected).Return(tt.mockErr)
+			}
+
+			svc, err := NewAlertService(mockRepo, NewMockAlertSource(t))
+			require.NoError(t, err)
+
+			rule, apiErr := svc.PutRule(middleware.WithTenant(t.Context(), "team-a"), tt.rule)
+
+			if tt.expectStatus == http.StatusOK {
+				assert.Nil(t, apiErr)
+				assert.Equal(t, expected, rule)
+			} else {
+				require.NotNil(t, apiErr)
+				assert.Equal(t, tt.expectStatus, apiErr.Code)
+			}
+		})
+	}
+}
+
+func TestAlertService_DeleteRule(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockDeleted  bool
+		mockErr      error
+		expectStatus int
+	}{
+		{
+			name:         "deleted",
+			mockDeleted:  true,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "not found",
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			name:         "repository error",
+			mockErr:      errors.New("db error"),
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockAlertRuleRepository(t)
+			mockRepo.EXPECT().Delete(mock.Anything, "high-heap").Return(tt.mockDeleted, tt.mockErr)
+
+			svc, err := NewAlertService(mockRepo, NewMockAlertSource(t))
+			require.NoError(t, err)
+
+			apiErr := svc.DeleteRule(t.Context(), "high-heap")
+
+			if tt.expectStatus == http.StatusOK {
+				assert.Nil(t, apiErr)
+			} else {
+				require.NotNil(t, apiErr)
+				assert.Equal(t, tt.expectStatus, apiErr.Code)
+			}
+		})
+	}
+}
+
+func TestAlertService_GetAlerts(t *testing.T) {
+	alerts := []models.Alert{{Rule: "high-heap", Tenant: "team-a", ID: "HeapAlloc", State: models.AlertFiring}}
+
+	mockSource := NewMockAlertSource(t)
+	mockSource.EXPECT().Alerts("team-a").Return(alerts)
+
+	svc, err := NewAlertService(repository.NewMockAlertRuleRepository(t), mockSource)
+	require.NoError(t, err)
+
+	result, apiErr := svc.GetAlerts(middleware.WithTenant(t.Context(), "team-a"))
+	assert.Nil(t, apiErr)
+	assert.Equal(t, alerts, result)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/repository"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+)
+
+// IdempotencyService defines the interface for idempotency key operations.
+// Requests repeated with a key get the recorded response of the first request.
+type IdempotencyService interface {
+	// Begin validates and claims a key of the request tenant for the request
+	// with the fingerprint, see models.RequestFingerprint.
+	// Returns the recorded response if the key was already completed,
+	// nil if the key is claimed by the caller,
+	// UnprocessableEntity if the key was claimed by a different request,
+	// and Conflict if the request of the key is still processed.
+	Begin(context.Context, string, string) (*models.IdempotentResponse, *api.APIError)
+
+	// Complete records the response of a claimed key.
+	Complete(context.Context, string, models.IdempotentResponse) *api.APIError
+
+	// Release forgets a claimed key, so that its request can be repeated.
+	Release(context.Context, string) *api.APIError
+
+	// StartExpiry periodically forgets expired keys of all tenants
+	// until context cancellation.
+	StartExpiry(context.Context, time.Duration)
+}
+
+// idempotencyService implements IdempotencyService on top of an IdempotencyRepository.
+type idempotencyService struct {
+	repository repository.IdempotencyRepository
+	ttl        time.Duration
+}
+
+// NewIdempotencyService creates a new idempotency key service.
+//
+// repository: Data access layer for idempotency key storage operations
+// ttl: How long responses of keys are remembered
+//
+// Returns:
+//   - IdempotencyService: Ready-to-use service instance
+//   - error: If repository is nil or ttl is not positive
+func NewIdempotencyService(repository repository.IdempotencyRepository, ttl time.Duration) (IdempotencyService, error) {
+	if repository == nil {
+		return nil, errors.New("create new idempotency service failed: repository is nil")
+	}
+	if ttl <= 0 {
+		return nil, errors.New("create new idempotency service failed: ttl must be positive")
+	}
+
+	return &idempotencyService{
+		repository: repository,
+		ttl:        ttl,
+	}, nil
+}
+
+// Begin validates and claims a key of the request tenant.
+func (service *idempotencyService) Begin(ctx context.Context, key string, fingerprint string) (*models.IdempotentResponse, *api.APIError) {
+	if key == "" {
+		return nil, api.BadRequest("idempotency key is empty")
+	}
+	if len(key) > models.MaxIdempotencyKeyLength {
+		return nil, api.BadRequest(fmt.Sprintf("idempotency key is longer than %d characters", models.MaxIdempotencyKeyLength))
+	}
+
+	response, reserved, err := service.repository.Reserve(ctx, key, fingerprint, time.Now().Add(service.ttl))
+	if err != nil {
+		return nil, api.Internal("Reserve idempotency key error", err)
+	}
+
+	if reserved {
+		return nil, nil
+	}
+	// Keys claimed before fingerprints were recorded have none
+	if response.Fingerprint != "" && response.Fingerprint != fingerprint {
+		return nil, api.UnprocessibleEntity(fmt.Sprintf("idempotency key %s was used for a different request", key))
+	}
+	if response.Pending() {
+		return nil, api.Conflict(fmt.Sprintf("request with idempotency key %s is in progress", key))
+	}
+
+	return &response, nil
+}
+
+// Complete records the response of a claimed key.
+func (service *idempotencyService) Complete(ctx context.Context, key string, response models.IdempotentResponse) *api.APIError {
+	if err := service.repository.Complete(ctx, key, response); err != nil {
+		return api.Internal("Complete idempotency key error", err)
+	}
+
+	return nil
+}
+
+// Release forgets a claimed key, so that its request can be repeated.
+func (service *idempotencyService) Release(ctx context.Context, key string) *api.APIError {
+	if err := service.repository.Release(ctx, key); err != nil {
+		return api.Internal("Release idempotency key error", err)
+	}
+
+	return nil
+}
+
+// StartExpiry periodically forgets expired keys of all tenants.
+// Runs until context cancellation.
+//
+// ctx: Context for graceful shutdown (cancellation stops the expiry)
+// interval: Time between expiry runs
+//
+// Expiry errors are logged and retried on the next tick.
+func (service *idempotencyService) StartExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			expired, err := service.repository.Expire(ctx, time.Now())
+			if err != nil {
+				slog.Error("Expire idempotency keys error", slog.String("error", err.Error()))
+			} else if expired > 0 {
+				slog.Debug("Expired idempotency keys forgotten", slog.Int("count", expired))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
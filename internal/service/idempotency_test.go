@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIdempotencyService(t *testing.T) {
+	svc, err := NewIdempotencyService(repository.NewMockIdempotencyRepository(t), time.Hour)
+	assert.NoError(t, err)
+	assert.NotNil(t, svc)
+
+	svc, err = NewIdempotencyService(nil, time.Hour)
+	assert.Error(t, err)
+	assert.Nil(t, svc)
+
+	svc, err = NewIdempotencyService(repository.NewMockIdempotencyRepository(t), 0)
+	assert.Error(t, err)
+	assert.Nil(t, svc)
+}
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	fingerprint := models.RequestFingerprint(http.MethodPost, "/updates/", []byte(`[]`))
+	completed := models.IdempotentResponse{Status: http.StatusOK, ContentType: "application/json", Body: []byte(`{}`), Fingerprint: fingerprint}
+	unfingerprinted := models.IdempotentResponse{Status: http.StatusOK}
+
+	tests := []struct {
+		name           string
+		key            string
+		mockResponse   models.IdempotentResponse
+		mockReserved   bool
+		mockErr        error
+		expectReserve  bool
+		expectResponse *models.IdempotentResponse
+		expectStatus   int
+	}{
+		{
+			name:          "claimed",
+			key:           "batch-1",
+			mockReserved:  true,
+			expectReserve: true,
+			expectStatus:  http.StatusOK,
+		},
+		{
+			name:           "completed",
+			key:            "batch-1",
+			mockResponse:   completed,
+			expectReserve:  true,
+			expectResponse: &completed,
+			expectStatus:   http.StatusOK,
+		},
+		{
+			name:           "completed before fingerprints",
+			key:            "batch-1",
+			mockResponse:   unfingerprinted,
+			expectReserve:  true,
+			expectResponse: &unfingerprinted,
+			expectStatus:   http.StatusOK,
+		},
+		{
+			name:          "different request",
+			key:           "batch-1",
+			mockResponse:  models.IdempotentResponse{Status: http.StatusOK, Fingerprint: models.RequestFingerprint(http.MethodPost, "/updates/", []byte(`[{}]`))},
+			expectReserve: true,
+			expectStatus:  http.StatusUnprocessableEntity,
+		},
+		{
+			name:          "in progress",
+			key:           "batch-1",
+			mockResponse:  models.IdempotentResponse{Fingerprint: fingerprint},
+			expectReserve: true,
+			expectStatus:  http.StatusConflict,
+		},
+		{
+			name:          "in progress with different request",
+			key:           "batch-1",
+			mockResponse:  models.IdempotentResponse{Fingerprint: "other"},
+			expectReserve: true,
+			expectStatus:  http.StatusUnprocessableEntity,
+		},
+		{
+			name:          "repository error",
+			key:           "batch-1",
+			mockErr:       errors.New("db error"),
+			expectReserve: true,
+			expectStatus:  http.StatusInternalServerError,
+		},
+		{
+			name:         "empty key",
+			key:          "",
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "long key",
+			key:          strings.Repeat("k", models.MaxIdempotencyKeyLength+1),
+			expectStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockIdempotencyRepository(t)
+			if tt.expectReserve {
+				mockRepo.EXPECT().
+					Reserve(mock.Anything, tt.key, fingerprint, mock.MatchedBy(func(expiresAt time.Time) bool {
+						return time.Until(expiresAt) > 59*time.Minute
+					})).
+					Return(tt.mockResponse, tt.mockReserved, tt.mockErr)
+			}
+
+			svc, err := NewIdempotencyService(mockRepo, time.Hour)
+			require.NoError(t, err)
+
+			response, apiErr := svc.Begin(t.Context(), tt.key, fingerprint)
+
+			if tt.expectStatus != http.StatusOK {
+				require.NotNil(t, apiErr)
+				assert.Equal(t, tt.expectStatus, apiErr.Code)
+				return
+			}
+
+			require.Nil(t, apiErr)
+			assert.Equal(t, tt.expectResponse, response)
+		})
+	}
+}
+
+func TestIdempotencyService_CompleteRelease(t *testing.T) {
+	response := models.IdempotentResponse{Status: http.StatusOK}
+
+	mockRepo := repository.NewMockIdempotencyRepository(t)
+	mockRepo.EXPECT().Complete(mock.Anything, "batch-1", response).Return(nil).Once()
+	mockRepo.EXPECT().Complete(mock.Anything, "batch-2", response).Return(errors.New("db error")).Once()
+	mockRepo.EXPECT().Release(mock.Anything, "batch-3").Return(nil).Once()
+	mockRepo.EXPECT().Release(mock.Anything, "batch-4").Return(errors.New("db error")).Once()
+
+	svc, err := NewIdempotencyService(mockRepo, time.Hour)
+	require.NoError(t, err)
+
+	assert.Nil(t, svc.Complete(t.Context(), "batch-1", response))
+	assert.Equal(t, http.StatusInternalServerError, svc.Complete(t.Context(), "batch-2", response).Code)
+	assert.Nil(t, svc.Release(t.Context(), "batch-3"))
+	assert.Equal(t, http.StatusInternalServerError, svc.Release(t.Context(), "batch-4").Code)
+}
+
+func TestIdempotencyService_StartExpiry(t *testing.T) {
+	mockRepo := repository.NewMockIdempotencyRepository(t)
+
+	expired := make(chan struct{})
+	mockRepo.EXPECT().
+		Expire(mock.Anything, mock.Anything).
+		RunAndReturn(func(context.Context, time.Time) (int, error) {
+			select {
+			case <-expired:
+			default:
+				close(expired)
+			}
+			return 1, nil
+		})
+
+	svc, err := NewIdempotencyService(mockRepo, time.Hour)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		svc.StartExpiry(ctx, time.Millisecond)
+		close(done)
+	}()
+
+	select {
+	case <-expired:
+	case <-time.After(time.Second):
+		t.Fatal("expired keys were not forgotten")
+	}
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/repository"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+)
+
+// MetaService defines the interface for metric metadata operations.
+type MetaService interface {
+	// Get retrieves the metadata of a metric by its ID.
+	// Returns NotFound if the metric has no metadata.
+	Get(context.Context, string) (models.Meta, *api.APIError)
+
+	// Put validates and stores the metadata of a metric,
+	// replacing any existing metadata including registered units.
+	Put(context.Context, models.Meta) *api.APIError
+}
+
+// metaService implements MetaService on top of a MetaRepository.
+type metaService struct {
+	repository repository.MetaRepository
+}
+
+// NewMetaService creates a new metadata service.
+//
+// repository: Data access layer for metadata storage operations
+//
+// Returns:
+//   - MetaService: Ready-to-use service instance
+//   - error: If repository is nil
+func NewMetaService(repository repository.MetaRepository) (MetaService, error) {
+	if repository == nil {
+		return nil, errors.New("create new meta service failed: repository is nil")
+	}
+
+	return &metaService{
+		repository: repository,
+	}, nil
+}
+
+// Get retrieves the metadata of a metric by its ID.
+func (service *metaService) Get(ctx context.Context, id string) (models.Meta, *api.APIError) {
+	meta, err := service.repository.Get(ctx, id)
+
+	if err != nil {
+		return models.Meta{}, api.Internal("Get metric metadata error", err)
+	}
+
+	if meta == nil {
+		return models.Meta{}, api.NotFound(fmt.Sprintf("metadata of metric %s not found", id))
+	}
+
+	return *meta, nil
+}
+
+// Put validates and stores the metadata of a metric.
+func (service *metaService) Put(ctx context.Context, meta models.Meta) *api.APIError {
+	if err := models.ValidateMeta(meta); err != nil {
+		return api.BadRequest(err.Error())
+	}
+
+	if err := service.repository.Put(ctx, meta); err != nil {
+		return api.Internal("Put metric metadata error", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMetaService(t *testing.T) {
+	svc, err := NewMetaService(repository.NewMockMetaRepository(t))
+	assert.NoError(t, err)
+	assert.NotNil(t, svc)
+
+	svc, err = NewMetaService(nil)
+	assert.Error(t, err)
+	assert.Nil(t, svc)
+}
+
+func TestMetaService_Get(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockMeta     *models.Meta
+		mockErr      error
+		expectMeta   models.Meta
+		expectStatus int
+	}{
+		{
+			name:         "found",
+			mockMeta:     &models.Meta{ID: "HeapAlloc", Unit: "bytes"},
+			expectMeta:   models.Meta{ID: "HeapAlloc", Unit: "bytes"},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "not found",
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			name:         "repository error",
+			mockErr:      errors.New("db error"),
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockMetaRepository(t)
+			mockRepo.EXPECT().Get(mock.Anything, "HeapAlloc").Return(tt.mockMeta, tt.mockErr)
+
+			svc, err := NewMetaService(mockRepo)
+			require.NoError(t, err)
+
+			meta, apiErr := svc.Get(t.Context(), "HeapAlloc")
+
+			if tt.expectStatus == http.StatusOK {
+				assert.Nil(t, apiErr)
+				assert.Equal(t, tt.expectMeta, meta)
+			} else {
+				require.NotNil(t, apiErr)
+				assert.Equal(t, tt.expectStatus, apiErr.Code)
+			}
+		})
+	}
+}
+
+func TestMetaService_Put(t *testing.T) {
+	tests := []struct {
+		name         string
+		meta         models.Meta
+		mockErr      error
+		expectCall   bool
+		expectStatus int
+	}{
+		{
+			name:         "valid metadata",
+			meta:         models.Meta{ID: "HeapAlloc", Unit: "bytes", Owner: "runtime"},
+			expectCall:   true,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "missing id",
+			meta:         models.Meta{Unit: "bytes"},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "negative ttl",
+			meta:         models.Meta{ID: "HeapAlloc", TTL: -1},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "repository error",
+			meta:         models.Meta{ID: "HeapAlloc"},
+			mockErr:      errors.New("db error"),
+			expectCall:   true,
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockMetaRepository(t)
+			if tt.expectCall {
+				mockRepo.EXPECT().Put(mock.Anything, tt.meta).Return(tt.mockErr)
+			}
+
+			svc, err := NewMetaService(mockRepo)
+			require.NoError(t, err)
+
+			apiErr := svc.Put(t.Context(), tt.meta)
+
+			if tt.expectStatus == http.StatusOK {
+				assert.Nil(t, apiErr)
+			} else {
+				require.NotNil(t, apiErr)
+				assert.Equal(t, tt.expectStatus, apiErr.Code)
+			}
+		})
+	}
+}
@@ -13,7 +13,9 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/gabkaclassic/metrics/internal/audit"
 	models "github.com/gabkaclassic/metrics/internal/model"
@@ -27,12 +29,14 @@ import (
 
 type MetricsService interface {
 	// Get retrieves a metric value by ID and type.
-	// Returns the raw value (int64 for counters, float64 for gauges).
+	// Returns the raw value (int64 for counters, float64 for gauges,
+	// models.HistogramSnapshot for histograms, models.SummarySnapshot for summaries,
+	// int64 cardinality estimate for sets).
 	Get(context.Context, string, string) (any, *api.APIError)
 
-	// GetStruct retrieves a complete metric structure by ID and type.
+	// GetStruct retrieves a complete metric structure by ID, type and labels.
 	// Returns the full Metrics model including all fields.
-	GetStruct(context.Context, string, string) (models.Metrics, *api.APIError)
+	GetStruct(context.Context, string, string, map[string]string) (models.Metrics, *api.APIError)
 
 	// Save processes and stores a metric from raw string values.
 	// Parses and validates input before storage.
@@ -43,88 +47,246 @@ type MetricsService interface {
 	SaveStruct(context.Context, models.Metrics) *api.APIError
 
 	// SaveAll processes and stores multiple metrics efficiently.
-	// Aggregates counters and processes gauges concurrently.
+	// Aggregates counters, histograms, summaries and sets and processes all types concurrently.
 	SaveAll(context.Context, []models.Metrics) *api.APIError
 
+	// Import validates imported records and stores the valid ones like SaveAll.
+	// Rejected records are reported by line and don't prevent others from being imported.
+	Import(context.Context, []models.ImportRecord, models.ImportOptions) (models.ImportReport, *api.APIError)
+
+	// SaveBatch validates every metric of a batch and stores the valid ones like SaveAll.
+	// Rejected metrics are reported by index and don't prevent others from being stored.
+	SaveBatch(context.Context, []models.Metrics) (models.BatchResult, *api.APIError)
+
 	// GetAll retrieves all stored metrics as a map.
-	// Returns metric ID to value mapping (int64 or float64).
+	// Returns series key to value mapping (int64 or float64).
 	GetAll(context.Context) (map[string]any, *api.APIError)
+
+	// GetUnits retrieves units of all metrics with a known unit.
+	// Returns metric ID to unit mapping.
+	GetUnits(context.Context) (map[string]string, *api.APIError)
+
+	// GetAllMetrics retrieves all series of the request tenant as complete structures.
+	GetAllMetrics(context.Context) ([]models.Metrics, *api.APIError)
+
+	// ExportPage retrieves at most ExportPageSize series of the request tenant
+	// as stored, ordered by ID and labels and starting after the given series.
+	// A nil series starts from the first series.
+	ExportPage(context.Context, *models.SeriesRef) ([]models.Metrics, *api.APIError)
+
+	// GetDescriptions retrieves descriptions of all metrics with a known description.
+	// Returns metric ID to description mapping.
+	GetDescriptions(context.Context) (map[string]string, *api.APIError)
+
+	// GetUpdated retrieves the last update time of every series of the request tenant.
+	// Returns series key to update time mapping.
+	GetUpdated(context.Context) (map[string]time.Time, *api.APIError)
+
+	// List retrieves a page of series of the request tenant selected by the query.
+	// Series carry their units and, for summaries, estimated quantiles.
+	List(context.Context, models.ListQuery) (models.MetricsPage, *api.APIError)
+
+	// GetMany retrieves the referenced series in request order.
+	// Series that don't exist are omitted.
+	GetMany(context.Context, []models.SeriesRef) ([]models.Metrics, *api.APIError)
+
+	// GetRange retrieves recorded points of a counter or gauge series.
+	// Points are aggregated into query.Step windows when step is set.
+	GetRange(context.Context, models.RangeQuery) ([]models.Point, *api.APIError)
+
+	// Rate computes the increase and per-second rate of a counter series
+	// or the per-second derivative of a gauge series within a recent window.
+	// Samples are kept in memory, independently of history storage.
+	Rate(context.Context, models.RateQuery) (models.Rate, *api.APIError)
+
+	// Delete removes all series of a metric by ID and type.
+	// Returns not found error if the metric has no series.
+	Delete(context.Context, string, string) *api.APIError
+
+	// DeleteAll removes all series of metrics whose IDs match a glob pattern.
+	// An empty type matches metrics of all types.
+	DeleteAll(context.Context, string, string) (models.DeleteResult, *api.APIError)
 }
 
+const (
+	// DefaultListLimit is the page size of listings without an explicit limit.
+	DefaultListLimit = 100
+
+	// MaxListLimit bounds the page size of listings and the size of bulk lookups.
+	MaxListLimit = 1000
+
+	// ExportPageSize is the number of series read at once by exports.
+	ExportPageSize = MaxListLimit
+)
+
 // metricsService implements MetricsService with repository and audit integration.
 // Provides thread-safe operations through repository synchronization.
 
 type metricsService struct {
-	repository repository.MetricsRepository
-	auditor    audit.Auditor
+	repository     repository.MetricsRepository
+	metaRepository repository.MetaRepository
+	auditor        audit.Auditor
+	publisher      Publisher
+	samples        *sampleWindow
+}
+
+// Publisher receives current states of series changed by saves.
+type Publisher interface {
+	// Active reports whether changes have any receivers.
+	// Current states are only read for active publishers.
+	Active() bool
+
+	// Publish delivers series of the tenant changed by a single save.
+	Publish(tenant string, metrics []models.Metrics)
+}
+
+// Option represents a functional option for metrics service configuration.
+type Option func(*metricsService)
+
+// WithPublisher publishes changed series after every successful save.
+// Series saved by a single SaveAll call are published together.
+func WithPublisher(publisher Publisher) Option {
+	return func(service *metricsService) {
+		service.publisher = publisher
+	}
+}
+
+// WithRateWindow keeps counter and gauge samples for retention to answer rate queries.
+// Rate queries are rejected without the option or with a non-positive retention.
+func WithRateWindow(retention time.Duration) Option {
+	return func(service *metricsService) {
+		if retention > 0 {
+			service.samples = newSampleWindow(retention)
+		}
+	}
 }
 
 // NewMetricsService creates a new metrics service with required dependencies.
 //
 // repository: Data access layer for metric storage operations
+// metaRepository: Data access layer for metric metadata (units)
 // auditor: Audit logging system for security and compliance tracking
+// options: Optional configuration, such as WithPublisher and WithRateWindow
 //
 // Returns:
 //   - MetricsService: Ready-to-use service instance
-//   - error: If repository or auditor is nil
-func NewMetricsService(repository repository.MetricsRepository, auditor audit.Auditor) (MetricsService, error) {
+//   - error: If repository, metaRepository or auditor is nil
+func NewMetricsService(repository repository.MetricsRepository, metaRepository repository.MetaRepository, auditor audit.Auditor, options ...Option) (MetricsService, error) {
 	if repository == nil {
 		return nil, errors.New("create new metrics service failed: repository is nil")
 	}
 
+	if metaRepository == nil {
+		return nil, errors.New("create new metrics service failed: meta repository is nil")
+	}
+
 	if auditor == nil {
 		return nil, errors.New("create new metrics service failed: auditor is nil")
 	}
 
-	return &metricsService{
-		repository: repository,
-		auditor:    auditor,
-	}, nil
+	service := &metricsService{
+		repository:     repository,
+		metaRepository: metaRepository,
+		auditor:        auditor,
+	}
+
+	for _, option := range options {
+		option(service)
+	}
+
+	return service, nil
 }
 
-// notifyOne logs a single metric operation to the audit system.
-// Extracts timestamp and IP from context and records asynchronously.
-func (service *metricsService) notifyOne(ctx context.Context, metric models.Metrics) {
-	ts := middleware.AuditTSFromCtx(ctx)
+// publish delivers current states of the saved series to the publisher.
+// Series are deduplicated and read back, so that cumulative metrics are
+// published with their stored totals. Read failures are only logged.
+func (service *metricsService) publish(ctx context.Context, metrics []models.Metrics) {
+	if service.publisher == nil || !service.publisher.Active() {
+		return
+	}
 
-	if ts == 0 {
-		slog.Error("Get audit timestamp from request context error")
+	seen := make(map[string]struct{}, len(metrics))
+	refs := make([]models.SeriesRef, 0, len(metrics))
+	for _, metric := range metrics {
+		key := metric.MType + " " + metric.Key()
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+		refs = append(refs, metric.Ref())
+	}
+
+	saved, err := service.repository.GetMany(ctx, refs)
+	if err != nil {
+		slog.Error("Get published metrics error", "error", err)
 		return
 	}
 
-	ip := middleware.AuditIPFromCtx(ctx)
+	current, err := service.lookupResults(ctx, saved)
+	if err != nil {
+		slog.Error("Get published metric metadata error", "error", err)
+		return
+	}
 
-	if len(ip) == 0 {
-		slog.Error("Get audit source IP from request context error")
+	service.publisher.Publish(middleware.TenantFromCtx(ctx), current)
+}
+
+// notifyOne logs a single metric operation to the audit system.
+// Extracts timestamp, IP and tenant from context and records asynchronously.
+func (service *metricsService) notifyOne(ctx context.Context, metric models.Metrics) {
+	ts, ip, ok := auditSource(ctx)
+	if !ok {
 		return
 	}
 
-	service.auditor.AuditOne(metric, ts, ip)
+	service.auditor.AuditOne(metric, ts, ip, middleware.TenantFromCtx(ctx))
 }
 
 // notifyMany logs multiple metric operations to the audit system.
-// Extracts timestamp and IP from context and records asynchronously.
+// Extracts timestamp, IP and tenant from context and records asynchronously.
 func (service *metricsService) notifyMany(ctx context.Context, metrics []models.Metrics) {
 	if len(metrics) == 0 {
 		slog.Debug("Metrics list for audit is empty")
 		return
 	}
 
+	ts, ip, ok := auditSource(ctx)
+	if !ok {
+		return
+	}
+
+	service.auditor.AuditMany(metrics, ts, ip, middleware.TenantFromCtx(ctx))
+}
+
+// notifyDelete logs deletion of metrics to the audit system.
+// Extracts timestamp, IP and tenant from context and records asynchronously.
+func (service *metricsService) notifyDelete(ctx context.Context, metrics []models.Metrics) {
+	ts, ip, ok := auditSource(ctx)
+	if !ok {
+		return
+	}
+
+	service.auditor.AuditDelete(metrics, ts, ip, middleware.TenantFromCtx(ctx))
+}
+
+// auditSource extracts the audit timestamp and source IP from context.
+// Logs and reports false if either is missing.
+func auditSource(ctx context.Context) (int64, string, bool) {
 	ts := middleware.AuditTSFromCtx(ctx)
 
 	if ts == 0 {
 		slog.Error("Get audit timestamp from request context error")
-		return
+		return 0, "", false
 	}
 
 	ip := middleware.AuditIPFromCtx(ctx)
 
 	if len(ip) == 0 {
 		slog.Error("Get audit source IP from request context error")
-		return
+		return 0, "", false
 	}
 
-	service.auditor.AuditMany(metrics, ts, ip)
+	return ts, ip, true
 }
 
 // GetAll retrieves all metrics from the repository.
@@ -139,11 +301,123 @@ func (service *metricsService) GetAll(ctx context.Context) (map[string]any, *api
 	return metrics, nil
 }
 
+// GetUnits retrieves units of all metrics from metadata.
+// Metrics without a unit are omitted.
+func (service *metricsService) GetUnits(ctx context.Context) (map[string]string, *api.APIError) {
+	metas, err := service.metaRepository.GetAll(ctx)
+
+	if err != nil {
+		return nil, api.Internal("Get metric units error", err)
+	}
+
+	units := make(map[string]string, len(metas))
+	for id, meta := range metas {
+		if meta.Unit != "" {
+			units[id] = meta.Unit
+		}
+	}
+
+	return units, nil
+}
+
+// ExportPage retrieves a page of stored series of the request tenant
+// with a single ordered List query, without filters.
+func (service *metricsService) ExportPage(ctx context.Context, after *models.SeriesRef) ([]models.Metrics, *api.APIError) {
+	metrics, err := service.repository.List(ctx, models.ListQuery{
+		SortBy: models.SortByID,
+		Limit:  ExportPageSize,
+		After:  after,
+	})
+	if err != nil {
+		return nil, api.Internal("List metrics error", err)
+	}
+
+	return metrics, nil
+}
+
+// GetAllMetrics retrieves all series of the request tenant.
+// Returns API error if repository operation fails.
+func (service *metricsService) GetAllMetrics(ctx context.Context) ([]models.Metrics, *api.APIError) {
+	metrics, err := service.repository.GetAllMetrics(ctx)
+
+	if err != nil {
+		return nil, api.Internal("Get all metrics error", err)
+	}
+
+	tenant := middleware.TenantFromCtx(ctx)
+	tenantMetrics := make([]models.Metrics, 0, len(metrics))
+	for _, metric := range metrics {
+		if metric.Tenant == tenant {
+			tenantMetrics = append(tenantMetrics, metric)
+		}
+	}
+
+	return tenantMetrics, nil
+}
+
+// GetDescriptions retrieves descriptions of all metrics from metadata.
+// Metrics without a description are omitted.
+func (service *metricsService) GetDescriptions(ctx context.Context) (map[string]string, *api.APIError) {
+	metas, err := service.metaRepository.GetAll(ctx)
+
+	if err != nil {
+		return nil, api.Internal("Get metric descriptions error", err)
+	}
+
+	descriptions := make(map[string]string, len(metas))
+	for id, meta := range metas {
+		if meta.Description != "" {
+			descriptions[id] = meta.Description
+		}
+	}
+
+	return descriptions, nil
+}
+
+// GetUpdated retrieves the last update time of every series of the request tenant.
+// Returns API error if repository operation fails.
+func (service *metricsService) GetUpdated(ctx context.Context) (map[string]time.Time, *api.APIError) {
+	updated, err := service.repository.GetUpdated(ctx)
+
+	if err != nil {
+		return nil, api.Internal("Get metric update times error", err)
+	}
+
+	return updated, nil
+}
+
+// registerUnits registers units reported with metrics as metadata
+// of metrics that have none yet. Failures are only logged,
+// since the metrics themselves are already stored.
+func (service *metricsService) registerUnits(ctx context.Context, metrics []models.Metrics) {
+	metas := make([]models.Meta, 0)
+	registered := make(map[string]struct{})
+
+	for _, metric := range metrics {
+		if metric.Unit == "" {
+			continue
+		}
+		if _, exists := registered[metric.ID]; exists {
+			continue
+		}
+		registered[metric.ID] = struct{}{}
+		metas = append(metas, models.Meta{ID: metric.ID, Unit: metric.Unit})
+	}
+
+	if len(metas) == 0 {
+		return
+	}
+
+	if err := service.metaRepository.Register(ctx, metas); err != nil {
+		slog.Error("Register metric units error", slog.Any("error", err))
+	}
+}
+
 // Get retrieves a single metric value by ID and type.
 // Validates that the retrieved metric matches the requested type.
 // Returns the appropriate value based on metric type.
 func (service *metricsService) Get(ctx context.Context, metricID string, metricType string) (any, *api.APIError) {
-	metric, err := service.repository.Get(ctx, metricID)
+	metric, err := service.repository.Get(ctx, metricID, nil)
 
 	if metric == nil || metric.MType != metricType {
 		return nil, api.NotFound(fmt.Sprintf("Metric %s with type %s not found", metricID, metricType))
@@ -158,36 +432,193 @@ func (service *metricsService) Get(ctx context.Context, metricID string, metricT
 		return metric.Delta, nil
 	case models.Gauge:
 		return metric.Value, nil
+	case models.Histogram:
+		return metric.Snapshot(), nil
+	case models.Summary:
+		return metric.SummarySnapshot(), nil
+	case models.Set:
+		return metric.Cardinality(), nil
 	default:
 		return nil, api.BadRequest(fmt.Sprintf("Unknown metric type: %s", metricType))
 	}
 }
 
-// GetStruct retrieves a complete metric structure by ID and type.
+// GetStruct retrieves a complete metric structure by ID, type and labels.
 // Returns the full metric model with all fields populated.
-func (service *metricsService) GetStruct(ctx context.Context, metricID string, metricType string) (models.Metrics, *api.APIError) {
-	metric, err := service.repository.Get(ctx, metricID)
+// Summaries additionally report models.DefaultQuantiles estimates.
+// The unit is filled from metric metadata.
+func (service *metricsService) GetStruct(ctx context.Context, metricID string, metricType string, labels map[string]string) (models.Metrics, *api.APIError) {
+	metric, err := service.repository.Get(ctx, metricID, labels)
 
 	if metric == nil || metric.MType != metricType {
-		return models.Metrics{}, api.NotFound(fmt.Sprintf("metric %v %v not found", metricID, metricType))
+		return models.Metrics{}, api.NotFound(fmt.Sprintf("metric %v %v not found", models.SeriesKey(metricID, labels), metricType))
 	}
 
 	if err != nil {
 		return models.Metrics{}, api.Internal("Get metric error", err)
 	}
 
-	return models.Metrics{
-		ID:    metricID,
-		MType: metricType,
-		Value: metric.Value,
-		Delta: metric.Delta,
-	}, nil
+	meta, err := service.metaRepository.Get(ctx, metricID)
+	if err != nil {
+		return models.Metrics{}, api.Internal("Get metric metadata error", err)
+	}
+
+	unit := ""
+	if meta != nil {
+		unit = meta.Unit
+	}
+
+	return lookupResult(*metric, unit), nil
+}
+
+// lookupResult returns the stored metric as reported by value lookups:
+// with the unit, estimated quantiles for summaries and without the tenant.
+func lookupResult(metric models.Metrics, unit string) models.Metrics {
+	result := models.Metrics{
+		ID:      metric.ID,
+		MType:   metric.MType,
+		Labels:  metric.Labels,
+		Value:   metric.Value,
+		Delta:   metric.Delta,
+		Bounds:  metric.Bounds,
+		Buckets: metric.Buckets,
+		Sum:     metric.Sum,
+		Count:   metric.Count,
+		Sketch:  metric.Sketch,
+		Unit:    unit,
+	}
+
+	if metric.MType == models.Summary {
+		result.Quantiles = metric.SummarySnapshot().Quantiles
+	}
+
+	return result
+}
+
+// List retrieves a page of series selected by the query.
+// Validates the query and applies DefaultListLimit when no limit is set.
+// One extra series is requested to decide whether a next page exists.
+func (service *metricsService) List(ctx context.Context, query models.ListQuery) (models.MetricsPage, *api.APIError) {
+	if query.MType != "" && !models.IsKnownType(query.MType) {
+		return models.MetricsPage{}, api.BadRequest(fmt.Sprintf("invalid metric type: %s", query.MType))
+	}
+
+	if err := query.Validate(); err != nil {
+		return models.MetricsPage{}, api.BadRequest(err.Error())
+	}
+
+	if query.Limit < 0 || query.Limit > MaxListLimit {
+		return models.MetricsPage{}, api.BadRequest(fmt.Sprintf("limit must be between 1 and %d", MaxListLimit))
+	}
+
+	if query.Limit == 0 {
+		query.Limit = DefaultListLimit
+	}
+
+	limit := query.Limit
+	query.Limit++
+
+	metrics, err := service.repository.List(ctx, query)
+	if err != nil {
+		return models.MetricsPage{}, api.Internal("List metrics error", err)
+	}
+
+	page := models.MetricsPage{}
+	if len(metrics) > limit {
+		metrics = metrics[:limit]
+		page.NextCursor = models.EncodeCursor(metrics[limit-1].Ref())
+	}
+
+	page.Metrics, err = service.lookupResults(ctx, metrics)
+	if err != nil {
+		return models.MetricsPage{}, api.Internal("Get metric metadata error", err)
+	}
+
+	return page, nil
+}
+
+// GetMany retrieves the referenced series in request order.
+// Validates references, at most MaxListLimit series can be requested at once.
+func (service *metricsService) GetMany(ctx context.Context, refs []models.SeriesRef) ([]models.Metrics, *api.APIError) {
+	if len(refs) > MaxListLimit {
+		return nil, api.BadRequest(fmt.Sprintf("at most %d metrics can be requested at once", MaxListLimit))
+	}
+
+	for _, ref := range refs {
+		if ref.ID == "" {
+			return nil, api.BadRequest("metric id is required")
+		}
+		if !models.IsKnownType(ref.MType) {
+			return nil, api.BadRequest(fmt.Sprintf("invalid metric type: %s", ref.MType))
+		}
+		if err := models.ValidateLabels(ref.Labels); err != nil {
+			return nil, api.BadRequest(err.Error())
+		}
+	}
+
+	if len(refs) == 0 {
+		return []models.Metrics{}, nil
+	}
+
+	found, err := service.repository.GetMany(ctx, refs)
+	if err != nil {
+		return nil, api.Internal("Get metrics error", err)
+	}
+
+	index := make(map[string]models.Metrics, len(found))
+	for _, metric := range found {
+		index[metric.MType+" "+metric.Key()] = metric
+	}
+
+	ordered := make([]models.Metrics, 0, len(found))
+	for _, ref := range refs {
+		if metric, exists := index[ref.MType+" "+ref.Key()]; exists {
+			ordered = append(ordered, metric)
+		}
+	}
+
+	results, err := service.lookupResults(ctx, ordered)
+	if err != nil {
+		return nil, api.Internal("Get metric metadata error", err)
+	}
+
+	return results, nil
+}
+
+// lookupResults converts stored metrics to lookup results,
+// filling units from the metadata of all metrics.
+func (service *metricsService) lookupResults(ctx context.Context, metrics []models.Metrics) ([]models.Metrics, error) {
+	results := make([]models.Metrics, 0, len(metrics))
+	if len(metrics) == 0 {
+		return results, nil
+	}
+
+	metas, err := service.metaRepository.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, metric := range metrics {
+		results = append(results, lookupResult(metric, metas[metric.ID].Unit))
+	}
+
+	return results, nil
 }
 
 // Save processes and stores a metric from raw string inputs.
 // Validates metric type, parses value, and calls appropriate repository method.
-// Performs audit logging asynchronously after successful storage.
+// For gauges a value with models.IncrementPrefix is added to the stored value.
+// For histograms the value is a single observation recorded with the bounds
+// of the stored histogram, or models.DefaultBounds for a new one.
+// For summaries the value is a single observation merged into the stored sketch.
+// For sets the value is a single member.
+// Performs audit logging asynchronously and publishes the saved series
+// after successful storage.
 func (service *metricsService) Save(ctx context.Context, id string, metricType string, rawValue string) *api.APIError {
+	if err := models.ValidateID(id); err != nil {
+		return api.BadRequest(err.Error())
+	}
+
 	switch metricType {
 	case models.Counter:
 		if delta, err := strconv.ParseInt(rawValue, 10, 64); err == nil {
@@ -198,126 +629,708 @@ func (service *metricsService) Save(ctx context.Context, id string, metricType s
 			}
 			err := service.repository.Add(ctx, metric)
 			if err != nil {
-				return api.Internal("Add delta error", err)
+				return saveError("Add delta error", err)
 			}
+			service.recordSamples(ctx, []models.Metrics{metric})
 			go service.notifyOne(ctx, metric)
 		} else {
 			return api.BadRequest(fmt.Sprintf("invalid metric value: %s", rawValue))
 		}
 	case models.Gauge:
+		if metric, err := models.ParseGauge(id, rawValue); err == nil {
+			if metric.Increment != nil {
+				err = service.repository.Increment(ctx, metric)
+				if err != nil {
+					return saveError("Increment value error", err)
+				}
+			} else {
+				err = service.repository.ResetOne(ctx, metric)
+				if err != nil {
+					return saveError("Reset value error", err)
+				}
+			}
+			service.recordSamples(ctx, []models.Metrics{metric})
+			go service.notifyOne(ctx, metric)
+		} else {
+			return api.BadRequest(fmt.Sprintf("invalid metric value: %s", rawValue))
+		}
+	case models.Histogram:
 		if value, err := strconv.ParseFloat(rawValue, 64); err == nil {
-			metric := models.Metrics{
-				ID:    id,
-				MType: models.Gauge,
-				Value: &value,
+			bounds := models.DefaultBounds
+			if saved, err := service.repository.Get(ctx, id, nil); err == nil && saved != nil && saved.MType == models.Histogram {
+				bounds = saved.Bounds
 			}
-			err := service.repository.ResetOne(ctx, metric)
+			metric := models.ObserveHistogram(id, bounds, value)
+			err := service.repository.Merge(ctx, metric)
 			if err != nil {
-				return api.Internal("Reset value error", err)
+				return saveError("Merge histogram error", err)
 			}
 			go service.notifyOne(ctx, metric)
 		} else {
 			return api.BadRequest(fmt.Sprintf("invalid metric value: %s", rawValue))
 		}
+	case models.Summary:
+		if value, err := strconv.ParseFloat(rawValue, 64); err == nil {
+			metric := models.ObserveSummary(id, value)
+			err := service.repository.Merge(ctx, metric)
+			if err != nil {
+				return saveError("Merge summary error", err)
+			}
+			go service.notifyOne(ctx, metric)
+		} else {
+			return api.BadRequest(fmt.Sprintf("invalid metric value: %s", rawValue))
+		}
+	case models.Set:
+		metric := models.ObserveSet(id, rawValue)
+		err := service.repository.Merge(ctx, metric)
+		if err != nil {
+			return saveError("Merge set error", err)
+		}
+		go service.notifyOne(ctx, metric)
 	default:
 		return api.BadRequest(fmt.Sprintf("invalid metric type: %s", metricType))
 	}
+	service.publish(ctx, []models.Metrics{{ID: id, MType: metricType}})
 
 	return nil
 }
 
 // SaveStruct stores a pre-validated metric structure.
-// Routes to appropriate repository method based on metric type.
-// Performs audit logging asynchronously after successful storage.
+// Routes to appropriate repository method based on metric type,
+// gauge increments are routed to Increment.
+// A reported unit is registered as metadata if the metric has none.
+// Performs audit logging asynchronously and publishes the saved series
+// after successful storage.
 func (service *metricsService) SaveStruct(ctx context.Context, metric models.Metrics) *api.APIError {
+	if err := models.ValidateID(metric.ID); err != nil {
+		return api.BadRequest(err.Error())
+	}
+	if err := models.ValidateLabels(metric.Labels); err != nil {
+		return api.BadRequest(err.Error())
+	}
+
 	var err error
 	switch metric.MType {
 	case models.Counter:
 		err = service.repository.Add(ctx, metric)
 	case models.Gauge:
-		err = service.repository.ResetOne(ctx, metric)
+		if validateErr := models.ValidateGauge(metric); validateErr != nil {
+			return api.BadRequest(fmt.Sprintf("invalid gauge %s: %v", metric.ID, validateErr))
+		}
+		if metric.Increment != nil {
+			err = service.repository.Increment(ctx, metric)
+		} else {
+			err = service.repository.ResetOne(ctx, metric)
+		}
+	case models.Histogram:
+		if validateErr := models.ValidateHistogram(metric); validateErr != nil {
+			return api.BadRequest(fmt.Sprintf("invalid histogram %s: %v", metric.ID, validateErr))
+		}
+		err = service.repository.Merge(ctx, metric)
+	case models.Summary:
+		if validateErr := models.ValidateSummary(metric); validateErr != nil {
+			return api.BadRequest(fmt.Sprintf("invalid summary %s: %v", metric.ID, validateErr))
+		}
+		err = service.repository.Merge(ctx, metric)
+	case models.Set:
+		normalized, normalizeErr := models.NormalizeSet(metric)
+		if normalizeErr != nil {
+			return api.BadRequest(fmt.Sprintf("invalid set %s: %v", metric.ID, normalizeErr))
+		}
+		err = service.repository.Merge(ctx, normalized)
 	default:
 		return api.BadRequest(fmt.Sprintf("invalid metric type: %s", metric.MType))
 	}
 
 	if err != nil {
-		return api.Internal("save metric error", err)
+		return saveError("save metric error", err)
 	}
+	service.registerUnits(ctx, []models.Metrics{metric})
+	service.recordSamples(ctx, []models.Metrics{metric})
 	go service.notifyOne(ctx, metric)
+	service.publish(ctx, []models.Metrics{metric})
 
 	return nil
 }
 
 // SaveAll efficiently processes and stores multiple metrics.
-// Aggregates counter deltas and processes counters/gauges concurrently.
+// Aggregates counter deltas, gauge increments, histograms, summaries and sets
+// and processes all types concurrently.
 // Performs audit logging asynchronously for all metrics.
 //
 // Process:
-//  1. Aggregates counter deltas by metric ID
-//  2. Collects latest gauge values by metric ID
-//  3. Processes counters and gauges in parallel goroutines
-//  4. Returns combined error if any operation fails
+//  1. Rejects the batch if a series key (ID and labels) is reported
+//     with different types, then aggregates counter deltas by series key,
+//     keeping the latest client timestamp
+//  2. Collects latest gauge values by series key and applies gauge
+//     increments in order: increments following a value are added to it,
+//     increments without a preceding value are summed
+//     and added to the stored value
+//  3. Merges histograms (bounds must match), summaries (sketch accuracy
+//     must match) and sets (members folded into HyperLogLogs of the same
+//     precision) of the same series
+//  4. Processes counters, gauges, gauge increments and mergeable metrics
+//     in parallel goroutines
+//  5. Returns combined error if any operation fails
+//  6. Registers reported units of metrics without metadata
+//  7. Records aggregated counter deltas and gauge values as rate samples
+//  8. Publishes all saved series as a single change
 func (service *metricsService) SaveAll(ctx context.Context, metrics []models.Metrics) *api.APIError {
-	counterSums := make(map[string]int64)
-	gaugeLastValues := make(map[string]float64)
+	counterSums := make(map[string]models.Metrics)
+	gaugeLastValues := make(map[string]models.Metrics)
+	gaugeIncrements := make(map[string]models.Metrics)
+	mergedMetrics := make(map[string]models.Metrics)
+	types := make(map[string]string)
 
 	for _, metric := range metrics {
+		if err := models.ValidateID(metric.ID); err != nil {
+			return api.BadRequest(err.Error())
+		}
+		if err := models.ValidateLabels(metric.Labels); err != nil {
+			return api.BadRequest(err.Error())
+		}
+
+		key := metric.Key()
+		if savedType, exists := types[key]; exists && savedType != metric.MType {
+			return api.BadRequest(fmt.Sprintf("metric %s has type %s", key, savedType))
+		}
+		types[key] = metric.MType
+
 		switch metric.MType {
 		case models.Counter:
 			if metric.Delta != nil {
-				counterSums[metric.ID] += *metric.Delta
+				delta := *metric.Delta
+				timestamp := metric.Timestamp
+				if saved, exists := counterSums[key]; exists {
+					delta += *saved.Delta
+					timestamp = latestTimestamp(saved.Timestamp, timestamp)
+				}
+				counterSums[key] = models.Metrics{
+					ID:        metric.ID,
+					MType:     models.Counter,
+					Labels:    metric.Labels,
+					Delta:     &delta,
+					Timestamp: timestamp,
+				}
 			}
 		case models.Gauge:
+			if metric.Value != nil && metric.Increment != nil {
+				return api.BadRequest(fmt.Sprintf("invalid gauge %s: value and increment are mutually exclusive", key))
+			}
 			if metric.Value != nil {
-				gaugeLastValues[metric.ID] = *metric.Value
+				value := *metric.Value
+				delete(gaugeIncrements, key)
+				gaugeLastValues[key] = models.Metrics{
+					ID:        metric.ID,
+					MType:     models.Gauge,
+					Labels:    metric.Labels,
+					Value:     &value,
+					Timestamp: metric.Timestamp,
+				}
+			} else if metric.Increment != nil {
+				increment := *metric.Increment
+				if saved, exists := gaugeLastValues[key]; exists {
+					value := *saved.Value + increment
+					saved.Value = &value
+					saved.Timestamp = latestTimestamp(saved.Timestamp, metric.Timestamp)
+					gaugeLastValues[key] = saved
+					continue
+				}
+				timestamp := metric.Timestamp
+				if saved, exists := gaugeIncrements[key]; exists {
+					increment += *saved.Increment
+					timestamp = latestTimestamp(saved.Timestamp, timestamp)
+				}
+				gaugeIncrements[key] = models.Metrics{
+					ID:        metric.ID,
+					MType:     models.Gauge,
+					Labels:    metric.Labels,
+					Increment: &increment,
+					Timestamp: timestamp,
+				}
+			}
+		case models.Histogram, models.Summary, models.Set:
+			switch metric.MType {
+			case models.Histogram:
+				if err := models.ValidateHistogram(metric); err != nil {
+					return api.BadRequest(fmt.Sprintf("invalid histogram %s: %v", key, err))
+				}
+			case models.Summary:
+				if err := models.ValidateSummary(metric); err != nil {
+					return api.BadRequest(fmt.Sprintf("invalid summary %s: %v", key, err))
+				}
+			case models.Set:
+				normalized, err := models.NormalizeSet(metric)
+				if err != nil {
+					return api.BadRequest(fmt.Sprintf("invalid set %s: %v", key, err))
+				}
+				metric = normalized
 			}
+			if saved, exists := mergedMetrics[key]; exists {
+				merged, err := models.Merge(saved, metric)
+				if err != nil {
+					return api.BadRequest(err.Error())
+				}
+				metric = merged
+			}
+			mergedMetrics[key] = metric
 		default:
 			return api.BadRequest(fmt.Sprintf("invalid metric type: %s", metric.MType))
 		}
 	}
 
 	counters := make([]models.Metrics, 0, len(counterSums))
-	for id, delta := range counterSums {
-		deltaCopy := delta
-		counters = append(counters, models.Metrics{
-			ID:    id,
-			MType: models.Counter,
-			Delta: &deltaCopy,
-		})
+	for _, counter := range counterSums {
+		counters = append(counters, counter)
 	}
 
 	gauges := make([]models.Metrics, 0, len(gaugeLastValues))
-	for id, value := range gaugeLastValues {
-		valueCopy := value
-		gauges = append(gauges, models.Metrics{
-			ID:    id,
-			MType: models.Gauge,
-			Value: &valueCopy,
-		})
+	for _, gauge := range gaugeLastValues {
+		gauges = append(gauges, gauge)
+	}
+
+	increments := make([]models.Metrics, 0, len(gaugeIncrements))
+	for _, increment := range gaugeIncrements {
+		increments = append(increments, increment)
 	}
 
-	errChan := make(chan error, 2)
+	mergeables := make([]models.Metrics, 0, len(mergedMetrics))
+	for _, mergeable := range mergedMetrics {
+		mergeables = append(mergeables, mergeable)
+	}
+
+	counterErrChan := make(chan error, 1)
+	gaugeErrChan := make(chan error, 1)
+	incrementErrChan := make(chan error, 1)
+	mergeErrChan := make(chan error, 1)
 
 	if len(counters) > 0 {
-		go func() { errChan <- service.repository.AddAll(ctx, counters) }()
+		go func() { counterErrChan <- service.repository.AddAll(ctx, counters) }()
 	} else {
-		go func() { errChan <- nil }()
+		counterErrChan <- nil
 	}
 
 	if len(gauges) > 0 {
-		go func() { errChan <- service.repository.ResetAll(ctx, gauges) }()
+		go func() { gaugeErrChan <- service.repository.ResetAll(ctx, gauges) }()
+	} else {
+		gaugeErrChan <- nil
+	}
+
+	if len(increments) > 0 {
+		go func() { incrementErrChan <- service.repository.IncrementAll(ctx, increments) }()
+	} else {
+		incrementErrChan <- nil
+	}
+
+	if len(mergeables) > 0 {
+		go func() { mergeErrChan <- service.repository.MergeAll(ctx, mergeables) }()
 	} else {
-		go func() { errChan <- nil }()
+		mergeErrChan <- nil
 	}
 
-	err1 := <-errChan
-	err2 := <-errChan
+	counterErr := <-counterErrChan
+	gaugeErr := <-gaugeErrChan
+	incrementErr := <-incrementErrChan
+	mergeErr := <-mergeErrChan
+
+	for _, err := range []error{counterErr, gaugeErr, incrementErr, mergeErr} {
+		if errors.Is(err, repository.ErrTypeMismatch) {
+			return api.BadRequest(err.Error())
+		}
+	}
 
-	if err1 != nil || err2 != nil {
-		return api.Internal("save metrics error", fmt.Errorf("counters: %v, gauges: %v", err1, err2))
+	if counterErr != nil || gaugeErr != nil || incrementErr != nil || mergeErr != nil {
+		return api.Internal(
+			"save metrics error",
+			fmt.Errorf("counters: %v, gauges: %v, gauge increments: %v, mergeable metrics: %v", counterErr, gaugeErr, incrementErr, mergeErr),
+		)
 	}
 
+	service.registerUnits(ctx, metrics)
+	service.recordSamples(ctx, slices.Concat(counters, gauges, increments))
+
 	go service.notifyMany(ctx, metrics)
+	service.publish(ctx, metrics)
 
 	return nil
 }
+
+// saveError converts a repository write error to an API error.
+// Series stored with another type are reported as bad requests.
+func saveError(message string, err error) *api.APIError {
+	if errors.Is(err, repository.ErrTypeMismatch) {
+		return api.BadRequest(err.Error())
+	}
+	return api.Internal(message, err)
+}
+
+// latestTimestamp returns the later of two optional client timestamps.
+func latestTimestamp(saved *int64, timestamp *int64) *int64 {
+	if timestamp == nil || (saved != nil && *saved > *timestamp) {
+		return saved
+	}
+	return timestamp
+}
+
+// Import validates imported records and stores the valid ones through SaveAll.
+//
+// Process:
+//  1. Validates records with validateRecords
+//  2. Returns the report without storing anything for dry runs
+//  3. In models.CounterReplace mode keeps the last imported value of every
+//     counter series and converts it to the difference to the stored value
+//  4. Stores the valid records with SaveAll
+//
+// Replaced counters are read and updated separately, so concurrent updates
+// of the same counters are added to the imported values.
+func (service *metricsService) Import(ctx context.Context, records []models.ImportRecord, options models.ImportOptions) (models.ImportReport, *api.APIError) {
+	switch options.Counters {
+	case "", models.CounterAdd, models.CounterReplace:
+	default:
+		return models.ImportReport{}, api.BadRequest(fmt.Sprintf("invalid counters mode %q, expected add or replace", options.Counters))
+	}
+
+	stored, err := service.storedTypes(ctx, records)
+	if err != nil {
+		return models.ImportReport{}, api.Internal("Get metric types error", err)
+	}
+
+	valid, rejected := validateRecords(records, stored)
+	report := models.ImportReport{
+		Total:    len(records),
+		Imported: len(valid),
+		Failed:   len(rejected),
+		DryRun:   options.DryRun,
+		Errors:   rejected,
+	}
+
+	if options.DryRun || len(valid) == 0 {
+		return report, nil
+	}
+
+	if options.Counters == models.CounterReplace {
+		replaced, err := service.replaceCounters(ctx, valid)
+		if err != nil {
+			return models.ImportReport{}, api.Internal("Get metrics error", err)
+		}
+		valid = replaced
+	}
+
+	if err := service.SaveAll(ctx, valid); err != nil {
+		return models.ImportReport{}, err
+	}
+
+	return report, nil
+}
+
+// SaveBatch validates every metric of a batch and stores the valid ones through SaveAll.
+// Metrics are validated like imported records, so counters without a delta
+// and gauges without a value or increment are rejected instead of skipped.
+func (service *metricsService) SaveBatch(ctx context.Context, metrics []models.Metrics) (models.BatchResult, *api.APIError) {
+	records := make([]models.ImportRecord, 0, len(metrics))
+	for index, metric := range metrics {
+		records = append(records, models.ImportRecord{Line: index, Metric: metric})
+	}
+
+	stored, err := service.storedTypes(ctx, records)
+	if err != nil {
+		return models.BatchResult{}, api.Internal("Get metric types error", err)
+	}
+
+	valid, rejected := validateRecords(records, stored)
+	result := models.BatchResult{Accepted: len(valid), Rejected: len(rejected)}
+	for _, failure := range rejected {
+		result.Errors = append(result.Errors, models.BatchError{Index: failure.Line, Error: failure.Error})
+	}
+
+	if len(valid) == 0 {
+		return result, nil
+	}
+
+	if err := service.SaveAll(ctx, valid); err != nil {
+		return models.BatchResult{}, err
+	}
+
+	return result, nil
+}
+
+// storedTypes returns the stored types of the series of the records
+// keyed by series key.
+// Records without an ID are skipped, the repository isn't queried
+// if no series are referenced.
+func (service *metricsService) storedTypes(ctx context.Context, records []models.ImportRecord) (map[string]string, error) {
+	refs := make([]models.SeriesRef, 0, len(records))
+	seen := make(map[string]bool, len(records))
+	for _, record := range records {
+		ref := record.Metric.Ref()
+		if ref.ID == "" || seen[ref.Key()] {
+			continue
+		}
+		seen[ref.Key()] = true
+		refs = append(refs, ref)
+	}
+
+	if len(refs) == 0 {
+		return map[string]string{}, nil
+	}
+
+	return service.repository.GetTypes(ctx, refs)
+}
+
+// validateRecords validates every record on its own and rejects records
+// of series stored or reported earlier with another type as well as
+// histograms, summaries and sets that can't be merged with earlier records
+// of the same series.
+// Series are keyed by ID and labels like in SaveAll and in storage.
+//
+// stored: stored types keyed by series key, see storedTypes
+//
+// Returns:
+//   - []models.Metrics: metrics of valid records in record order
+//   - []models.ImportError: rejected records in record order
+func validateRecords(records []models.ImportRecord, stored map[string]string) ([]models.Metrics, []models.ImportError) {
+	valid := make([]models.Metrics, 0, len(records))
+	var rejected []models.ImportError
+
+	merged := make(map[string]models.Metrics)
+	types := make(map[string]string)
+	for _, record := range records {
+		metric, err := validateImported(record.Metric)
+		key := metric.Key()
+		if err == nil {
+			savedType, exists := stored[key]
+			if !exists {
+				savedType, exists = types[key]
+			}
+			if exists && savedType != metric.MType {
+				err = fmt.Errorf("metric %s has type %s", key, savedType)
+			}
+		}
+		if err == nil && models.IsMergeable(metric.MType) {
+			if saved, exists := merged[key]; exists {
+				metric, err = models.Merge(saved, metric)
+			}
+			if err == nil {
+				merged[key] = metric
+			}
+		}
+		if err != nil {
+			rejected = append(rejected, models.ImportError{Line: record.Line, Error: err.Error()})
+			continue
+		}
+		types[key] = metric.MType
+		valid = append(valid, record.Metric)
+	}
+
+	return valid, rejected
+}
+
+// validateImported validates a single imported metric.
+//
+// Returns:
+//   - models.Metrics: the metric, sets with members folded into the sketch
+//   - error: reason the metric is rejected
+func validateImported(metric models.Metrics) (models.Metrics, error) {
+	if metric.ID == "" {
+		return metric, errors.New("metric id is required")
+	}
+	if err := models.ValidateID(metric.ID); err != nil {
+		return metric, err
+	}
+	if err := models.ValidateLabels(metric.Labels); err != nil {
+		return metric, err
+	}
+
+	switch metric.MType {
+	case models.Counter:
+		if metric.Delta == nil {
+			return metric, errors.New("counter delta is required")
+		}
+		return metric, nil
+	case models.Gauge:
+		return metric, models.ValidateGauge(metric)
+	case models.Histogram:
+		return metric, models.ValidateHistogram(metric)
+	case models.Summary:
+		return metric, models.ValidateSummary(metric)
+	case models.Set:
+		return models.NormalizeSet(metric)
+	default:
+		return metric, fmt.Errorf("invalid metric type: %s", metric.MType)
+	}
+}
+
+// replaceCounters replaces counters with a single update per series
+// bringing the stored value to the last imported value.
+// Other metrics are returned unchanged.
+func (service *metricsService) replaceCounters(ctx context.Context, metrics []models.Metrics) ([]models.Metrics, error) {
+	targets := make(map[string]models.Metrics)
+	refs := make([]models.SeriesRef, 0)
+	replaced := make([]models.Metrics, 0, len(metrics))
+	for _, metric := range metrics {
+		if metric.MType != models.Counter {
+			replaced = append(replaced, metric)
+			continue
+		}
+
+		key := metric.Key()
+		if _, exists := targets[key]; !exists {
+			refs = append(refs, models.SeriesRef{ID: metric.ID, MType: models.Counter, Labels: metric.Labels})
+		}
+		targets[key] = metric
+	}
+
+	if len(refs) == 0 {
+		return replaced, nil
+	}
+
+	stored, err := service.repository.GetMany(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]int64, len(stored))
+	for _, metric := range stored {
+		if metric.MType == models.Counter && metric.Delta != nil {
+			current[metric.Key()] = *metric.Delta
+		}
+	}
+
+	for _, ref := range refs {
+		target := targets[models.SeriesKey(ref.ID, ref.Labels)]
+		delta := *target.Delta - current[target.Key()]
+		target.Delta = &delta
+		replaced = append(replaced, target)
+	}
+
+	return replaced, nil
+}
+
+// GetRange retrieves recorded points of a series within the query interval.
+// Validates the query and aggregates points into step windows:
+// counter deltas are summed, gauges keep the last value.
+func (service *metricsService) GetRange(ctx context.Context, query models.RangeQuery) ([]models.Point, *api.APIError) {
+	if query.ID == "" {
+		return nil, api.BadRequest("metric id is required")
+	}
+
+	if !models.HasHistory(query.MType) {
+		return nil, api.BadRequest(fmt.Sprintf("metric type %s has no history", query.MType))
+	}
+
+	if err := models.ValidateLabels(query.Labels); err != nil {
+		return nil, api.BadRequest(err.Error())
+	}
+
+	if query.To.Before(query.From) {
+		return nil, api.BadRequest("range end is before range start")
+	}
+
+	if query.Step < 0 {
+		return nil, api.BadRequest("step can't be negative")
+	}
+
+	points, err := service.repository.GetRange(ctx, query)
+	if err != nil {
+		return nil, api.Internal("Get metric range error", err)
+	}
+
+	return models.AggregatePoints(points, query.MType, query.From, query.Step), nil
+}
+
+// Rate computes the rate of a counter or gauge series from samples within the query window.
+// The window must be positive and can't exceed the sample retention.
+// Returns not found error if the series doesn't exist.
+func (service *metricsService) Rate(ctx context.Context, query models.RateQuery) (models.Rate, *api.APIError) {
+	if service.samples == nil {
+		return models.Rate{}, api.BadRequest("rate queries are disabled")
+	}
+
+	if query.ID == "" {
+		return models.Rate{}, api.BadRequest("metric id is required")
+	}
+
+	if !models.HasHistory(query.MType) {
+		return models.Rate{}, api.BadRequest(fmt.Sprintf("metric type %s has no rate", query.MType))
+	}
+
+	if err := models.ValidateLabels(query.Labels); err != nil {
+		return models.Rate{}, api.BadRequest(err.Error())
+	}
+
+	if query.Window <= 0 {
+		return models.Rate{}, api.BadRequest("window must be positive")
+	}
+
+	if query.Window > service.samples.retention {
+		return models.Rate{}, api.BadRequest(fmt.Sprintf("window can't exceed %s", service.samples.retention))
+	}
+
+	metric, err := service.repository.Get(ctx, query.ID, query.Labels)
+
+	if metric == nil || metric.MType != query.MType {
+		return models.Rate{}, api.NotFound(fmt.Sprintf("metric %v %v not found", models.SeriesKey(query.ID, query.Labels), query.MType))
+	}
+
+	if err != nil {
+		return models.Rate{}, api.Internal("Get metric error", err)
+	}
+
+	ref := models.SeriesRef{ID: query.ID, MType: query.MType, Labels: query.Labels}
+	points := service.samples.points(middleware.TenantFromCtx(ctx), ref, query.Window)
+
+	return models.NewRate(query, points), nil
+}
+
+// Delete removes all series of a metric by ID and type.
+// Performs audit logging asynchronously after successful deletion.
+func (service *metricsService) Delete(ctx context.Context, metricID string, metricType string) *api.APIError {
+	deleted, err := service.repository.Delete(ctx, models.DeleteQuery{ID: metricID, MType: metricType})
+	if err != nil {
+		return api.Internal("Delete metric error", err)
+	}
+
+	if len(deleted) == 0 {
+		return api.NotFound(fmt.Sprintf("Metric %s with type %s not found", metricID, metricType))
+	}
+
+	service.removeSamples(ctx, deleted)
+	go service.notifyDelete(ctx, deleted)
+
+	return nil
+}
+
+// DeleteAll removes all series of metrics whose IDs match a glob pattern.
+// Validates the pattern and the optional type, returns keys of deleted series.
+// Performs audit logging asynchronously if anything was deleted.
+func (service *metricsService) DeleteAll(ctx context.Context, pattern string, metricType string) (models.DeleteResult, *api.APIError) {
+	if pattern == "" {
+		return models.DeleteResult{}, api.BadRequest("pattern is required")
+	}
+
+	if err := models.ValidatePattern(pattern); err != nil {
+		return models.DeleteResult{}, api.BadRequest(fmt.Sprintf("invalid pattern %s: %v", pattern, err))
+	}
+
+	if metricType != "" && !models.IsKnownType(metricType) {
+		return models.DeleteResult{}, api.BadRequest(fmt.Sprintf("invalid metric type: %s", metricType))
+	}
+
+	deleted, err := service.repository.Delete(ctx, models.DeleteQuery{Pattern: pattern, MType: metricType})
+	if err != nil {
+		return models.DeleteResult{}, api.Internal("Delete metrics error", err)
+	}
+
+	result := models.DeleteResult{Deleted: make([]string, len(deleted))}
+	for i, metric := range deleted {
+		result.Deleted[i] = metric.Key()
+	}
+	slices.Sort(result.Deleted)
+
+	if len(deleted) > 0 {
+		service.removeSamples(ctx, deleted)
+		go service.notifyDelete(ctx, deleted)
+	}
+
+	return result, nil
+}
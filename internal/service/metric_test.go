@@ -5,13 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	api "github.com/gabkaclassic/metrics/pkg/error"
 
 	"github.com/gabkaclassic/metrics/internal/audit"
 	models "github.com/gabkaclassic/metrics/internal/model"
 	"github.com/gabkaclassic/metrics/internal/repository"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"github.com/gabkaclassic/metrics/pkg/sketch"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -19,37 +24,49 @@ import (
 
 func TestNewMetricsService(t *testing.T) {
 	mockRepo := repository.NewMockMetricsRepository(t)
+	mockMetaRepo := repository.NewMockMetaRepository(t)
 	mockAuditor := audit.NewMockAuditor(t)
 
 	tests := []struct {
-		name        string
-		repository  repository.MetricsRepository
-		auditor     audit.Auditor
-		expectError bool
+		name           string
+		repository     repository.MetricsRepository
+		metaRepository repository.MetaRepository
+		auditor        audit.Auditor
+		expectError    bool
 	}{
 		{
-			name:        "valid repository",
-			repository:  mockRepo,
-			auditor:     mockAuditor,
-			expectError: false,
+			name:           "valid repository",
+			repository:     mockRepo,
+			metaRepository: mockMetaRepo,
+			auditor:        mockAuditor,
+			expectError:    false,
 		},
 		{
-			name:        "nil repository",
-			repository:  nil,
-			auditor:     mockAuditor,
-			expectError: true,
+			name:           "nil repository",
+			repository:     nil,
+			metaRepository: mockMetaRepo,
+			auditor:        mockAuditor,
+			expectError:    true,
 		},
 		{
-			name:        "nil auditor",
-			repository:  mockRepo,
-			auditor:     nil,
-			expectError: true,
+			name:           "nil meta repository",
+			repository:     mockRepo,
+			metaRepository: nil,
+			auditor:        mockAuditor,
+			expectError:    true,
+		},
+		{
+			name:           "nil auditor",
+			repository:     mockRepo,
+			metaRepository: mockMetaRepo,
+			auditor:        nil,
+			expectError:    true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			svc, err := NewMetricsService(tt.repository, tt.auditor)
+			svc, err := NewMetricsService(tt.repository, tt.metaRepository, tt.auditor)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -77,7 +94,7 @@ func TestMetricsService_Get(t *testing.T) {
 			metricID:   "m1",
 			metricType: models.Gauge,
 			setupMock: func(m *repository.MockMetricsRepository) {
-				m.EXPECT().Get(mock.Anything, "m1").
+				m.EXPECT().Get(mock.Anything, "m1", mock.Anything).
 					Return(&models.Metrics{ID: "m1", MType: models.Gauge, Value: floatPtr(10)}, nil)
 			},
 			expectValue:    floatPtr(10),
@@ -89,7 +106,7 @@ func TestMetricsService_Get(t *testing.T) {
 			metricID:   "m1",
 			metricType: models.Counter,
 			setupMock: func(m *repository.MockMetricsRepository) {
-				m.EXPECT().Get(mock.Anything, "m1").
+				m.EXPECT().Get(mock.Anything, "m1", mock.Anything).
 					Return(&models.Metrics{ID: "m1", MType: models.Gauge, Value: floatPtr(10)}, nil)
 			},
 			expectValue:    nil,
@@ -101,7 +118,7 @@ func TestMetricsService_Get(t *testing.T) {
 			metricID:   "m2",
 			metricType: models.Gauge,
 			setupMock: func(m *repository.MockMetricsRepository) {
-				m.EXPECT().Get(mock.Anything, "m2").
+				m.EXPECT().Get(mock.Anything, "m2", mock.Anything).
 					Return(nil, nil)
 			},
 			expectValue:    nil,
@@ -113,7 +130,7 @@ func TestMetricsService_Get(t *testing.T) {
 			metricID:   "m3",
 			metricType: models.Gauge,
 			setupMock: func(m *repository.MockMetricsRepository) {
-				m.EXPECT().Get(mock.Anything, "m3").
+				m.EXPECT().Get(mock.Anything, "m3", mock.Anything).
 					Return(nil, errors.New("db error"))
 			},
 			expectValue:    nil,
@@ -125,13 +142,41 @@ func TestMetricsService_Get(t *testing.T) {
 			metricID:   "m4",
 			metricType: models.Counter,
 			setupMock: func(m *repository.MockMetricsRepository) {
-				m.EXPECT().Get(mock.Anything, "m4").
+				m.EXPECT().Get(mock.Anything, "m4", mock.Anything).
 					Return(&models.Metrics{ID: "m4", MType: models.Counter, Delta: intPtr(42)}, nil)
 			},
 			expectValue:    intPtr(42),
 			expectAPIError: false,
 			expectNotFound: false,
 		},
+		{
+			name:       "metric exists with correct type (set)",
+			metricID:   "m6",
+			metricType: models.Set,
+			setupMock: func(m *repository.MockMetricsRepository) {
+				set := models.ObserveSet("m6", "a", "b", "c")
+				m.EXPECT().Get(mock.Anything, "m6", mock.Anything).Return(&set, nil)
+			},
+			expectValue:    int64(3),
+			expectAPIError: false,
+			expectNotFound: false,
+		},
+		{
+			name:       "metric exists with correct type (summary)",
+			metricID:   "m5",
+			metricType: models.Summary,
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().Get(mock.Anything, "m5", mock.Anything).
+					Return(&models.Metrics{ID: "m5", MType: models.Summary, Sketch: encodeSketch(t, 0.01, 4)}, nil)
+			},
+			expectValue: models.SummarySnapshot{
+				Count:     1,
+				Sum:       4,
+				Quantiles: map[string]float64{"0.5": 4, "0.9": 4, "0.95": 4, "0.99": 4},
+			},
+			expectAPIError: false,
+			expectNotFound: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -140,7 +185,7 @@ func TestMetricsService_Get(t *testing.T) {
 			mockAuditor := audit.NewMockAuditor(t)
 			tt.setupMock(mockRepo)
 
-			svc, err := NewMetricsService(mockRepo, mockAuditor)
+			svc, err := NewMetricsService(mockRepo, repository.NewMockMetaRepository(t), mockAuditor)
 			assert.NoError(t, err)
 
 			result, apiErr := svc.Get(t.Context(), tt.metricID, tt.metricType)
@@ -165,7 +210,9 @@ func TestMetricsService_GetStruct(t *testing.T) {
 		name           string
 		metricID       string
 		metricType     string
-		mockGet        func(context.Context, string) (*models.Metrics, error)
+		labels         map[string]string
+		mockGet        func(context.Context, string, map[string]string) (*models.Metrics, error)
+		meta           *models.Meta
 		expectResult   *models.Metrics
 		expectErrorMsg string
 		expectStatus   int
@@ -174,7 +221,7 @@ func TestMetricsService_GetStruct(t *testing.T) {
 			name:       "metric found and type matches",
 			metricID:   "m1",
 			metricType: "counter",
-			mockGet: func(ctx context.Context, id string) (*models.Metrics, error) {
+			mockGet: func(ctx context.Context, id string, labels map[string]string) (*models.Metrics, error) {
 				delta := int64(10)
 				return &models.Metrics{ID: "m1", MType: "counter", Delta: &delta}, nil
 			},
@@ -185,11 +232,63 @@ func TestMetricsService_GetStruct(t *testing.T) {
 			},
 			expectStatus: http.StatusOK,
 		},
+		{
+			name:       "metric found with unit",
+			metricID:   "HeapAlloc",
+			metricType: "gauge",
+			mockGet: func(ctx context.Context, id string, labels map[string]string) (*models.Metrics, error) {
+				val := 1024.0
+				return &models.Metrics{ID: "HeapAlloc", MType: "gauge", Value: &val}, nil
+			},
+			meta: &models.Meta{ID: "HeapAlloc", Unit: "bytes"},
+			expectResult: &models.Metrics{
+				ID:    "HeapAlloc",
+				MType: "gauge",
+				Value: floatPtr(1024),
+				Unit:  "bytes",
+			},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:       "labeled metric found",
+			metricID:   "m1",
+			metricType: "gauge",
+			labels:     map[string]string{"host": "web-1"},
+			mockGet: func(ctx context.Context, id string, labels map[string]string) (*models.Metrics, error) {
+				val := 1.5
+				return &models.Metrics{ID: "m1", MType: "gauge", Labels: labels, Value: &val}, nil
+			},
+			expectResult: &models.Metrics{
+				ID:     "m1",
+				MType:  "gauge",
+				Labels: map[string]string{"host": "web-1"},
+				Value:  floatPtr(1.5),
+			},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:       "summary reports quantiles",
+			metricID:   "s1",
+			metricType: models.Summary,
+			mockGet: func(ctx context.Context, id string, labels map[string]string) (*models.Metrics, error) {
+				summary := models.ObserveSummary("s1", 2)
+				return &summary, nil
+			},
+			expectResult: &models.Metrics{
+				ID:        "s1",
+				MType:     models.Summary,
+				Sum:       floatPtr(2),
+				Count:     intPtr(1),
+				Sketch:    models.ObserveSummary("s1", 2).Sketch,
+				Quantiles: map[string]float64{"0.5": 2, "0.9": 2, "0.95": 2, "0.99": 2},
+			},
+			expectStatus: http.StatusOK,
+		},
 		{
 			name:       "metric not found",
 			metricID:   "m2",
 			metricType: "gauge",
-			mockGet: func(ctx context.Context, id string) (*models.Metrics, error) {
+			mockGet: func(ctx context.Context, id string, labels map[string]string) (*models.Metrics, error) {
 				return nil, nil
 			},
 			expectErrorMsg: "metric m2 gauge not found",
@@ -199,7 +298,7 @@ func TestMetricsService_GetStruct(t *testing.T) {
 			name:       "metric type mismatch",
 			metricID:   "m3",
 			metricType: "counter",
-			mockGet: func(ctx context.Context, id string) (*models.Metrics, error) {
+			mockGet: func(ctx context.Context, id string, labels map[string]string) (*models.Metrics, error) {
 				val := 3.14
 				return &models.Metrics{ID: "m3", MType: "gauge", Value: &val}, nil
 			},
@@ -210,7 +309,7 @@ func TestMetricsService_GetStruct(t *testing.T) {
 			name:       "repository returns error (metric is nil)",
 			metricID:   "m4",
 			metricType: "counter",
-			mockGet: func(ctx context.Context, id string) (*models.Metrics, error) {
+			mockGet: func(ctx context.Context, id string, labels map[string]string) (*models.Metrics, error) {
 				return nil, errors.New("db error")
 			},
 			expectErrorMsg: "metric m4 counter not found",
@@ -220,7 +319,7 @@ func TestMetricsService_GetStruct(t *testing.T) {
 			name:       "repository returns error but metric not nil",
 			metricID:   "m5",
 			metricType: "counter",
-			mockGet: func(ctx context.Context, id string) (*models.Metrics, error) {
+			mockGet: func(ctx context.Context, id string, labels map[string]string) (*models.Metrics, error) {
 				delta := int64(1)
 				return &models.Metrics{ID: "m5", MType: "counter", Delta: &delta}, errors.New("db error")
 			},
@@ -233,21 +332,29 @@ func TestMetricsService_GetStruct(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := repository.NewMockMetricsRepository(t)
 			mockAuditor := audit.NewMockAuditor(t)
+			mockMetaRepo := repository.NewMockMetaRepository(t)
 			mockRepo.EXPECT().
-				Get(mock.Anything, tt.metricID).
+				Get(mock.Anything, tt.metricID, tt.labels).
 				RunAndReturn(tt.mockGet)
+			if tt.expectStatus == http.StatusOK {
+				mockMetaRepo.EXPECT().
+					Get(mock.Anything, tt.metricID).
+					Return(tt.meta, nil)
+			}
 
-			svc, _ := NewMetricsService(mockRepo, mockAuditor)
+			svc, _ := NewMetricsService(mockRepo, mockMetaRepo, mockAuditor)
 
-			result, apiErr := svc.GetStruct(t.Context(), tt.metricID, tt.metricType)
+			result, apiErr := svc.GetStruct(t.Context(), tt.metricID, tt.metricType, tt.labels)
 
 			if tt.expectStatus == http.StatusOK {
 				require.Nil(t, apiErr)
 				require.NotNil(t, result)
 				assert.Equal(t, tt.expectResult.ID, result.ID)
 				assert.Equal(t, tt.expectResult.MType, result.MType)
+				assert.Equal(t, tt.expectResult.Labels, result.Labels)
 				assert.Equal(t, tt.expectResult.Delta, result.Delta)
 				assert.Equal(t, tt.expectResult.Value, result.Value)
+				assert.Equal(t, tt.expectResult.Unit, result.Unit)
 			} else {
 				require.NotNil(t, apiErr)
 				assert.Equal(t, tt.expectStatus, apiErr.Code)
@@ -312,6 +419,65 @@ func TestMetricsService_Save(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name:       "gauge increment",
+			id:         "g1",
+			metricType: models.Gauge,
+			rawValue:   "+5",
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().
+					Increment(mock.Anything, models.Metrics{
+						ID:        "g1",
+						MType:     models.Gauge,
+						Increment: floatPtr(5),
+					}).
+					Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name:       "gauge decrement",
+			id:         "g1",
+			metricType: models.Gauge,
+			rawValue:   "+-2.5",
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().
+					Increment(mock.Anything, models.Metrics{
+						ID:        "g1",
+						MType:     models.Gauge,
+						Increment: floatPtr(-2.5),
+					}).
+					Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name:       "negative gauge value",
+			id:         "g1",
+			metricType: models.Gauge,
+			rawValue:   "-2.5",
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().
+					ResetOne(mock.Anything, models.Metrics{
+						ID:    "g1",
+						MType: models.Gauge,
+						Value: floatPtr(-2.5),
+					}).
+					Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name:       "gauge increment error",
+			id:         "g1",
+			metricType: models.Gauge,
+			rawValue:   "+1",
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().Increment(mock.Anything, mock.Anything).Return(errors.New("db error"))
+			},
+			expectError:   true,
+			errorContains: "Increment value error",
+		},
 		{
 			name:          "invalid gauge",
 			id:            "g2",
@@ -321,6 +487,103 @@ func TestMetricsService_Save(t *testing.T) {
 			expectError:   true,
 			errorContains: "invalid metric value",
 		},
+		{
+			name:       "valid histogram with default bounds",
+			id:         "h1",
+			metricType: models.Histogram,
+			rawValue:   "0.3",
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().Get(mock.Anything, "h1", mock.Anything).Return(nil, errors.New("not found"))
+				m.EXPECT().
+					Merge(mock.Anything, models.ObserveHistogram("h1", models.DefaultBounds, 0.3)).
+					Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name:       "valid histogram with stored bounds",
+			id:         "h2",
+			metricType: models.Histogram,
+			rawValue:   "7",
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().Get(mock.Anything, "h2", mock.Anything).Return(&models.Metrics{
+					ID:      "h2",
+					MType:   models.Histogram,
+					Bounds:  []float64{5, 10},
+					Buckets: []int64{1, 0, 0},
+					Sum:     floatPtr(1),
+					Count:   intPtr(1),
+				}, nil)
+				m.EXPECT().
+					Merge(mock.Anything, models.ObserveHistogram("h2", []float64{5, 10}, 7)).
+					Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name:          "invalid histogram",
+			id:            "h3",
+			metricType:    models.Histogram,
+			rawValue:      "abc",
+			setupMock:     func(m *repository.MockMetricsRepository) {},
+			expectError:   true,
+			errorContains: "invalid metric value",
+		},
+		{
+			name:       "valid summary",
+			id:         "s1",
+			metricType: models.Summary,
+			rawValue:   "0.3",
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().
+					Merge(mock.Anything, models.ObserveSummary("s1", 0.3)).
+					Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name:       "summary merge error",
+			id:         "s1",
+			metricType: models.Summary,
+			rawValue:   "0.3",
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().Merge(mock.Anything, mock.Anything).Return(errors.New("db error"))
+			},
+			expectError:   true,
+			errorContains: "Merge summary error",
+		},
+		{
+			name:       "set member",
+			id:         "u1",
+			metricType: models.Set,
+			rawValue:   "alice",
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().
+					Merge(mock.Anything, models.ObserveSet("u1", "alice")).
+					Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name:       "set merge error",
+			id:         "u1",
+			metricType: models.Set,
+			rawValue:   "alice",
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().Merge(mock.Anything, mock.Anything).Return(errors.New("db error"))
+			},
+			expectError:   true,
+			errorContains: "Merge set error",
+		},
+		{
+			name:          "invalid summary",
+			id:            "s2",
+			metricType:    models.Summary,
+			rawValue:      "abc",
+			setupMock:     func(m *repository.MockMetricsRepository) {},
+			expectError:   true,
+			errorContains: "invalid metric value",
+		},
 		{
 			name:          "invalid type",
 			id:            "x1",
@@ -338,7 +601,7 @@ func TestMetricsService_Save(t *testing.T) {
 			mockAuditor := audit.NewMockAuditor(t)
 			tt.setupMock(mockRepo)
 
-			svc, err := NewMetricsService(mockRepo, mockAuditor)
+			svc, err := NewMetricsService(mockRepo, repository.NewMockMetaRepository(t), mockAuditor)
 			assert.NoError(t, err)
 
 			apiErr := svc.Save(t.Context(), tt.id, tt.metricType, tt.rawValue)
@@ -359,6 +622,7 @@ func TestMetricsService_SaveStruct(t *testing.T) {
 		input          models.Metrics
 		mockAdd        func(ctx context.Context, metric models.Metrics)
 		mockReset      func(ctx context.Context, metric models.Metrics)
+		mockMerge      models.Metrics
 		expectErrorMsg string
 		expectStatus   int
 	}{
@@ -390,6 +654,107 @@ func TestMetricsService_SaveStruct(t *testing.T) {
 			},
 			expectStatus: http.StatusOK,
 		},
+		{
+			name: "gauge increment calls Increment",
+			input: models.Metrics{
+				ID:        "m12",
+				MType:     models.Gauge,
+				Increment: floatPtr(-1.5),
+			},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name: "gauge with value and increment",
+			input: models.Metrics{
+				ID:        "m13",
+				MType:     models.Gauge,
+				Value:     floatPtr(1),
+				Increment: floatPtr(1),
+			},
+			expectErrorMsg: "invalid gauge m13: gauge value and increment are mutually exclusive",
+			expectStatus:   http.StatusBadRequest,
+		},
+		{
+			name: "gauge without value",
+			input: models.Metrics{
+				ID:    "m14",
+				MType: models.Gauge,
+			},
+			expectErrorMsg: "invalid gauge m14: gauge value or increment is required",
+			expectStatus:   http.StatusBadRequest,
+		},
+		{
+			name:         "histogram metric calls Merge",
+			input:        models.ObserveHistogram("m4", []float64{1, 2}, 1.5),
+			expectStatus: http.StatusOK,
+		},
+		{
+			name: "invalid histogram",
+			input: models.Metrics{
+				ID:      "m5",
+				MType:   models.Histogram,
+				Bounds:  []float64{1, 2},
+				Buckets: []int64{1},
+				Sum:     floatPtr(1),
+				Count:   intPtr(1),
+			},
+			expectErrorMsg: "invalid histogram m5",
+			expectStatus:   http.StatusBadRequest,
+		},
+		{
+			name:         "summary metric calls Merge",
+			input:        models.ObserveSummary("m7", 1.5),
+			expectStatus: http.StatusOK,
+		},
+		{
+			name: "invalid summary sketch",
+			input: models.Metrics{
+				ID:     "m8",
+				MType:  models.Summary,
+				Sketch: []byte("broken"),
+			},
+			expectErrorMsg: "invalid summary m8",
+			expectStatus:   http.StatusBadRequest,
+		},
+		{
+			name: "set members",
+			input: models.Metrics{
+				ID:      "m10",
+				MType:   models.Set,
+				Members: []string{"alice", "bob"},
+			},
+			mockMerge:    models.ObserveSet("m10", "alice", "bob"),
+			expectStatus: http.StatusOK,
+		},
+		{
+			name: "set without members",
+			input: models.Metrics{
+				ID:    "m11",
+				MType: models.Set,
+			},
+			expectErrorMsg: "invalid set m11: set members or sketch are required",
+			expectStatus:   http.StatusBadRequest,
+		},
+		{
+			name: "missing summary sketch",
+			input: models.Metrics{
+				ID:    "m9",
+				MType: models.Summary,
+			},
+			expectErrorMsg: "summary sketch is required",
+			expectStatus:   http.StatusBadRequest,
+		},
+		{
+			name: "invalid label name",
+			input: models.Metrics{
+				ID:     "m6",
+				MType:  models.Counter,
+				Labels: map[string]string{"1host": "web-1"},
+				Delta:  intPtr(1),
+			},
+			expectErrorMsg: "invalid label name",
+			expectStatus:   http.StatusBadRequest,
+		},
 		{
 			name: "invalid metric type",
 			input: models.Metrics{
@@ -406,7 +771,7 @@ func TestMetricsService_SaveStruct(t *testing.T) {
 			mockRepo := repository.NewMockMetricsRepository(t)
 			mockAuditor := audit.NewMockAuditor(t)
 
-			if tt.input.MType == models.Counter {
+			if tt.input.MType == models.Counter && tt.expectStatus == http.StatusOK {
 				mockRepo.EXPECT().
 					Add(mock.Anything, mock.AnythingOfType("models.Metrics")).
 					RunAndReturn(func(ctx context.Context, metric models.Metrics) error {
@@ -416,7 +781,12 @@ func TestMetricsService_SaveStruct(t *testing.T) {
 						return nil
 					})
 			}
-			if tt.input.MType == models.Gauge {
+			if tt.input.MType == models.Gauge && tt.input.Increment != nil && tt.expectStatus == http.StatusOK {
+				mockRepo.EXPECT().
+					Increment(mock.Anything, tt.input).
+					Return(nil)
+			}
+			if tt.input.MType == models.Gauge && tt.input.Increment == nil && tt.expectStatus == http.StatusOK {
 				mockRepo.EXPECT().
 					ResetOne(mock.Anything, mock.AnythingOfType("models.Metrics")).
 					RunAndReturn(func(ctx context.Context, metric models.Metrics) error {
@@ -427,7 +797,17 @@ func TestMetricsService_SaveStruct(t *testing.T) {
 					})
 			}
 
-			svc, _ := NewMetricsService(mockRepo, mockAuditor)
+			if models.IsMergeable(tt.input.MType) && tt.expectStatus == http.StatusOK {
+				merged := tt.input
+				if tt.mockMerge.ID != "" {
+					merged = tt.mockMerge
+				}
+				mockRepo.EXPECT().
+					Merge(mock.Anything, merged).
+					Return(nil)
+			}
+
+			svc, _ := NewMetricsService(mockRepo, repository.NewMockMetaRepository(t), mockAuditor)
 
 			apiErr := svc.SaveStruct(t.Context(), tt.input)
 
@@ -489,7 +869,7 @@ func TestMetricsService_GetAll(t *testing.T) {
 				GetAll(mock.Anything).
 				Return(tt.mockReturn, tt.expectedError)
 
-			svc, err := NewMetricsService(mockRepo, mockAuditor)
+			svc, err := NewMetricsService(mockRepo, repository.NewMockMetaRepository(t), mockAuditor)
 			assert.NoError(t, err)
 
 			result, err := svc.GetAll(t.Context())
@@ -510,48 +890,353 @@ func TestMetricsService_GetAll(t *testing.T) {
 	}
 }
 
-func TestMetricsService_SaveAll(t *testing.T) {
+func TestMetricsService_GetUnits(t *testing.T) {
 	tests := []struct {
-		name          string
-		metrics       []models.Metrics
-		mockCounterFn func(repo *repository.MockMetricsRepository, metrics []models.Metrics)
-		mockGaugeFn   func(repo *repository.MockMetricsRepository, metrics []models.Metrics)
-		expectedError *api.APIError
+		name        string
+		mockReturn  map[string]models.Meta
+		mockErr     error
+		expected    map[string]string
+		expectError bool
 	}{
 		{
-			name:    "empty metrics",
-			metrics: []models.Metrics{},
-			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
-			},
-			mockGaugeFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+			name: "metrics with and without unit",
+			mockReturn: map[string]models.Meta{
+				"HeapAlloc": {ID: "HeapAlloc", Unit: "bytes"},
+				"Custom":    {ID: "Custom", Description: "no unit"},
 			},
-			expectedError: nil,
+			expected: map[string]string{"HeapAlloc": "bytes"},
 		},
 		{
-			name: "only counter metrics",
-			metrics: []models.Metrics{
-				{ID: "c1", MType: models.Counter, Delta: intPtr(10)},
-				{ID: "c1", MType: models.Counter, Delta: intPtr(5)},
-			},
-			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
-				repo.EXPECT().AddAll(mock.Anything, mock.Anything).Return(nil)
-			},
-			mockGaugeFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
-			},
-			expectedError: nil,
+			name:       "no metadata",
+			mockReturn: map[string]models.Meta{},
+			expected:   map[string]string{},
 		},
 		{
-			name: "only gauge metrics",
-			metrics: []models.Metrics{
-				{ID: "g1", MType: models.Gauge, Value: floatPtr(3.14)},
-				{ID: "g2", MType: models.Gauge, Value: floatPtr(2.71)},
-			},
-			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
-			},
-			mockGaugeFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
-				repo.EXPECT().ResetAll(mock.Anything, mock.Anything).Return(nil)
-			},
-			expectedError: nil,
+			name:        "repository returns error",
+			mockErr:     errors.New("db error"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMetaRepo := repository.NewMockMetaRepository(t)
+			mockMetaRepo.EXPECT().
+				GetAll(mock.Anything).
+				Return(tt.mockReturn, tt.mockErr)
+
+			svc, err := NewMetricsService(repository.NewMockMetricsRepository(t), mockMetaRepo, audit.NewMockAuditor(t))
+			require.NoError(t, err)
+
+			result, apiErr := svc.GetUnits(t.Context())
+
+			if tt.expectError {
+				require.NotNil(t, apiErr)
+				assert.Equal(t, http.StatusInternalServerError, apiErr.Code)
+				assert.Nil(t, result)
+			} else {
+				assert.Nil(t, apiErr)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMetricsService_GetUpdated(t *testing.T) {
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		mockReturn  map[string]time.Time
+		mockErr     error
+		expectError bool
+	}{
+		{
+			name:       "update times",
+			mockReturn: map[string]time.Time{"c1": updatedAt, `g1{host="a"}`: updatedAt},
+		},
+		{
+			name:        "repository returns error",
+			mockErr:     errors.New("db error"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockMetricsRepository(t)
+			mockRepo.EXPECT().
+				GetUpdated(mock.Anything).
+				Return(tt.mockReturn, tt.mockErr)
+
+			svc, err := NewMetricsService(mockRepo, repository.NewMockMetaRepository(t), audit.NewMockAuditor(t))
+			require.NoError(t, err)
+
+			result, apiErr := svc.GetUpdated(t.Context())
+
+			if tt.expectError {
+				require.NotNil(t, apiErr)
+				assert.Equal(t, http.StatusInternalServerError, apiErr.Code)
+				assert.Nil(t, result)
+			} else {
+				assert.Nil(t, apiErr)
+				assert.Equal(t, tt.mockReturn, result)
+			}
+		})
+	}
+}
+
+func TestMetricsService_GetAllMetrics(t *testing.T) {
+	tests := []struct {
+		name        string
+		tenant      string
+		mockReturn  []models.Metrics
+		mockErr     error
+		expected    []models.Metrics
+		expectError bool
+	}{
+		{
+			name:   "metrics of request tenant",
+			tenant: "team-a",
+			mockReturn: []models.Metrics{
+				{ID: "c1", MType: models.Counter, Delta: intPtr(1), Tenant: middleware.DefaultTenant},
+				{ID: "g1", MType: models.Gauge, Value: floatPtr(2), Tenant: "team-a"},
+			},
+			expected: []models.Metrics{
+				{ID: "g1", MType: models.Gauge, Value: floatPtr(2), Tenant: "team-a"},
+			},
+		},
+		{
+			name:       "no metrics",
+			tenant:     middleware.DefaultTenant,
+			mockReturn: []models.Metrics{},
+			expected:   []models.Metrics{},
+		},
+		{
+			name:        "repository returns error",
+			tenant:      middleware.DefaultTenant,
+			mockErr:     errors.New("db error"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockMetricsRepository(t)
+			mockRepo.EXPECT().
+				GetAllMetrics(mock.Anything).
+				Return(tt.mockReturn, tt.mockErr)
+
+			svc, err := NewMetricsService(mockRepo, repository.NewMockMetaRepository(t), audit.NewMockAuditor(t))
+			require.NoError(t, err)
+
+			result, apiErr := svc.GetAllMetrics(middleware.WithTenant(t.Context(), tt.tenant))
+
+			if tt.expectError {
+				require.NotNil(t, apiErr)
+				assert.Equal(t, http.StatusInternalServerError, apiErr.Code)
+				assert.Nil(t, result)
+			} else {
+				assert.Nil(t, apiErr)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMetricsService_ExportPage(t *testing.T) {
+	after := &models.SeriesRef{ID: "c1", MType: models.Counter}
+
+	tests := []struct {
+		name        string
+		after       *models.SeriesRef
+		mockReturn  []models.Metrics
+		mockErr     error
+		expectError bool
+	}{
+		{
+			name:       "first page",
+			mockReturn: []models.Metrics{{ID: "c1", MType: models.Counter, Delta: intPtr(1)}},
+		},
+		{
+			name:       "page after series",
+			after:      after,
+			mockReturn: []models.Metrics{{ID: "g1", MType: models.Gauge, Value: floatPtr(1)}},
+		},
+		{
+			name:        "repository error",
+			mockErr:     errors.New("db error"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockMetricsRepository(t)
+			mockRepo.EXPECT().
+				List(mock.Anything, models.ListQuery{SortBy: models.SortByID, Limit: ExportPageSize, After: tt.after}).
+				Return(tt.mockReturn, tt.mockErr)
+
+			svc, err := NewMetricsService(mockRepo, repository.NewMockMetaRepository(t), audit.NewMockAuditor(t))
+			require.NoError(t, err)
+
+			result, apiErr := svc.ExportPage(t.Context(), tt.after)
+
+			if tt.expectError {
+				require.NotNil(t, apiErr)
+				assert.Equal(t, http.StatusInternalServerError, apiErr.Code)
+				assert.Nil(t, result)
+			} else {
+				assert.Nil(t, apiErr)
+				assert.Equal(t, tt.mockReturn, result)
+			}
+		})
+	}
+}
+
+func TestMetricsService_GetDescriptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockReturn  map[string]models.Meta
+		mockErr     error
+		expected    map[string]string
+		expectError bool
+	}{
+		{
+			name: "metrics with and without description",
+			mockReturn: map[string]models.Meta{
+				"HeapAlloc": {ID: "HeapAlloc", Unit: "bytes"},
+				"Custom":    {ID: "Custom", Description: "custom gauge"},
+			},
+			expected: map[string]string{"Custom": "custom gauge"},
+		},
+		{
+			name:        "repository returns error",
+			mockErr:     errors.New("db error"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMetaRepo := repository.NewMockMetaRepository(t)
+			mockMetaRepo.EXPECT().
+				GetAll(mock.Anything).
+				Return(tt.mockReturn, tt.mockErr)
+
+			svc, err := NewMetricsService(repository.NewMockMetricsRepository(t), mockMetaRepo, audit.NewMockAuditor(t))
+			require.NoError(t, err)
+
+			result, apiErr := svc.GetDescriptions(t.Context())
+
+			if tt.expectError {
+				require.NotNil(t, apiErr)
+				assert.Equal(t, http.StatusInternalServerError, apiErr.Code)
+				assert.Nil(t, result)
+			} else {
+				assert.Nil(t, apiErr)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMetricsService_RegisterUnits(t *testing.T) {
+	t.Run("SaveStruct registers reported unit", func(t *testing.T) {
+		mockRepo := repository.NewMockMetricsRepository(t)
+		mockMetaRepo := repository.NewMockMetaRepository(t)
+		mockRepo.EXPECT().ResetOne(mock.Anything, mock.Anything).Return(nil)
+		mockMetaRepo.EXPECT().
+			Register(mock.Anything, []models.Meta{{ID: "HeapAlloc", Unit: "bytes"}}).
+			Return(nil)
+
+		svc, err := NewMetricsService(mockRepo, mockMetaRepo, audit.NewMockAuditor(t))
+		require.NoError(t, err)
+
+		apiErr := svc.SaveStruct(t.Context(), models.Metrics{ID: "HeapAlloc", MType: models.Gauge, Value: floatPtr(1), Unit: "bytes"})
+		assert.Nil(t, apiErr)
+	})
+
+	t.Run("SaveAll registers every metric once", func(t *testing.T) {
+		mockRepo := repository.NewMockMetricsRepository(t)
+		mockMetaRepo := repository.NewMockMetaRepository(t)
+		mockRepo.EXPECT().AddAll(mock.Anything, mock.Anything).Return(nil)
+		mockRepo.EXPECT().ResetAll(mock.Anything, mock.Anything).Return(nil)
+		mockMetaRepo.EXPECT().
+			Register(mock.Anything, []models.Meta{
+				{ID: "HeapAlloc", Unit: "bytes"},
+				{ID: "PollCount", Unit: "count"},
+			}).
+			Return(nil)
+
+		svc, err := NewMetricsService(mockRepo, mockMetaRepo, audit.NewMockAuditor(t))
+		require.NoError(t, err)
+
+		apiErr := svc.SaveAll(t.Context(), []models.Metrics{
+			{ID: "HeapAlloc", MType: models.Gauge, Value: floatPtr(1), Unit: "bytes"},
+			{ID: "HeapAlloc", MType: models.Gauge, Value: floatPtr(2), Unit: "bytes"},
+			{ID: "PollCount", MType: models.Counter, Delta: intPtr(1), Unit: "count"},
+			{ID: "Custom", MType: models.Counter, Delta: intPtr(1)},
+		})
+		assert.Nil(t, apiErr)
+	})
+
+	t.Run("registration error does not fail save", func(t *testing.T) {
+		mockRepo := repository.NewMockMetricsRepository(t)
+		mockMetaRepo := repository.NewMockMetaRepository(t)
+		mockRepo.EXPECT().Add(mock.Anything, mock.Anything).Return(nil)
+		mockMetaRepo.EXPECT().Register(mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+		svc, err := NewMetricsService(mockRepo, mockMetaRepo, audit.NewMockAuditor(t))
+		require.NoError(t, err)
+
+		apiErr := svc.SaveStruct(t.Context(), models.Metrics{ID: "PollCount", MType: models.Counter, Delta: intPtr(1), Unit: "count"})
+		assert.Nil(t, apiErr)
+	})
+}
+
+func TestMetricsService_SaveAll(t *testing.T) {
+	tests := []struct {
+		name          string
+		metrics       []models.Metrics
+		mockCounterFn func(repo *repository.MockMetricsRepository, metrics []models.Metrics)
+		mockGaugeFn   func(repo *repository.MockMetricsRepository, metrics []models.Metrics)
+		expectedError *api.APIError
+	}{
+		{
+			name:    "empty metrics",
+			metrics: []models.Metrics{},
+			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+			},
+			mockGaugeFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+			},
+			expectedError: nil,
+		},
+		{
+			name: "only counter metrics",
+			metrics: []models.Metrics{
+				{ID: "c1", MType: models.Counter, Delta: intPtr(10)},
+				{ID: "c1", MType: models.Counter, Delta: intPtr(5)},
+			},
+			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+				repo.EXPECT().AddAll(mock.Anything, mock.Anything).Return(nil)
+			},
+			mockGaugeFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+			},
+			expectedError: nil,
+		},
+		{
+			name: "only gauge metrics",
+			metrics: []models.Metrics{
+				{ID: "g1", MType: models.Gauge, Value: floatPtr(3.14)},
+				{ID: "g2", MType: models.Gauge, Value: floatPtr(2.71)},
+			},
+			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+			},
+			mockGaugeFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+				repo.EXPECT().ResetAll(mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedError: nil,
 		},
 		{
 			name: "mixed metrics",
@@ -570,78 +1255,1165 @@ func TestMetricsService_SaveAll(t *testing.T) {
 			expectedError: nil,
 		},
 		{
-			name: "invalid metric type",
+			name: "labeled counter series",
 			metrics: []models.Metrics{
-				{ID: "unknown", MType: "unknown"},
+				{ID: "c1", MType: models.Counter, Labels: map[string]string{"host": "a"}, Delta: intPtr(10)},
+				{ID: "c1", MType: models.Counter, Labels: map[string]string{"host": "a"}, Delta: intPtr(5)},
+			},
+			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+				repo.EXPECT().
+					AddAll(mock.Anything, []models.Metrics{
+						{ID: "c1", MType: models.Counter, Labels: map[string]string{"host": "a"}, Delta: intPtr(15)},
+					}).
+					Return(nil)
+			},
+			mockGaugeFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+			},
+			expectedError: nil,
+		},
+		{
+			name: "gauge increments are summed",
+			metrics: []models.Metrics{
+				{ID: "g1", MType: models.Gauge, Increment: floatPtr(5)},
+				{ID: "g1", MType: models.Gauge, Increment: floatPtr(-2)},
+			},
+			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+			},
+			mockGaugeFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+				repo.EXPECT().
+					IncrementAll(mock.Anything, []models.Metrics{
+						{ID: "g1", MType: models.Gauge, Increment: floatPtr(3)},
+					}).
+					Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "gauge increments applied in order with values",
+			metrics: []models.Metrics{
+				{ID: "g1", MType: models.Gauge, Increment: floatPtr(100)},
+				{ID: "g1", MType: models.Gauge, Value: floatPtr(10)},
+				{ID: "g1", MType: models.Gauge, Increment: floatPtr(5)},
+				{ID: "g2", MType: models.Gauge, Value: floatPtr(1)},
+				{ID: "g2", MType: models.Gauge, Increment: floatPtr(-1)},
+				{ID: "g2", MType: models.Gauge, Value: floatPtr(7)},
+			},
+			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+			},
+			mockGaugeFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+				repo.EXPECT().
+					ResetAll(mock.Anything, mock.Anything).
+					RunAndReturn(func(ctx context.Context, gauges []models.Metrics) error {
+						values := make(map[string]float64, len(gauges))
+						for _, gauge := range gauges {
+							values[gauge.ID] = *gauge.Value
+						}
+						assert.Equal(t, map[string]float64{"g1": 15, "g2": 7}, values)
+						return nil
+					})
+			},
+			expectedError: nil,
+		},
+		{
+			name: "gauge with value and increment",
+			metrics: []models.Metrics{
+				{ID: "g1", MType: models.Gauge, Value: floatPtr(1), Increment: floatPtr(1)},
 			},
 			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {},
 			mockGaugeFn:   func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {},
-			expectedError: api.BadRequest("invalid metric type: unknown"),
+			expectedError: api.BadRequest("invalid gauge g1: value and increment are mutually exclusive"),
 		},
 		{
-			name: "counter repository error",
+			name: "invalid label name",
 			metrics: []models.Metrics{
-				{ID: "c1", MType: models.Counter, Delta: intPtr(10)},
+				{ID: "c1", MType: models.Counter, Labels: map[string]string{"": "a"}, Delta: intPtr(10)},
+			},
+			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {},
+			mockGaugeFn:   func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {},
+			expectedError: api.BadRequest("invalid label name"),
+		},
+		{
+			name: "histogram metrics",
+			metrics: []models.Metrics{
+				models.ObserveHistogram("h1", []float64{1, 2}, 0.5),
+				models.ObserveHistogram("h1", []float64{1, 2}, 1.5),
 			},
 			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
-				repo.EXPECT().AddAll(mock.Anything, mock.Anything).Return(errors.New("counter error"))
 			},
 			mockGaugeFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+				repo.EXPECT().
+					MergeAll(mock.Anything, []models.Metrics{{
+						ID:      "h1",
+						MType:   models.Histogram,
+						Bounds:  []float64{1, 2},
+						Buckets: []int64{1, 1, 0},
+						Sum:     floatPtr(2),
+						Count:   intPtr(2),
+					}}).
+					Return(nil)
 			},
-			expectedError: api.Internal("save metrics error", fmt.Errorf("counters: %v, gauges: %v", errors.New("counter error"), nil)),
+			expectedError: nil,
 		},
 		{
-			name: "gauge repository error",
+			name: "summary metrics",
 			metrics: []models.Metrics{
-				{ID: "g1", MType: models.Gauge, Value: floatPtr(3.14)},
+				models.ObserveSummary("s1", 0.5),
+				models.ObserveSummary("s1", 1.5),
 			},
 			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
 			},
 			mockGaugeFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
-				repo.EXPECT().ResetAll(mock.Anything, mock.Anything).Return(errors.New("gauge error"))
+				merged, err := models.MergeSummary(metrics[0], metrics[1])
+				require.NoError(t, err)
+				require.Equal(t, int64(2), *merged.Count)
+				repo.EXPECT().
+					MergeAll(mock.Anything, []models.Metrics{merged}).
+					Return(nil)
 			},
-			expectedError: api.Internal("save metrics error", fmt.Errorf("counters: %v, gauges: %v", nil, errors.New("gauge error"))),
+			expectedError: nil,
 		},
 		{
-			name: "both repositories error",
+			name: "set members and sketches",
 			metrics: []models.Metrics{
-				{ID: "c1", MType: models.Counter, Delta: intPtr(10)},
-				{ID: "g1", MType: models.Gauge, Value: floatPtr(3.14)},
+				{ID: "u1", MType: models.Set, Members: []string{"alice", "bob"}},
+				models.ObserveSet("u1", "carol"),
+				{ID: "u1", MType: models.Set, Members: []string{"alice"}},
 			},
 			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
-				repo.EXPECT().AddAll(mock.Anything, mock.Anything).Return(errors.New("counter error"))
 			},
 			mockGaugeFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
-				repo.EXPECT().ResetAll(mock.Anything, mock.Anything).Return(errors.New("gauge error"))
+				repo.EXPECT().
+					MergeAll(mock.Anything, []models.Metrics{models.ObserveSet("u1", "alice", "bob", "carol")}).
+					Return(nil)
 			},
-			expectedError: api.Internal("save metrics error", fmt.Errorf("counters: %v, gauges: %v", errors.New("counter error"), errors.New("gauge error"))),
+			expectedError: nil,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mockRepo := repository.NewMockMetricsRepository(t)
-			mockAuditor := audit.NewMockAuditor(t)
-
-			tt.mockCounterFn(mockRepo, tt.metrics)
-			tt.mockGaugeFn(mockRepo, tt.metrics)
-
-			svc, err := NewMetricsService(mockRepo, mockAuditor)
-			assert.NoError(t, err)
-
-			result := svc.SaveAll(t.Context(), tt.metrics)
-
-			if tt.expectedError == nil {
-				assert.Nil(t, result)
-			} else {
-				assert.NotNil(t, result)
-				assert.Equal(t, tt.expectedError.Code, result.Code)
-				assert.Contains(t, result.Message, tt.expectedError.Message)
-			}
-		})
+		{
+			name: "invalid set sketch",
+			metrics: []models.Metrics{
+				{ID: "u1", MType: models.Set, Sketch: []byte("broken")},
+			},
+			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {},
+			mockGaugeFn:   func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {},
+			expectedError: api.BadRequest("invalid set u1"),
+		},
+		{
+			name: "summary accuracy mismatch",
+			metrics: []models.Metrics{
+				models.ObserveSummary("s1", 0.5),
+				{ID: "s1", MType: models.Summary, Sketch: encodeSketch(t, 0.05, 1.5)},
+			},
+			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {},
+			mockGaugeFn:   func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {},
+			expectedError: api.BadRequest("summary s1: sketch relative accuracy mismatch"),
+		},
+		{
+			name: "histogram bounds mismatch",
+			metrics: []models.Metrics{
+				models.ObserveHistogram("h1", []float64{1, 2}, 0.5),
+				models.ObserveHistogram("h1", []float64{5}, 1.5),
+			},
+			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {},
+			mockGaugeFn:   func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {},
+			expectedError: api.BadRequest("histogram h1 bounds mismatch"),
+		},
+		{
+			name: "id too long",
+			metrics: []models.Metrics{
+				{ID: strings.Repeat("a", models.MaxIDLength+1), MType: models.Counter, Delta: intPtr(5)},
+			},
+			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {},
+			mockGaugeFn:   func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {},
+			expectedError: api.BadRequest("metric id is longer than 64 characters"),
+		},
+		{
+			name: "series reported with different types",
+			metrics: []models.Metrics{
+				models.ObserveHistogram("x", []float64{1}, 0.5),
+				models.ObserveSummary("x", 0.5),
+			},
+			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {},
+			mockGaugeFn:   func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {},
+			expectedError: api.BadRequest("metric x has type histogram"),
+		},
+		{
+			name: "invalid metric type",
+			metrics: []models.Metrics{
+				{ID: "unknown", MType: "unknown"},
+			},
+			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {},
+			mockGaugeFn:   func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {},
+			expectedError: api.BadRequest("invalid metric type: unknown"),
+		},
+		{
+			name: "counter repository error",
+			metrics: []models.Metrics{
+				{ID: "c1", MType: models.Counter, Delta: intPtr(10)},
+			},
+			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+				repo.EXPECT().AddAll(mock.Anything, mock.Anything).Return(errors.New("counter error"))
+			},
+			mockGaugeFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+			},
+			expectedError: api.Internal("save metrics error", fmt.Errorf("counters: %v, gauges: %v", errors.New("counter error"), nil)),
+		},
+		{
+			name: "gauge repository error",
+			metrics: []models.Metrics{
+				{ID: "g1", MType: models.Gauge, Value: floatPtr(3.14)},
+			},
+			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+			},
+			mockGaugeFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+				repo.EXPECT().ResetAll(mock.Anything, mock.Anything).Return(errors.New("gauge error"))
+			},
+			expectedError: api.Internal("save metrics error", fmt.Errorf("counters: %v, gauges: %v", nil, errors.New("gauge error"))),
+		},
+		{
+			name: "both repositories error",
+			metrics: []models.Metrics{
+				{ID: "c1", MType: models.Counter, Delta: intPtr(10)},
+				{ID: "g1", MType: models.Gauge, Value: floatPtr(3.14)},
+			},
+			mockCounterFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+				repo.EXPECT().AddAll(mock.Anything, mock.Anything).Return(errors.New("counter error"))
+			},
+			mockGaugeFn: func(repo *repository.MockMetricsRepository, metrics []models.Metrics) {
+				repo.EXPECT().ResetAll(mock.Anything, mock.Anything).Return(errors.New("gauge error"))
+			},
+			expectedError: api.Internal("save metrics error", fmt.Errorf("counters: %v, gauges: %v", errors.New("counter error"), errors.New("gauge error"))),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockMetricsRepository(t)
+			mockAuditor := audit.NewMockAuditor(t)
+
+			tt.mockCounterFn(mockRepo, tt.metrics)
+			tt.mockGaugeFn(mockRepo, tt.metrics)
+
+			svc, err := NewMetricsService(mockRepo, repository.NewMockMetaRepository(t), mockAuditor)
+			assert.NoError(t, err)
+
+			result := svc.SaveAll(t.Context(), tt.metrics)
+
+			if tt.expectedError == nil {
+				assert.Nil(t, result)
+			} else {
+				assert.NotNil(t, result)
+				assert.Equal(t, tt.expectedError.Code, result.Code)
+				assert.Contains(t, result.Message, tt.expectedError.Message)
+			}
+		})
 	}
 }
 
 func floatPtr(value float64) *float64 {
 	return &value
 }
+
+func TestMetricsService_GetRange(t *testing.T) {
+	from := time.UnixMilli(0)
+	to := time.UnixMilli(60_000)
+
+	tests := []struct {
+		name          string
+		query         models.RangeQuery
+		setupMock     func(m *repository.MockMetricsRepository)
+		expectPoints  []models.Point
+		expectedError *api.APIError
+	}{
+		{
+			name:  "raw points",
+			query: models.RangeQuery{ID: "g1", MType: models.Gauge, From: from, To: to},
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().GetRange(mock.Anything, mock.Anything).Return([]models.Point{
+					{Timestamp: 1000, Value: floatPtr(1)},
+					{Timestamp: 2000, Value: floatPtr(2)},
+				}, nil)
+			},
+			expectPoints: []models.Point{
+				{Timestamp: 1000, Value: floatPtr(1)},
+				{Timestamp: 2000, Value: floatPtr(2)},
+			},
+		},
+		{
+			name:  "counter deltas summed per step",
+			query: models.RangeQuery{ID: "c1", MType: models.Counter, From: from, To: to, Step: 10 * time.Second},
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().GetRange(mock.Anything, mock.Anything).Return([]models.Point{
+					{Timestamp: 1000, Delta: intPtr(1)},
+					{Timestamp: 9000, Delta: intPtr(2)},
+					{Timestamp: 25000, Delta: intPtr(4)},
+				}, nil)
+			},
+			expectPoints: []models.Point{
+				{Timestamp: 0, Delta: intPtr(3)},
+				{Timestamp: 20000, Delta: intPtr(4)},
+			},
+		},
+		{
+			name:  "gauge keeps last value per step",
+			query: models.RangeQuery{ID: "g1", MType: models.Gauge, From: from, To: to, Step: 10 * time.Second},
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().GetRange(mock.Anything, mock.Anything).Return([]models.Point{
+					{Timestamp: 1000, Value: floatPtr(1)},
+					{Timestamp: 9000, Value: floatPtr(2)},
+					{Timestamp: 11000, Value: floatPtr(3)},
+				}, nil)
+			},
+			expectPoints: []models.Point{
+				{Timestamp: 0, Value: floatPtr(2)},
+				{Timestamp: 10000, Value: floatPtr(3)},
+			},
+		},
+		{
+			name:          "missing id",
+			query:         models.RangeQuery{MType: models.Gauge, From: from, To: to},
+			setupMock:     func(m *repository.MockMetricsRepository) {},
+			expectedError: api.BadRequest("metric id is required"),
+		},
+		{
+			name:          "histogram has no history",
+			query:         models.RangeQuery{ID: "h1", MType: models.Histogram, From: from, To: to},
+			setupMock:     func(m *repository.MockMetricsRepository) {},
+			expectedError: api.BadRequest("metric type histogram has no history"),
+		},
+		{
+			name:          "inverted range",
+			query:         models.RangeQuery{ID: "g1", MType: models.Gauge, From: to, To: from},
+			setupMock:     func(m *repository.MockMetricsRepository) {},
+			expectedError: api.BadRequest("range end is before range start"),
+		},
+		{
+			name:          "negative step",
+			query:         models.RangeQuery{ID: "g1", MType: models.Gauge, From: from, To: to, Step: -time.Second},
+			setupMock:     func(m *repository.MockMetricsRepository) {},
+			expectedError: api.BadRequest("step can't be negative"),
+		},
+		{
+			name:  "repository error",
+			query: models.RangeQuery{ID: "g1", MType: models.Gauge, From: from, To: to},
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().GetRange(mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+			},
+			expectedError: api.Internal("Get metric range error", errors.New("db error")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockMetricsRepository(t)
+			mockAuditor := audit.NewMockAuditor(t)
+			tt.setupMock(mockRepo)
+
+			svc, err := NewMetricsService(mockRepo, repository.NewMockMetaRepository(t), mockAuditor)
+			assert.NoError(t, err)
+
+			points, apiErr := svc.GetRange(t.Context(), tt.query)
+
+			if tt.expectedError == nil {
+				assert.Nil(t, apiErr)
+				assert.Equal(t, tt.expectPoints, points)
+			} else {
+				require.NotNil(t, apiErr)
+				assert.Equal(t, tt.expectedError.Code, apiErr.Code)
+				assert.Contains(t, apiErr.Message, tt.expectedError.Message)
+			}
+		})
+	}
+}
+
+// encodeSketch serializes a sketch with the given accuracy holding values.
+func encodeSketch(t *testing.T, accuracy float64, values ...float64) []byte {
+	t.Helper()
+
+	s, err := sketch.NewDDSketch(accuracy)
+	require.NoError(t, err)
+	for _, value := range values {
+		s.Add(value)
+	}
+
+	encoded, err := s.MarshalBinary()
+	require.NoError(t, err)
+	return encoded
+}
+
+// auditContext returns a request context carrying audit data and the tenant.
+func auditContext(t *testing.T, tenant string) context.Context {
+	var ctx context.Context
+	middleware.AuditContext(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/", nil))
+
+	return middleware.WithTenant(ctx, tenant)
+}
+
+func TestMetricsService_Delete(t *testing.T) {
+	deleted := []models.Metrics{
+		{ID: "g1", MType: models.Gauge, Value: floatPtr(1)},
+		{ID: "g1", MType: models.Gauge, Labels: map[string]string{"host": "a"}, Value: floatPtr(2)},
+	}
+
+	tests := []struct {
+		name         string
+		setupMock    func(repo *repository.MockMetricsRepository, auditor *audit.MockAuditor, audited chan struct{})
+		expectStatus int
+	}{
+		{
+			name: "deleted and audited",
+			setupMock: func(repo *repository.MockMetricsRepository, auditor *audit.MockAuditor, audited chan struct{}) {
+				repo.EXPECT().
+					Delete(mock.Anything, models.DeleteQuery{ID: "g1", MType: models.Gauge}).
+					Return(deleted, nil)
+				auditor.EXPECT().
+					AuditDelete(deleted, mock.Anything, mock.Anything, "team-a").
+					Run(func([]models.Metrics, int64, string, string) { close(audited) })
+			},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name: "not found",
+			setupMock: func(repo *repository.MockMetricsRepository, auditor *audit.MockAuditor, audited chan struct{}) {
+				repo.EXPECT().Delete(mock.Anything, mock.Anything).Return([]models.Metrics{}, nil)
+			},
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			name: "repository error",
+			setupMock: func(repo *repository.MockMetricsRepository, auditor *audit.MockAuditor, audited chan struct{}) {
+				repo.EXPECT().Delete(mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+			},
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockMetricsRepository(t)
+			mockAuditor := audit.NewMockAuditor(t)
+			audited := make(chan struct{})
+			tt.setupMock(mockRepo, mockAuditor, audited)
+
+			svc, err := NewMetricsService(mockRepo, repository.NewMockMetaRepository(t), mockAuditor)
+			require.NoError(t, err)
+
+			apiErr := svc.Delete(auditContext(t, "team-a"), "g1", models.Gauge)
+
+			if tt.expectStatus == http.StatusOK {
+				assert.Nil(t, apiErr)
+				select {
+				case <-audited:
+				case <-time.After(time.Second):
+					t.Fatal("deletion was not audited")
+				}
+			} else {
+				require.NotNil(t, apiErr)
+				assert.Equal(t, tt.expectStatus, apiErr.Code)
+			}
+		})
+	}
+}
+
+func TestMetricsService_DeleteAll(t *testing.T) {
+	tests := []struct {
+		name         string
+		pattern      string
+		metricType   string
+		setupMock    func(repo *repository.MockMetricsRepository)
+		expected     models.DeleteResult
+		expectStatus int
+	}{
+		{
+			name:    "deleted series keys sorted",
+			pattern: "cpu_*",
+			setupMock: func(repo *repository.MockMetricsRepository) {
+				repo.EXPECT().
+					Delete(mock.Anything, models.DeleteQuery{Pattern: "cpu_*"}).
+					Return([]models.Metrics{
+						{ID: "cpu_user", MType: models.Counter, Labels: map[string]string{"host": "a"}},
+						{ID: "cpu_system", MType: models.Counter},
+					}, nil)
+			},
+			expected:     models.DeleteResult{Deleted: []string{"cpu_system", `cpu_user{host="a"}`}},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:       "nothing matched",
+			pattern:    "disk_*",
+			metricType: models.Gauge,
+			setupMock: func(repo *repository.MockMetricsRepository) {
+				repo.EXPECT().
+					Delete(mock.Anything, models.DeleteQuery{Pattern: "disk_*", MType: models.Gauge}).
+					Return([]models.Metrics{}, nil)
+			},
+			expected:     models.DeleteResult{Deleted: []string{}},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "missing pattern",
+			setupMock:    func(repo *repository.MockMetricsRepository) {},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "malformed pattern",
+			pattern:      "cpu_[",
+			setupMock:    func(repo *repository.MockMetricsRepository) {},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "unknown type",
+			pattern:      "cpu_*",
+			metricType:   "unknown",
+			setupMock:    func(repo *repository.MockMetricsRepository) {},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "repository error",
+			pattern: "cpu_*",
+			setupMock: func(repo *repository.MockMetricsRepository) {
+				repo.EXPECT().Delete(mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+			},
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockMetricsRepository(t)
+			tt.setupMock(mockRepo)
+
+			svc, err := NewMetricsService(mockRepo, repository.NewMockMetaRepository(t), audit.NewMockAuditor(t))
+			require.NoError(t, err)
+
+			result, apiErr := svc.DeleteAll(t.Context(), tt.pattern, tt.metricType)
+
+			if tt.expectStatus == http.StatusOK {
+				assert.Nil(t, apiErr)
+				assert.Equal(t, tt.expected, result)
+			} else {
+				require.NotNil(t, apiErr)
+				assert.Equal(t, tt.expectStatus, apiErr.Code)
+			}
+		})
+	}
+}
+
+func TestMetricsService_List(t *testing.T) {
+	metrics := []models.Metrics{
+		{ID: "cpu_system", MType: models.Counter, Delta: intPtr(1), Tenant: middleware.DefaultTenant},
+		{ID: "cpu_temp", MType: models.Gauge, Value: floatPtr(40), Tenant: middleware.DefaultTenant},
+		{ID: "cpu_user", MType: models.Counter, Delta: intPtr(2), Tenant: middleware.DefaultTenant},
+	}
+
+	tests := []struct {
+		name         string
+		query        models.ListQuery
+		repoLimit    int
+		mockReturn   []models.Metrics
+		mockErr      error
+		metas        map[string]models.Meta
+		expected     models.MetricsPage
+		expectStatus int
+	}{
+		{
+			name:       "last page with units",
+			query:      models.ListQuery{Prefix: "cpu"},
+			repoLimit:  DefaultListLimit + 1,
+			mockReturn: metrics,
+			metas:      map[string]models.Meta{"cpu_temp": {ID: "cpu_temp", Unit: "celsius"}},
+			expected: models.MetricsPage{
+				Metrics: []models.Metrics{
+					{ID: "cpu_system", MType: models.Counter, Delta: intPtr(1)},
+					{ID: "cpu_temp", MType: models.Gauge, Value: floatPtr(40), Unit: "celsius"},
+					{ID: "cpu_user", MType: models.Counter, Delta: intPtr(2)},
+				},
+			},
+		},
+		{
+			name:       "page with next cursor",
+			query:      models.ListQuery{Limit: 2},
+			repoLimit:  3,
+			mockReturn: metrics,
+			metas:      map[string]models.Meta{},
+			expected: models.MetricsPage{
+				Metrics: []models.Metrics{
+					{ID: "cpu_system", MType: models.Counter, Delta: intPtr(1)},
+					{ID: "cpu_temp", MType: models.Gauge, Value: floatPtr(40)},
+				},
+				NextCursor: models.EncodeCursor(models.SeriesRef{ID: "cpu_temp", MType: models.Gauge}),
+			},
+		},
+		{
+			name:       "empty page",
+			query:      models.ListQuery{MType: models.Histogram},
+			repoLimit:  DefaultListLimit + 1,
+			mockReturn: []models.Metrics{},
+			expected:   models.MetricsPage{Metrics: []models.Metrics{}},
+		},
+		{
+			name:         "invalid type",
+			query:        models.ListQuery{MType: "unknown"},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid regex",
+			query:        models.ListQuery{Regex: "cpu_("},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid sort field",
+			query:        models.ListQuery{SortBy: "value"},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "limit too large",
+			query:        models.ListQuery{Limit: MaxListLimit + 1},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "repository returns error",
+			query:        models.ListQuery{},
+			repoLimit:    DefaultListLimit + 1,
+			mockErr:      errors.New("db error"),
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockMetricsRepository(t)
+			mockMetaRepo := repository.NewMockMetaRepository(t)
+			if tt.repoLimit > 0 {
+				expectedQuery := tt.query
+				expectedQuery.Limit = tt.repoLimit
+				mockRepo.EXPECT().List(mock.Anything, expectedQuery).Return(tt.mockReturn, tt.mockErr)
+			}
+			if tt.metas != nil {
+				mockMetaRepo.EXPECT().GetAll(mock.Anything).Return(tt.metas, nil)
+			}
+
+			svc, err := NewMetricsService(mockRepo, mockMetaRepo, audit.NewMockAuditor(t))
+			require.NoError(t, err)
+
+			page, apiErr := svc.List(t.Context(), tt.query)
+
+			if tt.expectStatus != 0 {
+				require.NotNil(t, apiErr)
+				assert.Equal(t, tt.expectStatus, apiErr.Code)
+			} else {
+				assert.Nil(t, apiErr)
+				assert.Equal(t, tt.expected, page)
+			}
+		})
+	}
+}
+
+func TestMetricsService_GetMany(t *testing.T) {
+	labels := map[string]string{"room": "lab"}
+
+	tests := []struct {
+		name         string
+		refs         []models.SeriesRef
+		mockReturn   []models.Metrics
+		mockErr      error
+		expectRepo   bool
+		expected     []models.Metrics
+		expectStatus int
+	}{
+		{
+			name: "request order with missing series omitted",
+			refs: []models.SeriesRef{
+				{ID: "temp", MType: models.Gauge, Labels: labels},
+				{ID: "missing", MType: models.Counter},
+				{ID: "requests", MType: models.Counter},
+			},
+			mockReturn: []models.Metrics{
+				{ID: "requests", MType: models.Counter, Delta: intPtr(5)},
+				{ID: "temp", MType: models.Gauge, Labels: labels, Value: floatPtr(21.5)},
+			},
+			expectRepo: true,
+			expected: []models.Metrics{
+				{ID: "temp", MType: models.Gauge, Labels: labels, Value: floatPtr(21.5), Unit: "celsius"},
+				{ID: "requests", MType: models.Counter, Delta: intPtr(5)},
+			},
+		},
+		{
+			name:     "no references",
+			refs:     []models.SeriesRef{},
+			expected: []models.Metrics{},
+		},
+		{
+			name:         "missing id",
+			refs:         []models.SeriesRef{{MType: models.Gauge}},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid type",
+			refs:         []models.SeriesRef{{ID: "temp", MType: "unknown"}},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid labels",
+			refs:         []models.SeriesRef{{ID: "temp", MType: models.Gauge, Labels: map[string]string{"1room": "lab"}}},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "too many references",
+			refs:         make([]models.SeriesRef, MaxListLimit+1),
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "repository returns error",
+			refs:         []models.SeriesRef{{ID: "temp", MType: models.Gauge}},
+			mockErr:      errors.New("db error"),
+			expectRepo:   true,
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockMetricsRepository(t)
+			mockMetaRepo := repository.NewMockMetaRepository(t)
+			if tt.expectRepo {
+				mockRepo.EXPECT().GetMany(mock.Anything, tt.refs).Return(tt.mockReturn, tt.mockErr)
+			}
+			if len(tt.mockReturn) > 0 {
+				mockMetaRepo.EXPECT().GetAll(mock.Anything).Return(map[string]models.Meta{
+					"temp": {ID: "temp", Unit: "celsius"},
+				}, nil)
+			}
+
+			svc, err := NewMetricsService(mockRepo, mockMetaRepo, audit.NewMockAuditor(t))
+			require.NoError(t, err)
+
+			result, apiErr := svc.GetMany(t.Context(), tt.refs)
+
+			if tt.expectStatus != 0 {
+				require.NotNil(t, apiErr)
+				assert.Equal(t, tt.expectStatus, apiErr.Code)
+			} else {
+				assert.Nil(t, apiErr)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMetricsService_publish(t *testing.T) {
+	saved := []models.Metrics{
+		{ID: "requests", MType: models.Counter, Delta: intPtr(5)},
+		{ID: "requests", MType: models.Counter, Delta: intPtr(3)},
+		{ID: "temp", MType: models.Gauge, Value: floatPtr(21.5)},
+	}
+	refs := []models.SeriesRef{
+		{ID: "requests", MType: models.Counter},
+		{ID: "temp", MType: models.Gauge},
+	}
+	current := []models.Metrics{
+		{ID: "requests", MType: models.Counter, Delta: intPtr(18), Tenant: "team-a"},
+		{ID: "temp", MType: models.Gauge, Value: floatPtr(21.5), Tenant: "team-a"},
+	}
+
+	tests := []struct {
+		name          string
+		withPublisher bool
+		active        bool
+		mockErr       error
+		expectPublish []models.Metrics
+	}{
+		{
+			name: "no publisher",
+		},
+		{
+			name:          "inactive publisher",
+			withPublisher: true,
+		},
+		{
+			name:          "current states published once",
+			withPublisher: true,
+			active:        true,
+			expectPublish: []models.Metrics{
+				{ID: "requests", MType: models.Counter, Delta: intPtr(18)},
+				{ID: "temp", MType: models.Gauge, Value: floatPtr(21.5), Unit: "celsius"},
+			},
+		},
+		{
+			name:          "repository returns error",
+			withPublisher: true,
+			active:        true,
+			mockErr:       errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockMetricsRepository(t)
+			mockMetaRepo := repository.NewMockMetaRepository(t)
+			mockPublisher := NewMockPublisher(t)
+
+			options := []Option{}
+			if tt.withPublisher {
+				options = append(options, WithPublisher(mockPublisher))
+				mockPublisher.EXPECT().Active().Return(tt.active)
+			}
+			if tt.active {
+				if tt.mockErr != nil {
+					mockRepo.EXPECT().GetMany(mock.Anything, refs).Return(nil, tt.mockErr)
+				} else {
+					mockRepo.EXPECT().GetMany(mock.Anything, refs).Return(current, nil)
+				}
+			}
+			if tt.expectPublish != nil {
+				mockMetaRepo.EXPECT().GetAll(mock.Anything).Return(map[string]models.Meta{
+					"temp": {ID: "temp", Unit: "celsius"},
+				}, nil)
+				mockPublisher.EXPECT().Publish("team-a", tt.expectPublish).Once()
+			}
+
+			svc, err := NewMetricsService(mockRepo, mockMetaRepo, audit.NewMockAuditor(t), options...)
+			require.NoError(t, err)
+
+			svc.(*metricsService).publish(middleware.WithTenant(t.Context(), "team-a"), saved)
+		})
+	}
+}
+
+func TestMetricsService_SaveAll_publish(t *testing.T) {
+	metrics := []models.Metrics{
+		{ID: "requests", MType: models.Counter, Delta: intPtr(5)},
+		{ID: "temp", MType: models.Gauge, Value: floatPtr(21.5)},
+	}
+	refs := []models.SeriesRef{
+		{ID: "requests", MType: models.Counter},
+		{ID: "temp", MType: models.Gauge},
+	}
+
+	mockRepo := repository.NewMockMetricsRepository(t)
+	mockRepo.EXPECT().AddAll(mock.Anything, mock.Anything).Return(nil)
+	mockRepo.EXPECT().ResetAll(mock.Anything, mock.Anything).Return(nil)
+	mockRepo.EXPECT().GetMany(mock.Anything, refs).Return(metrics, nil)
+
+	mockMetaRepo := repository.NewMockMetaRepository(t)
+	mockMetaRepo.EXPECT().GetAll(mock.Anything).Return(map[string]models.Meta{}, nil)
+
+	mockPublisher := NewMockPublisher(t)
+	mockPublisher.EXPECT().Active().Return(true)
+	mockPublisher.EXPECT().Publish(middleware.DefaultTenant, metrics).Once()
+
+	svc, err := NewMetricsService(mockRepo, mockMetaRepo, audit.NewMockAuditor(t), WithPublisher(mockPublisher))
+	require.NoError(t, err)
+
+	assert.Nil(t, svc.SaveAll(t.Context(), metrics))
+}
+
+func TestMetricsService_Import(t *testing.T) {
+	tests := []struct {
+		name           string
+		records        []models.ImportRecord
+		options        models.ImportOptions
+		mockFn         func(repo *repository.MockMetricsRepository)
+		expectedReport models.ImportReport
+		expectedError  *api.APIError
+	}{
+		{
+			name: "valid and invalid records",
+			records: []models.ImportRecord{
+				{Line: 2, Metric: models.Metrics{ID: "requests", MType: models.Counter, Delta: intPtr(5)}},
+				{Line: 3, Metric: models.Metrics{ID: "requests", MType: models.Counter}},
+				{Line: 4, Metric: models.Metrics{ID: "temp", MType: models.Gauge, Value: floatPtr(21.5)}},
+				{Line: 5, Metric: models.Metrics{MType: models.Gauge, Value: floatPtr(1)}},
+				{Line: 6, Metric: models.Metrics{ID: "temp", MType: "unknown"}},
+				{Line: 7, Metric: models.Metrics{ID: "temp", MType: models.Gauge, Labels: map[string]string{"bad-name": "x"}, Value: floatPtr(1)}},
+			},
+			mockFn: func(repo *repository.MockMetricsRepository) {
+				repo.EXPECT().GetTypes(mock.Anything, mock.Anything).Return(map[string]string{}, nil)
+				repo.EXPECT().AddAll(mock.Anything, []models.Metrics{
+					{ID: "requests", MType: models.Counter, Delta: intPtr(5)},
+				}).Return(nil)
+				repo.EXPECT().ResetAll(mock.Anything, []models.Metrics{
+					{ID: "temp", MType: models.Gauge, Value: floatPtr(21.5)},
+				}).Return(nil)
+			},
+			expectedReport: models.ImportReport{
+				Total: 6, Imported: 2, Failed: 4,
+				Errors: []models.ImportError{
+					{Line: 3, Error: "counter delta is required"},
+					{Line: 5, Error: "metric id is required"},
+					{Line: 6, Error: "invalid metric type: unknown"},
+					{Line: 7, Error: `invalid label name: "bad-name"`},
+				},
+			},
+		},
+		{
+			name: "histogram bounds mismatch",
+			records: []models.ImportRecord{
+				{Line: 1, Metric: models.ObserveHistogram("latency", []float64{1, 2}, 0.5)},
+				{Line: 2, Metric: models.ObserveHistogram("latency", []float64{5}, 1.5)},
+			},
+			mockFn: func(repo *repository.MockMetricsRepository) {
+				repo.EXPECT().GetTypes(mock.Anything, mock.Anything).Return(map[string]string{}, nil)
+				repo.EXPECT().MergeAll(mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedReport: models.ImportReport{
+				Total: 2, Imported: 1, Failed: 1,
+				Errors: []models.ImportError{{Line: 2, Error: "histogram latency bounds mismatch: [1 2] != [5]"}},
+			},
+		},
+		{
+			name: "dry run",
+			records: []models.ImportRecord{
+				{Line: 1, Metric: models.Metrics{ID: "requests", MType: models.Counter, Delta: intPtr(5)}},
+			},
+			options: models.ImportOptions{DryRun: true, Counters: models.CounterReplace},
+			mockFn: func(repo *repository.MockMetricsRepository) {
+				repo.EXPECT().GetTypes(mock.Anything, mock.Anything).Return(map[string]string{}, nil)
+			},
+			expectedReport: models.ImportReport{Total: 1, Imported: 1, DryRun: true},
+		},
+		{
+			name: "replace counters",
+			records: []models.ImportRecord{
+				{Line: 1, Metric: models.Metrics{ID: "requests", MType: models.Counter, Delta: intPtr(5)}},
+				{Line: 2, Metric: models.Metrics{ID: "requests", MType: models.Counter, Delta: intPtr(30)}},
+				{Line: 3, Metric: models.Metrics{ID: "errors", MType: models.Counter, Delta: intPtr(2)}},
+			},
+			options: models.ImportOptions{Counters: models.CounterReplace},
+			mockFn: func(repo *repository.MockMetricsRepository) {
+				repo.EXPECT().GetTypes(mock.Anything, mock.Anything).Return(map[string]string{}, nil)
+				repo.EXPECT().GetMany(mock.Anything, []models.SeriesRef{
+					{ID: "requests", MType: models.Counter},
+					{ID: "errors", MType: models.Counter},
+				}).Return([]models.Metrics{{ID: "requests", MType: models.Counter, Delta: intPtr(12)}}, nil)
+				repo.EXPECT().AddAll(mock.Anything, mock.MatchedBy(func(counters []models.Metrics) bool {
+					deltas := make(map[string]int64)
+					for _, counter := range counters {
+						deltas[counter.ID] = *counter.Delta
+					}
+					return len(counters) == 2 && deltas["requests"] == 18 && deltas["errors"] == 2
+				})).Return(nil)
+			},
+			expectedReport: models.ImportReport{Total: 3, Imported: 3},
+		},
+		{
+			name: "stored with another type",
+			records: []models.ImportRecord{
+				{Line: 1, Metric: models.Metrics{ID: "latency", MType: models.Counter, Delta: intPtr(5)}},
+				{Line: 2, Metric: models.Metrics{ID: "temp", MType: models.Gauge, Value: floatPtr(21.5)}},
+			},
+			mockFn: func(repo *repository.MockMetricsRepository) {
+				repo.EXPECT().GetTypes(mock.Anything, []models.SeriesRef{
+					{ID: "latency", MType: models.Counter},
+					{ID: "temp", MType: models.Gauge},
+				}).Return(map[string]string{"latency": models.Histogram, "temp": models.Gauge}, nil)
+				repo.EXPECT().ResetAll(mock.Anything, []models.Metrics{
+					{ID: "temp", MType: models.Gauge, Value: floatPtr(21.5)},
+				}).Return(nil)
+			},
+			expectedReport: models.ImportReport{
+				Total: 2, Imported: 1, Failed: 1,
+				Errors: []models.ImportError{{Line: 1, Error: "metric latency has type histogram"}},
+			},
+		},
+		{
+			name: "types lookup error",
+			records: []models.ImportRecord{
+				{Line: 1, Metric: models.Metrics{ID: "requests", MType: models.Counter, Delta: intPtr(5)}},
+			},
+			mockFn: func(repo *repository.MockMetricsRepository) {
+				repo.EXPECT().GetTypes(mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+			},
+			expectedError: api.Internal("Get metric types error", errors.New("db error")),
+		},
+		{
+			name:          "invalid counters mode",
+			options:       models.ImportOptions{Counters: "set"},
+			mockFn:        func(repo *repository.MockMetricsRepository) {},
+			expectedError: api.BadRequest(`invalid counters mode "set", expected add or replace`),
+		},
+		{
+			name: "repository error",
+			records: []models.ImportRecord{
+				{Line: 1, Metric: models.Metrics{ID: "requests", MType: models.Counter, Delta: intPtr(5)}},
+			},
+			options: models.ImportOptions{Counters: models.CounterReplace},
+			mockFn: func(repo *repository.MockMetricsRepository) {
+				repo.EXPECT().GetTypes(mock.Anything, mock.Anything).Return(map[string]string{}, nil)
+				repo.EXPECT().GetMany(mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+			},
+			expectedError: api.Internal("Get metrics error", errors.New("db error")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockMetricsRepository(t)
+			tt.mockFn(mockRepo)
+
+			svc, err := NewMetricsService(mockRepo, repository.NewMockMetaRepository(t), audit.NewMockAuditor(t))
+			require.NoError(t, err)
+
+			report, importErr := svc.Import(t.Context(), tt.records, tt.options)
+
+			if tt.expectedError != nil {
+				require.NotNil(t, importErr)
+				assert.Equal(t, tt.expectedError.Code, importErr.Code)
+				assert.Equal(t, tt.expectedError.Message, importErr.Message)
+				return
+			}
+
+			require.Nil(t, importErr)
+			assert.Equal(t, tt.expectedReport, report)
+		})
+	}
+}
+
+func TestMetricsService_SaveBatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		metrics        []models.Metrics
+		mockFn         func(repo *repository.MockMetricsRepository)
+		expectedResult models.BatchResult
+		expectedError  *api.APIError
+	}{
+		{
+			name: "valid and invalid items",
+			metrics: []models.Metrics{
+				{ID: "requests", MType: models.Counter, Delta: intPtr(5)},
+				{ID: "requests", MType: models.Counter},
+				{ID: "temp", MType: models.Gauge},
+				{ID: "temp", MType: models.Gauge, Value: floatPtr(21.5)},
+				{ID: "temp", MType: "unknown"},
+			},
+			mockFn: func(repo *repository.MockMetricsRepository) {
+				repo.EXPECT().GetTypes(mock.Anything, mock.Anything).Return(map[string]string{}, nil)
+				repo.EXPECT().AddAll(mock.Anything, []models.Metrics{
+					{ID: "requests", MType: models.Counter, Delta: intPtr(5)},
+				}).Return(nil)
+				repo.EXPECT().ResetAll(mock.Anything, []models.Metrics{
+					{ID: "temp", MType: models.Gauge, Value: floatPtr(21.5)},
+				}).Return(nil)
+			},
+			expectedResult: models.BatchResult{
+				Accepted: 2, Rejected: 3,
+				Errors: []models.BatchError{
+					{Index: 1, Error: "counter delta is required"},
+					{Index: 2, Error: "gauge value or increment is required"},
+					{Index: 4, Error: "invalid metric type: unknown"},
+				},
+			},
+		},
+		{
+			name: "all rejected",
+			metrics: []models.Metrics{
+				{MType: models.Counter, Delta: intPtr(5)},
+			},
+			mockFn: func(repo *repository.MockMetricsRepository) {},
+			expectedResult: models.BatchResult{
+				Rejected: 1,
+				Errors:   []models.BatchError{{Index: 0, Error: "metric id is required"}},
+			},
+		},
+		{
+			name: "repository error",
+			metrics: []models.Metrics{
+				{ID: "requests", MType: models.Counter, Delta: intPtr(5)},
+			},
+			mockFn: func(repo *repository.MockMetricsRepository) {
+				repo.EXPECT().GetTypes(mock.Anything, mock.Anything).Return(map[string]string{}, nil)
+				repo.EXPECT().AddAll(mock.Anything, mock.Anything).Return(errors.New("db error"))
+			},
+			expectedError: api.Internal("save metrics error", errors.New("db error")),
+		},
+		{
+			name: "counter over stored histogram",
+			metrics: []models.Metrics{
+				{ID: "x", MType: models.Counter, Delta: intPtr(5)},
+				{ID: "requests", MType: models.Counter, Delta: intPtr(1)},
+			},
+			mockFn: func(repo *repository.MockMetricsRepository) {
+				repo.EXPECT().GetTypes(mock.Anything, mock.Anything).Return(map[string]string{"x": models.Histogram}, nil)
+				repo.EXPECT().AddAll(mock.Anything, []models.Metrics{
+					{ID: "requests", MType: models.Counter, Delta: intPtr(1)},
+				}).Return(nil)
+			},
+			expectedResult: models.BatchResult{
+				Accepted: 1, Rejected: 1,
+				Errors: []models.BatchError{{Index: 0, Error: "metric x has type histogram"}},
+			},
+		},
+		{
+			name: "id too long",
+			metrics: []models.Metrics{
+				{ID: strings.Repeat("a", models.MaxIDLength+1), MType: models.Counter, Delta: intPtr(5)},
+				{ID: strings.Repeat("a", models.MaxIDLength), MType: models.Counter, Delta: intPtr(1)},
+			},
+			mockFn: func(repo *repository.MockMetricsRepository) {
+				repo.EXPECT().GetTypes(mock.Anything, mock.Anything).Return(map[string]string{}, nil)
+				repo.EXPECT().AddAll(mock.Anything, []models.Metrics{
+					{ID: strings.Repeat("a", models.MaxIDLength), MType: models.Counter, Delta: intPtr(1)},
+				}).Return(nil)
+			},
+			expectedResult: models.BatchResult{
+				Accepted: 1, Rejected: 1,
+				Errors: []models.BatchError{{Index: 0, Error: "metric id is longer than 64 characters"}},
+			},
+		},
+		{
+			name: "series reported with different types",
+			metrics: []models.Metrics{
+				models.ObserveHistogram("x", []float64{1}, 0.5),
+				models.ObserveSummary("x", 0.5),
+				{ID: "x", MType: models.Counter, Delta: intPtr(1)},
+			},
+			mockFn: func(repo *repository.MockMetricsRepository) {
+				repo.EXPECT().GetTypes(mock.Anything, mock.Anything).Return(map[string]string{}, nil)
+				repo.EXPECT().MergeAll(mock.Anything, []models.Metrics{
+					models.ObserveHistogram("x", []float64{1}, 0.5),
+				}).Return(nil)
+			},
+			expectedResult: models.BatchResult{
+				Accepted: 1, Rejected: 2,
+				Errors: []models.BatchError{
+					{Index: 1, Error: "metric x has type histogram"},
+					{Index: 2, Error: "metric x has type histogram"},
+				},
+			},
+		},
+		{
+			name: "type changed concurrently",
+			metrics: []models.Metrics{
+				{ID: "x", MType: models.Counter, Delta: intPtr(5)},
+			},
+			mockFn: func(repo *repository.MockMetricsRepository) {
+				repo.EXPECT().GetTypes(mock.Anything, mock.Anything).Return(map[string]string{}, nil)
+				repo.EXPECT().AddAll(mock.Anything, mock.Anything).Return(fmt.Errorf("%w: metric x has type histogram", repository.ErrTypeMismatch))
+			},
+			expectedError: api.BadRequest("metric type mismatch: metric x has type histogram"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockMetricsRepository(t)
+			tt.mockFn(mockRepo)
+
+			svc, err := NewMetricsService(mockRepo, repository.NewMockMetaRepository(t), audit.NewMockAuditor(t))
+			require.NoError(t, err)
+
+			result, saveErr := svc.SaveBatch(t.Context(), tt.metrics)
+
+			if tt.expectedError != nil {
+				require.NotNil(t, saveErr)
+				assert.Equal(t, tt.expectedError.Code, saveErr.Code)
+				assert.Equal(t, tt.expectedError.Message, saveErr.Message)
+				return
+			}
+
+			require.Nil(t, saveErr)
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
@@ -6,61 +6,1742 @@ package service
 
 import (
 	"context"
+	"time"
 
 	models "github.com/gabkaclassic/metrics/internal/model"
 	api "github.com/gabkaclassic/metrics/pkg/error"
 	mock "github.com/stretchr/testify/mock"
 )
 
-// NewMockMetricsService creates a new instance of MockMetricsService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// NewMockAlertService creates a new instance of MockAlertService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewMockMetricsService(t interface {
+func NewMockAlertService(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *MockMetricsService {
-	mock := &MockMetricsService{}
+}) *MockAlertService {
+	mock := &MockAlertService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockAlertService is an autogenerated mock type for the AlertService type
+type MockAlertService struct {
+	mock.Mock
+}
+
+type MockAlertService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAlertService) EXPECT() *MockAlertService_Expecter {
+	return &MockAlertService_Expecter{mock: &_m.Mock}
+}
+
+// DeleteRule provides a mock function for the type MockAlertService
+func (_mock *MockAlertService) DeleteRule(context1 context.Context, s string) *api.APIError {
+	ret := _mock.Called(context1, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRule")
+	}
+
+	var r0 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *api.APIError); ok {
+		r0 = returnFunc(context1, s)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.APIError)
+		}
+	}
+	return r0
+}
+
+// MockAlertService_DeleteRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRule'
+type MockAlertService_DeleteRule_Call struct {
+	*mock.Call
+}
+
+// DeleteRule is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+func (_e *MockAlertService_Expecter) DeleteRule(context1 interface{}, s interface{}) *MockAlertService_DeleteRule_Call {
+	return &MockAlertService_DeleteRule_Call{Call: _e.mock.On("DeleteRule", context1, s)}
+}
+
+func (_c *MockAlertService_DeleteRule_Call) Run(run func(context1 context.Context, s string)) *MockAlertService_DeleteRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAlertService_DeleteRule_Call) Return(aPIError *api.APIError) *MockAlertService_DeleteRule_Call {
+	_c.Call.Return(aPIError)
+	return _c
+}
+
+func (_c *MockAlertService_DeleteRule_Call) RunAndReturn(run func(context1 context.Context, s string) *api.APIError) *MockAlertService_DeleteRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAlerts provides a mock function for the type MockAlertService
+func (_mock *MockAlertService) GetAlerts(context1 context.Context) ([]models.Alert, *api.APIError) {
+	ret := _mock.Called(context1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAlerts")
+	}
+
+	var r0 []models.Alert
+	var r1 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.Alert, *api.APIError)); ok {
+		return returnFunc(context1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.Alert); ok {
+		r0 = returnFunc(context1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Alert)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) *api.APIError); ok {
+		r1 = returnFunc(context1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*api.APIError)
+		}
+	}
+	return r0, r1
+}
+
+// MockAlertService_GetAlerts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAlerts'
+type MockAlertService_GetAlerts_Call struct {
+	*mock.Call
+}
+
+// GetAlerts is a helper method to define mock.On call
+//   - context1 context.Context
+func (_e *MockAlertService_Expecter) GetAlerts(context1 interface{}) *MockAlertService_GetAlerts_Call {
+	return &MockAlertService_GetAlerts_Call{Call: _e.mock.On("GetAlerts", context1)}
+}
+
+func (_c *MockAlertService_GetAlerts_Call) Run(run func(context1 context.Context)) *MockAlertService_GetAlerts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAlertService_GetAlerts_Call) Return(alerts []models.Alert, aPIError *api.APIError) *MockAlertService_GetAlerts_Call {
+	_c.Call.Return(alerts, aPIError)
+	return _c
+}
+
+func (_c *MockAlertService_GetAlerts_Call) RunAndReturn(run func(context1 context.Context) ([]models.Alert, *api.APIError)) *MockAlertService_GetAlerts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRule provides a mock function for the type MockAlertService
+func (_mock *MockAlertService) GetRule(context1 context.Context, s string) (models.AlertRule, *api.APIError) {
+	ret := _mock.Called(context1, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRule")
+	}
+
+	var r0 models.AlertRule
+	var r1 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (models.AlertRule, *api.APIError)); ok {
+		return returnFunc(context1, s)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) models.AlertRule); ok {
+		r0 = returnFunc(context1, s)
+	} else {
+		r0 = ret.Get(0).(models.AlertRule)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) *api.APIError); ok {
+		r1 = returnFunc(context1, s)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*api.APIError)
+		}
+	}
+	return r0, r1
+}
+
+// MockAlertService_GetRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRule'
+type MockAlertService_GetRule_Call struct {
+	*mock.Call
+}
+
+// GetRule is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+func (_e *MockAlertService_Expecter) GetRule(context1 interface{}, s interface{}) *MockAlertService_GetRule_Call {
+	return &MockAlertService_GetRule_Call{Call: _e.mock.On("GetRule", context1, s)}
+}
+
+func (_c *MockAlertService_GetRule_Call) Run(run func(context1 context.Context, s string)) *MockAlertService_GetRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAlertService_GetRule_Call) Return(alertRule models.AlertRule, aPIError *api.APIError) *MockAlertService_GetRule_Call {
+	_c.Call.Return(alertRule, aPIError)
+	return _c
+}
+
+func (_c *MockAlertService_GetRule_Call) RunAndReturn(run func(context1 context.Context, s string) (models.AlertRule, *api.APIError)) *MockAlertService_GetRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRules provides a mock function for the type MockAlertService
+func (_mock *MockAlertService) GetRules(context1 context.Context) ([]models.AlertRule, *api.APIError) {
+	ret := _mock.Called(context1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRules")
+	}
+
+	var r0 []models.AlertRule
+	var r1 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.AlertRule, *api.APIError)); ok {
+		return returnFunc(context1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.AlertRule); ok {
+		r0 = returnFunc(context1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AlertRule)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) *api.APIError); ok {
+		r1 = returnFunc(context1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*api.APIError)
+		}
+	}
+	return r0, r1
+}
+
+// MockAlertService_GetRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRules'
+type MockAlertService_GetRules_Call struct {
+	*mock.Call
+}
+
+// GetRules is a helper method to define mock.On call
+//   - context1 context.Context
+func (_e *MockAlertService_Expecter) GetRules(context1 interface{}) *MockAlertService_GetRules_Call {
+	return &MockAlertService_GetRules_Call{Call: _e.mock.On("GetRules", context1)}
+}
+
+func (_c *MockAlertService_GetRules_Call) Run(run func(context1 context.Context)) *MockAlertService_GetRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAlertService_GetRules_Call) Return(alertRules []models.AlertRule, aPIError *api.APIError) *MockAlertService_GetRules_Call {
+	_c.Call.Return(alertRules, aPIError)
+	return _c
+}
+
+func (_c *MockAlertService_GetRules_Call) RunAndReturn(run func(context1 context.Context) ([]models.AlertRule, *api.APIError)) *MockAlertService_GetRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutRule provides a mock function for the type MockAlertService
+func (_mock *MockAlertService) PutRule(context1 context.Context, alertRule models.AlertRule) (models.AlertRule, *api.APIError) {
+	ret := _mock.Called(context1, alertRule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutRule")
+	}
+
+	var r0 models.AlertRule
+	var r1 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.AlertRule) (models.AlertRule, *api.APIError)); ok {
+		return returnFunc(context1, alertRule)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.AlertRule) models.AlertRule); ok {
+		r0 = returnFunc(context1, alertRule)
+	} else {
+		r0 = ret.Get(0).(models.AlertRule)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.AlertRule) *api.APIError); ok {
+		r1 = returnFunc(context1, alertRule)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*api.APIError)
+		}
+	}
+	return r0, r1
+}
+
+// MockAlertService_PutRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutRule'
+type MockAlertService_PutRule_Call struct {
+	*mock.Call
+}
+
+// PutRule is a helper method to define mock.On call
+//   - context1 context.Context
+//   - alertRule models.AlertRule
+func (_e *MockAlertService_Expecter) PutRule(context1 interface{}, alertRule interface{}) *MockAlertService_PutRule_Call {
+	return &MockAlertService_PutRule_Call{Call: _e.mock.On("PutRule", context1, alertRule)}
+}
+
+func (_c *MockAlertService_PutRule_Call) Run(run func(context1 context.Context, alertRule models.AlertRule)) *MockAlertService_PutRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.AlertRule
+		if args[1] != nil {
+			arg1 = args[1].(models.AlertRule)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAlertService_PutRule_Call) Return(alertRule models.AlertRule, aPIError *api.APIError) *MockAlertService_PutRule_Call {
+	_c.Call.Return(alertRule, aPIError)
+	return _c
+}
+
+func (_c *MockAlertService_PutRule_Call) RunAndReturn(run func(context1 context.Context, alertRule models.AlertRule) (models.AlertRule, *api.APIError)) *MockAlertService_PutRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAlertSource creates a new instance of MockAlertSource. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAlertSource(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAlertSource {
+	mock := &MockAlertSource{}
 	mock.Mock.Test(t)
 
-	t.Cleanup(func() { mock.AssertExpectations(t) })
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockAlertSource is an autogenerated mock type for the AlertSource type
+type MockAlertSource struct {
+	mock.Mock
+}
+
+type MockAlertSource_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAlertSource) EXPECT() *MockAlertSource_Expecter {
+	return &MockAlertSource_Expecter{mock: &_m.Mock}
+}
+
+// Alerts provides a mock function for the type MockAlertSource
+func (_mock *MockAlertSource) Alerts(tenant string) []models.Alert {
+	ret := _mock.Called(tenant)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Alerts")
+	}
+
+	var r0 []models.Alert
+	if returnFunc, ok := ret.Get(0).(func(string) []models.Alert); ok {
+		r0 = returnFunc(tenant)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Alert)
+		}
+	}
+	return r0
+}
+
+// MockAlertSource_Alerts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Alerts'
+type MockAlertSource_Alerts_Call struct {
+	*mock.Call
+}
+
+// Alerts is a helper method to define mock.On call
+//   - tenant string
+func (_e *MockAlertSource_Expecter) Alerts(tenant interface{}) *MockAlertSource_Alerts_Call {
+	return &MockAlertSource_Alerts_Call{Call: _e.mock.On("Alerts", tenant)}
+}
+
+func (_c *MockAlertSource_Alerts_Call) Run(run func(tenant string)) *MockAlertSource_Alerts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAlertSource_Alerts_Call) Return(alerts []models.Alert) *MockAlertSource_Alerts_Call {
+	_c.Call.Return(alerts)
+	return _c
+}
+
+func (_c *MockAlertSource_Alerts_Call) RunAndReturn(run func(tenant string) []models.Alert) *MockAlertSource_Alerts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIdempotencyService creates a new instance of MockIdempotencyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIdempotencyService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIdempotencyService {
+	mock := &MockIdempotencyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIdempotencyService is an autogenerated mock type for the IdempotencyService type
+type MockIdempotencyService struct {
+	mock.Mock
+}
+
+type MockIdempotencyService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIdempotencyService) EXPECT() *MockIdempotencyService_Expecter {
+	return &MockIdempotencyService_Expecter{mock: &_m.Mock}
+}
+
+// Begin provides a mock function for the type MockIdempotencyService
+func (_mock *MockIdempotencyService) Begin(context1 context.Context, s string, s1 string) (*models.IdempotentResponse, *api.APIError) {
+	ret := _mock.Called(context1, s, s1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Begin")
+	}
+
+	var r0 *models.IdempotentResponse
+	var r1 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*models.IdempotentResponse, *api.APIError)); ok {
+		return returnFunc(context1, s, s1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *models.IdempotentResponse); ok {
+		r0 = returnFunc(context1, s, s1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.IdempotentResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) *api.APIError); ok {
+		r1 = returnFunc(context1, s, s1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*api.APIError)
+		}
+	}
+	return r0, r1
+}
+
+// MockIdempotencyService_Begin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Begin'
+type MockIdempotencyService_Begin_Call struct {
+	*mock.Call
+}
+
+// Begin is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+//   - s1 string
+func (_e *MockIdempotencyService_Expecter) Begin(context1 interface{}, s interface{}, s1 interface{}) *MockIdempotencyService_Begin_Call {
+	return &MockIdempotencyService_Begin_Call{Call: _e.mock.On("Begin", context1, s, s1)}
+}
+
+func (_c *MockIdempotencyService_Begin_Call) Run(run func(context1 context.Context, s string, s1 string)) *MockIdempotencyService_Begin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIdempotencyService_Begin_Call) Return(idempotentResponse *models.IdempotentResponse, aPIError *api.APIError) *MockIdempotencyService_Begin_Call {
+	_c.Call.Return(idempotentResponse, aPIError)
+	return _c
+}
+
+func (_c *MockIdempotencyService_Begin_Call) RunAndReturn(run func(context1 context.Context, s string, s1 string) (*models.IdempotentResponse, *api.APIError)) *MockIdempotencyService_Begin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Complete provides a mock function for the type MockIdempotencyService
+func (_mock *MockIdempotencyService) Complete(context1 context.Context, s string, idempotentResponse models.IdempotentResponse) *api.APIError {
+	ret := _mock.Called(context1, s, idempotentResponse)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.IdempotentResponse) *api.APIError); ok {
+		r0 = returnFunc(context1, s, idempotentResponse)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.APIError)
+		}
+	}
+	return r0
+}
+
+// MockIdempotencyService_Complete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Complete'
+type MockIdempotencyService_Complete_Call struct {
+	*mock.Call
+}
+
+// Complete is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+//   - idempotentResponse models.IdempotentResponse
+func (_e *MockIdempotencyService_Expecter) Complete(context1 interface{}, s interface{}, idempotentResponse interface{}) *MockIdempotencyService_Complete_Call {
+	return &MockIdempotencyService_Complete_Call{Call: _e.mock.On("Complete", context1, s, idempotentResponse)}
+}
+
+func (_c *MockIdempotencyService_Complete_Call) Run(run func(context1 context.Context, s string, idempotentResponse models.IdempotentResponse)) *MockIdempotencyService_Complete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 models.IdempotentResponse
+		if args[2] != nil {
+			arg2 = args[2].(models.IdempotentResponse)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIdempotencyService_Complete_Call) Return(aPIError *api.APIError) *MockIdempotencyService_Complete_Call {
+	_c.Call.Return(aPIError)
+	return _c
+}
+
+func (_c *MockIdempotencyService_Complete_Call) RunAndReturn(run func(context1 context.Context, s string, idempotentResponse models.IdempotentResponse) *api.APIError) *MockIdempotencyService_Complete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Release provides a mock function for the type MockIdempotencyService
+func (_mock *MockIdempotencyService) Release(context1 context.Context, s string) *api.APIError {
+	ret := _mock.Called(context1, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *api.APIError); ok {
+		r0 = returnFunc(context1, s)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.APIError)
+		}
+	}
+	return r0
+}
+
+// MockIdempotencyService_Release_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Release'
+type MockIdempotencyService_Release_Call struct {
+	*mock.Call
+}
+
+// Release is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+func (_e *MockIdempotencyService_Expecter) Release(context1 interface{}, s interface{}) *MockIdempotencyService_Release_Call {
+	return &MockIdempotencyService_Release_Call{Call: _e.mock.On("Release", context1, s)}
+}
+
+func (_c *MockIdempotencyService_Release_Call) Run(run func(context1 context.Context, s string)) *MockIdempotencyService_Release_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIdempotencyService_Release_Call) Return(aPIError *api.APIError) *MockIdempotencyService_Release_Call {
+	_c.Call.Return(aPIError)
+	return _c
+}
+
+func (_c *MockIdempotencyService_Release_Call) RunAndReturn(run func(context1 context.Context, s string) *api.APIError) *MockIdempotencyService_Release_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StartExpiry provides a mock function for the type MockIdempotencyService
+func (_mock *MockIdempotencyService) StartExpiry(context1 context.Context, duration time.Duration) {
+	_mock.Called(context1, duration)
+	return
+}
+
+// MockIdempotencyService_StartExpiry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartExpiry'
+type MockIdempotencyService_StartExpiry_Call struct {
+	*mock.Call
+}
+
+// StartExpiry is a helper method to define mock.On call
+//   - context1 context.Context
+//   - duration time.Duration
+func (_e *MockIdempotencyService_Expecter) StartExpiry(context1 interface{}, duration interface{}) *MockIdempotencyService_StartExpiry_Call {
+	return &MockIdempotencyService_StartExpiry_Call{Call: _e.mock.On("StartExpiry", context1, duration)}
+}
+
+func (_c *MockIdempotencyService_StartExpiry_Call) Run(run func(context1 context.Context, duration time.Duration)) *MockIdempotencyService_StartExpiry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Duration
+		if args[1] != nil {
+			arg1 = args[1].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIdempotencyService_StartExpiry_Call) Return() *MockIdempotencyService_StartExpiry_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockIdempotencyService_StartExpiry_Call) RunAndReturn(run func(context1 context.Context, duration time.Duration)) *MockIdempotencyService_StartExpiry_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewMockMetaService creates a new instance of MockMetaService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMetaService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMetaService {
+	mock := &MockMetaService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockMetaService is an autogenerated mock type for the MetaService type
+type MockMetaService struct {
+	mock.Mock
+}
+
+type MockMetaService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMetaService) EXPECT() *MockMetaService_Expecter {
+	return &MockMetaService_Expecter{mock: &_m.Mock}
+}
+
+// Get provides a mock function for the type MockMetaService
+func (_mock *MockMetaService) Get(context1 context.Context, s string) (models.Meta, *api.APIError) {
+	ret := _mock.Called(context1, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 models.Meta
+	var r1 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (models.Meta, *api.APIError)); ok {
+		return returnFunc(context1, s)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) models.Meta); ok {
+		r0 = returnFunc(context1, s)
+	} else {
+		r0 = ret.Get(0).(models.Meta)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) *api.APIError); ok {
+		r1 = returnFunc(context1, s)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*api.APIError)
+		}
+	}
+	return r0, r1
+}
+
+// MockMetaService_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockMetaService_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+func (_e *MockMetaService_Expecter) Get(context1 interface{}, s interface{}) *MockMetaService_Get_Call {
+	return &MockMetaService_Get_Call{Call: _e.mock.On("Get", context1, s)}
+}
+
+func (_c *MockMetaService_Get_Call) Run(run func(context1 context.Context, s string)) *MockMetaService_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetaService_Get_Call) Return(meta models.Meta, aPIError *api.APIError) *MockMetaService_Get_Call {
+	_c.Call.Return(meta, aPIError)
+	return _c
+}
+
+func (_c *MockMetaService_Get_Call) RunAndReturn(run func(context1 context.Context, s string) (models.Meta, *api.APIError)) *MockMetaService_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Put provides a mock function for the type MockMetaService
+func (_mock *MockMetaService) Put(context1 context.Context, meta models.Meta) *api.APIError {
+	ret := _mock.Called(context1, meta)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Put")
+	}
+
+	var r0 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Meta) *api.APIError); ok {
+		r0 = returnFunc(context1, meta)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.APIError)
+		}
+	}
+	return r0
+}
+
+// MockMetaService_Put_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Put'
+type MockMetaService_Put_Call struct {
+	*mock.Call
+}
+
+// Put is a helper method to define mock.On call
+//   - context1 context.Context
+//   - meta models.Meta
+func (_e *MockMetaService_Expecter) Put(context1 interface{}, meta interface{}) *MockMetaService_Put_Call {
+	return &MockMetaService_Put_Call{Call: _e.mock.On("Put", context1, meta)}
+}
+
+func (_c *MockMetaService_Put_Call) Run(run func(context1 context.Context, meta models.Meta)) *MockMetaService_Put_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.Meta
+		if args[1] != nil {
+			arg1 = args[1].(models.Meta)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetaService_Put_Call) Return(aPIError *api.APIError) *MockMetaService_Put_Call {
+	_c.Call.Return(aPIError)
+	return _c
+}
+
+func (_c *MockMetaService_Put_Call) RunAndReturn(run func(context1 context.Context, meta models.Meta) *api.APIError) *MockMetaService_Put_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockMetricsService creates a new instance of MockMetricsService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMetricsService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMetricsService {
+	mock := &MockMetricsService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockMetricsService is an autogenerated mock type for the MetricsService type
+type MockMetricsService struct {
+	mock.Mock
+}
+
+type MockMetricsService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMetricsService) EXPECT() *MockMetricsService_Expecter {
+	return &MockMetricsService_Expecter{mock: &_m.Mock}
+}
+
+// Delete provides a mock function for the type MockMetricsService
+func (_mock *MockMetricsService) Delete(context1 context.Context, s string, s1 string) *api.APIError {
+	ret := _mock.Called(context1, s, s1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *api.APIError); ok {
+		r0 = returnFunc(context1, s, s1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.APIError)
+		}
+	}
+	return r0
+}
+
+// MockMetricsService_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockMetricsService_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+//   - s1 string
+func (_e *MockMetricsService_Expecter) Delete(context1 interface{}, s interface{}, s1 interface{}) *MockMetricsService_Delete_Call {
+	return &MockMetricsService_Delete_Call{Call: _e.mock.On("Delete", context1, s, s1)}
+}
+
+func (_c *MockMetricsService_Delete_Call) Run(run func(context1 context.Context, s string, s1 string)) *MockMetricsService_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsService_Delete_Call) Return(aPIError *api.APIError) *MockMetricsService_Delete_Call {
+	_c.Call.Return(aPIError)
+	return _c
+}
+
+func (_c *MockMetricsService_Delete_Call) RunAndReturn(run func(context1 context.Context, s string, s1 string) *api.APIError) *MockMetricsService_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAll provides a mock function for the type MockMetricsService
+func (_mock *MockMetricsService) DeleteAll(context1 context.Context, s string, s1 string) (models.DeleteResult, *api.APIError) {
+	ret := _mock.Called(context1, s, s1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAll")
+	}
+
+	var r0 models.DeleteResult
+	var r1 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (models.DeleteResult, *api.APIError)); ok {
+		return returnFunc(context1, s, s1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) models.DeleteResult); ok {
+		r0 = returnFunc(context1, s, s1)
+	} else {
+		r0 = ret.Get(0).(models.DeleteResult)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) *api.APIError); ok {
+		r1 = returnFunc(context1, s, s1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*api.APIError)
+		}
+	}
+	return r0, r1
+}
+
+// MockMetricsService_DeleteAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAll'
+type MockMetricsService_DeleteAll_Call struct {
+	*mock.Call
+}
+
+// DeleteAll is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+//   - s1 string
+func (_e *MockMetricsService_Expecter) DeleteAll(context1 interface{}, s interface{}, s1 interface{}) *MockMetricsService_DeleteAll_Call {
+	return &MockMetricsService_DeleteAll_Call{Call: _e.mock.On("DeleteAll", context1, s, s1)}
+}
+
+func (_c *MockMetricsService_DeleteAll_Call) Run(run func(context1 context.Context, s string, s1 string)) *MockMetricsService_DeleteAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsService_DeleteAll_Call) Return(deleteResult models.DeleteResult, aPIError *api.APIError) *MockMetricsService_DeleteAll_Call {
+	_c.Call.Return(deleteResult, aPIError)
+	return _c
+}
+
+func (_c *MockMetricsService_DeleteAll_Call) RunAndReturn(run func(context1 context.Context, s string, s1 string) (models.DeleteResult, *api.APIError)) *MockMetricsService_DeleteAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExportPage provides a mock function for the type MockMetricsService
+func (_mock *MockMetricsService) ExportPage(context1 context.Context, seriesRef *models.SeriesRef) ([]models.Metrics, *api.APIError) {
+	ret := _mock.Called(context1, seriesRef)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportPage")
+	}
+
+	var r0 []models.Metrics
+	var r1 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.SeriesRef) ([]models.Metrics, *api.APIError)); ok {
+		return returnFunc(context1, seriesRef)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.SeriesRef) []models.Metrics); ok {
+		r0 = returnFunc(context1, seriesRef)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Metrics)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *models.SeriesRef) *api.APIError); ok {
+		r1 = returnFunc(context1, seriesRef)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*api.APIError)
+		}
+	}
+	return r0, r1
+}
+
+// MockMetricsService_ExportPage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportPage'
+type MockMetricsService_ExportPage_Call struct {
+	*mock.Call
+}
+
+// ExportPage is a helper method to define mock.On call
+//   - context1 context.Context
+//   - seriesRef *models.SeriesRef
+func (_e *MockMetricsService_Expecter) ExportPage(context1 interface{}, seriesRef interface{}) *MockMetricsService_ExportPage_Call {
+	return &MockMetricsService_ExportPage_Call{Call: _e.mock.On("ExportPage", context1, seriesRef)}
+}
+
+func (_c *MockMetricsService_ExportPage_Call) Run(run func(context1 context.Context, seriesRef *models.SeriesRef)) *MockMetricsService_ExportPage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *models.SeriesRef
+		if args[1] != nil {
+			arg1 = args[1].(*models.SeriesRef)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsService_ExportPage_Call) Return(metricss []models.Metrics, aPIError *api.APIError) *MockMetricsService_ExportPage_Call {
+	_c.Call.Return(metricss, aPIError)
+	return _c
+}
+
+func (_c *MockMetricsService_ExportPage_Call) RunAndReturn(run func(context1 context.Context, seriesRef *models.SeriesRef) ([]models.Metrics, *api.APIError)) *MockMetricsService_ExportPage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function for the type MockMetricsService
+func (_mock *MockMetricsService) Get(context1 context.Context, s string, s1 string) (any, *api.APIError) {
+	ret := _mock.Called(context1, s, s1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 any
+	var r1 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (any, *api.APIError)); ok {
+		return returnFunc(context1, s, s1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) any); ok {
+		r0 = returnFunc(context1, s, s1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(any)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) *api.APIError); ok {
+		r1 = returnFunc(context1, s, s1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*api.APIError)
+		}
+	}
+	return r0, r1
+}
+
+// MockMetricsService_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockMetricsService_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+//   - s1 string
+func (_e *MockMetricsService_Expecter) Get(context1 interface{}, s interface{}, s1 interface{}) *MockMetricsService_Get_Call {
+	return &MockMetricsService_Get_Call{Call: _e.mock.On("Get", context1, s, s1)}
+}
+
+func (_c *MockMetricsService_Get_Call) Run(run func(context1 context.Context, s string, s1 string)) *MockMetricsService_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsService_Get_Call) Return(v any, aPIError *api.APIError) *MockMetricsService_Get_Call {
+	_c.Call.Return(v, aPIError)
+	return _c
+}
+
+func (_c *MockMetricsService_Get_Call) RunAndReturn(run func(context1 context.Context, s string, s1 string) (any, *api.APIError)) *MockMetricsService_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAll provides a mock function for the type MockMetricsService
+func (_mock *MockMetricsService) GetAll(context1 context.Context) (map[string]any, *api.APIError) {
+	ret := _mock.Called(context1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAll")
+	}
+
+	var r0 map[string]any
+	var r1 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (map[string]any, *api.APIError)); ok {
+		return returnFunc(context1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) map[string]any); ok {
+		r0 = returnFunc(context1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]any)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) *api.APIError); ok {
+		r1 = returnFunc(context1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*api.APIError)
+		}
+	}
+	return r0, r1
+}
+
+// MockMetricsService_GetAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAll'
+type MockMetricsService_GetAll_Call struct {
+	*mock.Call
+}
+
+// GetAll is a helper method to define mock.On call
+//   - context1 context.Context
+func (_e *MockMetricsService_Expecter) GetAll(context1 interface{}) *MockMetricsService_GetAll_Call {
+	return &MockMetricsService_GetAll_Call{Call: _e.mock.On("GetAll", context1)}
+}
+
+func (_c *MockMetricsService_GetAll_Call) Run(run func(context1 context.Context)) *MockMetricsService_GetAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsService_GetAll_Call) Return(stringToV map[string]any, aPIError *api.APIError) *MockMetricsService_GetAll_Call {
+	_c.Call.Return(stringToV, aPIError)
+	return _c
+}
+
+func (_c *MockMetricsService_GetAll_Call) RunAndReturn(run func(context1 context.Context) (map[string]any, *api.APIError)) *MockMetricsService_GetAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllMetrics provides a mock function for the type MockMetricsService
+func (_mock *MockMetricsService) GetAllMetrics(context1 context.Context) ([]models.Metrics, *api.APIError) {
+	ret := _mock.Called(context1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllMetrics")
+	}
+
+	var r0 []models.Metrics
+	var r1 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.Metrics, *api.APIError)); ok {
+		return returnFunc(context1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.Metrics); ok {
+		r0 = returnFunc(context1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Metrics)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) *api.APIError); ok {
+		r1 = returnFunc(context1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*api.APIError)
+		}
+	}
+	return r0, r1
+}
+
+// MockMetricsService_GetAllMetrics_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllMetrics'
+type MockMetricsService_GetAllMetrics_Call struct {
+	*mock.Call
+}
+
+// GetAllMetrics is a helper method to define mock.On call
+//   - context1 context.Context
+func (_e *MockMetricsService_Expecter) GetAllMetrics(context1 interface{}) *MockMetricsService_GetAllMetrics_Call {
+	return &MockMetricsService_GetAllMetrics_Call{Call: _e.mock.On("GetAllMetrics", context1)}
+}
+
+func (_c *MockMetricsService_GetAllMetrics_Call) Run(run func(context1 context.Context)) *MockMetricsService_GetAllMetrics_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsService_GetAllMetrics_Call) Return(metricss []models.Metrics, aPIError *api.APIError) *MockMetricsService_GetAllMetrics_Call {
+	_c.Call.Return(metricss, aPIError)
+	return _c
+}
+
+func (_c *MockMetricsService_GetAllMetrics_Call) RunAndReturn(run func(context1 context.Context) ([]models.Metrics, *api.APIError)) *MockMetricsService_GetAllMetrics_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDescriptions provides a mock function for the type MockMetricsService
+func (_mock *MockMetricsService) GetDescriptions(context1 context.Context) (map[string]string, *api.APIError) {
+	ret := _mock.Called(context1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDescriptions")
+	}
+
+	var r0 map[string]string
+	var r1 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (map[string]string, *api.APIError)); ok {
+		return returnFunc(context1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) map[string]string); ok {
+		r0 = returnFunc(context1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) *api.APIError); ok {
+		r1 = returnFunc(context1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*api.APIError)
+		}
+	}
+	return r0, r1
+}
+
+// MockMetricsService_GetDescriptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDescriptions'
+type MockMetricsService_GetDescriptions_Call struct {
+	*mock.Call
+}
+
+// GetDescriptions is a helper method to define mock.On call
+//   - context1 context.Context
+func (_e *MockMetricsService_Expecter) GetDescriptions(context1 interface{}) *MockMetricsService_GetDescriptions_Call {
+	return &MockMetricsService_GetDescriptions_Call{Call: _e.mock.On("GetDescriptions", context1)}
+}
+
+func (_c *MockMetricsService_GetDescriptions_Call) Run(run func(context1 context.Context)) *MockMetricsService_GetDescriptions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsService_GetDescriptions_Call) Return(stringToS map[string]string, aPIError *api.APIError) *MockMetricsService_GetDescriptions_Call {
+	_c.Call.Return(stringToS, aPIError)
+	return _c
+}
+
+func (_c *MockMetricsService_GetDescriptions_Call) RunAndReturn(run func(context1 context.Context) (map[string]string, *api.APIError)) *MockMetricsService_GetDescriptions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMany provides a mock function for the type MockMetricsService
+func (_mock *MockMetricsService) GetMany(context1 context.Context, seriesRefs []models.SeriesRef) ([]models.Metrics, *api.APIError) {
+	ret := _mock.Called(context1, seriesRefs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMany")
+	}
+
+	var r0 []models.Metrics
+	var r1 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.SeriesRef) ([]models.Metrics, *api.APIError)); ok {
+		return returnFunc(context1, seriesRefs)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.SeriesRef) []models.Metrics); ok {
+		r0 = returnFunc(context1, seriesRefs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Metrics)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []models.SeriesRef) *api.APIError); ok {
+		r1 = returnFunc(context1, seriesRefs)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*api.APIError)
+		}
+	}
+	return r0, r1
+}
+
+// MockMetricsService_GetMany_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMany'
+type MockMetricsService_GetMany_Call struct {
+	*mock.Call
+}
+
+// GetMany is a helper method to define mock.On call
+//   - context1 context.Context
+//   - seriesRefs []models.SeriesRef
+func (_e *MockMetricsService_Expecter) GetMany(context1 interface{}, seriesRefs interface{}) *MockMetricsService_GetMany_Call {
+	return &MockMetricsService_GetMany_Call{Call: _e.mock.On("GetMany", context1, seriesRefs)}
+}
+
+func (_c *MockMetricsService_GetMany_Call) Run(run func(context1 context.Context, seriesRefs []models.SeriesRef)) *MockMetricsService_GetMany_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []models.SeriesRef
+		if args[1] != nil {
+			arg1 = args[1].([]models.SeriesRef)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsService_GetMany_Call) Return(metricss []models.Metrics, aPIError *api.APIError) *MockMetricsService_GetMany_Call {
+	_c.Call.Return(metricss, aPIError)
+	return _c
+}
+
+func (_c *MockMetricsService_GetMany_Call) RunAndReturn(run func(context1 context.Context, seriesRefs []models.SeriesRef) ([]models.Metrics, *api.APIError)) *MockMetricsService_GetMany_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRange provides a mock function for the type MockMetricsService
+func (_mock *MockMetricsService) GetRange(context1 context.Context, rangeQuery models.RangeQuery) ([]models.Point, *api.APIError) {
+	ret := _mock.Called(context1, rangeQuery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRange")
+	}
+
+	var r0 []models.Point
+	var r1 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RangeQuery) ([]models.Point, *api.APIError)); ok {
+		return returnFunc(context1, rangeQuery)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RangeQuery) []models.Point); ok {
+		r0 = returnFunc(context1, rangeQuery)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Point)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.RangeQuery) *api.APIError); ok {
+		r1 = returnFunc(context1, rangeQuery)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*api.APIError)
+		}
+	}
+	return r0, r1
+}
+
+// MockMetricsService_GetRange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRange'
+type MockMetricsService_GetRange_Call struct {
+	*mock.Call
+}
+
+// GetRange is a helper method to define mock.On call
+//   - context1 context.Context
+//   - rangeQuery models.RangeQuery
+func (_e *MockMetricsService_Expecter) GetRange(context1 interface{}, rangeQuery interface{}) *MockMetricsService_GetRange_Call {
+	return &MockMetricsService_GetRange_Call{Call: _e.mock.On("GetRange", context1, rangeQuery)}
+}
+
+func (_c *MockMetricsService_GetRange_Call) Run(run func(context1 context.Context, rangeQuery models.RangeQuery)) *MockMetricsService_GetRange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.RangeQuery
+		if args[1] != nil {
+			arg1 = args[1].(models.RangeQuery)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsService_GetRange_Call) Return(points []models.Point, aPIError *api.APIError) *MockMetricsService_GetRange_Call {
+	_c.Call.Return(points, aPIError)
+	return _c
+}
+
+func (_c *MockMetricsService_GetRange_Call) RunAndReturn(run func(context1 context.Context, rangeQuery models.RangeQuery) ([]models.Point, *api.APIError)) *MockMetricsService_GetRange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetStruct provides a mock function for the type MockMetricsService
+func (_mock *MockMetricsService) GetStruct(context1 context.Context, s string, s1 string, stringToS map[string]string) (models.Metrics, *api.APIError) {
+	ret := _mock.Called(context1, s, s1, stringToS)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStruct")
+	}
+
+	var r0 models.Metrics
+	var r1 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, map[string]string) (models.Metrics, *api.APIError)); ok {
+		return returnFunc(context1, s, s1, stringToS)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, map[string]string) models.Metrics); ok {
+		r0 = returnFunc(context1, s, s1, stringToS)
+	} else {
+		r0 = ret.Get(0).(models.Metrics)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, map[string]string) *api.APIError); ok {
+		r1 = returnFunc(context1, s, s1, stringToS)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*api.APIError)
+		}
+	}
+	return r0, r1
+}
+
+// MockMetricsService_GetStruct_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetStruct'
+type MockMetricsService_GetStruct_Call struct {
+	*mock.Call
+}
+
+// GetStruct is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+//   - s1 string
+//   - stringToS map[string]string
+func (_e *MockMetricsService_Expecter) GetStruct(context1 interface{}, s interface{}, s1 interface{}, stringToS interface{}) *MockMetricsService_GetStruct_Call {
+	return &MockMetricsService_GetStruct_Call{Call: _e.mock.On("GetStruct", context1, s, s1, stringToS)}
+}
+
+func (_c *MockMetricsService_GetStruct_Call) Run(run func(context1 context.Context, s string, s1 string, stringToS map[string]string)) *MockMetricsService_GetStruct_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 map[string]string
+		if args[3] != nil {
+			arg3 = args[3].(map[string]string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsService_GetStruct_Call) Return(metrics models.Metrics, aPIError *api.APIError) *MockMetricsService_GetStruct_Call {
+	_c.Call.Return(metrics, aPIError)
+	return _c
+}
+
+func (_c *MockMetricsService_GetStruct_Call) RunAndReturn(run func(context1 context.Context, s string, s1 string, stringToS map[string]string) (models.Metrics, *api.APIError)) *MockMetricsService_GetStruct_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUnits provides a mock function for the type MockMetricsService
+func (_mock *MockMetricsService) GetUnits(context1 context.Context) (map[string]string, *api.APIError) {
+	ret := _mock.Called(context1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUnits")
+	}
+
+	var r0 map[string]string
+	var r1 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (map[string]string, *api.APIError)); ok {
+		return returnFunc(context1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) map[string]string); ok {
+		r0 = returnFunc(context1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) *api.APIError); ok {
+		r1 = returnFunc(context1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*api.APIError)
+		}
+	}
+	return r0, r1
+}
+
+// MockMetricsService_GetUnits_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUnits'
+type MockMetricsService_GetUnits_Call struct {
+	*mock.Call
+}
+
+// GetUnits is a helper method to define mock.On call
+//   - context1 context.Context
+func (_e *MockMetricsService_Expecter) GetUnits(context1 interface{}) *MockMetricsService_GetUnits_Call {
+	return &MockMetricsService_GetUnits_Call{Call: _e.mock.On("GetUnits", context1)}
+}
+
+func (_c *MockMetricsService_GetUnits_Call) Run(run func(context1 context.Context)) *MockMetricsService_GetUnits_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsService_GetUnits_Call) Return(stringToS map[string]string, aPIError *api.APIError) *MockMetricsService_GetUnits_Call {
+	_c.Call.Return(stringToS, aPIError)
+	return _c
+}
 
-	return mock
+func (_c *MockMetricsService_GetUnits_Call) RunAndReturn(run func(context1 context.Context) (map[string]string, *api.APIError)) *MockMetricsService_GetUnits_Call {
+	_c.Call.Return(run)
+	return _c
 }
 
-// MockMetricsService is an autogenerated mock type for the MetricsService type
-type MockMetricsService struct {
-	mock.Mock
+// GetUpdated provides a mock function for the type MockMetricsService
+func (_mock *MockMetricsService) GetUpdated(context1 context.Context) (map[string]time.Time, *api.APIError) {
+	ret := _mock.Called(context1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUpdated")
+	}
+
+	var r0 map[string]time.Time
+	var r1 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (map[string]time.Time, *api.APIError)); ok {
+		return returnFunc(context1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) map[string]time.Time); ok {
+		r0 = returnFunc(context1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]time.Time)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) *api.APIError); ok {
+		r1 = returnFunc(context1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*api.APIError)
+		}
+	}
+	return r0, r1
 }
 
-type MockMetricsService_Expecter struct {
-	mock *mock.Mock
+// MockMetricsService_GetUpdated_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUpdated'
+type MockMetricsService_GetUpdated_Call struct {
+	*mock.Call
 }
 
-func (_m *MockMetricsService) EXPECT() *MockMetricsService_Expecter {
-	return &MockMetricsService_Expecter{mock: &_m.Mock}
+// GetUpdated is a helper method to define mock.On call
+//   - context1 context.Context
+func (_e *MockMetricsService_Expecter) GetUpdated(context1 interface{}) *MockMetricsService_GetUpdated_Call {
+	return &MockMetricsService_GetUpdated_Call{Call: _e.mock.On("GetUpdated", context1)}
 }
 
-// Get provides a mock function for the type MockMetricsService
-func (_mock *MockMetricsService) Get(context1 context.Context, s string, s1 string) (any, *api.APIError) {
-	ret := _mock.Called(context1, s, s1)
+func (_c *MockMetricsService_GetUpdated_Call) Run(run func(context1 context.Context)) *MockMetricsService_GetUpdated_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsService_GetUpdated_Call) Return(stringToTime map[string]time.Time, aPIError *api.APIError) *MockMetricsService_GetUpdated_Call {
+	_c.Call.Return(stringToTime, aPIError)
+	return _c
+}
+
+func (_c *MockMetricsService_GetUpdated_Call) RunAndReturn(run func(context1 context.Context) (map[string]time.Time, *api.APIError)) *MockMetricsService_GetUpdated_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Import provides a mock function for the type MockMetricsService
+func (_mock *MockMetricsService) Import(context1 context.Context, importRecords []models.ImportRecord, importOptions models.ImportOptions) (models.ImportReport, *api.APIError) {
+	ret := _mock.Called(context1, importRecords, importOptions)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Get")
+		panic("no return value specified for Import")
 	}
 
-	var r0 any
+	var r0 models.ImportReport
 	var r1 *api.APIError
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (any, *api.APIError)); ok {
-		return returnFunc(context1, s, s1)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.ImportRecord, models.ImportOptions) (models.ImportReport, *api.APIError)); ok {
+		return returnFunc(context1, importRecords, importOptions)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) any); ok {
-		r0 = returnFunc(context1, s, s1)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.ImportRecord, models.ImportOptions) models.ImportReport); ok {
+		r0 = returnFunc(context1, importRecords, importOptions)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(any)
-		}
+		r0 = ret.Get(0).(models.ImportReport)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) *api.APIError); ok {
-		r1 = returnFunc(context1, s, s1)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []models.ImportRecord, models.ImportOptions) *api.APIError); ok {
+		r1 = returnFunc(context1, importRecords, importOptions)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*api.APIError)
@@ -69,32 +1750,32 @@ func (_mock *MockMetricsService) Get(context1 context.Context, s string, s1 stri
 	return r0, r1
 }
 
-// MockMetricsService_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
-type MockMetricsService_Get_Call struct {
+// MockMetricsService_Import_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Import'
+type MockMetricsService_Import_Call struct {
 	*mock.Call
 }
 
-// Get is a helper method to define mock.On call
+// Import is a helper method to define mock.On call
 //   - context1 context.Context
-//   - s string
-//   - s1 string
-func (_e *MockMetricsService_Expecter) Get(context1 interface{}, s interface{}, s1 interface{}) *MockMetricsService_Get_Call {
-	return &MockMetricsService_Get_Call{Call: _e.mock.On("Get", context1, s, s1)}
+//   - importRecords []models.ImportRecord
+//   - importOptions models.ImportOptions
+func (_e *MockMetricsService_Expecter) Import(context1 interface{}, importRecords interface{}, importOptions interface{}) *MockMetricsService_Import_Call {
+	return &MockMetricsService_Import_Call{Call: _e.mock.On("Import", context1, importRecords, importOptions)}
 }
 
-func (_c *MockMetricsService_Get_Call) Run(run func(context1 context.Context, s string, s1 string)) *MockMetricsService_Get_Call {
+func (_c *MockMetricsService_Import_Call) Run(run func(context1 context.Context, importRecords []models.ImportRecord, importOptions models.ImportOptions)) *MockMetricsService_Import_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 []models.ImportRecord
 		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg1 = args[1].([]models.ImportRecord)
 		}
-		var arg2 string
+		var arg2 models.ImportOptions
 		if args[2] != nil {
-			arg2 = args[2].(string)
+			arg2 = args[2].(models.ImportOptions)
 		}
 		run(
 			arg0,
@@ -105,38 +1786,36 @@ func (_c *MockMetricsService_Get_Call) Run(run func(context1 context.Context, s
 	return _c
 }
 
-func (_c *MockMetricsService_Get_Call) Return(v any, aPIError *api.APIError) *MockMetricsService_Get_Call {
-	_c.Call.Return(v, aPIError)
+func (_c *MockMetricsService_Import_Call) Return(importReport models.ImportReport, aPIError *api.APIError) *MockMetricsService_Import_Call {
+	_c.Call.Return(importReport, aPIError)
 	return _c
 }
 
-func (_c *MockMetricsService_Get_Call) RunAndReturn(run func(context1 context.Context, s string, s1 string) (any, *api.APIError)) *MockMetricsService_Get_Call {
+func (_c *MockMetricsService_Import_Call) RunAndReturn(run func(context1 context.Context, importRecords []models.ImportRecord, importOptions models.ImportOptions) (models.ImportReport, *api.APIError)) *MockMetricsService_Import_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetAll provides a mock function for the type MockMetricsService
-func (_mock *MockMetricsService) GetAll(context1 context.Context) (map[string]any, *api.APIError) {
-	ret := _mock.Called(context1)
+// List provides a mock function for the type MockMetricsService
+func (_mock *MockMetricsService) List(context1 context.Context, listQuery models.ListQuery) (models.MetricsPage, *api.APIError) {
+	ret := _mock.Called(context1, listQuery)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAll")
+		panic("no return value specified for List")
 	}
 
-	var r0 map[string]any
+	var r0 models.MetricsPage
 	var r1 *api.APIError
-	if returnFunc, ok := ret.Get(0).(func(context.Context) (map[string]any, *api.APIError)); ok {
-		return returnFunc(context1)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ListQuery) (models.MetricsPage, *api.APIError)); ok {
+		return returnFunc(context1, listQuery)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) map[string]any); ok {
-		r0 = returnFunc(context1)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ListQuery) models.MetricsPage); ok {
+		r0 = returnFunc(context1, listQuery)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[string]any)
-		}
+		r0 = ret.Get(0).(models.MetricsPage)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) *api.APIError); ok {
-		r1 = returnFunc(context1)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.ListQuery) *api.APIError); ok {
+		r1 = returnFunc(context1, listQuery)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*api.APIError)
@@ -145,60 +1824,66 @@ func (_mock *MockMetricsService) GetAll(context1 context.Context) (map[string]an
 	return r0, r1
 }
 
-// MockMetricsService_GetAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAll'
-type MockMetricsService_GetAll_Call struct {
+// MockMetricsService_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockMetricsService_List_Call struct {
 	*mock.Call
 }
 
-// GetAll is a helper method to define mock.On call
+// List is a helper method to define mock.On call
 //   - context1 context.Context
-func (_e *MockMetricsService_Expecter) GetAll(context1 interface{}) *MockMetricsService_GetAll_Call {
-	return &MockMetricsService_GetAll_Call{Call: _e.mock.On("GetAll", context1)}
+//   - listQuery models.ListQuery
+func (_e *MockMetricsService_Expecter) List(context1 interface{}, listQuery interface{}) *MockMetricsService_List_Call {
+	return &MockMetricsService_List_Call{Call: _e.mock.On("List", context1, listQuery)}
 }
 
-func (_c *MockMetricsService_GetAll_Call) Run(run func(context1 context.Context)) *MockMetricsService_GetAll_Call {
+func (_c *MockMetricsService_List_Call) Run(run func(context1 context.Context, listQuery models.ListQuery)) *MockMetricsService_List_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
+		var arg1 models.ListQuery
+		if args[1] != nil {
+			arg1 = args[1].(models.ListQuery)
+		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockMetricsService_GetAll_Call) Return(stringToV map[string]any, aPIError *api.APIError) *MockMetricsService_GetAll_Call {
-	_c.Call.Return(stringToV, aPIError)
+func (_c *MockMetricsService_List_Call) Return(metricsPage models.MetricsPage, aPIError *api.APIError) *MockMetricsService_List_Call {
+	_c.Call.Return(metricsPage, aPIError)
 	return _c
 }
 
-func (_c *MockMetricsService_GetAll_Call) RunAndReturn(run func(context1 context.Context) (map[string]any, *api.APIError)) *MockMetricsService_GetAll_Call {
+func (_c *MockMetricsService_List_Call) RunAndReturn(run func(context1 context.Context, listQuery models.ListQuery) (models.MetricsPage, *api.APIError)) *MockMetricsService_List_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetStruct provides a mock function for the type MockMetricsService
-func (_mock *MockMetricsService) GetStruct(context1 context.Context, s string, s1 string) (models.Metrics, *api.APIError) {
-	ret := _mock.Called(context1, s, s1)
+// Rate provides a mock function for the type MockMetricsService
+func (_mock *MockMetricsService) Rate(context1 context.Context, rateQuery models.RateQuery) (models.Rate, *api.APIError) {
+	ret := _mock.Called(context1, rateQuery)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetStruct")
+		panic("no return value specified for Rate")
 	}
 
-	var r0 models.Metrics
+	var r0 models.Rate
 	var r1 *api.APIError
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (models.Metrics, *api.APIError)); ok {
-		return returnFunc(context1, s, s1)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RateQuery) (models.Rate, *api.APIError)); ok {
+		return returnFunc(context1, rateQuery)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) models.Metrics); ok {
-		r0 = returnFunc(context1, s, s1)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RateQuery) models.Rate); ok {
+		r0 = returnFunc(context1, rateQuery)
 	} else {
-		r0 = ret.Get(0).(models.Metrics)
+		r0 = ret.Get(0).(models.Rate)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) *api.APIError); ok {
-		r1 = returnFunc(context1, s, s1)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.RateQuery) *api.APIError); ok {
+		r1 = returnFunc(context1, rateQuery)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*api.APIError)
@@ -207,48 +1892,42 @@ func (_mock *MockMetricsService) GetStruct(context1 context.Context, s string, s
 	return r0, r1
 }
 
-// MockMetricsService_GetStruct_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetStruct'
-type MockMetricsService_GetStruct_Call struct {
+// MockMetricsService_Rate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Rate'
+type MockMetricsService_Rate_Call struct {
 	*mock.Call
 }
 
-// GetStruct is a helper method to define mock.On call
+// Rate is a helper method to define mock.On call
 //   - context1 context.Context
-//   - s string
-//   - s1 string
-func (_e *MockMetricsService_Expecter) GetStruct(context1 interface{}, s interface{}, s1 interface{}) *MockMetricsService_GetStruct_Call {
-	return &MockMetricsService_GetStruct_Call{Call: _e.mock.On("GetStruct", context1, s, s1)}
+//   - rateQuery models.RateQuery
+func (_e *MockMetricsService_Expecter) Rate(context1 interface{}, rateQuery interface{}) *MockMetricsService_Rate_Call {
+	return &MockMetricsService_Rate_Call{Call: _e.mock.On("Rate", context1, rateQuery)}
 }
 
-func (_c *MockMetricsService_GetStruct_Call) Run(run func(context1 context.Context, s string, s1 string)) *MockMetricsService_GetStruct_Call {
+func (_c *MockMetricsService_Rate_Call) Run(run func(context1 context.Context, rateQuery models.RateQuery)) *MockMetricsService_Rate_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 models.RateQuery
 		if args[1] != nil {
-			arg1 = args[1].(string)
-		}
-		var arg2 string
-		if args[2] != nil {
-			arg2 = args[2].(string)
+			arg1 = args[1].(models.RateQuery)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockMetricsService_GetStruct_Call) Return(metrics models.Metrics, aPIError *api.APIError) *MockMetricsService_GetStruct_Call {
-	_c.Call.Return(metrics, aPIError)
+func (_c *MockMetricsService_Rate_Call) Return(rate models.Rate, aPIError *api.APIError) *MockMetricsService_Rate_Call {
+	_c.Call.Return(rate, aPIError)
 	return _c
 }
 
-func (_c *MockMetricsService_GetStruct_Call) RunAndReturn(run func(context1 context.Context, s string, s1 string) (models.Metrics, *api.APIError)) *MockMetricsService_GetStruct_Call {
+func (_c *MockMetricsService_Rate_Call) RunAndReturn(run func(context1 context.Context, rateQuery models.RateQuery) (models.Rate, *api.APIError)) *MockMetricsService_Rate_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -383,6 +2062,74 @@ func (_c *MockMetricsService_SaveAll_Call) RunAndReturn(run func(context1 contex
 	return _c
 }
 
+// SaveBatch provides a mock function for the type MockMetricsService
+func (_mock *MockMetricsService) SaveBatch(context1 context.Context, metricss []models.Metrics) (models.BatchResult, *api.APIError) {
+	ret := _mock.Called(context1, metricss)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveBatch")
+	}
+
+	var r0 models.BatchResult
+	var r1 *api.APIError
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.Metrics) (models.BatchResult, *api.APIError)); ok {
+		return returnFunc(context1, metricss)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.Metrics) models.BatchResult); ok {
+		r0 = returnFunc(context1, metricss)
+	} else {
+		r0 = ret.Get(0).(models.BatchResult)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []models.Metrics) *api.APIError); ok {
+		r1 = returnFunc(context1, metricss)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*api.APIError)
+		}
+	}
+	return r0, r1
+}
+
+// MockMetricsService_SaveBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveBatch'
+type MockMetricsService_SaveBatch_Call struct {
+	*mock.Call
+}
+
+// SaveBatch is a helper method to define mock.On call
+//   - context1 context.Context
+//   - metricss []models.Metrics
+func (_e *MockMetricsService_Expecter) SaveBatch(context1 interface{}, metricss interface{}) *MockMetricsService_SaveBatch_Call {
+	return &MockMetricsService_SaveBatch_Call{Call: _e.mock.On("SaveBatch", context1, metricss)}
+}
+
+func (_c *MockMetricsService_SaveBatch_Call) Run(run func(context1 context.Context, metricss []models.Metrics)) *MockMetricsService_SaveBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []models.Metrics
+		if args[1] != nil {
+			arg1 = args[1].([]models.Metrics)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetricsService_SaveBatch_Call) Return(batchResult models.BatchResult, aPIError *api.APIError) *MockMetricsService_SaveBatch_Call {
+	_c.Call.Return(batchResult, aPIError)
+	return _c
+}
+
+func (_c *MockMetricsService_SaveBatch_Call) RunAndReturn(run func(context1 context.Context, metricss []models.Metrics) (models.BatchResult, *api.APIError)) *MockMetricsService_SaveBatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SaveStruct provides a mock function for the type MockMetricsService
 func (_mock *MockMetricsService) SaveStruct(context1 context.Context, metrics models.Metrics) *api.APIError {
 	ret := _mock.Called(context1, metrics)
@@ -441,3 +2188,120 @@ func (_c *MockMetricsService_SaveStruct_Call) RunAndReturn(run func(context1 con
 	_c.Call.Return(run)
 	return _c
 }
+
+// NewMockPublisher creates a new instance of MockPublisher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPublisher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPublisher {
+	mock := &MockPublisher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockPublisher is an autogenerated mock type for the Publisher type
+type MockPublisher struct {
+	mock.Mock
+}
+
+type MockPublisher_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockPublisher) EXPECT() *MockPublisher_Expecter {
+	return &MockPublisher_Expecter{mock: &_m.Mock}
+}
+
+// Active provides a mock function for the type MockPublisher
+func (_mock *MockPublisher) Active() bool {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Active")
+	}
+
+	var r0 bool
+	if returnFunc, ok := ret.Get(0).(func() bool); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	return r0
+}
+
+// MockPublisher_Active_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Active'
+type MockPublisher_Active_Call struct {
+	*mock.Call
+}
+
+// Active is a helper method to define mock.On call
+func (_e *MockPublisher_Expecter) Active() *MockPublisher_Active_Call {
+	return &MockPublisher_Active_Call{Call: _e.mock.On("Active")}
+}
+
+func (_c *MockPublisher_Active_Call) Run(run func()) *MockPublisher_Active_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockPublisher_Active_Call) Return(b bool) *MockPublisher_Active_Call {
+	_c.Call.Return(b)
+	return _c
+}
+
+func (_c *MockPublisher_Active_Call) RunAndReturn(run func() bool) *MockPublisher_Active_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Publish provides a mock function for the type MockPublisher
+func (_mock *MockPublisher) Publish(tenant string, metrics []models.Metrics) {
+	_mock.Called(tenant, metrics)
+	return
+}
+
+// MockPublisher_Publish_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Publish'
+type MockPublisher_Publish_Call struct {
+	*mock.Call
+}
+
+// Publish is a helper method to define mock.On call
+//   - tenant string
+//   - metrics []models.Metrics
+func (_e *MockPublisher_Expecter) Publish(tenant interface{}, metrics interface{}) *MockPublisher_Publish_Call {
+	return &MockPublisher_Publish_Call{Call: _e.mock.On("Publish", tenant, metrics)}
+}
+
+func (_c *MockPublisher_Publish_Call) Run(run func(tenant string, metrics []models.Metrics)) *MockPublisher_Publish_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []models.Metrics
+		if args[1] != nil {
+			arg1 = args[1].([]models.Metrics)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPublisher_Publish_Call) Return() *MockPublisher_Publish_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockPublisher_Publish_Call) RunAndReturn(run func(tenant string, metrics []models.Metrics)) *MockPublisher_Publish_Call {
+	_c.Run(run)
+	return _c
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+)
+
+// sampleWindow keeps recent counter deltas and gauge values of every series,
+// so that rates can be computed without full history storage.
+// Samples of the same second are merged: counter deltas are summed,
+// gauges keep the last value. Samples older than retention are dropped.
+type sampleWindow struct {
+	// retention is how long samples are kept.
+	retention time.Duration
+
+	// now returns the current time, replaced in tests.
+	now func() time.Time
+
+	// mu guards series.
+	mu sync.Mutex
+
+	// series holds chronologically ordered samples keyed by sampleKey.
+	series map[string][]models.Point
+}
+
+// newSampleWindow creates an empty sample window keeping samples for retention.
+func newSampleWindow(retention time.Duration) *sampleWindow {
+	return &sampleWindow{
+		retention: retention,
+		now:       time.Now,
+		series:    make(map[string][]models.Point),
+	}
+}
+
+// sampleKey identifies a series of a tenant.
+func sampleKey(tenant string, ref models.SeriesRef) string {
+	return tenant + "\x00" + ref.MType + "\x00" + ref.Key()
+}
+
+// record adds the delta or value of a metric as a sample of its series.
+// Metrics without a timestamp are sampled at the current time.
+func (w *sampleWindow) record(tenant string, metric models.Metrics) {
+	point := metric.Point()
+	if metric.Timestamp == nil {
+		point.Timestamp = w.now().UnixMilli()
+	}
+
+	cutoff := w.now().Add(-w.retention).UnixMilli()
+	if point.Timestamp < cutoff {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key := sampleKey(tenant, metric.Ref())
+	points := w.series[key]
+
+	expired := 0
+	for expired < len(points) && points[expired].Timestamp < cutoff {
+		expired++
+	}
+	points = points[expired:]
+
+	// Samples usually arrive in order, so the insertion point is searched from the end
+	index := len(points)
+	for index > 0 && points[index-1].Timestamp > point.Timestamp {
+		index--
+	}
+
+	if index > 0 && points[index-1].Timestamp/1000 == point.Timestamp/1000 {
+		merged := &points[index-1]
+		merged.Timestamp = point.Timestamp
+		if point.Delta != nil {
+			sum := *point.Delta
+			if merged.Delta != nil {
+				sum += *merged.Delta
+			}
+			merged.Delta = &sum
+		}
+		if point.Value != nil {
+			merged.Value = point.Value
+		}
+	} else {
+		points = slices.Insert(points, index, point)
+	}
+
+	w.series[key] = points
+}
+
+// points returns copies of samples of a series taken within the window ending now.
+func (w *sampleWindow) points(tenant string, ref models.SeriesRef, window time.Duration) []models.Point {
+	from := w.now().Add(-window).UnixMilli()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	result := make([]models.Point, 0)
+	for _, point := range w.series[sampleKey(tenant, ref)] {
+		if point.Timestamp >= from {
+			result = append(result, point)
+		}
+	}
+
+	return result
+}
+
+// remove drops samples of deleted series.
+func (w *sampleWindow) remove(tenant string, metrics []models.Metrics) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, metric := range metrics {
+		delete(w.series, sampleKey(tenant, metric.Ref()))
+	}
+}
+
+// recordSamples records saved counter deltas and gauge values in the sample window.
+// Gauges changed by increments are read back to sample their resulting values,
+// read failures are only logged.
+func (service *metricsService) recordSamples(ctx context.Context, metrics []models.Metrics) {
+	if service.samples == nil {
+		return
+	}
+
+	tenant := middleware.TenantFromCtx(ctx)
+	increments := make(map[string]models.Metrics)
+	refs := make([]models.SeriesRef, 0)
+
+	for _, metric := range metrics {
+		switch {
+		case metric.MType == models.Counter && metric.Delta != nil,
+			metric.MType == models.Gauge && metric.Value != nil:
+			service.samples.record(tenant, metric)
+		case metric.MType == models.Gauge && metric.Increment != nil:
+			increments[metric.Key()] = metric
+			refs = append(refs, metric.Ref())
+		}
+	}
+
+	if len(refs) == 0 {
+		return
+	}
+
+	saved, err := service.repository.GetMany(ctx, refs)
+	if err != nil {
+		slog.Error("Get incremented gauges error", "error", err)
+		return
+	}
+
+	for _, gauge := range saved {
+		increment, exists := increments[gauge.Key()]
+		if !exists || gauge.Value == nil {
+			continue
+		}
+		increment.Value = gauge.Value
+		increment.Increment = nil
+		service.samples.record(tenant, increment)
+	}
+}
+
+// removeSamples drops samples of deleted series of the request tenant.
+func (service *metricsService) removeSamples(ctx context.Context, deleted []models.Metrics) {
+	if service.samples == nil {
+		return
+	}
+
+	service.samples.remove(middleware.TenantFromCtx(ctx), deleted)
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gabkaclassic/metrics/internal/audit"
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/repository"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleWindow_record(t *testing.T) {
+	now := time.UnixMilli(100_000)
+	window := newSampleWindow(time.Minute)
+	window.now = func() time.Time { return now }
+
+	counter := func(timestamp int64, delta int64) models.Metrics {
+		return models.Metrics{ID: "requests", MType: models.Counter, Delta: &delta, Timestamp: &timestamp}
+	}
+	ref := models.SeriesRef{ID: "requests", MType: models.Counter}
+
+	window.record("default", counter(90_100, 1))
+	window.record("default", counter(90_900, 2))
+	window.record("default", counter(95_000, 4))
+	window.record("default", counter(92_000, 8))
+	window.record("default", counter(30_000, 16))
+	window.record("team-a", counter(95_000, 32))
+	window.record("default", models.Metrics{ID: "requests", MType: models.Counter, Delta: intPtr(64)})
+
+	assert.Equal(t, []models.Point{
+		{Timestamp: 90_900, Delta: intPtr(3)},
+		{Timestamp: 92_000, Delta: intPtr(8)},
+		{Timestamp: 95_000, Delta: intPtr(4)},
+		{Timestamp: 100_000, Delta: intPtr(64)},
+	}, window.points("default", ref, time.Minute), "same second merged, out of order inserted, expired dropped")
+
+	assert.Equal(t, []models.Point{
+		{Timestamp: 95_000, Delta: intPtr(4)},
+		{Timestamp: 100_000, Delta: intPtr(64)},
+	}, window.points("default", ref, 5*time.Second))
+
+	now = now.Add(56 * time.Second)
+	window.record("default", counter(156_000, 1))
+
+	assert.Equal(t, []models.Point{
+		{Timestamp: 100_000, Delta: intPtr(64)},
+		{Timestamp: 156_000, Delta: intPtr(1)},
+	}, window.series[sampleKey("default", ref)], "samples older than retention are pruned")
+
+	window.remove("default", []models.Metrics{{ID: "requests", MType: models.Counter}})
+
+	assert.Empty(t, window.points("default", ref, time.Minute))
+	assert.Len(t, window.points("team-a", ref, time.Hour), 1)
+}
+
+func TestMetricsService_recordSamples(t *testing.T) {
+	mockRepo := repository.NewMockMetricsRepository(t)
+	mockRepo.EXPECT().
+		GetMany(mock.Anything, []models.SeriesRef{{ID: "queue", MType: models.Gauge}}).
+		Return([]models.Metrics{{ID: "queue", MType: models.Gauge, Value: floatPtr(7)}}, nil)
+
+	svc, err := NewMetricsService(mockRepo, repository.NewMockMetaRepository(t), audit.NewMockAuditor(t), WithRateWindow(time.Minute))
+	require.NoError(t, err)
+	samples := svc.(*metricsService).samples
+	samples.now = func() time.Time { return time.UnixMilli(100_000) }
+
+	svc.(*metricsService).recordSamples(context.Background(), []models.Metrics{
+		{ID: "requests", MType: models.Counter, Delta: intPtr(2)},
+		{ID: "load", MType: models.Gauge, Value: floatPtr(0.5)},
+		{ID: "queue", MType: models.Gauge, Increment: floatPtr(2)},
+		{ID: "latency", MType: models.Summary},
+	})
+
+	assert.Equal(t, []models.Point{{Timestamp: 100_000, Delta: intPtr(2)}},
+		samples.points(middleware.DefaultTenant, models.SeriesRef{ID: "requests", MType: models.Counter}, time.Minute))
+	assert.Equal(t, []models.Point{{Timestamp: 100_000, Value: floatPtr(0.5)}},
+		samples.points(middleware.DefaultTenant, models.SeriesRef{ID: "load", MType: models.Gauge}, time.Minute))
+	assert.Equal(t, []models.Point{{Timestamp: 100_000, Value: floatPtr(7)}},
+		samples.points(middleware.DefaultTenant, models.SeriesRef{ID: "queue", MType: models.Gauge}, time.Minute))
+	assert.Len(t, samples.series, 3)
+}
+
+func TestMetricsService_Rate(t *testing.T) {
+	now := time.UnixMilli(1_000_000)
+
+	tests := []struct {
+		name          string
+		retention     time.Duration
+		query         models.RateQuery
+		samples       []models.Metrics
+		setupMock     func(m *repository.MockMetricsRepository)
+		expected      models.Rate
+		expectedError *api.APIError
+	}{
+		{
+			name:      "counter rate",
+			retention: time.Hour,
+			query:     models.RateQuery{ID: "requests", MType: models.Counter, Window: time.Minute},
+			samples: []models.Metrics{
+				{ID: "requests", MType: models.Counter, Delta: intPtr(100), Timestamp: intPtr(930_000)},
+				{ID: "requests", MType: models.Counter, Delta: intPtr(30), Timestamp: intPtr(950_000)},
+				{ID: "requests", MType: models.Counter, Delta: intPtr(90), Timestamp: intPtr(990_000)},
+			},
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().Get(mock.Anything, "requests", map[string]string(nil)).
+					Return(&models.Metrics{ID: "requests", MType: models.Counter, Delta: intPtr(220)}, nil)
+			},
+			expected: models.Rate{
+				ID: "requests", MType: models.Counter, Window: 60, Samples: 2,
+				Increase: intPtr(120), Rate: floatPtr(2),
+			},
+		},
+		{
+			name:      "gauge derivative",
+			retention: time.Hour,
+			query:     models.RateQuery{ID: "load", MType: models.Gauge, Labels: map[string]string{"host": "a"}, Window: time.Minute},
+			samples: []models.Metrics{
+				{ID: "load", MType: models.Gauge, Labels: map[string]string{"host": "a"}, Value: floatPtr(10), Timestamp: intPtr(970_000)},
+				{ID: "load", MType: models.Gauge, Labels: map[string]string{"host": "a"}, Value: floatPtr(5), Timestamp: intPtr(980_000)},
+				{ID: "load", MType: models.Gauge, Labels: map[string]string{"host": "a"}, Value: floatPtr(0), Timestamp: intPtr(990_000)},
+			},
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().Get(mock.Anything, "load", map[string]string{"host": "a"}).
+					Return(&models.Metrics{ID: "load", MType: models.Gauge, Value: floatPtr(0)}, nil)
+			},
+			expected: models.Rate{
+				ID: "load", MType: models.Gauge, Labels: map[string]string{"host": "a"}, Window: 60, Samples: 3,
+				Derivative: floatPtr(-0.5),
+			},
+		},
+		{
+			name:      "gauge with a single sample",
+			retention: time.Hour,
+			query:     models.RateQuery{ID: "load", MType: models.Gauge, Window: time.Minute},
+			samples: []models.Metrics{
+				{ID: "load", MType: models.Gauge, Value: floatPtr(1), Timestamp: intPtr(990_000)},
+			},
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().Get(mock.Anything, "load", map[string]string(nil)).
+					Return(&models.Metrics{ID: "load", MType: models.Gauge, Value: floatPtr(1)}, nil)
+			},
+			expected: models.Rate{ID: "load", MType: models.Gauge, Window: 60, Samples: 1},
+		},
+		{
+			name:          "disabled",
+			query:         models.RateQuery{ID: "requests", MType: models.Counter, Window: time.Minute},
+			setupMock:     func(m *repository.MockMetricsRepository) {},
+			expectedError: api.BadRequest("rate queries are disabled"),
+		},
+		{
+			name:          "missing id",
+			retention:     time.Hour,
+			query:         models.RateQuery{MType: models.Counter, Window: time.Minute},
+			setupMock:     func(m *repository.MockMetricsRepository) {},
+			expectedError: api.BadRequest("metric id is required"),
+		},
+		{
+			name:          "histogram has no rate",
+			retention:     time.Hour,
+			query:         models.RateQuery{ID: "latency", MType: models.Histogram, Window: time.Minute},
+			setupMock:     func(m *repository.MockMetricsRepository) {},
+			expectedError: api.BadRequest("metric type histogram has no rate"),
+		},
+		{
+			name:          "invalid label",
+			retention:     time.Hour,
+			query:         models.RateQuery{ID: "requests", MType: models.Counter, Labels: map[string]string{"1a": "b"}, Window: time.Minute},
+			setupMock:     func(m *repository.MockMetricsRepository) {},
+			expectedError: api.BadRequest(`invalid label name: "1a"`),
+		},
+		{
+			name:          "non-positive window",
+			retention:     time.Hour,
+			query:         models.RateQuery{ID: "requests", MType: models.Counter},
+			setupMock:     func(m *repository.MockMetricsRepository) {},
+			expectedError: api.BadRequest("window must be positive"),
+		},
+		{
+			name:          "window above retention",
+			retention:     time.Minute,
+			query:         models.RateQuery{ID: "requests", MType: models.Counter, Window: time.Hour},
+			setupMock:     func(m *repository.MockMetricsRepository) {},
+			expectedError: api.BadRequest("window can't exceed 1m0s"),
+		},
+		{
+			name:      "not found",
+			retention: time.Hour,
+			query:     models.RateQuery{ID: "requests", MType: models.Counter, Window: time.Minute},
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().Get(mock.Anything, "requests", map[string]string(nil)).Return(nil, errors.New("metric requests not found"))
+			},
+			expectedError: api.NotFound("metric requests counter not found"),
+		},
+		{
+			name:      "type mismatch",
+			retention: time.Hour,
+			query:     models.RateQuery{ID: "requests", MType: models.Counter, Window: time.Minute},
+			setupMock: func(m *repository.MockMetricsRepository) {
+				m.EXPECT().Get(mock.Anything, "requests", map[string]string(nil)).
+					Return(&models.Metrics{ID: "requests", MType: models.Gauge, Value: floatPtr(1)}, nil)
+			},
+			expectedError: api.NotFound("metric requests counter not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockMetricsRepository(t)
+			tt.setupMock(mockRepo)
+
+			svc, err := NewMetricsService(mockRepo, repository.NewMockMetaRepository(t), audit.NewMockAuditor(t), WithRateWindow(tt.retention))
+			require.NoError(t, err)
+
+			if samples := svc.(*metricsService).samples; samples != nil {
+				samples.now = func() time.Time { return now }
+				for _, sample := range tt.samples {
+					samples.record(middleware.DefaultTenant, sample)
+				}
+			}
+
+			rate, apiErr := svc.Rate(context.Background(), tt.query)
+
+			if tt.expectedError != nil {
+				require.NotNil(t, apiErr)
+				assert.Equal(t, tt.expectedError.Code, apiErr.Code)
+				assert.Equal(t, tt.expectedError.Message, apiErr.Message)
+				return
+			}
+
+			require.Nil(t, apiErr)
+			assert.Equal(t, tt.expected, rate)
+		})
+	}
+}
+
+func TestMetricsService_SaveAll_recordsSamples(t *testing.T) {
+	mockRepo := repository.NewMockMetricsRepository(t)
+	mockRepo.EXPECT().AddAll(mock.Anything, mock.Anything).Return(nil)
+	mockRepo.EXPECT().ResetAll(mock.Anything, mock.Anything).Return(nil)
+	mockRepo.EXPECT().Get(mock.Anything, "requests", map[string]string(nil)).
+		Return(&models.Metrics{ID: "requests", MType: models.Counter, Delta: intPtr(5)}, nil)
+	mockRepo.EXPECT().Delete(mock.Anything, models.DeleteQuery{ID: "requests", MType: models.Counter}).
+		Return([]models.Metrics{{ID: "requests", MType: models.Counter}}, nil)
+
+	svc, err := NewMetricsService(mockRepo, repository.NewMockMetaRepository(t), audit.NewMockAuditor(t), WithRateWindow(time.Minute))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.Nil(t, svc.SaveAll(ctx, []models.Metrics{
+		{ID: "requests", MType: models.Counter, Delta: intPtr(2)},
+		{ID: "requests", MType: models.Counter, Delta: intPtr(3)},
+		{ID: "load", MType: models.Gauge, Value: floatPtr(1)},
+	}))
+
+	rate, apiErr := svc.Rate(ctx, models.RateQuery{ID: "requests", MType: models.Counter, Window: time.Minute})
+	require.Nil(t, apiErr)
+	assert.Equal(t, intPtr(5), rate.Increase)
+	assert.Equal(t, 1, rate.Samples)
+
+	require.Nil(t, svc.Delete(ctx, "requests", models.Counter))
+	assert.Len(t, svc.(*metricsService).samples.series, 1, "samples of deleted series are dropped")
+}
@@ -0,0 +1,265 @@
+// Package statsd ingests metrics sent over the StatsD line protocol.
+//
+// The listener accepts datagrams on UDP and unix datagram sockets,
+// parses them into metrics and coalesces them per sender. Once per flush
+// interval the collected metrics of each sender are passed to
+// MetricsService.SaveBatch, so storage and audit behave exactly like
+// the partial /updates/ endpoint: invalid metrics are rejected one by one
+// without dropping the rest of the batch.
+package statsd
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gabkaclassic/metrics/internal/config"
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/service"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+)
+
+// maxPacketSize is the largest datagram the listener reads.
+const maxPacketSize = 65535
+
+// Listener collects StatsD metrics and periodically saves them.
+type Listener struct {
+	// service stores the collected metrics.
+	service service.MetricsService
+
+	// tenant receives all collected metrics.
+	tenant string
+
+	// maxPending is the number of metrics of a sender kept until the next flush.
+	maxPending int
+
+	// mu guards pending and dropped.
+	mu sync.Mutex
+
+	// pending holds metrics collected since the last flush keyed by sender.
+	pending map[string][]models.Metrics
+
+	// dropped counts metrics above maxPending since the last flush keyed by sender.
+	dropped map[string]int
+
+	// now returns the current time, replaced in tests.
+	now func() time.Time
+}
+
+// NewListener creates a new StatsD listener.
+//
+// service: Metrics service receiving collected metrics
+// cfg: StatsD configuration containing tenant and pending limit
+//
+// Returns:
+//   - *Listener: Initialized listener ready for serving
+//   - error: If service is nil or the pending limit is not positive
+func NewListener(service service.MetricsService, cfg config.StatsD) (*Listener, error) {
+	if service == nil {
+		return nil, errors.New("create statsd listener error: service can't be nil")
+	}
+
+	if cfg.MaxPending <= 0 {
+		return nil, errors.New("create statsd listener error: max pending must be positive")
+	}
+
+	tenant := cfg.Tenant
+	if tenant == "" {
+		tenant = middleware.DefaultTenant
+	}
+
+	return &Listener{
+		service:    service,
+		tenant:     tenant,
+		maxPending: cfg.MaxPending,
+		pending:    make(map[string][]models.Metrics),
+		dropped:    make(map[string]int),
+		now:        time.Now,
+	}, nil
+}
+
+// Serve reads datagrams from conn until it is closed.
+// Malformed lines are logged and skipped, well-formed lines
+// of the same datagram are still collected.
+//
+// conn: Packet connection to read from
+// source: Sender name used when the datagram has no remote address
+func (l *Listener) Serve(conn net.PacketConn, source string) {
+	buf := make([]byte, maxPacketSize)
+
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				slog.Error("StatsD read error", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		metrics, parseErr := Parse(buf[:n])
+		if parseErr != nil {
+			slog.Warn("StatsD parse error", slog.String("error", parseErr.Error()))
+		}
+
+		l.collect(senderOf(addr, source), metrics)
+	}
+}
+
+// collect queues metrics of a sender until the next flush.
+// Metrics above the pending limit of the sender are counted and dropped.
+func (l *Listener) collect(sender string, metrics []models.Metrics) {
+	if len(metrics) == 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	free := max(l.maxPending-len(l.pending[sender]), 0)
+	if len(metrics) > free {
+		l.dropped[sender] += len(metrics) - free
+		metrics = metrics[:free]
+	}
+
+	if len(metrics) > 0 {
+		l.pending[sender] = append(l.pending[sender], metrics...)
+	}
+}
+
+// Flush saves metrics collected since the last flush, one batch per sender.
+// Each batch is audited with the sender as source IP.
+// Rejected and dropped metrics are logged, the rest of the batch is saved.
+//
+// ctx: Context the batches are saved with
+func (l *Listener) Flush(ctx context.Context) {
+	l.mu.Lock()
+	pending, dropped := l.pending, l.dropped
+	l.pending = make(map[string][]models.Metrics)
+	l.dropped = make(map[string]int)
+	l.mu.Unlock()
+
+	for sender, count := range dropped {
+		slog.Warn(
+			"StatsD metrics dropped, too many pending metrics",
+			slog.String("sender", sender),
+			slog.Int("count", count),
+		)
+	}
+
+	ctx = middleware.WithTenant(ctx, l.tenant)
+	timestamp := l.now().Unix()
+
+	for sender, metrics := range pending {
+		result, err := l.service.SaveBatch(middleware.WithAuditSource(ctx, sender, timestamp), metrics)
+		if err != nil {
+			slog.Error(
+				"StatsD save error",
+				slog.String("sender", sender),
+				slog.Int("count", len(metrics)),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+
+		for _, rejected := range result.Errors {
+			slog.Warn(
+				"StatsD metric rejected",
+				slog.String("sender", sender),
+				slog.String("id", metrics[rejected.Index].ID),
+				slog.String("error", rejected.Error),
+			)
+		}
+	}
+}
+
+// StartListener opens the configured sockets and serves them
+// until context cancellation, flushing collected metrics every
+// flush interval and once more on shutdown.
+//
+// ctx: Context for graceful shutdown (cancellation stops the listener)
+// cfg: StatsD configuration containing addresses and flush interval
+//
+// Returns:
+//   - error: Flush interval or socket setup failure details
+func (l *Listener) StartListener(ctx context.Context, cfg config.StatsD) error {
+	if cfg.FlushInterval <= 0 {
+		return errors.New("statsd flush interval must be positive")
+	}
+
+	conns := make([]net.PacketConn, 0, 2)
+	closeAll := func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+		if cfg.Socket != "" {
+			os.Remove(cfg.Socket)
+		}
+	}
+
+	if cfg.Address != "" {
+		conn, err := net.ListenPacket("udp", cfg.Address)
+		if err != nil {
+			closeAll()
+			return err
+		}
+		conns = append(conns, conn)
+		go l.Serve(conn, cfg.Address)
+		slog.Info("StatsD UDP listener started", slog.String("address", conn.LocalAddr().String()))
+	}
+
+	if cfg.Socket != "" {
+		if err := os.Remove(cfg.Socket); err != nil && !errors.Is(err, os.ErrNotExist) {
+			closeAll()
+			return err
+		}
+		conn, err := net.ListenPacket("unixgram", cfg.Socket)
+		if err != nil {
+			closeAll()
+			return err
+		}
+		conns = append(conns, conn)
+		go l.Serve(conn, cfg.Socket)
+		slog.Info("StatsD unix listener started", slog.String("socket", cfg.Socket))
+	}
+
+	go l.run(ctx, cfg.FlushInterval, closeAll)
+
+	return nil
+}
+
+// run flushes collected metrics every interval until context cancellation,
+// then closes the sockets and flushes the remainder.
+func (l *Listener) run(ctx context.Context, interval time.Duration, closeAll func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			l.Flush(ctx)
+		case <-ctx.Done():
+			closeAll()
+			l.Flush(context.WithoutCancel(ctx))
+			slog.Info("StatsD listener stopped")
+			return
+		}
+	}
+}
+
+// senderOf returns the host of the datagram sender,
+// falling back to source for unnamed senders such as unix sockets.
+func senderOf(addr net.Addr, source string) string {
+	if addr == nil || addr.String() == "" {
+		return source
+	}
+
+	if host, _, err := net.SplitHostPort(addr.String()); err == nil {
+		return host
+	}
+
+	return addr.String()
+}
@@ -0,0 +1,265 @@
+package statsd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gabkaclassic/metrics/internal/config"
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/gabkaclassic/metrics/internal/service"
+	api "github.com/gabkaclassic/metrics/pkg/error"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewListener(t *testing.T) {
+	tests := []struct {
+		name           string
+		service        service.MetricsService
+		cfg            config.StatsD
+		expectedTenant string
+		wantErr        string
+	}{
+		{
+			name:           "default tenant",
+			service:        service.NewMockMetricsService(t),
+			cfg:            config.StatsD{MaxPending: 10},
+			expectedTenant: middleware.DefaultTenant,
+		},
+		{
+			name:           "configured tenant",
+			service:        service.NewMockMetricsService(t),
+			cfg:            config.StatsD{Tenant: "legacy", MaxPending: 10},
+			expectedTenant: "legacy",
+		},
+		{
+			name:    "nil service",
+			cfg:     config.StatsD{MaxPending: 10},
+			wantErr: "create statsd listener error: service can't be nil",
+		},
+		{
+			name:    "invalid max pending",
+			service: service.NewMockMetricsService(t),
+			wantErr: "create statsd listener error: max pending must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := NewListener(tt.service, tt.cfg)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, l)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedTenant, l.tenant)
+			}
+		})
+	}
+}
+
+func TestListener_Flush(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name      string
+		pending   map[string][]models.Metrics
+		setupMock func(*service.MockMetricsService)
+	}{
+		{
+			name: "batch per sender",
+			pending: map[string][]models.Metrics{
+				"10.0.0.1": {{ID: "requests", MType: models.Counter, Delta: int64Ptr(1)}},
+				"10.0.0.2": {{ID: "temperature", MType: models.Gauge, Value: float64Ptr(20)}},
+			},
+			setupMock: func(svc *service.MockMetricsService) {
+				for sender, metric := range map[string]models.Metrics{
+					"10.0.0.1": {ID: "requests", MType: models.Counter, Delta: int64Ptr(1)},
+					"10.0.0.2": {ID: "temperature", MType: models.Gauge, Value: float64Ptr(20)},
+				} {
+					svc.EXPECT().
+						SaveBatch(mock.MatchedBy(func(ctx context.Context) bool {
+							return middleware.AuditIPFromCtx(ctx) == sender &&
+								middleware.AuditTSFromCtx(ctx) == now.Unix() &&
+								middleware.TenantFromCtx(ctx) == "legacy"
+						}), []models.Metrics{metric}).
+						Return(models.BatchResult{Accepted: 1}, nil)
+				}
+			},
+		},
+		{
+			name: "rejected metric is logged",
+			pending: map[string][]models.Metrics{
+				"10.0.0.1": {
+					{ID: "requests", MType: models.Counter, Delta: int64Ptr(1)},
+					{ID: "latency", MType: models.Summary},
+				},
+			},
+			setupMock: func(svc *service.MockMetricsService) {
+				svc.EXPECT().
+					SaveBatch(mock.Anything, mock.Anything).
+					Return(models.BatchResult{
+						Accepted: 1,
+						Rejected: 1,
+						Errors:   []models.BatchError{{Index: 1, Error: "summary sketch is required"}},
+					}, nil)
+			},
+		},
+		{
+			name: "save error is logged",
+			pending: map[string][]models.Metrics{
+				"10.0.0.1": {{ID: "requests", MType: models.Counter, Delta: int64Ptr(1)}},
+			},
+			setupMock: func(svc *service.MockMetricsService) {
+				svc.EXPECT().
+					SaveBatch(mock.Anything, mock.Anything).
+					Return(models.BatchResult{}, api.Internal("save error", errors.New("db error")))
+			},
+		},
+		{
+			name:      "nothing collected",
+			pending:   map[string][]models.Metrics{},
+			setupMock: func(svc *service.MockMetricsService) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewMockMetricsService(t)
+			tt.setupMock(svc)
+
+			l, err := NewListener(svc, config.StatsD{Tenant: "legacy", MaxPending: 10})
+			require.NoError(t, err)
+			l.now = func() time.Time { return now }
+			l.pending = tt.pending
+			l.dropped = map[string]int{"10.0.0.3": 5}
+
+			l.Flush(t.Context())
+
+			assert.Empty(t, l.pending)
+			assert.Empty(t, l.dropped)
+		})
+	}
+}
+
+func TestListener_collect(t *testing.T) {
+	l, err := NewListener(service.NewMockMetricsService(t), config.StatsD{MaxPending: 3})
+	require.NoError(t, err)
+
+	metric := models.Metrics{ID: "requests", MType: models.Counter, Delta: int64Ptr(1)}
+
+	l.collect("10.0.0.1", []models.Metrics{metric, metric})
+	l.collect("10.0.0.1", []models.Metrics{metric, metric})
+	l.collect("10.0.0.1", []models.Metrics{metric})
+	l.collect("10.0.0.2", []models.Metrics{metric})
+
+	assert.Len(t, l.pending["10.0.0.1"], 3)
+	assert.Len(t, l.pending["10.0.0.2"], 1)
+	assert.Equal(t, map[string]int{"10.0.0.1": 2}, l.dropped)
+}
+
+func TestListener_StartListener(t *testing.T) {
+	tests := []struct {
+		name    string
+		network string
+		cfg     func(t *testing.T) config.StatsD
+		sender  string
+	}{
+		{
+			name:    "udp",
+			network: "udp",
+			cfg: func(t *testing.T) config.StatsD {
+				return config.StatsD{Address: "127.0.0.1:0", FlushInterval: time.Hour}
+			},
+			sender: "127.0.0.1",
+		},
+		{
+			name:    "unix datagram",
+			network: "unixgram",
+			cfg: func(t *testing.T) config.StatsD {
+				return config.StatsD{Socket: filepath.Join(t.TempDir(), "statsd.sock"), FlushInterval: time.Hour}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg(t)
+			sender := tt.sender
+			if sender == "" {
+				sender = cfg.Socket
+			}
+
+			saved := make(chan []models.Metrics, 1)
+			svc := service.NewMockMetricsService(t)
+			svc.EXPECT().
+				SaveBatch(mock.MatchedBy(func(ctx context.Context) bool {
+					return middleware.AuditIPFromCtx(ctx) == sender
+				}), mock.Anything).
+				RunAndReturn(func(_ context.Context, metrics []models.Metrics) (models.BatchResult, *api.APIError) {
+					saved <- metrics
+					return models.BatchResult{Accepted: len(metrics)}, nil
+				})
+
+			l, err := NewListener(svc, config.StatsD{MaxPending: 10})
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(t.Context())
+			defer cancel()
+
+			if tt.network == "udp" {
+				probe, err := net.ListenPacket("udp", cfg.Address)
+				require.NoError(t, err)
+				cfg.Address = probe.LocalAddr().String()
+				probe.Close()
+			}
+
+			require.NoError(t, l.StartListener(ctx, cfg))
+
+			target := cfg.Address
+			if tt.network == "unixgram" {
+				target = cfg.Socket
+			}
+			conn, err := net.Dial(tt.network, target)
+			require.NoError(t, err)
+			defer conn.Close()
+
+			_, err = conn.Write([]byte("requests:1|c\nrequests:2|c"))
+			require.NoError(t, err)
+
+			require.Eventually(t, func() bool {
+				l.mu.Lock()
+				defer l.mu.Unlock()
+				return len(l.pending[sender]) == 2
+			}, time.Second, time.Millisecond)
+
+			cancel()
+
+			select {
+			case metrics := <-saved:
+				assert.Equal(t, []models.Metrics{
+					{ID: "requests", MType: models.Counter, Delta: int64Ptr(1)},
+					{ID: "requests", MType: models.Counter, Delta: int64Ptr(2)},
+				}, metrics)
+			case <-time.After(time.Second):
+				t.Fatal("metrics were not flushed on shutdown")
+			}
+		})
+	}
+}
+
+func TestListener_StartListener_invalidInterval(t *testing.T) {
+	l, err := NewListener(service.NewMockMetricsService(t), config.StatsD{MaxPending: 10})
+	require.NoError(t, err)
+
+	err = l.StartListener(t.Context(), config.StatsD{Address: "127.0.0.1:0"})
+
+	assert.EqualError(t, err, "statsd flush interval must be positive")
+}
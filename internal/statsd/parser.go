@@ -0,0 +1,165 @@
+package statsd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+)
+
+// StatsD metric types.
+const (
+	typeCounter = "c"
+	typeGauge   = "g"
+	typeTimer   = "ms"
+	typeHisto   = "h"
+	typeSet     = "s"
+)
+
+// Parse parses a StatsD packet of newline-separated lines.
+//
+// packet: Raw datagram payload
+//
+// Returns:
+//   - []models.Metrics: Metrics of all well-formed lines
+//   - error: Joined errors of malformed lines, nil if all lines are valid
+func Parse(packet []byte) ([]models.Metrics, error) {
+	metrics := make([]models.Metrics, 0)
+	var errs []error
+
+	for line := range strings.SplitSeq(string(packet), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		metric, err := ParseLine(line)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		metrics = append(metrics, metric)
+	}
+
+	return metrics, errors.Join(errs...)
+}
+
+// ParseLine parses a single StatsD line of the form
+// name:value|type[|@sample_rate][|#tag:value,...].
+//
+// line: StatsD line without the trailing newline
+//
+// Returns:
+//   - models.Metrics: Parsed metric
+//   - error: Line format, value or tag errors
+//
+// Type mapping:
+//   - c: counter, the value is scaled by 1/sample_rate and rounded
+//   - g: gauge, values prefixed with "+" or "-" change the stored value
+//   - ms, h: summary observation, aggregated with other observations
+//   - s: set member
+//
+// Tags become series labels.
+func ParseLine(line string) (models.Metrics, error) {
+	name, rest, found := strings.Cut(line, ":")
+	if !found || name == "" {
+		return models.Metrics{}, fmt.Errorf("invalid statsd line %q: missing name", line)
+	}
+
+	fields := strings.Split(rest, "|")
+	if len(fields) < 2 {
+		return models.Metrics{}, fmt.Errorf("invalid statsd line %q: missing type", line)
+	}
+
+	value, metricType := fields[0], fields[1]
+	if value == "" {
+		return models.Metrics{}, fmt.Errorf("invalid statsd line %q: missing value", line)
+	}
+
+	rate := 1.0
+	var labels map[string]string
+
+	for _, field := range fields[2:] {
+		switch {
+		case strings.HasPrefix(field, "@"):
+			parsed, err := strconv.ParseFloat(field[1:], 64)
+			if err != nil || parsed <= 0 || parsed > 1 {
+				return models.Metrics{}, fmt.Errorf("invalid statsd line %q: invalid sample rate", line)
+			}
+			rate = parsed
+		case strings.HasPrefix(field, "#"):
+			parsed, err := parseTags(field[1:])
+			if err != nil {
+				return models.Metrics{}, fmt.Errorf("invalid statsd line %q: %w", line, err)
+			}
+			labels = parsed
+		default:
+			return models.Metrics{}, fmt.Errorf("invalid statsd line %q: unknown field %q", line, field)
+		}
+	}
+
+	metric, err := parseMetric(name, value, metricType, rate)
+	if err != nil {
+		return models.Metrics{}, fmt.Errorf("invalid statsd line %q: %w", line, err)
+	}
+	metric.Labels = labels
+
+	return metric, nil
+}
+
+// parseMetric converts a StatsD value of the given type to a metric.
+// Signed gauge values are relative per the StatsD convention:
+// "+5" increments and "-5" decrements the gauge, unlike models.ParseGauge
+// where only models.IncrementPrefix marks a relative update.
+func parseMetric(name string, value string, metricType string, rate float64) (models.Metrics, error) {
+	switch metricType {
+	case typeCounter:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return models.Metrics{}, errors.New("invalid counter value")
+		}
+		delta := int64(math.Round(parsed / rate))
+		return models.Metrics{ID: name, MType: models.Counter, Delta: &delta}, nil
+	case typeGauge:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return models.Metrics{}, errors.New("invalid gauge value")
+		}
+		if value[0] == '+' || value[0] == '-' {
+			return models.Metrics{ID: name, MType: models.Gauge, Increment: &parsed}, nil
+		}
+		return models.Metrics{ID: name, MType: models.Gauge, Value: &parsed}, nil
+	case typeTimer, typeHisto:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return models.Metrics{}, errors.New("invalid timing value")
+		}
+		return models.ObserveSummary(name, parsed), nil
+	case typeSet:
+		return models.ObserveSet(name, value), nil
+	default:
+		return models.Metrics{}, fmt.Errorf("unknown metric type %q", metricType)
+	}
+}
+
+// parseTags parses comma-separated name:value tags into labels.
+func parseTags(raw string) (map[string]string, error) {
+	labels := make(map[string]string)
+
+	for tag := range strings.SplitSeq(raw, ",") {
+		name, value, found := strings.Cut(tag, ":")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid tag %q", tag)
+		}
+		labels[name] = value
+	}
+
+	if err := models.ValidateLabels(labels); err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
@@ -0,0 +1,109 @@
+package statsd
+
+import (
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func float64Ptr(v float64) *float64 { return &v }
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected models.Metrics
+		wantErr  bool
+	}{
+		{
+			name:     "counter",
+			line:     "requests:3|c",
+			expected: models.Metrics{ID: "requests", MType: models.Counter, Delta: int64Ptr(3)},
+		},
+		{
+			name:     "sampled counter",
+			line:     "requests:1|c|@0.1",
+			expected: models.Metrics{ID: "requests", MType: models.Counter, Delta: int64Ptr(10)},
+		},
+		{
+			name:     "gauge value",
+			line:     "temperature:21.5|g",
+			expected: models.Metrics{ID: "temperature", MType: models.Gauge, Value: float64Ptr(21.5)},
+		},
+		{
+			name:     "gauge increment",
+			line:     "connections:+5|g",
+			expected: models.Metrics{ID: "connections", MType: models.Gauge, Increment: float64Ptr(5)},
+		},
+		{
+			name:     "gauge decrement",
+			line:     "connections:-2|g",
+			expected: models.Metrics{ID: "connections", MType: models.Gauge, Increment: float64Ptr(-2)},
+		},
+		{
+			name:     "timer",
+			line:     "latency:320|ms",
+			expected: models.ObserveSummary("latency", 320),
+		},
+		{
+			name:     "histogram",
+			line:     "size:1.5|h",
+			expected: models.ObserveSummary("size", 1.5),
+		},
+		{
+			name:     "set",
+			line:     "users:alice|s",
+			expected: models.ObserveSet("users", "alice"),
+		},
+		{
+			name: "tags become labels",
+			line: "requests:1|c|#host:web-1,route:/api",
+			expected: models.Metrics{
+				ID:     "requests",
+				MType:  models.Counter,
+				Delta:  int64Ptr(1),
+				Labels: map[string]string{"host": "web-1", "route": "/api"},
+			},
+		},
+		{name: "missing name", line: ":1|c", wantErr: true},
+		{name: "missing separator", line: "requests", wantErr: true},
+		{name: "missing type", line: "requests:1", wantErr: true},
+		{name: "missing value", line: "requests:|c", wantErr: true},
+		{name: "unknown type", line: "requests:1|x", wantErr: true},
+		{name: "invalid counter value", line: "requests:abc|c", wantErr: true},
+		{name: "invalid gauge value", line: "temperature:hot|g", wantErr: true},
+		{name: "gauge double sign", line: "connections:+-2|g", wantErr: true},
+		{name: "invalid timer value", line: "latency:fast|ms", wantErr: true},
+		{name: "invalid sample rate", line: "requests:1|c|@2", wantErr: true},
+		{name: "invalid tag", line: "requests:1|c|#host", wantErr: true},
+		{name: "invalid label name", line: "requests:1|c|#1host:a", wantErr: true},
+		{name: "unknown field", line: "requests:1|c|x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric, err := ParseLine(tt.line)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, metric)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	metrics, err := Parse([]byte("requests:1|c\nbroken\n\ntemperature:20|g\n"))
+
+	assert.Error(t, err)
+	assert.Equal(t, []models.Metrics{
+		{ID: "requests", MType: models.Counter, Delta: int64Ptr(1)},
+		{ID: "temperature", MType: models.Gauge, Value: float64Ptr(20)},
+	}, metrics)
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"cmp"
+	"slices"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+)
+
+// DefaultHistorySize is the number of points kept per series
+// when no history size is configured.
+const DefaultHistorySize = 1000
+
+// History is a bounded ring buffer of points of a single metric series.
+// Once full, every new point overwrites the oldest one.
+// History is not safe for concurrent use.
+type History struct {
+	points []models.Point
+	next   int
+	full   bool
+}
+
+// NewHistory creates an empty history holding up to size points.
+// Non-positive size falls back to DefaultHistorySize.
+func NewHistory(size int) *History {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+
+	return &History{
+		points: make([]models.Point, size),
+	}
+}
+
+// Append records a point, evicting the oldest one if the buffer is full.
+func (h *History) Append(point models.Point) {
+	h.points[h.next] = point
+	h.next = (h.next + 1) % len(h.points)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// Range returns points with timestamps within [from, to] in chronological order.
+// Timestamps are Unix milliseconds.
+func (h *History) Range(from, to int64) []models.Point {
+	result := make([]models.Point, 0)
+
+	for _, point := range h.ordered() {
+		if point.Timestamp >= from && point.Timestamp <= to {
+			result = append(result, point)
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b models.Point) int {
+		return cmp.Compare(a.Timestamp, b.Timestamp)
+	})
+
+	return result
+}
+
+// Prune drops points with timestamps before the given Unix milliseconds,
+// keeping the arrival order of the rest.
+// Returns the number of dropped points.
+func (h *History) Prune(before int64) int {
+	ordered := h.ordered()
+
+	kept := make([]models.Point, 0, len(ordered))
+	for _, point := range ordered {
+		if point.Timestamp >= before {
+			kept = append(kept, point)
+		}
+	}
+
+	pruned := len(ordered) - len(kept)
+	if pruned == 0 {
+		return 0
+	}
+
+	h.points = make([]models.Point, len(h.points))
+	copy(h.points, kept)
+	h.next = len(kept) % len(h.points)
+	h.full = len(kept) == len(h.points)
+
+	return pruned
+}
+
+// ordered returns buffered points from the oldest to the newest by arrival.
+func (h *History) ordered() []models.Point {
+	if !h.full {
+		return h.points[:h.next]
+	}
+
+	return append(slices.Clone(h.points[h.next:]), h.points[:h.next]...)
+}
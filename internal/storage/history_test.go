@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory_Range(t *testing.T) {
+	point := func(ts int64) models.Point {
+		value := float64(ts)
+		return models.Point{Timestamp: ts, Value: &value}
+	}
+
+	tests := []struct {
+		name     string
+		size     int
+		appended []int64
+		from     int64
+		to       int64
+		expected []int64
+	}{
+		{
+			name:     "empty history",
+			size:     3,
+			appended: nil,
+			from:     0,
+			to:       100,
+			expected: []int64{},
+		},
+		{
+			name:     "not full",
+			size:     3,
+			appended: []int64{1, 2},
+			from:     0,
+			to:       100,
+			expected: []int64{1, 2},
+		},
+		{
+			name:     "oldest points evicted",
+			size:     3,
+			appended: []int64{1, 2, 3, 4, 5},
+			from:     0,
+			to:       100,
+			expected: []int64{3, 4, 5},
+		},
+		{
+			name:     "filtered by interval",
+			size:     5,
+			appended: []int64{1, 2, 3, 4, 5},
+			from:     2,
+			to:       4,
+			expected: []int64{2, 3, 4},
+		},
+		{
+			name:     "out of order timestamps sorted",
+			size:     5,
+			appended: []int64{3, 1, 2},
+			from:     0,
+			to:       100,
+			expected: []int64{1, 2, 3},
+		},
+		{
+			name:     "default size",
+			size:     0,
+			appended: []int64{1},
+			from:     0,
+			to:       100,
+			expected: []int64{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := NewHistory(tt.size)
+			for _, ts := range tt.appended {
+				history.Append(point(ts))
+			}
+
+			result := history.Range(tt.from, tt.to)
+
+			timestamps := make([]int64, len(result))
+			for i, p := range result {
+				timestamps[i] = p.Timestamp
+			}
+			assert.Equal(t, tt.expected, timestamps)
+		})
+	}
+}
+
+func TestHistory_Prune(t *testing.T) {
+	point := func(ts int64) models.Point {
+		value := float64(ts)
+		return models.Point{Timestamp: ts, Value: &value}
+	}
+
+	tests := []struct {
+		name           string
+		size           int
+		appended       []int64
+		before         int64
+		expectedPruned int
+		expected       []int64
+	}{
+		{
+			name:           "nothing to prune",
+			size:           3,
+			appended:       []int64{3, 4},
+			before:         3,
+			expectedPruned: 0,
+			expected:       []int64{3, 4},
+		},
+		{
+			name:           "old points of wrapped buffer",
+			size:           3,
+			appended:       []int64{1, 2, 3, 4, 5},
+			before:         4,
+			expectedPruned: 1,
+			expected:       []int64{4, 5},
+		},
+		{
+			name:           "out of order timestamps",
+			size:           5,
+			appended:       []int64{5, 1, 6, 2},
+			before:         3,
+			expectedPruned: 2,
+			expected:       []int64{5, 6},
+		},
+		{
+			name:           "all points",
+			size:           2,
+			appended:       []int64{1, 2},
+			before:         10,
+			expectedPruned: 2,
+			expected:       []int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := NewHistory(tt.size)
+			for _, ts := range tt.appended {
+				history.Append(point(ts))
+			}
+
+			assert.Equal(t, tt.expectedPruned, history.Prune(tt.before))
+
+			result := history.Range(0, 99)
+			timestamps := make([]int64, len(result))
+			for i, p := range result {
+				timestamps[i] = p.Timestamp
+			}
+			assert.Equal(t, tt.expected, timestamps)
+
+			// The size bound holds after pruning
+			for ts := int64(100); ts <= int64(100+tt.size); ts++ {
+				history.Append(point(ts))
+			}
+			assert.Len(t, history.Range(0, 200), tt.size)
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gabkaclassic/metrics/internal/config"
 	models "github.com/gabkaclassic/metrics/internal/model"
@@ -28,15 +29,43 @@ import (
 // MemStorage provides in-memory storage for metrics.
 // Suitable for development, testing, or single-instance deployments.
 type MemStorage struct {
-	// Metrics stores metrics in a map with metric ID as key.
-	Metrics map[string]models.Metrics
+	// Metrics stores metrics partitioned by tenant, then keyed by series key.
+	Metrics map[string]map[string]models.Metrics
+
+	// History stores recent points of every series partitioned by tenant,
+	// then keyed by series key.
+	History map[string]map[string]*History
+
+	// Updated stores the last update time of every series partitioned
+	// by tenant, then keyed by series key.
+	Updated map[string]map[string]time.Time
+
+	// HistorySize limits the number of points kept per series.
+	HistorySize int
+
+	// Meta stores metric metadata partitioned by tenant, then keyed by metric ID.
+	Meta map[string]map[string]models.Meta
+
+	// AlertRules stores alert rules partitioned by tenant, then keyed by rule ID.
+	AlertRules map[string]map[string]models.AlertRule
+
+	// Idempotency stores responses of idempotent requests partitioned by tenant,
+	// then keyed by idempotency key.
+	Idempotency map[string]map[string]models.IdempotentResponse
 }
 
 // NewMemStorage creates and initializes a new in-memory storage.
-// Returns a ready-to-use MemStorage with an empty metrics map.
+// Returns a ready-to-use MemStorage with empty metrics, history, update time, metadata,
+// alert rule and idempotency maps.
 func NewMemStorage() *MemStorage {
 	return &MemStorage{
-		Metrics: make(map[string]models.Metrics),
+		Metrics:     make(map[string]map[string]models.Metrics),
+		History:     make(map[string]map[string]*History),
+		Updated:     make(map[string]map[string]time.Time),
+		HistorySize: DefaultHistorySize,
+		Meta:        make(map[string]map[string]models.Meta),
+		AlertRules:  make(map[string]map[string]models.AlertRule),
+		Idempotency: make(map[string]map[string]models.IdempotentResponse),
 	}
 }
 
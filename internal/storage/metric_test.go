@@ -12,4 +12,6 @@ func TestNewMemStorage(t *testing.T) {
 	assert.NotNil(t, storage)
 	assert.NotNil(t, storage.Metrics)
 	assert.Empty(t, storage.Metrics)
+	assert.NotNil(t, storage.History)
+	assert.Equal(t, DefaultHistorySize, storage.HistorySize)
 }
@@ -0,0 +1,189 @@
+// Package stream distributes live metric changes to subscribers.
+//
+// Broker receives current states of series changed by every save
+// from service.MetricsService and fans them out to subscribers of the
+// owning tenant. Recent events are retained, so reconnecting clients
+// can resume after the last event they received.
+//
+// Throttler limits the rate of updates delivered per series.
+package stream
+
+import (
+	"errors"
+	"sync"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+)
+
+const (
+	// DefaultHistorySize is the number of recent events retained for replay.
+	DefaultHistorySize = 1024
+
+	// subscriptionBuffer is the number of events queued per subscriber.
+	// Subscribers that fall behind further are closed and must reconnect.
+	subscriptionBuffer = 64
+)
+
+// Event is a set of series changed by a single save.
+type Event struct {
+	// ID is the position of the event, increasing with every published event.
+	ID uint64
+
+	// Tenant owns the series.
+	Tenant string
+
+	// Metrics are the current states of the changed series.
+	Metrics []models.Metrics
+}
+
+// Subscription receives events of a single tenant.
+type Subscription struct {
+	// Events delivers published events in order.
+	// Closed when the subscription ends or falls behind.
+	Events <-chan Event
+
+	events chan Event
+	tenant string
+}
+
+// Broker fans out published events to subscribers and retains
+// the most recent events for replay.
+type Broker struct {
+	mu          sync.Mutex
+	lastID      uint64
+	history     []Event
+	historySize int
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+// NewBroker creates a new event broker.
+//
+// historySize: Number of recent events retained for replay
+//
+// Returns:
+//   - *Broker: Ready-to-use broker
+//   - error: If historySize is not positive
+func NewBroker(historySize int) (*Broker, error) {
+	if historySize <= 0 {
+		return nil, errors.New("create stream broker error: history size must be positive")
+	}
+
+	return &Broker{
+		history:     make([]Event, 0, historySize),
+		historySize: historySize,
+		subscribers: make(map[*Subscription]struct{}),
+	}, nil
+}
+
+// Active reports whether the broker has subscribers.
+func (broker *Broker) Active() bool {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	return len(broker.subscribers) > 0
+}
+
+// Publish delivers series of the tenant changed by a single save
+// as one event. Subscribers whose queue is full are closed.
+func (broker *Broker) Publish(tenant string, metrics []models.Metrics) {
+	if len(metrics) == 0 {
+		return
+	}
+
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	if broker.closed {
+		return
+	}
+
+	broker.lastID++
+	event := Event{ID: broker.lastID, Tenant: tenant, Metrics: metrics}
+
+	if len(broker.history) == broker.historySize {
+		broker.history = append(broker.history[:0], broker.history[1:]...)
+	}
+	broker.history = append(broker.history, event)
+
+	for subscription := range broker.subscribers {
+		if subscription.tenant != tenant {
+			continue
+		}
+
+		select {
+		case subscription.events <- event:
+		default:
+			broker.remove(subscription)
+		}
+	}
+}
+
+// Subscribe registers a subscriber of the tenant.
+//
+// tenant: Tenant whose events are delivered
+// lastEventID: ID of the last event received before reconnecting, zero for none
+//
+// Returns:
+//   - *Subscription: Subscription receiving new events
+//   - []Event: Retained events of the tenant published after lastEventID
+//
+// Events missed while the history was overflowing can't be replayed.
+// A closed broker returns a closed subscription.
+func (broker *Broker) Subscribe(tenant string, lastEventID uint64) (*Subscription, []Event) {
+	events := make(chan Event, subscriptionBuffer)
+	subscription := &Subscription{Events: events, events: events, tenant: tenant}
+
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	if broker.closed {
+		close(events)
+		return subscription, nil
+	}
+
+	replay := make([]Event, 0)
+	if lastEventID > 0 {
+		for _, event := range broker.history {
+			if event.ID > lastEventID && event.Tenant == tenant {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	broker.subscribers[subscription] = struct{}{}
+
+	return subscription, replay
+}
+
+// Unsubscribe ends the subscription and closes its events channel.
+// Unsubscribing twice is safe.
+func (broker *Broker) Unsubscribe(subscription *Subscription) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	broker.remove(subscription)
+}
+
+// Close ends all subscriptions and rejects new ones.
+// Used on shutdown, so that streaming responses complete.
+func (broker *Broker) Close() {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	broker.closed = true
+	for subscription := range broker.subscribers {
+		broker.remove(subscription)
+	}
+}
+
+// remove closes the subscription if it is registered.
+// Must be called with the lock held.
+func (broker *Broker) remove(subscription *Subscription) {
+	if _, exists := broker.subscribers[subscription]; !exists {
+		return
+	}
+
+	delete(broker.subscribers, subscription)
+	close(subscription.events)
+}
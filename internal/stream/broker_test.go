@@ -0,0 +1,155 @@
+package stream
+
+import (
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gauge(id string, value float64) models.Metrics {
+	return models.Metrics{ID: id, MType: models.Gauge, Value: &value}
+}
+
+func TestNewBroker(t *testing.T) {
+	broker, err := NewBroker(DefaultHistorySize)
+	assert.NoError(t, err)
+	assert.NotNil(t, broker)
+
+	broker, err = NewBroker(0)
+	assert.Error(t, err)
+	assert.Nil(t, broker)
+}
+
+func TestBroker_Publish(t *testing.T) {
+	tests := []struct {
+		name          string
+		tenant        string
+		metrics       []models.Metrics
+		expectEvent   bool
+		expectMetrics []models.Metrics
+	}{
+		{
+			name:          "tenant subscriber",
+			tenant:        "team-a",
+			metrics:       []models.Metrics{gauge("cpu", 1), gauge("mem", 2)},
+			expectEvent:   true,
+			expectMetrics: []models.Metrics{gauge("cpu", 1), gauge("mem", 2)},
+		},
+		{
+			name:    "other tenant",
+			tenant:  "team-b",
+			metrics: []models.Metrics{gauge("cpu", 1)},
+		},
+		{
+			name:   "no metrics",
+			tenant: "team-a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker, err := NewBroker(DefaultHistorySize)
+			require.NoError(t, err)
+
+			subscription, replay := broker.Subscribe("team-a", 0)
+			assert.Empty(t, replay)
+			assert.True(t, broker.Active())
+
+			broker.Publish(tt.tenant, tt.metrics)
+
+			select {
+			case event := <-subscription.Events:
+				require.True(t, tt.expectEvent)
+				assert.Equal(t, uint64(1), event.ID)
+				assert.Equal(t, "team-a", event.Tenant)
+				assert.Equal(t, tt.expectMetrics, event.Metrics)
+			default:
+				assert.False(t, tt.expectEvent)
+			}
+
+			broker.Unsubscribe(subscription)
+			broker.Unsubscribe(subscription)
+			assert.False(t, broker.Active())
+
+			_, ok := <-subscription.Events
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestBroker_Publish_slowSubscriber(t *testing.T) {
+	broker, err := NewBroker(DefaultHistorySize)
+	require.NoError(t, err)
+
+	subscription, _ := broker.Subscribe("team-a", 0)
+	for i := 0; i <= subscriptionBuffer; i++ {
+		broker.Publish("team-a", []models.Metrics{gauge("cpu", float64(i))})
+	}
+
+	assert.False(t, broker.Active())
+
+	received := 0
+	for range subscription.Events {
+		received++
+	}
+	assert.Equal(t, subscriptionBuffer, received)
+}
+
+func TestBroker_Subscribe(t *testing.T) {
+	tests := []struct {
+		name        string
+		tenant      string
+		lastEventID uint64
+		expectIDs   []uint64
+	}{
+		{name: "no last event", tenant: "team-a", lastEventID: 0, expectIDs: []uint64{}},
+		{name: "after last event", tenant: "team-a", lastEventID: 3, expectIDs: []uint64{4, 5}},
+		{name: "beyond history", tenant: "team-a", lastEventID: 1, expectIDs: []uint64{3, 4, 5}},
+		{name: "other tenant", tenant: "team-b", lastEventID: 1, expectIDs: []uint64{6}},
+		{name: "latest event", tenant: "team-a", lastEventID: 5, expectIDs: []uint64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker, err := NewBroker(4)
+			require.NoError(t, err)
+
+			for i := 1; i <= 5; i++ {
+				broker.Publish("team-a", []models.Metrics{gauge("cpu", float64(i))})
+			}
+			broker.Publish("team-b", []models.Metrics{gauge("cpu", 6)})
+
+			subscription, replay := broker.Subscribe(tt.tenant, tt.lastEventID)
+			defer broker.Unsubscribe(subscription)
+
+			ids := make([]uint64, 0, len(replay))
+			for _, event := range replay {
+				assert.Equal(t, tt.tenant, event.Tenant)
+				ids = append(ids, event.ID)
+			}
+			assert.Equal(t, tt.expectIDs, ids)
+		})
+	}
+}
+
+func TestBroker_Close(t *testing.T) {
+	broker, err := NewBroker(DefaultHistorySize)
+	require.NoError(t, err)
+
+	subscription, _ := broker.Subscribe("team-a", 0)
+	broker.Close()
+
+	_, ok := <-subscription.Events
+	assert.False(t, ok)
+	assert.False(t, broker.Active())
+
+	broker.Publish("team-a", []models.Metrics{gauge("cpu", 1)})
+
+	late, replay := broker.Subscribe("team-a", 0)
+	_, ok = <-late.Events
+	assert.False(t, ok)
+	assert.Empty(t, replay)
+	broker.Unsubscribe(late)
+}
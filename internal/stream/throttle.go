@@ -0,0 +1,75 @@
+package stream
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+)
+
+// Throttler limits updates of every series to one per interval.
+//
+// The first update of a series passes immediately, later updates
+// within the interval are held back and only the latest one is
+// released once the interval has passed. A non-positive interval
+// disables throttling. Throttler is not safe for concurrent use.
+type Throttler struct {
+	interval time.Duration
+	sent     map[string]time.Time
+	pending  map[string]models.Metrics
+}
+
+// NewThrottler creates a throttler with the given interval per series.
+func NewThrottler(interval time.Duration) *Throttler {
+	return &Throttler{
+		interval: interval,
+		sent:     make(map[string]time.Time),
+		pending:  make(map[string]models.Metrics),
+	}
+}
+
+// Add returns metrics that can be sent at now,
+// holding back series updated within the interval.
+func (throttler *Throttler) Add(now time.Time, metrics []models.Metrics) []models.Metrics {
+	if throttler.interval <= 0 {
+		return metrics
+	}
+
+	ready := make([]models.Metrics, 0, len(metrics))
+	for _, metric := range metrics {
+		key := seriesKey(metric)
+		if sent, exists := throttler.sent[key]; exists && now.Sub(sent) < throttler.interval {
+			throttler.pending[key] = metric
+			continue
+		}
+
+		delete(throttler.pending, key)
+		throttler.sent[key] = now
+		ready = append(ready, metric)
+	}
+
+	return ready
+}
+
+// Flush returns held back metrics whose interval has passed at now,
+// ordered by series key.
+func (throttler *Throttler) Flush(now time.Time) []models.Metrics {
+	ready := make([]models.Metrics, 0)
+	for _, key := range slices.Sorted(maps.Keys(throttler.pending)) {
+		if now.Sub(throttler.sent[key]) < throttler.interval {
+			continue
+		}
+
+		ready = append(ready, throttler.pending[key])
+		throttler.sent[key] = now
+		delete(throttler.pending, key)
+	}
+
+	return ready
+}
+
+// seriesKey identifies a series together with its type.
+func seriesKey(metric models.Metrics) string {
+	return metric.MType + " " + metric.Key()
+}
@@ -0,0 +1,81 @@
+package stream
+
+import (
+	"testing"
+	"time"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThrottler(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+
+	type step struct {
+		at     time.Duration
+		add    []models.Metrics
+		flush  bool
+		expect []models.Metrics
+	}
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+		steps    []step
+	}{
+		{
+			name:     "disabled",
+			interval: 0,
+			steps: []step{
+				{at: 0, add: []models.Metrics{gauge("cpu", 1)}, expect: []models.Metrics{gauge("cpu", 1)}},
+				{at: 0, add: []models.Metrics{gauge("cpu", 2)}, expect: []models.Metrics{gauge("cpu", 2)}},
+			},
+		},
+		{
+			name:     "latest update released after interval",
+			interval: time.Second,
+			steps: []step{
+				{at: 0, add: []models.Metrics{gauge("cpu", 1), gauge("mem", 1)}, expect: []models.Metrics{gauge("cpu", 1), gauge("mem", 1)}},
+				{at: 100 * time.Millisecond, add: []models.Metrics{gauge("cpu", 2)}, expect: []models.Metrics{}},
+				{at: 200 * time.Millisecond, add: []models.Metrics{gauge("cpu", 3)}, expect: []models.Metrics{}},
+				{at: 500 * time.Millisecond, flush: true, expect: []models.Metrics{}},
+				{at: time.Second, flush: true, expect: []models.Metrics{gauge("cpu", 3)}},
+				{at: 1500 * time.Millisecond, flush: true, expect: []models.Metrics{}},
+			},
+		},
+		{
+			name:     "update after interval passes immediately",
+			interval: time.Second,
+			steps: []step{
+				{at: 0, add: []models.Metrics{gauge("cpu", 1)}, expect: []models.Metrics{gauge("cpu", 1)}},
+				{at: 500 * time.Millisecond, add: []models.Metrics{gauge("cpu", 2)}, expect: []models.Metrics{}},
+				{at: 1200 * time.Millisecond, add: []models.Metrics{gauge("cpu", 3)}, expect: []models.Metrics{gauge("cpu", 3)}},
+				{at: 2200 * time.Millisecond, flush: true, expect: []models.Metrics{}},
+			},
+		},
+		{
+			name:     "held back series released independently",
+			interval: time.Second,
+			steps: []step{
+				{at: 0, add: []models.Metrics{gauge("cpu", 1)}, expect: []models.Metrics{gauge("cpu", 1)}},
+				{at: 500 * time.Millisecond, add: []models.Metrics{gauge("cpu", 2), gauge("mem", 1)}, expect: []models.Metrics{gauge("mem", 1)}},
+				{at: time.Second, flush: true, expect: []models.Metrics{gauge("cpu", 2)}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			throttler := NewThrottler(tt.interval)
+
+			for _, step := range tt.steps {
+				now := start.Add(step.at)
+				if step.flush {
+					assert.Equal(t, step.expect, throttler.Flush(now))
+				} else {
+					assert.Equal(t, step.expect, throttler.Add(now, step.add))
+				}
+			}
+		})
+	}
+}
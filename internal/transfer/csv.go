@@ -0,0 +1,288 @@
+package transfer
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+)
+
+// csvColumns lists CSV columns in export order.
+//
+// Imported documents must have the id and type columns,
+// other columns are optional and may come in any order.
+// Labels, bounds, buckets and members are JSON-encoded cells,
+// sketches are base64-encoded, empty cells leave the field unset.
+var csvColumns = []string{
+	"id", "type", "labels", "delta", "value", "increment", "bounds", "buckets",
+	"sum", "count", "sketch", "members", "unit", "timestamp",
+}
+
+// csvEncoder writes a header row followed by a metric per row.
+type csvEncoder struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func newCSVEncoder(w io.Writer) *csvEncoder {
+	return &csvEncoder{writer: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder) Encode(metric models.Metrics) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	row := make([]string, 0, len(csvColumns))
+	for _, column := range csvColumns {
+		cell, err := formatCSVCell(metric, column)
+		if err != nil {
+			return err
+		}
+		row = append(row, cell)
+	}
+
+	return e.writer.Write(row)
+}
+
+func (e *csvEncoder) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func (e *csvEncoder) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+	e.headerWritten = true
+
+	return e.writer.Write(csvColumns)
+}
+
+// decodeCSV reads a header row followed by a metric per row.
+// Malformed rows, including rows with a wrong number of cells, are reported by line.
+func decodeCSV(r io.Reader) ([]models.ImportRecord, []models.ImportError, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("invalid CSV document: missing header")
+		}
+		return nil, nil, fmt.Errorf("invalid CSV document: %w", err)
+	}
+
+	columns, err := parseCSVHeader(header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	records := make([]models.ImportRecord, 0)
+	failures := make([]models.ImportError, 0)
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, failures, nil
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			failures = append(failures, models.ImportError{Line: parseErr.StartLine, Error: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		var metric models.Metrics
+		if err := parseCSVRow(&metric, columns, row); err != nil {
+			failures = append(failures, models.ImportError{Line: line, Error: err.Error()})
+			continue
+		}
+		records = append(records, models.ImportRecord{Line: line, Metric: metric})
+	}
+}
+
+// parseCSVHeader validates header cells against the known columns.
+func parseCSVHeader(header []string) ([]string, error) {
+	known := make(map[string]bool, len(csvColumns))
+	for _, column := range csvColumns {
+		known[column] = true
+	}
+
+	columns := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, column := range header {
+		if !known[column] {
+			return nil, fmt.Errorf("invalid CSV document: unknown column %q", column)
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("invalid CSV document: duplicate column %q", column)
+		}
+		seen[column] = true
+		columns[i] = column
+	}
+
+	if !seen["id"] || !seen["type"] {
+		return nil, errors.New("invalid CSV document: id and type columns are required")
+	}
+
+	return columns, nil
+}
+
+// parseCSVRow fills the metric from the row cells.
+func parseCSVRow(metric *models.Metrics, columns, row []string) error {
+	for i, cell := range row {
+		if cell == "" {
+			continue
+		}
+		if err := parseCSVCell(metric, columns[i], cell); err != nil {
+			return fmt.Errorf("invalid %s: %w", columns[i], err)
+		}
+	}
+
+	return nil
+}
+
+func parseCSVCell(metric *models.Metrics, column, cell string) error {
+	switch column {
+	case "id":
+		metric.ID = cell
+	case "type":
+		metric.MType = cell
+	case "unit":
+		metric.Unit = cell
+	case "labels":
+		return json.Unmarshal([]byte(cell), &metric.Labels)
+	case "bounds":
+		return json.Unmarshal([]byte(cell), &metric.Bounds)
+	case "buckets":
+		return json.Unmarshal([]byte(cell), &metric.Buckets)
+	case "members":
+		return json.Unmarshal([]byte(cell), &metric.Members)
+	case "delta":
+		return parseCSVInt(cell, &metric.Delta)
+	case "count":
+		return parseCSVInt(cell, &metric.Count)
+	case "timestamp":
+		return parseCSVInt(cell, &metric.Timestamp)
+	case "value":
+		return parseCSVFloat(cell, &metric.Value)
+	case "increment":
+		return parseCSVFloat(cell, &metric.Increment)
+	case "sum":
+		return parseCSVFloat(cell, &metric.Sum)
+	case "sketch":
+		sketch, err := base64.StdEncoding.DecodeString(cell)
+		if err != nil {
+			return err
+		}
+		metric.Sketch = sketch
+	}
+
+	return nil
+}
+
+func parseCSVInt(cell string, target **int64) error {
+	value, err := strconv.ParseInt(cell, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	*target = &value
+	return nil
+}
+
+func parseCSVFloat(cell string, target **float64) error {
+	value, err := strconv.ParseFloat(cell, 64)
+	if err != nil {
+		return err
+	}
+
+	*target = &value
+	return nil
+}
+
+func formatCSVCell(metric models.Metrics, column string) (string, error) {
+	switch column {
+	case "id":
+		return metric.ID, nil
+	case "type":
+		return metric.MType, nil
+	case "unit":
+		return metric.Unit, nil
+	case "labels":
+		if len(metric.Labels) == 0 {
+			return "", nil
+		}
+		return formatCSVJSON(metric.Labels)
+	case "bounds":
+		if len(metric.Bounds) == 0 {
+			return "", nil
+		}
+		return formatCSVJSON(metric.Bounds)
+	case "buckets":
+		if len(metric.Buckets) == 0 {
+			return "", nil
+		}
+		return formatCSVJSON(metric.Buckets)
+	case "members":
+		if len(metric.Members) == 0 {
+			return "", nil
+		}
+		return formatCSVJSON(metric.Members)
+	case "delta":
+		return formatCSVInt(metric.Delta), nil
+	case "count":
+		return formatCSVInt(metric.Count), nil
+	case "timestamp":
+		return formatCSVInt(metric.Timestamp), nil
+	case "value":
+		return formatCSVFloat(metric.Value), nil
+	case "increment":
+		return formatCSVFloat(metric.Increment), nil
+	case "sum":
+		return formatCSVFloat(metric.Sum), nil
+	case "sketch":
+		return base64.StdEncoding.EncodeToString(metric.Sketch), nil
+	}
+
+	return "", nil
+}
+
+func formatCSVJSON(value any) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func formatCSVInt(value *int64) string {
+	if value == nil {
+		return ""
+	}
+
+	return strconv.FormatInt(*value, 10)
+}
+
+func formatCSVFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(*value, 'g', -1, 64)
+}
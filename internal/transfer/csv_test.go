@@ -0,0 +1,118 @@
+package transfer
+
+import (
+	"strings"
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCSV(t *testing.T) {
+	tests := []struct {
+		name             string
+		input            string
+		expectedRecords  []models.ImportRecord
+		expectedFailures []models.ImportError
+		expectedError    string
+	}{
+		{
+			name: "columns in any order",
+			input: "type,id,labels,delta,increment,members\n" +
+				"counter,requests,\"{\"\"host\"\":\"\"a\"\"}\",5,,\n" +
+				"gauge,temp,,,-2,\n" +
+				"set,users,,,,\"[\"\"u1\"\",\"\"u2\"\"]\"\n",
+			expectedRecords: []models.ImportRecord{
+				{Line: 2, Metric: models.Metrics{ID: "requests", MType: models.Counter, Labels: map[string]string{"host": "a"}, Delta: int64Ptr(5)}},
+				{Line: 3, Metric: models.Metrics{ID: "temp", MType: models.Gauge, Increment: float64Ptr(-2)}},
+				{Line: 4, Metric: models.Metrics{ID: "users", MType: models.Set, Members: []string{"u1", "u2"}}},
+			},
+		},
+		{
+			name: "malformed rows",
+			input: "id,type,value,sketch\n" +
+				"temp,gauge,abc,\n" +
+				"temp,gauge\n" +
+				"sizes,summary,,!!\n" +
+				"temp,gauge,1.5,\n",
+			expectedRecords: []models.ImportRecord{
+				{Line: 5, Metric: models.Metrics{ID: "temp", MType: models.Gauge, Value: float64Ptr(1.5)}},
+			},
+			expectedFailures: []models.ImportError{
+				{Line: 2, Error: `invalid value: strconv.ParseFloat: parsing "abc": invalid syntax`},
+				{Line: 3, Error: "wrong number of fields"},
+				{Line: 4, Error: "invalid sketch: illegal base64 data at input byte 0"},
+			},
+		},
+		{
+			name:          "empty document",
+			input:         "",
+			expectedError: "invalid CSV document: missing header",
+		},
+		{
+			name:          "unknown column",
+			input:         "id,type,color\n",
+			expectedError: `invalid CSV document: unknown column "color"`,
+		},
+		{
+			name:          "duplicate column",
+			input:         "id,type,id\n",
+			expectedError: `invalid CSV document: duplicate column "id"`,
+		},
+		{
+			name:          "missing type column",
+			input:         "id,value\n",
+			expectedError: "invalid CSV document: id and type columns are required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, failures, err := decodeCSV(strings.NewReader(tt.input))
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRecords, records)
+			if tt.expectedFailures == nil {
+				assert.Empty(t, failures)
+			} else {
+				assert.Equal(t, tt.expectedFailures, failures)
+			}
+		})
+	}
+}
+
+func TestFormatCSVCell(t *testing.T) {
+	metric := models.Metrics{
+		ID: "latency", MType: models.Histogram, Labels: map[string]string{"b": "2", "a": "1"},
+		Bounds: []float64{0.1}, Buckets: []int64{1, 2}, Sum: float64Ptr(0.25), Count: int64Ptr(3),
+	}
+
+	tests := []struct {
+		column   string
+		expected string
+	}{
+		{column: "labels", expected: `{"a":"1","b":"2"}`},
+		{column: "bounds", expected: "[0.1]"},
+		{column: "buckets", expected: "[1,2]"},
+		{column: "sum", expected: "0.25"},
+		{column: "count", expected: "3"},
+		{column: "delta", expected: ""},
+		{column: "sketch", expected: ""},
+		{column: "members", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.column, func(t *testing.T) {
+			cell, err := formatCSVCell(metric, tt.column)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cell)
+		})
+	}
+}
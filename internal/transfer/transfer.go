@@ -0,0 +1,184 @@
+// Package transfer encodes and decodes metrics for bulk export and import.
+//
+// Supported formats:
+//   - json   — a JSON array of metrics
+//   - ndjson — one JSON metric per line
+//   - csv    — a header row followed by one metric per row, see csv.go
+//
+// Encoders write metrics one by one, so exports are streamed to the writer.
+// Decoders report malformed records individually with their line,
+// only malformed documents fail the whole decoding.
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+)
+
+// Supported formats.
+const (
+	JSON   = "json"
+	NDJSON = "ndjson"
+	CSV    = "csv"
+)
+
+// Encoder writes metrics in an export format.
+type Encoder interface {
+	// Encode writes a single metric.
+	Encode(models.Metrics) error
+
+	// Close finishes the document, the underlying writer is left open.
+	Close() error
+}
+
+// NewEncoder creates an encoder writing metrics in the format.
+//
+// Returns:
+//   - Encoder: Ready-to-use encoder
+//   - error: If the format is not supported
+func NewEncoder(w io.Writer, format string) (Encoder, error) {
+	switch format {
+	case JSON:
+		return &jsonEncoder{writer: bufio.NewWriter(w)}, nil
+	case NDJSON:
+		buffered := bufio.NewWriter(w)
+		return &ndjsonEncoder{writer: buffered, encoder: json.NewEncoder(buffered)}, nil
+	case CSV:
+		return newCSVEncoder(w), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q, expected csv, ndjson or json", format)
+	}
+}
+
+// Decode reads all records of a document in the format.
+//
+// Returns:
+//   - []models.ImportRecord: Decoded records in document order
+//   - []models.ImportError: Malformed records in document order
+//   - error: If the format is not supported or the document is malformed
+func Decode(r io.Reader, format string) ([]models.ImportRecord, []models.ImportError, error) {
+	switch format {
+	case JSON:
+		return decodeJSON(r)
+	case NDJSON:
+		return decodeNDJSON(r)
+	case CSV:
+		return decodeCSV(r)
+	default:
+		return nil, nil, fmt.Errorf("unsupported format %q, expected csv, ndjson or json", format)
+	}
+}
+
+// jsonEncoder writes metrics as elements of a JSON array.
+type jsonEncoder struct {
+	writer  *bufio.Writer
+	encoded int
+}
+
+func (e *jsonEncoder) Encode(metric models.Metrics) error {
+	data, err := json.Marshal(metric)
+	if err != nil {
+		return err
+	}
+
+	separator := byte(',')
+	if e.encoded == 0 {
+		separator = '['
+	}
+	e.encoded++
+
+	e.writer.WriteByte(separator)
+	_, err = e.writer.Write(data)
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	if e.encoded == 0 {
+		e.writer.WriteByte('[')
+	}
+	e.writer.WriteString("]\n")
+	return e.writer.Flush()
+}
+
+// ndjsonEncoder writes a JSON metric per line.
+type ndjsonEncoder struct {
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(metric models.Metrics) error {
+	return e.encoder.Encode(metric)
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return e.writer.Flush()
+}
+
+// decodeJSON reads a JSON array of metrics.
+// Elements that are valid JSON but not metrics are reported by position.
+func decodeJSON(r io.Reader) ([]models.ImportRecord, []models.ImportError, error) {
+	decoder := json.NewDecoder(r)
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON document: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, nil, errors.New("invalid JSON document: expected an array of metrics")
+	}
+
+	records := make([]models.ImportRecord, 0)
+	failures := make([]models.ImportError, 0)
+	for line := 1; decoder.More(); line++ {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, nil, fmt.Errorf("invalid JSON document: %w", err)
+		}
+
+		var metric models.Metrics
+		if err := json.Unmarshal(raw, &metric); err != nil {
+			failures = append(failures, models.ImportError{Line: line, Error: err.Error()})
+			continue
+		}
+		records = append(records, models.ImportRecord{Line: line, Metric: metric})
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON document: %w", err)
+	}
+
+	return records, failures, nil
+}
+
+// decodeNDJSON reads a JSON metric per line, blank lines are skipped.
+func decodeNDJSON(r io.Reader) ([]models.ImportRecord, []models.ImportError, error) {
+	reader := bufio.NewReader(r)
+
+	records := make([]models.ImportRecord, 0)
+	failures := make([]models.ImportError, 0)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, nil, err
+		}
+
+		if data = bytes.TrimSpace(data); len(data) > 0 {
+			var metric models.Metrics
+			if decodeErr := json.Unmarshal(data, &metric); decodeErr != nil {
+				failures = append(failures, models.ImportError{Line: line, Error: decodeErr.Error()})
+			} else {
+				records = append(records, models.ImportRecord{Line: line, Metric: metric})
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return records, failures, nil
+		}
+	}
+}
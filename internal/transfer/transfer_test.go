@@ -0,0 +1,154 @@
+package transfer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	models "github.com/gabkaclassic/metrics/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func float64Ptr(v float64) *float64 { return &v }
+
+func transferMetrics() []models.Metrics {
+	return []models.Metrics{
+		{ID: "requests", MType: models.Counter, Delta: int64Ptr(42), Labels: map[string]string{"host": "a"}},
+		{ID: "temp", MType: models.Gauge, Value: float64Ptr(21.5), Unit: "celsius"},
+		{
+			ID: "latency", MType: models.Histogram,
+			Bounds: []float64{0.1, 0.5}, Buckets: []int64{3, 5, 1},
+			Sum: float64Ptr(2.75), Count: int64Ptr(9),
+		},
+		{ID: "sizes", MType: models.Summary, Sketch: []byte{1, 2, 3}, Timestamp: int64Ptr(1700000000000)},
+	}
+}
+
+func TestEncoder_roundTrip(t *testing.T) {
+	for _, format := range []string{JSON, NDJSON, CSV} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			encoder, err := NewEncoder(&buf, format)
+			require.NoError(t, err)
+			for _, metric := range transferMetrics() {
+				require.NoError(t, encoder.Encode(metric))
+			}
+			require.NoError(t, encoder.Close())
+
+			records, failures, err := Decode(&buf, format)
+			require.NoError(t, err)
+			assert.Empty(t, failures)
+
+			decoded := make([]models.Metrics, 0, len(records))
+			for _, record := range records {
+				decoded = append(decoded, record.Metric)
+			}
+			assert.Equal(t, transferMetrics(), decoded)
+		})
+	}
+}
+
+func TestEncoder_empty(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{format: JSON, expected: "[]\n"},
+		{format: NDJSON, expected: ""},
+		{format: CSV, expected: strings.Join(csvColumns, ",") + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			encoder, err := NewEncoder(&buf, tt.format)
+			require.NoError(t, err)
+			require.NoError(t, encoder.Close())
+
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestNewEncoder_unsupportedFormat(t *testing.T) {
+	_, err := NewEncoder(&bytes.Buffer{}, "xml")
+
+	assert.EqualError(t, err, `unsupported format "xml", expected csv, ndjson or json`)
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name             string
+		format           string
+		input            string
+		expectedRecords  []models.ImportRecord
+		expectedFailures []models.ImportError
+		expectedError    string
+	}{
+		{
+			name:   "json array",
+			format: JSON,
+			input:  `[{"id":"a","type":"counter","delta":1},{"id":"b","type":"gauge","value":"x"},{"id":"c","type":"gauge","value":2}]`,
+			expectedRecords: []models.ImportRecord{
+				{Line: 1, Metric: models.Metrics{ID: "a", MType: models.Counter, Delta: int64Ptr(1)}},
+				{Line: 3, Metric: models.Metrics{ID: "c", MType: models.Gauge, Value: float64Ptr(2)}},
+			},
+			expectedFailures: []models.ImportError{
+				{Line: 2, Error: "json: cannot unmarshal string into Go struct field Metrics.value of type float64"},
+			},
+		},
+		{
+			name:          "json object",
+			format:        JSON,
+			input:         `{"id":"a"}`,
+			expectedError: "invalid JSON document: expected an array of metrics",
+		},
+		{
+			name:          "json syntax error",
+			format:        JSON,
+			input:         `[{"id":"a"},`,
+			expectedError: "invalid JSON document: unexpected end of JSON input",
+		},
+		{
+			name:   "ndjson lines",
+			format: NDJSON,
+			input:  "{\"id\":\"a\",\"type\":\"counter\",\"delta\":1}\n\n{broken\n{\"id\":\"c\",\"type\":\"gauge\",\"value\":2}",
+			expectedRecords: []models.ImportRecord{
+				{Line: 1, Metric: models.Metrics{ID: "a", MType: models.Counter, Delta: int64Ptr(1)}},
+				{Line: 4, Metric: models.Metrics{ID: "c", MType: models.Gauge, Value: float64Ptr(2)}},
+			},
+			expectedFailures: []models.ImportError{
+				{Line: 3, Error: "invalid character 'b' looking for beginning of object key string"},
+			},
+		},
+		{
+			name:          "unsupported format",
+			format:        "xml",
+			expectedError: `unsupported format "xml", expected csv, ndjson or json`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, failures, err := Decode(strings.NewReader(tt.input), tt.format)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRecords, records)
+			if tt.expectedFailures == nil {
+				assert.Empty(t, failures)
+			} else {
+				assert.Equal(t, tt.expectedFailures, failures)
+			}
+		})
+	}
+}
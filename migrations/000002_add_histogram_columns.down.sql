@@ -0,0 +1,5 @@
+ALTER TABLE metric
+    DROP COLUMN IF EXISTS "bounds",
+    DROP COLUMN IF EXISTS "buckets",
+    DROP COLUMN IF EXISTS "sum",
+    DROP COLUMN IF EXISTS "count";
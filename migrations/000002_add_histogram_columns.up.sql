@@ -0,0 +1,5 @@
+ALTER TABLE metric
+    ADD COLUMN IF NOT EXISTS "bounds" double precision[],
+    ADD COLUMN IF NOT EXISTS "buckets" bigint[],
+    ADD COLUMN IF NOT EXISTS "sum" double precision,
+    ADD COLUMN IF NOT EXISTS "count" bigint;
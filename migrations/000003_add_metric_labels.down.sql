@@ -0,0 +1,6 @@
+DELETE FROM metric WHERE "labels" <> '{}'::jsonb;
+
+ALTER TABLE metric DROP CONSTRAINT IF EXISTS metric_pkey;
+ALTER TABLE metric ADD PRIMARY KEY ("id");
+
+ALTER TABLE metric DROP COLUMN IF EXISTS "labels";
@@ -0,0 +1,5 @@
+ALTER TABLE metric
+    ADD COLUMN IF NOT EXISTS "labels" jsonb NOT NULL DEFAULT '{}'::jsonb;
+
+ALTER TABLE metric DROP CONSTRAINT IF EXISTS metric_pkey;
+ALTER TABLE metric ADD PRIMARY KEY ("id", "labels");
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS metric_history (
+    "id" varchar(64) NOT NULL,
+    "labels" jsonb NOT NULL DEFAULT '{}'::jsonb,
+    "type" varchar(16) NOT NULL,
+    "ts" timestamptz NOT NULL,
+    "delta" bigint,
+    "value" double precision
+);
+
+CREATE INDEX IF NOT EXISTS metric_history_id_ts_idx ON metric_history ("id", "ts");
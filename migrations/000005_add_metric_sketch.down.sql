@@ -0,0 +1,2 @@
+ALTER TABLE metric
+    DROP COLUMN IF EXISTS "sketch";
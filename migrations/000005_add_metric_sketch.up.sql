@@ -0,0 +1,2 @@
+ALTER TABLE metric
+    ADD COLUMN IF NOT EXISTS "sketch" bytea;
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS metric_meta (
+    "id" varchar(64) PRIMARY KEY,
+    "unit" text NOT NULL DEFAULT '',
+    "description" text NOT NULL DEFAULT '',
+    "owner" text NOT NULL DEFAULT ''
+);
@@ -0,0 +1,12 @@
+DELETE FROM metric WHERE "tenant" <> 'default';
+DELETE FROM metric_history WHERE "tenant" <> 'default';
+
+DROP INDEX IF EXISTS metric_history_tenant_id_ts_idx;
+CREATE INDEX IF NOT EXISTS metric_history_id_ts_idx ON metric_history ("id", "ts");
+
+ALTER TABLE metric_history DROP COLUMN IF EXISTS "tenant";
+
+ALTER TABLE metric DROP CONSTRAINT IF EXISTS metric_pkey;
+ALTER TABLE metric ADD PRIMARY KEY ("id", "labels");
+
+ALTER TABLE metric DROP COLUMN IF EXISTS "tenant";
@@ -0,0 +1,11 @@
+ALTER TABLE metric
+    ADD COLUMN IF NOT EXISTS "tenant" varchar(64) NOT NULL DEFAULT 'default';
+
+ALTER TABLE metric DROP CONSTRAINT IF EXISTS metric_pkey;
+ALTER TABLE metric ADD PRIMARY KEY ("tenant", "id", "labels");
+
+ALTER TABLE metric_history
+    ADD COLUMN IF NOT EXISTS "tenant" varchar(64) NOT NULL DEFAULT 'default';
+
+DROP INDEX IF EXISTS metric_history_id_ts_idx;
+CREATE INDEX IF NOT EXISTS metric_history_tenant_id_ts_idx ON metric_history ("tenant", "id", "ts");
@@ -0,0 +1,5 @@
+ALTER TABLE metric_meta DROP COLUMN IF EXISTS "ttl";
+
+DROP INDEX IF EXISTS metric_updated_at_idx;
+
+ALTER TABLE metric DROP COLUMN IF EXISTS "updated_at";
@@ -0,0 +1,7 @@
+ALTER TABLE metric
+    ADD COLUMN IF NOT EXISTS "updated_at" timestamptz NOT NULL DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS metric_updated_at_idx ON metric ("updated_at");
+
+ALTER TABLE metric_meta
+    ADD COLUMN IF NOT EXISTS "ttl" bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS alert_rule (
+    "tenant" varchar(64) NOT NULL DEFAULT 'default',
+    "id" varchar(64) NOT NULL,
+    "metric" text NOT NULL,
+    "type" varchar(16) NOT NULL DEFAULT '',
+    "labels" jsonb NOT NULL DEFAULT '{}'::jsonb,
+    "op" varchar(16) NOT NULL,
+    "threshold" double precision NOT NULL DEFAULT 0,
+    "for" bigint NOT NULL DEFAULT 0,
+    "webhooks" text[] NOT NULL DEFAULT '{}',
+    PRIMARY KEY ("tenant", "id")
+);
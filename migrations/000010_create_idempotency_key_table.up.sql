@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS idempotency_key (
+    "tenant" varchar(64) NOT NULL DEFAULT 'default',
+    "key" varchar(255) NOT NULL,
+    "status" integer NOT NULL DEFAULT 0,
+    "content_type" text NOT NULL DEFAULT '',
+    "body" bytea,
+    "expires_at" timestamptz NOT NULL,
+    PRIMARY KEY ("tenant", "key")
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_key_expires_at_idx ON idempotency_key ("expires_at");
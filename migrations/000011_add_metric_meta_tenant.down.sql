@@ -0,0 +1,6 @@
+DELETE FROM metric_meta WHERE "tenant" <> 'default';
+
+ALTER TABLE metric_meta DROP CONSTRAINT IF EXISTS metric_meta_pkey;
+ALTER TABLE metric_meta ADD PRIMARY KEY ("id");
+
+ALTER TABLE metric_meta DROP COLUMN IF EXISTS "tenant";
@@ -0,0 +1,5 @@
+ALTER TABLE metric_meta
+    ADD COLUMN IF NOT EXISTS "tenant" varchar(64) NOT NULL DEFAULT 'default';
+
+ALTER TABLE metric_meta DROP CONSTRAINT IF EXISTS metric_meta_pkey;
+ALTER TABLE metric_meta ADD PRIMARY KEY ("tenant", "id");
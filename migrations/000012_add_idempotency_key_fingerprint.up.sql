@@ -0,0 +1,2 @@
+ALTER TABLE idempotency_key
+    ADD COLUMN IF NOT EXISTS "fingerprint" text NOT NULL DEFAULT '';
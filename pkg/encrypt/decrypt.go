@@ -0,0 +1,72 @@
+package encrypt
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+type (
+	// Decryptor defines the interface for decrypting payloads.
+	Decryptor interface {
+		// Decrypt opens the given payload.
+		// data: Encrypted payload.
+		// Returns: original data or error if the payload can't be opened.
+		Decrypt(data []byte) ([]byte, error)
+	}
+
+	// RSADecryptor implements Decryptor with RSA-OAEP and AES-GCM.
+	// Opens payloads sealed by RSAEncryptor with the matching public key.
+	RSADecryptor struct {
+		key *rsa.PrivateKey
+	}
+)
+
+// NewRSADecryptor creates a new hybrid decryptor with the provided private key.
+//
+// key: RSA private key of the payload recipient.
+//
+// Returns: Initialized Decryptor ready to open payloads.
+func NewRSADecryptor(key *rsa.PrivateKey) Decryptor {
+	return &RSADecryptor{
+		key: key,
+	}
+}
+
+// Decrypt decrypts the AES key of the payload with RSA-OAEP
+// and opens the rest of the payload with AES-GCM.
+//
+// data: Payload created by RSAEncryptor.
+//
+// Returns:
+//   - []byte: Original data
+//   - error: If the payload is truncated, was encrypted for another key or was modified
+func (decryptor *RSADecryptor) Decrypt(data []byte) ([]byte, error) {
+	keySize := decryptor.key.Size()
+	if len(data) < keySize {
+		return nil, errors.New("payload is too short")
+	}
+
+	key, err := rsa.DecryptOAEP(sha256.New(), nil, decryptor.key, data[:keySize], nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt key error: %w", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data = data[keySize:]
+	if len(data) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, errors.New("payload is too short")
+	}
+
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt payload error: %w", err)
+	}
+
+	return plain, nil
+}
@@ -0,0 +1,75 @@
+package encrypt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSADecryptor_Decrypt(t *testing.T) {
+	key := newTestKey(t)
+	data := []byte(`[{"id":"Alloc","type":"gauge","value":1.5}]`)
+
+	payload, err := NewRSAEncryptor(&key.PublicKey).Encrypt(data)
+	require.NoError(t, err)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, MinKeyBits)
+	require.NoError(t, err)
+	foreign, err := NewRSAEncryptor(&otherKey.PublicKey).Encrypt(data)
+	require.NoError(t, err)
+
+	tampered := append([]byte(nil), payload...)
+	tampered[len(tampered)-1] ^= 0xff
+
+	tests := []struct {
+		name     string
+		payload  []byte
+		expected []byte
+		wantErr  string
+	}{
+		{
+			name:     "valid",
+			payload:  payload,
+			expected: data,
+		},
+		{
+			name:    "encrypted for another key",
+			payload: foreign,
+			wantErr: "decrypt key error",
+		},
+		{
+			name:    "tampered",
+			payload: tampered,
+			wantErr: "decrypt payload error",
+		},
+		{
+			name:    "shorter than the key",
+			payload: payload[:key.Size()-1],
+			wantErr: "payload is too short",
+		},
+		{
+			name:    "missing nonce",
+			payload: payload[:key.Size()+4],
+			wantErr: "payload is too short",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decryptor := NewRSADecryptor(key)
+
+			result, err := decryptor.Decrypt(tt.payload)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Nil(t, result)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
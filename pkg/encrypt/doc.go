@@ -0,0 +1,15 @@
+// Package encrypt provides hybrid asymmetric encryption of request bodies.
+//
+// Payloads are sealed with a random AES-256-GCM key, which is in turn
+// encrypted with RSA-OAEP (SHA-256), so payloads of any size can be sent
+// to the owner of the private key. It provides:
+//   - Encryptor: Seals data with an RSA public key
+//   - Decryptor: Opens data with the matching RSA private key
+//   - PEM encoding, parsing and generation of RSA key pairs
+//
+// An encrypted payload is laid out as:
+//
+//	RSA-OAEP encrypted AES key | GCM nonce | AES-GCM ciphertext
+//
+// The encrypted key is as long as the RSA modulus, so no framing is needed.
+package encrypt
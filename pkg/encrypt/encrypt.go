@@ -0,0 +1,98 @@
+package encrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+)
+
+// Scheme names the hybrid encryption scheme of the package.
+// Sent by clients so that servers know how to decrypt request bodies.
+const Scheme = "rsa-oaep-aes256-gcm"
+
+// aesKeySize is the length of AES-256 keys in bytes.
+const aesKeySize = 32
+
+type (
+	// Encryptor defines the interface for encrypting payloads.
+	Encryptor interface {
+		// Encrypt seals the given data.
+		// data: Raw bytes to encrypt.
+		// Returns: encrypted payload or error if encryption fails.
+		Encrypt(data []byte) ([]byte, error)
+	}
+
+	// RSAEncryptor implements Encryptor with RSA-OAEP and AES-GCM.
+	// Every payload is sealed with a fresh AES key.
+	RSAEncryptor struct {
+		key *rsa.PublicKey
+	}
+)
+
+// NewRSAEncryptor creates a new hybrid encryptor with the provided public key.
+//
+// key: RSA public key of the payload recipient.
+//
+// Returns: Initialized Encryptor ready to seal payloads.
+// Payloads can be opened only by a Decryptor with the matching private key.
+func NewRSAEncryptor(key *rsa.PublicKey) Encryptor {
+	return &RSAEncryptor{
+		key: key,
+	}
+}
+
+// Encrypt seals data with a random AES-256-GCM key
+// and prepends the key encrypted with RSA-OAEP.
+//
+// data: Bytes to encrypt, any length.
+//
+// Returns:
+//   - []byte: Encrypted payload
+//   - error: If random generation or encryption fails
+//
+// Encryption is not deterministic: the same data produces different payloads.
+func (encryptor *RSAEncryptor) Encrypt(data []byte) ([]byte, error) {
+	key := make([]byte, aesKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generate key error: %w", err)
+	}
+
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, encryptor.key, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt key error: %w", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce error: %w", err)
+	}
+
+	payload := make([]byte, 0, len(encryptedKey)+len(nonce)+len(data)+gcm.Overhead())
+	payload = append(payload, encryptedKey...)
+	payload = append(payload, nonce...)
+
+	return gcm.Seal(payload, nonce, data, nil), nil
+}
+
+// newGCM creates an AES-GCM cipher with the given key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher error: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create gcm error: %w", err)
+	}
+
+	return gcm, nil
+}
@@ -0,0 +1,48 @@
+package encrypt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSAEncryptor_Encrypt(t *testing.T) {
+	key := newTestKey(t)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{
+			name: "regular data",
+			data: []byte(`{"id":"PollCount","type":"counter","delta":1}`),
+		},
+		{
+			name: "empty data",
+			data: []byte{},
+		},
+		{
+			name: "data larger than the rsa key",
+			data: bytes.Repeat([]byte("metrics"), 10_000),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encryptor := NewRSAEncryptor(&key.PublicKey)
+
+			first, err := encryptor.Encrypt(tt.data)
+			require.NoError(t, err)
+			second, err := encryptor.Encrypt(tt.data)
+			require.NoError(t, err)
+
+			assert.NotEqual(t, first, second)
+			assert.Len(t, first, key.Size()+12+len(tt.data)+16)
+			if len(tt.data) > 0 {
+				assert.False(t, bytes.Contains(first, tt.data))
+			}
+		})
+	}
+}
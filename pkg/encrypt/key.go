@@ -0,0 +1,109 @@
+package encrypt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// MinKeyBits is the smallest accepted RSA modulus size in bits.
+const MinKeyBits = 2048
+
+// GenerateKeyPair generates a new RSA key pair encoded as PEM.
+//
+// bits: RSA modulus size, at least MinKeyBits.
+//
+// Returns:
+//   - []byte: PKCS#8 private key in a "PRIVATE KEY" PEM block
+//   - []byte: PKIX public key in a "PUBLIC KEY" PEM block
+//   - error: If bits is too small or generation fails
+func GenerateKeyPair(bits int) ([]byte, []byte, error) {
+	if bits < MinKeyBits {
+		return nil, nil, fmt.Errorf("key size must be at least %d bits", MinKeyBits)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate key error: %w", err)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal private key error: %w", err)
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal public key error: %w", err)
+	}
+
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+
+	return privatePEM, publicPEM, nil
+}
+
+// ParsePublicKey parses an RSA public key from PEM.
+//
+// Both PKIX ("PUBLIC KEY") and PKCS#1 ("RSA PUBLIC KEY") blocks are accepted.
+func ParsePublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse public key error: %w", err)
+		}
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("public key is %T, not RSA", key)
+		}
+		return rsaKey, nil
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse public key error: %w", err)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
+	}
+}
+
+// ParsePrivateKey parses an RSA private key from PEM.
+//
+// Both PKCS#8 ("PRIVATE KEY") and PKCS#1 ("RSA PRIVATE KEY") blocks are accepted.
+func ParsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse private key error: %w", err)
+		}
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("private key is %T, not RSA", key)
+		}
+		return rsaKey, nil
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse private key error: %w", err)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
+	}
+}
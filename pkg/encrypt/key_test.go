@@ -0,0 +1,186 @@
+package encrypt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testKeyOnce sync.Once
+	testKey     *rsa.PrivateKey
+)
+
+// newTestKey returns an RSA key shared by tests, generation is slow.
+func newTestKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	testKeyOnce.Do(func() {
+		key, err := rsa.GenerateKey(rand.Reader, MinKeyBits)
+		require.NoError(t, err)
+		testKey = key
+	})
+	require.NotNil(t, testKey)
+	return testKey
+}
+
+func TestGenerateKeyPair(t *testing.T) {
+	tests := []struct {
+		name    string
+		bits    int
+		wantErr string
+	}{
+		{name: "valid", bits: MinKeyBits},
+		{name: "too small", bits: 1024, wantErr: "key size must be at least 2048 bits"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privatePEM, publicPEM, err := GenerateKeyPair(tt.bits)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			privateKey, err := ParsePrivateKey(privatePEM)
+			require.NoError(t, err)
+			publicKey, err := ParsePublicKey(publicPEM)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.bits, privateKey.N.BitLen())
+			assert.True(t, privateKey.PublicKey.Equal(publicKey))
+		})
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	key := newTestKey(t)
+
+	pkix, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecPKIX, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{
+			name: "pkix",
+			data: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkix}),
+		},
+		{
+			name: "pkcs1",
+			data: pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey)}),
+		},
+		{
+			name:    "not pem",
+			data:    []byte("public key"),
+			wantErr: "no PEM block found",
+		},
+		{
+			name:    "private key block",
+			data:    pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkix}),
+			wantErr: `unexpected PEM block type "PRIVATE KEY"`,
+		},
+		{
+			name:    "not rsa",
+			data:    pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: ecPKIX}),
+			wantErr: "public key is *ecdsa.PublicKey, not RSA",
+		},
+		{
+			name:    "corrupted",
+			data:    pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: []byte("corrupted")}),
+			wantErr: "parse public key error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publicKey, err := ParsePublicKey(tt.data)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Nil(t, publicKey)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, key.PublicKey.Equal(publicKey))
+		})
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key := newTestKey(t)
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecPKCS8, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{
+			name: "pkcs8",
+			data: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+		},
+		{
+			name: "pkcs1",
+			data: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		},
+		{
+			name:    "not pem",
+			data:    []byte("private key"),
+			wantErr: "no PEM block found",
+		},
+		{
+			name:    "public key block",
+			data:    pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkcs8}),
+			wantErr: `unexpected PEM block type "PUBLIC KEY"`,
+		},
+		{
+			name:    "not rsa",
+			data:    pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecPKCS8}),
+			wantErr: "private key is *ecdsa.PrivateKey, not RSA",
+		},
+		{
+			name:    "corrupted",
+			data:    pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte("corrupted")}),
+			wantErr: "parse private key error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privateKey, err := ParsePrivateKey(tt.data)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Nil(t, privateKey)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, key.Equal(privateKey))
+		})
+	}
+}
@@ -65,12 +65,20 @@ func Unauthorized(message string) *APIError {
 	return &APIError{Code: http.StatusUnauthorized, Message: message}
 }
 
+func Conflict(message string) *APIError {
+	return &APIError{Code: http.StatusConflict, Message: message}
+}
+
 // RespondError writes an API error response to the client.
 //
 // On error, responds with JSON body:
 //
 //	{ "error": "<message>" }
 //
+// or, for responses of versioned APIs (see VersionHeader):
+//
+//	{ "error": { "code": "<code>", "message": "<message>" } }
+//
 // HTTP status code is taken from APIError.Code.
 // Unknown errors are converted to 500 Internal Server Error.
 func RespondError(w http.ResponseWriter, err error) {
@@ -86,6 +94,14 @@ func RespondError(w http.ResponseWriter, err error) {
 
 	requestID := w.Header().Get("X-Request-ID")
 	slog.Info("error request handling", slog.Any("error", apiErr.Err), slog.String("message", apiErr.Message), slog.String("id", requestID))
+
+	if w.Header().Get(VersionHeader) != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(apiErr.Code)
+		json.NewEncoder(w).Encode(ErrorEnvelope{Error: ErrorBody{Code: apiErr.ErrorCode(), Message: apiErr.Message}})
+		return
+	}
+
 	w.WriteHeader(apiErr.Code)
 	json.NewEncoder(w).Encode(apiErr)
 }
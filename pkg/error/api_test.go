@@ -13,6 +13,7 @@ func TestRespondError(t *testing.T) {
 	tests := []struct {
 		name     string
 		err      error
+		version  string
 		wantCode int
 		wantBody string
 	}{
@@ -28,6 +29,20 @@ func TestRespondError(t *testing.T) {
 			wantCode: http.StatusInternalServerError,
 			wantBody: `{"error":"Internal server error"}` + "\n",
 		},
+		{
+			name:     "versioned APIError",
+			err:      NotFound("metric not found"),
+			version:  "v1",
+			wantCode: http.StatusNotFound,
+			wantBody: `{"error":{"code":"not_found","message":"metric not found"}}` + "\n",
+		},
+		{
+			name:     "versioned generic error",
+			err:      errors.New("some error"),
+			version:  "v1",
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"error":{"code":"internal_server_error","message":"Internal server error"}}` + "\n",
+		},
 		{
 			name:     "nil error",
 			err:      nil,
@@ -39,6 +54,9 @@ func TestRespondError(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			rr := httptest.NewRecorder()
+			if tt.version != "" {
+				rr.Header().Set(VersionHeader, tt.version)
+			}
 			RespondError(rr, tt.err)
 
 			assert.Equal(t, tt.wantCode, rr.Code)
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// VersionHeader names the API version of a response.
+//
+// Errors of responses carrying the header are written as ErrorEnvelope,
+// responses without it keep the unversioned { "error": "<message>" } body.
+const VersionHeader = "API-Version"
+
+// Envelope wraps successful responses of versioned APIs.
+//
+// swagger:model Envelope
+type Envelope struct {
+	// Response payload
+	Data any `json:"data"`
+}
+
+// ErrorEnvelope wraps error responses of versioned APIs.
+//
+// swagger:model ErrorEnvelope
+type ErrorEnvelope struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describes an error of a versioned API.
+//
+// swagger:model ErrorBody
+type ErrorBody struct {
+	// Machine-readable error code derived from the HTTP status
+	// example: not_found
+	Code string `json:"code"`
+	// Error message
+	// example: metric not found
+	Message string `json:"message"`
+}
+
+// ErrorCode returns the machine-readable code of the error,
+// the snake-cased HTTP status text (e.g. bad_request, not_found).
+func (e *APIError) ErrorCode() string {
+	text := http.StatusText(e.Code)
+	if text == "" {
+		return "unknown"
+	}
+
+	return strings.ReplaceAll(strings.ToLower(text), " ", "_")
+}
+
+// Respond writes a successful versioned API response.
+//
+// Responds with JSON body:
+//
+//	{ "data": <data> }
+//
+// Statuses without a body (204 No Content) are written without an envelope.
+func Respond(w http.ResponseWriter, status int, data any) {
+	if status == http.StatusNoContent {
+		w.WriteHeader(status)
+		return
+	}
+
+	body, err := json.Marshal(Envelope{Data: data})
+	if err != nil {
+		RespondError(w, Internal("Encode response failed", err))
+		return
+	}
+
+	w.WriteHeader(status)
+	w.Write(append(body, '\n'))
+}
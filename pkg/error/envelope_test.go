@@ -0,0 +1,81 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError_ErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *APIError
+		want string
+	}{
+		{name: "bad request", err: BadRequest("bad"), want: "bad_request"},
+		{name: "not found", err: NotFound("missing"), want: "not_found"},
+		{name: "conflict", err: Conflict("in progress"), want: "conflict"},
+		{name: "unprocessable entity", err: UnprocessibleEntity("invalid"), want: "unprocessable_entity"},
+		{name: "method not allowed", err: NotAllowed(), want: "method_not_allowed"},
+		{name: "internal", err: Internal("failed", nil), want: "internal_server_error"},
+		{name: "unknown status", err: New(599, "unknown", nil), want: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.err.ErrorCode())
+		})
+	}
+}
+
+func TestRespond(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		data     any
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "object",
+			status:   http.StatusOK,
+			data:     map[string]int{"accepted": 2},
+			wantCode: http.StatusOK,
+			wantBody: `{"data":{"accepted":2}}` + "\n",
+		},
+		{
+			name:     "nil data",
+			status:   http.StatusOK,
+			data:     nil,
+			wantCode: http.StatusOK,
+			wantBody: `{"data":null}` + "\n",
+		},
+		{
+			name:     "no content",
+			status:   http.StatusNoContent,
+			data:     "ignored",
+			wantCode: http.StatusNoContent,
+			wantBody: ``,
+		},
+		{
+			name:     "unencodable data",
+			status:   http.StatusOK,
+			data:     math.Inf(1),
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"error":"Encode response failed"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			Respond(rr, tt.status, tt.data)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, tt.wantBody, rr.Body.String())
+		})
+	}
+}
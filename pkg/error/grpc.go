@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcCodes maps HTTP status codes to gRPC status codes.
+var grpcCodes = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusMethodNotAllowed:    codes.Unimplemented,
+	http.StatusConflict:            codes.Aborted,
+	http.StatusUnprocessableEntity: codes.InvalidArgument,
+	http.StatusInternalServerError: codes.Internal,
+}
+
+// GRPCStatus converts the error to a gRPC status.
+//
+// gRPC servers call it for returned errors, so service errors
+// can be returned from RPC handlers as is.
+// Unknown HTTP status codes map to codes.Unknown.
+func (e *APIError) GRPCStatus() *status.Status {
+	code, exists := grpcCodes[e.Code]
+	if !exists {
+		code = codes.Unknown
+	}
+	return status.New(code, e.Message)
+}
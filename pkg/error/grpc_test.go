@@ -0,0 +1,37 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAPIError_GRPCStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *APIError
+		wantCode codes.Code
+	}{
+		{name: "bad request", err: BadRequest("bad request"), wantCode: codes.InvalidArgument},
+		{name: "unauthorized", err: Unauthorized("unauthorized"), wantCode: codes.Unauthenticated},
+		{name: "forbidden", err: Forbidden("forbidden"), wantCode: codes.PermissionDenied},
+		{name: "not found", err: NotFound("not found"), wantCode: codes.NotFound},
+		{name: "conflict", err: Conflict("in progress"), wantCode: codes.Aborted},
+		{name: "unprocessable entity", err: UnprocessibleEntity("invalid"), wantCode: codes.InvalidArgument},
+		{name: "internal", err: Internal("internal", errors.New("db error")), wantCode: codes.Internal},
+		{name: "unknown code", err: New(418, "teapot", nil), wantCode: codes.Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ok := status.FromError(tt.err)
+
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantCode, st.Code())
+			assert.Equal(t, tt.err.Message, st.Message())
+		})
+	}
+}
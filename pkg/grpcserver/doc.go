@@ -0,0 +1,7 @@
+// Package grpcserver provides a minimal gRPC server wrapper with
+// graceful shutdown support.
+//
+// The package encapsulates grpc.Server initialization,
+// configuration via functional options and controlled shutdown
+// using context cancellation, mirroring package httpserver.
+package grpcserver
@@ -0,0 +1,36 @@
+package grpcserver
+
+import (
+	"google.golang.org/grpc"
+)
+
+// Option represents a functional option for Server configuration.
+type Option func(*Server)
+
+// Address sets the server listen address.
+func Address(address string) Option {
+	return func(server *Server) {
+		server.address = address
+	}
+}
+
+// Service registers a service implementation on the server.
+func Service(desc *grpc.ServiceDesc, impl any) Option {
+	return func(server *Server) {
+		server.services = append(server.services, service{desc: desc, impl: impl})
+	}
+}
+
+// UnaryInterceptors appends interceptors applied to unary calls in the given order.
+func UnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) Option {
+	return func(server *Server) {
+		server.unary = append(server.unary, interceptors...)
+	}
+}
+
+// StreamInterceptors appends interceptors applied to streams in the given order.
+func StreamInterceptors(interceptors ...grpc.StreamServerInterceptor) Option {
+	return func(server *Server) {
+		server.stream = append(server.stream, interceptors...)
+	}
+}
@@ -0,0 +1,112 @@
+package grpcserver
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	// defaultAddress is used when no explicit server address is provided.
+	defaultAddress = "0.0.0.0:3200"
+
+	// shutdownTimeout bounds the graceful shutdown before calls are cancelled.
+	shutdownTimeout = 5 * time.Second
+)
+
+// service is a service implementation registered on the server.
+type service struct {
+	desc *grpc.ServiceDesc
+	impl any
+}
+
+// Server represents a gRPC server instance.
+type Server struct {
+	address  string
+	services []service
+	unary    []grpc.UnaryServerInterceptor
+	stream   []grpc.StreamServerInterceptor
+	server   *grpc.Server
+}
+
+// New creates a new Server instance configured with provided options.
+//
+// If no address is specified, defaultAddress is used.
+func New(options ...Option) *Server {
+
+	server := &Server{
+		address: defaultAddress,
+	}
+
+	for _, option := range options {
+		option(server)
+	}
+
+	server.server = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(server.unary...),
+		grpc.ChainStreamInterceptor(server.stream...),
+	)
+
+	for _, s := range server.services {
+		server.server.RegisterService(s.desc, s.impl)
+	}
+
+	return server
+}
+
+// Serve serves gRPC calls on an existing listener until it is closed.
+func (server *Server) Serve(listener net.Listener) error {
+	return server.server.Serve(listener)
+}
+
+// Run starts the gRPC server and blocks until context cancellation.
+//
+// The server listens on the configured address and performs a graceful
+// shutdown with a fixed timeout after context cancellation, cancelling
+// calls still running after the timeout.
+// The provided stop function is called if the server fails.
+func (server *Server) Run(ctx context.Context, stop context.CancelFunc) {
+	slog.Info("Starting gRPC server...", slog.String("address", server.address))
+
+	listener, err := net.Listen("tcp", server.address)
+	if err != nil {
+		slog.Error("gRPC server listen error", slog.String("error", err.Error()))
+		stop()
+		return
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		if err := server.server.Serve(listener); err != nil {
+			errChan <- err
+		}
+		close(errChan)
+	}()
+
+	select {
+	case err := <-errChan:
+		if err != nil {
+			slog.Error("gRPC server run error", slog.String("error", err.Error()))
+			stop()
+		}
+	case <-ctx.Done():
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		server.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		server.server.Stop()
+		slog.Error("gRPC server shutdown timed out, calls cancelled")
+	}
+
+	slog.Info("gRPC server stopped gracefully")
+}
@@ -0,0 +1,111 @@
+package grpcserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+func TestNew(t *testing.T) {
+	unary := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(ctx, req)
+	}
+	stream := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, ss)
+	}
+	desc := &grpc.ServiceDesc{ServiceName: "test.Service", HandlerType: (*any)(nil)}
+
+	tests := []struct {
+		name         string
+		options      []Option
+		wantAddr     string
+		wantServices []string
+		wantUnary    int
+		wantStream   int
+	}{
+		{
+			name:     "default options",
+			wantAddr: defaultAddress,
+		},
+		{
+			name:     "custom address",
+			options:  []Option{Address("127.0.0.1:9000")},
+			wantAddr: "127.0.0.1:9000",
+		},
+		{
+			name: "service and interceptors",
+			options: []Option{
+				Service(desc, struct{}{}),
+				UnaryInterceptors(unary, unary),
+				StreamInterceptors(stream),
+			},
+			wantAddr:     defaultAddress,
+			wantServices: []string{"test.Service"},
+			wantUnary:    2,
+			wantStream:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := New(tt.options...)
+
+			assert.Equal(t, tt.wantAddr, server.address)
+			assert.Len(t, server.unary, tt.wantUnary)
+			assert.Len(t, server.stream, tt.wantStream)
+
+			services := make([]string, 0)
+			for name := range server.server.GetServiceInfo() {
+				services = append(services, name)
+			}
+			assert.ElementsMatch(t, tt.wantServices, services)
+		})
+	}
+}
+
+func TestServer_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		address     string
+		wantStopped bool
+	}{
+		{
+			name:    "graceful shutdown",
+			address: "127.0.0.1:0",
+		},
+		{
+			name:        "listen error",
+			address:     "invalid-address",
+			wantStopped: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := New(Address(tt.address))
+
+			ctx, cancel := context.WithCancel(t.Context())
+			stopped := false
+			stop := func() { stopped = true }
+
+			done := make(chan struct{})
+			go func() {
+				server.Run(ctx, stop)
+				close(done)
+			}()
+
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("server did not stop")
+			}
+			assert.Equal(t, tt.wantStopped, stopped)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package httpclient
 
 import (
+	"bytes"
 	"io"
 	"net/http"
 	"net/url"
@@ -100,11 +101,13 @@ func buildURL(base string, params Params) string {
 
 // do executes an HTTP request with retries, headers and optional body.
 //
+// The body is read once up front, so every retry sends it in full.
+//
 // Internal method used by Get, Post, Put, Patch, Delete.
 func (c *Client) do(url string, method string, opts *RequestOptions) (*http.Response, error) {
 	var params Params
 	var headers Headers
-	var body io.Reader
+	var body []byte
 
 	if opts != nil {
 		if opts.Params != nil {
@@ -113,7 +116,13 @@ func (c *Client) do(url string, method string, opts *RequestOptions) (*http.Resp
 		if opts.Headers != nil {
 			headers = *opts.Headers
 		}
-		body = opts.Body
+		if opts.Body != nil {
+			data, err := io.ReadAll(opts.Body)
+			if err != nil {
+				return nil, err
+			}
+			body = data
+		}
 	}
 
 	fullURL := buildURL(url, params)
@@ -122,7 +131,12 @@ func (c *Client) do(url string, method string, opts *RequestOptions) (*http.Resp
 	var err error
 
 	for {
-		req, reqErr := http.NewRequest(method, fullURL, body)
+		var reqBody io.Reader
+		if body != nil {
+			reqBody = bytes.NewReader(body)
+		}
+
+		req, reqErr := http.NewRequest(method, fullURL, reqBody)
 		if reqErr != nil {
 			return nil, reqErr
 		}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -113,6 +114,7 @@ func TestClient_do(t *testing.T) {
 		clientHeaders *Headers
 		callHeaders   *Headers
 		params        *Params
+		body          string
 		maxRetries    int
 		respFilter    ResponseFilter
 		wantStatus    int
@@ -155,6 +157,27 @@ func TestClient_do(t *testing.T) {
 			wantStatus:    http.StatusCreated,
 			wantErr:       false,
 		},
+		{
+			name: "retry resends body",
+			serverHandler: func() http.HandlerFunc {
+				count := 0
+				return func(w http.ResponseWriter, r *http.Request) {
+					body, _ := io.ReadAll(r.Body)
+					assert.Equal(t, "payload", string(body))
+					if count == 0 {
+						count++
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					w.WriteHeader(http.StatusOK)
+				}
+			}(),
+			body:       "payload",
+			maxRetries: 2,
+			respFilter: func(resp *http.Response, err error) bool { return resp.StatusCode >= 500 },
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
 		{
 			name: "request fails after max retries",
 			serverHandler: func(w http.ResponseWriter, r *http.Request) {
@@ -209,6 +232,9 @@ func TestClient_do(t *testing.T) {
 				Headers: tt.callHeaders,
 				Body:    nil,
 			}
+			if tt.body != "" {
+				opts.Body = strings.NewReader(tt.body)
+			}
 
 			resp, err := c.do(testURL, http.MethodGet, opts)
 			if tt.wantErr {
@@ -0,0 +1,13 @@
+// Package interceptor provides gRPC interceptors mirroring the HTTP middleware.
+//
+// Server interceptors come in unary and stream flavours:
+//   - AuditContext: injects the source IP and timestamp, like middleware.AuditContext
+//   - TrustedSubnet: rejects callers outside of a trusted subnet
+//   - Tenant: resolves the request tenant, like middleware.Tenant
+//   - SignVerify: verifies HMAC-SHA256 signatures of requests, like middleware.SignVerify
+//
+// Client interceptors attach metadata such as tenant headers to outgoing calls.
+//
+// Signatures cover the payload built by SignPayload and are sent
+// in the HashKey metadata.
+package interceptor
@@ -0,0 +1,333 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	api "github.com/gabkaclassic/metrics/pkg/error"
+	"github.com/gabkaclassic/metrics/pkg/hash"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// HashKey is the metadata key carrying the request signature.
+	HashKey = "hash"
+
+	// RealIPKey is the metadata key carrying the client IP set by proxies or clients.
+	RealIPKey = "x-real-ip"
+
+	// forwardedForKey is the metadata key carrying the proxy chain.
+	forwardedForKey = "x-forwarded-for"
+)
+
+// contextStream is a server stream with a replaced context.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the replaced stream context.
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// UnaryAuditContext injects audit metadata into the unary call context.
+//
+// The interceptor takes the client IP from the first x-forwarded-for entry
+// or the peer address and stores it together with the call timestamp.
+func UnaryAuditContext() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(auditContext(ctx), req)
+	}
+}
+
+// StreamAuditContext injects audit metadata into the stream context.
+// See UnaryAuditContext.
+func StreamAuditContext() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: auditContext(ss.Context())})
+	}
+}
+
+// auditContext returns ctx carrying the caller IP and the current timestamp.
+func auditContext(ctx context.Context) context.Context {
+	ip := ""
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = p.Addr.String()
+	}
+	if xff := firstValue(ctx, forwardedForKey); xff != "" {
+		ip = strings.TrimSpace(strings.Split(xff, ",")[0])
+	}
+
+	return middleware.WithAuditSource(ctx, ip, time.Now().Unix())
+}
+
+// UnaryTrustedSubnet rejects unary calls from clients outside of subnet.
+//
+// subnet: Trusted subnet, nil disables the check
+//
+// The client IP is taken from the x-real-ip metadata or the peer address.
+// Calls from other addresses are rejected with PermissionDenied.
+func UnaryTrustedSubnet(subnet *net.IPNet) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := checkSubnet(ctx, subnet); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamTrustedSubnet rejects streams from clients outside of subnet.
+// See UnaryTrustedSubnet.
+func StreamTrustedSubnet(subnet *net.IPNet) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkSubnet(ss.Context(), subnet); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// checkSubnet reports an error if the caller is outside of subnet.
+func checkSubnet(ctx context.Context, subnet *net.IPNet) error {
+	if subnet == nil {
+		return nil
+	}
+
+	raw := firstValue(ctx, RealIPKey)
+	if raw == "" {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			raw = p.Addr.String()
+		}
+	}
+	if host, _, err := net.SplitHostPort(raw); err == nil {
+		raw = host
+	}
+
+	ip := net.ParseIP(raw)
+	if ip == nil || !subnet.Contains(ip) {
+		return api.Forbidden("Client is not in trusted subnet")
+	}
+
+	return nil
+}
+
+// UnaryTenant injects the call tenant into the unary call context.
+//
+// apiKeys: API key to tenant mapping
+//
+// The tenant is resolved from the x-api-key and x-tenant metadata
+// by the rules of middleware.Tenant.
+func UnaryTenant(apiKeys map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		tenantCtx, err := tenantContext(ctx, apiKeys)
+		if err != nil {
+			return nil, err
+		}
+		return handler(tenantCtx, req)
+	}
+}
+
+// StreamTenant injects the call tenant into the stream context.
+// See UnaryTenant.
+func StreamTenant(apiKeys map[string]string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		tenantCtx, err := tenantContext(ss.Context(), apiKeys)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: tenantCtx})
+	}
+}
+
+// tenantContext returns ctx carrying the resolved tenant.
+func tenantContext(ctx context.Context, apiKeys map[string]string) (context.Context, error) {
+	tenant, err := middleware.ResolveTenant(
+		apiKeys,
+		firstValue(ctx, middleware.APIKeyHeader),
+		firstValue(ctx, middleware.TenantHeader),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return middleware.WithTenant(ctx, tenant), nil
+}
+
+// UnarySignVerify verifies request signatures of unary calls.
+//
+// signKey: Secret key of the HMAC-SHA256 signature
+// methods: Full names of verified methods, other methods are not verified
+//
+// The signature is taken from the hash metadata and must cover
+// SignPayload of the request. Invalid signatures are rejected with InvalidArgument.
+func UnarySignVerify(signKey string, methods ...string) grpc.UnaryServerInterceptor {
+	verifier := hash.NewSHA256Verifier(signKey)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !slices.Contains(methods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		message, ok := req.(proto.Message)
+		if !ok {
+			return nil, api.Internal("Internal server error", errors.New("request is not a protobuf message"))
+		}
+
+		payload, err := SignPayload(message)
+		if err != nil {
+			return nil, api.Internal("Internal server error", err)
+		}
+
+		if !verifier.Verify(payload, firstValue(ctx, HashKey)) {
+			return nil, api.BadRequest("Data sign is invalid")
+		}
+		slog.Debug("Data sign verified successful")
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamSignVerify verifies request signatures of client streams.
+//
+// signKey: Secret key of the HMAC-SHA256 signature
+// methods: Full names of verified methods, other methods are not verified
+//
+// The signature is taken from the hash metadata and must cover
+// SignPayload of all messages sent on the stream. It is verified once
+// the client closes the stream: the handler receives an InvalidArgument
+// error instead of io.EOF if the signature is invalid.
+func StreamSignVerify(signKey string, methods ...string) grpc.StreamServerInterceptor {
+	verifier := hash.NewSHA256Verifier(signKey)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !slices.Contains(methods, info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		return handler(srv, &verifiedStream{
+			ServerStream: ss,
+			verifier:     verifier,
+			sign:         firstValue(ss.Context(), HashKey),
+		})
+	}
+}
+
+// verifiedStream accumulates received messages and verifies
+// their signature at the end of the stream.
+type verifiedStream struct {
+	grpc.ServerStream
+	verifier hash.Verifier
+	sign     string
+	payload  []byte
+}
+
+// RecvMsg receives a message and adds it to the signed payload.
+func (s *verifiedStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+
+	if errors.Is(err, io.EOF) {
+		if !s.verifier.Verify(s.payload, s.sign) {
+			return api.BadRequest("Data sign is invalid")
+		}
+		slog.Debug("Data sign verified successful")
+		return err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	message, ok := m.(proto.Message)
+	if !ok {
+		return api.Internal("Internal server error", errors.New("request is not a protobuf message"))
+	}
+
+	s.payload, err = appendPayload(s.payload, message)
+	if err != nil {
+		return api.Internal("Internal server error", err)
+	}
+
+	return nil
+}
+
+// SignPayload returns the signed representation of messages:
+// their deterministic encodings, each prefixed with its length.
+//
+// Returns:
+//   - []byte: Payload to sign or verify
+//   - error: Marshaling failure details
+func SignPayload(messages ...proto.Message) ([]byte, error) {
+	var payload []byte
+
+	for _, message := range messages {
+		var err error
+		payload, err = appendPayload(payload, message)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return payload, nil
+}
+
+// appendPayload appends the length-prefixed deterministic encoding of message.
+func appendPayload(payload []byte, message proto.Message) ([]byte, error) {
+	raw, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return protowire.AppendBytes(payload, raw), nil
+}
+
+// UnaryClientMetadata attaches metadata to outgoing unary calls.
+//
+// md: Metadata keys and values, such as tenant headers
+func UnaryClientMetadata(md map[string]string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingContext(ctx, md), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientMetadata attaches metadata to outgoing streams.
+// See UnaryClientMetadata.
+func StreamClientMetadata(md map[string]string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingContext(ctx, md), desc, cc, method, opts...)
+	}
+}
+
+// outgoingContext returns ctx with md appended to the outgoing metadata.
+func outgoingContext(ctx context.Context, md map[string]string) context.Context {
+	for key, value := range md {
+		ctx = metadata.AppendToOutgoingContext(ctx, key, value)
+	}
+	return ctx
+}
+
+// firstValue returns the first incoming metadata value of key.
+func firstValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
@@ -0,0 +1,371 @@
+package interceptor
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/gabkaclassic/metrics/pkg/hash"
+	"github.com/gabkaclassic/metrics/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const testMethod = "/test.Service/Method"
+
+// fakeStream is a server stream replaying messages.
+type fakeStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	messages []proto.Message
+}
+
+func (s *fakeStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeStream) RecvMsg(m any) error {
+	if len(s.messages) == 0 {
+		return io.EOF
+	}
+	proto.Merge(m.(proto.Message), s.messages[0])
+	s.messages = s.messages[1:]
+	return nil
+}
+
+// incomingContext returns a call context with the peer address and metadata.
+func incomingContext(addr string, md map[string]string) context.Context {
+	ctx := context.Background()
+	if addr != "" {
+		tcpAddr, _ := net.ResolveTCPAddr("tcp", addr)
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: tcpAddr})
+	}
+	return metadata.NewIncomingContext(ctx, metadata.New(md))
+}
+
+// unaryInfo returns server info of the test method.
+func unaryInfo() *grpc.UnaryServerInfo {
+	return &grpc.UnaryServerInfo{FullMethod: testMethod}
+}
+
+// streamInfo returns stream info of the test method.
+func streamInfo() *grpc.StreamServerInfo {
+	return &grpc.StreamServerInfo{FullMethod: testMethod, IsClientStream: true}
+}
+
+func TestUnaryAuditContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		md       map[string]string
+		expected string
+	}{
+		{
+			name:     "peer address",
+			expected: "10.0.0.1:5000",
+		},
+		{
+			name:     "forwarded for",
+			md:       map[string]string{"x-forwarded-for": "192.168.1.1, 10.0.0.2"},
+			expected: "192.168.1.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotIP string
+			var gotTS int64
+			handler := func(ctx context.Context, req any) (any, error) {
+				gotIP = middleware.AuditIPFromCtx(ctx)
+				gotTS = middleware.AuditTSFromCtx(ctx)
+				return nil, nil
+			}
+
+			_, err := UnaryAuditContext()(incomingContext("10.0.0.1:5000", tt.md), nil, unaryInfo(), handler)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, gotIP)
+			assert.NotZero(t, gotTS)
+		})
+	}
+}
+
+func TestStreamAuditContext(t *testing.T) {
+	var gotIP string
+	handler := func(srv any, ss grpc.ServerStream) error {
+		gotIP = middleware.AuditIPFromCtx(ss.Context())
+		return nil
+	}
+
+	stream := &fakeStream{ctx: incomingContext("10.0.0.1:5000", nil)}
+	err := StreamAuditContext()(nil, stream, streamInfo(), handler)
+
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1:5000", gotIP)
+}
+
+func TestUnaryTrustedSubnet(t *testing.T) {
+	_, subnet, err := net.ParseCIDR("10.0.0.0/24")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		subnet   *net.IPNet
+		addr     string
+		md       map[string]string
+		wantCode codes.Code
+	}{
+		{name: "check disabled", addr: "192.168.1.1:5000", wantCode: codes.OK},
+		{name: "peer in subnet", subnet: subnet, addr: "10.0.0.7:5000", wantCode: codes.OK},
+		{name: "peer outside subnet", subnet: subnet, addr: "192.168.1.1:5000", wantCode: codes.PermissionDenied},
+		{
+			name:     "real ip in subnet",
+			subnet:   subnet,
+			addr:     "192.168.1.1:5000",
+			md:       map[string]string{RealIPKey: "10.0.0.9"},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "invalid real ip",
+			subnet:   subnet,
+			addr:     "10.0.0.7:5000",
+			md:       map[string]string{RealIPKey: "not-an-ip"},
+			wantCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := func(ctx context.Context, req any) (any, error) {
+				called = true
+				return nil, nil
+			}
+
+			_, err := UnaryTrustedSubnet(tt.subnet)(incomingContext(tt.addr, tt.md), nil, unaryInfo(), handler)
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantCode == codes.OK, called)
+		})
+	}
+}
+
+func TestStreamTrustedSubnet(t *testing.T) {
+	_, subnet, err := net.ParseCIDR("10.0.0.0/24")
+	require.NoError(t, err)
+
+	handler := func(srv any, ss grpc.ServerStream) error { return nil }
+
+	err = StreamTrustedSubnet(subnet)(nil, &fakeStream{ctx: incomingContext("192.168.1.1:5000", nil)}, streamInfo(), handler)
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestUnaryTenant(t *testing.T) {
+	tests := []struct {
+		name         string
+		apiKeys      map[string]string
+		md           map[string]string
+		wantCode     codes.Code
+		expectTenant string
+	}{
+		{
+			name:         "default tenant",
+			wantCode:     codes.OK,
+			expectTenant: middleware.DefaultTenant,
+		},
+		{
+			name:         "tenant from metadata",
+			md:           map[string]string{middleware.TenantHeader: "team-a"},
+			wantCode:     codes.OK,
+			expectTenant: "team-a",
+		},
+		{
+			name:     "invalid tenant",
+			md:       map[string]string{middleware.TenantHeader: "team a/b"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:         "tenant from API key",
+			apiKeys:      map[string]string{"secret": "team-b"},
+			md:           map[string]string{middleware.APIKeyHeader: "secret"},
+			wantCode:     codes.OK,
+			expectTenant: "team-b",
+		},
+		{
+			name:     "unknown API key",
+			apiKeys:  map[string]string{"secret": "team-b"},
+			md:       map[string]string{middleware.APIKeyHeader: "wrong"},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotTenant string
+			handler := func(ctx context.Context, req any) (any, error) {
+				gotTenant = middleware.TenantFromCtx(ctx)
+				return nil, nil
+			}
+
+			_, err := UnaryTenant(tt.apiKeys)(incomingContext("", tt.md), nil, unaryInfo(), handler)
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.expectTenant, gotTenant)
+		})
+	}
+}
+
+func TestStreamTenant(t *testing.T) {
+	var gotTenant string
+	handler := func(srv any, ss grpc.ServerStream) error {
+		gotTenant = middleware.TenantFromCtx(ss.Context())
+		return nil
+	}
+
+	stream := &fakeStream{ctx: incomingContext("", map[string]string{middleware.TenantHeader: "team-a"})}
+	err := StreamTenant(nil)(nil, stream, streamInfo(), handler)
+
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", gotTenant)
+}
+
+func TestUnarySignVerify(t *testing.T) {
+	req := wrapperspb.String("payload")
+	payload, err := SignPayload(req)
+	require.NoError(t, err)
+	validSign := hash.NewSHA256Signer("secret").Sign(payload)
+
+	tests := []struct {
+		name     string
+		methods  []string
+		md       map[string]string
+		wantCode codes.Code
+	}{
+		{
+			name:     "valid sign",
+			methods:  []string{testMethod},
+			md:       map[string]string{HashKey: validSign},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "invalid sign",
+			methods:  []string{testMethod},
+			md:       map[string]string{HashKey: "invalid"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "missing sign",
+			methods:  []string{testMethod},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "method not verified",
+			wantCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := func(ctx context.Context, req any) (any, error) { return nil, nil }
+
+			_, err := UnarySignVerify("secret", tt.methods...)(incomingContext("", tt.md), req, unaryInfo(), handler)
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestStreamSignVerify(t *testing.T) {
+	messages := []proto.Message{wrapperspb.String("first"), wrapperspb.String("second")}
+	payload, err := SignPayload(messages...)
+	require.NoError(t, err)
+	validSign := hash.NewSHA256Signer("secret").Sign(payload)
+
+	tests := []struct {
+		name     string
+		sign     string
+		messages []proto.Message
+		wantCode codes.Code
+	}{
+		{name: "valid sign", sign: validSign, messages: messages, wantCode: codes.OK},
+		{name: "invalid sign", sign: "invalid", messages: messages, wantCode: codes.InvalidArgument},
+		{name: "messages reordered", sign: validSign, messages: []proto.Message{messages[1], messages[0]}, wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := 0
+			handler := func(srv any, ss grpc.ServerStream) error {
+				for {
+					err := ss.RecvMsg(&wrapperspb.StringValue{})
+					if err == io.EOF {
+						return nil
+					}
+					if err != nil {
+						return err
+					}
+					received++
+				}
+			}
+
+			stream := &fakeStream{
+				ctx:      incomingContext("", map[string]string{HashKey: tt.sign}),
+				messages: tt.messages,
+			}
+			err := StreamSignVerify("secret", testMethod)(nil, stream, streamInfo(), handler)
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, len(tt.messages), received)
+		})
+	}
+}
+
+func TestSignPayload(t *testing.T) {
+	single, err := SignPayload(wrapperspb.String("a"))
+	require.NoError(t, err)
+
+	joined, err := SignPayload(wrapperspb.String("a"), wrapperspb.String("b"))
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, single)
+	assert.Equal(t, single, joined[:len(single)])
+	assert.NotEqual(t, single, joined)
+}
+
+func TestUnaryClientMetadata(t *testing.T) {
+	var got metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		got, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+
+	err := UnaryClientMetadata(map[string]string{middleware.TenantHeader: "team-a"})(
+		context.Background(), testMethod, nil, nil, nil, invoker,
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a"}, got.Get(middleware.TenantHeader))
+}
+
+func TestStreamClientMetadata(t *testing.T) {
+	var got metadata.MD
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		got, _ = metadata.FromOutgoingContext(ctx)
+		return nil, nil
+	}
+
+	_, err := StreamClientMetadata(map[string]string{middleware.APIKeyHeader: "secret"})(
+		context.Background(), &grpc.StreamDesc{}, nil, testMethod, streamer,
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"secret"}, got.Get(middleware.APIKeyHeader))
+}
@@ -1,6 +1,9 @@
 // Package metric provides the core metric abstraction and implementations.
 //
-// The package defines two metric types:
+// The package defines five metric types:
 //   - Gauge: Represents a value that can go up and down (e.g., memory usage)
 //   - Counter: Represents a monotonically increasing value (e.g., request count)
+//   - Histogram: Represents a distribution of observations (e.g., latencies)
+//   - Summary: Represents quantiles of observations estimated with a sketch
+//   - Set: Represents the number of distinct members (e.g., unique users)
 package metric
@@ -50,6 +50,7 @@ func (metric *RandomValue) Update() {
 type RuntimeGaugeMetric struct {
 	GaugeMetric
 	name string
+	unit string
 }
 
 // Update executes the value function to refresh the metric.
@@ -62,6 +63,17 @@ func (metric *RuntimeGaugeMetric) Name() string {
 	return metric.name
 }
 
+// Unit returns the runtime gauge's unit, empty if not declared.
+func (metric *RuntimeGaugeMetric) Unit() string {
+	return metric.unit
+}
+
+// WithUnit declares the unit of the gauge values and returns the gauge.
+func (metric *RuntimeGaugeMetric) WithUnit(unit string) *RuntimeGaugeMetric {
+	metric.unit = unit
+	return metric
+}
+
 // NewRuntimeGaugeMetric creates a new RuntimeGaugeMetric.
 //
 // name: The metric identifier
@@ -84,6 +96,8 @@ func NewRuntimeGaugeMetric(name string, value ValueFunctionType) *RuntimeGaugeMe
 //   - TotalMemory: Total system memory in bytes
 //   - FreeMemory: Available system memory in bytes
 //   - CPUutilization1: Current CPU utilization percentage
+//
+// Every metric declares its unit.
 func PsMetrics(getMem func() *mem.VirtualMemoryStat, getCPU func() *[]float64) []Metric {
 	return []Metric{
 		NewRuntimeGaugeMetric("TotalMemory", func() float64 {
@@ -92,21 +106,21 @@ func PsMetrics(getMem func() *mem.VirtualMemoryStat, getCPU func() *[]float64) [
 				return 0
 			}
 			return float64(m.Total)
-		}),
+		}).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric("FreeMemory", func() float64 {
 			m := getMem()
 			if m == nil {
 				return 0
 			}
 			return float64(m.Free)
-		}),
+		}).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric("CPUutilization1", func() float64 {
 			cpu := getCPU()
 			if cpu == nil || len(*cpu) == 0 {
 				return 0
 			}
 			return (*cpu)[0]
-		}),
+		}).WithUnit(UnitPercent),
 	}
 }
 
@@ -120,100 +134,102 @@ func PsMetrics(getMem func() *mem.VirtualMemoryStat, getCPU func() *[]float64) [
 //   - NumGC: Number of completed GC cycles
 //   - GCCPUFraction: CPU time used by GC
 //   - And other runtime statistics
+//
+// Every metric declares its unit.
 func RuntimeMetrics(stats *runtime.MemStats) []Metric {
 	return []Metric{
 		NewRuntimeGaugeMetric(
 			"Alloc", func() float64 { return float64(stats.Alloc) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"BuckHashSys", func() float64 { return float64(stats.BuckHashSys) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"Frees", func() float64 { return float64(stats.Frees) },
-		),
+		).WithUnit(UnitCount),
 		NewRuntimeGaugeMetric(
 			"GCCPUFraction", func() float64 { return float64(stats.GCCPUFraction) },
-		),
+		).WithUnit(UnitRatio),
 		NewRuntimeGaugeMetric(
 			"GCSys", func() float64 { return float64(stats.Alloc) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"HeapAlloc", func() float64 { return float64(stats.HeapAlloc) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"HeapIdle", func() float64 { return float64(stats.HeapIdle) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"HeapInuse", func() float64 { return float64(stats.HeapInuse) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"HeapObjects", func() float64 { return float64(stats.HeapObjects) },
-		),
+		).WithUnit(UnitObjects),
 		NewRuntimeGaugeMetric(
 			"HeapReleased", func() float64 { return float64(stats.HeapReleased) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"HeapSys", func() float64 { return float64(stats.HeapSys) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"LastGC", func() float64 { return float64(stats.LastGC) },
-		),
+		).WithUnit(UnitNanoseconds),
 		NewRuntimeGaugeMetric(
 			"Lookups", func() float64 { return float64(stats.Lookups) },
-		),
+		).WithUnit(UnitCount),
 		NewRuntimeGaugeMetric(
 			"MCacheInuse", func() float64 { return float64(stats.MCacheInuse) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"MCacheSys", func() float64 { return float64(stats.MCacheSys) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"MCacheInuse", func() float64 { return float64(stats.MCacheInuse) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"MSpanInuse", func() float64 { return float64(stats.MSpanInuse) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"MSpanSys", func() float64 { return float64(stats.MSpanSys) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"Mallocs", func() float64 { return float64(stats.Mallocs) },
-		),
+		).WithUnit(UnitCount),
 		NewRuntimeGaugeMetric(
 			"NextGC", func() float64 { return float64(stats.NextGC) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"NumForcedGC", func() float64 { return float64(stats.NumForcedGC) },
-		),
+		).WithUnit(UnitCount),
 		NewRuntimeGaugeMetric(
 			"NumGC", func() float64 { return float64(stats.NumGC) },
-		),
+		).WithUnit(UnitCount),
 		NewRuntimeGaugeMetric(
 			"NumForcedGC", func() float64 { return float64(stats.NumForcedGC) },
-		),
+		).WithUnit(UnitCount),
 		NewRuntimeGaugeMetric(
 			"OtherSys", func() float64 { return float64(stats.OtherSys) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"PauseTotalNs", func() float64 { return float64(stats.PauseTotalNs) },
-		),
+		).WithUnit(UnitNanoseconds),
 		NewRuntimeGaugeMetric(
 			"NumForcedGC", func() float64 { return float64(stats.NumForcedGC) },
-		),
+		).WithUnit(UnitCount),
 		NewRuntimeGaugeMetric(
 			"StackInuse", func() float64 { return float64(stats.StackInuse) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"StackSys", func() float64 { return float64(stats.StackSys) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"Sys", func() float64 { return float64(stats.Sys) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"StackInuse", func() float64 { return float64(stats.StackInuse) },
-		),
+		).WithUnit(UnitBytes),
 		NewRuntimeGaugeMetric(
 			"TotalAlloc", func() float64 { return float64(stats.TotalAlloc) },
-		),
+		).WithUnit(UnitBytes),
 	}
 }
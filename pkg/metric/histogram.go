@@ -0,0 +1,113 @@
+package metric
+
+import (
+	"slices"
+	"sync"
+)
+
+// HistogramValue is a snapshot of a histogram state returned by Value.
+type HistogramValue struct {
+	Bounds  []float64
+	Buckets []int64
+	Sum     float64
+	Count   int64
+}
+
+// HistogramMetric counts observations into buckets with fixed upper bounds.
+// Safe for concurrent use.
+type HistogramMetric struct {
+	mu      sync.Mutex
+	name    string
+	bounds  []float64
+	buckets []int64
+	sum     float64
+	count   int64
+}
+
+// NewHistogramMetric creates a new HistogramMetric.
+//
+// name: The metric identifier
+// bounds: Upper bucket bounds in ascending order; an extra bucket
+// for values above the highest bound is added automatically
+func NewHistogramMetric(name string, bounds []float64) *HistogramMetric {
+	return &HistogramMetric{
+		name:    name,
+		bounds:  slices.Clone(bounds),
+		buckets: make([]int64, len(bounds)+1),
+	}
+}
+
+// Type returns HistogramType for HistogramMetric instances.
+func (metric *HistogramMetric) Type() MetricType {
+	return HistogramType
+}
+
+// Name returns the histogram's name.
+func (metric *HistogramMetric) Name() string {
+	return metric.name
+}
+
+// Update does nothing: histograms change only through Observe.
+func (metric *HistogramMetric) Update() {}
+
+// Observe records a single value into the matching bucket.
+func (metric *HistogramMetric) Observe(value float64) {
+	index, _ := slices.BinarySearch(metric.bounds, value)
+
+	metric.mu.Lock()
+	defer metric.mu.Unlock()
+
+	metric.buckets[index]++
+	metric.sum += value
+	metric.count++
+}
+
+// Value returns a HistogramValue snapshot of the current state.
+func (metric *HistogramMetric) Value() any {
+	metric.mu.Lock()
+	defer metric.mu.Unlock()
+
+	return metric.snapshot()
+}
+
+// Drain returns a HistogramValue snapshot of the current state
+// and resets the histogram to no observations.
+func (metric *HistogramMetric) Drain() any {
+	metric.mu.Lock()
+	defer metric.mu.Unlock()
+
+	value := metric.snapshot()
+	clear(metric.buckets)
+	metric.sum = 0
+	metric.count = 0
+
+	return value
+}
+
+// Restore adds a HistogramValue returned by Drain back to the histogram.
+// Values with other bounds are ignored.
+func (metric *HistogramMetric) Restore(value any) {
+	drained, ok := value.(HistogramValue)
+	if !ok || !slices.Equal(drained.Bounds, metric.bounds) || len(drained.Buckets) != len(metric.buckets) {
+		return
+	}
+
+	metric.mu.Lock()
+	defer metric.mu.Unlock()
+
+	for i, count := range drained.Buckets {
+		metric.buckets[i] += count
+	}
+	metric.sum += drained.Sum
+	metric.count += drained.Count
+}
+
+// snapshot copies the current state, the caller must hold mu.
+func (metric *HistogramMetric) snapshot() HistogramValue {
+	return HistogramValue{
+		Bounds:  slices.Clone(metric.bounds),
+		Buckets: slices.Clone(metric.buckets),
+		Sum:     metric.sum,
+		Count:   metric.count,
+	}
+}
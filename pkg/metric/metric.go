@@ -4,13 +4,16 @@ package metric
 type MetricType string
 
 const (
-	GaugeType   MetricType = "gauge"   // Gauge metric type
-	CounterType MetricType = "counter" // Counter metric type
+	GaugeType     MetricType = "gauge"     // Gauge metric type
+	CounterType   MetricType = "counter"   // Counter metric type
+	HistogramType MetricType = "histogram" // Histogram metric type
+	SummaryType   MetricType = "summary"   // Summary metric type
+	SetType       MetricType = "set"       // Set metric type
 )
 
 // Metric is the interface that all metrics must implement.
 type Metric interface {
-	// Type returns the metric type (gauge or counter).
+	// Type returns the metric type (gauge, counter, histogram, summary or set).
 	Type() MetricType
 
 	// Name returns the unique identifier of the metric.
@@ -22,3 +25,44 @@ type Metric interface {
 	// Value returns the current metric value as interface{}.
 	Value() any
 }
+
+// Units of metric values declared by the built-in collectors.
+const (
+	UnitBytes       = "bytes"
+	UnitCount       = "count"
+	UnitObjects     = "objects"
+	UnitRatio       = "ratio"
+	UnitPercent     = "percent"
+	UnitNanoseconds = "nanoseconds"
+)
+
+// Described is implemented by metrics that declare the unit of their values.
+// The unit is reported along with the value and registered as metric metadata
+// by the server on first report.
+type Described interface {
+	// Unit returns the unit of the metric values, e.g. "bytes".
+	Unit() string
+}
+
+// UnitOf returns the declared unit of the metric, or "" if it declares none.
+func UnitOf(m Metric) string {
+	if described, ok := m.(Described); ok {
+		return described.Unit()
+	}
+	return ""
+}
+
+// Drainable is implemented by metrics whose observations are merged
+// into the stored value by the server, such as histograms and summaries.
+// Such metrics must report only observations made since the previous report,
+// otherwise every observation would be counted again on each report.
+type Drainable interface {
+	// Drain returns the current value like Value and resets the metric,
+	// atomically with respect to concurrent observations.
+	Drain() any
+
+	// Restore merges a value returned by Drain back into the metric,
+	// so that observations of a failed report are sent with the next one.
+	// Values of another metric shape are ignored.
+	Restore(value any)
+}
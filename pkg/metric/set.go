@@ -0,0 +1,65 @@
+package metric
+
+import (
+	"sync"
+
+	"github.com/gabkaclassic/metrics/pkg/sketch"
+)
+
+// SetMetric estimates the number of distinct members with a HyperLogLog.
+// Safe for concurrent use.
+type SetMetric struct {
+	mu   sync.Mutex
+	name string
+	hll  *sketch.HyperLogLog
+}
+
+// NewSetMetric creates a new SetMetric.
+//
+// name: The metric identifier
+// precision: HyperLogLog precision, in [sketch.MinPrecision, sketch.MaxPrecision]
+//
+// Returns:
+//   - *SetMetric: Set without members
+//   - error: If precision is out of range
+func NewSetMetric(name string, precision uint8) (*SetMetric, error) {
+	hll, err := sketch.NewHyperLogLog(precision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SetMetric{
+		name: name,
+		hll:  hll,
+	}, nil
+}
+
+// Type returns SetType for SetMetric instances.
+func (metric *SetMetric) Type() MetricType {
+	return SetType
+}
+
+// Name returns the set's name.
+func (metric *SetMetric) Name() string {
+	return metric.name
+}
+
+// Update does nothing: sets change only through Add.
+func (metric *SetMetric) Update() {}
+
+// Add records a member of the set.
+func (metric *SetMetric) Add(member string) {
+	metric.mu.Lock()
+	defer metric.mu.Unlock()
+
+	metric.hll.Add(member)
+}
+
+// Value returns the serialized HyperLogLog as []byte.
+func (metric *SetMetric) Value() any {
+	metric.mu.Lock()
+	defer metric.mu.Unlock()
+
+	encoded, _ := metric.hll.MarshalBinary()
+	return encoded
+}
@@ -0,0 +1,95 @@
+package metric
+
+import (
+	"sync"
+
+	"github.com/gabkaclassic/metrics/pkg/sketch"
+)
+
+// SummaryMetric estimates quantiles of observations with a DDSketch.
+// Safe for concurrent use.
+type SummaryMetric struct {
+	mu     sync.Mutex
+	name   string
+	sketch *sketch.DDSketch
+}
+
+// NewSummaryMetric creates a new SummaryMetric.
+//
+// name: The metric identifier
+// relativeAccuracy: Relative accuracy of quantile estimates, in (0, 1)
+//
+// Returns:
+//   - *SummaryMetric: Summary without observations
+//   - error: If relativeAccuracy is out of range
+func NewSummaryMetric(name string, relativeAccuracy float64) (*SummaryMetric, error) {
+	s, err := sketch.NewDDSketch(relativeAccuracy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SummaryMetric{
+		name:   name,
+		sketch: s,
+	}, nil
+}
+
+// Type returns SummaryType for SummaryMetric instances.
+func (metric *SummaryMetric) Type() MetricType {
+	return SummaryType
+}
+
+// Name returns the summary's name.
+func (metric *SummaryMetric) Name() string {
+	return metric.name
+}
+
+// Update does nothing: summaries change only through Observe.
+func (metric *SummaryMetric) Update() {}
+
+// Observe records a single value into the sketch.
+func (metric *SummaryMetric) Observe(value float64) {
+	metric.mu.Lock()
+	defer metric.mu.Unlock()
+
+	metric.sketch.Add(value)
+}
+
+// Value returns the serialized sketch as []byte.
+func (metric *SummaryMetric) Value() any {
+	metric.mu.Lock()
+	defer metric.mu.Unlock()
+
+	encoded, _ := metric.sketch.MarshalBinary()
+	return encoded
+}
+
+// Drain returns the serialized sketch as []byte
+// and resets the sketch to no observations.
+func (metric *SummaryMetric) Drain() any {
+	metric.mu.Lock()
+	defer metric.mu.Unlock()
+
+	encoded, _ := metric.sketch.MarshalBinary()
+	metric.sketch.Reset()
+	return encoded
+}
+
+// Restore merges a serialized sketch returned by Drain back into the summary.
+// Values that are not sketches of the same accuracy are ignored.
+func (metric *SummaryMetric) Restore(value any) {
+	encoded, ok := value.([]byte)
+	if !ok {
+		return
+	}
+
+	var drained sketch.DDSketch
+	if err := drained.UnmarshalBinary(encoded); err != nil {
+		return
+	}
+
+	metric.mu.Lock()
+	defer metric.mu.Unlock()
+
+	_ = metric.sketch.Merge(&drained)
+}
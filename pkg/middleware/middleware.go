@@ -2,10 +2,13 @@
 //
 // The package contains composable middleware functions used to:
 //   - Validate request integrity (signature verification)
+//   - Decrypt request bodies encrypted with the server public key
 //   - Compress and decompress HTTP bodies
 //   - Enforce and set Content-Type headers
 //   - Log incoming HTTP requests
 //   - Inject audit metadata into request context
+//   - Resolve the request tenant into request context
+//   - Mark responses of versioned APIs
 //
 // Middlewares are designed to be combined using Wrap.
 package middleware
@@ -13,13 +16,16 @@ package middleware
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/gabkaclassic/metrics/pkg/compress"
+	"github.com/gabkaclassic/metrics/pkg/encrypt"
 	api "github.com/gabkaclassic/metrics/pkg/error"
 	"github.com/gabkaclassic/metrics/pkg/hash"
 	"github.com/google/uuid"
@@ -41,10 +47,15 @@ type (
 
 const (
 	// Supported content types.
-	JSON     ContentType = "application/json"
-	TEXT     ContentType = "text/plain; charset=utf-8"
-	HTML     ContentType = "text/html"
-	HTMLUTF8 ContentType = "text/html; charset=utf-8"
+	JSON        ContentType = "application/json"
+	TEXT        ContentType = "text/plain; charset=utf-8"
+	HTML        ContentType = "text/html"
+	HTMLUTF8    ContentType = "text/html; charset=utf-8"
+	PROMETHEUS  ContentType = "text/plain; version=0.0.4; charset=utf-8"
+	EVENTSTREAM ContentType = "text/event-stream"
+	CSS         ContentType = "text/css; charset=utf-8"
+	CSV         ContentType = "text/csv; charset=utf-8"
+	NDJSON      ContentType = "application/x-ndjson"
 
 	// Supported compression types.
 	GZIP CompressType = "gzip"
@@ -52,8 +63,26 @@ const (
 	// Context keys used for audit metadata.
 	ctxIPKey ContextKey = "sourceIP"
 	ctxTSKey ContextKey = "ts"
+
+	// Context key used for the request tenant.
+	ctxTenantKey ContextKey = "tenant"
+
+	// DefaultTenant owns metrics of requests without a tenant.
+	DefaultTenant = "default"
+
+	// TenantHeader names the request tenant when no API keys are configured.
+	TenantHeader = "X-Tenant"
+
+	// APIKeyHeader carries the API key identifying the request tenant.
+	APIKeyHeader = "X-API-Key"
+
+	// EncryptionHeader names the encryption scheme of an encrypted request body.
+	EncryptionHeader = "X-Encryption"
 )
 
+// tenantPattern restricts tenant names to URL- and label-safe identifiers.
+var tenantPattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,64}$`)
+
 var compressors = map[CompressType]func(http.ResponseWriter) (*compress.CompressWriter, error){
 	GZIP: compress.NewGzipWriter,
 }
@@ -174,6 +203,55 @@ func Decompress() middleware {
 	}
 }
 
+// Decrypt returns a middleware that decrypts request bodies.
+//
+// Bodies marked with the X-Encryption header are decrypted before being passed
+// to the next handler, so the middleware must run before Decompress.
+// Requests without the header are passed through unchanged.
+// Unsupported schemes, undecryptable bodies and encrypted bodies sent
+// to a server without a private key are rejected with 400 status.
+func Decrypt(decryptor encrypt.Decryptor) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			scheme := r.Header.Get(EncryptionHeader)
+			if scheme == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if scheme != encrypt.Scheme {
+				api.RespondError(w, api.BadRequest(fmt.Sprintf("Unsupported encryption scheme: %s", scheme)))
+				return
+			}
+
+			if decryptor == nil {
+				api.RespondError(w, api.BadRequest("Encrypted bodies are not accepted"))
+				return
+			}
+
+			bodyBytes, err := io.ReadAll(r.Body)
+			if err != nil {
+				api.RespondError(w, api.Internal("Internal server error", err))
+				return
+			}
+
+			plain, err := decryptor.Decrypt(bodyBytes)
+			if err != nil {
+				slog.Debug("Data decryption failed", slog.String("error", err.Error()))
+				api.RespondError(w, api.BadRequest("Data decryption failed"))
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(plain))
+			r.ContentLength = int64(len(plain))
+			r.Header.Del(EncryptionHeader)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // Wrap applies a chain of middlewares to an HTTP handler.
 //
 // Middlewares are applied in the order they are provided.
@@ -194,6 +272,19 @@ func WithContentType(ct ContentType) middleware {
 	}
 }
 
+// APIVersion returns a middleware that marks responses with the API version.
+//
+// The version is sent in the API-Version header,
+// errors of marked responses are written in the versioned envelope format.
+func APIVersion(version string) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(api.VersionHeader, version)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // RequireContentType returns a middleware that enforces request Content-Type.
 //
 // Requests with a mismatched Content-Type are rejected with 400 status.
@@ -273,13 +364,19 @@ func AuditContext(next http.Handler) http.Handler {
 			ip = strings.TrimSpace(strings.Split(xff, ",")[0])
 		}
 
-		ctx := context.WithValue(r.Context(), ctxIPKey, ip)
-		ctx = context.WithValue(ctx, ctxTSKey, time.Now().Unix())
+		ctx := WithAuditSource(r.Context(), ip, time.Now().Unix())
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// WithAuditSource returns a copy of ctx carrying audit metadata.
+// Used by non-HTTP callers such as the StatsD listener.
+func WithAuditSource(ctx context.Context, ip string, ts int64) context.Context {
+	ctx = context.WithValue(ctx, ctxIPKey, ip)
+	return context.WithValue(ctx, ctxTSKey, ts)
+}
+
 // AuditIPFromCtx extracts the source IP address from context.
 //
 // Returns empty string if the value is not present.
@@ -299,3 +396,73 @@ func AuditTSFromCtx(ctx context.Context) int64 {
 	}
 	return 0
 }
+
+// Tenant injects the request tenant into the request context.
+//
+// apiKeys: API key to tenant mapping.
+//
+// With API keys configured, the tenant is resolved from the X-API-Key header
+// and requests without a known key are rejected with 401 Unauthorized.
+// Otherwise the tenant is taken from the X-Tenant header,
+// falling back to DefaultTenant. Invalid tenant names are rejected
+// with 400 Bad Request.
+func Tenant(apiKeys map[string]string) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenant, err := ResolveTenant(apiKeys, r.Header.Get(APIKeyHeader), r.Header.Get(TenantHeader))
+			if err != nil {
+				api.RespondError(w, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithTenant(r.Context(), tenant)))
+		})
+	}
+}
+
+// ResolveTenant resolves the tenant of a request.
+//
+// apiKeys: API key to tenant mapping
+// apiKey: API key sent with the request
+// requested: Tenant requested explicitly, empty for the default tenant
+//
+// Returns:
+//   - string: Resolved tenant
+//   - *api.APIError: Unauthorized for unknown API keys, BadRequest for invalid tenant names
+//
+// Shared by the HTTP middleware and gRPC interceptors.
+func ResolveTenant(apiKeys map[string]string, apiKey string, requested string) (string, *api.APIError) {
+	tenant := DefaultTenant
+
+	if len(apiKeys) > 0 {
+		keyTenant, exists := apiKeys[apiKey]
+		if !exists {
+			return "", api.Unauthorized("API key is invalid")
+		}
+		tenant = keyTenant
+	} else if requested != "" {
+		tenant = requested
+	}
+
+	if !tenantPattern.MatchString(tenant) {
+		return "", api.BadRequest(fmt.Sprintf("invalid tenant: %s", tenant))
+	}
+
+	return tenant, nil
+}
+
+// WithTenant returns a copy of ctx carrying the tenant.
+// Used by non-HTTP callers such as dump restoration.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, ctxTenantKey, tenant)
+}
+
+// TenantFromCtx extracts the tenant from context.
+//
+// Returns DefaultTenant if the value is not present.
+func TenantFromCtx(ctx context.Context) string {
+	if v, ok := ctx.Value(ctxTenantKey).(string); ok && v != "" {
+		return v
+	}
+	return DefaultTenant
+}
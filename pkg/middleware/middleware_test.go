@@ -13,6 +13,8 @@ import (
 	"time"
 
 	"github.com/gabkaclassic/metrics/pkg/compress"
+	"github.com/gabkaclassic/metrics/pkg/encrypt"
+	api "github.com/gabkaclassic/metrics/pkg/error"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -195,6 +197,47 @@ func TestWithContentType(t *testing.T) {
 	}
 }
 
+func TestAPIVersion(t *testing.T) {
+	tests := []struct {
+		name         string
+		handler      http.HandlerFunc
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "successful response",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: "",
+		},
+		{
+			name: "error response is enveloped",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				api.RespondError(w, api.BadRequest("Invalid content type"))
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `{"error":{"code":"bad_request","message":"Invalid content type"}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := APIVersion("v1")(tt.handler)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rr := httptest.NewRecorder()
+
+			wrapped.ServeHTTP(rr, req)
+
+			assert.Equal(t, "v1", rr.Header().Get(api.VersionHeader))
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
 func TestLogger(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -492,6 +535,86 @@ func TestAuditTSFromCtx(t *testing.T) {
 	}
 }
 
+func TestWithAuditSource(t *testing.T) {
+	ctx := WithAuditSource(context.Background(), "10.0.0.1", 1672531200)
+
+	assert.Equal(t, "10.0.0.1", AuditIPFromCtx(ctx))
+	assert.Equal(t, int64(1672531200), AuditTSFromCtx(ctx))
+}
+
+func TestTenant(t *testing.T) {
+	tests := []struct {
+		name         string
+		apiKeys      map[string]string
+		headers      map[string]string
+		expectStatus int
+		expectTenant string
+	}{
+		{
+			name:         "default tenant without header",
+			expectStatus: http.StatusOK,
+			expectTenant: DefaultTenant,
+		},
+		{
+			name:         "tenant from header",
+			headers:      map[string]string{TenantHeader: "team-a"},
+			expectStatus: http.StatusOK,
+			expectTenant: "team-a",
+		},
+		{
+			name:         "invalid tenant header",
+			headers:      map[string]string{TenantHeader: "team a/b"},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "tenant from API key",
+			apiKeys:      map[string]string{"secret": "team-b"},
+			headers:      map[string]string{APIKeyHeader: "secret", TenantHeader: "team-a"},
+			expectStatus: http.StatusOK,
+			expectTenant: "team-b",
+		},
+		{
+			name:         "unknown API key",
+			apiKeys:      map[string]string{"secret": "team-b"},
+			headers:      map[string]string{APIKeyHeader: "wrong"},
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "missing API key",
+			apiKeys:      map[string]string{"secret": "team-b"},
+			headers:      map[string]string{TenantHeader: "team-a"},
+			expectStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotTenant string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotTenant = TenantFromCtx(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rr := httptest.NewRecorder()
+
+			Tenant(tt.apiKeys)(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+			assert.Equal(t, tt.expectTenant, gotTenant)
+		})
+	}
+}
+
+func TestTenantFromCtx(t *testing.T) {
+	assert.Equal(t, DefaultTenant, TenantFromCtx(context.Background()))
+	assert.Equal(t, DefaultTenant, TenantFromCtx(WithTenant(context.Background(), "")))
+	assert.Equal(t, "team-a", TenantFromCtx(WithTenant(context.Background(), "team-a")))
+}
+
 func TestCompressMiddleware(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -661,3 +784,109 @@ func TestDecompressMiddleware(t *testing.T) {
 		})
 	}
 }
+
+// decryptorFunc adapts a function to encrypt.Decryptor.
+type decryptorFunc func(data []byte) ([]byte, error)
+
+func (f decryptorFunc) Decrypt(data []byte) ([]byte, error) { return f(data) }
+
+func TestDecrypt(t *testing.T) {
+	reverse := decryptorFunc(func(data []byte) ([]byte, error) {
+		if string(data) == "broken" {
+			return nil, errors.New("boom")
+		}
+		result := make([]byte, len(data))
+		for i, b := range data {
+			result[len(data)-1-i] = b
+		}
+		return result, nil
+	})
+
+	tests := []struct {
+		name           string
+		scheme         string
+		decryptor      encrypt.Decryptor
+		body           string
+		expectBody     string
+		expectStatus   int
+		expectNextCall bool
+	}{
+		{
+			name:           "encrypted body decrypted",
+			scheme:         encrypt.Scheme,
+			decryptor:      reverse,
+			body:           "daolyap",
+			expectBody:     "payload",
+			expectStatus:   http.StatusOK,
+			expectNextCall: true,
+		},
+		{
+			name:           "plain body passes through",
+			decryptor:      reverse,
+			body:           "payload",
+			expectBody:     "payload",
+			expectStatus:   http.StatusOK,
+			expectNextCall: true,
+		},
+		{
+			name:           "plain body without decryptor passes through",
+			body:           "payload",
+			expectBody:     "payload",
+			expectStatus:   http.StatusOK,
+			expectNextCall: true,
+		},
+		{
+			name:         "unsupported scheme",
+			scheme:       "rot13",
+			decryptor:    reverse,
+			body:         "cnlybnq",
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "encrypted body without decryptor",
+			scheme:       encrypt.Scheme,
+			body:         "daolyap",
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "decryption error",
+			scheme:       encrypt.Scheme,
+			decryptor:    reverse,
+			body:         "broken",
+			expectStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nextCalled := false
+			var receivedBody []byte
+			var receivedScheme string
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+				receivedBody, _ = io.ReadAll(r.Body)
+				receivedScheme = r.Header.Get(EncryptionHeader)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			mw := Decrypt(tt.decryptor)(next)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			if tt.scheme != "" {
+				req.Header.Set(EncryptionHeader, tt.scheme)
+			}
+
+			rr := httptest.NewRecorder()
+			mw.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectStatus, rr.Code)
+			assert.Equal(t, tt.expectNextCall, nextCalled)
+
+			if tt.expectNextCall {
+				assert.Equal(t, tt.expectBody, string(receivedBody))
+				assert.Empty(t, receivedScheme)
+			}
+		})
+	}
+}
@@ -0,0 +1,390 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+)
+
+const (
+	// ddSketchMagic marks serialized DDSketch payloads.
+	ddSketchMagic byte = 'D'
+
+	// ddSketchVersion is the current serialization format version.
+	ddSketchVersion byte = 1
+
+	// maxBins bounds the number of bins per sign.
+	// When exceeded, the lowest-magnitude bins are collapsed,
+	// trading accuracy of the smallest values for bounded memory.
+	maxBins = 2048
+
+	// minIndexableValue is the smallest magnitude tracked in bins,
+	// smaller values are counted as zeros.
+	minIndexableValue = 1e-9
+)
+
+var (
+	// ErrEmptySketch is returned when querying a sketch without values.
+	ErrEmptySketch = errors.New("sketch is empty")
+
+	// ErrAccuracyMismatch is returned when merging sketches with different accuracy.
+	ErrAccuracyMismatch = errors.New("sketch relative accuracy mismatch")
+)
+
+// DDSketch is a quantile sketch with relative-error guarantees.
+//
+// Values are counted in logarithmically sized bins, so any quantile estimate
+// is within relativeAccuracy of the true value (for values not affected by
+// bin collapsing). Sketches with the same accuracy can be merged exactly.
+//
+// DDSketch is not safe for concurrent use.
+type DDSketch struct {
+	relativeAccuracy float64
+	gamma            float64
+	logGamma         float64
+
+	positive  map[int32]uint64
+	negative  map[int32]uint64
+	zeroCount uint64
+
+	count uint64
+	sum   float64
+	min   float64
+	max   float64
+}
+
+// NewDDSketch creates an empty sketch.
+//
+// relativeAccuracy: Maximum relative error of quantile estimates, in (0, 1).
+// For example 0.01 keeps estimates within 1% of the true value.
+//
+// Returns:
+//   - *DDSketch: Empty sketch
+//   - error: If relativeAccuracy is out of range
+func NewDDSketch(relativeAccuracy float64) (*DDSketch, error) {
+	if !(relativeAccuracy > 0 && relativeAccuracy < 1) {
+		return nil, fmt.Errorf("relative accuracy must be in (0, 1), got %v", relativeAccuracy)
+	}
+
+	gamma := (1 + relativeAccuracy) / (1 - relativeAccuracy)
+
+	return &DDSketch{
+		relativeAccuracy: relativeAccuracy,
+		gamma:            gamma,
+		logGamma:         math.Log(gamma),
+		positive:         make(map[int32]uint64),
+		negative:         make(map[int32]uint64),
+		min:              math.Inf(1),
+		max:              math.Inf(-1),
+	}, nil
+}
+
+// RelativeAccuracy returns the accuracy the sketch was created with.
+func (s *DDSketch) RelativeAccuracy() float64 {
+	return s.relativeAccuracy
+}
+
+// Count returns the number of added values.
+func (s *DDSketch) Count() uint64 {
+	return s.count
+}
+
+// Sum returns the exact sum of added values.
+func (s *DDSketch) Sum() float64 {
+	return s.sum
+}
+
+// Add records a single value. NaN and infinite values are ignored.
+func (s *DDSketch) Add(value float64) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return
+	}
+
+	switch {
+	case value >= minIndexableValue:
+		s.positive[s.index(value)]++
+		collapse(s.positive)
+	case value <= -minIndexableValue:
+		s.negative[s.index(-value)]++
+		collapse(s.negative)
+	default:
+		s.zeroCount++
+	}
+
+	s.count++
+	s.sum += value
+	s.min = math.Min(s.min, value)
+	s.max = math.Max(s.max, value)
+}
+
+// Reset removes all values, keeping the relative accuracy.
+func (s *DDSketch) Reset() {
+	clear(s.positive)
+	clear(s.negative)
+	s.zeroCount = 0
+	s.count = 0
+	s.sum = 0
+	s.min = math.Inf(1)
+	s.max = math.Inf(-1)
+}
+
+// Merge adds all values of other into the sketch.
+// Both sketches must have the same relative accuracy.
+func (s *DDSketch) Merge(other *DDSketch) error {
+	if s.relativeAccuracy != other.relativeAccuracy {
+		return ErrAccuracyMismatch
+	}
+
+	for index, count := range other.positive {
+		s.positive[index] += count
+	}
+	for index, count := range other.negative {
+		s.negative[index] += count
+	}
+	collapse(s.positive)
+	collapse(s.negative)
+
+	s.zeroCount += other.zeroCount
+	s.count += other.count
+	s.sum += other.sum
+	s.min = math.Min(s.min, other.min)
+	s.max = math.Max(s.max, other.max)
+
+	return nil
+}
+
+// Quantile returns the estimated value at quantile q in [0, 1].
+//
+// Returns:
+//   - float64: Estimated value, clamped to the observed min and max
+//   - error: ErrEmptySketch for an empty sketch, or if q is out of range
+func (s *DDSketch) Quantile(q float64) (float64, error) {
+	if !(q >= 0 && q <= 1) {
+		return 0, fmt.Errorf("quantile must be in [0, 1], got %v", q)
+	}
+
+	if s.count == 0 {
+		return 0, ErrEmptySketch
+	}
+
+	rank := uint64(q * float64(s.count-1))
+	var seen uint64
+
+	// Negative values in ascending order: largest magnitude first.
+	negativeIndexes := slices.Sorted(maps.Keys(s.negative))
+	for i := len(negativeIndexes) - 1; i >= 0; i-- {
+		seen += s.negative[negativeIndexes[i]]
+		if seen > rank {
+			return s.clamp(-s.value(negativeIndexes[i])), nil
+		}
+	}
+
+	seen += s.zeroCount
+	if seen > rank {
+		return s.clamp(0), nil
+	}
+
+	for _, index := range slices.Sorted(maps.Keys(s.positive)) {
+		seen += s.positive[index]
+		if seen > rank {
+			return s.clamp(s.value(index)), nil
+		}
+	}
+
+	return s.max, nil
+}
+
+// MarshalBinary serializes the sketch into a compact binary form.
+func (s *DDSketch) MarshalBinary() ([]byte, error) {
+	data := []byte{ddSketchMagic, ddSketchVersion}
+	data = binary.BigEndian.AppendUint64(data, math.Float64bits(s.relativeAccuracy))
+	data = binary.AppendUvarint(data, s.count)
+	data = binary.AppendUvarint(data, s.zeroCount)
+	data = binary.BigEndian.AppendUint64(data, math.Float64bits(s.sum))
+	data = binary.BigEndian.AppendUint64(data, math.Float64bits(s.min))
+	data = binary.BigEndian.AppendUint64(data, math.Float64bits(s.max))
+	data = appendBins(data, s.positive)
+	data = appendBins(data, s.negative)
+
+	return data, nil
+}
+
+// UnmarshalBinary restores a sketch serialized with MarshalBinary.
+// Returns an error for malformed or inconsistent payloads.
+func (s *DDSketch) UnmarshalBinary(data []byte) error {
+	reader := &binaryReader{data: data}
+
+	if reader.byte() != ddSketchMagic {
+		return errors.New("invalid sketch payload")
+	}
+	if version := reader.byte(); version != ddSketchVersion {
+		return fmt.Errorf("unsupported sketch version %d", version)
+	}
+
+	decoded, err := NewDDSketch(reader.float64())
+	if err != nil {
+		return err
+	}
+
+	decoded.count = reader.uvarint()
+	decoded.zeroCount = reader.uvarint()
+	decoded.sum = reader.float64()
+	decoded.min = reader.float64()
+	decoded.max = reader.float64()
+	decoded.positive = reader.bins()
+	decoded.negative = reader.bins()
+
+	if reader.err != nil {
+		return fmt.Errorf("invalid sketch payload: %w", reader.err)
+	}
+	if len(reader.data) != 0 {
+		return errors.New("invalid sketch payload: trailing data")
+	}
+
+	total := decoded.zeroCount
+	for _, count := range decoded.positive {
+		total += count
+	}
+	for _, count := range decoded.negative {
+		total += count
+	}
+	if total != decoded.count {
+		return errors.New("invalid sketch payload: bin counts don't match total count")
+	}
+
+	*s = *decoded
+	return nil
+}
+
+// index returns the bin index of a positive value.
+func (s *DDSketch) index(value float64) int32 {
+	return int32(math.Ceil(math.Log(value) / s.logGamma))
+}
+
+// value returns the representative value of a bin,
+// equidistant in relative terms from the bin bounds.
+func (s *DDSketch) value(index int32) float64 {
+	return 2 * math.Pow(s.gamma, float64(index)) / (s.gamma + 1)
+}
+
+// clamp limits an estimate to the observed value range.
+func (s *DDSketch) clamp(value float64) float64 {
+	return math.Max(s.min, math.Min(s.max, value))
+}
+
+// collapse folds the lowest bins together until at most maxBins remain.
+func collapse(bins map[int32]uint64) {
+	if len(bins) <= maxBins {
+		return
+	}
+
+	// A single extra bin, the common case when adding values,
+	// is folded without sorting all indexes.
+	if len(bins) == maxBins+1 {
+		lowest, next := int32(math.MaxInt32), int32(math.MaxInt32)
+		for index := range bins {
+			switch {
+			case index < lowest:
+				lowest, next = index, lowest
+			case index < next:
+				next = index
+			}
+		}
+		bins[next] += bins[lowest]
+		delete(bins, lowest)
+		return
+	}
+
+	indexes := slices.Sorted(maps.Keys(bins))
+	target := indexes[len(indexes)-maxBins]
+	for _, index := range indexes[:len(indexes)-maxBins] {
+		bins[target] += bins[index]
+		delete(bins, index)
+	}
+}
+
+// appendBins encodes bins in ascending index order.
+func appendBins(data []byte, bins map[int32]uint64) []byte {
+	data = binary.AppendUvarint(data, uint64(len(bins)))
+	for _, index := range slices.Sorted(maps.Keys(bins)) {
+		data = binary.AppendVarint(data, int64(index))
+		data = binary.AppendUvarint(data, bins[index])
+	}
+	return data
+}
+
+// binaryReader decodes sequential fields, remembering the first error.
+type binaryReader struct {
+	data []byte
+	err  error
+}
+
+func (r *binaryReader) fail() {
+	if r.err == nil {
+		r.err = errors.New("unexpected end of data")
+	}
+	r.data = nil
+}
+
+func (r *binaryReader) byte() byte {
+	if len(r.data) < 1 {
+		r.fail()
+		return 0
+	}
+	value := r.data[0]
+	r.data = r.data[1:]
+	return value
+}
+
+func (r *binaryReader) float64() float64 {
+	if len(r.data) < 8 {
+		r.fail()
+		return 0
+	}
+	value := math.Float64frombits(binary.BigEndian.Uint64(r.data))
+	r.data = r.data[8:]
+	return value
+}
+
+func (r *binaryReader) uvarint() uint64 {
+	value, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.fail()
+		return 0
+	}
+	r.data = r.data[n:]
+	return value
+}
+
+func (r *binaryReader) varint() int64 {
+	value, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.fail()
+		return 0
+	}
+	r.data = r.data[n:]
+	return value
+}
+
+func (r *binaryReader) bins() map[int32]uint64 {
+	size := r.uvarint()
+	// Every bin takes at least two bytes, larger sizes are malformed.
+	if size > uint64(len(r.data)/2) {
+		r.fail()
+		return make(map[int32]uint64)
+	}
+
+	bins := make(map[int32]uint64, size)
+	for range size {
+		index := r.varint()
+		if index < math.MinInt32 || index > math.MaxInt32 {
+			r.fail()
+			break
+		}
+		bins[int32(index)] = r.uvarint()
+	}
+	return bins
+}
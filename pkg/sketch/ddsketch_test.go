@@ -0,0 +1,241 @@
+package sketch
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDDSketch(t *testing.T) {
+	tests := []struct {
+		name     string
+		accuracy float64
+		wantErr  bool
+	}{
+		{name: "valid accuracy", accuracy: 0.01},
+		{name: "zero accuracy", accuracy: 0, wantErr: true},
+		{name: "negative accuracy", accuracy: -0.1, wantErr: true},
+		{name: "accuracy of one", accuracy: 1, wantErr: true},
+		{name: "NaN accuracy", accuracy: math.NaN(), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sketch, err := NewDDSketch(tt.accuracy)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, sketch)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.accuracy, sketch.RelativeAccuracy())
+			assert.Zero(t, sketch.Count())
+		})
+	}
+}
+
+func TestDDSketch_Quantile(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []float64
+		quantile float64
+		expected float64
+	}{
+		{name: "median of sequence", values: sequence(1, 1000), quantile: 0.5, expected: 500},
+		{name: "p99 of sequence", values: sequence(1, 1000), quantile: 0.99, expected: 990},
+		{name: "min", values: sequence(1, 1000), quantile: 0, expected: 1},
+		{name: "max", values: sequence(1, 1000), quantile: 1, expected: 1000},
+		{name: "single value", values: []float64{42}, quantile: 0.5, expected: 42},
+		{name: "negative values", values: sequence(-100, -1), quantile: 0.5, expected: -50},
+		{name: "zeros", values: []float64{0, 0, 0}, quantile: 0.9, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sketch, err := NewDDSketch(0.01)
+			require.NoError(t, err)
+
+			for _, value := range tt.values {
+				sketch.Add(value)
+			}
+
+			result, err := sketch.Quantile(tt.quantile)
+
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expected, result, math.Abs(tt.expected)*0.02)
+		})
+	}
+}
+
+func TestDDSketch_Quantile_Errors(t *testing.T) {
+	sketch, err := NewDDSketch(0.01)
+	require.NoError(t, err)
+
+	_, err = sketch.Quantile(0.5)
+	assert.ErrorIs(t, err, ErrEmptySketch)
+
+	sketch.Add(1)
+	_, err = sketch.Quantile(1.5)
+	assert.Error(t, err)
+}
+
+func TestDDSketch_Add_IgnoresNonFinite(t *testing.T) {
+	sketch, err := NewDDSketch(0.01)
+	require.NoError(t, err)
+
+	sketch.Add(math.NaN())
+	sketch.Add(math.Inf(1))
+	sketch.Add(2)
+
+	assert.Equal(t, uint64(1), sketch.Count())
+	assert.Equal(t, 2.0, sketch.Sum())
+}
+
+func TestDDSketch_Reset(t *testing.T) {
+	sketch, err := NewDDSketch(0.01)
+	require.NoError(t, err)
+
+	for _, value := range []float64{-3, 0, 2, 5} {
+		sketch.Add(value)
+	}
+	sketch.Reset()
+
+	assert.Equal(t, uint64(0), sketch.Count())
+	assert.Equal(t, 0.0, sketch.Sum())
+	_, err = sketch.Quantile(0.5)
+	assert.ErrorIs(t, err, ErrEmptySketch)
+
+	sketch.Add(4)
+	median, err := sketch.Quantile(0.5)
+	require.NoError(t, err)
+	assert.InEpsilon(t, 4, median, 0.01)
+	assert.Equal(t, 0.01, sketch.RelativeAccuracy())
+}
+
+func TestDDSketch_Merge(t *testing.T) {
+	tests := []struct {
+		name          string
+		otherAccuracy float64
+		wantErr       error
+	}{
+		{name: "same accuracy", otherAccuracy: 0.01},
+		{name: "different accuracy", otherAccuracy: 0.05, wantErr: ErrAccuracyMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, err := NewDDSketch(0.01)
+			require.NoError(t, err)
+			second, err := NewDDSketch(tt.otherAccuracy)
+			require.NoError(t, err)
+
+			for _, value := range sequence(1, 500) {
+				first.Add(value)
+			}
+			for _, value := range sequence(501, 1000) {
+				second.Add(value)
+			}
+
+			err = first.Merge(second)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, uint64(500), first.Count())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, uint64(1000), first.Count())
+			assert.Equal(t, 500500.0, first.Sum())
+
+			median, err := first.Quantile(0.5)
+			require.NoError(t, err)
+			assert.InEpsilon(t, 500, median, 0.02)
+		})
+	}
+}
+
+func TestDDSketch_Collapse(t *testing.T) {
+	sketch, err := NewDDSketch(0.001)
+	require.NoError(t, err)
+
+	for i := range 10000 {
+		sketch.Add(math.Pow(1.01, float64(i)))
+	}
+
+	assert.LessOrEqual(t, len(sketch.positive), maxBins)
+	assert.Equal(t, uint64(10000), sketch.Count())
+
+	p99, err := sketch.Quantile(0.99)
+	require.NoError(t, err)
+	assert.InEpsilon(t, math.Pow(1.01, 9899), p99, 0.01)
+}
+
+func TestDDSketch_MarshalBinary(t *testing.T) {
+	sketch, err := NewDDSketch(0.02)
+	require.NoError(t, err)
+	for _, value := range []float64{-3, 0, 1.5, 1.5, 100} {
+		sketch.Add(value)
+	}
+
+	data, err := sketch.MarshalBinary()
+	require.NoError(t, err)
+
+	var decoded DDSketch
+	require.NoError(t, decoded.UnmarshalBinary(data))
+
+	assert.Equal(t, sketch.RelativeAccuracy(), decoded.RelativeAccuracy())
+	assert.Equal(t, sketch.Count(), decoded.Count())
+	assert.Equal(t, sketch.Sum(), decoded.Sum())
+	for _, q := range []float64{0, 0.25, 0.5, 0.75, 1} {
+		expected, err := sketch.Quantile(q)
+		require.NoError(t, err)
+		actual, err := decoded.Quantile(q)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestDDSketch_UnmarshalBinary_Invalid(t *testing.T) {
+	sketch, err := NewDDSketch(0.01)
+	require.NoError(t, err)
+	sketch.Add(1)
+	valid, err := sketch.MarshalBinary()
+	require.NoError(t, err)
+
+	inconsistent, err := sketch.MarshalBinary()
+	require.NoError(t, err)
+	// Count is the first varint after magic, version and accuracy.
+	inconsistent[10] = 5
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty payload", data: nil},
+		{name: "wrong magic", data: append([]byte{'X'}, valid[1:]...)},
+		{name: "unsupported version", data: append([]byte{ddSketchMagic, 9}, valid[2:]...)},
+		{name: "truncated payload", data: valid[:len(valid)-1]},
+		{name: "trailing data", data: append(append([]byte{}, valid...), 0)},
+		{name: "inconsistent count", data: inconsistent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decoded DDSketch
+			assert.Error(t, decoded.UnmarshalBinary(tt.data))
+		})
+	}
+}
+
+func sequence(from, to int) []float64 {
+	values := make([]float64, 0, to-from+1)
+	for i := from; i <= to; i++ {
+		values = append(values, float64(i))
+	}
+	return values
+}
@@ -0,0 +1,10 @@
+// Package sketch provides mergeable streaming estimators for metric values.
+//
+// The package implements:
+//   - DDSketch: quantile sketch with relative-error guarantees
+//   - HyperLogLog: distinct count estimator with fixed memory
+//
+// Sketches are compact, can be merged without losing accuracy and are
+// serialized with MarshalBinary for transmission between agent and server
+// and for storage.
+package sketch
@@ -0,0 +1,152 @@
+package sketch
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHyperLogLog(t *testing.T) {
+	tests := []struct {
+		name      string
+		precision uint8
+		wantErr   bool
+	}{
+		{name: "min precision", precision: MinPrecision},
+		{name: "default precision", precision: 12},
+		{name: "max precision", precision: MaxPrecision},
+		{name: "too small precision", precision: MinPrecision - 1, wantErr: true},
+		{name: "too large precision", precision: MaxPrecision + 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hll, err := NewHyperLogLog(tt.precision)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, hll)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.precision, hll.Precision())
+			assert.Zero(t, hll.Estimate())
+		})
+	}
+}
+
+func TestHyperLogLog_Estimate(t *testing.T) {
+	tests := []struct {
+		name     string
+		members  int
+		repeats  int
+		expected float64
+	}{
+		{name: "single member", members: 1, repeats: 1, expected: 1},
+		{name: "duplicates are counted once", members: 100, repeats: 5, expected: 100},
+		{name: "small cardinality", members: 1000, repeats: 1, expected: 1000},
+		{name: "large cardinality", members: 100000, repeats: 1, expected: 100000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hll, err := NewHyperLogLog(12)
+			require.NoError(t, err)
+
+			for range tt.repeats {
+				for i := range tt.members {
+					hll.Add(fmt.Sprintf("user-%d", i))
+				}
+			}
+
+			// Five standard errors of precision 12.
+			assert.InEpsilon(t, tt.expected, float64(hll.Estimate()), 0.08)
+		})
+	}
+}
+
+func TestHyperLogLog_Merge(t *testing.T) {
+	tests := []struct {
+		name           string
+		otherPrecision uint8
+		wantErr        error
+	}{
+		{name: "same precision", otherPrecision: 12},
+		{name: "different precision", otherPrecision: 10, wantErr: ErrPrecisionMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, err := NewHyperLogLog(12)
+			require.NoError(t, err)
+			second, err := NewHyperLogLog(tt.otherPrecision)
+			require.NoError(t, err)
+
+			for i := range 3000 {
+				first.Add(fmt.Sprintf("ip-%d", i))
+			}
+			for i := 2000; i < 5000; i++ {
+				second.Add(fmt.Sprintf("ip-%d", i))
+			}
+
+			err = first.Merge(second)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.InEpsilon(t, 5000, float64(first.Estimate()), 0.08)
+		})
+	}
+}
+
+func TestHyperLogLog_MarshalBinary(t *testing.T) {
+	hll, err := NewHyperLogLog(8)
+	require.NoError(t, err)
+	for i := range 500 {
+		hll.Add(fmt.Sprintf("member-%d", i))
+	}
+
+	data, err := hll.MarshalBinary()
+	require.NoError(t, err)
+
+	var decoded HyperLogLog
+	require.NoError(t, decoded.UnmarshalBinary(data))
+
+	assert.Equal(t, hll.Precision(), decoded.Precision())
+	assert.Equal(t, hll.Estimate(), decoded.Estimate())
+}
+
+func TestHyperLogLog_UnmarshalBinary_Invalid(t *testing.T) {
+	hll, err := NewHyperLogLog(4)
+	require.NoError(t, err)
+	valid, err := hll.MarshalBinary()
+	require.NoError(t, err)
+
+	outOfRange := append([]byte{}, valid...)
+	outOfRange[3] = 200
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty payload", data: nil},
+		{name: "wrong magic", data: append([]byte{'X'}, valid[1:]...)},
+		{name: "unsupported version", data: append([]byte{hllMagic, 9}, valid[2:]...)},
+		{name: "invalid precision", data: append([]byte{hllMagic, hllVersion, 30}, valid[3:]...)},
+		{name: "truncated registers", data: valid[:len(valid)-1]},
+		{name: "register out of range", data: outOfRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decoded HyperLogLog
+			assert.Error(t, decoded.UnmarshalBinary(tt.data))
+		})
+	}
+}